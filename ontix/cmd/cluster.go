@@ -10,6 +10,7 @@ import (
 	"github.com/ikala/ontix/config"
 	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
 	"github.com/ikala/ontix/internal/infra/mlservice"
 	"github.com/ikala/ontix/internal/infra/postgres"
 	"github.com/ikala/ontix/internal/infra/redis"
@@ -66,7 +67,7 @@ func clusterFx(minClusterSize int, force bool) {
 				db.Pool.Exec(ctx, "TRUNCATE post_clusters RESTART IDENTITY CASCADE")
 				db.Pool.Exec(ctx, "TRUNCATE clusters RESTART IDENTITY CASCADE")
 				centroidRepo.Clear(ctx)
-				fmt.Println("Cleared.\n")
+				fmt.Print("Cleared.\n\n")
 			}
 
 			// 1. 取得所有有 embedding 的貼文 (新結構：posts + post_embeddings)
@@ -309,9 +310,10 @@ func validateTagsWithClusterName(tags []*entity.PostTag, clusterName string) boo
 	// 建立 cluster 關鍵詞集合
 	clusterTerms := make(map[string]bool)
 
-	// 加入 cluster 名稱的每個字詞
-	for _, word := range splitChineseWordsCluster(clusterName) {
-		clusterTerms[strings.ToLower(word)] = true
+	// 加入 cluster 名稱與其中的字詞
+	clusterTerms[strings.ToLower(clusterName)] = true
+	for _, word := range service.Tokenize(clusterName, service.DetectLanguage(clusterName)) {
+		clusterTerms[word] = true
 	}
 
 	// 檢查 tags 是否有任何匹配
@@ -340,20 +342,3 @@ func validateTagsWithClusterName(tags []*entity.PostTag, clusterName string) boo
 
 	return false
 }
-
-// splitChineseWordsCluster 簡單的中文分詞
-func splitChineseWordsCluster(s string) []string {
-	words := []string{s}
-
-	runes := []rune(s)
-	if len(runes) <= 2 {
-		return words
-	}
-
-	// 2-gram
-	for i := 0; i < len(runes)-1; i++ {
-		words = append(words, string(runes[i:i+2]))
-	}
-
-	return words
-}
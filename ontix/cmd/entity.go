@@ -19,6 +19,7 @@ var entityCmd = func() *cobra.Command {
 	var content string
 	var batch bool
 	var limit int
+	var rulesOnly bool
 
 	cmd := &cobra.Command{
 		Use:   "entity",
@@ -35,31 +36,40 @@ var entityCmd = func() *cobra.Command {
 			if content == "" {
 				log.Fatal("請提供貼文內容: ontix entity -c \"內容\" 或 ontix entity --batch")
 			}
-			entitySingleFx(content)
+			entitySingleFx(content, rulesOnly)
 		},
 	}
 
 	cmd.Flags().StringVarP(&content, "content", "c", "", "貼文內容（單筆測試）")
 	cmd.Flags().BoolVar(&batch, "batch", false, "批次處理 DB 中已有的貼文")
 	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "批次處理筆數限制")
+	cmd.Flags().BoolVar(&rulesOnly, "rules-only", false, "只執行別名規則連結（不呼叫 LLM、不寫入 DB）")
+
+	cmd.AddCommand(entityEmbedCmd())
+	cmd.AddCommand(entityAlignCmd())
+	cmd.AddCommand(entityAspectsCmd())
 	return cmd
 }
 
 // === 單筆模式：測試 LLM 抽取效果 ===
 
-func entitySingleFx(content string) {
+func entitySingleFx(content string, rulesOnly bool) {
 	app := fx.New(
 		fx.NopLogger,
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EntityExtractionService { return c },
 			func(c *openai.Client) service.EmbeddingService { return c },
 			postgres.New,
 			postgres.NewObjectRepo,
 			postgres.NewOntologySchemaRepo,
 			postgres.NewObjectRelationRepo,
+			postgres.NewEntityEmbeddingRepo,
 		),
 		fx.Invoke(func(
 			extractionSvc service.EntityExtractionService,
@@ -67,6 +77,8 @@ func entitySingleFx(content string) {
 			objectRepo repository.ObjectRepository,
 			schemaRepo repository.OntologySchemaRepository,
 			relRepo repository.ObjectRelationRepository,
+			embedRepo repository.EntityEmbeddingRepository,
+			cfg *config.Config,
 		) {
 			ctx := context.Background()
 
@@ -75,6 +87,18 @@ func entitySingleFx(content string) {
 
 			// Step 1: 載入已知 Entity（透過 extractor 以取得 class 資訊）
 			extractor := service.NewEntityExtractor(extractionSvc, embedSvc, objectRepo, schemaRepo, relRepo)
+			candidateSel := newCandidateSelector(cfg, objectRepo, embedRepo)
+			if candidateSel != nil {
+				extractor.SetCandidateSelector(candidateSel)
+			}
+			extractor.SetChunker(newChunker(cfg))
+			linker := newDictionaryLinker(cfg, objectRepo)
+			if linker == nil && rulesOnly {
+				linker = service.NewDictionaryLinker(objectRepo, cfg.EntityLinker.MinAliasRunes)
+			}
+			if linker != nil {
+				extractor.SetDictionaryLinker(linker)
+			}
 			known, _ := extractor.BuildKnownEntities(ctx)
 			if len(known) > 0 {
 				fmt.Printf("已知 Entity: %d 個\n", len(known))
@@ -82,16 +106,50 @@ func entitySingleFx(content string) {
 				fmt.Println("已知 Entity: 0 個（首次運行）")
 			}
 
-			// Step 2: LLM 抽取 + 消歧
-			fmt.Println("\n--- LLM 抽取結果 ---")
-			result, err := extractionSvc.ExtractEntities(ctx, content, known)
-			if err != nil {
-				log.Fatalf("Entity extraction error: %v", err)
+			// Step 1.5: 別名規則連結（命中的片段不送 LLM）
+			llmContent := content
+			if linker != nil {
+				names := make(map[string]string, len(known))
+				for _, k := range known {
+					names[k.ObjectID] = k.CanonicalName
+				}
+				linked := linker.Link(content)
+				fmt.Printf("\n--- 規則連結（字典 %d 個別名）---\n", linker.Size())
+				if len(linked.Links) == 0 {
+					fmt.Println("無命中")
+				}
+				for _, l := range linked.Links {
+					fmt.Printf("  %q [%d:%d] → %s (別名 %q)\n", l.MentionText, l.Start, l.End, names[l.ObjectID], l.Alias)
+				}
+				fmt.Printf("剩餘文字: %s\n", linked.Residual)
+				if rulesOnly {
+					return
+				}
+				if !linked.HasResidual() {
+					fmt.Println("全部由規則連結，不呼叫 LLM")
+					llmContent = ""
+				} else {
+					llmContent = linked.Residual
+				}
 			}
 
-			if len(result.Entities) == 0 {
-				fmt.Println("未識別到任何實體")
-				return
+			if candidateSel != nil && len(known) > 0 && llmContent != "" {
+				known = candidateSel.Select(ctx, llmContent, nil, known).Entities
+				fmt.Printf("候選 Entity: %d 個（top-%d）\n", len(known), candidateSel.TopK())
+			}
+
+			// Step 2: LLM 抽取 + 消歧
+			result := &service.EntityExtractionResult{}
+			if llmContent != "" {
+				fmt.Println("\n--- LLM 抽取結果 ---")
+				var err error
+				result, err = extractionSvc.ExtractEntities(ctx, llmContent, known)
+				if err != nil {
+					log.Fatalf("Entity extraction error: %v", err)
+				}
+				if len(result.Entities) == 0 {
+					fmt.Println("未識別到任何實體")
+				}
 			}
 
 			for i, e := range result.Entities {
@@ -131,7 +189,7 @@ func entitySingleFx(content string) {
 			}
 
 			fmt.Printf("\n=== 結果 ===\n")
-			fmt.Printf("識別 Entity: %d\n", summary.EntitiesFound)
+			fmt.Printf("識別 Entity: %d（規則連結 %d）\n", summary.EntitiesFound, summary.RuleLinked)
 			fmt.Printf("新建 Entity: %d\n", summary.EntitiesCreated)
 			fmt.Printf("Aspect 數量: %d\n", summary.AspectsFound)
 			fmt.Printf("Link 數量: %d\n", summary.LinksFound)
@@ -151,13 +209,20 @@ func entityBatchFx(limit int) {
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EntityExtractionService { return c },
 			func(c *openai.Client) service.EmbeddingService { return c },
 			postgres.New,
 			postgres.NewObjectRepo,
 			postgres.NewOntologySchemaRepo,
 			postgres.NewObjectRelationRepo,
+			postgres.NewEntityEmbeddingRepo,
+			postgres.NewAspectTaxonomyRepo,
+			postgres.NewSentimentCalibrationRepo,
+			service.NewSentimentCalibrator,
 		),
 		fx.Invoke(func(
 			extractionSvc service.EntityExtractionService,
@@ -166,6 +231,11 @@ func entityBatchFx(limit int) {
 			objectRepo repository.ObjectRepository,
 			schemaRepo repository.OntologySchemaRepository,
 			relRepo repository.ObjectRelationRepository,
+			embedRepo repository.EntityEmbeddingRepository,
+			aspectRepo repository.AspectTaxonomyRepository,
+			calibrator *service.SentimentCalibrator,
+			llmCache *service.LLMCache,
+			cfg *config.Config,
 		) {
 			ctx := context.Background()
 
@@ -185,8 +255,24 @@ func entityBatchFx(limit int) {
 			fmt.Printf("待處理貼文: %d\n\n", len(posts))
 
 			extractor := service.NewEntityExtractor(extractionSvc, embedSvc, objectRepo, schemaRepo, relRepo)
+			candidateSel := newCandidateSelector(cfg, objectRepo, embedRepo)
+			if candidateSel != nil {
+				extractor.SetCandidateSelector(candidateSel)
+			}
+			if linker := newDictionaryLinker(cfg, objectRepo); linker != nil {
+				extractor.SetDictionaryLinker(linker)
+			}
+			extractor.SetChunker(newChunker(cfg))
+			if aspects := newAspectTaxonomy(cfg, aspectRepo, embedSvc); aspects != nil {
+				extractor.SetAspectTaxonomy(aspects)
+			}
+			if _, err := calibrator.Load(ctx); err != nil {
+				log.Printf("load sentiment calibrations error (using raw scores): %v", err)
+			}
+			extractor.SetSentimentCalibrator(calibrator)
 
 			totalEntities := 0
+			totalRuleLinked := 0
 			totalCreated := 0
 			totalAspects := 0
 			totalLinks := 0
@@ -196,23 +282,19 @@ func entityBatchFx(limit int) {
 			for i, post := range posts {
 				fmt.Printf("[%d/%d] %s ", i+1, len(posts), post.PostID)
 
-				content := post.Content
-				if len(content) > 500 {
-					content = content[:500]
-				}
-
-				summary, err := extractor.ProcessPost(ctx, post.PostID, content)
+				summary, err := extractor.ProcessPost(ctx, post.PostID, post.Content)
 				if err != nil {
 					fmt.Printf("error: %v\n", err)
 					failCount++
 					continue
 				}
 
-				fmt.Printf("entities=%d (new=%d) aspects=%d links=%d\n",
-					summary.EntitiesFound, summary.EntitiesCreated, summary.AspectsFound, summary.LinksFound)
+				fmt.Printf("entities=%d (new=%d, rule=%d) aspects=%d links=%d chunks=%d\n",
+					summary.EntitiesFound, summary.EntitiesCreated, summary.RuleLinked, summary.AspectsFound, summary.LinksFound, summary.Chunks)
 
 				totalEntities += summary.EntitiesFound
 				totalCreated += summary.EntitiesCreated
+				totalRuleLinked += summary.RuleLinked
 				totalAspects += summary.AspectsFound
 				totalLinks += summary.LinksFound
 				successCount++
@@ -223,9 +305,13 @@ func entityBatchFx(limit int) {
 
 			fmt.Printf("\n=== 完成 ===\n")
 			fmt.Printf("成功: %d / 失敗: %d\n", successCount, failCount)
-			fmt.Printf("識別 Entity: %d (新建: %d)\n", totalEntities, totalCreated)
+			fmt.Printf("識別 Entity: %d (新建: %d, 規則連結: %d)\n", totalEntities, totalCreated, totalRuleLinked)
 			fmt.Printf("Aspect 數量: %d\n", totalAspects)
 			fmt.Printf("Link 數量: %d\n", totalLinks)
+			fmt.Printf("LLM 快取: %s\n", llmCache.Summary())
+			if candidateSel != nil {
+				fmt.Printf("候選 Entity: %s\n", candidateSel.Summary())
+			}
 		}),
 	)
 
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// alignBatchSize 回填時每批讀取的筆數
+const alignBatchSize = 200
+
+func entityAlignCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "align",
+		Short: "回填提及與面向片段在原文中的位置（既有資料沒有 mention_start / mention_end）",
+		Run: func(cmd *cobra.Command, args []string) {
+			entityAlignFx(limit)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 10000, "提及與面向各最多處理幾筆（0 = 不限）")
+	return cmd
+}
+
+func entityAlignFx(limit int) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewObjectRepo,
+		),
+		fx.Invoke(func(objectRepo repository.ObjectRepository) {
+			ctx := context.Background()
+
+			fmt.Println("=== Ontix Mention Span Alignment ===")
+			for _, kind := range []string{"mention", "aspect"} {
+				aligned, missed, err := alignSpans(ctx, objectRepo, kind, limit)
+				if err != nil {
+					log.Fatalf("Span alignment (%s) failed after %d rows: %v", kind, aligned+missed, err)
+				}
+				fmt.Printf("%s: 對齊 %d 筆，對不上 %d 筆\n", kind, aligned, missed)
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// alignSpans 以 id 游標逐批對齊；對不上的保持 NULL（下次執行仍會再試）
+func alignSpans(ctx context.Context, objectRepo repository.ObjectRepository, kind string, limit int) (aligned, missed int, err error) {
+	var afterID int64
+	for limit <= 0 || aligned+missed < limit {
+		batch := alignBatchSize
+		if limit > 0 && limit-aligned-missed < batch {
+			batch = limit - aligned - missed
+		}
+		targets, err := objectRepo.ListUnalignedSpans(ctx, kind, afterID, batch)
+		if err != nil {
+			return aligned, missed, err
+		}
+		if len(targets) == 0 {
+			break
+		}
+		for _, t := range targets {
+			afterID = t.ID
+			span, _ := service.AlignSpan(t.Content, t.MentionText, 0, 0)
+			if span == nil {
+				missed++
+				continue
+			}
+			if err := objectRepo.SaveSpan(ctx, kind, t.ID, span); err != nil {
+				return aligned, missed, err
+			}
+			aligned++
+		}
+	}
+	return aligned, missed, nil
+}
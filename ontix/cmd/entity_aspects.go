@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func entityAspectsCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "aspects",
+		Short: "面向正規化回填：計算面向節點向量，將既有 entity_aspects 的原始面向對應到正規面向樹",
+		Run: func(cmd *cobra.Command, args []string) {
+			entityAspectsFx(limit)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 5000, "最多處理幾個原始面向（依出現次數由多到少）")
+	return cmd
+}
+
+func entityAspectsFx(limit int) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
+			func(c *openai.Client) service.EmbeddingService { return c },
+			postgres.New,
+			postgres.NewAspectTaxonomyRepo,
+		),
+		fx.Invoke(func(repo repository.AspectTaxonomyRepository, embedSvc service.EmbeddingService, cfg *config.Config) {
+			ctx := context.Background()
+			taxonomy := service.NewAspectTaxonomy(repo, embedSvc, cfg.AspectTaxonomy.AutoThreshold)
+
+			fmt.Println("=== Ontix Aspect Taxonomy Backfill ===")
+			embedded, err := taxonomy.EmbedNodes(ctx)
+			if err != nil {
+				log.Fatalf("Aspect node embedding failed after %d nodes: %v", embedded, err)
+			}
+			fmt.Printf("面向節點向量: 新增 %d 個\n", embedded)
+
+			res, err := taxonomy.Backfill(ctx, limit)
+			if err != nil {
+				log.Fatalf("Aspect backfill failed after %d aspects: %v", res.Aspects, err)
+			}
+			fmt.Printf("原始面向: 處理 %d 個，對應 %d 個（更新 %d 筆），待審核 %d 個\n",
+				res.Aspects, res.Mapped, res.Rows, res.Pending)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
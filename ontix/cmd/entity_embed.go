@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func entityEmbedCmd() *cobra.Command {
+	var limit int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "embed",
+		Short: "產生 Entity embedding（提及片段 + aspect 評價 + 共同提及），供 Entity 語意搜尋與相似 Entity",
+		Run: func(cmd *cobra.Command, args []string) {
+			entityEmbedFx(limit, force)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 500, "最多處理幾個 Entity")
+	cmd.Flags().BoolVar(&force, "force", false, "全部重新產生（預設只處理尚未產生或提及數成長超過 20% 的 Entity）")
+	return cmd
+}
+
+func entityEmbedFx(limit int, force bool) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
+			func(c *openai.Client) service.EmbeddingService { return c },
+			postgres.New,
+			postgres.NewEntityEmbeddingRepo,
+			service.NewEntityEmbedder,
+		),
+		fx.Invoke(func(embedder *service.EntityEmbedder, llmCache *service.LLMCache) {
+			ctx := context.Background()
+
+			fmt.Println("=== Ontix Entity Embedding ===")
+			updated, err := embedder.RefreshStale(ctx, limit, force)
+			if err != nil {
+				log.Fatalf("Entity embedding failed after %d entities: %v", updated, err)
+			}
+			fmt.Printf("已更新 Entity embedding: %d\n", updated)
+			fmt.Printf("LLM 快取: %s\n", llmCache.Summary())
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
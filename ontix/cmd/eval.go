@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// evalOptions ontix eval 參數
+type evalOptions struct {
+	out        string
+	baseline   string
+	topicMode  string
+	threshold  float64
+	tasks      []string
+	limit      int
+	topN       int
+	noKnown    bool
+	model      string
+	noFewShots bool
+}
+
+var evalCmd = func() *cobra.Command {
+	var opts evalOptions
+
+	cmd := &cobra.Command{
+		Use:   "eval <gold.jsonl>",
+		Short: "以標註黃金集評估主題分類、情感、軟標籤、面向與 Entity 抽取品質",
+		Long: `每行一篇貼文，欄位省略代表不評估該任務：
+  {"id": "p1", "content": "...", "topics": ["food"], "sentiment": "positive",
+   "tags": ["推薦"], "entities": [{"name": "鼎泰豐", "type": "place"}],
+   "aspects": [{"aspect": "服務", "sentiment": "positive"}]}
+
+搭配 --out 存下報告，調整 prompt / threshold 後以 --baseline 比較差異。`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			evalFx(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.out, "out", "o", "", "將報告存成 JSON（可作為下次的 baseline）")
+	cmd.Flags().StringVarP(&opts.baseline, "baseline", "b", "", "與先前存下的報告比較")
+	cmd.Flags().StringVar(&opts.topicMode, "topic-mode", "llm", "主題分類管線：llm（LLMClassifier）或 embedding（TopicAssigner 多標籤）")
+	cmd.Flags().Float64Var(&opts.threshold, "threshold", 0, "embedding 模式的相似度門檻（0 = 使用預設值）")
+	cmd.Flags().StringSliceVar(&opts.tasks, "tasks", nil, "只評估指定任務：topics,sentiment,tags,entities,aspects")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "l", 0, "只評估前 N 筆（0 = 全部）")
+	cmd.Flags().IntVar(&opts.topN, "top", 15, "每個任務顯示的標籤數")
+	cmd.Flags().BoolVar(&opts.noKnown, "no-known-entities", false, "Entity 抽取不注入已知 Entity")
+	cmd.Flags().StringVar(&opts.model, "model", "gpt-4o-mini", "LLMClassifier 使用的模型")
+	cmd.Flags().BoolVar(&opts.noFewShots, "no-few-shots", false, "不注入人工校正範例（預設與 worker 相同會注入）")
+	return cmd
+}
+
+func evalFx(goldPath string, opts evalOptions) {
+	gold, err := loadGoldSet(goldPath, opts.limit)
+	if err != nil {
+		log.Fatalf("Failed to load gold set: %v", err)
+	}
+	if len(gold) == 0 {
+		log.Fatalf("Gold set %s is empty", goldPath)
+	}
+
+	var baseline *service.EvalReport
+	if opts.baseline != "" {
+		baseline, err = loadEvalReport(opts.baseline)
+		if err != nil {
+			log.Fatalf("Failed to load baseline: %v", err)
+		}
+	}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
+			func(c *openai.Client) service.EmbeddingService { return c },
+			func(c *openai.Client) service.TaggingService { return c },
+			func(c *openai.Client) service.EntityExtractionService { return c },
+			postgres.New,
+			postgres.NewTopicRepo,
+			postgres.NewObjectRepo,
+			postgres.NewOntologySchemaRepo,
+			postgres.NewObjectRelationRepo,
+			postgres.NewReviewRepo,
+			service.NewTopicAssigner,
+			service.NewEntityExtractor,
+		),
+		fx.Invoke(func(
+			cfg *config.Config,
+			client *openai.Client,
+			embedSvc service.EmbeddingService,
+			topicRepo repository.TopicRepository,
+			reviewRepo repository.ReviewRepository,
+			topicAssigner *service.TopicAssigner,
+			extractor *service.EntityExtractor,
+			llmCache *service.LLMCache,
+			usageTracker *service.UsageTracker,
+		) {
+			ctx := context.Background()
+
+			topics, err := topicRepo.FindAll(ctx)
+			if err != nil {
+				log.Fatalf("Failed to load topics: %v", err)
+			}
+			classifier := service.NewLLMClassifier(cfg.OpenAIAPIKey, opts.model, topics)
+			classifier.SetCache(llmCache)
+			classifier.SetUsageTracker(usageTracker)
+
+			// 與 worker 相同注入人工校正範例，才能反映線上實際的 prompt
+			if !opts.noFewShots {
+				const perField = 5
+				if examples, err := reviewRepo.ListFewShotExamples(ctx, entity.CorrectionFieldTopic, perField); err == nil {
+					classifier.SetFewShotExamples(examples)
+				}
+				var examples []*entity.FewShotExample
+				for _, field := range []entity.CorrectionField{entity.CorrectionFieldSentiment, entity.CorrectionFieldTags} {
+					if ex, err := reviewRepo.ListFewShotExamples(ctx, field, perField); err == nil {
+						examples = append(examples, ex...)
+					}
+				}
+				client.SetFewShotExamples(examples)
+			}
+
+			evaluator := service.NewEvaluator(client, classifier, client)
+			evaluator.SetTasks(opts.tasks)
+
+			switch opts.topicMode {
+			case "llm":
+			case "embedding":
+				if opts.threshold > 0 {
+					topicAssigner.SetThreshold(opts.threshold)
+				}
+				if err := topicAssigner.LoadCalibrations(ctx); err != nil {
+					log.Printf("load topic calibrations error (using defaults): %v", err)
+				}
+				evaluator.SetTopicAssigner(topicAssigner, embedSvc)
+			default:
+				log.Fatalf("Unknown --topic-mode %q (llm or embedding)", opts.topicMode)
+			}
+
+			if !opts.noKnown {
+				known, err := extractor.BuildKnownEntities(ctx)
+				if err != nil {
+					log.Printf("load known entities error (continuing without): %v", err)
+				}
+				evaluator.SetKnownEntities(known)
+			}
+
+			fmt.Println("=== Ontix Evaluation ===")
+			fmt.Printf("Gold set: %s (%d examples)\n", goldPath, len(gold))
+			fmt.Printf("Topic pipeline: %s\n\n", evaluator.TopicPipeline())
+
+			report, err := evaluator.Run(ctx, gold, func(current, total int) {
+				if current%10 == 0 || current == total {
+					log.Printf("[Eval] %d/%d", current, total)
+				}
+			})
+			if err != nil {
+				log.Fatalf("Evaluation failed: %v", err)
+			}
+			report.GoldPath = goldPath
+			report.Config["model"] = opts.model
+			if opts.topicMode == "embedding" {
+				report.Config["threshold"] = fmt.Sprintf("%g", opts.threshold)
+				report.Config["calibrated_topics"] = fmt.Sprintf("%d", topicAssigner.CalibrationCount())
+			}
+			report.Config["few_shots"] = fmt.Sprintf("%v", !opts.noFewShots)
+			report.Config["llm_cache"] = llmCache.Summary()
+
+			printEvalReport(report, opts.topN)
+
+			if baseline != nil {
+				printEvalDiff(service.DiffReports(baseline, report), opts.topN)
+			}
+
+			if opts.out != "" {
+				if err := saveEvalReport(opts.out, report); err != nil {
+					log.Fatalf("Failed to save report: %v", err)
+				}
+				fmt.Printf("\nReport saved to %s\n", opts.out)
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadGoldSet(path string, limit int) ([]*service.GoldExample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var gold []*service.GoldExample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var ex service.GoldExample
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if ex.Content == "" {
+			log.Printf("line %d: empty content, skipped", lineNo)
+			continue
+		}
+		if ex.ID == "" {
+			ex.ID = fmt.Sprintf("line-%d", lineNo)
+		}
+		gold = append(gold, &ex)
+		if limit > 0 && len(gold) >= limit {
+			break
+		}
+	}
+	return gold, scanner.Err()
+}
+
+func loadEvalReport(path string) (*service.EvalReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report service.EvalReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func saveEvalReport(path string, report *service.EvalReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+var evalTaskOrder = []string{
+	service.EvalTaskTopics,
+	service.EvalTaskSentiment,
+	service.EvalTaskTags,
+	service.EvalTaskAspects,
+	service.EvalTaskEntities,
+}
+
+func printEvalReport(report *service.EvalReport, topN int) {
+	fmt.Printf("%-10s %5s %7s %7s %7s %8s %8s\n", "TASK", "N", "P", "R", "F1", "MACRO-F1", "ACCURACY")
+	for _, name := range evalTaskOrder {
+		t, ok := report.Tasks[name]
+		if !ok {
+			continue
+		}
+		acc := "-"
+		if t.Accuracy != nil {
+			acc = fmt.Sprintf("%.3f", *t.Accuracy)
+		}
+		fmt.Printf("%-10s %5d %7.3f %7.3f %7.3f %8.3f %8s\n",
+			name, t.Examples, t.Micro.Precision, t.Micro.Recall, t.Micro.F1, t.MacroF1, acc)
+	}
+
+	for _, name := range evalTaskOrder {
+		t, ok := report.Tasks[name]
+		if !ok {
+			continue
+		}
+		fmt.Printf("\n--- %s (per label, top %d by support) ---\n", name, topN)
+		fmt.Printf("%-28s %7s %7s %7s %5s %5s %5s\n", "LABEL", "P", "R", "F1", "TP", "FP", "FN")
+		for i, l := range t.PerLabel {
+			if i >= topN {
+				fmt.Printf("... %d more\n", len(t.PerLabel)-topN)
+				break
+			}
+			fmt.Printf("%-28s %7.3f %7.3f %7.3f %5d %5d %5d\n", l.Label, l.Precision, l.Recall, l.F1, l.TP, l.FP, l.FN)
+		}
+
+		if t.Confusion != nil && len(t.Confusion.Labels) <= topN {
+			title := map[string]string{
+				service.EvalTaskTopics:    "primary topic",
+				service.EvalTaskSentiment: "sentiment",
+				service.EvalTaskEntities:  "entity type (matched names)",
+				service.EvalTaskAspects:   "aspect sentiment (matched aspects)",
+			}[name]
+			fmt.Printf("\nConfusion: %s (rows = gold, cols = predicted)\n", title)
+			printConfusion(t.Confusion)
+		}
+	}
+
+	if len(report.Costs) > 0 {
+		fmt.Println("\n--- Cost / Latency ---")
+		fmt.Printf("%-18s %6s %6s %9s %9s %10s %8s %8s\n", "PIPELINE", "CALLS", "ERRORS", "IN_TOK", "OUT_TOK", "COST_USD", "AVG_MS", "P95_MS")
+		for name, c := range report.Costs {
+			fmt.Printf("%-18s %6d %6d %9d %9d %10.4f %8d %8d\n",
+				name, c.Calls, c.Errors, c.InputTokens, c.OutputTokens, c.CostUSD, c.AvgLatencyMS, c.P95LatencyMS)
+		}
+	}
+}
+
+func printConfusion(m *service.ConfusionMatrix) {
+	fmt.Printf("%-16s", "")
+	for _, l := range m.Labels {
+		fmt.Printf(" %10s", truncateLabel(l, 10))
+	}
+	fmt.Println()
+	for i, l := range m.Labels {
+		fmt.Printf("%-16s", truncateLabel(l, 16))
+		for _, n := range m.Matrix[i] {
+			fmt.Printf(" %10d", n)
+		}
+		fmt.Println()
+	}
+}
+
+func printEvalDiff(diff *service.EvalDiff, topN int) {
+	fmt.Println("\n=== Diff vs Baseline ===")
+	fmt.Printf("%-10s %10s %10s %10s\n", "TASK", "ΔMICRO-F1", "ΔMACRO-F1", "ΔACCURACY")
+	for _, t := range diff.Tasks {
+		acc := "-"
+		if t.AccuracyDelta != nil {
+			acc = fmt.Sprintf("%+.3f", *t.AccuracyDelta)
+		}
+		fmt.Printf("%-10s %+10.3f %+10.3f %10s\n", t.Task, t.MicroF1Delta, t.MacroF1Delta, acc)
+	}
+	if len(diff.OnlyInBase) > 0 {
+		fmt.Printf("Not evaluated this run: %s\n", strings.Join(diff.OnlyInBase, ", "))
+	}
+
+	for _, t := range diff.Tasks {
+		var changed []service.LabelDiff
+		for _, l := range t.Labels {
+			if l.DeltaF1 != 0 {
+				changed = append(changed, l)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		fmt.Printf("\n--- %s: largest F1 changes ---\n", t.Task)
+		for i, l := range changed {
+			if i >= topN {
+				break
+			}
+			fmt.Printf("%-28s %.3f → %.3f (%+.3f)\n", l.Label, l.BaselineF1, l.CurrentF1, l.DeltaF1)
+		}
+	}
+
+	if len(diff.CostDelta) > 0 {
+		fmt.Println("\n--- Cost / Latency change ---")
+		for name, d := range diff.CostDelta {
+			fmt.Printf("%-18s cost %+.4f USD, p95 %+d ms\n", name, d, diff.P95Delta[name])
+		}
+	}
+}
+
+func truncateLabel(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
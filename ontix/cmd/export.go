@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/parquet"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// newExportService 建立匯出服務（CSV / JSONL / Parquet）
+func newExportService(repo repository.ExportRepository) *service.ExportService {
+	svc := service.NewExportService(repo)
+	svc.SetEncoder(entity.ExportParquet, parquet.NewEncoder)
+	return svc
+}
+
+var exportCmd = func() *cobra.Command {
+	var datasets string
+	var format string
+	var period string
+	var from string
+	var to string
+	var entities string
+	var topics string
+	var platforms string
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "匯出貼文、Entity 提及、面向、觀測與推理事實（CSV / JSONL / Parquet）",
+		Long: `匯出資料供 notebook 分析，以串流寫出不會一次載入全部資料。
+
+資料集：posts, mentions, aspects, observations, facts（all = 全部）
+匯出多個資料集時 --out 為目錄，每個資料集一個檔案；單一資料集時 --out 為檔名（- 為 stdout）`,
+		Run: func(cmd *cobra.Command, args []string) {
+			filter := entity.ExportFilter{
+				From:       parseExportDate(from, false),
+				To:         parseExportDate(to, true),
+				EntityIDs:  splitExportList(entities),
+				TopicCodes: splitExportList(topics),
+				Platforms:  splitExportList(platforms),
+			}
+			exportFx(datasets, entity.ExportFormat(format), period, filter, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&datasets, "dataset", "d", "posts", "資料集（逗號分隔，all = 全部）")
+	cmd.Flags().StringVarP(&format, "format", "f", "csv", "格式：csv / jsonl / parquet")
+	cmd.Flags().StringVarP(&period, "period", "p", "", "最近 N 週（例：4w），與 --from 擇一")
+	cmd.Flags().StringVar(&from, "from", "", "起始日（YYYY-MM-DD，含）")
+	cmd.Flags().StringVar(&to, "to", "", "結束日（YYYY-MM-DD，含）")
+	cmd.Flags().StringVar(&entities, "entity", "", "Entity ID（逗號分隔）")
+	cmd.Flags().StringVar(&topics, "topic", "", "主題代碼（逗號分隔）")
+	cmd.Flags().StringVar(&platforms, "platform", "", "平台（逗號分隔）")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "輸出檔案或目錄（預設 ./ontix-<dataset>-<date>.<format>）")
+	return cmd
+}
+
+func exportFx(datasets string, format entity.ExportFormat, period string, filter entity.ExportFilter, out string) {
+	var list []entity.ExportDataset
+	if datasets == "all" {
+		list = entity.AllExportDatasets
+	} else {
+		for _, d := range splitExportList(datasets) {
+			list = append(list, entity.ExportDataset(d))
+		}
+	}
+	if len(list) == 0 {
+		log.Fatal("--dataset is required")
+	}
+	if len(list) > 1 && out == "-" {
+		log.Fatal("--out - (stdout) only supports a single dataset")
+	}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewExportRepo,
+			newExportService,
+		),
+		fx.Invoke(func(svc *service.ExportService) {
+			ctx := context.Background()
+			now := time.Now()
+
+			for _, dataset := range list {
+				req := &service.ExportRequest{Dataset: dataset, Format: format, Period: period, Filter: filter}
+				if err := svc.Validate(req); err != nil {
+					log.Fatalf("Export error: %v", err)
+				}
+
+				path := out
+				switch {
+				case out == "":
+					path = service.ExportFilename(dataset, format, now)
+				case len(list) > 1:
+					if err := os.MkdirAll(out, 0o755); err != nil {
+						log.Fatalf("Create %s error: %v", out, err)
+					}
+					path = filepath.Join(out, service.ExportFilename(dataset, format, now))
+				}
+
+				start := time.Now()
+				n, err := exportTo(ctx, svc, req, path)
+				if err != nil {
+					log.Fatalf("Export %s error: %v", dataset, err)
+				}
+				if path != "-" {
+					fmt.Fprintf(os.Stderr, "%-12s %8d rows → %s (%s)\n", dataset, n, path, time.Since(start).Round(time.Millisecond))
+				}
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func exportTo(ctx context.Context, svc *service.ExportService, req *service.ExportRequest, path string) (int, error) {
+	if path == "-" {
+		w := bufio.NewWriter(os.Stdout)
+		n, err := svc.Export(ctx, w, req)
+		if err != nil {
+			return n, err
+		}
+		return n, w.Flush()
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriterSize(f, 1<<20)
+	n, err := svc.Export(ctx, w, req)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// parseExportDate 解析 YYYY-MM-DD；endOfDay 為 true 時含當日（回傳隔日 00:00）
+func parseExportDate(s string, endOfDay bool) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid date %q (expected YYYY-MM-DD): %v", s, err)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t
+}
+
+func splitExportList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
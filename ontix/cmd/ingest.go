@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/infra/ingest"
+	"github.com/ikala/ontix/internal/infra/redis"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+var ingestCmd = func() *cobra.Command {
+	var format string
+	var platform string
+	var batchSize int
+	var skip int
+	var limit int
+	var dryRun bool
+	var force bool
+	var rejectsPath string
+
+	cmd := &cobra.Command{
+		Use:   "ingest <file>...",
+		Short: "匯入貼文檔案（JSONL / CSV / 各平台 API 匯出 JSON）到處理佇列",
+		Long: `解析貼文檔案、依平台 Mapper 正規化並驗證後，以 pipeline 批次寫入 Redis Stream（由 worker 處理）。
+
+格式未指定時依副檔名判斷（.jsonl / .ndjson / .csv / .json），檔名為 - 時讀取 stdin。
+平台 Mapper：generic（預設，讀取 platform 欄位）、instagram、facebook、threads、youtube、tiktok。`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts := ingest.Options{
+				Format:    ingest.Format(format),
+				Platform:  platform,
+				BatchSize: batchSize,
+				Skip:      skip,
+				Limit:     limit,
+				DryRun:    dryRun,
+				Force:     force,
+			}
+			ingestFx(args, opts, rejectsPath)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "輸入格式：jsonl / csv / json（預設依副檔名）")
+	cmd.Flags().StringVarP(&platform, "platform", "p", "", "平台 Mapper（預設 generic）")
+	cmd.Flags().IntVar(&batchSize, "batch-size", ingest.DefaultBatchSize, "每次寫入 Redis 的筆數")
+	cmd.Flags().IntVarP(&skip, "skip", "s", 0, "每個檔案略過前 N 筆")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 0, "每個檔案最多處理 N 筆（0 = 全部）")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只驗證不寫入佇列")
+	cmd.Flags().BoolVar(&force, "force", false, "未變動的貼文也重新排入（略過冪等檢查）")
+	cmd.Flags().StringVar(&rejectsPath, "rejects", "", "將所有退件（含原因）寫入 JSONL 檔")
+	return cmd
+}
+
+func ingestFx(files []string, opts ingest.Options, rejectsPath string) {
+	if _, ok := ingest.Lookup(opts.Platform); !ok {
+		log.Fatalf("Unknown platform %q (available: %s)", opts.Platform, strings.Join(ingest.Names(), ", "))
+	}
+
+	// dry-run 不寫入佇列，不需要設定檔與 Redis
+	if opts.DryRun {
+		runIngest(ingest.NewIngester(nil, nil), nil, files, opts, rejectsPath)
+		return
+	}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			redis.New,
+			redis.NewStreamRepo,
+			newIngestGuard,
+			ingest.NewIngester,
+		),
+		fx.Invoke(func(ingester *ingest.Ingester, stream *redis.StreamRepo) {
+			runIngest(ingester, stream, files, opts, rejectsPath)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runIngest 逐檔匯入並輸出摘要；stream 為 nil 時（dry-run）不查詢佇列長度
+func runIngest(ingester *ingest.Ingester, stream *redis.StreamRepo, files []string, opts ingest.Options, rejectsPath string) {
+	ctx := context.Background()
+
+	var rejects *bufio.Writer
+	if rejectsPath != "" {
+		f, err := os.Create(rejectsPath)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", rejectsPath, err)
+		}
+		defer f.Close()
+		rejects = bufio.NewWriter(f)
+		defer rejects.Flush()
+	}
+
+	fmt.Println("=== Ontix Ingest ===")
+	if opts.DryRun {
+		fmt.Println("Dry run: validating only, nothing is queued")
+	}
+
+	var accepted, rejected, unchanged int
+	for _, path := range files {
+		fileOpts := opts
+		if fileOpts.Format == "" {
+			fileOpts.Format = ingest.DetectFormat(path)
+		}
+		if fileOpts.Format == "" {
+			log.Fatalf("Cannot detect format of %s, use --format", path)
+		}
+		if rejects != nil {
+			file := path
+			fileOpts.OnReject = func(r *ingest.Rejection) {
+				data, _ := json.Marshal(struct {
+					File string `json:"file"`
+					*ingest.Rejection
+				}{file, r})
+				rejects.Write(append(data, '\n'))
+			}
+		}
+
+		start := time.Now()
+		res, err := ingestFile(ctx, ingester, path, fileOpts)
+		if res != nil {
+			fmt.Printf("%s (%s): %d rows, %d accepted, %d rejected, %d unchanged (%s)\n",
+				path, fileOpts.Format, res.Total, res.Accepted, res.Rejected, res.Unchanged, time.Since(start).Round(time.Millisecond))
+			if rejects == nil {
+				printRejections(res)
+			}
+			accepted += res.Accepted
+			rejected += res.Rejected
+			unchanged += res.Unchanged
+		}
+		if err != nil {
+			log.Fatalf("Ingest %s error: %v", path, err)
+		}
+	}
+
+	fmt.Printf("\n=== Done ===\n")
+	fmt.Printf("Accepted: %d / Rejected: %d / Unchanged: %d\n", accepted, rejected, unchanged)
+	if unchanged > 0 {
+		fmt.Println("(unchanged posts were skipped, use --force to queue them again)")
+	}
+	if rejects != nil && rejected > 0 {
+		fmt.Printf("Rejections written to %s\n", rejectsPath)
+	}
+	if stream != nil {
+		if n, err := stream.Len(ctx); err == nil {
+			fmt.Printf("Queue length: %d\n", n)
+		}
+	}
+}
+
+// newIngestGuard 建立發佈端冪等檢查（內容與互動數未變的貼文在 TTL 內只排入佇列一次）
+func newIngestGuard(cfg *config.Config, client *redis.Client) *redis.IngestGuard {
+	return redis.NewIngestGuard(client, time.Duration(cfg.Dedup.SeenTTLHours)*time.Hour)
+}
+
+func ingestFile(ctx context.Context, ingester *ingest.Ingester, path string, opts ingest.Options) (*ingest.Result, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return ingester.Ingest(ctx, r, opts)
+}
+
+// printRejections 列出前 20 筆退件
+func printRejections(res *ingest.Result) {
+	const show = 20
+	for i, r := range res.Rejections {
+		if i == show {
+			fmt.Printf("  ... %d more (use --rejects to save all)\n", res.Rejected-show)
+			break
+		}
+		id := r.ID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Printf("  row %d [%s]: %s\n", r.Row, id, r.Reason)
+	}
+}
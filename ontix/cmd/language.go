@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// newTranslator 片段翻譯（language.translation 關閉時回傳 nil，API 回 503）
+func newTranslator(cfg *config.Config, client *openai.Client) *service.Translator {
+	if !cfg.Language.Translation {
+		return nil
+	}
+	target, ok := entity.ParseLanguage(cfg.Language.TranslationTarget)
+	if !ok && cfg.Language.TranslationTarget != "" {
+		log.Printf("Unknown language.translation_target %q, translating to zh", cfg.Language.TranslationTarget)
+	}
+	return service.NewTranslator(client, target)
+}
+
+func languageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "language",
+		Short: "貼文語言偵測",
+	}
+	cmd.AddCommand(languageDetectCmd())
+	return cmd
+}
+
+func languageDetectCmd() *cobra.Command {
+	var limit int
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "detect",
+		Short: "回填尚未偵測語言的歷史貼文（posts.language IS NULL）",
+		Run: func(cmd *cobra.Command, args []string) {
+			languageDetectFx(limit, batchSize)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "最多處理幾篇（0 = 全部）")
+	cmd.Flags().IntVar(&batchSize, "batch", 1000, "每批寫入的貼文數")
+	return cmd
+}
+
+func languageDetectFx(limit, batchSize int) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewPostRepo,
+		),
+		fx.Invoke(func(postRepo repository.PostRepository) {
+			ctx := context.Background()
+			if batchSize <= 0 {
+				batchSize = 1000
+			}
+
+			fmt.Println("=== Language Detection ===")
+			counts := make(map[entity.Language]int)
+			total := 0
+			var afterID int64
+			for limit <= 0 || total < limit {
+				n := batchSize
+				if limit > 0 && limit-total < n {
+					n = limit - total
+				}
+				posts, err := postRepo.ListWithoutLanguage(ctx, afterID, n)
+				if err != nil {
+					log.Fatalf("Failed to list posts: %v", err)
+				}
+				if len(posts) == 0 {
+					break
+				}
+
+				languages := make(map[string]entity.Language, len(posts))
+				for _, p := range posts {
+					lang := service.DetectLanguage(p.Content)
+					languages[p.PostID] = lang
+					counts[lang]++
+				}
+				if err := postRepo.UpdateLanguages(ctx, languages); err != nil {
+					log.Fatalf("Failed to update languages after %d posts: %v", total, err)
+				}
+				total += len(posts)
+				afterID = posts[len(posts)-1].ID
+				fmt.Printf("  %d posts...\n", total)
+			}
+
+			fmt.Printf("\n偵測完成: %d 篇\n", total)
+			langs := make([]entity.Language, 0, len(counts))
+			for lang := range counts {
+				langs = append(langs, lang)
+			}
+			sort.Slice(langs, func(i, j int) bool { return counts[langs[i]] > counts[langs[j]] })
+			for _, lang := range langs {
+				code := string(lang)
+				if code == "" {
+					code = "-"
+				}
+				fmt.Printf("  %-4s %-6s %d\n", code, lang.Name(), counts[lang])
+			}
+			if total > 0 {
+				fmt.Println("\n已有人工校正資料時，可執行 `ontix sentiment calibrate` 擬合各語言的情緒校準")
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
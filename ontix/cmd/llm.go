@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"log"
+	"time"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/gemini"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/redis"
+)
+
+// newLLMCache 建立 LLM 回應快取
+// --no-cache、設定停用或 Redis 無法連線時回傳 nil（視為停用，不影響主流程）
+func newLLMCache(cfg *config.Config) *service.LLMCache {
+	if config.NoCache || cfg.LLMCache.Disabled {
+		return nil
+	}
+	client, err := redis.New(cfg)
+	if err != nil {
+		log.Printf("LLM cache disabled: %v", err)
+		return nil
+	}
+	return service.NewLLMCache(redis.NewLLMCacheRepo(client), time.Duration(cfg.LLMCache.TTLHours)*time.Hour)
+}
+
+// newUsageTracker 建立 LLM 用量記帳 + 每日預算控管
+func newUsageTracker(cfg *config.Config, repo repository.UsageRepository) *service.UsageTracker {
+	return service.NewUsageTracker(repo, cfg.Usage.Tenant, cfg.Usage.DailyBudgetUSD)
+}
+
+// newOpenAIClient 建立 OpenAI 客戶端並掛上回應快取與用量記帳
+func newOpenAIClient(cfg *config.Config, cache *service.LLMCache, tracker *service.UsageTracker) (*openai.Client, error) {
+	client, err := openai.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.SetCache(cache)
+	client.SetUsageTracker(tracker)
+	return client, nil
+}
+
+// newGeminiClient 建立 Gemini 客戶端並掛上用量記帳
+func newGeminiClient(cfg *config.Config, tracker *service.UsageTracker) (*gemini.Client, error) {
+	client, err := gemini.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.SetUsageTracker(tracker)
+	return client, nil
+}
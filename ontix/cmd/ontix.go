@@ -38,7 +38,7 @@ func ontixFx() {
 
 		// Services
 		fx.Provide(
-			gemini.New,
+			newGeminiClient,
 			func(c *gemini.Client) service.EmbeddingService { return c },
 			func(c *gemini.Client) service.LLMService { return c },
 		),
@@ -49,6 +49,8 @@ func ontixFx() {
 			postgres.NewPostRepo,
 			postgres.NewTagRepo,
 			postgres.NewClusterRepo,
+			postgres.NewUsageRepo,
+			newUsageTracker,
 		),
 
 		// Redis
@@ -72,7 +72,10 @@ func ontologyEvaluateFx(periodStr, periodType string, materialize, narrative boo
 			postgres.NewDerivedFactRepo,
 			postgres.NewObjectRelationRepo,
 			service.NewOntologyEngine,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.NarrativeService { return c },
 		),
 		fx.Invoke(func(engine *service.OntologyEngine, narrativeSvc service.NarrativeService) {
@@ -46,6 +46,7 @@ type RawData struct {
 	OwnerID        string      `json:"owner_id"`
 	PlatformUserID string      `json:"platform_user_id"`
 	OwnerUsername  string      `json:"owner_username"`
+	OwnerFollowers int         `json:"owner_followers"`
 	PostTime       string      `json:"post_time"`
 	Content        string      `json:"content"`
 	Platform       string      `json:"platform"`
@@ -66,6 +67,7 @@ type APIRequest struct {
 	ShareCount     int    `json:"share_count"`
 	ViewCount      int    `json:"view_count"`
 	OwnerUsername  string `json:"owner_username"`
+	OwnerFollowers int    `json:"owner_followers,omitempty"`
 	PostTime       string `json:"post_time"`
 }
 
@@ -119,6 +121,7 @@ func runPush(filePath string, skip int, limit int, endpoint string, delay int) {
 	client := &http.Client{Timeout: 10 * time.Second}
 	successCount := 0
 	failCount := 0
+	skipCount := 0
 
 	for i, raw := range validPosts {
 		// Convert post_id string to int64 (try multiple fields)
@@ -161,6 +164,7 @@ func runPush(filePath string, skip int, limit int, endpoint string, delay int) {
 			ShareCount:     raw.ShareCount,
 			ViewCount:      raw.ViewCount,
 			OwnerUsername:  ownerUsername,
+			OwnerFollowers: raw.OwnerFollowers,
 			PostTime:       raw.PostTime,
 		}
 
@@ -181,6 +185,9 @@ func runPush(filePath string, skip int, limit int, endpoint string, delay int) {
 		if resp.StatusCode == http.StatusAccepted {
 			fmt.Println("OK")
 			successCount++
+		} else if resp.StatusCode == http.StatusOK {
+			fmt.Println("SKIP (unchanged)")
+			skipCount++
 		} else {
 			fmt.Printf("X %s\n", string(respBody))
 			failCount++
@@ -192,7 +199,7 @@ func runPush(filePath string, skip int, limit int, endpoint string, delay int) {
 	}
 
 	fmt.Printf("\n=== Done ===\n")
-	fmt.Printf("Success: %d / Failed: %d\n", successCount, failCount)
+	fmt.Printf("Success: %d / Skipped: %d / Failed: %d\n", successCount, skipCount, failCount)
 }
 
 func init() {
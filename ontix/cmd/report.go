@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/mail"
+	"github.com/ikala/ontix/internal/infra/openai"
+	"github.com/ikala/ontix/internal/infra/pdf"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/ikala/ontix/internal/infra/redis"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+// newReportService 建立報表服務並依設定掛上摘要快取、PDF 轉檔與 Email 寄送
+// PDF 轉檔器 / SMTP 未設定或無法使用時僅停用該功能（報表仍產出 HTML）
+func newReportService(
+	cfg *config.Config,
+	repo repository.ReportRepository,
+	data repository.ReportDataRepository,
+	summarizer service.EntitySummaryService,
+	redisClient *redis.Client,
+) *service.ReportService {
+	svc := service.NewReportService(repo, data, summarizer)
+	if redisClient != nil {
+		svc.SetSummaryCache(redisClient)
+	}
+
+	renderer, err := pdf.New(cfg)
+	if err != nil {
+		log.Printf("Report PDF disabled: %v", err)
+	}
+	if renderer != nil {
+		svc.SetPDFRenderer(renderer)
+	}
+	if mailer := mail.New(cfg); mailer != nil {
+		svc.SetMailer(mailer)
+	}
+	return svc
+}
+
+var reportCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "品牌報表：列出排程報表定義、手動產出報表",
+	}
+
+	cmd.AddCommand(reportListCmd())
+	cmd.AddCommand(reportRunCmd())
+	return cmd
+}
+
+func reportListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "列出報表定義與下次排程時間",
+		Run: func(cmd *cobra.Command, args []string) {
+			reportFx(func(ctx context.Context, svc *service.ReportService) {
+				defs, total, err := svc.List(ctx, 0, 1000)
+				if err != nil {
+					log.Fatalf("List reports error: %v", err)
+				}
+				fmt.Printf("%d report definitions\n\n", total)
+				fmt.Printf("%-5s %-28s %-6s %-16s %-8s %s\n", "ID", "NAME", "PERIOD", "SCHEDULE", "ACTIVE", "NEXT RUN")
+				for _, d := range defs {
+					next := "-"
+					if d.NextRunAt != nil {
+						next = d.NextRunAt.Format("2006-01-02 15:04 MST")
+					}
+					schedule := d.Schedule
+					if schedule == "" {
+						schedule = "(manual)"
+					}
+					fmt.Printf("%-5d %-28s %-6s %-16s %-8v %s\n", d.ID, d.Name, d.Period, schedule, d.IsActive, next)
+				}
+			})
+		},
+	}
+}
+
+func reportRunCmd() *cobra.Command {
+	var id int64
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "立即產出一份報表（寫入 report_runs，設定收件人時會寄送）",
+		Run: func(cmd *cobra.Command, args []string) {
+			if id <= 0 {
+				log.Fatal("--id is required")
+			}
+			reportFx(func(ctx context.Context, svc *service.ReportService) {
+				def, err := svc.Get(ctx, id)
+				if err != nil {
+					log.Fatalf("Load report %d error: %v", id, err)
+				}
+
+				run, err := svc.Run(ctx, def, service.ReportTriggerManual)
+				if err != nil {
+					log.Fatalf("Run report error: %v", err)
+				}
+				fmt.Printf("Report %d (%s) run %d: %s\n", def.ID, def.Name, run.ID, run.Status)
+				fmt.Printf("  Period:    %s ~ %s\n", run.PeriodStart.Format("2006-01-02"), run.PeriodEnd.Format("2006-01-02"))
+				if len(run.DeliveredTo) > 0 {
+					fmt.Printf("  Delivered: %s\n", strings.Join(run.DeliveredTo, ", "))
+				}
+				if run.Error != "" {
+					fmt.Printf("  Note:      %s\n", run.Error)
+				}
+
+				if out == "" {
+					return
+				}
+				content := []byte(run.HTML)
+				if strings.EqualFold(filepath.Ext(out), ".pdf") {
+					if len(run.PDF) == 0 {
+						log.Fatal("No PDF produced (check reports.pdf_converter)")
+					}
+					content = run.PDF
+				}
+				if err := os.WriteFile(out, content, 0o644); err != nil {
+					log.Fatalf("Write %s error: %v", out, err)
+				}
+				fmt.Printf("  Saved:     %s\n", out)
+			})
+		},
+	}
+
+	cmd.Flags().Int64Var(&id, "id", 0, "報表定義 ID")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "另存輸出檔（.html 或 .pdf）")
+	return cmd
+}
+
+func reportFx(fn func(ctx context.Context, svc *service.ReportService)) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
+			func(c *openai.Client) service.EntitySummaryService { return c },
+			postgres.New,
+			postgres.NewReportRepo,
+			postgres.NewReportDataRepo,
+			redis.New,
+			newReportService,
+		),
+		fx.Invoke(func(svc *service.ReportService) {
+			fn(context.Background(), svc)
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
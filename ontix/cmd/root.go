@@ -22,6 +22,7 @@ func Execute() {
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	rootCmd.PersistentFlags().StringVar(&config.ConfigPath, "config", "./config/dev.yaml", "config file")
+	rootCmd.PersistentFlags().BoolVar(&config.NoCache, "no-cache", false, "disable LLM response cache")
 
 	rootCmd.AddCommand(ontixCmd())
 	rootCmd.AddCommand(versionCmd())
@@ -35,4 +36,12 @@ func init() {
 	rootCmd.AddCommand(entityCmd())
 	rootCmd.AddCommand(refreshCmd())
 	rootCmd.AddCommand(ontologyCmd())
+	rootCmd.AddCommand(topicCmd())
+	rootCmd.AddCommand(evalCmd())
+	rootCmd.AddCommand(usageCmd())
+	rootCmd.AddCommand(reportCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(ingestCmd())
+	rootCmd.AddCommand(sentimentCmd())
+	rootCmd.AddCommand(languageCmd())
 }
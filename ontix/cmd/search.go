@@ -4,99 +4,98 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/ikala/ontix/config"
-	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 	"github.com/ikala/ontix/internal/infra/openai"
 	"github.com/ikala/ontix/internal/infra/postgres"
-	"github.com/pgvector/pgvector-go"
 	"github.com/spf13/cobra"
 	"go.uber.org/fx"
 )
 
 var searchCmd = func() *cobra.Command {
 	var limit int
+	var mode string
+	var platform string
 
 	cmd := &cobra.Command{
 		Use:   "search [query]",
-		Short: "語意搜尋貼文",
+		Short: "混合搜尋貼文（語意 + 關鍵字）",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			query := args[0]
-			searchFx(query, limit)
+			query := strings.Join(args, " ")
+			searchFx(query, limit, mode, platform)
 		},
 	}
 
 	cmd.Flags().IntVarP(&limit, "limit", "l", 5, "結果數量")
+	cmd.Flags().StringVar(&mode, "mode", "hybrid", "搜尋模式：hybrid / vector / keyword")
+	cmd.Flags().StringVar(&platform, "platform", "", "只搜尋指定平台（逗號分隔）")
 	return cmd
 }
 
-func searchFx(query string, limit int) {
+func searchFx(query string, limit int, mode, platform string) {
 	app := fx.New(
 		fx.NopLogger,
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EmbeddingService { return c },
 			postgres.New,
-			postgres.NewPostRepo,
-			postgres.NewTagRepo,
+			postgres.NewSearchRepo,
+			service.NewSearchService,
 		),
-		fx.Invoke(func(
-			embedSvc service.EmbeddingService,
-			postRepo repository.PostRepository,
-			tagRepo repository.TagRepository,
-			db *postgres.DB,
-		) {
+		fx.Invoke(func(searchSvc *service.SearchService) {
 			ctx := context.Background()
 
-			fmt.Printf("=== Ontix 語意搜尋 ===\n")
-			fmt.Printf("查詢: %s\n\n", query)
+			fmt.Printf("=== Ontix 搜尋 ===\n")
+			fmt.Printf("查詢: %s (%s)\n\n", query, mode)
 
-			// 1. 產生查詢向量
-			embedding, err := embedSvc.Embed(ctx, query)
-			if err != nil {
-				log.Fatalf("Embedding error: %v", err)
+			var platforms []string
+			for _, p := range strings.Split(platform, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					platforms = append(platforms, p)
+				}
 			}
 
-			// 2. 向量搜尋
-			results, err := searchSimilar(ctx, db, embedding, limit)
+			results, err := searchSvc.Search(ctx, service.SearchRequest{
+				Query:  query,
+				Mode:   entity.SearchMode(mode),
+				Filter: entity.SearchFilter{Platforms: platforms},
+				Limit:  limit,
+			})
 			if err != nil {
 				log.Fatalf("Search error: %v", err)
 			}
 
-			if len(results) == 0 {
+			if len(results.Hits) == 0 {
 				fmt.Println("找不到相關貼文")
 				return
 			}
 
-			// 3. 輸出結果
-			for i, r := range results {
-				fmt.Printf("--- #%d (相似度: %.2f%%) ---\n", i+1, r.Similarity*100)
-				fmt.Printf("ID: %s\n", r.PostID)
-
-				content := r.Content
-				if len(content) > 200 {
-					content = content[:200] + "..."
+			for i, h := range results.Hits {
+				fmt.Printf("--- #%d (score: %.4f", i+1, h.Score)
+				if h.Similarity != nil {
+					fmt.Printf(", 相似度: %.2f%%", *h.Similarity*100)
 				}
-				fmt.Printf("內容: %s\n", content)
-
-				// 取得標籤
-				tags, _ := tagRepo.FindTagsByPostID(ctx, r.PostID)
-				if len(tags) > 0 {
-					fmt.Print("標籤: ")
-					for j, t := range tags {
-						if j > 0 {
-							fmt.Print(", ")
-						}
-						fmt.Print(t.TagID)
-					}
-					fmt.Println()
+				if h.KeywordScore != nil {
+					fmt.Printf(", 關鍵字: %.3f", *h.KeywordScore)
+				}
+				fmt.Println(") ---")
+				fmt.Printf("ID: %s [%s]\n", h.PostID, h.Platform)
+				fmt.Printf("內容: %s\n", service.TruncateRunes(h.Content, 200))
+				if len(h.SoftTags) > 0 {
+					fmt.Printf("標籤: %s\n", strings.Join(h.SoftTags, ", "))
 				}
 				fmt.Println()
 			}
+			fmt.Printf("共 %d 筆命中\n", results.Total)
 		}),
 	)
 
@@ -104,35 +103,3 @@ func searchFx(query string, limit int) {
 		log.Fatal(err)
 	}
 }
-
-type SearchResult struct {
-	PostID     string
-	Content    string
-	Similarity float64
-}
-
-func searchSimilar(ctx context.Context, db *postgres.DB, embedding []float32, limit int) ([]SearchResult, error) {
-	// 新結構：JOIN post_embeddings
-	query := `
-		SELECT p.post_id, p.content, 1 - (pe.embedding <=> $1::vector) as similarity
-		FROM posts p
-		JOIN post_embeddings pe ON p.post_id = pe.post_id
-		ORDER BY pe.embedding <=> $1::vector
-		LIMIT $2`
-
-	rows, err := db.Pool.Query(ctx, query, pgvector.NewVector(embedding), limit)
-	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
-	}
-	defer rows.Close()
-
-	var results []SearchResult
-	for rows.Next() {
-		var r SearchResult
-		if err := rows.Scan(&r.PostID, &r.Content, &r.Similarity); err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
-		}
-		results = append(results, r)
-	}
-	return results, nil
-}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+func sentimentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sentiment",
+		Short: "情緒分數校準（跨模型 / prompt 版本對應到共同尺度）",
+	}
+	cmd.AddCommand(sentimentCalibrateCmd())
+	cmd.AddCommand(sentimentRescoreCmd())
+	return cmd
+}
+
+func sentimentCalibrateCmd() *cobra.Command {
+	var dryRun bool
+	var minSamples int
+
+	cmd := &cobra.Command{
+		Use:   "calibrate",
+		Short: "以人工校正的提及擬合各 model / prompt 版本的情緒分數校準（isotonic regression）",
+		Run: func(cmd *cobra.Command, args []string) {
+			sentimentCalibrateFx(dryRun, minSamples)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只顯示擬合結果，不寫入資料庫")
+	cmd.Flags().IntVar(&minSamples, "min-samples", 30, "每個 model / prompt 版本最少樣本數")
+	return cmd
+}
+
+func sentimentCalibrateFx(dryRun bool, minSamples int) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewSentimentCalibrationRepo,
+			service.NewSentimentCalibrator,
+		),
+		fx.Invoke(func(calibrator *service.SentimentCalibrator) {
+			ctx := context.Background()
+			calibrator.SetMinSamples(minSamples)
+
+			result, err := calibrator.Fit(ctx, dryRun)
+			if err != nil {
+				log.Fatalf("Calibration failed: %v", err)
+			}
+
+			fmt.Println("=== Sentiment Calibration ===")
+			fmt.Printf("Labeled mentions: %d\n", result.Samples)
+			fmt.Printf("Dry run: %v\n\n", dryRun)
+
+			if len(result.Fitted) > 0 {
+				fmt.Printf("%-20s %-12s %-6s %6s %6s %10s %9s\n", "MODEL", "PROMPT", "LANG", "N", "KNOTS", "MAE BEFORE", "MAE AFTER")
+				for _, c := range result.Fitted {
+					lang := c.Language
+					if lang == "" {
+						lang = "*"
+					}
+					fmt.Printf("%-20s %-12s %-6s %6d %6d %10.4f %9.4f\n",
+						c.Model, c.PromptVersion, lang, c.SampleCount, len(c.RawKnots), c.MAEBefore, c.MAEAfter)
+				}
+				if !dryRun {
+					fmt.Println("\n執行 `ontix sentiment rescore --rematerialize` 將校準套用到歷史資料")
+				}
+			}
+
+			if len(result.Skipped) > 0 {
+				keys := make([]string, 0, len(result.Skipped))
+				for k := range result.Skipped {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				fmt.Println("\nSkipped (raw scores kept):")
+				for _, k := range keys {
+					fmt.Printf("  %-40s %s\n", strings.TrimSuffix(k, "|"), result.Skipped[k])
+				}
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func sentimentRescoreCmd() *cobra.Command {
+	var rematerialize bool
+
+	cmd := &cobra.Command{
+		Use:   "rescore",
+		Short: "以啟用中的校準重新計算歷史提及 / 面向的情緒分數",
+		Run: func(cmd *cobra.Command, args []string) {
+			sentimentRescoreFx(rematerialize)
+		},
+	}
+
+	cmd.Flags().BoolVar(&rematerialize, "rematerialize", false, "重新聚合受影響週次的觀測並刷新 materialized views")
+	return cmd
+}
+
+func sentimentRescoreFx(rematerialize bool) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewSentimentCalibrationRepo,
+			postgres.NewObservationRepo,
+			service.NewSentimentCalibrator,
+		),
+		fx.Invoke(func(calibrator *service.SentimentCalibrator, observationRepo repository.ObservationRepository, db *postgres.DB) {
+			ctx := context.Background()
+
+			fmt.Println("=== Sentiment Rescore ===")
+			n, err := calibrator.Load(ctx)
+			if err != nil {
+				log.Fatalf("Failed to load sentiment calibrations: %v", err)
+			}
+			fmt.Printf("Active calibrations: %d\n", n)
+			if n == 0 {
+				fmt.Println("沒有啟用中的校準，請先執行 `ontix sentiment calibrate`")
+				return
+			}
+
+			result, err := calibrator.Rescore(ctx)
+			if err != nil {
+				log.Fatalf("Rescore failed after %d mentions / %d aspects: %v", result.Mentions, result.Aspects, err)
+			}
+			fmt.Printf("更新提及: %d\n", result.Mentions)
+			fmt.Printf("更新面向: %d\n", result.Aspects)
+
+			if !rematerialize || result.Since == nil {
+				return
+			}
+
+			// 從最早受影響的週一起逐週重新聚合（與 worker 相同的週期切法）
+			since := result.Since.UTC()
+			weekday := int(since.Weekday())
+			if weekday == 0 {
+				weekday = 7
+			}
+			week := time.Date(since.Year(), since.Month(), since.Day()-(weekday-1), 0, 0, 0, 0, time.UTC)
+			weeks := 0
+			for ; !week.After(time.Now()); week = week.AddDate(0, 0, 7) {
+				if _, err := observationRepo.MaterializeObservations(ctx, week, "week"); err != nil {
+					log.Fatalf("Failed to materialize observations for %s: %v", week.Format("2006-01-02"), err)
+				}
+				weeks++
+			}
+			fmt.Printf("重新聚合觀測: %d 週（自 %s）\n", weeks, result.Since.Format("2006-01-02"))
+
+			if err := db.RefreshMaterializedViews(ctx); err != nil {
+				log.Fatalf("Failed to refresh materialized views: %v", err)
+			}
+			fmt.Println("Materialized views refreshed")
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
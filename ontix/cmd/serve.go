@@ -6,7 +6,9 @@ import (
 
 	"github.com/ikala/ontix/config"
 	httpserver "github.com/ikala/ontix/internal/api/http"
+	"github.com/ikala/ontix/internal/domain/repository"
 	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/ingest"
 	"github.com/ikala/ontix/internal/infra/openai"
 	"github.com/ikala/ontix/internal/infra/postgres"
 	"github.com/ikala/ontix/internal/infra/redis"
@@ -36,7 +38,10 @@ func runServer(port int) {
 		fx.Provide(
 			config.New,
 			// OpenAI
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EmbeddingService { return c },
 			// PostgreSQL
 			postgres.New,
@@ -50,6 +55,42 @@ func runServer(port int) {
 			postgres.NewObservationRepo,
 			postgres.NewDerivedFactRepo,
 			postgres.NewObjectRelationRepo,
+			// Review Queue
+			postgres.NewReviewRepo,
+			// Hybrid Search
+			postgres.NewSearchRepo,
+			service.NewSearchService,
+			// Entity Embedding
+			postgres.NewEntityEmbeddingRepo,
+			service.NewEntityEmbedder,
+			// Ontology Q&A
+			postgres.NewGraphRetrievalRepo,
+			func(c *openai.Client) service.AskLLMService { return c },
+			service.NewAskService,
+			// Chat Sessions
+			postgres.NewChatSessionRepo,
+			service.NewChatSessionService,
+			// Scheduled Reports
+			postgres.NewReportRepo,
+			postgres.NewReportDataRepo,
+			newReportService,
+			// Bulk Export
+			postgres.NewExportRepo,
+			newExportService,
+			// 重複貼文
+			postgres.NewDedupRepo,
+			// 作者 / KOL
+			postgres.NewAuthorRepo,
+			// 競品比較
+			postgres.NewBenchmarkRepo,
+			service.NewBenchmarkService,
+			// 面向正規化（API 一律可用，不受 aspect_taxonomy.disabled 影響）
+			postgres.NewAspectTaxonomyRepo,
+			func(cfg *config.Config, repo repository.AspectTaxonomyRepository, embedSvc service.EmbeddingService) *service.AspectTaxonomy {
+				return service.NewAspectTaxonomy(repo, embedSvc, cfg.AspectTaxonomy.AutoThreshold)
+			},
+			// 片段翻譯（language.translation 關閉時為 nil）
+			newTranslator,
 			// Narrative
 			func(c *openai.Client) service.NarrativeService { return c },
 			// Entity Summary
@@ -57,6 +98,8 @@ func runServer(port int) {
 			// Redis
 			redis.New,
 			redis.NewStreamRepo,
+			newIngestGuard,
+			ingest.NewIngester,
 			// HTTP Server
 			httpserver.NewServer,
 		),
@@ -64,24 +107,52 @@ func runServer(port int) {
 			addr := fmt.Sprintf(":%d", port)
 			log.Printf("HTTP server starting on %s", addr)
 			log.Printf("  POST /api/posts     - Ingest posts")
+			log.Printf("  POST /api/posts/bulk - Bulk ingest (JSONL / CSV / platform JSON exports)")
+			log.Printf("  DELETE /api/posts/:id - Delete post (tombstone, cascades to derived data)")
+			log.Printf("  GET  /api/posts/:id/metrics - Engagement metrics history")
 			log.Printf("  GET  /api/health    - Health check")
 			log.Printf("  GET  /api/queue/len - Queue length")
-			log.Printf("  GET  /api/search    - Semantic search")
+			log.Printf("  GET  /api/search    - Hybrid search (vector + keyword, filters incl. language, facets)")
 			log.Printf("  GET  /api/dashboard - Dashboard data")
 			log.Printf("  GET  /api/entities  - Entity list (Ontology)")
+			log.Printf("  GET  /api/entities/search       - Entity semantic search")
 			log.Printf("  GET  /api/entities/:id          - Entity detail")
 			log.Printf("  GET  /api/entities/:id/aspects  - Aspect analysis")
 			log.Printf("  GET  /api/entities/:id/mentions - Post mentions")
 			log.Printf("  GET  /api/entities/:id/links    - Entity links")
 			log.Printf("  GET  /api/entities/:id/observations - Observation trend")
 			log.Printf("  GET  /api/entities/:id/summary      - AI insight summary")
+			log.Printf("  GET  /api/entities/:id/similar      - Similar entities")
 			log.Printf("  GET  /api/entity-types          - Entity types")
 			log.Printf("  GET  /api/graph                 - Entity graph (nodes+edges)")
+			log.Printf("  POST /api/ask                   - Ontology Q&A (graph RAG, SSE)")
 			log.Printf("  GET  /api/inbox                 - Inbox facts")
 			log.Printf("  GET  /api/inbox/count           - Unread count")
 			log.Printf("  PATCH /api/inbox/:id/read       - Mark fact read")
 			log.Printf("  PATCH /api/inbox/:id/dismiss    - Dismiss fact")
 			log.Printf("  GET  /api/entities/:id/facts    - Entity facts")
+			log.Printf("  POST /api/entities/:id/chat     - Entity chat (SSE, durable sessions)")
+			log.Printf("  GET  /api/chat/sessions         - Chat sessions (create / rename / delete)")
+			log.Printf("  GET  /api/chat/sessions/:id     - Chat session history")
+			log.Printf("  GET  /api/review                - Review queue")
+			log.Printf("  GET  /api/review/:id            - Review item detail")
+			log.Printf("  POST /api/review/:id/resolve    - Confirm / correct labels")
+			log.Printf("  POST /api/review/:id/skip       - Skip review item")
+			log.Printf("  GET  /api/usage                 - LLM usage / budget")
+			log.Printf("  GET  /api/reports               - Scheduled reports (create / update / delete)")
+			log.Printf("  POST /api/reports/:id/run       - Run report now")
+			log.Printf("  GET  /api/reports/:id/runs      - Report history (download HTML / PDF)")
+			log.Printf("  GET  /api/export                - Bulk export (CSV / JSONL / Parquet)")
+			log.Printf("  GET  /api/duplicates            - Duplicate groups (reposts / copy-paste)")
+			log.Printf("  GET  /api/duplicates/:post_id   - Duplicate group of a post")
+			log.Printf("  GET  /api/authors               - Author registry (followers, frequency, engagement)")
+			log.Printf("  GET  /api/authors/:id           - Author profile (follower history, entity / topic affinity)")
+			log.Printf("  GET  /api/kols                  - KOL ranking per entity or topic")
+			log.Printf("  GET  /api/entities/:id/competitors - Competitor set (competes_with + co-mention, add / remove)")
+			log.Printf("  GET  /api/benchmark             - Competitor benchmark (share of voice, sentiment gap, aspects, trend)")
+			log.Printf("  GET  /api/aspects/taxonomy      - Canonical aspect tree per class (create nodes)")
+			log.Printf("  GET  /api/aspects/review        - Unmapped aspect review queue (resolve / ignore)")
+			log.Printf("  POST /api/translate             - Translate foreign-language snippets (language.translation)")
 
 			if err := server.Run(addr); err != nil {
 				log.Fatalf("Server error: %v", err)
@@ -61,7 +61,10 @@ func tagSingleFx(content string) {
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EmbeddingService { return c },
 			func(c *openai.Client) service.LLMService { return c },
 			postgres.New,
@@ -115,6 +118,7 @@ func tagSingleFx(content string) {
 				Platform:   entity.PlatformInstagram,
 				Embedding:  embedding,
 				CreatedAt:  time.Now(),
+				Language:   service.DetectLanguage(content),
 			}
 			if err := postRepo.Save(ctx, post); err != nil {
 				log.Fatalf("Save post error: %v", err)
@@ -176,6 +180,7 @@ type RawPost struct {
 	ShareCount     int    `json:"share_count"`
 	ViewCount      int    `json:"view_count"`
 	OwnerUsername  string `json:"owner_username"`
+	OwnerFollowers int    `json:"owner_followers"`
 	PostTime       string `json:"post_time"`
 }
 
@@ -185,7 +190,10 @@ func tagBatchFx(filePath string, limit int) {
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EmbeddingService { return c },
 			func(c *openai.Client) service.LLMService { return c },
 			postgres.New,
@@ -197,8 +205,11 @@ func tagBatchFx(filePath string, limit int) {
 			llmSvc service.LLMService,
 			postRepo repository.PostRepository,
 			tagRepo repository.TagRepository,
+			llmCache *service.LLMCache,
+			cfg *config.Config,
 		) {
 			ctx := context.Background()
+			chunker := newChunker(cfg)
 
 			// 讀取 JSON
 			data, err := os.ReadFile(filePath)
@@ -247,13 +258,11 @@ func tagBatchFx(filePath string, limit int) {
 					continue
 				}
 
-				content := raw.Content
-				if len(content) > 500 {
-					content = content[:500]
-				}
+				// 標籤只看開頭一段；embedding 為各段向量合併
+				content, _ := service.Head(raw.Content, chunker.MaxRunes())
 
 				// Embedding
-				embedding, err := embedSvc.Embed(ctx, content)
+				embedding, chunks, chunkVectors, err := chunker.EmbedChunks(ctx, embedSvc, raw.Content)
 				if err != nil {
 					fmt.Printf("❌ embed: %v\n", err)
 					failCount++
@@ -275,8 +284,9 @@ func tagBatchFx(filePath string, limit int) {
 					Content:    raw.Content,
 					Platform:   toPlatform(raw.Platform),
 					Author: entity.Author{
-						ID:       raw.PlatformUserID,
-						Username: raw.OwnerUsername,
+						ID:        raw.PlatformUserID,
+						Username:  raw.OwnerUsername,
+						Followers: raw.OwnerFollowers,
 					},
 					Metrics: entity.Metrics{
 						Likes:    raw.LikeCount,
@@ -286,6 +296,7 @@ func tagBatchFx(filePath string, limit int) {
 					},
 					Embedding: embedding,
 					CreatedAt: postTime,
+					Language:  service.DetectLanguage(raw.Content),
 				}
 
 				if err := postRepo.Save(ctx, post); err != nil {
@@ -293,6 +304,16 @@ func tagBatchFx(filePath string, limit int) {
 					failCount++
 					continue
 				}
+				// 單段貼文不另存 chunk，但仍清掉舊的 chunk
+				var rows []*entity.PostChunk
+				if len(chunks) > 1 {
+					for j, ch := range chunks {
+						rows = append(rows, &entity.PostChunk{PostID: postID, ChunkIndex: ch.Index, Start: ch.Start, End: ch.End, Content: ch.Text, Embedding: chunkVectors[j]})
+					}
+				}
+				if err := postRepo.SaveChunks(ctx, postID, rows); err != nil {
+					fmt.Printf("⚠️ chunks: %v ", err)
+				}
 
 				// 儲存標籤
 				for _, t := range tags {
@@ -328,6 +349,7 @@ func tagBatchFx(filePath string, limit int) {
 
 			fmt.Printf("\n=== 完成 ===\n")
 			fmt.Printf("成功: %d / 失敗: %d\n", successCount, failCount)
+			fmt.Printf("LLM 快取: %s\n", llmCache.Summary())
 		}),
 	)
 
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+var topicCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topic",
+		Short: "主題分類：人工標註與機率校準",
+	}
+
+	cmd.AddCommand(topicLabelCmd())
+	cmd.AddCommand(topicCalibrateCmd())
+	return cmd
+}
+
+// topicLabelLine 標註檔的一行：{"post_id": "123", "topics": ["food", "travel"]}
+type topicLabelLine struct {
+	PostID string   `json:"post_id"`
+	Topics []string `json:"topics"`
+}
+
+func topicLabelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "label <labels.jsonl>",
+		Short: "匯入人工主題標註（每行一篇貼文，列出所有相關主題）",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			topicLabelFx(args[0])
+		},
+	}
+}
+
+func topicLabelFx(path string) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewTopicRepo,
+		),
+		fx.Invoke(func(topicRepo repository.TopicRepository) {
+			ctx := context.Background()
+
+			topics, err := topicRepo.FindAll(ctx)
+			if err != nil {
+				log.Fatalf("Failed to load topics: %v", err)
+			}
+			byCode := make(map[string]*entity.Topic, len(topics))
+			for _, t := range topics {
+				byCode[t.Code] = t
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				log.Fatalf("Failed to open %s: %v", path, err)
+			}
+			defer f.Close()
+
+			var posts, labels, unknown int
+			scanner := bufio.NewScanner(f)
+			scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+			for lineNo := 1; scanner.Scan(); lineNo++ {
+				if len(scanner.Bytes()) == 0 {
+					continue
+				}
+				var line topicLabelLine
+				if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+					log.Printf("line %d: invalid json: %v", lineNo, err)
+					continue
+				}
+				postID, err := strconv.ParseInt(line.PostID, 10, 64)
+				if err != nil {
+					log.Printf("line %d: invalid post_id %q", lineNo, line.PostID)
+					continue
+				}
+
+				// 標註視為完整集合：列出的主題為正樣本，其餘主題為負樣本
+				relevant := make(map[int]bool)
+				for _, code := range line.Topics {
+					t, ok := byCode[code]
+					if !ok {
+						unknown++
+						continue
+					}
+					relevant[t.ID] = true
+				}
+				for _, t := range topics {
+					label := &entity.TopicLabel{
+						PostID:     postID,
+						TopicID:    t.ID,
+						IsRelevant: relevant[t.ID],
+						Source:     entity.TagSourceManual,
+					}
+					if err := topicRepo.SaveTopicLabel(ctx, label); err != nil {
+						log.Fatalf("Failed to save label: %v", err)
+					}
+					if label.IsRelevant {
+						labels++
+					}
+				}
+				posts++
+			}
+			if err := scanner.Err(); err != nil {
+				log.Fatalf("Failed to read %s: %v", path, err)
+			}
+
+			fmt.Printf("Imported %d posts, %d positive labels", posts, labels)
+			if unknown > 0 {
+				fmt.Printf(" (%d unknown topic codes skipped)", unknown)
+			}
+			fmt.Println()
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func topicCalibrateCmd() *cobra.Command {
+	var dryRun bool
+	var minSamples int
+	var minPositive int
+
+	cmd := &cobra.Command{
+		Use:   "calibrate",
+		Short: "以人工標註擬合各主題的機率校準（Platt scaling）與多標籤門檻",
+		Run: func(cmd *cobra.Command, args []string) {
+			topicCalibrateFx(dryRun, minSamples, minPositive)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "只顯示擬合結果，不寫入資料庫")
+	cmd.Flags().IntVar(&minSamples, "min-samples", 30, "每個主題最少樣本數")
+	cmd.Flags().IntVar(&minPositive, "min-positive", 3, "每個主題最少正樣本數")
+	return cmd
+}
+
+func topicCalibrateFx(dryRun bool, minSamples, minPositive int) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewPostRepo,
+			postgres.NewTopicRepo,
+			service.NewTopicAssigner,
+			service.NewTopicCalibrator,
+		),
+		fx.Invoke(func(calibrator *service.TopicCalibrator, topicRepo repository.TopicRepository) {
+			ctx := context.Background()
+			calibrator.SetMinSamples(minSamples, minPositive)
+
+			result, err := calibrator.Fit(ctx, dryRun)
+			if err != nil {
+				log.Fatalf("Calibration failed: %v", err)
+			}
+
+			topics, _ := topicRepo.FindAll(ctx)
+			codes := make(map[int]string, len(topics))
+			for _, t := range topics {
+				codes[t.ID] = t.Code
+			}
+
+			fmt.Println("=== Topic Calibration ===")
+			fmt.Printf("Labeled posts: %d (with embedding: %d)\n", result.LabeledPosts, result.UsedPosts)
+			fmt.Printf("Dry run: %v\n\n", dryRun)
+
+			if len(result.Fitted) > 0 {
+				fmt.Printf("%-32s %8s %9s %9s %7s %9s\n", "TOPIC", "SLOPE", "INTERCEPT", "THRESHOLD", "POS/N", "LOGLOSS")
+				for _, c := range result.Fitted {
+					fmt.Printf("%-32s %8.2f %9.2f %9.3f %3d/%-3d %9.4f\n",
+						codes[c.TopicID], c.Slope, c.Intercept, c.Threshold, c.PositiveCount, c.SampleCount, c.LogLoss)
+				}
+			}
+
+			if len(result.Skipped) > 0 {
+				ids := make([]int, 0, len(result.Skipped))
+				for id := range result.Skipped {
+					ids = append(ids, id)
+				}
+				sort.Ints(ids)
+				fmt.Println("\nSkipped (uncalibrated topics use default sigmoid):")
+				for _, id := range ids {
+					fmt.Printf("  %-32s %s\n", codes[id], result.Skipped[id])
+				}
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
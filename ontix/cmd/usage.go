@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/postgres"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+var usageCmd = func() *cobra.Command {
+	var days int
+	var groupBy string
+	var tenant string
+	var stage string
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "查看 LLM 用量、成本與延遲（依階段/日期/模型/tenant 彙總）",
+		Run: func(cmd *cobra.Command, args []string) {
+			usageFx(days, groupBy, tenant, stage)
+		},
+	}
+
+	cmd.Flags().IntVar(&days, "days", 7, "統計最近幾天")
+	cmd.Flags().StringVar(&groupBy, "group-by", "stage", "彙總維度：stage / day / model / tenant")
+	cmd.Flags().StringVar(&tenant, "tenant", "", "只看指定 tenant（預設全部）")
+	cmd.Flags().StringVar(&stage, "stage", "", "只看指定階段")
+	return cmd
+}
+
+func usageFx(days int, groupBy, tenant, stage string) {
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(config.ConfigPath),
+		fx.Provide(
+			config.New,
+			postgres.New,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+		),
+		fx.Invoke(func(repo repository.UsageRepository, tracker *service.UsageTracker) {
+			ctx := context.Background()
+			if days <= 0 {
+				days = 7
+			}
+
+			filter := entity.UsageFilter{
+				Tenant:  tenant,
+				Stage:   entity.UsageStage(stage),
+				From:    time.Now().AddDate(0, 0, -days),
+				To:      time.Now(),
+				GroupBy: groupBy,
+			}
+			rows, err := repo.Summarize(ctx, filter)
+			if err != nil {
+				log.Fatalf("Failed to summarize usage: %v", err)
+			}
+
+			fmt.Printf("=== LLM Usage (last %d days, by %s) ===\n\n", days, groupBy)
+			fmt.Printf("%-24s %8s %6s %12s %12s %10s %8s\n", "GROUP", "CALLS", "FAILS", "IN_TOK", "OUT_TOK", "COST_USD", "AVG_MS")
+			fmt.Println(strings.Repeat("-", 86))
+
+			var total entity.UsageSummary
+			var latencySum float64
+			for _, r := range rows {
+				fmt.Printf("%-24s %8d %6d %12d %12d %10.4f %8.0f\n",
+					r.Group, r.Calls, r.Failures, r.InputTokens, r.OutputTokens, r.CostUSD, r.AvgLatencyMS)
+				total.Calls += r.Calls
+				total.Failures += r.Failures
+				total.InputTokens += r.InputTokens
+				total.OutputTokens += r.OutputTokens
+				total.CostUSD += r.CostUSD
+				latencySum += r.AvgLatencyMS * float64(r.Calls)
+			}
+			if total.Calls > 0 {
+				total.AvgLatencyMS = latencySum / float64(total.Calls)
+			}
+			fmt.Println(strings.Repeat("-", 86))
+			fmt.Printf("%-24s %8d %6d %12d %12d %10.4f %8.0f\n",
+				"TOTAL", total.Calls, total.Failures, total.InputTokens, total.OutputTokens, total.CostUSD, total.AvgLatencyMS)
+
+			status := tracker.Status(ctx, tenant)
+			fmt.Printf("\nToday (%s): $%.4f spent", status.Tenant, status.SpentTodayUSD)
+			if status.DailyBudgetUSD > 0 {
+				fmt.Printf(" / $%.2f budget", status.DailyBudgetUSD)
+				if status.RemainingUSD != nil {
+					fmt.Printf(" ($%.4f remaining)", *status.RemainingUSD)
+				}
+			} else {
+				fmt.Print(" (no budget limit)")
+			}
+			fmt.Println()
+			if len(status.DisabledStages) > 0 {
+				fmt.Printf("Degraded stages: %s\n", strings.Join(status.DisabledStages, ", "))
+			}
+		}),
+	)
+
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
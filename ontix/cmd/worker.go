@@ -25,22 +25,24 @@ var workerCmd = func() *cobra.Command {
 	var batchSize int
 	var timeout int
 	var concurrency int
+	var multiLabel bool
 
 	cmd := &cobra.Command{
 		Use:   "worker",
 		Short: "Start Stream Worker (consume queue)",
 		Run: func(cmd *cobra.Command, args []string) {
-			runWorker(batchSize, time.Duration(timeout)*time.Second, concurrency)
+			runWorker(batchSize, time.Duration(timeout)*time.Second, concurrency, multiLabel)
 		},
 	}
 
 	cmd.Flags().IntVarP(&batchSize, "batch", "b", 1, "Batch size")
 	cmd.Flags().IntVarP(&timeout, "timeout", "t", 1, "Wait timeout in seconds (min 1 for graceful shutdown)")
 	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 1, "Number of concurrent workers")
+	cmd.Flags().BoolVar(&multiLabel, "multi-label", false, "Multi-label topic assignment with calibrated probabilities (replaces LLM classification)")
 	return cmd
 }
 
-func runWorker(batchSize int, timeout time.Duration, concurrency int) {
+func runWorker(batchSize int, timeout time.Duration, concurrency int, multiLabel bool) {
 	// Ensure minimum timeout for graceful shutdown
 	if timeout < time.Second {
 		timeout = time.Second
@@ -66,7 +68,10 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 		fx.Supply(config.ConfigPath),
 		fx.Provide(
 			config.New,
-			openai.New,
+			newLLMCache,
+			postgres.NewUsageRepo,
+			newUsageTracker,
+			newOpenAIClient,
 			func(c *openai.Client) service.EmbeddingService { return c },
 			func(c *openai.Client) service.LLMService { return c },
 			func(c *openai.Client) service.TaggingService { return c }, // 全量 LLM 智能標註
@@ -77,19 +82,25 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 			postgres.NewTopicRepo,
 			postgres.NewPostAnalysisRepo, // 分析結果儲存
 			postgres.NewObjectRepo,      // Ontology Entity 儲存
+			postgres.NewReviewRepo,      // 人工審核佇列
 			// Ontology Phase 1
 			postgres.NewOntologySchemaRepo,
 			postgres.NewObservationRepo,
 			postgres.NewDerivedFactRepo,
 			postgres.NewObjectRelationRepo,
+			postgres.NewEntityEmbeddingRepo, // Entity embedding
 			redis.New,
 			redis.NewStreamRepo,
 			redis.NewCentroidRepo,
 			service.NewAssigner,
+			service.NewTopicAssigner,
 			// 構建 LLMClassifier
-			func(cfg *config.Config, topicRepo repository.TopicRepository) *service.LLMClassifier {
+			func(cfg *config.Config, topicRepo repository.TopicRepository, cache *service.LLMCache, tracker *service.UsageTracker) *service.LLMClassifier {
 				topics, _ := topicRepo.FindAll(context.Background())
-				return service.NewLLMClassifier(cfg.OpenAIAPIKey, "gpt-4o-mini", topics)
+				classifier := service.NewLLMClassifier(cfg.OpenAIAPIKey, "gpt-4o-mini", topics)
+				classifier.SetCache(cache)
+				classifier.SetUsageTracker(tracker)
+				return classifier
 			},
 			// 構建 ColdStartRepo
 			func(client *redis.Client) repository.ColdStartRepository {
@@ -132,6 +143,28 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 			func(c *openai.Client) service.NarrativeService { return c },
 			// Ontology 推理引擎
 			service.NewOntologyEngine,
+			service.NewEntityEmbedder,
+			// 排程報表
+			postgres.NewReportRepo,
+			postgres.NewReportDataRepo,
+			func(c *openai.Client) service.EntitySummaryService { return c },
+			newReportService,
+			// 重複貼文偵測
+			postgres.NewDedupRepo,
+			newDedupService,
+			// 抽取候選 Entity
+			newCandidateSelector,
+			newDictionaryLinker,
+			newChunker,
+			// 作者登錄
+			postgres.NewAuthorRepo,
+			newAuthorRegistry,
+			// 面向正規化
+			postgres.NewAspectTaxonomyRepo,
+			newAspectTaxonomy,
+			// 情緒分數校準
+			postgres.NewSentimentCalibrationRepo,
+			service.NewSentimentCalibrator,
 			worker.NewStreamWorker,
 		),
 		fx.Invoke(func(
@@ -145,8 +178,27 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 			entityExtractor *service.EntityExtractor,
 			ontologyEngine *service.OntologyEngine,
 			narrativeSvc service.NarrativeService,
+			topicAssigner *service.TopicAssigner,
+			reviewRepo repository.ReviewRepository,
+			llmCache *service.LLMCache,
+			entityEmbedder *service.EntityEmbedder,
+			reportSvc *service.ReportService,
+			dedupSvc *service.DedupService,
+			candidateSel *service.CandidateSelector,
+			linker *service.DictionaryLinker,
+			chunker *service.Chunker,
+			authorRegistry *service.AuthorRegistry,
+			aspectTaxonomy *service.AspectTaxonomy,
+			sentimentCalibrator *service.SentimentCalibrator,
+			cfg *config.Config,
 		) {
 			ontologyEngine.SetNarrativeService(narrativeSvc)
+			if multiLabel {
+				if err := topicAssigner.LoadCalibrations(ctx); err != nil {
+					log.Printf("load topic calibrations error (using defaults): %v", err)
+				}
+				w.SetTopicAssigner(topicAssigner)
+			}
 			w.SetBatchSize(batchSize)
 			w.SetBatchTimeout(timeout)
 			w.SetConcurrency(concurrency)
@@ -155,7 +207,34 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 			w.SetTaggingService(taggingSvc)
 			w.SetAnalysisRepo(analysisRepo)
 			w.SetEntityExtractor(entityExtractor)
+			w.SetReviewRepo(reviewRepo)
+			entityExtractor.SetReviewRepo(reviewRepo)
+			if candidateSel != nil {
+				entityExtractor.SetCandidateSelector(candidateSel)
+			}
+			if linker != nil {
+				entityExtractor.SetDictionaryLinker(linker)
+			}
+			entityExtractor.SetChunker(chunker)
+			if aspectTaxonomy != nil {
+				entityExtractor.SetAspectTaxonomy(aspectTaxonomy)
+			}
+			sentimentCalibrations, err := sentimentCalibrator.Load(ctx)
+			if err != nil {
+				log.Printf("load sentiment calibrations error (using raw scores): %v", err)
+			}
+			entityExtractor.SetSentimentCalibrator(sentimentCalibrator)
+			w.SetChunker(chunker)
 			w.SetOntologyEngine(ontologyEngine)
+			w.SetLLMCache(llmCache)
+			w.SetEntityEmbedder(entityEmbedder)
+			w.SetReportService(reportSvc)
+			if dedupSvc != nil {
+				w.SetDedupService(dedupSvc)
+			}
+			if authorRegistry != nil {
+				w.SetAuthorRegistry(authorRegistry)
+			}
 			w.SetDB(db)
 
 			topicCount := len(llmClassifier.GetTopics())
@@ -163,13 +242,57 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 			log.Printf("Batch size: %d", batchSize)
 			log.Printf("Concurrency: %d", concurrency)
 			log.Printf("Timeout: %s", timeout)
-			log.Printf("LLM Classification: %d topics (gpt-4o-mini)", topicCount)
+			if multiLabel {
+				log.Printf("Multi-label Classification: %d topics (%d calibrated)", topicCount, topicAssigner.CalibrationCount())
+			} else {
+				log.Printf("LLM Classification: %d topics (gpt-4o-mini)", topicCount)
+			}
 			log.Printf("Cold Start: enabled (trigger: %d/%d+24h/%d+7d)", entity.DefaultColdStartConfig().MinCountIdeal, entity.DefaultColdStartConfig().MinCountAcceptable, entity.DefaultColdStartConfig().MinCountFallback)
 			log.Println("Sub-cluster: KNN assignment enabled")
 			log.Println("Full LLM Tagging: enabled (sentiment, soft_tags, aspects)")
 			log.Println("Entity Extraction: enabled (Ontology)")
+			log.Printf("Chunking: %d runes/chunk, max %d chunks, %s pooling", chunker.MaxRunes(), chunker.MaxChunks(), chunker.Pooling())
+			if candidateSel != nil {
+				log.Printf("Entity Candidates: top-%d per post (alias + trigram + embedding, stats every 10 min)", candidateSel.TopK())
+			} else {
+				log.Println("Entity Candidates: disabled (all known entities injected)")
+			}
+			if linker != nil {
+				log.Println("Entity Linker: enabled (alias dictionary before LLM, source=rule)")
+			} else {
+				log.Println("Entity Linker: disabled")
+			}
 			log.Println("Materialized Views: auto-refresh every 10 min")
 			log.Println("Ontology Engine: evaluate every 1 hour")
+			log.Println("Review Queue: enabled (few-shot refresh every 10 min)")
+			if llmCache != nil {
+				log.Println("LLM Cache: enabled (stats every 10 min)")
+			} else {
+				log.Println("LLM Cache: disabled")
+			}
+			log.Println("Scheduled Reports: check every 1 min")
+			if dedupSvc != nil {
+				log.Printf("Dedup: enabled (policy=%s, threshold=%.2f)", dedupSvc.Policy(), dedupSvc.Threshold())
+			} else {
+				log.Println("Dedup: near-duplicate detection disabled (already processed posts are still skipped)")
+			}
+			switch {
+			case authorRegistry == nil:
+				log.Println("Author Registry: disabled")
+			case authorRegistry.MinFollowers() < 0:
+				log.Println("Author Registry: enabled (link existing person entities only)")
+			default:
+				log.Printf("Author Registry: enabled (auto-create person entity at %d+ followers)", authorRegistry.MinFollowers())
+			}
+			if aspectTaxonomy != nil {
+				log.Printf("Aspect Taxonomy: enabled (auto-map at similarity >= %.2f, rest to review queue)", aspectTaxonomy.AutoThreshold())
+			} else {
+				log.Println("Aspect Taxonomy: disabled (run `ontix entity aspects` to backfill)")
+			}
+			log.Printf("Sentiment Calibration: %d active (run `ontix sentiment calibrate` after model / prompt changes)", sentimentCalibrations)
+			if cfg.Usage.DailyBudgetUSD > 0 {
+				log.Printf("LLM Budget: $%.2f/day (tenant %s)", cfg.Usage.DailyBudgetUSD, cfg.Usage.Tenant)
+			}
 
 			if err := w.Run(ctx); err != nil && err != context.Canceled {
 				log.Fatalf("Worker error: %v", err)
@@ -181,3 +304,53 @@ func runWorker(batchSize int, timeout time.Duration, concurrency int) {
 		log.Fatal(err)
 	}
 }
+
+// newDedupService 建立近似重複偵測；設定停用時回傳 nil，未知策略退回預設 link
+func newDedupService(cfg *config.Config, repo repository.DedupRepository) *service.DedupService {
+	if cfg.Dedup.Disabled {
+		return nil
+	}
+	policy := entity.DedupPolicy(cfg.Dedup.Policy)
+	if policy != "" && !entity.ValidDedupPolicy(policy) {
+		log.Printf("Unknown dedup policy %q, using %s", policy, entity.DedupPolicyLink)
+		policy = entity.DedupPolicyLink
+	}
+	return service.NewDedupService(repo, policy, cfg.Dedup.Threshold, cfg.Dedup.MinChars)
+}
+
+// newCandidateSelector 建立抽取候選挑選；設定停用時回傳 nil
+func newCandidateSelector(cfg *config.Config, objectRepo repository.ObjectRepository, embedRepo repository.EntityEmbeddingRepository) *service.CandidateSelector {
+	if cfg.EntityCandidates.Disabled {
+		return nil
+	}
+	return service.NewCandidateSelector(objectRepo, embedRepo, cfg.EntityCandidates.TopK)
+}
+
+// newDictionaryLinker 建立別名規則連結；設定停用時回傳 nil
+func newDictionaryLinker(cfg *config.Config, objectRepo repository.ObjectRepository) *service.DictionaryLinker {
+	if cfg.EntityLinker.Disabled {
+		return nil
+	}
+	return service.NewDictionaryLinker(objectRepo, cfg.EntityLinker.MinAliasRunes)
+}
+
+// newAuthorRegistry 建立作者登錄；設定停用時回傳 nil
+func newAuthorRegistry(cfg *config.Config, authorRepo repository.AuthorRepository, objectRepo repository.ObjectRepository) *service.AuthorRegistry {
+	if cfg.Authors.Disabled {
+		return nil
+	}
+	return service.NewAuthorRegistry(authorRepo, objectRepo, cfg.Authors.KOLMinFollowers)
+}
+
+// newAspectTaxonomy 建立面向正規化（停用時回傳 nil）
+func newAspectTaxonomy(cfg *config.Config, repo repository.AspectTaxonomyRepository, embedSvc service.EmbeddingService) *service.AspectTaxonomy {
+	if cfg.AspectTaxonomy.Disabled {
+		return nil
+	}
+	return service.NewAspectTaxonomy(repo, embedSvc, cfg.AspectTaxonomy.AutoThreshold)
+}
+
+// newChunker 建立長文切段
+func newChunker(cfg *config.Config) *service.Chunker {
+	return service.NewChunker(cfg.Chunking.MaxRunes, cfg.Chunking.MaxChunks, service.PoolMode(cfg.Chunking.Pooling))
+}
@@ -8,6 +8,9 @@ import (
 
 var ConfigPath = ""
 
+// NoCache 停用 LLM 回應快取（--no-cache）
+var NoCache = false
+
 // Build info (set via ldflags)
 var (
 	VERSION string
@@ -31,6 +34,36 @@ type Config struct {
 
 	// ML Service
 	MLService MLServiceConfig `yaml:"ml_service"`
+
+	// LLM 回應快取
+	LLMCache LLMCacheConfig `yaml:"llm_cache"`
+
+	// LLM 用量記帳 / 每日預算
+	Usage UsageConfig `yaml:"usage"`
+
+	// 排程報表（PDF 轉檔、Email 寄送）
+	Reports ReportsConfig `yaml:"reports"`
+
+	// 重複貼文偵測（發佈端冪等 + 近似重複）
+	Dedup DedupConfig `yaml:"dedup"`
+
+	// 抽取 Prompt 的候選 Entity 挑選
+	EntityCandidates EntityCandidatesConfig `yaml:"entity_candidates"`
+
+	// LLM 之前的別名規則連結
+	EntityLinker EntityLinkerConfig `yaml:"entity_linker"`
+
+	// 長文切段（embedding 合併、分段抽取）
+	Chunking ChunkingConfig `yaml:"chunking"`
+
+	// 作者 / KOL 登錄
+	Authors AuthorsConfig `yaml:"authors"`
+
+	// 面向正規化（原始面向 → 正規面向樹）
+	AspectTaxonomy AspectTaxonomyConfig `yaml:"aspect_taxonomy"`
+
+	// 語言（偵測 / 片段翻譯）
+	Language LanguageConfig `yaml:"language"`
 }
 
 type PostgresConfig struct {
@@ -56,6 +89,69 @@ type MLServiceConfig struct {
 	Port string `yaml:"port"`
 }
 
+type LLMCacheConfig struct {
+	Disabled bool `yaml:"disabled"`
+	TTLHours int  `yaml:"ttl_hours"` // 0 = 預設 30 天
+}
+
+type UsageConfig struct {
+	Tenant         string  `yaml:"tenant"`           // 預設 tenant（API 可用 X-Tenant header 覆寫）
+	DailyBudgetUSD float64 `yaml:"daily_budget_usd"` // 0 = 不限制；超過時依序停用 narrative → summary → chat / tagging / extraction
+}
+
+type ReportsConfig struct {
+	PDFConverter string     `yaml:"pdf_converter"` // wkhtmltopdf / chromium，空字串 = 不產生 PDF
+	PDFBinary    string     `yaml:"pdf_binary"`    // 轉檔程式路徑（預設依 converter 從 PATH 尋找）
+	SMTP         SMTPConfig `yaml:"smtp"`
+}
+
+type DedupConfig struct {
+	Disabled     bool    `yaml:"disabled"`       // 停用近似重複偵測（同一篇貼文仍不會重複處理）
+	Policy       string  `yaml:"policy"`         // drop / link / process，預設 link
+	Threshold    float64 `yaml:"threshold"`      // MinHash 相似度門檻，0 = 預設 0.85
+	MinChars     int     `yaml:"min_chars"`      // 少於此字數不做近似比對，0 = 預設 20
+	SeenTTLHours int     `yaml:"seen_ttl_hours"` // 發佈端冪等 key 保留時間，0 = 預設 30 天
+}
+
+type EntityCandidatesConfig struct {
+	Disabled bool `yaml:"disabled"` // 停用時每篇貼文注入全部已知 Entity
+	TopK     int  `yaml:"top_k"`    // 每篇貼文注入的候選上限，0 = 預設 40
+}
+
+type EntityLinkerConfig struct {
+	Disabled      bool `yaml:"disabled"`        // 停用時全文送 LLM 抽取
+	MinAliasRunes int  `yaml:"min_alias_runes"` // 正規化後少於此字數的別名不做規則比對，0 = 預設 2
+}
+
+type ChunkingConfig struct {
+	MaxRunes  int    `yaml:"max_runes"`  // 單段字數上限，0 = 預設 800
+	MaxChunks int    `yaml:"max_chunks"` // 單篇最多處理段數（超過捨棄），0 = 預設 16
+	Pooling   string `yaml:"pooling"`    // mean / max，段落向量合併為貼文向量的方式，預設 mean
+}
+
+type AuthorsConfig struct {
+	Disabled        bool `yaml:"disabled"`          // 停用時不登記作者（KOL 排名只含既有資料）
+	KOLMinFollowers int  `yaml:"kol_min_followers"` // 追蹤數達此門檻自動建立 person Entity，0 = 預設 10000，負數 = 只連結既有 Entity
+}
+
+type AspectTaxonomyConfig struct {
+	Disabled      bool    `yaml:"disabled"`       // 停用時抽取不做對應（可之後以 ontix entity aspects 回填）
+	AutoThreshold float64 `yaml:"auto_threshold"` // embedding 相似度達此門檻自動對應，其餘進審核佇列，0 = 預設 0.85
+}
+
+type LanguageConfig struct {
+	Translation       bool   `yaml:"translation"`        // 開放 /api/translate 與搜尋結果的片段翻譯（另計 LLM 用量）
+	TranslationTarget string `yaml:"translation_target"` // 預設翻譯目標語言（zh / en / ja / ms），空字串 = zh
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"` // 空字串 = 不寄送
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
 // New 載入設定檔並存入全域變數
 func New(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// AskRequest Ontology 問答請求
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+	Period   string `json:"period"` // 1w / 4w / 12w，覆寫 LLM 規劃的時間範圍
+}
+
+// askRefusalMessage 證據不足時的固定回覆
+const askRefusalMessage = "資料不足：目前的知識圖譜中找不到足以回答這個問題的資料，請換個問法或指定品牌 / 產品名稱。"
+
+// ask POST /api/ask — 全域 Ontology 問答（graph RAG，SSE streaming）
+//
+// 事件順序：plan → evidence → （refusal）→ token（data: {"content":...}）→ citations → [DONE]
+func (s *Server) ask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "question is required"})
+		return
+	}
+
+	// 1. 規劃 + 檢索（失敗時仍在 SSE 之前回傳 JSON 錯誤）
+	plan, err := s.askSvc.Plan(ctx, question)
+	if errors.Is(err, service.ErrBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "daily LLM budget exceeded"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to plan question"})
+		return
+	}
+	if req.Period != "" {
+		plan.Period = req.Period
+	}
+	evidence, err := s.askSvc.Retrieve(ctx, question, plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve evidence"})
+		return
+	}
+
+	// 2. Set SSE headers
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	flusher, _ := c.Writer.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	writeContent := func(content string) {
+		chunk, _ := json.Marshal(gin.H{"content": content})
+		c.Writer.WriteString("data: " + string(chunk) + "\n\n")
+		flush()
+	}
+	done := func() {
+		c.Writer.WriteString("data: [DONE]\n\n")
+		flush()
+	}
+
+	c.SSEvent("plan", plan)
+	c.SSEvent("evidence", evidence)
+	flush()
+
+	// 3. 證據不足 → 拒答
+	tokenCh, errCh, err := s.askSvc.Answer(ctx, question, evidence)
+	if errors.Is(err, service.ErrInsufficientData) {
+		c.SSEvent("refusal", gin.H{"reason": err.Error()})
+		writeContent(askRefusalMessage)
+		done()
+		return
+	}
+
+	// 4. Stream tokens
+	var fullResponse strings.Builder
+	for {
+		select {
+		case token, ok := <-tokenCh:
+			if !ok {
+				select {
+				case err := <-errCh:
+					if err != nil {
+						c.SSEvent("error", gin.H{"error": err.Error()})
+						flush()
+					}
+				default:
+				}
+				c.SSEvent("citations", service.ExtractCitations(fullResponse.String(), evidence))
+				done()
+				return
+			}
+			fullResponse.WriteString(token)
+			writeContent(token)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateAspectNodeRequest 新增正規面向節點
+type CreateAspectNodeRequest struct {
+	Class    string   `json:"class" binding:"required"` // ontology class slug
+	ParentID *int     `json:"parent_id"`
+	Name     string   `json:"name" binding:"required"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// ResolveAspectMappingRequest 審核原始面向：指定節點或忽略
+type ResolveAspectMappingRequest struct {
+	NodeID   *int   `json:"node_id"`
+	Ignore   bool   `json:"ignore"`
+	Reviewer string `json:"reviewer"` // 未提供時取 X-User-ID
+}
+
+// getAspectTaxonomy GET /api/aspects/taxonomy?class=restaurant
+//
+// 正規面向樹；指定 class 時包含祖先 class 繼承來的節點
+func (s *Server) getAspectTaxonomy(c *gin.Context) {
+	ctx := c.Request.Context()
+	classID := 0
+	if slug := c.Query("class"); slug != "" {
+		id, err := s.classIDBySlug(ctx, slug)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve class"})
+			return
+		}
+		if id == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "class not found"})
+			return
+		}
+		classID = id
+	}
+
+	nodes, err := s.aspectTaxonomy.Nodes(ctx, classID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load aspect taxonomy"})
+		return
+	}
+	respondOne(c, nodes)
+}
+
+// createAspectNode POST /api/aspects/taxonomy
+func (s *Server) createAspectNode(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req CreateAspectNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	classID, err := s.classIDBySlug(ctx, req.Class)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve class"})
+		return
+	}
+	if classID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown class: " + req.Class})
+		return
+	}
+
+	node := &entity.AspectNode{
+		ClassID:   classID,
+		ClassSlug: req.Class,
+		ParentID:  req.ParentID,
+		Name:      req.Name,
+		Synonyms:  req.Synonyms,
+	}
+	err = s.aspectTaxonomy.CreateNode(ctx, node)
+	if errors.Is(err, service.ErrInvalidAspectNode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be unique within the class and parent must belong to the class or its ancestors"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create aspect node"})
+		return
+	}
+	c.JSON(http.StatusCreated, ApiResponse{Data: node})
+}
+
+// listAspectMappings GET /api/aspects/review?status=pending&offset=0&limit=20
+//
+// 原始面向對應；預設為審核佇列（pending，依提及數排序，附建議節點與相似度）
+func (s *Server) listAspectMappings(c *gin.Context) {
+	status := entity.AspectMappingStatus(c.DefaultQuery("status", string(entity.AspectMappingPending)))
+	switch status {
+	case entity.AspectMappingAuto, entity.AspectMappingManual, entity.AspectMappingPending, entity.AspectMappingIgnored:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of auto, manual, pending, ignored, all"})
+		return
+	}
+	offset := max(parseIntDefault(c.Query("offset"), 0), 0)
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+
+	mappings, total, err := s.aspectTaxonomy.Mappings(c.Request.Context(), status, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list aspect mappings"})
+		return
+	}
+	respondList(c, mappings, offset, limit, total)
+}
+
+// resolveAspectMapping POST /api/aspects/review/:id/resolve
+//
+// 指定節點（manual）或忽略（ignored），既有的 entity_aspects 立即更新；
+// 彙總統計與觀測在下次 refresh / materialize 後反映
+func (s *Server) resolveAspectMapping(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req ResolveAspectMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.NodeID != nil) == req.Ignore {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "provide either node_id or ignore=true"})
+		return
+	}
+	if req.Reviewer == "" {
+		req.Reviewer = c.GetHeader("X-User-ID")
+	}
+
+	mapping, applied, err := s.aspectTaxonomy.ResolveMapping(c.Request.Context(), id, req.NodeID, req.Reviewer)
+	switch {
+	case errors.Is(err, service.ErrAspectMappingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "aspect mapping not found"})
+		return
+	case errors.Is(err, service.ErrInvalidAspectNode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "node must belong to the mapping's class or its ancestors"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve aspect mapping"})
+		return
+	}
+	respondOne(c, gin.H{"mapping": mapping, "applied": applied})
+}
+
+// classIDBySlug ontology class slug → id，不存在時回傳 0
+func (s *Server) classIDBySlug(ctx context.Context, slug string) (int, error) {
+	var id int
+	err := s.db.Pool.QueryRow(ctx, `SELECT id FROM ontology_classes WHERE slug = $1`, slug).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
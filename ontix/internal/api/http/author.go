@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// listAuthors GET /api/authors?platform=instagram&q=foo&min_followers=10000&linked=true&sort=followers|posts|posts_per_week|engagement_rate|avg_sentiment|last_seen&order=desc
+//
+// 作者登錄（追蹤數、發文頻率、平均情感、互動率）
+func (s *Server) listAuthors(c *gin.Context) {
+	offset := clamp(parseIntDefault(c.Query("offset"), 0), 0, 100000)
+	limit := clamp(parseIntDefault(c.Query("limit"), 50), 1, 200)
+
+	filter := &entity.AuthorFilter{
+		Platform:     c.Query("platform"),
+		Q:            c.Query("q"),
+		MinFollowers: parseIntDefault(c.Query("min_followers"), 0),
+		LinkedOnly:   c.Query("linked") == "true",
+		Sort:         c.Query("sort"),
+		Order:        c.Query("order"),
+	}
+
+	authors, total, err := s.authorRepo.List(c.Request.Context(), filter, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list authors"})
+		return
+	}
+	if authors == nil {
+		authors = []*entity.AuthorProfile{}
+	}
+	respondList(c, authors, offset, limit, total)
+}
+
+// getAuthor GET /api/authors/:id?days=90
+//
+// 作者檔案：統計、追蹤數每日趨勢、最常提及的 Entity 與主題
+func (s *Server) getAuthor(c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author id"})
+		return
+	}
+
+	author, err := s.authorRepo.FindByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get author"})
+		return
+	}
+	if author == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "author not found"})
+		return
+	}
+
+	detail := &entity.AuthorDetail{AuthorProfile: author}
+	days := clamp(parseIntDefault(c.Query("days"), 90), 1, 730)
+	if detail.FollowerHistory, err = s.authorRepo.FollowerHistory(ctx, id, days); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get follower history"})
+		return
+	}
+	if detail.TopEntities, err = s.authorRepo.TopEntities(ctx, id, 10); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get author entities"})
+		return
+	}
+	if detail.TopTopics, err = s.authorRepo.TopTopics(ctx, id, 10); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get author topics"})
+		return
+	}
+	respondOne(c, detail)
+}
+
+// rankKOLs GET /api/kols?entity_id=<uuid>|topic_id=3&period=4w&platform=instagram&min_followers=10000&sort=posts|engagement|reach|followers|avg_sentiment&limit=20
+//
+// 某 Entity 或主題的 KOL 排名（依作者登錄彙整相關貼文數、互動數、觸及）
+func (s *Server) rankKOLs(c *gin.Context) {
+	filter := &entity.KOLRankFilter{
+		ObjectID:     c.Query("entity_id"),
+		TopicID:      parseIntDefault(c.Query("topic_id"), 0),
+		Platform:     c.Query("platform"),
+		Since:        parsePeriodSince(c.Query("period")),
+		MinFollowers: parseIntDefault(c.Query("min_followers"), 0),
+		Sort:         c.Query("sort"),
+	}
+	if (filter.ObjectID == "") == (filter.TopicID == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of entity_id or topic_id is required"})
+		return
+	}
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+
+	ranks, err := s.authorRepo.RankKOLs(c.Request.Context(), filter, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rank KOLs"})
+		return
+	}
+	respondOne(c, ranks)
+}
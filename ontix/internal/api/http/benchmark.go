@@ -0,0 +1,137 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// AddCompetitorRequest 人工指定競品
+type AddCompetitorRequest struct {
+	CompetitorID string `json:"competitor_id" binding:"required"`
+}
+
+// listCompetitors GET /api/entities/:id/competitors?source=relation|co_mention&period=12w&min_co_mentions=2&limit=9
+//
+// 競品集合：competes_with 關係（明確）+ 同類且常同篇提及的 Entity（隱含）
+func (s *Server) listCompetitors(c *gin.Context) {
+	id := c.Param("id")
+	if !isUUID(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "entity not found"})
+		return
+	}
+
+	filter := &entity.CompetitorFilter{
+		Source:        entity.CompetitorSource(c.Query("source")),
+		Since:         parsePeriodSince(c.Query("period")),
+		MinCoMentions: parseIntDefault(c.Query("min_co_mentions"), 2),
+		Limit:         clamp(parseIntDefault(c.Query("limit"), 9), 1, 50),
+	}
+	competitors, err := s.benchmarkSvc.Competitors(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list competitors"})
+		return
+	}
+	respondOne(c, competitors)
+}
+
+// addCompetitor POST /api/entities/:id/competitors
+func (s *Server) addCompetitor(c *gin.Context) {
+	var req AddCompetitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("id")
+	if !isUUID(id) || !isUUID(req.CompetitorID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity ids must be UUIDs"})
+		return
+	}
+
+	err := s.benchmarkSvc.AddCompetitor(c.Request.Context(), id, req.CompetitorID)
+	if errors.Is(err, service.ErrInvalidCompetitor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entities must be two different brands or two different products"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add competitor"})
+		return
+	}
+	c.JSON(http.StatusCreated, ApiResponse{Data: gin.H{"status": "ok"}})
+}
+
+// removeCompetitor DELETE /api/entities/:id/competitors/:competitor_id
+func (s *Server) removeCompetitor(c *gin.Context) {
+	id, competitorID := c.Param("id"), c.Param("competitor_id")
+	if !isUUID(id) || !isUUID(competitorID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "competitor relation not found"})
+		return
+	}
+
+	removed, err := s.benchmarkSvc.RemoveCompetitor(c.Request.Context(), id, competitorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove competitor"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "competitor relation not found"})
+		return
+	}
+	respondOne(c, gin.H{"status": "ok"})
+}
+
+// benchmark GET /api/benchmark?entity_id=<uuid>|ids=<uuid>,<uuid>,...&period_type=week&periods=12&min_aspect_count=3&aspect_limit=20
+//
+// 競品比較：聲量佔比、情感落差、面向逐項比較、逐期趨勢
+// ids 的第一個為主體；只給 entity_id 時以該 Entity 的競品集合比較
+func (s *Server) benchmark(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var ids []string
+	if raw := c.Query("ids"); raw != "" {
+		for _, id := range strings.Split(raw, ",") {
+			ids = append(ids, strings.TrimSpace(id))
+		}
+	} else if id := c.Query("entity_id"); id != "" {
+		if !isUUID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "entity_id must be a UUID"})
+			return
+		}
+		competitors, err := s.benchmarkSvc.Competitors(ctx, id, &entity.CompetitorFilter{MinCoMentions: 2})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve competitors"})
+			return
+		}
+		ids = append(ids, id)
+		for _, comp := range competitors {
+			ids = append(ids, comp.ObjectID)
+		}
+	}
+	for _, id := range ids {
+		if !isUUID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be UUIDs"})
+			return
+		}
+	}
+
+	result, err := s.benchmarkSvc.Benchmark(ctx, &entity.BenchmarkFilter{
+		ObjectIDs:      ids,
+		PeriodType:     c.DefaultQuery("period_type", "week"),
+		Periods:        clamp(parseIntDefault(c.Query("periods"), 12), 1, 104),
+		MinAspectCount: parseIntDefault(c.Query("min_aspect_count"), 3),
+		AspectLimit:    clamp(parseIntDefault(c.Query("aspect_limit"), 20), 1, 100),
+	})
+	if errors.Is(err, service.ErrInvalidBenchmark) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "benchmark needs 2 to 10 entities (ids, or entity_id with at least one competitor)"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build benchmark"})
+		return
+	}
+	respondOne(c, result)
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// CreateChatSessionRequest 建立對話 session 請求
+type CreateChatSessionRequest struct {
+	EntityID string `json:"entity_id" binding:"required"`
+	Title    string `json:"title"`
+	Period   string `json:"period"`
+}
+
+// RenameChatSessionRequest 修改對話 session 標題請求
+type RenameChatSessionRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+// ChatSessionDetail session 與完整對話紀錄
+type ChatSessionDetail struct {
+	*entity.ChatSession
+	Messages []*entity.ChatSessionMessage `json:"messages"`
+}
+
+// chatUserID 從 X-User-ID header 取得使用者（未提供時為 anonymous）
+func chatUserID(c *gin.Context) string {
+	if user := c.GetHeader("X-User-ID"); user != "" {
+		return user
+	}
+	return entity.DefaultChatUser
+}
+
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+// listChatSessions GET /api/chat/sessions?entity_id=&offset=0&limit=20
+func (s *Server) listChatSessions(c *gin.Context) {
+	ctx := c.Request.Context()
+	offset := max(parseIntDefault(c.Query("offset"), 0), 0)
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+
+	entityID := c.Query("entity_id")
+	if entityID != "" && !isUUID(entityID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_id must be a UUID"})
+		return
+	}
+
+	sessions, total, err := s.chatSvc.List(ctx, entity.ChatSessionFilter{
+		UserID:   chatUserID(c),
+		ObjectID: entityID,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list chat sessions"})
+		return
+	}
+	if sessions == nil {
+		sessions = []*entity.ChatSession{}
+	}
+	respondList(c, sessions, offset, limit, total)
+}
+
+// createChatSession POST /api/chat/sessions
+func (s *Server) createChatSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateChatSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !isUUID(req.EntityID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_id must be a UUID"})
+		return
+	}
+	if req.Period == "" {
+		req.Period = "4w"
+	}
+
+	var exists bool
+	if err := s.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM objects WHERE id = $1)`, req.EntityID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load entity"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "entity not found"})
+		return
+	}
+
+	session, err := s.chatSvc.Create(ctx, chatUserID(c), req.EntityID, req.Title, req.Period)
+	if errors.Is(err, service.ErrChatTitleTooLong) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create chat session"})
+		return
+	}
+	c.JSON(http.StatusCreated, ApiResponse{Data: session})
+}
+
+// getChatSession GET /api/chat/sessions/:id — session 與完整對話紀錄
+func (s *Server) getChatSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	session, ok := s.loadChatSession(c)
+	if !ok {
+		return
+	}
+	messages, err := s.chatSvc.Messages(ctx, session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load chat messages"})
+		return
+	}
+	if messages == nil {
+		messages = []*entity.ChatSessionMessage{}
+	}
+	respondOne(c, ChatSessionDetail{ChatSession: session, Messages: messages})
+}
+
+// renameChatSession PATCH /api/chat/sessions/:id
+func (s *Server) renameChatSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req RenameChatSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("id")
+	if !isUUID(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return
+	}
+
+	session, err := s.chatSvc.Rename(ctx, chatUserID(c), id, req.Title)
+	if errors.Is(err, service.ErrChatTitleTooLong) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrChatSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rename chat session"})
+		return
+	}
+	respondOne(c, session)
+}
+
+// deleteChatSession DELETE /api/chat/sessions/:id
+func (s *Server) deleteChatSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	if !isUUID(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return
+	}
+
+	err := s.chatSvc.Delete(ctx, chatUserID(c), id)
+	if errors.Is(err, service.ErrChatSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete chat session"})
+		return
+	}
+	respondOne(c, gin.H{"status": "ok"})
+}
+
+// loadChatSession 取得目前使用者的 session，失敗時已寫入錯誤回應
+func (s *Server) loadChatSession(c *gin.Context) (*entity.ChatSession, bool) {
+	id := c.Param("id")
+	if !isUUID(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return nil, false
+	}
+	session, err := s.chatSvc.Get(c.Request.Context(), chatUserID(c), id)
+	if errors.Is(err, service.ErrChatSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load chat session"})
+		return nil, false
+	}
+	return session, true
+}
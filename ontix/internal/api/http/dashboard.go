@@ -2,10 +2,12 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
 )
 
 // DashboardResponse Dashboard API 回應
@@ -35,6 +37,11 @@ type EntityRankItem struct {
 	SubType       string  `json:"sub_type,omitempty"`
 	MentionCount  int     `json:"mention_count"`
 	AvgSentiment  float64 `json:"avg_sentiment"`
+
+	// 加權指標（與上方同一批提及）
+	WeightedSentiment float64 `json:"weighted_sentiment"` // 以 1 + ln(1 + 互動數) 加權的平均情感
+	EstimatedReach    int64   `json:"estimated_reach"`    // 提及貼文的觸及估計總和
+	ShareOfVoice      float64 `json:"share_of_voice"`     // 同類 Entity 提及數佔比（0 ~ 1）
 }
 
 // RecentEntityItem 最近發現的新 Entity
@@ -54,6 +61,36 @@ type EntitySentimentItem struct {
 	AvgSentiment  float64 `json:"avg_sentiment"`
 	MentionCount  int     `json:"mention_count"`
 	PositiveRatio float64 `json:"positive_ratio"`
+
+	WeightedSentiment float64 `json:"weighted_sentiment"` // 以 1 + ln(1 + 互動數) 加權的平均情感
+}
+
+// mentionPostWeight 提及貼文的互動數與觸及估計（SQL 片段，pem 為 post_entity_mentions 別名）
+// 與 entity_observations 的加權指標同一算法：權重 1 + ln(1 + 互動數)，觸及取觀看數，沒有時用追蹤數
+const mentionPostWeight = `
+		CROSS JOIN LATERAL (
+			SELECT
+				COALESCE(MAX(COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0)), 0) AS engagement,
+				COALESCE(MAX(COALESCE(NULLIF(p.views, 0), NULLIF(p.author_followers, 0))), 0)::BIGINT AS reach
+			FROM posts p
+			WHERE p.post_id = pem.post_id
+		) pw`
+
+// weightedSentimentSQL 互動加權平均情感（搭配 mentionPostWeight）
+const weightedSentimentSQL = `COALESCE(
+			SUM((1 + LN(1 + pw.engagement)) * pem.sentiment_score)
+			/ NULLIF(SUM(1 + LN(1 + pw.engagement)) FILTER (WHERE pem.sentiment_score IS NOT NULL), 0), 0)`
+
+// postInLanguage 貼文語言篩選條件（%s 為 placeholder；post_id 欄位為 VARCHAR 時前面接欄位名）
+const postInLanguage = `IN (SELECT post_id FROM posts WHERE language = %s)`
+
+// languageFilter language 非空時附加參數並回傳 " AND <cond>"（cond 中的 %s 為 placeholder）
+func languageFilter(cond, language string, args []any) (string, []any) {
+	if language == "" {
+		return "", args
+	}
+	args = append(args, language)
+	return " AND " + fmt.Sprintf(cond, fmt.Sprintf("$%d", len(args))), args
 }
 
 // DashboardStats 統計數據
@@ -77,10 +114,12 @@ type TrendItem struct {
 
 // TopicStats 主題統計
 type TopicStats struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	PostCount int    `json:"post_count"`
-	Posts24h  int    `json:"posts_24h"`
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	PostCount     int     `json:"post_count"`
+	Posts24h      int     `json:"posts_24h"`
+	WeightedCount float64 `json:"weighted_count"` // 多標籤機率加總（分數歸屬，未校準的標籤計 1）
+	Weighted24h   float64 `json:"weighted_24h"`
 }
 
 // LiveFeedPost 即時動態貼文
@@ -110,31 +149,43 @@ type SoftTagStats struct {
 	AvgConfidence float64 `json:"avg_confidence"`
 }
 
-// dashboard GET /api/dashboard?period=4w
+// dashboard GET /api/dashboard?period=4w&language=en
+//
+// language 只篩選由貼文衍生的統計（基本統計、主題、即時動態、面向、標籤、Entity 排行）
 func (s *Server) dashboard(c *gin.Context) {
 	ctx := c.Request.Context()
 	periodInterval := parsePeriodInterval(c.Query("period"))
 
+	var language string
+	if v := c.Query("language"); v != "" {
+		lang, ok := entity.ParseLanguage(v)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "language must be one of zh, en, ja, ms"})
+			return
+		}
+		language = string(lang)
+	}
+
 	// 1. 取得基本統計
-	stats := s.getDashboardStats(ctx, periodInterval)
+	stats := s.getDashboardStats(ctx, periodInterval, language)
 
 	// 2. 取得趨勢（從 clusters）
 	trends := s.getTrends(ctx)
 
 	// 3. 取得主題統計
-	topics := s.getTopicStats(ctx)
+	topics := s.getTopicStats(ctx, language)
 
 	// 4. 取得即時動態
-	liveFeed := s.getLiveFeed(ctx, 10)
+	liveFeed := s.getLiveFeed(ctx, 10, language)
 
 	// 5. 取得面向統計
-	aspects := s.getAspectStats(ctx, 10)
+	aspects := s.getAspectStats(ctx, 10, language)
 
 	// 6. 取得熱門標籤
-	topTags := s.getTopSoftTags(ctx, 15)
+	topTags := s.getTopSoftTags(ctx, 15, language)
 
 	// 7. 取得 Entity Highlights (Ontology)
-	entityHighlights := s.getEntityHighlights(ctx, periodInterval)
+	entityHighlights := s.getEntityHighlights(ctx, periodInterval, language)
 
 	response := DashboardResponse{
 		Stats:            stats,
@@ -149,36 +200,40 @@ func (s *Server) dashboard(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func (s *Server) getDashboardStats(ctx context.Context, periodInterval string) DashboardStats {
+func (s *Server) getDashboardStats(ctx context.Context, periodInterval, language string) DashboardStats {
 	var stats DashboardStats
 
 	if periodInterval != "" {
 		// Period-filtered stats
-		s.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM posts WHERE created_at >= NOW() - $1::interval`, periodInterval).Scan(&stats.TotalPosts)
+		langFilter, args := languageFilter("language = %s", language, []any{periodInterval})
+		s.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM posts WHERE created_at >= NOW() - $1::interval`+langFilter, args...).Scan(&stats.TotalPosts)
 		s.db.Pool.QueryRow(ctx, `
 			SELECT COALESCE(AVG(sentiment_score), 0.5) FROM posts
-			WHERE sentiment_score IS NOT NULL AND created_at >= NOW() - $1::interval
-		`, periodInterval).Scan(&stats.AvgSentiment)
+			WHERE sentiment_score IS NOT NULL AND created_at >= NOW() - $1::interval`+langFilter,
+			args...).Scan(&stats.AvgSentiment)
 	} else {
 		// All-time stats
-		s.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM posts`).Scan(&stats.TotalPosts)
+		langFilter, args := languageFilter("language = %s", language, nil)
+		s.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM posts WHERE true`+langFilter, args...).Scan(&stats.TotalPosts)
 		s.db.Pool.QueryRow(ctx, `
 			SELECT COALESCE(AVG(sentiment_score), 0.5) FROM posts
-			WHERE sentiment_score IS NOT NULL
-		`).Scan(&stats.AvgSentiment)
+			WHERE sentiment_score IS NOT NULL`+langFilter,
+			args...).Scan(&stats.AvgSentiment)
 	}
 
+	langFilter, args := languageFilter("language = %s", language, nil)
+
 	// Posts today (always)
 	s.db.Pool.QueryRow(ctx, `
 		SELECT COUNT(*) FROM posts
-		WHERE created_at > NOW() - INTERVAL '24 hours'
-	`).Scan(&stats.PostsToday)
+		WHERE created_at > NOW() - INTERVAL '24 hours'`+langFilter,
+		args...).Scan(&stats.PostsToday)
 
 	// Posts this week (always)
 	s.db.Pool.QueryRow(ctx, `
 		SELECT COUNT(*) FROM posts
-		WHERE created_at > NOW() - INTERVAL '7 days'
-	`).Scan(&stats.PostsThisWeek)
+		WHERE created_at > NOW() - INTERVAL '7 days'`+langFilter,
+		args...).Scan(&stats.PostsThisWeek)
 
 	// Queue length
 	queueLen, _ := s.stream.Len(ctx)
@@ -227,21 +282,25 @@ func (s *Server) getTrends(ctx context.Context) []TrendItem {
 	return trends
 }
 
-func (s *Server) getTopicStats(ctx context.Context) []TopicStats {
+func (s *Server) getTopicStats(ctx context.Context, language string) []TopicStats {
 	var topics []TopicStats
 
+	// post_topics.post_id 為 BIGINT；語言條件放在 JOIN 上，沒有該語言貼文的主題仍列出（計數為 0）
+	langFilter, args := languageFilter("pt.post_id::text "+postInLanguage, language, nil)
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
 			t.id,
 			t.name,
 			COUNT(pt.post_id) as post_count,
-			COUNT(pt.post_id) FILTER (WHERE pt.assigned_at > NOW() - INTERVAL '24 hours') as posts_24h
+			COUNT(pt.post_id) FILTER (WHERE pt.assigned_at > NOW() - INTERVAL '24 hours') as posts_24h,
+			COALESCE(SUM(COALESCE(pt.probability, 1)) FILTER (WHERE pt.post_id IS NOT NULL), 0) as weighted_count,
+			COALESCE(SUM(COALESCE(pt.probability, 1)) FILTER (WHERE pt.assigned_at > NOW() - INTERVAL '24 hours'), 0) as weighted_24h
 		FROM topics t
-		LEFT JOIN post_topics pt ON t.id = pt.topic_id
+		LEFT JOIN post_topics pt ON t.id = pt.topic_id`+langFilter+`
 		GROUP BY t.id, t.name
 		ORDER BY post_count DESC
 		LIMIT 10
-	`)
+	`, args...)
 	if err != nil {
 		return topics
 	}
@@ -249,7 +308,7 @@ func (s *Server) getTopicStats(ctx context.Context) []TopicStats {
 
 	for rows.Next() {
 		var t TopicStats
-		if err := rows.Scan(&t.ID, &t.Name, &t.PostCount, &t.Posts24h); err != nil {
+		if err := rows.Scan(&t.ID, &t.Name, &t.PostCount, &t.Posts24h, &t.WeightedCount, &t.Weighted24h); err != nil {
 			continue
 		}
 		topics = append(topics, t)
@@ -258,9 +317,10 @@ func (s *Server) getTopicStats(ctx context.Context) []TopicStats {
 	return topics
 }
 
-func (s *Server) getLiveFeed(ctx context.Context, limit int) []LiveFeedPost {
+func (s *Server) getLiveFeed(ctx context.Context, limit int, language string) []LiveFeedPost {
 	var posts []LiveFeedPost
 
+	langFilter, args := languageFilter("p.language = %s", language, []any{limit})
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
 			p.post_id,
@@ -274,9 +334,10 @@ func (s *Server) getLiveFeed(ctx context.Context, limit int) []LiveFeedPost {
 			) as soft_tags,
 			p.created_at
 		FROM posts p
+		WHERE true`+langFilter+`
 		ORDER BY p.created_at DESC
 		LIMIT $1
-	`, limit)
+	`, args...)
 	if err != nil {
 		return posts
 	}
@@ -293,18 +354,17 @@ func (s *Server) getLiveFeed(ctx context.Context, limit int) []LiveFeedPost {
 		p.CreatedAt = createdAt.Format(time.RFC3339)
 
 		// 截斷內容
-		if len(p.Content) > 150 {
-			p.Content = p.Content[:150] + "..."
-		}
+		p.Content = excerpt(p.Content, 150)
 		posts = append(posts, p)
 	}
 
 	return posts
 }
 
-func (s *Server) getAspectStats(ctx context.Context, limit int) []AspectStats {
+func (s *Server) getAspectStats(ctx context.Context, limit int, language string) []AspectStats {
 	var aspects []AspectStats
 
+	langFilter, args := languageFilter("post_id "+postInLanguage, language, []any{limit})
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
 			aspect,
@@ -314,10 +374,11 @@ func (s *Server) getAspectStats(ctx context.Context, limit int) []AspectStats {
 			COUNT(*) FILTER (WHERE sentiment = 'neutral') as neutral_count,
 			ROUND(COUNT(*) FILTER (WHERE sentiment = 'positive')::NUMERIC / NULLIF(COUNT(*), 0) * 100, 1) as positive_ratio
 		FROM post_aspects
+		WHERE true`+langFilter+`
 		GROUP BY aspect
 		ORDER BY total DESC
 		LIMIT $1
-	`, limit)
+	`, args...)
 	if err != nil {
 		return aspects
 	}
@@ -336,17 +397,17 @@ func (s *Server) getAspectStats(ctx context.Context, limit int) []AspectStats {
 
 // --- Entity Highlights ---
 
-func (s *Server) getEntityHighlights(ctx context.Context, periodInterval string) *EntityHighlights {
+func (s *Server) getEntityHighlights(ctx context.Context, periodInterval, language string) *EntityHighlights {
 	return &EntityHighlights{
-		TopEntities:      s.getTopEntities(ctx, 10, periodInterval),
+		TopEntities:      s.getTopEntities(ctx, 10, periodInterval, language),
 		RecentEntities:   s.getRecentEntities(ctx, 5),
-		SentimentRanking: s.getEntitySentimentRanking(ctx, 10, periodInterval),
+		SentimentRanking: s.getEntitySentimentRanking(ctx, 10, periodInterval, language),
 		TypeDistribution: s.getEntityTypeDistribution(ctx),
 	}
 }
 
 // getTopEntities 被提及最多的 Entity（Top N）
-func (s *Server) getTopEntities(ctx context.Context, limit int, periodInterval string) []EntityRankItem {
+func (s *Server) getTopEntities(ctx context.Context, limit int, periodInterval, language string) []EntityRankItem {
 	var items []EntityRankItem
 
 	periodFilter := ""
@@ -355,6 +416,7 @@ func (s *Server) getTopEntities(ctx context.Context, limit int, periodInterval s
 		periodFilter = " AND pem.created_at >= NOW() - $2::interval"
 		queryArgs = append(queryArgs, periodInterval)
 	}
+	langFilter, queryArgs := languageFilter("pem.post_id "+postInLanguage, language, queryArgs)
 
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
@@ -363,11 +425,14 @@ func (s *Server) getTopEntities(ctx context.Context, limit int, periodInterval s
 			ot.name as type,
 			COALESCE(o.properties->>'sub_type', '') as sub_type,
 			COUNT(pem.id) as mention_count,
-			COALESCE(AVG(pem.sentiment_score), 0) as avg_sentiment
+			COALESCE(AVG(pem.sentiment_score), 0) as avg_sentiment,
+			`+weightedSentimentSQL+` as weighted_sentiment,
+			COALESCE(SUM(pw.reach), 0)::BIGINT as estimated_reach,
+			COUNT(pem.id)::float8 / SUM(COUNT(pem.id)) OVER (PARTITION BY ot.name) as share_of_voice
 		FROM objects o
 		JOIN object_types ot ON o.type_id = ot.id
-		JOIN post_entity_mentions pem ON o.id = pem.object_id
-		WHERE o.status = 'active'`+periodFilter+`
+		JOIN post_entity_mentions pem ON o.id = pem.object_id`+mentionPostWeight+`
+		WHERE o.status = 'active'`+periodFilter+langFilter+`
 		GROUP BY o.id, o.canonical_name, ot.name, o.properties->>'sub_type'
 		ORDER BY mention_count DESC
 		LIMIT $1
@@ -379,7 +444,8 @@ func (s *Server) getTopEntities(ctx context.Context, limit int, periodInterval s
 
 	for rows.Next() {
 		var e EntityRankItem
-		if err := rows.Scan(&e.ID, &e.CanonicalName, &e.Type, &e.SubType, &e.MentionCount, &e.AvgSentiment); err != nil {
+		if err := rows.Scan(&e.ID, &e.CanonicalName, &e.Type, &e.SubType, &e.MentionCount, &e.AvgSentiment,
+			&e.WeightedSentiment, &e.EstimatedReach, &e.ShareOfVoice); err != nil {
 			continue
 		}
 		items = append(items, e)
@@ -422,7 +488,7 @@ func (s *Server) getRecentEntities(ctx context.Context, limit int) []RecentEntit
 }
 
 // getEntitySentimentRanking 品牌情感排行（至少 2 次提及才列入）
-func (s *Server) getEntitySentimentRanking(ctx context.Context, limit int, periodInterval string) []EntitySentimentItem {
+func (s *Server) getEntitySentimentRanking(ctx context.Context, limit int, periodInterval, language string) []EntitySentimentItem {
 	var items []EntitySentimentItem
 
 	periodFilter := ""
@@ -431,6 +497,7 @@ func (s *Server) getEntitySentimentRanking(ctx context.Context, limit int, perio
 		periodFilter = " AND pem.created_at >= NOW() - $2::interval"
 		queryArgs = append(queryArgs, periodInterval)
 	}
+	langFilter, queryArgs := languageFilter("pem.post_id "+postInLanguage, language, queryArgs)
 
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
@@ -442,11 +509,12 @@ func (s *Server) getEntitySentimentRanking(ctx context.Context, limit int, perio
 			ROUND(
 				COUNT(pem.id) FILTER (WHERE pem.sentiment = 'positive')::NUMERIC
 				/ NULLIF(COUNT(pem.id), 0) * 100, 1
-			) as positive_ratio
+			) as positive_ratio,
+			`+weightedSentimentSQL+` as weighted_sentiment
 		FROM objects o
 		JOIN object_types ot ON o.type_id = ot.id
-		JOIN post_entity_mentions pem ON o.id = pem.object_id
-		WHERE o.status = 'active'`+periodFilter+`
+		JOIN post_entity_mentions pem ON o.id = pem.object_id`+mentionPostWeight+`
+		WHERE o.status = 'active'`+periodFilter+langFilter+`
 		GROUP BY o.id, o.canonical_name, ot.name
 		HAVING COUNT(pem.id) >= 2
 		ORDER BY avg_sentiment DESC
@@ -459,7 +527,7 @@ func (s *Server) getEntitySentimentRanking(ctx context.Context, limit int, perio
 
 	for rows.Next() {
 		var e EntitySentimentItem
-		if err := rows.Scan(&e.ID, &e.CanonicalName, &e.Type, &e.AvgSentiment, &e.MentionCount, &e.PositiveRatio); err != nil {
+		if err := rows.Scan(&e.ID, &e.CanonicalName, &e.Type, &e.AvgSentiment, &e.MentionCount, &e.PositiveRatio, &e.WeightedSentiment); err != nil {
 			continue
 		}
 		items = append(items, e)
@@ -495,19 +563,21 @@ func (s *Server) getEntityTypeDistribution(ctx context.Context) map[string]int {
 	return dist
 }
 
-func (s *Server) getTopSoftTags(ctx context.Context, limit int) []SoftTagStats {
+func (s *Server) getTopSoftTags(ctx context.Context, limit int, language string) []SoftTagStats {
 	var tags []SoftTagStats
 
+	langFilter, args := languageFilter("post_id "+postInLanguage, language, []any{limit})
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT
 			tag,
 			COUNT(*) as post_count,
 			AVG(confidence) as avg_confidence
 		FROM post_soft_tags
+		WHERE true`+langFilter+`
 		GROUP BY tag
 		ORDER BY post_count DESC
 		LIMIT $1
-	`, limit)
+	`, args...)
 	if err != nil {
 		return tags
 	}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// listDuplicateGroups GET /api/duplicates?platform=instagram&method=exact|minhash&min_size=2&since=2026-01-01&per_group=5
+//
+// 依重複數排序列出重複群組（轉貼、複製貼上洗版），每組附最近 per_group 筆重複貼文
+func (s *Server) listDuplicateGroups(c *gin.Context) {
+	offset := clamp(parseIntDefault(c.Query("offset"), 0), 0, 100000)
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+	perGroup := clamp(parseIntDefault(c.Query("per_group"), 5), 0, 50)
+
+	filter := &entity.DuplicateFilter{
+		Platform: c.Query("platform"),
+		Method:   entity.DedupMethod(c.Query("method")),
+		MinSize:  parseIntDefault(c.Query("min_size"), 1),
+	}
+	switch filter.Method {
+	case "", entity.DedupMethodExact, entity.DedupMethodMinHash:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "method must be exact or minhash"})
+		return
+	}
+	if since := c.Query("since"); since != "" {
+		if filter.Since = parseTimeParam(since, false); filter.Since == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be RFC3339 or YYYY-MM-DD"})
+			return
+		}
+	}
+
+	groups, total, err := s.dedupRepo.ListGroups(c.Request.Context(), filter, perGroup, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list duplicate groups"})
+		return
+	}
+	if groups == nil {
+		groups = []*entity.DuplicateGroup{}
+	}
+	respondList(c, groups, offset, limit, total)
+}
+
+// getDuplicateGroup GET /api/duplicates/:post_id
+//
+// 貼文所屬的重複群組（post_id 可為原始貼文或任一重複貼文）
+func (s *Server) getDuplicateGroup(c *gin.Context) {
+	group, err := s.dedupRepo.GetGroup(c.Request.Context(), c.Param("post_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get duplicate group"})
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post has no duplicates"})
+		return
+	}
+	respondOne(c, group)
+}
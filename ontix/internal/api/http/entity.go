@@ -3,12 +3,15 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 )
 
@@ -56,12 +59,14 @@ type EntityDetailStats struct {
 
 // EntityAspectSummary Aspect 摘要
 type EntityAspectSummary struct {
-	Aspect        string  `json:"aspect"`
-	Total         int     `json:"total"`
-	AvgSentiment  float64 `json:"avg_sentiment"`
-	PositiveCount int     `json:"positive_count"`
-	NegativeCount int     `json:"negative_count"`
-	NeutralCount  int     `json:"neutral_count"`
+	Aspect        string   `json:"aspect"` // 正規面向名稱，未對應時為原字串
+	NodeID        *int     `json:"node_id,omitempty"`
+	Path          []string `json:"path,omitempty"` // 根 → 節點
+	Total         int      `json:"total"`
+	AvgSentiment  float64  `json:"avg_sentiment"`
+	PositiveCount int      `json:"positive_count"`
+	NegativeCount int      `json:"negative_count"`
+	NeutralCount  int      `json:"neutral_count"`
 }
 
 // EntityLinkItem Entity 之間的關係
@@ -76,14 +81,52 @@ type EntityLinkItem struct {
 
 // EntityMentionItem 貼文提及
 type EntityMentionItem struct {
-	PostID         string  `json:"post_id"`
-	Content        string  `json:"content"`
-	Sentiment      string  `json:"sentiment"`
-	SentimentScore float64 `json:"sentiment_score"`
-	MentionText    string  `json:"mention_text"`
-	AuthorName     string  `json:"author_name"`
-	Platform       string  `json:"platform"`
-	CreatedAt      string  `json:"created_at"`
+	PostID         string   `json:"post_id"`
+	Content        string   `json:"content"`
+	Sentiment      string   `json:"sentiment"`
+	SentimentScore *float64 `json:"sentiment_score"` // 規則連結的提及沒有分數（null）
+	MentionText    string   `json:"mention_text"`
+	AuthorName     string   `json:"author_name"`
+	Platform       string   `json:"platform"`
+	CreatedAt      string   `json:"created_at"`
+	Kind           string   `json:"kind"` // post / comment / reply
+
+	// 原文位置（posts.content 的字元 / code point 位置，end 不含；對不上時省略）
+	// content 為以提及為中心的片段，從原文第 content_offset 個字元開始：
+	// 標示位置 = mention_start - content_offset
+	// *_utf16 為同一位置的 UTF-16 code unit 索引（JavaScript 字串索引，emoji 等補充平面字元佔 2）
+	MentionStart       *int            `json:"mention_start,omitempty"`
+	MentionEnd         *int            `json:"mention_end,omitempty"`
+	ContentOffset      int             `json:"content_offset"`
+	MentionStartUTF16  *int            `json:"mention_start_utf16,omitempty"`
+	MentionEndUTF16    *int            `json:"mention_end_utf16,omitempty"`
+	ContentOffsetUTF16 int             `json:"content_offset_utf16"`
+	Aspects            []MentionAspect `json:"aspects,omitempty"`
+
+	Thread *MentionThread `json:"thread,omitempty"` // 留言 / 回覆所屬的串
+
+	content []rune // 完整原文（換算面向的 UTF-16 位置用）
+}
+
+// MentionAspect 貼文中對該 Entity 的面向評價（位置同 EntityMentionItem）
+type MentionAspect struct {
+	Aspect            string `json:"aspect"`
+	Sentiment         string `json:"sentiment"`
+	MentionText       string `json:"mention_text"`
+	MentionStart      *int   `json:"mention_start,omitempty"`
+	MentionEnd        *int   `json:"mention_end,omitempty"`
+	MentionStartUTF16 *int   `json:"mention_start_utf16,omitempty"`
+	MentionEndUTF16   *int   `json:"mention_end_utf16,omitempty"`
+}
+
+// MentionThread 留言提及的串上下文
+type MentionThread struct {
+	RootPostID    string `json:"root_post_id"`
+	RootContent   string `json:"root_content"`
+	RootAuthor    string `json:"root_author"`
+	ParentPostID  string `json:"parent_post_id"`
+	ParentContent string `json:"parent_content,omitempty"` // 回覆的上層留言
+	ParentAuthor  string `json:"parent_author,omitempty"`
 }
 
 // EntityTypeItem Entity type 統計
@@ -115,6 +158,20 @@ type ObservationItem struct {
 	NegativeCount int     `json:"negative_count"`
 	NeutralCount  int     `json:"neutral_count"`
 	MixedCount    int     `json:"mixed_count"`
+
+	// 留言 / 回覆的提及（上方計數只含原始貼文）
+	CommentMentionCount  int     `json:"comment_mention_count"`
+	CommentAvgSentiment  float64 `json:"comment_avg_sentiment"`
+	CommentPositiveCount int     `json:"comment_positive_count"`
+	CommentNegativeCount int     `json:"comment_negative_count"`
+	CommentNeutralCount  int     `json:"comment_neutral_count"`
+	CommentMixedCount    int     `json:"comment_mixed_count"`
+
+	// 加權指標（只含原始貼文）
+	WeightedSentiment *float64 `json:"weighted_sentiment"` // 以互動數加權的平均情感，尚未計算時為 nil
+	TotalEngagement   int64    `json:"total_engagement"`
+	EstimatedReach    int64    `json:"estimated_reach"`
+	ShareOfVoice      *float64 `json:"share_of_voice"` // 同類 Entity 提及數佔比（0 ~ 1）
 }
 
 // --- Handlers ---
@@ -152,7 +209,9 @@ func (s *Server) getEntity(c *gin.Context) {
 	respondOne(c, detail)
 }
 
-// getEntityAspects GET /api/entities/:id/aspects?q=服務&sentiment=positive&sort=total&order=desc&offset=0&limit=20
+// getEntityAspects GET /api/entities/:id/aspects?q=服務&sentiment=positive&level=1&sort=total&order=desc&offset=0&limit=20
+//
+// level 為面向樹的彙總層級（1 = 頂層，例：薯條、飲料併入餐點），省略時以正規節點逐項列出
 func (s *Server) getEntityAspects(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
@@ -162,7 +221,7 @@ func (s *Server) getEntityAspects(c *gin.Context) {
 	respondList(c, aspects, params.Offset, params.Limit, total)
 }
 
-// getEntityMentions GET /api/entities/:id/mentions?sentiment=positive&sort=created_at&order=desc&offset=0&limit=20
+// getEntityMentions GET /api/entities/:id/mentions?sentiment=positive&kind=comment&sort=created_at&order=desc&offset=0&limit=20
 func (s *Server) getEntityMentions(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
@@ -508,9 +567,30 @@ func (s *Server) getEntityAspectStatsV2(ctx context.Context, id string, p Aspect
 
 	whereSQL := " WHERE " + strings.Join(whereClauses, " AND ")
 
+	// level > 0：子節點彙總到面向樹的第 level 層（未對應的面向維持原字串）
+	source := "entity_aspect_stats"
+	if p.Level > 0 {
+		depth := "LEAST(" + strconv.Itoa(p.Level) + ", cardinality(aspect_path))"
+		source = `(
+			SELECT
+				object_id,
+				COALESCE(aspect_path[` + depth + `], aspect) AS aspect,
+				aspect_path[1:` + depth + `] AS aspect_path,
+				MAX(aspect_node_id) FILTER (WHERE cardinality(aspect_path) <= ` + strconv.Itoa(p.Level) + `) AS aspect_node_id,
+				SUM(total) AS total,
+				SUM(avg_sentiment_score * total) / NULLIF(SUM(total), 0) AS avg_sentiment_score,
+				SUM(positive_count) AS positive_count,
+				SUM(negative_count) AS negative_count,
+				SUM(neutral_count) AS neutral_count
+			FROM entity_aspect_stats
+			WHERE object_id = $1
+			GROUP BY 1, 2, 3
+		) rolled`
+	}
+
 	// Count
 	var total int
-	countQuery := "SELECT COUNT(*) FROM entity_aspect_stats" + whereSQL
+	countQuery := "SELECT COUNT(*) FROM " + source + whereSQL
 	if err := s.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
 		return aspects, 0
 	}
@@ -520,12 +600,14 @@ func (s *Server) getEntityAspectStatsV2(ctx context.Context, id string, p Aspect
 	dataQuery := `
 		SELECT
 			aspect,
-			total,
-			COALESCE(avg_sentiment_score, 0) as avg_sentiment,
-			positive_count,
-			negative_count,
-			neutral_count
-		FROM entity_aspect_stats
+			aspect_node_id,
+			aspect_path,
+			total::int,
+			COALESCE(avg_sentiment_score, 0)::float8 as avg_sentiment,
+			positive_count::int,
+			negative_count::int,
+			neutral_count::int
+		FROM ` + source + `
 	` + whereSQL + `
 		ORDER BY ` + orderBy + `
 		LIMIT $` + strconv.Itoa(argIdx) + ` OFFSET $` + strconv.Itoa(argIdx+1)
@@ -540,7 +622,7 @@ func (s *Server) getEntityAspectStatsV2(ctx context.Context, id string, p Aspect
 
 	for rows.Next() {
 		var a EntityAspectSummary
-		if err := rows.Scan(&a.Aspect, &a.Total, &a.AvgSentiment, &a.PositiveCount, &a.NegativeCount, &a.NeutralCount); err != nil {
+		if err := rows.Scan(&a.Aspect, &a.NodeID, &a.Path, &a.Total, &a.AvgSentiment, &a.PositiveCount, &a.NegativeCount, &a.NeutralCount); err != nil {
 			continue
 		}
 		aspects = append(aspects, a)
@@ -569,6 +651,13 @@ func (s *Server) getEntityMentionListV2(ctx context.Context, id string, p Mentio
 		argIdx++
 	}
 
+	switch p.Kind {
+	case string(entity.PostKindPost):
+		whereClauses = append(whereClauses, "NOT EXISTS (SELECT 1 FROM posts kp WHERE kp.post_id = pem.post_id AND kp.kind <> 'post')")
+	case string(entity.PostKindComment):
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM posts kp WHERE kp.post_id = pem.post_id AND kp.kind <> 'post')")
+	}
+
 	whereSQL := " WHERE " + strings.Join(whereClauses, " AND ")
 
 	// Count
@@ -587,11 +676,22 @@ func (s *Server) getEntityMentionListV2(ctx context.Context, id string, p Mentio
 			pem.sentiment,
 			pem.sentiment_score,
 			pem.mention_text,
+			pem.mention_start,
+			pem.mention_end,
 			COALESCE(p.author_username, '') as author_name,
 			COALESCE(p.platform, '') as platform,
-			pem.created_at
+			pem.created_at,
+			COALESCE(p.kind, 'post'),
+			COALESCE(p.root_post_id, ''),
+			COALESCE(rp.content, ''),
+			COALESCE(rp.author_username, ''),
+			COALESCE(p.parent_post_id, ''),
+			COALESCE(pp.content, ''),
+			COALESCE(pp.author_username, '')
 		FROM post_entity_mentions pem
 		LEFT JOIN posts p ON pem.post_id = p.post_id
+		LEFT JOIN posts rp ON rp.post_id = p.root_post_id
+		LEFT JOIN posts pp ON pp.post_id = p.parent_post_id AND p.kind = 'reply'
 	` + whereSQL + `
 		ORDER BY ` + orderBy + `
 		LIMIT $` + strconv.Itoa(argIdx) + ` OFFSET $` + strconv.Itoa(argIdx+1)
@@ -607,19 +707,66 @@ func (s *Server) getEntityMentionListV2(ctx context.Context, id string, p Mentio
 	for rows.Next() {
 		var m EntityMentionItem
 		var createdAt time.Time
-		if err := rows.Scan(&m.PostID, &m.Content, &m.Sentiment, &m.SentimentScore, &m.MentionText, &m.AuthorName, &m.Platform, &createdAt); err != nil {
+		var t MentionThread
+		if err := rows.Scan(&m.PostID, &m.Content, &m.Sentiment, &m.SentimentScore, &m.MentionText, &m.MentionStart, &m.MentionEnd,
+			&m.AuthorName, &m.Platform, &createdAt,
+			&m.Kind, &t.RootPostID, &t.RootContent, &t.RootAuthor, &t.ParentPostID, &t.ParentContent, &t.ParentAuthor); err != nil {
 			continue
 		}
 		m.CreatedAt = createdAt.Format(time.RFC3339)
-		if len(m.Content) > 200 {
-			m.Content = m.Content[:200] + "..."
+		m.content = []rune(m.Content)
+		m.MentionStartUTF16, m.MentionEndUTF16 = utf16Span(m.content, m.MentionStart, m.MentionEnd)
+		m.Content, m.ContentOffset = excerptAround(m.Content, m.MentionStart, m.MentionEnd, 200)
+		m.ContentOffsetUTF16 = utf16Index(m.content, m.ContentOffset)
+		if m.Kind != string(entity.PostKindPost) {
+			t.RootContent = excerpt(t.RootContent, 200)
+			t.ParentContent = excerpt(t.ParentContent, 200)
+			m.Thread = &t
 		}
 		mentions = append(mentions, m)
 	}
+	rows.Close()
 
+	s.attachMentionAspects(ctx, id, mentions)
 	return mentions, total
 }
 
+// attachMentionAspects 補上各貼文中對該 Entity 的面向評價與原文位置
+func (s *Server) attachMentionAspects(ctx context.Context, id string, mentions []EntityMentionItem) {
+	if len(mentions) == 0 {
+		return
+	}
+	postIDs := make([]string, len(mentions))
+	index := make(map[string]int, len(mentions))
+	for i, m := range mentions {
+		postIDs[i] = m.PostID
+		index[m.PostID] = i
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT post_id, aspect, sentiment, COALESCE(mention_text, ''), mention_start, mention_end
+		FROM entity_aspects
+		WHERE object_id = $1 AND post_id = ANY($2)
+		ORDER BY post_id, mention_start NULLS LAST, id
+	`, id, postIDs)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var a MentionAspect
+		if err := rows.Scan(&postID, &a.Aspect, &a.Sentiment, &a.MentionText, &a.MentionStart, &a.MentionEnd); err != nil {
+			continue
+		}
+		if i, ok := index[postID]; ok {
+			a.MentionStartUTF16, a.MentionEndUTF16 = utf16Span(mentions[i].content, a.MentionStart, a.MentionEnd)
+			mentions[i].Aspects = append(mentions[i].Aspects, a)
+		}
+	}
+}
+
 func (s *Server) getEntityLinkList(ctx context.Context, id string) []EntityLinkItem {
 	var links []EntityLinkItem
 
@@ -792,7 +939,17 @@ func (s *Server) getEntityObservations(c *gin.Context) {
 			positive_count,
 			negative_count,
 			neutral_count,
-			mixed_count
+			mixed_count,
+			comment_mention_count,
+			COALESCE(comment_avg_sentiment, 0),
+			comment_positive_count,
+			comment_negative_count,
+			comment_neutral_count,
+			comment_mixed_count,
+			weighted_sentiment::float8,
+			total_engagement,
+			estimated_reach,
+			share_of_voice::float8
 		FROM entity_observations
 		WHERE object_id = $1 AND period_type = $2
 		ORDER BY period_start DESC
@@ -807,7 +964,9 @@ func (s *Server) getEntityObservations(c *gin.Context) {
 	for rows.Next() {
 		var o ObservationItem
 		var periodStart time.Time
-		if err := rows.Scan(&periodStart, &o.PeriodType, &o.MentionCount, &o.AvgSentiment, &o.PositiveCount, &o.NegativeCount, &o.NeutralCount, &o.MixedCount); err != nil {
+		if err := rows.Scan(&periodStart, &o.PeriodType, &o.MentionCount, &o.AvgSentiment, &o.PositiveCount, &o.NegativeCount, &o.NeutralCount, &o.MixedCount,
+			&o.CommentMentionCount, &o.CommentAvgSentiment, &o.CommentPositiveCount, &o.CommentNegativeCount, &o.CommentNeutralCount, &o.CommentMixedCount,
+			&o.WeightedSentiment, &o.TotalEngagement, &o.EstimatedReach, &o.ShareOfVoice); err != nil {
 			continue
 		}
 		o.PeriodStart = periodStart.Format("2006-01-02")
@@ -854,7 +1013,7 @@ func (s *Server) getEntitySummary(c *gin.Context) {
 	period := c.DefaultQuery("period", "4w")
 
 	// 1. Check Redis cache
-	cacheKey := "entity_summary:" + id + ":" + period
+	cacheKey := service.EntitySummaryCacheKey(id, period)
 	if cached, err := s.redisClient.Get(ctx, cacheKey); err == nil {
 		var resp EntitySummaryResponse
 		if json.Unmarshal([]byte(cached), &resp) == nil {
@@ -956,11 +1115,7 @@ func (s *Server) getEntitySummary(c *gin.Context) {
 	})
 	var sampleNeg []string
 	for _, m := range negMentions {
-		text := m.Content
-		if len(text) > 150 {
-			text = text[:150] + "..."
-		}
-		sampleNeg = append(sampleNeg, text)
+		sampleNeg = append(sampleNeg, excerpt(m.Content, 150))
 	}
 
 	// 7. Period label
@@ -996,6 +1151,10 @@ func (s *Server) getEntitySummary(c *gin.Context) {
 	}
 
 	result, err := s.summarySvc.GenerateEntitySummary(ctx, summaryReq)
+	if errors.Is(err, service.ErrBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "daily LLM budget exceeded"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate summary"})
 		return
@@ -1040,10 +1199,18 @@ func (s *Server) getEntitySummary(c *gin.Context) {
 // ChatRequest Follow-up chat 請求
 type ChatRequest struct {
 	Question  string `json:"question" binding:"required"`
-	SessionID string `json:"session_id" binding:"required"`
+	SessionID string `json:"session_id"` // 空字串 = 開新 session；不存在的 UUID 會以該 ID 建立
+}
+
+// ChatTurnUsage 一輪對話與整個 session 的 token 用量
+type ChatTurnUsage struct {
+	Turn    entity.TokenUsage `json:"turn"`
+	Session entity.TokenUsage `json:"session"`
 }
 
 // chatWithEntity POST /api/entities/:id/chat — SSE streaming chat
+//
+// 事件順序：session → token（data: {"content":...}）→ citations → usage → [DONE]
 func (s *Server) chatWithEntity(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
@@ -1053,6 +1220,10 @@ func (s *Server) chatWithEntity(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.SessionID != "" && !isUUID(req.SessionID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id must be a UUID"})
+		return
+	}
 
 	// 1. Load entity basic info
 	var entityName, entityType string
@@ -1066,35 +1237,46 @@ func (s *Server) chatWithEntity(c *gin.Context) {
 		return
 	}
 
-	// 2. Load cached summary
-	period := c.DefaultQuery("period", "4w")
-	summaryKey := "entity_summary:" + id + ":" + period
+	// 2. Open (or create) the chat session；續談時沿用 session 建立時的期間
+	session, err := s.chatSvc.Open(ctx, chatUserID(c), req.SessionID, id, c.DefaultQuery("period", "4w"))
+	if errors.Is(err, service.ErrChatSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chat session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open chat session"})
+		return
+	}
+	session.ObjectName = entityName
+	period := session.Period
+	if period == "" {
+		period = "4w"
+	}
+
+	// 3. Load cached summary
+	summaryKey := service.EntitySummaryCacheKey(id, period)
 	summaryJSON := ""
 	if cached, err := s.redisClient.Get(ctx, summaryKey); err == nil {
 		summaryJSON = cached
 	}
 
-	// 3. Build context data (aspects + stats)
-	contextData := s.buildEntityContextData(ctx, id, period)
-
-	// 4. Load chat history from Redis
-	historyKey := "entity_chat:" + id + ":" + req.SessionID
-	var history []service.ChatMessage
-	if cached, err := s.redisClient.Get(ctx, historyKey); err == nil {
-		json.Unmarshal([]byte(cached), &history)
-	}
+	// 4. Build context data (aspects + stats + mentions + facts) with citation markers
+	contextData, sources := s.buildEntityContextData(ctx, id, period)
 
-	// 5. Call streaming LLM
+	// 5. Call streaming LLM with the session history
 	chatReq := &service.EntityChatRequest{
 		EntityName:  entityName,
 		EntityType:  entityType,
 		SummaryJSON: summaryJSON,
 		ContextData: contextData,
-		History:     history,
 		Question:    req.Question,
 	}
 
-	tokenCh, errCh := s.summarySvc.StreamEntityChat(ctx, chatReq)
+	tokenCh, errCh, meter, err := s.chatSvc.Stream(ctx, session, chatReq)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load chat history"})
+		return
+	}
 
 	// 6. Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
@@ -1102,41 +1284,51 @@ func (s *Server) chatWithEntity(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Accel-Buffering", "no")
 
-	// 7. Stream tokens
-	var fullResponse strings.Builder
 	flusher, _ := c.Writer.(http.Flusher)
+	c.SSEvent("session", session)
+	if flusher != nil {
+		flusher.Flush()
+	}
 
+	// 7. Stream tokens
+	var fullResponse strings.Builder
 	for {
 		select {
 		case token, ok := <-tokenCh:
 			if !ok {
 				// Channel closed, check for errors
+				var streamErr error
 				select {
-				case err := <-errCh:
-					if err != nil {
-						c.SSEvent("error", gin.H{"error": err.Error()})
+				case streamErr = <-errCh:
+					if streamErr != nil {
+						c.SSEvent("error", gin.H{"error": streamErr.Error()})
 						if flusher != nil {
 							flusher.Flush()
 						}
 					}
 				default:
 				}
+
+				// 8. Persist the turn (skip failed turns with no answer)
+				turn := &service.ChatTurn{
+					Question:  req.Question,
+					Answer:    fullResponse.String(),
+					Citations: sources.Extract(fullResponse.String()),
+					Usage:     meter.Usage(),
+				}
+				if streamErr == nil || turn.Answer != "" {
+					if err := s.chatSvc.SaveTurn(context.WithoutCancel(ctx), session, turn); err != nil {
+						log.Printf("[chat] save turn for session %s: %v", session.ID, err)
+					}
+				}
+
+				c.SSEvent("citations", turn.Citations)
+				c.SSEvent("usage", ChatTurnUsage{Turn: turn.Usage, Session: session.Usage})
 				// Send [DONE]
 				c.Writer.WriteString("data: [DONE]\n\n")
 				if flusher != nil {
 					flusher.Flush()
 				}
-
-				// 8. Save history to Redis (append user question + assistant answer, max 10 rounds)
-				history = append(history, service.ChatMessage{Role: "user", Content: req.Question})
-				history = append(history, service.ChatMessage{Role: "assistant", Content: fullResponse.String()})
-				// Keep last 10 rounds (20 messages)
-				if len(history) > 20 {
-					history = history[len(history)-20:]
-				}
-				if historyJSON, err := json.Marshal(history); err == nil {
-					s.redisClient.Set(ctx, historyKey, string(historyJSON), 1*time.Hour)
-				}
 				return
 			}
 			fullResponse.WriteString(token)
@@ -1152,10 +1344,14 @@ func (s *Server) chatWithEntity(c *gin.Context) {
 	}
 }
 
-// buildEntityContextData 組裝 entity 的上下文數據摘要
-func (s *Server) buildEntityContextData(ctx context.Context, id string, period string) string {
+// chatMentionLimit chat 上下文中列出的代表性提及數
+const chatMentionLimit = 8
+
+// buildEntityContextData 組裝 entity 的上下文數據摘要，並回傳可引用的來源（提及 / 面向 / 事實）
+func (s *Server) buildEntityContextData(ctx context.Context, id string, period string) (string, service.CitationSources) {
 	periodInterval := parsePeriodInterval(period)
 	var b strings.Builder
+	sources := make(service.CitationSources)
 
 	// Stats
 	statsQuery := `
@@ -1182,8 +1378,38 @@ func (s *Server) buildEntityContextData(ctx context.Context, id string, period s
 	if len(aspects) > 0 {
 		b.WriteString("【面向分析】\n")
 		for _, a := range aspects {
-			b.WriteString("- " + a.Aspect + " (total:" + strconv.Itoa(a.Total) + " +:" + strconv.Itoa(a.PositiveCount) + " -:" + strconv.Itoa(a.NegativeCount) + ")\n")
+			sources.Add(entity.CitationAspect, a.Aspect, a.Aspect)
+			b.WriteString("- " + service.CitationMarker(entity.CitationAspect, a.Aspect) + " " + a.Aspect + " (total:" + strconv.Itoa(a.Total) + " +:" + strconv.Itoa(a.PositiveCount) + " -:" + strconv.Itoa(a.NegativeCount) + ")\n")
+		}
+	}
+
+	// Representative mentions
+	mentionQuery := `
+		SELECT pem.post_id, COALESCE(pem.sentiment, ''), COALESCE(pem.mention_text, '')
+		FROM post_entity_mentions pem
+		WHERE pem.object_id = $1 AND COALESCE(pem.mention_text, '') <> ''`
+	mentionArgs := []any{id}
+	if periodInterval != "" {
+		mentionQuery += " AND pem.created_at >= NOW() - $2::interval"
+		mentionArgs = append(mentionArgs, periodInterval)
+	}
+	mentionQuery += " ORDER BY pem.created_at DESC LIMIT " + strconv.Itoa(chatMentionLimit)
+	if rows, err := s.db.Pool.Query(ctx, mentionQuery, mentionArgs...); err == nil {
+		first := true
+		for rows.Next() {
+			var postID, sentiment, text string
+			if err := rows.Scan(&postID, &sentiment, &text); err != nil {
+				continue
+			}
+			if first {
+				b.WriteString("【代表性提及】\n")
+				first = false
+			}
+			text = service.TruncateRunes(strings.Join(strings.Fields(text), " "), 120)
+			sources.Add(entity.CitationPost, postID, service.TruncateRunes(text, 60))
+			b.WriteString("- " + service.CitationMarker(entity.CitationPost, postID) + " (" + sentiment + ") " + text + "\n")
 		}
+		rows.Close()
 	}
 
 	// Recent facts
@@ -1191,7 +1417,9 @@ func (s *Server) buildEntityContextData(ctx context.Context, id string, period s
 	if len(facts) > 0 {
 		b.WriteString("【推理引擎 Insights/Alerts】\n")
 		for _, f := range facts {
-			b.WriteString("- [" + f.FactType + "/" + f.Severity + "] " + f.Title + ": " + f.Description + "\n")
+			factID := strconv.FormatInt(f.ID, 10)
+			sources.Add(entity.CitationFact, factID, f.Title)
+			b.WriteString("- " + service.CitationMarker(entity.CitationFact, factID) + " [" + f.FactType + "/" + f.Severity + "] " + f.Title + ": " + f.Description + "\n")
 		}
 	}
 
@@ -1204,7 +1432,7 @@ func (s *Server) buildEntityContextData(ctx context.Context, id string, period s
 		}
 	}
 
-	return b.String()
+	return b.String(), sources
 }
 
 // --- KOL Attribution ---
@@ -1255,29 +1483,21 @@ func (s *Server) getKOLAttribution(c *gin.Context) {
 
 	limitArgIdx := len(args) + 1
 
-	// Strategy: KOL = post author (person entity matched via canonical_name/alias)
-	// For each product/brand mention, find the post's author and match to a person entity.
+	// Strategy: KOL = post author (person entity linked in the author registry)
+	// For each product/brand mention, find the post's author and its linked person entity.
 	// This captures "who talks about this product" rather than "who is mentioned alongside".
 	query := `
 		WITH author_mentions AS (
 			SELECT
-				kol.id AS kol_id,
+				a.object_id AS kol_id,
 				COUNT(DISTINCT pem.post_id) AS co_mention_count,
 				AVG(pem.sentiment_score) AS avg_sentiment
 			FROM post_entity_mentions pem
 			JOIN posts p ON pem.post_id = p.post_id
-			JOIN objects kol ON kol.id IN (
-				SELECT o2.id FROM objects o2
-				JOIN object_types ot2 ON o2.type_id = ot2.id AND ot2.name = 'person'
-				WHERE o2.canonical_name = p.author_username
-				UNION
-				SELECT oa.object_id FROM object_aliases oa
-				JOIN objects o3 ON oa.object_id = o3.id
-				JOIN object_types ot3 ON o3.type_id = ot3.id AND ot3.name = 'person'
-				WHERE oa.alias = p.author_username
-			)
-			WHERE pem.object_id = $1` + dateFilter + `
-			GROUP BY kol.id
+			JOIN authors a ON a.platform = p.platform
+				AND a.platform_user_id = COALESCE(NULLIF(p.author_id, ''), p.author_username)
+			WHERE pem.object_id = $1 AND a.object_id IS NOT NULL` + dateFilter + `
+			GROUP BY a.object_id
 		),
 		total AS (
 			SELECT COALESCE(SUM(co_mention_count), 0) AS total_mentions FROM author_mentions
@@ -1308,7 +1528,8 @@ func (s *Server) getKOLAttribution(c *gin.Context) {
 
 	rows, err := s.db.Pool.Query(ctx, query, args...)
 	if err != nil {
-		respondOne(c, []KOLAttributionItem{})
+		log.Printf("[kol] attribution for %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query KOL attribution"})
 		return
 	}
 	defer rows.Close()
@@ -1337,17 +1558,16 @@ func (s *Server) getKOLAttribution(c *gin.Context) {
 
 		sparkQuery := `
 			SELECT
-				kol.id AS kol_id,
+				a.object_id AS kol_id,
 				date_trunc('week', p.created_at) AS week,
 				COUNT(DISTINCT pem.post_id) AS cnt
 			FROM post_entity_mentions pem
 			JOIN posts p ON pem.post_id = p.post_id
-			JOIN objects kol ON kol.id = ANY($2)
-				AND (kol.canonical_name = p.author_username
-				  OR EXISTS (SELECT 1 FROM object_aliases oa WHERE oa.object_id = kol.id AND oa.alias = p.author_username))
-			WHERE pem.object_id = $1` + sparkDateFilter + `
-			GROUP BY kol.id, date_trunc('week', p.created_at)
-			ORDER BY kol.id, week
+			JOIN authors a ON a.platform = p.platform
+				AND a.platform_user_id = COALESCE(NULLIF(p.author_id, ''), p.author_username)
+			WHERE pem.object_id = $1 AND a.object_id::text = ANY($2)` + sparkDateFilter + `
+			GROUP BY a.object_id, date_trunc('week', p.created_at)
+			ORDER BY a.object_id, week
 		`
 
 		sparkRows, err := s.db.Pool.Query(ctx, sparkQuery, sparkArgs...)
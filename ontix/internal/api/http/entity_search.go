@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/jackc/pgx/v5"
+)
+
+// searchEntities GET /api/entities/search?q=平價早午餐&type=restaurant&limit=20
+// 以 Entity embedding（提及片段 + aspect 評價 + 共同提及）做語意搜尋
+func (s *Server) searchEntities(c *gin.Context) {
+	ctx := c.Request.Context()
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
+		return
+	}
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 50)
+
+	matches, err := s.entityEmbedder.Search(ctx, q, entity.ObjectType(c.Query("type")), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "entity search failed"})
+		return
+	}
+	if matches == nil {
+		matches = []*entity.EntityMatch{}
+	}
+	respondList(c, matches, 0, limit, len(matches))
+}
+
+// getSimilarEntities GET /api/entities/:id/similar?type=&any_type=false&limit=10
+// 預設只找同類型的 Entity；type 指定其他類型，any_type=true 不限類型
+func (s *Server) getSimilarEntities(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	limit := clamp(parseIntDefault(c.Query("limit"), 10), 1, 50)
+
+	var sourceType string
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT ot.name FROM objects o JOIN object_types ot ON ot.id = o.type_id
+		WHERE o.id::text = $1`, id).Scan(&sourceType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "entity not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load entity"})
+		return
+	}
+
+	objectType := entity.ObjectType(sourceType)
+	if t := c.Query("type"); t != "" {
+		objectType = entity.ObjectType(t)
+	} else if c.Query("any_type") == "true" {
+		objectType = ""
+	}
+
+	matches, err := s.entityEmbedder.Similar(ctx, id, objectType, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrEntityNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "entity not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to find similar entities"})
+		return
+	}
+	if matches == nil {
+		matches = []*entity.EntityMatch{}
+	}
+	respondList(c, matches, 0, limit, len(matches))
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// exportData GET /api/export?dataset=posts&format=csv|jsonl|parquet&period=4w&from=&to=&entity=&topic=&platform=&language=
+//
+// 以串流方式輸出完整資料集（不分頁）；entity / topic / platform / language 可用逗號分隔多個值
+func (s *Server) exportData(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := &service.ExportRequest{
+		Dataset: entity.ExportDataset(c.DefaultQuery("dataset", string(entity.ExportPosts))),
+		Format:  entity.ExportFormat(c.DefaultQuery("format", string(entity.ExportCSV))),
+		Period:  c.Query("period"),
+		Filter: entity.ExportFilter{
+			From:       parseTimeParam(c.Query("from"), false),
+			To:         parseTimeParam(c.Query("to"), true),
+			EntityIDs:  parseCSV(c.Query("entity")),
+			TopicCodes: parseCSV(c.Query("topic")),
+			Platforms:  parseCSV(c.Query("platform")),
+			Languages:  parseLanguages(c.Query("language")),
+		},
+	}
+	for _, id := range req.Filter.EntityIDs {
+		if !isUUID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "entity must be UUIDs"})
+			return
+		}
+	}
+	// 串流開始後無法再回傳錯誤狀態碼，先驗證參數
+	if err := s.exportSvc.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := service.ExportFilename(req.Dataset, req.Format, time.Now())
+	c.Header("Content-Type", service.ExportContentType(req.Format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	start := time.Now()
+	n, err := s.exportSvc.Export(ctx, c.Writer, req)
+	if err != nil && !errors.Is(err, ctx.Err()) {
+		// 已送出部分內容，無法再改狀態碼，只記錄錯誤
+		log.Printf("[export] %s.%s failed after %d rows: %v", req.Dataset, req.Format, n, err)
+		return
+	}
+	log.Printf("[export] %s.%s: %d rows in %s", req.Dataset, req.Format, n, time.Since(start).Round(time.Millisecond))
+}
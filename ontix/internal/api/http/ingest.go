@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/infra/ingest"
+)
+
+// maxBulkBodyBytes POST /api/posts/bulk 請求大小上限
+const maxBulkBodyBytes = 256 << 20
+
+// ingestBulk POST /api/posts/bulk?format=jsonl|csv|json&platform=instagram&dry_run=true
+//
+// 請求本文為原始檔案內容（JSONL / CSV / 平台 API 匯出的 JSON），未指定 format 時依 Content-Type 判斷；
+// 逐筆驗證後以 pipeline 批次寫入佇列，回傳接受 / 退件數與退件原因
+func (s *Server) ingestBulk(c *gin.Context) {
+	format := ingest.Format(strings.ToLower(c.Query("format")))
+	if format == "" {
+		format = ingest.DetectFormat(c.ContentType())
+	}
+	if format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format is required (jsonl, csv or json)"})
+		return
+	}
+
+	platform := c.Query("platform")
+	if _, ok := ingest.Lookup(platform); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "unknown platform",
+			"platforms": ingest.Names(),
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkBodyBytes)
+	res, err := s.ingester.Ingest(c.Request.Context(), body, ingest.Options{
+		Format:    format,
+		Platform:  platform,
+		BatchSize: clamp(parseIntDefault(c.Query("batch_size"), ingest.DefaultBatchSize), 1, 5000),
+		DryRun:    c.Query("dry_run") == "true",
+	})
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large", "data": res})
+		case errors.Is(err, ingest.ErrPublish):
+			log.Printf("[ingest] bulk publish error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue", "data": res})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "data": res})
+		}
+		return
+	}
+
+	log.Printf("[ingest] bulk %s (%s): %d accepted, %d rejected", format, ingestPlatformLabel(platform), res.Accepted, res.Rejected)
+	respondOne(c, res)
+}
+
+func ingestPlatformLabel(platform string) string {
+	if platform == "" {
+		return "generic"
+	}
+	return platform
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// DeletePostRequest DELETE /api/posts/:id 請求（body 可省略）
+type DeletePostRequest struct {
+	Reason      string `json:"reason"`       // takedown / user_request / gdpr ...
+	RequestedBy string `json:"requested_by"` // 未提供時取 X-User-ID
+}
+
+// PostTombstoneResponse 刪除結果
+type PostTombstoneResponse struct {
+	PostID      string `json:"post_id"`
+	Platform    string `json:"platform,omitempty"`
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requested_by"`
+	DeletedAt   string `json:"deleted_at"`
+	Existed     bool   `json:"existed"` // false = 貼文尚未入庫（仍留下 tombstone，之後匯入會略過）
+}
+
+// MetricsSnapshotResponse 互動數快照
+type MetricsSnapshotResponse struct {
+	Likes      int    `json:"likes"`
+	Comments   int    `json:"comments"`
+	Shares     int    `json:"shares"`
+	Views      int    `json:"views"`
+	CapturedAt string `json:"captured_at"`
+}
+
+// deletePost DELETE /api/posts/:id
+//
+// 平台下架合規：刪除貼文與所有衍生資料（mentions、aspects、tags、topics、embedding…）及其底下的留言，
+// 重算受影響的觀測並留下 tombstone，之後重新匯入同一篇貼文會被略過
+func (s *Server) deletePost(c *gin.Context) {
+	postID := c.Param("id")
+	if _, err := strconv.ParseInt(postID, 10, 64); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	var req DeletePostRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Reason == "" {
+		req.Reason = c.Query("reason")
+	}
+	if req.RequestedBy == "" {
+		req.RequestedBy = c.GetHeader("X-User-ID")
+	}
+
+	tombstone := &entity.PostTombstone{
+		PostID:      postID,
+		Reason:      req.Reason,
+		RequestedBy: req.RequestedBy,
+	}
+	existed, err := s.postRepo.Delete(c.Request.Context(), tombstone)
+	if err != nil {
+		log.Printf("[post] delete %s error: %v", postID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete post"})
+		return
+	}
+	log.Printf("[post] deleted %s (reason=%q, by=%q, existed=%v)", postID, tombstone.Reason, tombstone.RequestedBy, existed)
+
+	respondOne(c, PostTombstoneResponse{
+		PostID:      tombstone.PostID,
+		Platform:    string(tombstone.Platform),
+		Reason:      tombstone.Reason,
+		RequestedBy: tombstone.RequestedBy,
+		DeletedAt:   tombstone.DeletedAt.Format(time.RFC3339),
+		Existed:     existed,
+	})
+}
+
+// getPostMetrics GET /api/posts/:id/metrics?limit=100
+//
+// 貼文互動數快照（由新到舊；每次重新匯入且互動數有變動時記錄一筆）
+func (s *Server) getPostMetrics(c *gin.Context) {
+	limit := clamp(parseIntDefault(c.Query("limit"), 100), 1, 1000)
+
+	snapshots, err := s.postRepo.ListMetricsHistory(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get metrics history"})
+		return
+	}
+	if len(snapshots) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+		return
+	}
+
+	resp := make([]MetricsSnapshotResponse, len(snapshots))
+	for i, snap := range snapshots {
+		resp[i] = MetricsSnapshotResponse{
+			Likes:      snap.Metrics.Likes,
+			Comments:   snap.Metrics.Comments,
+			Shares:     snap.Metrics.Shares,
+			Views:      snap.Metrics.Views,
+			CapturedAt: snap.CapturedAt.Format(time.RFC3339),
+		}
+	}
+	respondOne(c, resp)
+}
@@ -2,8 +2,11 @@ package http
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
 )
 
 // EntityListParams Entity 列表查詢參數
@@ -26,6 +29,7 @@ type EntityListParams struct {
 type MentionListParams struct {
 	Aspect    string
 	Sentiment string
+	Kind      string // post / comment（含回覆）；空字串 = 全部
 	Sort      string
 	Order     string
 	Offset    int
@@ -36,6 +40,7 @@ type MentionListParams struct {
 type AspectListParams struct {
 	Q         string
 	Sentiment string
+	Level     int // 在面向樹的第幾層彙總（1 = 頂層，0 = 不 roll-up）
 	Sort      string
 	Order     string
 	Offset    int
@@ -95,6 +100,16 @@ func parsePeriodInterval(period string) string {
 	}
 }
 
+// parsePeriodSince maps period shorthand to a start time (nil for all-time)
+func parsePeriodSince(period string) *time.Time {
+	days := map[string]int{"1w": 7, "4w": 28, "12w": 84}[period]
+	if days == 0 {
+		return nil
+	}
+	t := time.Now().AddDate(0, 0, -days)
+	return &t
+}
+
 func parseEntityListParams(c *gin.Context) EntityListParams {
 	p := EntityListParams{
 		Q:       c.Query("q"),
@@ -135,6 +150,7 @@ func parseMentionListParams(c *gin.Context) MentionListParams {
 	return MentionListParams{
 		Aspect:    c.Query("aspect"),
 		Sentiment: c.Query("sentiment"),
+		Kind:      c.Query("kind"),
 		Sort:      c.Query("sort"),
 		Order:     c.Query("order"),
 		Offset:    parseIntDefault(c.Query("offset"), 0),
@@ -146,6 +162,7 @@ func parseAspectListParams(c *gin.Context) AspectListParams {
 	return AspectListParams{
 		Q:         c.Query("q"),
 		Sentiment: c.Query("sentiment"),
+		Level:     clamp(parseIntDefault(c.Query("level"), 0), 0, 10),
 		Sort:      c.Query("sort"),
 		Order:     c.Query("order"),
 		Offset:    parseIntDefault(c.Query("offset"), 0),
@@ -174,8 +191,116 @@ func parseInboxListParams(c *gin.Context) InboxListParams {
 	}
 }
 
+// ReviewListParams 審核佇列查詢參數
+type ReviewListParams struct {
+	Status string
+	Reason string
+	Offset int
+	Limit  int
+}
+
+func parseReviewListParams(c *gin.Context) ReviewListParams {
+	status := c.Query("status")
+	if status == "" {
+		status = "pending"
+	}
+	return ReviewListParams{
+		Status: status,
+		Reason: c.Query("reason"),
+		Offset: parseIntDefault(c.Query("offset"), 0),
+		Limit:  clamp(parseIntDefault(c.Query("limit"), 20), 1, 100),
+	}
+}
+
+// SearchParams 混合搜尋查詢參數
+type SearchParams struct {
+	Q             string
+	Mode          string
+	Platforms     []string
+	From          *time.Time
+	To            *time.Time
+	Topics        []string
+	Sentiments    []string
+	Intents       []string
+	Entities      []string
+	Authors       []string
+	Languages     []string
+	Translate     bool // 非目標語言的結果附上翻譯片段
+	Cursor        string
+	Limit         int
+	MinSimilarity float64
+}
+
+func parseSearchParams(c *gin.Context) SearchParams {
+	p := SearchParams{
+		Q:          strings.TrimSpace(c.Query("q")),
+		Mode:       c.DefaultQuery("mode", "hybrid"),
+		Platforms:  parseCSV(c.Query("platform")),
+		Topics:     parseCSV(c.Query("topic")),
+		Sentiments: parseCSV(c.Query("sentiment")),
+		Intents:    parseCSV(c.Query("intent")),
+		Entities:   parseCSV(c.Query("entity")),
+		Authors:    parseCSV(c.Query("author")),
+		Languages:  parseLanguages(c.Query("language")),
+		Translate:  c.Query("translate") == "true",
+		Cursor:     c.Query("cursor"),
+		Limit:      clamp(parseIntDefault(c.Query("limit"), 20), 1, 100),
+		From:       parseTimeParam(c.Query("from"), false),
+		To:         parseTimeParam(c.Query("to"), true),
+	}
+	if v := c.Query("min_similarity"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f < 1 {
+			p.MinSimilarity = f
+		}
+	}
+	return p
+}
+
 // --- Helpers ---
 
+// parseCSV 解析逗號分隔的多值參數（忽略空白項）
+func parseCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseLanguages 解析逗號分隔的語言代碼（zh-TW 等地區寫法正規化為 zh；無法辨識的值原樣保留）
+func parseLanguages(s string) []string {
+	values := parseCSV(s)
+	for i, v := range values {
+		if lang, ok := entity.ParseLanguage(v); ok {
+			values[i] = string(lang)
+		}
+	}
+	return values
+}
+
+// parseTimeParam 解析 RFC3339 或 YYYY-MM-DD；endOfDay 為 true 時日期格式含當日（回傳隔日 00:00）
+func parseTimeParam(s string, endOfDay bool) *time.Time {
+	if s == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t
+}
+
 func parseIntDefault(s string, defaultVal int) int {
 	if s == "" {
 		return defaultVal
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// ReportRequest 建立 / 更新報表定義請求
+type ReportRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	EntityIDs  []string `json:"entity_ids"`
+	ClassIDs   []int    `json:"class_ids"`
+	Period     string   `json:"period"`   // 1w / 4w / 12w（預設 1w）
+	Sections   []string `json:"sections"` // 空 = 全部章節
+	Formats    []string `json:"formats"`  // html / pdf（預設兩者）
+	Schedule   string   `json:"schedule"` // cron，例：0 9 * * 1（每週一 09:00）
+	Timezone   string   `json:"timezone"`
+	Recipients []string `json:"recipients"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// ReportDetail 報表定義與最近的產出紀錄
+type ReportDetail struct {
+	*entity.ReportDefinition
+	RecentRuns []*entity.ReportRun `json:"recent_runs"`
+}
+
+func (r *ReportRequest) toDefinition(def *entity.ReportDefinition) {
+	def.Name = r.Name
+	def.EntityIDs = r.EntityIDs
+	def.ClassIDs = r.ClassIDs
+	def.Period = r.Period
+	def.Schedule = r.Schedule
+	def.Timezone = r.Timezone
+	def.Recipients = r.Recipients
+	def.Sections = nil
+	for _, s := range r.Sections {
+		def.Sections = append(def.Sections, entity.ReportSection(s))
+	}
+	def.Formats = nil
+	for _, f := range r.Formats {
+		def.Formats = append(def.Formats, entity.ReportFormat(f))
+	}
+	if r.IsActive != nil {
+		def.IsActive = *r.IsActive
+	}
+}
+
+// listReports GET /api/reports?offset=0&limit=20
+func (s *Server) listReports(c *gin.Context) {
+	ctx := c.Request.Context()
+	offset := max(parseIntDefault(c.Query("offset"), 0), 0)
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+
+	defs, total, err := s.reportSvc.List(ctx, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list reports"})
+		return
+	}
+	if defs == nil {
+		defs = []*entity.ReportDefinition{}
+	}
+	respondList(c, defs, offset, limit, total)
+}
+
+// createReport POST /api/reports
+func (s *Server) createReport(c *gin.Context) {
+	var req ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, id := range req.EntityIDs {
+		if !isUUID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "entity_ids must be UUIDs"})
+			return
+		}
+	}
+
+	def := &entity.ReportDefinition{IsActive: true}
+	req.toDefinition(def)
+	if err := s.reportSvc.Create(c.Request.Context(), def); err != nil {
+		s.respondReportError(c, err, "failed to create report")
+		return
+	}
+	c.JSON(http.StatusCreated, ApiResponse{Data: def})
+}
+
+// getReport GET /api/reports/:id
+func (s *Server) getReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	def, ok := s.loadReport(c)
+	if !ok {
+		return
+	}
+
+	runs, _, err := s.reportSvc.Runs(ctx, def.ID, 0, 10)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load report runs"})
+		return
+	}
+	if runs == nil {
+		runs = []*entity.ReportRun{}
+	}
+	respondOne(c, ReportDetail{ReportDefinition: def, RecentRuns: runs})
+}
+
+// updateReport PUT /api/reports/:id
+func (s *Server) updateReport(c *gin.Context) {
+	def, ok := s.loadReport(c)
+	if !ok {
+		return
+	}
+
+	var req ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, id := range req.EntityIDs {
+		if !isUUID(id) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "entity_ids must be UUIDs"})
+			return
+		}
+	}
+
+	req.toDefinition(def)
+	if err := s.reportSvc.Update(c.Request.Context(), def); err != nil {
+		s.respondReportError(c, err, "failed to update report")
+		return
+	}
+	respondOne(c, def)
+}
+
+// deleteReport DELETE /api/reports/:id
+func (s *Server) deleteReport(c *gin.Context) {
+	def, ok := s.loadReport(c)
+	if !ok {
+		return
+	}
+	if err := s.reportSvc.Delete(c.Request.Context(), def.ID); err != nil {
+		s.respondReportError(c, err, "failed to delete report")
+		return
+	}
+	respondOne(c, gin.H{"status": "ok"})
+}
+
+// runReport POST /api/reports/:id/run — 立即產出（同步，完成後回傳產出紀錄）
+func (s *Server) runReport(c *gin.Context) {
+	def, ok := s.loadReport(c)
+	if !ok {
+		return
+	}
+	run, err := s.reportSvc.Run(c.Request.Context(), def, service.ReportTriggerManual)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run report"})
+		return
+	}
+	respondOne(c, run)
+}
+
+// listReportRuns GET /api/reports/:id/runs?offset=0&limit=20
+func (s *Server) listReportRuns(c *gin.Context) {
+	def, ok := s.loadReport(c)
+	if !ok {
+		return
+	}
+	offset := max(parseIntDefault(c.Query("offset"), 0), 0)
+	limit := clamp(parseIntDefault(c.Query("limit"), 20), 1, 100)
+
+	runs, total, err := s.reportSvc.Runs(c.Request.Context(), def.ID, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list report runs"})
+		return
+	}
+	if runs == nil {
+		runs = []*entity.ReportRun{}
+	}
+	respondList(c, runs, offset, limit, total)
+}
+
+// downloadReportRun GET /api/reports/:id/runs/:run_id/download?format=html|pdf
+func (s *Server) downloadReportRun(c *gin.Context) {
+	defID, err1 := strconv.ParseInt(c.Param("id"), 10, 64)
+	runID, err2 := strconv.ParseInt(c.Param("run_id"), 10, 64)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	run, err := s.reportSvc.FindRun(c.Request.Context(), defID, runID)
+	if errors.Is(err, service.ErrReportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "report run not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load report run"})
+		return
+	}
+
+	filename := fmt.Sprintf("report-%d-%s", defID, run.PeriodEnd.Format("20060102"))
+	switch c.DefaultQuery("format", "html") {
+	case "pdf":
+		if len(run.PDF) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no PDF for this run"})
+			return
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", run.PDF)
+	case "html":
+		if run.HTML == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no HTML for this run"})
+			return
+		}
+		if c.Query("download") == "true" {
+			c.Header("Content-Disposition", `attachment; filename="`+filename+`.html"`)
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(run.HTML))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html or pdf"})
+	}
+}
+
+// loadReport 取得 :id 報表定義，失敗時已寫入錯誤回應
+func (s *Server) loadReport(c *gin.Context) (*entity.ReportDefinition, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	def, err := s.reportSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		s.respondReportError(c, err, "failed to load report")
+		return nil, false
+	}
+	return def, true
+}
+
+func (s *Server) respondReportError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+	case errors.Is(err, service.ErrInvalidReport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
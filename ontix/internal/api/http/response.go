@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"unicode/utf16"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,3 +38,57 @@ func respondList(c *gin.Context, data any, offset, limit, total int) {
 func respondOne(c *gin.Context, data any) {
 	c.JSON(http.StatusOK, ApiResponse{Data: data})
 }
+
+// excerpt 依字元截斷預覽文字（不切斷 UTF-8），超過 n 字時加上 "..."
+func excerpt(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "..."
+}
+
+// excerptAround 取包含 [start, end) 的 n 字片段（提及位置置中），回傳片段與其在原文的起始字元位置
+// 沒有位置或原文不超過 n 字時與 excerpt 相同（起始位置 0）
+func excerptAround(s string, start, end *int, n int) (string, int) {
+	r := []rune(s)
+	if len(r) <= n || start == nil || end == nil || *start < 0 || *end > len(r) || *start >= *end {
+		return excerpt(s, n), 0
+	}
+	from := *start - (n-(*end-*start))/2
+	if from > len(r)-n {
+		from = len(r) - n
+	}
+	if from < 0 {
+		from = 0
+	}
+	to := from + n
+	if to < *end {
+		to = *end // 提及本身超過 n 字時完整保留
+	}
+	if to >= len(r) {
+		return string(r[from:]), from
+	}
+	return string(r[from:to]) + "...", from
+}
+
+// utf16Index 前 i 個字元（code point）的 UTF-16 code unit 數，即 JavaScript 字串索引
+func utf16Index(r []rune, i int) int {
+	if i > len(r) {
+		i = len(r)
+	}
+	n := 0
+	for _, c := range r[:i] {
+		n += utf16.RuneLen(c)
+	}
+	return n
+}
+
+// utf16Span 將字元位置 [start, end) 換算為 UTF-16 位置（沒有位置或超出原文時為 nil）
+func utf16Span(r []rune, start, end *int) (*int, *int) {
+	if start == nil || end == nil || *start < 0 || *end > len(r) || *start >= *end {
+		return nil, nil
+	}
+	s, e := utf16Index(r, *start), utf16Index(r, *end)
+	return &s, &e
+}
@@ -0,0 +1,363 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// --- Response Types ---
+
+// ReviewItemResponse 審核項目
+type ReviewItemResponse struct {
+	ID         int64          `json:"id"`
+	PostID     string         `json:"post_id"`
+	Content    string         `json:"content"`
+	Reason     string         `json:"reason"`
+	Detail     map[string]any `json:"detail,omitempty"`
+	Priority   float64        `json:"priority"`
+	Status     string         `json:"status"`
+	Reviewer   string         `json:"reviewer,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+	ResolvedAt *string        `json:"resolved_at,omitempty"`
+}
+
+// ReviewDetailResponse 審核項目 + 貼文目前的標註狀態
+type ReviewDetailResponse struct {
+	ReviewItemResponse
+	Topics    []ReviewTopicItem   `json:"topics"`
+	Sentiment *ReviewSentiment    `json:"sentiment,omitempty"`
+	Tags      []string            `json:"tags"`
+	Mentions  []ReviewMentionItem `json:"mentions"`
+}
+
+// ReviewTopicItem 貼文目前的主題
+type ReviewTopicItem struct {
+	TopicID     int     `json:"topic_id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Similarity  float64 `json:"similarity"`
+	Probability float64 `json:"probability,omitempty"`
+	Confidence  string  `json:"confidence"`
+	IsAmbiguous bool    `json:"is_ambiguous"`
+}
+
+// ReviewSentiment 貼文目前的整體情感
+type ReviewSentiment struct {
+	Label  string  `json:"label"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason,omitempty"`
+	Source string  `json:"source"`
+}
+
+// ReviewMentionItem 貼文目前的 Entity 提及
+type ReviewMentionItem struct {
+	ObjectID       string  `json:"object_id"`
+	CanonicalName  string  `json:"canonical_name"`
+	Type           string  `json:"type"`
+	Sentiment      string  `json:"sentiment"`
+	SentimentScore float64 `json:"sentiment_score"`
+	MentionText    string  `json:"mention_text"`
+	Source         string  `json:"source"`
+}
+
+// resolveReviewRequest POST /api/review/:id/resolve 請求
+// 欄位省略表示不修改；主題相關的審核項目省略 topics 表示確認現有主題
+type resolveReviewRequest struct {
+	Reviewer  string                      `json:"reviewer" binding:"required"`
+	Topics    []string                    `json:"topics"` // 主題代碼（完整集合）
+	Tags      []string                    `json:"tags"`
+	Sentiment *entity.SentimentCorrection `json:"sentiment"`
+	Mentions  []entity.MentionCorrection  `json:"mentions"`
+}
+
+var validSentimentLabels = map[string]bool{
+	"positive": true, "negative": true, "neutral": true, "mixed": true,
+}
+
+// --- Handlers ---
+
+// listReviewItems GET /api/review?status=pending&reason=ambiguous_topic
+func (s *Server) listReviewItems(c *gin.Context) {
+	p := parseReviewListParams(c)
+
+	items, total, err := s.reviewRepo.List(c.Request.Context(), entity.ReviewStatus(p.Status), entity.ReviewReason(p.Reason), p.Offset, p.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list review items"})
+		return
+	}
+
+	resp := make([]ReviewItemResponse, len(items))
+	for i, item := range items {
+		resp[i] = toReviewItemResponse(item)
+	}
+	respondList(c, resp, p.Offset, p.Limit, total)
+}
+
+// getReviewStats GET /api/review/stats
+func (s *Server) getReviewStats(c *gin.Context) {
+	counts, err := s.reviewRepo.CountByReason(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count review items"})
+		return
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	respondOne(c, gin.H{"pending": total, "by_reason": counts})
+}
+
+// getReviewItem GET /api/review/:id
+func (s *Server) getReviewItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	item, err := s.reviewRepo.FindByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get review item"})
+		return
+	}
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review item not found"})
+		return
+	}
+
+	respondOne(c, ReviewDetailResponse{
+		ReviewItemResponse: toReviewItemResponse(item),
+		Topics:             s.getReviewTopics(ctx, item.PostID),
+		Sentiment:          s.getReviewSentiment(ctx, item.PostID),
+		Tags:               s.getReviewTags(ctx, item.PostID),
+		Mentions:           s.getReviewMentions(ctx, item.PostID),
+	})
+}
+
+// resolveReviewItem POST /api/review/:id/resolve
+func (s *Server) resolveReviewItem(c *gin.Context) {
+	ctx := c.Request.Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req resolveReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := s.reviewRepo.FindByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get review item"})
+		return
+	}
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review item not found"})
+		return
+	}
+
+	correction := &entity.ReviewCorrection{
+		Reviewer: req.Reviewer,
+		Tags:     req.Tags,
+		Mentions: req.Mentions,
+	}
+
+	if req.Topics != nil {
+		correction.Topics = make([]int, 0, len(req.Topics))
+		for _, code := range req.Topics {
+			topic, err := s.topicRepo.FindByCode(ctx, code)
+			if err != nil || topic == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown topic: " + code})
+				return
+			}
+			correction.Topics = append(correction.Topics, topic.ID)
+		}
+	}
+
+	if req.Sentiment != nil {
+		if !validSentimentLabels[req.Sentiment.Label] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sentiment.label must be positive/negative/neutral/mixed"})
+			return
+		}
+		if req.Sentiment.Score < 0 || req.Sentiment.Score > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sentiment.score must be between 0 and 1"})
+			return
+		}
+		correction.Sentiment = req.Sentiment
+	}
+
+	for _, m := range req.Mentions {
+		if m.ObjectID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mentions[].object_id is required"})
+			return
+		}
+		if !m.Remove && m.Sentiment != "" && !validSentimentLabels[m.Sentiment] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mentions[].sentiment must be positive/negative/neutral/mixed"})
+			return
+		}
+	}
+
+	if err := s.reviewRepo.Resolve(ctx, id, correction); err != nil {
+		respondReviewError(c, err, "failed to resolve review item")
+		return
+	}
+
+	respondOne(c, gin.H{"status": "resolved", "id": id})
+}
+
+// skipReviewItem POST /api/review/:id/skip
+func (s *Server) skipReviewItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		Reviewer string `json:"reviewer" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.reviewRepo.Skip(c.Request.Context(), id, req.Reviewer); err != nil {
+		respondReviewError(c, err, "failed to skip review item")
+		return
+	}
+
+	respondOne(c, gin.H{"status": "skipped", "id": id})
+}
+
+// respondReviewError 審核項目不存在 → 404，已審核 / 已略過 → 409，其餘 → 500
+func respondReviewError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, repository.ErrReviewItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "review item not found"})
+	case errors.Is(err, repository.ErrReviewItemClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "review item already resolved or skipped"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
+
+// --- Query Methods ---
+
+func toReviewItemResponse(item *entity.ReviewItem) ReviewItemResponse {
+	resp := ReviewItemResponse{
+		ID:        item.ID,
+		PostID:    item.PostID,
+		Content:   item.Content,
+		Reason:    string(item.Reason),
+		Detail:    item.Detail,
+		Priority:  item.Priority,
+		Status:    string(item.Status),
+		Reviewer:  item.Reviewer,
+		CreatedAt: item.CreatedAt.Format(time.RFC3339),
+	}
+	if item.ResolvedAt != nil {
+		ts := item.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &ts
+	}
+	return resp
+}
+
+func (s *Server) getReviewTopics(ctx context.Context, postID string) []ReviewTopicItem {
+	topics := []ReviewTopicItem{}
+
+	numericID, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil {
+		return topics
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT t.id, t.code, t.name, pt.similarity, COALESCE(pt.probability, 0),
+		       COALESCE(pt.confidence, ''), COALESCE(pt.is_ambiguous, false)
+		FROM post_topics pt
+		JOIN topics t ON t.id = pt.topic_id
+		WHERE pt.post_id = $1
+		ORDER BY COALESCE(pt.probability, 0) DESC, pt.similarity DESC`, numericID)
+	if err != nil {
+		return topics
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t ReviewTopicItem
+		if err := rows.Scan(&t.TopicID, &t.Code, &t.Name, &t.Similarity, &t.Probability, &t.Confidence, &t.IsAmbiguous); err != nil {
+			continue
+		}
+		topics = append(topics, t)
+	}
+	return topics
+}
+
+func (s *Server) getReviewSentiment(ctx context.Context, postID string) *ReviewSentiment {
+	var label, reason, source string
+	var score float64
+	err := s.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(sentiment, ''), COALESCE(sentiment_score, 0), COALESCE(sentiment_reason, ''),
+		       COALESCE(sentiment_source, 'llm')
+		FROM posts WHERE post_id = $1`, postID).Scan(&label, &score, &reason, &source)
+	if err != nil || label == "" {
+		return nil
+	}
+	return &ReviewSentiment{Label: label, Score: score, Reason: reason, Source: source}
+}
+
+func (s *Server) getReviewTags(ctx context.Context, postID string) []string {
+	tags := []string{}
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT tag FROM post_soft_tags WHERE post_id = $1 ORDER BY confidence DESC`, postID)
+	if err != nil {
+		return tags
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (s *Server) getReviewMentions(ctx context.Context, postID string) []ReviewMentionItem {
+	mentions := []ReviewMentionItem{}
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT pem.object_id, o.canonical_name, ot.name,
+		       COALESCE(pem.sentiment, ''), COALESCE(pem.sentiment_score, 0),
+		       COALESCE(pem.mention_text, ''), pem.source
+		FROM post_entity_mentions pem
+		JOIN objects o ON o.id = pem.object_id
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE pem.post_id = $1
+		ORDER BY o.canonical_name`, postID)
+	if err != nil {
+		return mentions
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m ReviewMentionItem
+		if err := rows.Scan(&m.ObjectID, &m.CanonicalName, &m.Type, &m.Sentiment, &m.SentimentScore, &m.MentionText, &m.Source); err != nil {
+			continue
+		}
+		mentions = append(mentions, m)
+	}
+	return mentions
+}
@@ -1,32 +1,48 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/pgvector/pgvector-go"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
 )
 
 // SearchResult 搜尋結果
 type SearchResult struct {
-	PostID     string   `json:"post_id"`
-	Content    string   `json:"content"`
-	Similarity float64  `json:"similarity"`
-	Sentiment  string   `json:"sentiment,omitempty"`
-	Intent     string   `json:"intent,omitempty"`
-	SoftTags   []string `json:"soft_tags,omitempty"`
+	PostID       string    `json:"post_id"`
+	Content      string    `json:"content"`
+	Snippet      string    `json:"snippet"`
+	Translated   string    `json:"translated_snippet,omitempty"` // ?translate=true 時非目標語言結果的翻譯
+	Platform     string    `json:"platform"`
+	Language     string    `json:"language,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Score        float64   `json:"score"`
+	Similarity   float64   `json:"similarity"`
+	KeywordScore float64   `json:"keyword_score"`
+	Sentiment    string    `json:"sentiment,omitempty"`
+	Intent       string    `json:"intent,omitempty"`
+	SoftTags     []string  `json:"soft_tags,omitempty"`
 }
 
 // SearchResponse 搜尋回應
 type SearchResponse struct {
-	Query        string                `json:"query"`
-	Results      []SearchResult        `json:"results"`
-	Distribution SemanticDistribution  `json:"distribution"`
-	Neighbors    []SemanticNeighbor    `json:"neighbors"`
+	Query        string               `json:"query"`
+	Mode         string               `json:"mode"`
+	Results      []SearchResult       `json:"results"`
+	Total        int                  `json:"total"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+	Facets       *entity.SearchFacets `json:"facets"`
+	Distribution SemanticDistribution `json:"distribution"`
+	Neighbors    []SemanticNeighbor   `json:"neighbors"`
 }
 
-// SemanticDistribution 語意分佈
+// SemanticDistribution 語意分佈（完整命中集合）
 type SemanticDistribution struct {
 	Sentiments map[string]int `json:"sentiments"`
 	Intents    map[string]int `json:"intents"`
@@ -39,168 +55,154 @@ type TagCount struct {
 	Count int    `json:"count"`
 }
 
-// SemanticNeighbor 語意鄰居
+// SemanticNeighbor 語意鄰居（與查詢向量最接近的主題）
 type SemanticNeighbor struct {
 	Term       string  `json:"term"`
+	Code       string  `json:"code"`
 	Similarity float64 `json:"similarity"`
 }
 
-// search GET /api/search?q=xxx&limit=20
+// search GET /api/search?q=xxx&mode=hybrid&platform=&from=&to=&topic=&sentiment=&intent=&entity=&author=&language=&translate=&cursor=&limit=20
 func (s *Server) search(c *gin.Context) {
 	ctx := c.Request.Context()
-	query := c.Query("q")
-	if query == "" {
+	p := parseSearchParams(c)
+	if p.Q == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'q' is required"})
 		return
 	}
 
-	limit := 20
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
-			limit = parsed
-		}
-	}
-
-	// 1. 產生查詢向量
-	embedding, err := s.embedSvc.Embed(ctx, query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate embedding"})
+	mode := entity.SearchMode(p.Mode)
+	switch mode {
+	case entity.SearchModeHybrid, entity.SearchModeVector, entity.SearchModeKeyword:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be one of hybrid, vector, keyword"})
 		return
 	}
 
-	// 2. 向量搜尋 (包含 sentiment, intent, soft_tags)
-	dbQuery := `
-		SELECT
-			p.post_id,
-			p.content,
-			1 - (pe.embedding <=> $1::vector) as similarity,
-			COALESCE(p.sentiment, '') as sentiment,
-			COALESCE(p.intent, '') as intent,
-			COALESCE(
-				(SELECT array_agg(tag ORDER BY confidence DESC)
-				 FROM post_soft_tags pst WHERE pst.post_id = p.post_id LIMIT 5),
-				'{}'::text[]
-			) as soft_tags
-		FROM posts p
-		JOIN post_embeddings pe ON p.post_id = pe.post_id
-		ORDER BY pe.embedding <=> $1::vector
-		LIMIT $2`
-
-	rows, err := s.db.Pool.Query(ctx, dbQuery, pgvector.NewVector(embedding), limit)
+	results, err := s.searchSvc.Search(ctx, service.SearchRequest{
+		Query: p.Q,
+		Mode:  mode,
+		Filter: entity.SearchFilter{
+			Platforms:  p.Platforms,
+			From:       p.From,
+			To:         p.To,
+			TopicCodes: p.Topics,
+			Sentiments: p.Sentiments,
+			Intents:    p.Intents,
+			EntityIDs:  p.Entities,
+			Authors:    p.Authors,
+			Languages:  p.Languages,
+		},
+		Cursor:        p.Cursor,
+		Limit:         p.Limit,
+		MinSimilarity: p.MinSimilarity,
+	})
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
 		return
 	}
-	defer rows.Close()
-
-	var results []SearchResult
-	sentimentCounts := make(map[string]int)
-	intentCounts := make(map[string]int)
-	tagCounts := make(map[string]int)
-
-	for rows.Next() {
-		var r SearchResult
-		var softTags []string
-		if err := rows.Scan(&r.PostID, &r.Content, &r.Similarity, &r.Sentiment, &r.Intent, &softTags); err != nil {
-			continue
-		}
-		r.SoftTags = softTags
 
-		// 截斷內容
-		if len(r.Content) > 300 {
-			r.Content = r.Content[:300] + "..."
-		}
-
-		results = append(results, r)
+	response := SearchResponse{
+		Query:      p.Q,
+		Mode:       string(results.Mode),
+		Results:    make([]SearchResult, 0, len(results.Hits)),
+		Total:      results.Total,
+		NextCursor: results.NextCursor,
+		Facets:     results.Facets,
+		Distribution: SemanticDistribution{
+			Sentiments: facetMap(results.Facets.Sentiments),
+			Intents:    facetMap(results.Facets.Intents),
+			TopTags:    topTags(results.Facets.Tags, 10),
+		},
+		Neighbors: []SemanticNeighbor{},
+	}
 
-		// 統計分佈
-		if r.Sentiment != "" {
-			sentimentCounts[r.Sentiment]++
+	for _, h := range results.Hits {
+		r := SearchResult{
+			PostID:    h.PostID,
+			Content:   service.TruncateRunes(h.Content, 300),
+			Snippet:   h.Snippet,
+			Platform:  h.Platform,
+			Language:  h.Language,
+			Author:    h.AuthorUsername,
+			CreatedAt: h.CreatedAt,
+			Score:     h.Score,
+			Sentiment: h.Sentiment,
+			Intent:    h.Intent,
+			SoftTags:  h.SoftTags,
 		}
-		if r.Intent != "" {
-			intentCounts[r.Intent]++
+		if h.Similarity != nil {
+			r.Similarity = *h.Similarity
 		}
-		for _, tag := range softTags {
-			tagCounts[tag]++
+		if h.KeywordScore != nil {
+			r.KeywordScore = *h.KeywordScore
 		}
+		response.Results = append(response.Results, r)
 	}
 
-	// 3. 取得 Top Tags
-	topTags := getTopTags(tagCounts, 10)
-
-	// 4. 計算語意鄰居 (基於搜尋結果的高頻標籤)
-	neighbors := computeSemanticNeighbors(tagCounts, 5)
+	if p.Translate {
+		s.translateSearchResults(ctx, response.Results)
+	}
 
-	// 5. 回傳結果
-	response := SearchResponse{
-		Query:   query,
-		Results: results,
-		Distribution: SemanticDistribution{
-			Sentiments: sentimentCounts,
-			Intents:    intentCounts,
-			TopTags:    topTags,
-		},
-		Neighbors: neighbors,
+	for _, n := range results.Neighbors {
+		response.Neighbors = append(response.Neighbors, SemanticNeighbor{
+			Term:       n.Name,
+			Code:       n.Code,
+			Similarity: n.Similarity,
+		})
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// getTopTags 取得 Top N 標籤
-func getTopTags(counts map[string]int, limit int) []TagCount {
-	var tags []TagCount
-	for tag, count := range counts {
-		tags = append(tags, TagCount{Tag: tag, Count: count})
+// translateSearchResults 為非目標語言的結果附上翻譯片段（未開放翻譯或失敗時維持原樣，不影響搜尋）
+func (s *Server) translateSearchResults(ctx context.Context, results []SearchResult) {
+	if s.translator == nil || len(results) == 0 {
+		return
 	}
-
-	// 排序
-	for i := 0; i < len(tags)-1; i++ {
-		for j := i + 1; j < len(tags); j++ {
-			if tags[j].Count > tags[i].Count {
-				tags[i], tags[j] = tags[j], tags[i]
-			}
+	texts := make([]string, len(results))
+	for i, r := range results {
+		texts[i] = r.Snippet
+		if texts[i] == "" {
+			texts[i] = r.Content
 		}
 	}
-
-	if len(tags) > limit {
-		tags = tags[:limit]
+	for start := 0; start < len(texts); start += service.MaxTranslateTexts {
+		end := min(start+service.MaxTranslateTexts, len(texts))
+		translations, err := s.translator.Translate(ctx, texts[start:end], "")
+		if err != nil {
+			log.Printf("[search] translate snippets: %v", err)
+			return
+		}
+		for i, tr := range translations {
+			if !tr.Skipped {
+				results[start+i].Translated = tr.Translated
+			}
+		}
 	}
-	return tags
 }
 
-// computeSemanticNeighbors 計算語意鄰居
-func computeSemanticNeighbors(tagCounts map[string]int, limit int) []SemanticNeighbor {
-	var neighbors []SemanticNeighbor
-
-	// 基於標籤頻率計算相似度
-	totalTags := 0
-	for _, count := range tagCounts {
-		totalTags += count
-	}
-
-	if totalTags == 0 {
-		return neighbors
-	}
-
-	for tag, count := range tagCounts {
-		similarity := float64(count) / float64(totalTags)
-		neighbors = append(neighbors, SemanticNeighbor{
-			Term:       tag,
-			Similarity: similarity,
-		})
+// facetMap facet 轉為 value → count
+func facetMap(facets []entity.FacetCount) map[string]int {
+	m := make(map[string]int, len(facets))
+	for _, f := range facets {
+		m[f.Value] = f.Count
 	}
+	return m
+}
 
-	// 排序
-	for i := 0; i < len(neighbors)-1; i++ {
-		for j := i + 1; j < len(neighbors); j++ {
-			if neighbors[j].Similarity > neighbors[i].Similarity {
-				neighbors[i], neighbors[j] = neighbors[j], neighbors[i]
-			}
+// topTags 取得 Top N 標籤（facet 已依計數排序）
+func topTags(facets []entity.FacetCount, limit int) []TagCount {
+	tags := make([]TagCount, 0, limit)
+	for _, f := range facets {
+		if len(tags) >= limit {
+			break
 		}
+		tags = append(tags, TagCount{Tag: f.Value, Count: f.Count})
 	}
-
-	if len(neighbors) > limit {
-		neighbors = neighbors[:limit]
-	}
-	return neighbors
+	return tags
 }
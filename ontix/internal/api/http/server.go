@@ -7,23 +7,40 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ikala/ontix/internal/domain/repository"
 	"github.com/ikala/ontix/internal/domain/service"
+	"github.com/ikala/ontix/internal/infra/ingest"
 	"github.com/ikala/ontix/internal/infra/postgres"
 	"github.com/ikala/ontix/internal/infra/redis"
 )
 
 // Server is the HTTP server
 type Server struct {
-	stream       *redis.StreamRepo
-	db           *postgres.DB
-	redisClient  *redis.Client
-	embedSvc     service.EmbeddingService
-	summarySvc   service.EntitySummaryService
-	postRepo     repository.PostRepository
-	tagRepo      repository.TagRepository
-	topicRepo    repository.TopicRepository
-	analysisRepo repository.PostAnalysisRepository
-	factRepo     repository.DerivedFactRepository
-	engine       *gin.Engine
+	stream         *redis.StreamRepo
+	db             *postgres.DB
+	redisClient    *redis.Client
+	embedSvc       service.EmbeddingService
+	summarySvc     service.EntitySummaryService
+	postRepo       repository.PostRepository
+	tagRepo        repository.TagRepository
+	topicRepo      repository.TopicRepository
+	analysisRepo   repository.PostAnalysisRepository
+	factRepo       repository.DerivedFactRepository
+	reviewRepo     repository.ReviewRepository
+	usageRepo      repository.UsageRepository
+	usageTracker   *service.UsageTracker
+	searchSvc      *service.SearchService
+	entityEmbedder *service.EntityEmbedder
+	askSvc         *service.AskService
+	chatSvc        *service.ChatSessionService
+	reportSvc      *service.ReportService
+	exportSvc      *service.ExportService
+	ingester       *ingest.Ingester
+	ingestGuard    *redis.IngestGuard
+	dedupRepo      repository.DedupRepository
+	authorRepo     repository.AuthorRepository
+	benchmarkSvc   *service.BenchmarkService
+	aspectTaxonomy *service.AspectTaxonomy
+	translator     *service.Translator // nil = 未開放片段翻譯
+	engine         *gin.Engine
 }
 
 // NewServer creates a new HTTP server
@@ -38,6 +55,22 @@ func NewServer(
 	topicRepo repository.TopicRepository,
 	analysisRepo repository.PostAnalysisRepository,
 	factRepo repository.DerivedFactRepository,
+	reviewRepo repository.ReviewRepository,
+	usageRepo repository.UsageRepository,
+	usageTracker *service.UsageTracker,
+	searchSvc *service.SearchService,
+	entityEmbedder *service.EntityEmbedder,
+	askSvc *service.AskService,
+	chatSvc *service.ChatSessionService,
+	reportSvc *service.ReportService,
+	exportSvc *service.ExportService,
+	ingester *ingest.Ingester,
+	ingestGuard *redis.IngestGuard,
+	dedupRepo repository.DedupRepository,
+	authorRepo repository.AuthorRepository,
+	benchmarkSvc *service.BenchmarkService,
+	aspectTaxonomy *service.AspectTaxonomy,
+	translator *service.Translator,
 ) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
@@ -47,21 +80,38 @@ func NewServer(
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:5173", "http://localhost:3000"}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", "X-Tenant", "X-User-ID"}
 	engine.Use(cors.New(config))
+	engine.Use(tenantMiddleware)
 
 	s := &Server{
-		stream:       stream,
-		db:           db,
-		redisClient:  redisClient,
-		embedSvc:     embedSvc,
-		summarySvc:   summarySvc,
-		postRepo:     postRepo,
-		tagRepo:      tagRepo,
-		topicRepo:    topicRepo,
-		analysisRepo: analysisRepo,
-		factRepo:     factRepo,
-		engine:       engine,
+		stream:         stream,
+		db:             db,
+		redisClient:    redisClient,
+		embedSvc:       embedSvc,
+		summarySvc:     summarySvc,
+		postRepo:       postRepo,
+		tagRepo:        tagRepo,
+		topicRepo:      topicRepo,
+		analysisRepo:   analysisRepo,
+		factRepo:       factRepo,
+		reviewRepo:     reviewRepo,
+		usageRepo:      usageRepo,
+		usageTracker:   usageTracker,
+		searchSvc:      searchSvc,
+		entityEmbedder: entityEmbedder,
+		askSvc:         askSvc,
+		chatSvc:        chatSvc,
+		reportSvc:      reportSvc,
+		exportSvc:      exportSvc,
+		ingester:       ingester,
+		ingestGuard:    ingestGuard,
+		dedupRepo:      dedupRepo,
+		authorRepo:     authorRepo,
+		benchmarkSvc:   benchmarkSvc,
+		aspectTaxonomy: aspectTaxonomy,
+		translator:     translator,
+		engine:         engine,
 	}
 	s.setupRoutes()
 	return s
@@ -72,6 +122,9 @@ func (s *Server) setupRoutes() {
 	{
 		// 現有路由
 		api.POST("/posts", s.ingestPost)
+		api.POST("/posts/bulk", s.ingestBulk)
+		api.DELETE("/posts/:id", s.deletePost)
+		api.GET("/posts/:id/metrics", s.getPostMetrics)
 		api.GET("/health", s.health)
 		api.GET("/queue/len", s.queueLen)
 
@@ -81,15 +134,20 @@ func (s *Server) setupRoutes() {
 
 		// Entity (Ontology) 路由
 		api.GET("/entities", s.listEntities)
+		api.GET("/entities/search", s.searchEntities)
 		api.GET("/entities/:id", s.getEntity)
 		api.GET("/entities/:id/aspects", s.getEntityAspects)
 		api.GET("/entities/:id/mentions", s.getEntityMentions)
 		api.GET("/entities/:id/links", s.getEntityLinks)
 		api.GET("/entities/:id/observations", s.getEntityObservations)
 		api.GET("/entities/:id/summary", s.getEntitySummary)
+		api.GET("/entities/:id/similar", s.getSimilarEntities)
 		api.GET("/entity-types", s.getEntityTypes)
 		api.GET("/graph", s.getGraph)
 
+		// Ontology 問答（graph RAG）
+		api.POST("/ask", s.ask)
+
 		// Inbox (Derived Facts) 路由
 		api.GET("/inbox", s.listInboxFacts)
 		api.GET("/inbox/count", s.getInboxCount)
@@ -99,6 +157,60 @@ func (s *Server) setupRoutes() {
 		api.GET("/entities/:id/facts", s.getEntityFacts)
 		api.GET("/entities/:id/kol-attribution", s.getKOLAttribution)
 		api.POST("/entities/:id/chat", s.chatWithEntity)
+
+		// Chat Sessions（持久化對話紀錄）
+		api.GET("/chat/sessions", s.listChatSessions)
+		api.POST("/chat/sessions", s.createChatSession)
+		api.GET("/chat/sessions/:id", s.getChatSession)
+		api.PATCH("/chat/sessions/:id", s.renameChatSession)
+		api.DELETE("/chat/sessions/:id", s.deleteChatSession)
+
+		// Scheduled reports
+		api.GET("/reports", s.listReports)
+		api.POST("/reports", s.createReport)
+		api.GET("/reports/:id", s.getReport)
+		api.PUT("/reports/:id", s.updateReport)
+		api.DELETE("/reports/:id", s.deleteReport)
+		api.POST("/reports/:id/run", s.runReport)
+		api.GET("/reports/:id/runs", s.listReportRuns)
+		api.GET("/reports/:id/runs/:run_id/download", s.downloadReportRun)
+
+		// 重複貼文群組
+		api.GET("/duplicates", s.listDuplicateGroups)
+		api.GET("/duplicates/:post_id", s.getDuplicateGroup)
+
+		// 作者 / KOL
+		api.GET("/authors", s.listAuthors)
+		api.GET("/authors/:id", s.getAuthor)
+		api.GET("/kols", s.rankKOLs)
+
+		// 競品集合 / 比較
+		api.GET("/entities/:id/competitors", s.listCompetitors)
+		api.POST("/entities/:id/competitors", s.addCompetitor)
+		api.DELETE("/entities/:id/competitors/:competitor_id", s.removeCompetitor)
+		api.GET("/benchmark", s.benchmark)
+
+		// 面向正規化（面向樹 / 原始面向審核佇列）
+		api.GET("/aspects/taxonomy", s.getAspectTaxonomy)
+		api.POST("/aspects/taxonomy", s.createAspectNode)
+		api.GET("/aspects/review", s.listAspectMappings)
+		api.POST("/aspects/review/:id/resolve", s.resolveAspectMapping)
+
+		// 外語片段翻譯（language.translation 開啟時）
+		api.POST("/translate", s.translate)
+
+		// Bulk export
+		api.GET("/export", s.exportData)
+
+		// Review Queue（人工審核）路由
+		api.GET("/review", s.listReviewItems)
+		api.GET("/review/stats", s.getReviewStats)
+		api.GET("/review/:id", s.getReviewItem)
+		api.POST("/review/:id/resolve", s.resolveReviewItem)
+		api.POST("/review/:id/skip", s.skipReviewItem)
+
+		// LLM 用量 / 預算
+		api.GET("/usage", s.getUsage)
 	}
 }
 
@@ -120,7 +232,27 @@ func (s *Server) ingestPost(c *gin.Context) {
 		return
 	}
 
+	// 冪等：同一篇貼文（platform + ID）內容與互動數都未變時不再排入，?force=true 強制重送
+	guarded := s.ingestGuard != nil && c.Query("force") != "true"
+	if guarded {
+		fresh, err := s.ingestGuard.Claim(c.Request.Context(), []redis.PostMessage{req})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue"})
+			return
+		}
+		if !fresh[0] {
+			c.JSON(http.StatusOK, gin.H{
+				"status":  "unchanged",
+				"post_id": req.ID,
+			})
+			return
+		}
+	}
+
 	if err := s.stream.Publish(c.Request.Context(), req); err != nil {
+		if guarded {
+			s.ingestGuard.Release(c.Request.Context(), []redis.PostMessage{req})
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue"})
 		return
 	}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// TranslateRequest 片段翻譯請求
+type TranslateRequest struct {
+	Texts  []string `json:"texts" binding:"required"`
+	Target string   `json:"target"` // zh / en / ja / ms，空字串 = 設定的預設目標語言
+}
+
+// translate POST /api/translate
+//
+// 將外語貼文片段翻譯給分析師閱讀；已是目標語言的片段原文照回（skipped），最多 50 則
+func (s *Server) translate(c *gin.Context) {
+	if s.translator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "translation is disabled"})
+		return
+	}
+
+	var req TranslateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	target, ok := parseTranslateTarget(req.Target)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target must be one of zh, en, ja, ms"})
+		return
+	}
+
+	results, err := s.translator.Translate(c.Request.Context(), req.Texts, target)
+	if errors.Is(err, service.ErrTooManyTranslateTexts) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "daily LLM budget exceeded"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "translation failed"})
+		return
+	}
+	respondOne(c, results)
+}
+
+// parseTranslateTarget 解析目標語言（空字串回傳 LanguageUnknown，由 Translator 使用預設值）
+func parseTranslateTarget(s string) (entity.Language, bool) {
+	if s == "" {
+		return entity.LanguageUnknown, true
+	}
+	return entity.ParseLanguage(s)
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// UsageResponse LLM 用量報表
+type UsageResponse struct {
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	GroupBy string                 `json:"group_by"`
+	Rows    []*entity.UsageSummary `json:"rows"`
+	Total   entity.UsageSummary    `json:"total"`
+	Budget  *service.BudgetStatus  `json:"budget"`
+}
+
+// tenantMiddleware 從 X-Tenant header 標記 tenant（LLM 用量依此記帳）
+func tenantMiddleware(c *gin.Context) {
+	if tenant := c.GetHeader("X-Tenant"); tenant != "" {
+		c.Request = c.Request.WithContext(service.WithTenant(c.Request.Context(), tenant))
+	}
+	c.Next()
+}
+
+// getUsage GET /api/usage?days=7&group_by=day&tenant=&stage=
+func (s *Server) getUsage(c *gin.Context) {
+	ctx := c.Request.Context()
+	p := parseUsageParams(c)
+
+	rows, err := s.usageRepo.Summarize(ctx, entity.UsageFilter{
+		Tenant:  p.Tenant,
+		Stage:   entity.UsageStage(p.Stage),
+		From:    p.From,
+		To:      p.To,
+		GroupBy: p.GroupBy,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to summarize usage"})
+		return
+	}
+	if rows == nil {
+		rows = []*entity.UsageSummary{}
+	}
+
+	total := entity.UsageSummary{Group: "total"}
+	var latencySum float64
+	for _, r := range rows {
+		total.Calls += r.Calls
+		total.Failures += r.Failures
+		total.InputTokens += r.InputTokens
+		total.OutputTokens += r.OutputTokens
+		total.CostUSD += r.CostUSD
+		latencySum += r.AvgLatencyMS * float64(r.Calls)
+	}
+	if total.Calls > 0 {
+		total.AvgLatencyMS = latencySum / float64(total.Calls)
+	}
+
+	respondOne(c, UsageResponse{
+		From:    p.From.Format("2006-01-02"),
+		To:      p.To.Format("2006-01-02"),
+		GroupBy: p.GroupBy,
+		Rows:    rows,
+		Total:   total,
+		Budget:  s.usageTracker.Status(ctx, p.Tenant),
+	})
+}
+
+// UsageParams 用量查詢參數
+type UsageParams struct {
+	Tenant  string
+	Stage   string
+	GroupBy string
+	From    time.Time
+	To      time.Time
+}
+
+func parseUsageParams(c *gin.Context) UsageParams {
+	p := UsageParams{
+		Tenant:  c.Query("tenant"),
+		Stage:   c.Query("stage"),
+		GroupBy: c.DefaultQuery("group_by", "day"),
+	}
+
+	now := time.Now()
+	y, m, d := now.Date()
+	p.To = time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if v := c.Query("to"); v != "" {
+		if t, err := time.ParseInLocation("2006-01-02", v, now.Location()); err == nil {
+			p.To = t.AddDate(0, 0, 1) // 含當日
+		}
+	}
+
+	days := clamp(parseIntDefault(c.Query("days"), 7), 1, 90)
+	p.From = p.To.AddDate(0, 0, -days)
+	if v := c.Query("from"); v != "" {
+		if t, err := time.ParseInLocation("2006-01-02", v, now.Location()); err == nil && t.Before(p.To) {
+			p.From = t
+		}
+	}
+	return p
+}
@@ -0,0 +1,123 @@
+package entity
+
+import "time"
+
+// AskWindow 問答檢索的時間窗：本期 [From, To)，對照期 [PrevFrom, From)
+type AskWindow struct {
+	PrevFrom time.Time
+	From     time.Time
+	To       time.Time
+}
+
+// AskEntity 問題涉及的 Entity
+type AskEntity struct {
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	Type  ObjectType `json:"type"`
+	Match string     `json:"match"` // name / alias / fuzzy / semantic
+}
+
+// SentimentShift Entity（或其某個 aspect）本期 vs 對照期的情感變化
+type SentimentShift struct {
+	EntityID     string  `json:"entity_id"`
+	EntityName   string  `json:"entity_name"`
+	Aspect       string  `json:"aspect,omitempty"` // 空字串 = Entity 整體
+	CurTotal     int     `json:"cur_total"`
+	CurNegative  int     `json:"cur_negative"`
+	CurAvgScore  float64 `json:"cur_avg_score"`
+	PrevTotal    int     `json:"prev_total"`
+	PrevNegative int     `json:"prev_negative"`
+	PrevAvgScore float64 `json:"prev_avg_score"`
+}
+
+// NegativeRatioDelta 負面比例變化（本期 - 對照期，百分點）
+func (s *SentimentShift) NegativeRatioDelta() float64 {
+	return ratio(s.CurNegative, s.CurTotal)*100 - ratio(s.PrevNegative, s.PrevTotal)*100
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// AuthorDriver 本期帶動某 Entity 討論的作者（KOL）
+type AuthorDriver struct {
+	EntityID   string   `json:"entity_id"`
+	EntityName string   `json:"entity_name"`
+	Author     string   `json:"author"`
+	Followers  int      `json:"followers"`
+	Posts      int      `json:"posts"`
+	Negative   int      `json:"negative"`
+	PostIDs    []string `json:"post_ids"`
+}
+
+// AskFact 推理引擎產出的 derived fact
+type AskFact struct {
+	ID          int64     `json:"id"`
+	EntityID    string    `json:"entity_id"`
+	EntityName  string    `json:"entity_name"`
+	FactType    string    `json:"fact_type"`
+	Severity    string    `json:"severity"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RelationEvidence Entity 關係
+type RelationEvidence struct {
+	SourceID   string `json:"source_id"`
+	SourceName string `json:"source_name"`
+	Relation   string `json:"relation"`
+	TargetID   string `json:"target_id"`
+	TargetName string `json:"target_name"`
+	TargetType string `json:"target_type"`
+}
+
+// PostEvidence 代表性貼文
+type PostEvidence struct {
+	PostID     string    `json:"post_id"`
+	EntityID   string    `json:"entity_id"`
+	EntityName string    `json:"entity_name"`
+	Snippet    string    `json:"snippet"`
+	Sentiment  string    `json:"sentiment"`
+	Author     string    `json:"author"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AskEvidenceQuery 證據檢索條件
+type AskEvidenceQuery struct {
+	EntityIDs []string
+	Aspects   []string // 子字串比對 aspect（空 = 不限）
+	Sentiment string   // 空 = 不限
+	Window    AskWindow
+	Limit     int
+}
+
+// AskEvidence 問答檢索到的所有證據
+type AskEvidence struct {
+	Entities  []*AskEntity        `json:"entities"`
+	Shifts    []*SentimentShift   `json:"shifts"`
+	Drivers   []*AuthorDriver     `json:"drivers"`
+	Facts     []*AskFact          `json:"facts"`
+	Relations []*RelationEvidence `json:"relations"`
+	Posts     []*PostEvidence     `json:"posts"`
+	Window    AskWindow           `json:"window"`
+}
+
+// CitationKind 引用來源類型
+type CitationKind string
+
+const (
+	CitationPost   CitationKind = "post"
+	CitationFact   CitationKind = "fact"
+	CitationEntity CitationKind = "entity"
+)
+
+// Citation 回答中引用的來源
+type Citation struct {
+	Kind  CitationKind `json:"kind"`
+	ID    string       `json:"id"`
+	Label string       `json:"label"`
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// ============================================
+// Aspect Taxonomy — 面向正規化與階層
+// ============================================
+
+// AspectRootClassID 未設定 class 的 Entity 視為根類別（只使用通用節點）
+const AspectRootClassID = 1
+
+// AspectNode 正規面向樹的節點（子類繼承父類的節點）
+type AspectNode struct {
+	ID        int           `json:"id"`
+	ClassID   int           `json:"class_id"`
+	ClassSlug string        `json:"class,omitempty"`
+	ParentID  *int          `json:"parent_id,omitempty"`
+	Name      string        `json:"name"`
+	Path      []string      `json:"path"`     // 根 → 此節點的名稱
+	Synonyms  []string      `json:"synonyms"` // 精確對應的別名
+	Children  []*AspectNode `json:"children,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// AspectMappingStatus 原始面向的對應狀態
+type AspectMappingStatus string
+
+const (
+	AspectMappingAuto    AspectMappingStatus = "auto"    // 精確命中或相似度達門檻
+	AspectMappingManual  AspectMappingStatus = "manual"  // 人工指定
+	AspectMappingPending AspectMappingStatus = "pending" // 待審核
+	AspectMappingIgnored AspectMappingStatus = "ignored" // 人工判定不歸入任何節點
+)
+
+// AspectMapping 原始面向（依 Entity 的 class）→ 正規節點
+type AspectMapping struct {
+	ID                int64               `json:"id"`
+	ClassID           int                 `json:"class_id"`
+	ClassSlug         string              `json:"class,omitempty"`
+	RawAspect         string              `json:"raw_aspect"`
+	NodeID            *int                `json:"node_id,omitempty"`
+	NodePath          []string            `json:"node_path,omitempty"`
+	Status            AspectMappingStatus `json:"status"`
+	Similarity        *float64            `json:"similarity,omitempty"` // 與建議節點的相似度（精確命中為 1）
+	SuggestedNodeID   *int                `json:"suggested_node_id,omitempty"`
+	SuggestedNodePath []string            `json:"suggested_node_path,omitempty"`
+	MentionCount      int                 `json:"mention_count"`
+	Reviewer          string              `json:"reviewer,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+// RawAspect 尚未對應到節點的原始面向（回填用）
+type RawAspect struct {
+	ClassID   int
+	RawAspect string
+	Count     int
+}
+
+// AspectKey 原始面向的比對鍵（小寫、去頭尾空白），與 aspect_mappings.raw_aspect 一致
+func AspectKey(aspect string) string {
+	return strings.ToLower(strings.TrimSpace(aspect))
+}
@@ -0,0 +1,97 @@
+package entity
+
+import "time"
+
+// ============================================
+// Author Registry — 作者 / KOL 檔案
+// ============================================
+
+// KOLMinFollowers 追蹤數達此門檻的作者視為 KOL（自動建立 person Entity）
+const KOLMinFollowers = 10000
+
+// AuthorProfile 作者檔案（每個平台帳號一筆）
+type AuthorProfile struct {
+	ID             int64        `json:"id"`
+	Platform       Platform     `json:"platform"`
+	PlatformUserID string       `json:"platform_user_id"`
+	Username       string       `json:"username"`
+	Followers      int          `json:"followers"`
+	ObjectID       *string      `json:"object_id,omitempty"` // 連結的 person Entity
+	ObjectName     string       `json:"object_name,omitempty"`
+	FirstSeenAt    time.Time    `json:"first_seen_at"`
+	LastSeenAt     time.Time    `json:"last_seen_at"`
+	Stats          *AuthorStats `json:"stats,omitempty"`
+}
+
+// AuthorKey 作者鍵：平台帳號 ID，沒有時用帳號名稱（與 posts 的 join 鍵一致）
+func AuthorKey(a Author) string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return a.Username
+}
+
+// AuthorStats 作者統計（author_stats materialized view）
+type AuthorStats struct {
+	PostCount      int        `json:"post_count"`
+	Posts28d       int        `json:"posts_28d"`
+	PostsPerWeek   float64    `json:"posts_per_week"`  // 近 4 週平均
+	AvgSentiment   *float64   `json:"avg_sentiment"`   // 0.0 ~ 1.0，尚無情感分析時為 nil
+	AvgEngagement  float64    `json:"avg_engagement"`  // 每篇平均互動數（讚 + 留言 + 分享）
+	EngagementRate *float64   `json:"engagement_rate"` // 每篇互動數 / 追蹤數，追蹤數未知時為 nil
+	LastPostAt     *time.Time `json:"last_post_at,omitempty"`
+}
+
+// FollowerSnapshot 追蹤數每日快照
+type FollowerSnapshot struct {
+	Date      time.Time `json:"date"`
+	Followers int       `json:"followers"`
+}
+
+// AuthorAffinity 作者常談論的 Entity 或主題
+type AuthorAffinity struct {
+	ID           string   `json:"id"` // object ID 或 topic ID
+	Name         string   `json:"name"`
+	Type         string   `json:"type"` // Entity 類型，主題為 topic
+	Posts        int      `json:"posts"`
+	Share        float64  `json:"share"` // 佔作者貼文比例（0 ~ 1）
+	AvgSentiment *float64 `json:"avg_sentiment,omitempty"`
+}
+
+// AuthorDetail 作者檔案 + 追蹤數趨勢 + 談論對象
+type AuthorDetail struct {
+	*AuthorProfile
+	FollowerHistory []*FollowerSnapshot `json:"follower_history"`
+	TopEntities     []*AuthorAffinity   `json:"top_entities"`
+	TopTopics       []*AuthorAffinity   `json:"top_topics"`
+}
+
+// AuthorFilter 作者列表查詢條件
+type AuthorFilter struct {
+	Platform     string
+	Q            string // 帳號名稱模糊搜尋
+	MinFollowers int
+	LinkedOnly   bool   // 只列出已連結 person Entity 的作者
+	Sort         string // followers / posts / posts_per_week / engagement_rate / avg_sentiment / last_seen
+	Order        string // asc / desc
+}
+
+// KOLRankFilter KOL 排名條件（ObjectID 與 TopicID 擇一）
+type KOLRankFilter struct {
+	ObjectID     string
+	TopicID      int
+	Platform     string
+	Since        *time.Time
+	MinFollowers int
+	Sort         string // posts / engagement / reach / followers / avg_sentiment
+}
+
+// KOLRank 作者在某 Entity / 主題的影響力
+type KOLRank struct {
+	Author          *AuthorProfile `json:"author"`
+	Posts           int            `json:"posts"`
+	Engagement      int64          `json:"engagement"` // 相關貼文的互動數總和
+	Reach           int64          `json:"reach"`      // 相關貼文的追蹤數總和（曝光估計）
+	AvgSentiment    *float64       `json:"avg_sentiment,omitempty"`
+	ContributionPct float64        `json:"contribution_pct"` // 佔該 Entity / 主題貼文數的比例
+}
@@ -0,0 +1,116 @@
+package entity
+
+import "time"
+
+// ============================================
+// Competitor Benchmarking — 競品比較
+// ============================================
+
+// CompetitorRelationSlugs 明確競爭關係（品牌 ↔ 品牌、產品 ↔ 產品，皆為對稱關係）
+var CompetitorRelationSlugs = []string{"competes_with", "competes_with_product"}
+
+// CompetitorSource 競品來源
+type CompetitorSource string
+
+const (
+	CompetitorSourceRelation  CompetitorSource = "relation"   // competes_with 關係
+	CompetitorSourceCoMention CompetitorSource = "co_mention" // 同類 + 常在同一篇貼文被提及
+)
+
+// Competitor 某 Entity 的競品
+type Competitor struct {
+	ObjectID       string           `json:"object_id"`
+	Name           string           `json:"name"`
+	Type           string           `json:"type"`
+	Source         CompetitorSource `json:"source"`
+	RelationSource string           `json:"relation_source,omitempty"` // 關係來源：llm / manual / inferred
+	CoMentions     int              `json:"co_mentions"`               // 與該 Entity 同篇提及的貼文數
+}
+
+// CompetitorFilter 競品查詢條件
+type CompetitorFilter struct {
+	Source        CompetitorSource // 空字串為兩者都要
+	Since         *time.Time       // 同篇提及的起算時間
+	MinCoMentions int              // 隱含競品的最少同篇提及數
+	Limit         int
+}
+
+// BenchmarkFilter 競品比較條件
+type BenchmarkFilter struct {
+	ObjectIDs      []string // 第一個為主體（落差以主體為基準）
+	PeriodType     string   // day / week
+	Periods        int      // 最近幾期
+	MinAspectCount int      // 面向比較的最少提及數
+	AspectLimit    int
+}
+
+// BenchmarkTotals 一個 Entity 在比較區間內的觀測彙總（entity_observations）
+type BenchmarkTotals struct {
+	ObjectID          string
+	Name              string
+	Type              string
+	MentionCount      int
+	AvgSentiment      *float64
+	WeightedSentiment *float64
+	TotalEngagement   int64
+	EstimatedReach    int64
+}
+
+// BenchmarkEntity 比較集合中一個 Entity 的結果
+type BenchmarkEntity struct {
+	ObjectID          string   `json:"object_id"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	IsFocal           bool     `json:"is_focal"`
+	MentionCount      int      `json:"mention_count"`
+	ShareOfVoice      float64  `json:"share_of_voice"` // 佔比較集合提及數的比例（0 ~ 1）
+	ReachShare        float64  `json:"reach_share"`    // 佔比較集合觸及估計的比例（0 ~ 1）
+	AvgSentiment      *float64 `json:"avg_sentiment"`
+	WeightedSentiment *float64 `json:"weighted_sentiment"`
+	SentimentGap      *float64 `json:"sentiment_gap"` // 主體 - 此 Entity（正值代表主體較正面）
+	TotalEngagement   int64    `json:"total_engagement"`
+	EstimatedReach    int64    `json:"estimated_reach"`
+}
+
+// AspectStat 單一 Entity 的面向統計（entity_aspect_stats）
+type AspectStat struct {
+	ObjectID      string  `json:"object_id"`
+	Aspect        string  `json:"aspect"`
+	Count         int     `json:"count"`
+	AvgSentiment  float64 `json:"avg_sentiment"`
+	PositiveRatio float64 `json:"positive_ratio"` // 0 ~ 100
+}
+
+// BenchmarkAspect 面向逐項比較
+type BenchmarkAspect struct {
+	Aspect       string        `json:"aspect"`
+	Total        int           `json:"total"`
+	Entities     []*AspectStat `json:"entities"`
+	LeaderID     string        `json:"leader_id"`     // 該面向情感最高的 Entity
+	SentimentGap *float64      `json:"sentiment_gap"` // 主體 - 最佳競品，主體或競品沒有資料時為 nil
+}
+
+// BenchmarkTrendPoint 單一 Entity 在某期的觀測
+type BenchmarkTrendPoint struct {
+	ObjectID          string   `json:"object_id"`
+	MentionCount      int      `json:"mention_count"`
+	ShareOfVoice      float64  `json:"share_of_voice"`
+	AvgSentiment      *float64 `json:"avg_sentiment"`
+	WeightedSentiment *float64 `json:"weighted_sentiment"`
+}
+
+// BenchmarkPeriod 比較集合在某期的觀測
+type BenchmarkPeriod struct {
+	PeriodStart time.Time              `json:"period_start"`
+	Points      []*BenchmarkTrendPoint `json:"points"`
+}
+
+// Benchmark 競品比較結果
+type Benchmark struct {
+	FocalID    string             `json:"focal_id"`
+	PeriodType string             `json:"period_type"`
+	Periods    int                `json:"periods"`
+	Entities   []*BenchmarkEntity `json:"entities"`
+	Aspects    []*BenchmarkAspect `json:"aspects"`
+	Trend      []*BenchmarkPeriod `json:"trend"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// ============================================
+// Chat Sessions — Entity follow-up 對話紀錄
+// ============================================
+
+// DefaultChatUser 未指定使用者時使用
+const DefaultChatUser = "anonymous"
+
+// CitationAspect 引用 Entity 的某個面向（Citation.ID 為 aspect 名稱）
+const CitationAspect CitationKind = "aspect"
+
+// TokenUsage LLM token 用量與估算成本
+type TokenUsage struct {
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// ChatSession 一個使用者針對某 Entity 的對話 session
+type ChatSession struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	ObjectID        string     `json:"entity_id"`
+	ObjectName      string     `json:"entity_name"`
+	Title           string     `json:"title"`
+	Period          string     `json:"period"`
+	Summary         string     `json:"summary,omitempty"` // 較舊對話的摘要
+	SummarizedUntil int64      `json:"-"`                 // 已併入 Summary 的最後一則訊息 ID
+	MessageCount    int        `json:"message_count"`
+	Usage           TokenUsage `json:"usage"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ChatSessionMessage 對話中的一則訊息
+type ChatSessionMessage struct {
+	ID        int64      `json:"id"`
+	SessionID string     `json:"session_id"`
+	Role      string     `json:"role"` // user / assistant
+	Content   string     `json:"content"`
+	Citations []Citation `json:"citations"`
+	Usage     TokenUsage `json:"usage"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ChatSessionFilter 對話 session 查詢條件
+type ChatSessionFilter struct {
+	UserID   string
+	ObjectID string // 空字串 = 全部 Entity
+	Offset   int
+	Limit    int
+}
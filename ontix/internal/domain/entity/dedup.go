@@ -0,0 +1,76 @@
+package entity
+
+import "time"
+
+// ============================================
+// Content Deduplication — 轉貼 / 複製貼上洗版偵測
+// ============================================
+
+// DedupPolicy 近似重複貼文的處理策略
+type DedupPolicy string
+
+const (
+	DedupPolicyDrop    DedupPolicy = "drop"    // 不入庫、不分析，只記錄重複關係
+	DedupPolicyLink    DedupPolicy = "link"    // 入庫並連到原始貼文，略過 LLM 分析
+	DedupPolicyProcess DedupPolicy = "process" // 完整分析，但不計入觀測（entity_observations）
+)
+
+// ValidDedupPolicy 是否為合法策略
+func ValidDedupPolicy(p DedupPolicy) bool {
+	switch p {
+	case DedupPolicyDrop, DedupPolicyLink, DedupPolicyProcess:
+		return true
+	}
+	return false
+}
+
+// DedupMethod 重複判定方式
+type DedupMethod string
+
+const (
+	DedupMethodExact   DedupMethod = "exact"   // 正規化後內容完全相同
+	DedupMethodMinHash DedupMethod = "minhash" // MinHash 估計 Jaccard 相似度超過門檻
+)
+
+// PostFingerprint 原始貼文的內容指紋
+type PostFingerprint struct {
+	PostID      string
+	Platform    Platform
+	ContentHash string   // 正規化內容 SHA-256（hex）
+	Signature   []uint32 // MinHash 簽章
+	CreatedAt   time.Time
+}
+
+// PostDuplicate 偵測到的重複貼文
+type PostDuplicate struct {
+	PostID         string      `json:"post_id"`
+	Platform       Platform    `json:"platform"`
+	AuthorUsername string      `json:"author_username,omitempty"`
+	Content        string      `json:"content"`
+	OriginalPostID string      `json:"original_post_id"`
+	Similarity     float64     `json:"similarity"`
+	Method         DedupMethod `json:"method"`
+	Policy         DedupPolicy `json:"policy"`
+	DetectedAt     time.Time   `json:"detected_at"`
+}
+
+// DuplicateGroup 以原始貼文為中心的重複群組
+type DuplicateGroup struct {
+	OriginalPostID string           `json:"original_post_id"`
+	Platform       Platform         `json:"platform,omitempty"`
+	AuthorUsername string           `json:"author_username,omitempty"`
+	Content        string           `json:"content,omitempty"` // 原始貼文已不存在時為空
+	PostedAt       *time.Time       `json:"posted_at,omitempty"`
+	DuplicateCount int              `json:"duplicate_count"`
+	AuthorCount    int              `json:"author_count"` // 重複貼文的不同作者數（洗版指標）
+	LastDetectedAt time.Time        `json:"last_detected_at"`
+	Duplicates     []*PostDuplicate `json:"duplicates"`
+}
+
+// DuplicateFilter 重複群組查詢條件
+type DuplicateFilter struct {
+	Platform string
+	Method   DedupMethod
+	MinSize  int        // 最少重複數
+	Since    *time.Time // 最後偵測時間下限
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AspectProfile Entity 某個 aspect 的評價分佈
+type AspectProfile struct {
+	Aspect   string
+	Positive int
+	Negative int
+	Neutral  int
+}
+
+// EntityProfile 產生 Entity embedding 的素材
+type EntityProfile struct {
+	ObjectID      string
+	Type          ObjectType
+	CanonicalName string
+	Aliases       []string
+	Aspects       []AspectProfile // 依提及次數排序
+	CoMentions    []string        // 常一起被提及的 Entity
+	MentionTexts  []string        // 最近的提及片段
+	MentionCount  int
+	PostCentroid  []float32 // 提及貼文 embedding 的平均（沒有則為 nil）
+}
+
+// Text 組成 profile 文字（送進 EmbeddingService）
+func (p *EntityProfile) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s（%s）\n", p.CanonicalName, p.Type)
+	if len(p.Aliases) > 0 {
+		fmt.Fprintf(&sb, "別名：%s\n", strings.Join(p.Aliases, "、"))
+	}
+	if len(p.Aspects) > 0 {
+		parts := make([]string, 0, len(p.Aspects))
+		for _, a := range p.Aspects {
+			parts = append(parts, fmt.Sprintf("%s(正面%d 負面%d 中立%d)", a.Aspect, a.Positive, a.Negative, a.Neutral))
+		}
+		fmt.Fprintf(&sb, "評價面向：%s\n", strings.Join(parts, "、"))
+	}
+	if len(p.CoMentions) > 0 {
+		fmt.Fprintf(&sb, "常一起提及：%s\n", strings.Join(p.CoMentions, "、"))
+	}
+	for _, t := range p.MentionTexts {
+		fmt.Fprintf(&sb, "「%s」\n", t)
+	}
+	return sb.String()
+}
+
+// EntityMatch Entity 語意搜尋 / 相似 Entity 結果
+type EntityMatch struct {
+	ID            string     `json:"id"`
+	Type          ObjectType `json:"type"`
+	CanonicalName string     `json:"canonical_name"`
+	MentionCount  int        `json:"mention_count"`
+	Similarity    float64    `json:"similarity"`
+	SharedAspects []string   `json:"shared_aspects,omitempty"` // 只有相似 Entity 查詢會填
+	CoMentions    int        `json:"co_mentions,omitempty"`    // 與來源 Entity 同篇提及次數
+}
@@ -0,0 +1,143 @@
+package entity
+
+import "time"
+
+// ExportDataset 可匯出的資料集
+type ExportDataset string
+
+const (
+	ExportPosts        ExportDataset = "posts"        // 貼文 + 主題 / 軟標籤 / 情感
+	ExportMentions     ExportDataset = "mentions"     // Entity 提及
+	ExportAspects      ExportDataset = "aspects"      // Entity 面向評價
+	ExportObservations ExportDataset = "observations" // Entity 觀測快照（日 / 週）
+	ExportFacts        ExportDataset = "facts"        // 推理引擎產出的 derived facts
+)
+
+// AllExportDatasets 全部資料集（依匯出順序）
+var AllExportDatasets = []ExportDataset{
+	ExportPosts, ExportMentions, ExportAspects, ExportObservations, ExportFacts,
+}
+
+// ExportFormat 匯出格式
+type ExportFormat string
+
+const (
+	ExportCSV     ExportFormat = "csv"
+	ExportJSONL   ExportFormat = "jsonl"
+	ExportParquet ExportFormat = "parquet"
+)
+
+// ExportColumnType 欄位型別（決定 CSV / JSONL 的編碼與 Parquet schema）
+type ExportColumnType string
+
+const (
+	ExportString  ExportColumnType = "string"
+	ExportInt     ExportColumnType = "int"     // int64
+	ExportFloat   ExportColumnType = "float"   // float64
+	ExportBool    ExportColumnType = "bool"    // bool
+	ExportTime    ExportColumnType = "time"    // time.Time
+	ExportStrings ExportColumnType = "strings" // []string
+)
+
+// ExportColumn 匯出欄位
+type ExportColumn struct {
+	Name string
+	Type ExportColumnType
+}
+
+// ExportFilter 匯出篩選條件
+//
+// 期間套用在貼文時間（posts / mentions / aspects）、觀測期起始日（observations）或事實產生時間（facts）；
+// 主題、平台與語言只對貼文相關資料集有效
+type ExportFilter struct {
+	From       *time.Time
+	To         *time.Time
+	EntityIDs  []string
+	TopicCodes []string
+	Platforms  []string
+	Languages  []string
+}
+
+var exportColumns = map[ExportDataset][]ExportColumn{
+	ExportPosts: {
+		{"post_id", ExportString},
+		{"platform", ExportString},
+		{"created_at", ExportTime},
+		{"author_id", ExportString},
+		{"author_username", ExportString},
+		{"author_followers", ExportInt},
+		{"content", ExportString},
+		{"likes", ExportInt},
+		{"comments", ExportInt},
+		{"shares", ExportInt},
+		{"views", ExportInt},
+		{"sentiment", ExportString},
+		{"sentiment_score", ExportFloat},
+		{"sentiment_source", ExportString},
+		{"intent", ExportString},
+		{"product_type", ExportString},
+		{"topics", ExportStrings},
+		{"soft_tags", ExportStrings},
+		{"kind", ExportString},
+		{"parent_post_id", ExportString},
+		{"root_post_id", ExportString},
+		{"language", ExportString},
+	},
+	ExportMentions: {
+		{"post_id", ExportString},
+		{"object_id", ExportString},
+		{"entity_name", ExportString},
+		{"entity_type", ExportString},
+		{"sentiment", ExportString},
+		{"sentiment_score", ExportFloat},
+		{"mention_text", ExportString},
+		{"source", ExportString},
+		{"platform", ExportString},
+		{"post_created_at", ExportTime},
+	},
+	ExportAspects: {
+		{"post_id", ExportString},
+		{"object_id", ExportString},
+		{"entity_name", ExportString},
+		{"aspect", ExportString},
+		{"sentiment", ExportString},
+		{"sentiment_score", ExportFloat},
+		{"mention_text", ExportString},
+		{"platform", ExportString},
+		{"post_created_at", ExportTime},
+	},
+	ExportObservations: {
+		{"object_id", ExportString},
+		{"entity_name", ExportString},
+		{"entity_type", ExportString},
+		{"period_type", ExportString},
+		{"period_start", ExportTime},
+		{"mention_count", ExportInt},
+		{"positive_count", ExportInt},
+		{"negative_count", ExportInt},
+		{"neutral_count", ExportInt},
+		{"mixed_count", ExportInt},
+		{"avg_sentiment", ExportFloat},
+		{"aspect_data", ExportString}, // JSON
+	},
+	ExportFacts: {
+		{"id", ExportInt},
+		{"object_id", ExportString},
+		{"entity_name", ExportString},
+		{"fact_type", ExportString},
+		{"severity", ExportString},
+		{"title", ExportString},
+		{"description", ExportString},
+		{"evidence", ExportString}, // JSON
+		{"period_type", ExportString},
+		{"period_start", ExportTime},
+		{"is_read", ExportBool},
+		{"is_dismissed", ExportBool},
+		{"created_at", ExportTime},
+	},
+}
+
+// ExportColumns 資料集欄位（順序即輸出順序）；未知資料集回傳 nil
+func ExportColumns(dataset ExportDataset) []ExportColumn {
+	return exportColumns[dataset]
+}
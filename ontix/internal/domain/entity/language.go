@@ -0,0 +1,66 @@
+package entity
+
+import "strings"
+
+// Language 貼文語言（ISO 639-1）
+type Language string
+
+const (
+	LanguageUnknown  Language = ""   // 無法判斷（內容過短、純表情符號等）
+	LanguageChinese  Language = "zh" // 中文（prompt 以繁體中文撰寫）
+	LanguageEnglish  Language = "en"
+	LanguageJapanese Language = "ja"
+	LanguageMalay    Language = "ms"
+)
+
+// Languages 支援的語言
+var Languages = []Language{LanguageChinese, LanguageEnglish, LanguageJapanese, LanguageMalay}
+
+// languageAliases 常見的語言標記寫法
+var languageAliases = map[string]Language{
+	"zh-tw":   LanguageChinese,
+	"zh-hant": LanguageChinese,
+	"zh-hk":   LanguageChinese,
+	"zh-cn":   LanguageChinese,
+	"zh-hans": LanguageChinese,
+	"en-us":   LanguageEnglish,
+	"en-gb":   LanguageEnglish,
+	"ja-jp":   LanguageJapanese,
+	"ms-my":   LanguageMalay,
+	"my":      LanguageMalay, // 常被誤用的國家代碼
+}
+
+// ParseLanguage 解析語言代碼（不分大小寫，接受 zh-TW 之類的地區寫法）
+func ParseLanguage(s string) (Language, bool) {
+	s = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(s, "_", "-")))
+	if lang, ok := languageAliases[s]; ok {
+		return lang, true
+	}
+	for _, lang := range Languages {
+		if string(lang) == s {
+			return lang, true
+		}
+	}
+	return LanguageUnknown, false
+}
+
+// Name 語言的中文名稱（prompt 與 CLI 顯示用）
+func (l Language) Name() string {
+	switch l {
+	case LanguageChinese:
+		return "中文"
+	case LanguageEnglish:
+		return "英文"
+	case LanguageJapanese:
+		return "日文"
+	case LanguageMalay:
+		return "馬來文"
+	default:
+		return "未知"
+	}
+}
+
+// SpaceDelimited 是否以空白分詞（英文、馬來文）；中日文需以 n-gram 比對
+func (l Language) SpaceDelimited() bool {
+	return l == LanguageEnglish || l == LanguageMalay
+}
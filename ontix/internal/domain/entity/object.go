@@ -82,10 +82,12 @@ type PostEntityMention struct {
 	PostID         string
 	ObjectID       string
 	Sentiment      string   // positive / negative / neutral / mixed
-	SentimentScore float64
+	SentimentScore *float64 // 規則連結未判斷情感時為 nil（平均時略過）
 	MentionText    string   // 原文片段
 	Source         string   // llm / manual / rule
+	Span           *TextSpan // 片段在 posts.content 中的位置（對不上時為 nil）
 	CreatedAt      time.Time
+	SentimentScoring
 }
 
 // EntityAspect Entity 的 Aspect 評價
@@ -97,5 +99,30 @@ type EntityAspect struct {
 	Sentiment      string   // positive / negative / neutral
 	SentimentScore float64
 	MentionText    string   // 原文片段
+	Span           *TextSpan // 片段在 posts.content 中的位置（對不上時為 nil）
+	AspectNodeID   *int      // 對應的正規面向節點（未對應時為 nil）
 	CreatedAt      time.Time
+	SentimentScoring
+}
+
+// SentimentScoring LLM 情緒分數的來源與校準（SentimentScore 為校準後分數）
+type SentimentScoring struct {
+	RawSentimentScore *float64 // LLM 原始分數（規則 / 人工新增時為 nil）
+	Model             string   // 產生分數的模型
+	PromptVersion     string   // 產生分數的 prompt 版本
+	CalibrationID     *int64   // 套用的校準（未校準時為 nil）
+}
+
+// TextSpan 原文位置（字元 / Unicode code point 位置，End 不含）
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SpanAlignTarget 待補原文位置的提及或面向（回填用）
+type SpanAlignTarget struct {
+	Kind        string // mention / aspect
+	ID          int64
+	MentionText string
+	Content     string // posts.content
 }
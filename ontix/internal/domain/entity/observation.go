@@ -20,6 +20,20 @@ type EntityObservation struct {
 	MixedCount    int
 	AvgSentiment  float64
 
+	// 留言 / 回覆的提及另外聚合（上方計數與面向快照只含原始貼文）
+	CommentMentionCount  int
+	CommentPositiveCount int
+	CommentNegativeCount int
+	CommentNeutralCount  int
+	CommentMixedCount    int
+	CommentAvgSentiment  float64
+
+	// 加權指標（只含原始貼文）
+	WeightedSentiment float64 // 以 1 + ln(1 + 互動數) 加權的平均情感
+	TotalEngagement   int64   // 提及貼文的互動數總和（讚 + 留言 + 分享）
+	EstimatedReach    int64   // 提及貼文的觸及估計總和（觀看數，沒有時用追蹤數）
+	ShareOfVoice      float64 // 同類 Entity 本期提及數佔比（0 ~ 1）
+
 	// 面向快照：本期 top aspects
 	AspectData []AspectObservation
 
@@ -28,9 +42,11 @@ type EntityObservation struct {
 
 // AspectObservation 單個面向的觀測彙總
 type AspectObservation struct {
-	Aspect       string  `json:"aspect"`
-	Count        int     `json:"count"`
-	AvgSentiment float64 `json:"avg_sentiment"`
+	Aspect       string   `json:"aspect"` // 正規節點名稱，未對應時為原字串
+	Count        int      `json:"count"`
+	AvgSentiment float64  `json:"avg_sentiment"`
+	NodeID       *int     `json:"node_id,omitempty"`
+	Path         []string `json:"path,omitempty"` // 根 → 節點（roll-up 用）
 }
 
 // ObservationDelta 兩期 observation 的差異（推理引擎的核心輸入）
@@ -48,6 +64,14 @@ type ObservationDelta struct {
 	MentionDelta      int
 	MentionDeltaPct   float64
 
+	// 加權指標 delta
+	WeightedSentimentDelta    float64 // current - previous
+	WeightedSentimentDeltaPct float64 // (current - previous) / |previous| * 100
+	ReachDelta                int64
+	ReachDeltaPct             float64
+	ShareOfVoiceDelta         float64 // 佔比差（current - previous，0 ~ 1 尺度）
+	ShareOfVoiceDeltaPct      float64 // (current - previous) / previous * 100
+
 	// 面向 delta
 	NewAspects     []string           // 本期有、上期沒有的面向
 	RemovedAspects []string           // 上期有、本期沒有的面向
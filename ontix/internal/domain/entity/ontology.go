@@ -99,13 +99,14 @@ type Rule struct {
 // RuleCondition 規則條件（從 JSONB 反序列化）
 type RuleCondition struct {
 	EntityClass        string  `json:"entity_class"`         // "product", "brand", "entity"(=any)
-	Metric             string  `json:"metric"`               // avg_sentiment / mention_count / new_aspects / aspect_sentiment
+	Metric             string  `json:"metric"`               // avg_sentiment / mention_count / weighted_sentiment / estimated_reach / share_of_voice / new_aspects / aspect_sentiment
 	Compare            string  `json:"compare"`              // prev_period
 	Operator           string  `json:"operator"`             // decrease_pct / increase_pct / above / below / equals / exists / sign_flip
 	Threshold          float64 `json:"threshold"`            // 15 = 15%
 	MinMentions        int     `json:"min_mentions"`         // 最少提及數才觸發
 	MinAspectMentions  int     `json:"min_aspect_mentions"`  // 面向最少提及數
 	ConsecutivePeriods int     `json:"consecutive_periods"`  // 連續幾期符合
+	AspectLevel        int     `json:"aspect_level"`         // 面向規則在面向樹的第幾層比較（1 = 頂層，0 = 不 roll-up）
 }
 
 // RuleActionConfig 規則動作配置（從 JSONB 反序列化）
@@ -12,6 +12,36 @@ type Post struct {
 	Metrics   Metrics
 	Embedding Vector
 	CreatedAt time.Time
+	Language  Language // 入庫時偵測的語言（空字串 = 無法判斷）
+
+	// 留言串（Kind 為 post 時 ParentID / RootID 為空）
+	Kind     PostKind
+	ParentID string // 直接上層（貼文或留言）的 PostID
+	RootID   string // 串的原始貼文 PostID
+}
+
+// PostChunk 長文切段（Start / End 為 posts.content 的 rune 位置，End 不含）
+type PostChunk struct {
+	PostID     string
+	ChunkIndex int
+	Start      int
+	End        int
+	Content    string
+	Embedding  Vector
+}
+
+// PostKind 內容類型
+type PostKind string
+
+const (
+	PostKindPost    PostKind = "post"    // 原始貼文
+	PostKindComment PostKind = "comment" // 貼文下的留言
+	PostKindReply   PostKind = "reply"   // 留言下的回覆
+)
+
+// IsComment 是否為留言或回覆
+func (p *Post) IsComment() bool {
+	return p.Kind == PostKindComment || p.Kind == PostKindReply
 }
 
 // Platform 貼文來源平台
@@ -80,3 +110,29 @@ func (p *Post) RiskScore() int {
 func (p *Post) IsHighValue() bool {
 	return p.RiskScore() >= 70
 }
+
+// MetricsSnapshot 某個時間點的互動數快照
+type MetricsSnapshot struct {
+	PostID     string
+	Metrics    Metrics
+	CapturedAt time.Time
+}
+
+// ReanalysisMark 內容編輯後重跑的標記：舊內容與 LLM 衍生資料保留到新分析成功才替換，
+// 重跑前的去重狀態（新內容會重新比對）記在這裡，重跑失敗時據此還原
+type ReanalysisMark struct {
+	PostID           string
+	Language         Language         // 儲存的語言，新內容偵測不出語言時沿用
+	Fingerprint      *PostFingerprint // 重跑前的指紋（nil = 非原始貼文或內容過短）
+	FingerprintBands []int64          // 指紋的 LSH 分桶（依 band 順序）
+	Duplicate        *PostDuplicate   // 重跑前的重複紀錄（nil = 非重複貼文）
+}
+
+// PostTombstone 已刪除貼文（平台下架 / 使用者要求），重新匯入時略過
+type PostTombstone struct {
+	PostID      string
+	Platform    Platform
+	Reason      string
+	RequestedBy string
+	DeletedAt   time.Time
+}
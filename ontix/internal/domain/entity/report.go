@@ -0,0 +1,170 @@
+package entity
+
+import "time"
+
+// ============================================
+// Reports — 排程品牌報表
+// ============================================
+
+// ReportSection 報表章節
+type ReportSection string
+
+const (
+	ReportSectionKPIs           ReportSection = "kpis"            // 提及數、情感分佈、互動數（vs 上期）
+	ReportSectionSentimentTrend ReportSection = "sentiment_trend" // 每週提及 / 情感走勢
+	ReportSectionTopAspects     ReportSection = "top_aspects"     // 熱門面向與評價
+	ReportSectionKOLAttribution ReportSection = "kol_attribution" // 帶動討論的作者
+	ReportSectionFacts          ReportSection = "facts"           // 推理引擎 alerts / trends / insights
+	ReportSectionAISummary      ReportSection = "ai_summary"      // GenerateEntitySummary 產出的洞察
+)
+
+// AllReportSections 預設章節（依報表呈現順序）
+var AllReportSections = []ReportSection{
+	ReportSectionKPIs,
+	ReportSectionAISummary,
+	ReportSectionSentimentTrend,
+	ReportSectionTopAspects,
+	ReportSectionKOLAttribution,
+	ReportSectionFacts,
+}
+
+// ReportFormat 報表輸出格式
+type ReportFormat string
+
+const (
+	ReportFormatHTML ReportFormat = "html"
+	ReportFormatPDF  ReportFormat = "pdf"
+)
+
+// ReportRunStatus 報表產出狀態
+type ReportRunStatus string
+
+const (
+	ReportRunRunning   ReportRunStatus = "running"
+	ReportRunSucceeded ReportRunStatus = "succeeded"
+	ReportRunFailed    ReportRunStatus = "failed"
+)
+
+// ReportDefinition 報表定義
+type ReportDefinition struct {
+	ID         int64           `json:"id"`
+	Name       string          `json:"name"`
+	EntityIDs  []string        `json:"entity_ids"`
+	ClassIDs   []int           `json:"class_ids"`
+	Period     string          `json:"period"` // 1w / 4w / 12w
+	Sections   []ReportSection `json:"sections"`
+	Formats    []ReportFormat  `json:"formats"`
+	Schedule   string          `json:"schedule"` // cron（分 時 日 月 週），空字串 = 只手動產出
+	Timezone   string          `json:"timezone"`
+	Recipients []string        `json:"recipients"`
+	IsActive   bool            `json:"is_active"`
+	LastRunAt  *time.Time      `json:"last_run_at,omitempty"`
+	NextRunAt  *time.Time      `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// HasSection 是否包含某章節
+func (d *ReportDefinition) HasSection(section ReportSection) bool {
+	for _, s := range d.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFormat 是否輸出某格式
+func (d *ReportDefinition) HasFormat(format ReportFormat) bool {
+	for _, f := range d.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ReportRun 一次報表產出（HTML / PDF 內容只在下載時載入）
+type ReportRun struct {
+	ID           int64           `json:"id"`
+	DefinitionID int64           `json:"definition_id"`
+	Status       ReportRunStatus `json:"status"`
+	Trigger      string          `json:"trigger"` // schedule / manual
+	PeriodStart  time.Time       `json:"period_start"`
+	PeriodEnd    time.Time       `json:"period_end"`
+	HasHTML      bool            `json:"has_html"`
+	HasPDF       bool            `json:"has_pdf"`
+	Error        string          `json:"error,omitempty"`
+	DeliveredTo  []string        `json:"delivered_to"`
+	CreatedAt    time.Time       `json:"created_at"`
+	FinishedAt   *time.Time      `json:"finished_at,omitempty"`
+
+	HTML string `json:"-"`
+	PDF  []byte `json:"-"`
+}
+
+// ReportWindow 報表期間：本期 [From, To)，上期 [PrevFrom, From)
+type ReportWindow struct {
+	PrevFrom time.Time
+	From     time.Time
+	To       time.Time
+}
+
+// ReportEntity 報表涵蓋的 Entity
+type ReportEntity struct {
+	ID   string
+	Name string
+	Type ObjectType
+}
+
+// ReportKPIs 本期 vs 上期指標
+type ReportKPIs struct {
+	Mentions         int
+	PrevMentions     int
+	Positive         int
+	Negative         int
+	Neutral          int
+	AvgSentiment     float64
+	PrevAvgSentiment float64
+	Posts            int
+	Engagement       int64 // likes + comments + shares
+	PrevEngagement   int64
+}
+
+// ReportTrendPoint 每週提及 / 情感
+type ReportTrendPoint struct {
+	WeekStart    time.Time
+	Mentions     int
+	Positive     int
+	Negative     int
+	AvgSentiment float64
+}
+
+// ReportAspect 面向評價
+type ReportAspect struct {
+	Aspect       string
+	Total        int
+	Positive     int
+	Negative     int
+	AvgSentiment float64
+}
+
+// ReportKOL 帶動討論的作者
+type ReportKOL struct {
+	Author          string
+	Followers       int
+	Posts           int
+	Engagement      int64
+	AvgSentiment    float64
+	ContributionPct float64 // 佔本期提及的比例
+}
+
+// ReportFact 推理引擎產出的 fact
+type ReportFact struct {
+	ID          int64
+	FactType    string
+	Severity    string
+	Title       string
+	Description string
+	CreatedAt   time.Time
+}
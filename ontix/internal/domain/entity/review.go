@@ -0,0 +1,84 @@
+package entity
+
+import "time"
+
+// ============================================
+// Review Queue — 人工審核與主動學習
+// ============================================
+
+// ReviewReason 進入審核佇列的原因
+type ReviewReason string
+
+const (
+	ReviewReasonAmbiguousTopic ReviewReason = "ambiguous_topic" // TopicAssigner 模糊分類
+	ReviewReasonLowConfidence  ReviewReason = "low_confidence"  // LLMClassifier 低信心
+	ReviewReasonAliasConflict  ReviewReason = "alias_conflict"  // Entity 抽取結果與別名表不一致
+)
+
+// ReviewStatus 審核狀態
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusResolved ReviewStatus = "resolved"
+	ReviewStatusSkipped  ReviewStatus = "skipped"
+)
+
+// ReviewItem 審核佇列項目
+type ReviewItem struct {
+	ID         int64
+	PostID     string
+	Reason     ReviewReason
+	Detail     map[string]any // 觸發當下的模型輸出
+	Priority   float64        // 越高越優先（越不確定越先審）
+	Status     ReviewStatus
+	Reviewer   string
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+
+	// 查詢時 join
+	Content string
+}
+
+// ReviewCorrection 審核者提交的校正內容
+// 各欄位為 nil 表示不修改；Topics 為 nil 且原因為主題相關時，視為確認現有主題
+type ReviewCorrection struct {
+	Reviewer  string
+	Topics    []int                // 正確的主題 ID 集合
+	Tags      []string             // 正確的軟標籤集合
+	Sentiment *SentimentCorrection // 正確的整體情感
+	Mentions  []MentionCorrection  // Entity 提及的校正
+}
+
+// SentimentCorrection 情感校正
+type SentimentCorrection struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// MentionCorrection Entity 提及校正
+type MentionCorrection struct {
+	ObjectID       string  `json:"object_id"`
+	Sentiment      string  `json:"sentiment,omitempty"`
+	SentimentScore float64 `json:"sentiment_score,omitempty"`
+	MentionText    string  `json:"mention_text,omitempty"`
+	Remove         bool    `json:"remove,omitempty"` // 誤抽，刪除此提及
+}
+
+// CorrectionField 校正紀錄的欄位
+type CorrectionField string
+
+const (
+	CorrectionFieldTopic     CorrectionField = "topic"
+	CorrectionFieldTags      CorrectionField = "tags"
+	CorrectionFieldSentiment CorrectionField = "sentiment"
+	CorrectionFieldMention   CorrectionField = "mention"
+)
+
+// FewShotExample 人工校正後的範例（注入 LLM prompt 做 few-shot）
+type FewShotExample struct {
+	PostID  string
+	Content string
+	Field   CorrectionField
+	After   map[string]any // 校正後的正確答案
+}
@@ -0,0 +1,95 @@
+package entity
+
+import "time"
+
+// SearchMode 搜尋模式
+type SearchMode string
+
+const (
+	SearchModeHybrid  SearchMode = "hybrid"  // 向量 + 關鍵字（RRF 融合）
+	SearchModeVector  SearchMode = "vector"  // 只用向量相似度
+	SearchModeKeyword SearchMode = "keyword" // 只用 trigram / 全文檢索
+)
+
+// SearchFilter 搜尋篩選條件（空值 = 不限制）
+type SearchFilter struct {
+	Platforms  []string
+	From       *time.Time
+	To         *time.Time
+	TopicCodes []string
+	Sentiments []string
+	Intents    []string
+	EntityIDs  []string // objects.id
+	Authors    []string // author_username 或 author_id
+	Languages  []string // posts.language（zh / en / ja / ms）
+}
+
+// SearchCursor 分頁游標：上一頁最後一筆的 (score, post_id)
+type SearchCursor struct {
+	Score  float64
+	PostID string
+}
+
+// SearchQuery 混合搜尋請求
+type SearchQuery struct {
+	Text          string
+	Terms         []string  // 關鍵字（Text 以空白切分）
+	Embedding     []float32 // 查詢向量（keyword 模式可為空）
+	Mode          SearchMode
+	Filter        SearchFilter
+	Cursor        *SearchCursor
+	Limit         int
+	VectorK       int     // 向量候選數
+	MinSimilarity float64 // 向量候選最低相似度
+}
+
+// SearchHit 單筆搜尋結果
+type SearchHit struct {
+	PostID         string
+	Content        string
+	Platform       string
+	Language       string
+	AuthorUsername string
+	CreatedAt      time.Time
+	Sentiment      string
+	Intent         string
+	SoftTags       []string
+	Snippet        string   // 命中關鍵字附近的片段（<mark> 標示，已 HTML escape）
+	Score          float64  // RRF 融合分數
+	Similarity     *float64 // 向量相似度（未進入向量候選為 nil）
+	KeywordScore   *float64 // 關鍵字分數（未命中關鍵字為 nil）
+}
+
+// FacetCount 單一 facet 值的計數
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets 完整命中集合上的 facet 計數
+type SearchFacets struct {
+	Platforms  []FacetCount `json:"platforms"`
+	Sentiments []FacetCount `json:"sentiments"`
+	Intents    []FacetCount `json:"intents"`
+	Topics     []FacetCount `json:"topics"`
+	Entities   []FacetCount `json:"entities"`
+	Authors    []FacetCount `json:"authors"`
+	Tags       []FacetCount `json:"tags"`
+	Languages  []FacetCount `json:"languages"`
+}
+
+// SearchPage 一頁搜尋結果
+type SearchPage struct {
+	Hits       []*SearchHit
+	Total      int // 命中集合總數
+	Facets     *SearchFacets
+	NextCursor *SearchCursor // nil = 沒有下一頁
+}
+
+// TopicNeighbor 與查詢語意最接近的主題
+type TopicNeighbor struct {
+	Code       string
+	Name       string
+	Similarity float64
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// ============================================
+// Sentiment Calibration — 跨模型 / prompt 版本的情緒分數校準
+// ============================================
+
+// SentimentCalibration 某個 model / prompt 版本（/ 語言）的分數對應表
+// 以單調（isotonic）擬合的節點做分段線性內插，將原始分數轉為共同尺度
+type SentimentCalibration struct {
+	ID              int64     `json:"id"`
+	Model           string    `json:"model"`
+	PromptVersion   string    `json:"prompt_version"`
+	Language        string    `json:"language"`         // 空字串 = 不分語言
+	RawKnots        []float64 `json:"raw_knots"`        // 遞增的原始分數
+	CalibratedKnots []float64 `json:"calibrated_knots"` // 對應的校準分數（非遞減）
+	SampleCount     int       `json:"sample_count"`
+	MAEBefore       float64   `json:"mae_before"` // 校準前與標註的平均絕對誤差
+	MAEAfter        float64   `json:"mae_after"`
+	IsActive        bool      `json:"is_active"`
+	FittedAt        time.Time `json:"fitted_at"`
+}
+
+// Apply 將原始分數轉為校準後分數（節點範圍外取端點值，結果限制在 0~1）
+func (c *SentimentCalibration) Apply(raw float64) float64 {
+	n := len(c.RawKnots)
+	if n == 0 || n != len(c.CalibratedKnots) {
+		return raw
+	}
+	var v float64
+	switch i := sort.SearchFloat64s(c.RawKnots, raw); {
+	case i == 0:
+		v = c.CalibratedKnots[0]
+	case i >= n:
+		v = c.CalibratedKnots[n-1]
+	default:
+		x0, x1 := c.RawKnots[i-1], c.RawKnots[i]
+		y0, y1 := c.CalibratedKnots[i-1], c.CalibratedKnots[i]
+		v = y0 + (y1-y0)*(raw-x0)/(x1-x0)
+	}
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// SentimentSample 校準用的標註樣本：LLM 原始分數 + 人工校正後的目標分數
+type SentimentSample struct {
+	Model         string
+	PromptVersion string
+	Language      string
+	Raw           float64
+	Target        float64
+}
+
+// sentimentBands 各情緒標籤對應的分數區間（與抽取 prompt 的說明一致）
+var sentimentBands = map[string][2]float64{
+	"positive": {0.7, 1.0},
+	"neutral":  {0.4, 0.6},
+	"mixed":    {0.4, 0.6},
+	"negative": {0.0, 0.3},
+}
+
+// SentimentLabelFor 依（校準後）分數重新判定標籤，以相鄰區間的中點為界；
+// mixed 無法由分數與 neutral 區分，分數仍在中間區間時保留原標籤
+func SentimentLabelFor(label string, score float64) string {
+	switch {
+	case score >= (sentimentBands["neutral"][1]+sentimentBands["positive"][0])/2:
+		return "positive"
+	case score <= (sentimentBands["negative"][1]+sentimentBands["neutral"][0])/2:
+		return "negative"
+	case label == "mixed":
+		return "mixed"
+	default:
+		return "neutral"
+	}
+}
+
+// SentimentTarget 人工校正的目標分數：分數落在標籤區間內時採用，否則取區間中點
+// （審核時常只改標籤、分數維持 LLM 原值或未填）；未知標籤回傳 false
+func SentimentTarget(label string, score float64) (float64, bool) {
+	band, ok := sentimentBands[label]
+	if !ok {
+		return 0, false
+	}
+	if score >= band[0] && score <= band[1] {
+		return score, true
+	}
+	return (band[0] + band[1]) / 2, true
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSentimentCalibrationApply(t *testing.T) {
+	cal := &SentimentCalibration{
+		RawKnots:        []float64{0.2, 0.5, 0.8},
+		CalibratedKnots: []float64{0.1, 0.5, 0.7},
+	}
+
+	tests := []struct {
+		name string
+		cal  *SentimentCalibration
+		raw  float64
+		want float64
+	}{
+		{"below first knot", cal, 0.05, 0.1},
+		{"at first knot", cal, 0.2, 0.1},
+		{"interpolates", cal, 0.35, 0.3},
+		{"at middle knot", cal, 0.5, 0.5},
+		{"interpolates upper segment", cal, 0.65, 0.6},
+		{"above last knot", cal, 0.95, 0.7},
+		{"no knots returns raw", &SentimentCalibration{}, 0.42, 0.42},
+		{"mismatched knots returns raw", &SentimentCalibration{RawKnots: []float64{0.5}}, 0.42, 0.42},
+		{"clamped to 1", &SentimentCalibration{RawKnots: []float64{0.5}, CalibratedKnots: []float64{1.2}}, 0.5, 1},
+		{"clamped to 0", &SentimentCalibration{RawKnots: []float64{0.5}, CalibratedKnots: []float64{-0.2}}, 0.5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cal.Apply(tt.raw); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Apply(%v) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentimentLabelFor(t *testing.T) {
+	tests := []struct {
+		label string
+		score float64
+		want  string
+	}{
+		{"neutral", 0.9, "positive"},
+		{"negative", 0.65, "positive"},
+		{"positive", 0.35, "negative"},
+		{"neutral", 0.1, "negative"},
+		{"positive", 0.5, "neutral"},
+		{"mixed", 0.5, "mixed"},
+		{"mixed", 0.8, "positive"},
+		{"", 0.5, "neutral"},
+	}
+
+	for _, tt := range tests {
+		if got := SentimentLabelFor(tt.label, tt.score); got != tt.want {
+			t.Errorf("SentimentLabelFor(%q, %v) = %q, want %q", tt.label, tt.score, got, tt.want)
+		}
+	}
+}
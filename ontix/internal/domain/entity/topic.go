@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // Topic 預定義主題
 type Topic struct {
@@ -24,6 +27,7 @@ type PostTopic struct {
 	Confidence   ConfidenceLevel
 	IsAmbiguous  bool    // 是否模糊分類
 	KeywordBoost float64 // 關鍵字加權值
+	Probability  float64 // 校準後機率（多標籤模式；單標籤為 0 表示未校準）
 	AssignedAt   time.Time
 }
 
@@ -51,3 +55,32 @@ const (
 	TopicDailyConversation     TopicCode = "daily_conversation_topics"
 	TopicClimateAndEnvironment TopicCode = "climate_and_environment"
 )
+
+// TopicCalibration 主題機率校準參數（Platt scaling）
+// 將 TopicAssigner 的原始 cosine similarity 映射為「屬於此主題」的機率：
+// P(topic | sim) = 1 / (1 + exp(-(Slope*sim + Intercept)))
+type TopicCalibration struct {
+	TopicID       int
+	Slope         float64
+	Intercept     float64
+	Threshold     float64 // 多標籤模式的機率門檻（F1 最佳點）
+	SampleCount   int     // 擬合用樣本數
+	PositiveCount int     // 其中正樣本數
+	LogLoss       float64 // 擬合後的 log loss
+	FittedAt      time.Time
+}
+
+// Probability 將原始相似度轉為校準後機率
+func (c *TopicCalibration) Probability(similarity float64) float64 {
+	return 1 / (1 + math.Exp(-(c.Slope*similarity + c.Intercept)))
+}
+
+// TopicLabel 人工標註的貼文主題（校準與評估用 gold label）
+// 同一篇貼文的標註視為完整集合：未被標註的主題即為負樣本
+type TopicLabel struct {
+	PostID     int64
+	TopicID    int
+	IsRelevant bool
+	Source     TagSource // manual / feedback
+	CreatedAt  time.Time
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// ============================================
+// LLM Usage — 成本 / Token / 延遲記帳
+// ============================================
+
+// UsageStage LLM 呼叫所屬的管線階段
+type UsageStage string
+
+const (
+	UsageStageTagging        UsageStage = "tagging"        // AnalyzePost / GenerateTags
+	UsageStageExtraction     UsageStage = "extraction"     // ExtractEntities
+	UsageStageClassification UsageStage = "classification" // LLMClassifier
+	UsageStageNarrative      UsageStage = "narrative"      // Ontology 週報敘事
+	UsageStageSummary        UsageStage = "summary"        // Entity AI 摘要
+	UsageStageChat           UsageStage = "chat"           // Entity follow-up 對話
+	UsageStageEmbedding      UsageStage = "embedding"      // Embed / BatchEmbed
+	UsageStageTranslation    UsageStage = "translation"    // 分析師片段翻譯
+)
+
+// DefaultTenant 未指定 tenant 時使用
+const DefaultTenant = "default"
+
+// LLMUsage 單次 LLM / Embedding 呼叫紀錄
+type LLMUsage struct {
+	ID           int64
+	Tenant       string
+	Stage        UsageStage
+	Provider     string // openai / gemini
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+	LatencyMS    int64
+	Success      bool
+	CreatedAt    time.Time
+}
+
+// UsageSummary 用量彙總（依 group_by 欄位分組）
+type UsageSummary struct {
+	Group        string  `json:"group"`
+	Calls        int     `json:"calls"`
+	Failures     int     `json:"failures"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	AvgLatencyMS float64 `json:"avg_latency_ms"`
+}
+
+// UsageFilter 用量查詢條件
+type UsageFilter struct {
+	Tenant  string // 空字串 = 全部
+	Stage   UsageStage
+	From    time.Time
+	To      time.Time
+	GroupBy string // day / stage / model / tenant
+}
+
+// budgetCutoff 每日預算使用比例達到門檻時停用該階段（由非核心功能開始降級）
+// 未列出的階段（classification、embedding）為核心管線，不受預算限制
+var budgetCutoff = map[UsageStage]float64{
+	UsageStageNarrative:   0.8,
+	UsageStageTranslation: 0.8,
+	UsageStageSummary:     0.9,
+	UsageStageChat:        1.0,
+	UsageStageTagging:     1.0,
+	UsageStageExtraction:  1.0,
+}
+
+// BudgetCutoff 取得階段的預算降級門檻（0 = 不受限制）
+func BudgetCutoff(stage UsageStage) float64 {
+	return budgetCutoff[stage]
+}
+
+// modelPrice 每 1M tokens 的美元價格
+type modelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPricing 各模型定價（USD / 1M tokens）
+var modelPricing = map[string]modelPrice{
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+	"gpt-4o":                 {Input: 2.50, Output: 10.00},
+	"text-embedding-3-small": {Input: 0.02},
+	"text-embedding-3-large": {Input: 0.13},
+	"gemini-2.0-flash":       {Input: 0.10, Output: 0.40},
+	"text-embedding-004":     {},
+}
+
+// EstimateCostUSD 依模型定價估算成本（未知模型以 gpt-4o-mini 計價）
+func EstimateCostUSD(model string, inputTokens, outputTokens int) float64 {
+	price, ok := modelPricing[model]
+	if !ok {
+		// 帶日期的版本（gpt-4o-mini-2024-07-18）以最長前綴比對
+		matched := ""
+		for name, p := range modelPricing {
+			if strings.HasPrefix(model, name+"-") && len(name) > len(matched) {
+				price, ok, matched = p, true, name
+			}
+		}
+	}
+	if !ok {
+		price = modelPricing["gpt-4o-mini"]
+	}
+	return float64(inputTokens)*price.Input/1_000_000 + float64(outputTokens)*price.Output/1_000_000
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// AspectTaxonomyRepository 正規面向樹與原始面向對應
+// 「class 的節點」皆包含祖先 class 的節點（子類繼承父類的面向）
+type AspectTaxonomyRepository interface {
+	// ListNodes 某 class 可用的節點（含祖先 class），classID <= 0 時回傳全部；依 path 排序
+	ListNodes(ctx context.Context, classID int) ([]*entity.AspectNode, error)
+
+	// FindNode 以 ID 查節點，不存在時回傳 nil
+	FindNode(ctx context.Context, id int) (*entity.AspectNode, error)
+
+	// SaveNode 新增節點（path 由父節點推得），回填 ID / Path；同 class 已有同名節點時回傳 false
+	SaveNode(ctx context.Context, node *entity.AspectNode) (bool, error)
+
+	// NodesWithoutEmbedding 尚未計算向量的節點
+	NodesWithoutEmbedding(ctx context.Context, limit int) ([]*entity.AspectNode, error)
+
+	// SaveNodeEmbedding 儲存節點向量
+	SaveNodeEmbedding(ctx context.Context, id int, embedding []float32) error
+
+	// MatchNode 名稱或別名精確命中（不分大小寫）的節點，最近的 class 優先；沒有時回傳 nil
+	MatchNode(ctx context.Context, classID int, raw string) (*entity.AspectNode, error)
+
+	// NearestNode 向量最相近的節點與其 cosine 相似度；沒有已計算向量的節點時回傳 nil
+	NearestNode(ctx context.Context, classID int, embedding []float32) (*entity.AspectNode, float64, error)
+
+	// FindMapping 查原始面向的對應（raw 需已經過 entity.AspectKey），不存在時回傳 nil
+	FindMapping(ctx context.Context, classID int, raw string) (*entity.AspectMapping, error)
+
+	// FindMappingByID 以 ID 查對應，不存在時回傳 nil
+	FindMappingByID(ctx context.Context, id int64) (*entity.AspectMapping, error)
+
+	// SaveMapping 新增對應；已存在時累加 mention_count，只有 pending 的對應會被新結果覆寫
+	SaveMapping(ctx context.Context, m *entity.AspectMapping) error
+
+	// ListMappings 依狀態列出對應（空字串為全部），pending 依提及數排序；回傳總數
+	ListMappings(ctx context.Context, status entity.AspectMappingStatus, offset, limit int) ([]*entity.AspectMapping, int, error)
+
+	// ResolveMapping 人工審核：指定節點（manual）或忽略（ignored）
+	ResolveMapping(ctx context.Context, id int64, nodeID *int, status entity.AspectMappingStatus, reviewer string) error
+
+	// ApplyMapping 將對應寫回 entity_aspects.aspect_node_id（nodeID 為 nil 時清除），回傳更新筆數
+	ApplyMapping(ctx context.Context, classID int, raw string, nodeID *int) (int64, error)
+
+	// UnmappedAspects 尚未對應到節點、也未在審核中的原始面向（依出現次數排序）
+	UnmappedAspects(ctx context.Context, limit int) ([]*entity.RawAspect, error)
+}
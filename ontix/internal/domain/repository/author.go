@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// AuthorRepository 作者登錄儲存庫介面
+type AuthorRepository interface {
+	// Upsert 登錄作者（已存在時更新帳號名稱、追蹤數與最後出現時間，並記錄當日追蹤數快照）
+	// 回傳的 profile 帶有 ID 與既有的 ObjectID
+	Upsert(ctx context.Context, author *entity.AuthorProfile) error
+
+	// LinkObject 將作者連結到 person Entity
+	LinkObject(ctx context.Context, authorID int64, objectID string) error
+
+	// FindByID 查詢作者（含統計），不存在回傳 nil
+	FindByID(ctx context.Context, id int64) (*entity.AuthorProfile, error)
+
+	// List 列出作者（含統計），回傳總數
+	List(ctx context.Context, filter *entity.AuthorFilter, offset, limit int) ([]*entity.AuthorProfile, int, error)
+
+	// FollowerHistory 追蹤數每日快照（由舊到新）
+	FollowerHistory(ctx context.Context, authorID int64, days int) ([]*entity.FollowerSnapshot, error)
+
+	// TopEntities 作者最常提及的 Entity
+	TopEntities(ctx context.Context, authorID int64, limit int) ([]*entity.AuthorAffinity, error)
+
+	// TopTopics 作者貼文最常歸屬的主題
+	TopTopics(ctx context.Context, authorID int64, limit int) ([]*entity.AuthorAffinity, error)
+
+	// RankKOLs 依 Entity 或主題排名作者
+	RankKOLs(ctx context.Context, filter *entity.KOLRankFilter, limit int) ([]*entity.KOLRank, error)
+}
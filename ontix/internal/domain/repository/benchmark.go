@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// BenchmarkRepository 競品集合與比較資料
+type BenchmarkRepository interface {
+	// ExplicitCompetitors 以 competes_with 關係連結的競品（雙向）
+	ExplicitCompetitors(ctx context.Context, objectID string) ([]*entity.Competitor, error)
+
+	// CoMentionedPeers 同類 Entity 依同篇提及數排序（隱含競品）
+	CoMentionedPeers(ctx context.Context, objectID string, filter *entity.CompetitorFilter) ([]*entity.Competitor, error)
+
+	// AddCompetitor 建立人工 competes_with 關係（依兩端 class 選擇品牌或產品的關係類型）
+	// 兩端不是同一種可競爭的 class 時回傳 false
+	AddCompetitor(ctx context.Context, objectID, competitorID string) (bool, error)
+
+	// RemoveCompetitor 刪除兩個 Entity 之間的 competes_with 關係（不分方向），回傳是否有刪除
+	RemoveCompetitor(ctx context.Context, objectID, competitorID string) (bool, error)
+
+	// Totals 比較集合在最近 N 期的觀測彙總（沒有觀測的 Entity 也會回傳，計數為 0）
+	Totals(ctx context.Context, objectIDs []string, periodType string, periods int) ([]*entity.BenchmarkTotals, error)
+
+	// Trend 比較集合在最近 N 期的逐期觀測（由舊到新，ShareOfVoice 由呼叫端計算）
+	Trend(ctx context.Context, objectIDs []string, periodType string, periods int) ([]*entity.BenchmarkPeriod, error)
+
+	// AspectStats 比較集合的面向統計（entity_aspect_stats，只含提及數 >= minCount 的面向）
+	AspectStats(ctx context.Context, objectIDs []string, minCount int) ([]*entity.AspectStat, error)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// ChatSessionRepository Entity 對話 session 儲存庫介面
+type ChatSessionRepository interface {
+	// Create 建立 session（ID 為空時自動產生，並回填 ID / 時間）
+	Create(ctx context.Context, session *entity.ChatSession) error
+
+	// FindByID 查詢 session（不存在時回傳 nil）
+	FindByID(ctx context.Context, id string) (*entity.ChatSession, error)
+
+	// List 列出使用者的 session（依最後更新時間排序），回傳總數
+	List(ctx context.Context, filter entity.ChatSessionFilter) ([]*entity.ChatSession, int, error)
+
+	// Rename 修改 session 標題
+	Rename(ctx context.Context, id, title string) error
+
+	// Delete 刪除 session 與其訊息
+	Delete(ctx context.Context, id string) error
+
+	// AppendMessages 新增訊息，並累計 session 的訊息數與 token 用量
+	AppendMessages(ctx context.Context, sessionID string, messages []*entity.ChatSessionMessage) error
+
+	// ListMessages 列出 ID 大於 afterID 的訊息（依時間排序）
+	ListMessages(ctx context.Context, sessionID string, afterID int64) ([]*entity.ChatSessionMessage, error)
+
+	// SaveSummary 更新較舊對話的摘要，並累計摘要所花的 token 用量
+	SaveSummary(ctx context.Context, sessionID, summary string, summarizedUntil int64, usage entity.TokenUsage) error
+}
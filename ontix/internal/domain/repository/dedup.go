@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// DedupRepository 內容指紋與重複貼文儲存庫介面
+type DedupRepository interface {
+	// FindByContentHash 查詢內容雜湊相同的原始貼文，找不到回傳 nil
+	FindByContentHash(ctx context.Context, hash string) (*entity.PostFingerprint, error)
+
+	// FindCandidates 依 LSH 分桶找出近似重複候選（至少一個 band 相同）
+	FindCandidates(ctx context.Context, bands []int64, limit int) ([]*entity.PostFingerprint, error)
+
+	// SaveFingerprint 儲存原始貼文的指紋與分桶（已存在時略過）
+	SaveFingerprint(ctx context.Context, fp *entity.PostFingerprint, bands []int64) error
+
+	// SaveDuplicate 記錄重複貼文（同一篇重複偵測時更新）
+	SaveDuplicate(ctx context.Context, dup *entity.PostDuplicate) error
+
+	// ListGroups 列出重複群組（依重複數排序），每組附最近 perGroup 筆重複貼文，回傳總組數
+	ListGroups(ctx context.Context, filter *entity.DuplicateFilter, perGroup, offset, limit int) ([]*entity.DuplicateGroup, int, error)
+
+	// GetGroup 取得貼文所屬的重複群組（postID 可為原始貼文或重複貼文），不存在回傳 nil
+	GetGroup(ctx context.Context, postID string) (*entity.DuplicateGroup, error)
+}
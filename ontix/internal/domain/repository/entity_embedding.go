@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// EntityEmbeddingRepository Entity embedding 儲存庫介面
+type EntityEmbeddingRepository interface {
+	// ListStaleProfiles 需要（重新）產生 embedding 的 Entity profile（尚未產生或提及數成長超過 20%）
+	// force 為 true 時不論新舊全部回傳
+	ListStaleProfiles(ctx context.Context, limit int, force bool) ([]*entity.EntityProfile, error)
+
+	// LoadProfile 載入單一 Entity 的 profile
+	LoadProfile(ctx context.Context, objectID string) (*entity.EntityProfile, error)
+
+	// SaveEmbedding 儲存 Entity embedding 與產生當下的提及數
+	SaveEmbedding(ctx context.Context, objectID string, embedding []float32, mentionCount int) error
+
+	// HasEmbedding Entity 是否已有 embedding
+	HasEmbedding(ctx context.Context, objectID string) (bool, error)
+
+	// SearchByEmbedding 以查詢向量搜尋 Entity（objectType 空字串 = 不限類型）
+	SearchByEmbedding(ctx context.Context, embedding []float32, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error)
+
+	// FindSimilar 與指定 Entity 最相似的 Entity（含共同 aspect 與同篇提及次數）
+	FindSimilar(ctx context.Context, objectID string, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// ExportRepository 批次匯出
+type ExportRepository interface {
+	// Stream 逐列讀出資料集並回呼 fn（不會一次載入全部結果）
+	// row 依 entity.ExportColumns(dataset) 的欄位順序，NULL 為 nil；fn 回傳錯誤時中止
+	Stream(ctx context.Context, dataset entity.ExportDataset, filter *entity.ExportFilter, fn func(row []any) error) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// GraphRetrievalRepository Ontology 問答（graph RAG）檢索介面
+type GraphRetrievalRepository interface {
+	// ResolveEntityName 以名稱 / 別名 / 模糊比對找到 Entity（找不到回傳 nil）
+	ResolveEntityName(ctx context.Context, name string) (*entity.AskEntity, error)
+
+	// SentimentShifts Entity 整體與各 aspect 本期 vs 對照期的情感變化
+	SentimentShifts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.SentimentShift, error)
+
+	// AuthorDrivers 本期帶動討論的作者（可限定 aspect / 情感）
+	AuthorDrivers(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.AuthorDriver, error)
+
+	// Facts 期間內未忽略的 derived facts
+	Facts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.AskFact, error)
+
+	// Relations Entity 的關係（object_relations + object_links）
+	Relations(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.RelationEvidence, error)
+
+	// EvidencePosts 本期代表性貼文（可限定 aspect / 情感）
+	EvidencePosts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.PostEvidence, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LLMCacheRepository LLM / Embedding 回應快取（content-addressed）
+type LLMCacheRepository interface {
+	// Get 取得快取值（不存在時 found=false）
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set 寫入快取值（ttl=0 表示不過期）
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
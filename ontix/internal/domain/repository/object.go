@@ -37,6 +37,9 @@ type ObjectRepository interface {
 	// SaveAlias 新增別名
 	SaveAlias(ctx context.Context, alias *entity.ObjectAlias) error
 
+	// ListActiveAliases 列出 active Entity 的所有別名（建立記憶體內別名比對用）
+	ListActiveAliases(ctx context.Context) ([]*entity.ObjectAlias, error)
+
 	// --- Links ---
 
 	// SaveLink 建立 Entity 之間的關係
@@ -60,4 +63,12 @@ type ObjectRepository interface {
 
 	// FindAspectsByObject 查詢某 Entity 的所有 Aspect 評價
 	FindAspectsByObject(ctx context.Context, objectID string) ([]*entity.EntityAspect, error)
+
+	// --- Span 回填 ---
+
+	// ListUnalignedSpans 列出尚未記錄原文位置的提及或面向（kind: mention / aspect，依 id 遞增，afterID 為游標）
+	ListUnalignedSpans(ctx context.Context, kind string, afterID int64, limit int) ([]*entity.SpanAlignTarget, error)
+
+	// SaveSpan 更新提及或面向的原文位置
+	SaveSpan(ctx context.Context, kind string, id int64, span *entity.TextSpan) error
 }
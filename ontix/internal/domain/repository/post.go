@@ -12,4 +12,38 @@ type PostRepository interface {
 	FindByID(ctx context.Context, id string) (*entity.Post, error)
 	FindByIDs(ctx context.Context, ids []string) ([]*entity.Post, error)
 	FindSimilar(ctx context.Context, postID string, limit int) ([]*entity.Post, error)
+
+	// UpdateMetrics 更新互動數並記錄快照（與上一筆快照相同時不記錄）
+	UpdateMetrics(ctx context.Context, postID string, metrics entity.Metrics) error
+
+	// ListMetricsHistory 互動數快照（由新到舊）
+	ListMetricsHistory(ctx context.Context, postID string, limit int) ([]*entity.MetricsSnapshot, error)
+
+	// PrepareReanalysis 內容編輯後重跑前呼叫：記下並清除去重狀態（新內容重新比對），內容與 LLM 結果仍保留
+	PrepareReanalysis(ctx context.Context, postID string) (*entity.ReanalysisMark, error)
+
+	// ReplaceAnalysis 新分析成功後，在同一交易內寫入新內容（post）並以新結果取代舊的 LLM 衍生資料（保留人工校正），
+	// 重算受影響的觀測；analysis、mentions、aspects 為 nil 時只清除舊結果
+	ReplaceAnalysis(ctx context.Context, post *entity.Post, mark *entity.ReanalysisMark, analysis *PostAnalysis,
+		mentions []*entity.PostEntityMention, aspects []*entity.EntityAspect) error
+
+	// RestoreReanalysis 重跑失敗時還原 PrepareReanalysis 之前的去重狀態（內容與分析結果未曾替換）
+	RestoreReanalysis(ctx context.Context, mark *entity.ReanalysisMark) error
+
+	// Delete 刪除貼文及所有衍生資料（mentions、aspects、tags、topics…）並留下 tombstone，
+	// 底下的留言 / 回覆一併刪除；重複群組的原始貼文被刪除時由最早的重複貼文遞補；
+	// 重算受影響的觀測；回傳貼文原本是否存在
+	Delete(ctx context.Context, tombstone *entity.PostTombstone) (bool, error)
+
+	// SaveChunks 以本次切段結果取代貼文既有的 chunk（長文 chunk 向量供搜尋使用）
+	SaveChunks(ctx context.Context, postID string, chunks []*entity.PostChunk) error
+
+	// FindTombstoned 回傳 ids 中已刪除的貼文 ID
+	FindTombstoned(ctx context.Context, ids []string) (map[string]bool, error)
+
+	// ListWithoutLanguage 尚未偵測語言的貼文（依 id 遞增，afterID 之後；只填 ID / PostID / Content）
+	ListWithoutLanguage(ctx context.Context, afterID int64, limit int) ([]*entity.Post, error)
+
+	// UpdateLanguages 寫入偵測到的語言（postID → 語言，無法判斷時寫入空字串）
+	UpdateLanguages(ctx context.Context, languages map[string]entity.Language) error
 }
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// ReportRepository 報表定義與產出紀錄儲存庫介面
+type ReportRepository interface {
+	// CreateDefinition 建立報表定義（回填 ID / 時間）
+	CreateDefinition(ctx context.Context, def *entity.ReportDefinition) error
+
+	// UpdateDefinition 更新報表定義
+	UpdateDefinition(ctx context.Context, def *entity.ReportDefinition) error
+
+	// DeleteDefinition 刪除報表定義與其產出紀錄
+	DeleteDefinition(ctx context.Context, id int64) error
+
+	// FindDefinition 查詢報表定義（不存在時回傳 nil）
+	FindDefinition(ctx context.Context, id int64) (*entity.ReportDefinition, error)
+
+	// ListDefinitions 列出報表定義，回傳總數
+	ListDefinitions(ctx context.Context, offset, limit int) ([]*entity.ReportDefinition, int, error)
+
+	// ClaimDue 原子認領已到排程時間的啟用中報表（next_run_at 暫時推到 leaseUntil，避免多個 worker 重複產出）
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) ([]*entity.ReportDefinition, error)
+
+	// MarkScheduled 記錄最後產出時間與下次排程時間
+	MarkScheduled(ctx context.Context, id int64, lastRunAt time.Time, nextRunAt *time.Time) error
+
+	// CreateRun 建立產出紀錄（狀態 running）
+	CreateRun(ctx context.Context, run *entity.ReportRun) error
+
+	// FinishRun 寫入產出結果（狀態、HTML / PDF、錯誤、寄送對象）
+	FinishRun(ctx context.Context, run *entity.ReportRun) error
+
+	// FindRun 查詢產出紀錄；withContent 時一併載入 HTML / PDF（不存在時回傳 nil）
+	FindRun(ctx context.Context, id int64, withContent bool) (*entity.ReportRun, error)
+
+	// ListRuns 列出報表的產出紀錄（新到舊，不含內容），回傳總數
+	ListRuns(ctx context.Context, definitionID int64, offset, limit int) ([]*entity.ReportRun, int, error)
+}
+
+// ReportDataRepository 報表各章節的資料查詢介面
+type ReportDataRepository interface {
+	// ResolveEntities 展開報表涵蓋的 Entity（指定 ID + class 及其子 class 下提及最多的 Entity）
+	ResolveEntities(ctx context.Context, entityIDs []string, classIDs []int, limit int) ([]*entity.ReportEntity, error)
+
+	// KPIs 本期 vs 上期指標
+	KPIs(ctx context.Context, objectID string, w entity.ReportWindow) (*entity.ReportKPIs, error)
+
+	// SentimentTrend 期間內每週提及 / 情感
+	SentimentTrend(ctx context.Context, objectID string, w entity.ReportWindow) ([]*entity.ReportTrendPoint, error)
+
+	// TopAspects 本期提及最多的面向
+	TopAspects(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportAspect, error)
+
+	// KOLs 本期帶動討論的作者
+	KOLs(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportKOL, error)
+
+	// Facts 本期未忽略的 derived facts（依嚴重程度排序）
+	Facts(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportFact, error)
+
+	// NegativeSamples 本期負面提及片段
+	NegativeSamples(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]string, error)
+}
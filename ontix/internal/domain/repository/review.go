@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+var (
+	// ErrReviewItemNotFound 審核項目不存在
+	ErrReviewItemNotFound = errors.New("review item not found")
+	// ErrReviewItemClosed 審核項目已審核或已略過（不可重複校正）
+	ErrReviewItemClosed = errors.New("review item already closed")
+)
+
+// ReviewRepository 人工審核佇列儲存庫介面
+type ReviewRepository interface {
+	// Enqueue 加入審核佇列（同一篇貼文同一原因只保留一筆，pending 時更新 detail/priority）
+	Enqueue(ctx context.Context, item *entity.ReviewItem) error
+
+	// FindByID 查詢單一審核項目（含貼文內容）
+	FindByID(ctx context.Context, id int64) (*entity.ReviewItem, error)
+
+	// List 依狀態 / 原因列出審核項目（依 priority 排序），回傳總數
+	List(ctx context.Context, status entity.ReviewStatus, reason entity.ReviewReason, offset, limit int) ([]*entity.ReviewItem, int, error)
+
+	// CountByReason 各原因的 pending 數量
+	CountByReason(ctx context.Context) (map[entity.ReviewReason]int, error)
+
+	// Resolve 套用校正（source=manual）並標記為已審核，整個過程在同一交易內完成
+	// 項目不存在回傳 ErrReviewItemNotFound，非 pending 回傳 ErrReviewItemClosed
+	Resolve(ctx context.Context, id int64, correction *entity.ReviewCorrection) error
+
+	// Skip 略過 pending 的審核項目（錯誤同 Resolve）
+	Skip(ctx context.Context, id int64, reviewer string) error
+
+	// ListFewShotExamples 取得最近的人工校正範例（供 LLM few-shot）
+	ListFewShotExamples(ctx context.Context, field entity.CorrectionField, limit int) ([]*entity.FewShotExample, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// SearchRepository 貼文混合搜尋儲存庫介面
+type SearchRepository interface {
+	// HybridSearch 向量 + 關鍵字混合搜尋，回傳一頁結果（含總數與 facet）
+	HybridSearch(ctx context.Context, q *entity.SearchQuery) (*entity.SearchPage, error)
+
+	// NearestTopics 與查詢向量最接近的主題（語意鄰居）
+	NearestTopics(ctx context.Context, embedding []float32, limit int) ([]*entity.TopicNeighbor, error)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// SentimentScoreRow 待重新計分的一筆提及 / 面向
+type SentimentScoreRow struct {
+	ID        int64
+	Raw       float64
+	Sentiment string // 目前的標籤（重新計分後依分數重新判定）
+	CreatedAt time.Time
+}
+
+// SentimentCalibrationRepository 情緒分數校準與重新計分
+// kind 為 mention（post_entity_mentions，只含 source=llm）或 aspect（entity_aspects）
+type SentimentCalibrationRepository interface {
+	// ListSamples 人工校正過、或審核時確認無誤（未修改）且保留 LLM 原始分數與模型的提及（Target 已由標籤 / 分數換算）
+	ListSamples(ctx context.Context) ([]*entity.SentimentSample, error)
+
+	// SaveCalibration 新增校準並停用同一 model / prompt_version / language 的舊校準，回填 ID
+	SaveCalibration(ctx context.Context, cal *entity.SentimentCalibration) error
+
+	// ListActive 所有啟用中的校準
+	ListActive(ctx context.Context) ([]*entity.SentimentCalibration, error)
+
+	// ListStale 某 model / prompt_version 下尚未套用 calibrationID 的資料（依 id 遞增，afterID 之後）
+	// language 非空時只含該語言貼文的資料；excludeLanguages 排除已有語言專屬校準的語言
+	ListStale(ctx context.Context, kind, model, promptVersion, language string, excludeLanguages []string, calibrationID int64, afterID int64, limit int) ([]*SentimentScoreRow, error)
+
+	// UpdateScores 寫入校準後分數、依分數重新判定的標籤與 calibrationID
+	UpdateScores(ctx context.Context, kind string, ids []int64, scores []float64, labels []string, calibrationID int64) error
+}
@@ -16,9 +16,18 @@ type TopicRepository interface {
 
 	// 貼文-主題關聯
 	SavePostTopic(ctx context.Context, pt *entity.PostTopic) error
+	ReplacePostTopics(ctx context.Context, postID int64, pts []*entity.PostTopic) error
 	FindPostTopics(ctx context.Context, postID int64) ([]*entity.PostTopic, error)
 	FindTopicPosts(ctx context.Context, topicID int, limit int, offset int) ([]int64, error)
 
 	// 統計
 	GetTopicStats(ctx context.Context) (map[string]int, error)
+
+	// 人工標註（gold label）
+	SaveTopicLabel(ctx context.Context, label *entity.TopicLabel) error
+	ListTopicLabels(ctx context.Context) ([]*entity.TopicLabel, error)
+
+	// 機率校準
+	SaveCalibration(ctx context.Context, cal *entity.TopicCalibration) error
+	ListCalibrations(ctx context.Context) ([]*entity.TopicCalibration, error)
 }
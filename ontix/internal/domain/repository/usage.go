@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// UsageRepository LLM 用量記帳
+type UsageRepository interface {
+	// Record 記錄一次 LLM / Embedding 呼叫
+	Record(ctx context.Context, usage *entity.LLMUsage) error
+	// CostSince 取得 tenant 自某時間點起的累計成本（tenant 空字串 = 全部）
+	CostSince(ctx context.Context, tenant string, since time.Time) (float64, error)
+	// Summarize 依條件彙總用量
+	Summarize(ctx context.Context, filter entity.UsageFilter) ([]*entity.UsageSummary, error)
+}
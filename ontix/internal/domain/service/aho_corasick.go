@@ -0,0 +1,129 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// AhoCorasick 多模式字串比對（一次掃描找出文字中出現的所有別名）
+// 以 rune 為單位、不分大小寫；英數別名要求前後為詞邊界（避免 "AI" 命中 "SAID"）
+type AhoCorasick struct {
+	nodes    []acNode
+	patterns []string
+	lengths  []int // pattern 的 rune 長度
+}
+
+type acNode struct {
+	next   map[rune]int32
+	fail   int32
+	output []int32 // 以此節點結尾的 pattern（含 fail 鏈上的）
+}
+
+// ACMatch 比對結果（Start / End 為 rune 位置，End 不含）
+type ACMatch struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+// NewAhoCorasick 以 patterns 建立自動機（空字串略過）
+func NewAhoCorasick(patterns []string) *AhoCorasick {
+	ac := &AhoCorasick{nodes: []acNode{{}}, patterns: patterns, lengths: make([]int, len(patterns))}
+
+	for i, p := range patterns {
+		runes := foldRunes(strings.TrimSpace(p))
+		if len(runes) == 0 {
+			continue
+		}
+		ac.lengths[i] = len(runes)
+		cur := int32(0)
+		for _, r := range runes {
+			nxt, ok := ac.nodes[cur].next[r]
+			if !ok {
+				if ac.nodes[cur].next == nil {
+					ac.nodes[cur].next = make(map[rune]int32)
+				}
+				ac.nodes = append(ac.nodes, acNode{})
+				nxt = int32(len(ac.nodes) - 1)
+				ac.nodes[cur].next[r] = nxt
+			}
+			cur = nxt
+		}
+		ac.nodes[cur].output = append(ac.nodes[cur].output, int32(i))
+	}
+
+	// BFS 建 fail link
+	queue := make([]int32, 0, len(ac.nodes))
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range ac.nodes[cur].next {
+			f := ac.nodes[cur].fail
+			for f != 0 && !ac.has(f, r) {
+				f = ac.nodes[f].fail
+			}
+			if nxt, ok := ac.nodes[f].next[r]; ok && nxt != child {
+				ac.nodes[child].fail = nxt
+			}
+			ac.nodes[child].output = append(ac.nodes[child].output, ac.nodes[ac.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+	return ac
+}
+
+func (ac *AhoCorasick) has(node int32, r rune) bool {
+	_, ok := ac.nodes[node].next[r]
+	return ok
+}
+
+// Len pattern 數量
+func (ac *AhoCorasick) Len() int {
+	return len(ac.patterns)
+}
+
+// FindAll 找出 text 中所有（可重疊的）pattern 出現位置
+func (ac *AhoCorasick) FindAll(text string) []ACMatch {
+	runes := foldRunes(text)
+	var matches []ACMatch
+	cur := int32(0)
+	for i, r := range runes {
+		for cur != 0 && !ac.has(cur, r) {
+			cur = ac.nodes[cur].fail
+		}
+		if nxt, ok := ac.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+		for _, p := range ac.nodes[cur].output {
+			start := i + 1 - ac.lengths[p]
+			if !atWordBoundary(runes, start, i+1) {
+				continue
+			}
+			matches = append(matches, ACMatch{Pattern: int(p), Start: start, End: i + 1})
+		}
+	}
+	return matches
+}
+
+// foldRunes 逐字轉小寫（不用 strings.ToLower，確保 rune 位置與原文一致）
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// atWordBoundary 拉丁字母 / 數字開頭或結尾的命中，前後不能緊接拉丁字母 / 數字（中日文無空白分詞，不檢查）
+func atWordBoundary(runes []rune, start, end int) bool {
+	if isLatinWordRune(runes[start]) && start > 0 && isLatinWordRune(runes[start-1]) {
+		return false
+	}
+	if isLatinWordRune(runes[end-1]) && end < len(runes) && isLatinWordRune(runes[end]) {
+		return false
+	}
+	return true
+}
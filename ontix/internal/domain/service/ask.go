@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// 問答檢索項目（AskPlan.Retrieve）
+const (
+	AskRetrieveSentiment = "sentiment" // 情感 / aspect 變化
+	AskRetrieveKOLs      = "kols"      // 帶動討論的作者
+	AskRetrieveFacts     = "facts"     // 推理引擎 alerts / trends / insights
+	AskRetrieveRelations = "relations" // Entity 關係
+	AskRetrievePosts     = "posts"     // 代表性貼文
+)
+
+// askEntityTypes 提供給規劃 prompt 的 Entity 類型
+var askEntityTypes = []string{
+	string(entity.ObjectTypeBrand), string(entity.ObjectTypeProduct), string(entity.ObjectTypePlace),
+	string(entity.ObjectTypePerson), string(entity.ObjectTypeWork), string(entity.ObjectTypeEvent),
+	string(entity.ObjectTypeOrganization), string(entity.ObjectTypeContentTopic),
+}
+
+// askSemanticMinSimilarity 以描述語意找 Entity 時的最低相似度
+const askSemanticMinSimilarity = 0.35
+
+// ErrInsufficientData 檢索不到足以回答問題的資料
+var ErrInsufficientData = errors.New("insufficient data to answer")
+
+// AskPlan LLM 依問題規劃的檢索計畫
+type AskPlan struct {
+	Entities    []string `json:"entities"`     // 問題中點名的 Entity（品牌、產品、KOL...）
+	EntityTypes []string `json:"entity_types"` // 問題涉及的 Entity 類型（brand / product / person ...）
+	Category    string   `json:"category"`     // 未點名時用來語意搜尋 Entity 的描述（例：保養品牌）
+	Aspects     []string `json:"aspects"`      // 問題關注的面向（例：價格）
+	Sentiment   string   `json:"sentiment"`    // positive / negative / 空字串
+	Period      string   `json:"period"`       // 1w / 4w / 12w
+	Retrieve    []string `json:"retrieve"`     // 要檢索的項目（Ask Retrieve 常數）
+}
+
+// Wants 計畫是否包含某檢索項目（未指定時全部檢索）
+func (p *AskPlan) Wants(item string) bool {
+	if len(p.Retrieve) == 0 {
+		return true
+	}
+	for _, r := range p.Retrieve {
+		if r == item {
+			return true
+		}
+	}
+	return false
+}
+
+// AskAnswerRequest 產生回答的請求
+type AskAnswerRequest struct {
+	Question string
+	Evidence string // FormatAskEvidence 產生的證據文字（含引用標記）
+}
+
+// AskLLMService 問答所需的 LLM 能力：規劃檢索、串流回答
+type AskLLMService interface {
+	PlanAskQuery(ctx context.Context, question string, entityTypes []string) (*AskPlan, error)
+	StreamAskAnswer(ctx context.Context, req *AskAnswerRequest) (<-chan string, <-chan error)
+}
+
+// AskService Ontology 問答（graph RAG）：規劃 → 檢索 → 串流回答（附引用）
+type AskService struct {
+	llm      AskLLMService
+	repo     repository.GraphRetrievalRepository
+	embedder *EntityEmbedder
+	limit    int
+}
+
+// NewAskService 建立 AskService
+func NewAskService(llm AskLLMService, repo repository.GraphRetrievalRepository, embedder *EntityEmbedder) *AskService {
+	return &AskService{llm: llm, repo: repo, embedder: embedder, limit: 10}
+}
+
+// Plan 讓 LLM 規劃檢索計畫
+func (s *AskService) Plan(ctx context.Context, question string) (*AskPlan, error) {
+	plan, err := s.llm.PlanAskQuery(ctx, question, askEntityTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan question: %w", err)
+	}
+	if parsePeriodDays(plan.Period) == 0 {
+		plan.Period = "4w"
+	}
+	return plan, nil
+}
+
+// Retrieve 依計畫執行檢索
+func (s *AskService) Retrieve(ctx context.Context, question string, plan *AskPlan) (*entity.AskEvidence, error) {
+	now := time.Now()
+	days := parsePeriodDays(plan.Period)
+	if days == 0 {
+		plan.Period, days = "4w", 28
+	}
+	window := entity.AskWindow{
+		To:       now,
+		From:     now.AddDate(0, 0, -days),
+		PrevFrom: now.AddDate(0, 0, -2*days),
+	}
+	ev := &entity.AskEvidence{Window: window}
+
+	// 1. 找出問題涉及的 Entity：點名的名稱 → 描述語意搜尋
+	seen := make(map[string]bool)
+	for _, name := range plan.Entities {
+		e, err := s.repo.ResolveEntityName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil && !seen[e.ID] {
+			seen[e.ID] = true
+			ev.Entities = append(ev.Entities, e)
+		}
+	}
+	if len(ev.Entities) == 0 && s.embedder != nil {
+		query := plan.Category
+		if query == "" {
+			query = question
+		}
+		types := plan.EntityTypes
+		if len(types) == 0 {
+			types = []string{""}
+		}
+		for _, t := range types {
+			matches, err := s.embedder.Search(ctx, query, entity.ObjectType(t), s.limit)
+			if err != nil {
+				log.Printf("[ask] semantic entity search failed: %v", err)
+				break
+			}
+			for _, m := range matches {
+				if m.Similarity < askSemanticMinSimilarity || seen[m.ID] {
+					continue
+				}
+				seen[m.ID] = true
+				ev.Entities = append(ev.Entities, &entity.AskEntity{ID: m.ID, Name: m.CanonicalName, Type: m.Type, Match: "semantic"})
+			}
+		}
+	}
+	if len(ev.Entities) == 0 {
+		return ev, nil
+	}
+
+	ids := make([]string, len(ev.Entities))
+	for i, e := range ev.Entities {
+		ids[i] = e.ID
+	}
+	q := &entity.AskEvidenceQuery{
+		EntityIDs: ids,
+		Aspects:   plan.Aspects,
+		Sentiment: plan.Sentiment,
+		Window:    window,
+		Limit:     s.limit,
+	}
+
+	// 2. 依計畫檢索
+	var err error
+	if plan.Wants(AskRetrieveSentiment) {
+		shiftQuery := *q
+		shiftQuery.Limit = s.limit * 3
+		if ev.Shifts, err = s.repo.SentimentShifts(ctx, &shiftQuery); err != nil {
+			return nil, err
+		}
+	}
+	if plan.Wants(AskRetrieveKOLs) {
+		if ev.Drivers, err = s.repo.AuthorDrivers(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	if plan.Wants(AskRetrieveFacts) {
+		if ev.Facts, err = s.repo.Facts(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	if plan.Wants(AskRetrieveRelations) {
+		if ev.Relations, err = s.repo.Relations(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	// 貼文是引用的主要來源，一律檢索
+	if ev.Posts, err = s.repo.EvidencePosts(ctx, q); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// Sufficient 證據是否足以回答：至少要有 Entity，且本期有提及、貼文或 fact
+func Sufficient(ev *entity.AskEvidence) bool {
+	if len(ev.Entities) == 0 {
+		return false
+	}
+	if len(ev.Posts) > 0 || len(ev.Facts) > 0 {
+		return true
+	}
+	for _, s := range ev.Shifts {
+		if s.CurTotal > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Answer 串流回答；證據不足時回傳 ErrInsufficientData（不呼叫 LLM）
+func (s *AskService) Answer(ctx context.Context, question string, ev *entity.AskEvidence) (<-chan string, <-chan error, error) {
+	if !Sufficient(ev) {
+		return nil, nil, ErrInsufficientData
+	}
+	tokenCh, errCh := s.llm.StreamAskAnswer(ctx, &AskAnswerRequest{
+		Question: question,
+		Evidence: FormatAskEvidence(ev),
+	})
+	return tokenCh, errCh, nil
+}
+
+// FormatAskEvidence 將證據整理成 prompt 文字；可引用的來源標記為 [P:post_id]、[F:fact_id]、[E:entity_id]
+func FormatAskEvidence(ev *entity.AskEvidence) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "【時間範圍】本期 %s ~ %s，對照期 %s ~ %s\n",
+		ev.Window.From.Format("2006-01-02"), ev.Window.To.Format("2006-01-02"),
+		ev.Window.PrevFrom.Format("2006-01-02"), ev.Window.From.Format("2006-01-02"))
+
+	b.WriteString("【Entity】\n")
+	for _, e := range ev.Entities {
+		fmt.Fprintf(&b, "- [E:%s] %s (%s)\n", e.ID, e.Name, e.Type)
+	}
+
+	if len(ev.Shifts) > 0 {
+		b.WriteString("【情感變化】（負面數/提及數，平均情感分數 0~1）\n")
+		for _, s := range ev.Shifts {
+			target := s.EntityName
+			if s.Aspect != "" {
+				target += " / " + s.Aspect
+			}
+			fmt.Fprintf(&b, "- [E:%s] %s：本期 %d/%d (%.2f)，對照期 %d/%d (%.2f)，負面比例變化 %+.1f 個百分點\n",
+				s.EntityID, target, s.CurNegative, s.CurTotal, s.CurAvgScore,
+				s.PrevNegative, s.PrevTotal, s.PrevAvgScore, s.NegativeRatioDelta())
+		}
+	}
+
+	if len(ev.Drivers) > 0 {
+		b.WriteString("【帶動討論的作者】\n")
+		for _, d := range ev.Drivers {
+			refs := make([]string, len(d.PostIDs))
+			for i, id := range d.PostIDs {
+				refs[i] = "[P:" + id + "]"
+			}
+			fmt.Fprintf(&b, "- %s（粉絲 %d）談論 %s %d 篇，其中負面 %d 篇 %s\n",
+				d.Author, d.Followers, d.EntityName, d.Posts, d.Negative, strings.Join(refs, ""))
+		}
+	}
+
+	if len(ev.Facts) > 0 {
+		b.WriteString("【推理引擎 Alerts / Trends / Insights】\n")
+		for _, f := range ev.Facts {
+			fmt.Fprintf(&b, "- [F:%d] %s [%s/%s] %s：%s\n", f.ID, f.EntityName, f.FactType, f.Severity, f.Title, f.Description)
+		}
+	}
+
+	if len(ev.Relations) > 0 {
+		b.WriteString("【關係】\n")
+		for _, r := range ev.Relations {
+			fmt.Fprintf(&b, "- %s —%s→ [E:%s] %s (%s)\n", r.SourceName, r.Relation, r.TargetID, r.TargetName, r.TargetType)
+		}
+	}
+
+	if len(ev.Posts) > 0 {
+		b.WriteString("【代表性貼文】\n")
+		for _, p := range ev.Posts {
+			fmt.Fprintf(&b, "- [P:%s] %s（%s，%s，@%s）「%s」\n",
+				p.PostID, p.EntityName, p.Sentiment, p.CreatedAt.Format("2006-01-02"), p.Author,
+				strings.Join(strings.Fields(p.Snippet), " "))
+		}
+	}
+	return b.String()
+}
+
+// ExtractCitations 從回答中找出引用標記，只保留證據中確實存在的來源
+func ExtractCitations(answer string, ev *entity.AskEvidence) []entity.Citation {
+	sources := make(CitationSources)
+	for _, e := range ev.Entities {
+		sources.Add(entity.CitationEntity, e.ID, e.Name)
+	}
+	for _, r := range ev.Relations {
+		sources.Add(entity.CitationEntity, r.TargetID, r.TargetName)
+	}
+	for _, s := range ev.Shifts {
+		sources.Add(entity.CitationEntity, s.EntityID, s.EntityName)
+	}
+	for _, f := range ev.Facts {
+		sources.Add(entity.CitationFact, strconv.FormatInt(f.ID, 10), f.Title)
+	}
+	for _, p := range ev.Posts {
+		sources.Add(entity.CitationPost, p.PostID, TruncateRunes(p.Snippet, 60))
+	}
+	for _, d := range ev.Drivers {
+		for _, id := range d.PostIDs {
+			if !sources.Has(entity.CitationPost, id) {
+				sources.Add(entity.CitationPost, id, "@"+d.Author)
+			}
+		}
+	}
+	return sources.Extract(answer)
+}
+
+// parsePeriodDays 1w / 4w / 12w → 天數（無法解析回傳 0）
+func parsePeriodDays(period string) int {
+	if !strings.HasSuffix(period, "w") {
+		return 0
+	}
+	weeks, err := strconv.Atoi(strings.TrimSuffix(period, "w"))
+	if err != nil || weeks <= 0 || weeks > 52 {
+		return 0
+	}
+	return weeks * 7
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+var (
+	// ErrInvalidAspectNode 節點名稱為空、父節點不存在或不屬於同一條 class 鏈、或同 class 已有同名節點
+	ErrInvalidAspectNode = errors.New("invalid aspect node")
+	// ErrAspectMappingNotFound 對應不存在
+	ErrAspectMappingNotFound = errors.New("aspect mapping not found")
+)
+
+const (
+	defaultAspectAutoThreshold = 0.85
+	aspectEmbedBatchSize       = 64
+	// aspectCacheTTL 已定案對應的快取時間（其他程序的審核結果最慢在此時間後生效）
+	aspectCacheTTL = 10 * time.Minute
+	// aspectCacheMaxEntries 快取上限，超過時先清除過期項目，仍超過則整個清空
+	aspectCacheMaxEntries = 50000
+)
+
+// AspectTaxonomy 原始面向 → 正規節點
+//
+// 對應順序：既有對應 → 名稱 / 別名精確命中 → embedding 最相近節點（相似度達門檻才自動對應）
+// 未達門檻的面向記為 pending 進入審核佇列（附最相近的建議節點），審核前 aspect_node_id 留空
+type AspectTaxonomy struct {
+	repo          repository.AspectTaxonomyRepository
+	embedSvc      EmbeddingService // 可為 nil（只做精確命中）
+	autoThreshold float64
+
+	mu    sync.RWMutex
+	cache map[string]aspectCacheEntry // class:raw → node（只快取已定案的對應，pending 每次重查以便審核後生效）
+}
+
+// aspectCacheEntry 快取的對應結果與到期時間
+type aspectCacheEntry struct {
+	nodeID  *int
+	expires time.Time
+}
+
+// NewAspectTaxonomy 建立 AspectTaxonomy（autoThreshold <= 0 時使用預設 0.85）
+func NewAspectTaxonomy(repo repository.AspectTaxonomyRepository, embedSvc EmbeddingService, autoThreshold float64) *AspectTaxonomy {
+	if autoThreshold <= 0 {
+		autoThreshold = defaultAspectAutoThreshold
+	}
+	return &AspectTaxonomy{
+		repo:          repo,
+		embedSvc:      embedSvc,
+		autoThreshold: autoThreshold,
+		cache:         make(map[string]aspectCacheEntry),
+	}
+}
+
+// AutoThreshold 自動對應的相似度門檻
+func (t *AspectTaxonomy) AutoThreshold() float64 {
+	return t.autoThreshold
+}
+
+// Resolve 原始面向對應的節點 ID（classID 為 nil 時視為根類別），待審核或忽略的面向回傳 nil
+func (t *AspectTaxonomy) Resolve(ctx context.Context, classID *int, raw string) (*int, error) {
+	cls := entity.AspectRootClassID
+	if classID != nil {
+		cls = *classID
+	}
+	key := entity.AspectKey(raw)
+	if key == "" {
+		return nil, nil
+	}
+	m, err := t.resolve(ctx, cls, key, 1)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return m.NodeID, nil
+}
+
+// resolve 查快取 / 既有對應，沒有時分類並寫入對應（count 為這次遇到的面向數）
+func (t *AspectTaxonomy) resolve(ctx context.Context, classID int, key string, count int) (*entity.AspectMapping, error) {
+	cacheKey := fmt.Sprintf("%d:%s", classID, key)
+	t.mu.RLock()
+	cached, ok := t.cache[cacheKey]
+	t.mu.RUnlock()
+	if ok && time.Now().Before(cached.expires) {
+		return &entity.AspectMapping{ClassID: classID, RawAspect: key, NodeID: cached.nodeID}, nil
+	}
+
+	m, err := t.repo.FindMapping(ctx, classID, key)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		if m, err = t.classify(ctx, classID, key); err != nil {
+			return nil, err
+		}
+	}
+	if m.ID == 0 || m.Status == entity.AspectMappingPending {
+		// 新對應，或待審核的面向再次出現（累加提及數，審核依此排序）
+		m.MentionCount = count
+		if err := t.repo.SaveMapping(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Status != entity.AspectMappingPending {
+		t.remember(cacheKey, m.NodeID)
+	}
+	return m, nil
+}
+
+// remember 快取已定案的對應（超過上限時先清除過期項目）
+func (t *AspectTaxonomy) remember(cacheKey string, nodeID *int) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.cache) >= aspectCacheMaxEntries {
+		for k, e := range t.cache {
+			if !now.Before(e.expires) {
+				delete(t.cache, k)
+			}
+		}
+		if len(t.cache) >= aspectCacheMaxEntries {
+			t.cache = make(map[string]aspectCacheEntry)
+		}
+	}
+	t.cache[cacheKey] = aspectCacheEntry{nodeID: nodeID, expires: now.Add(aspectCacheTTL)}
+}
+
+// classify 為新的原始面向找節點：精確命中 → embedding 最相近
+func (t *AspectTaxonomy) classify(ctx context.Context, classID int, key string) (*entity.AspectMapping, error) {
+	m := &entity.AspectMapping{ClassID: classID, RawAspect: key, Status: entity.AspectMappingPending}
+
+	node, err := t.repo.MatchNode(ctx, classID, key)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil {
+		exact := 1.0
+		m.NodeID, m.Similarity, m.Status = &node.ID, &exact, entity.AspectMappingAuto
+		return m, nil
+	}
+	if t.embedSvc == nil {
+		return m, nil
+	}
+
+	emb, err := t.embedSvc.Embed(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed aspect %q: %w", key, err)
+	}
+	node, similarity, err := t.repo.NearestNode(ctx, classID, emb)
+	if err != nil || node == nil {
+		return m, err
+	}
+	m.Similarity = &similarity
+	m.SuggestedNodeID = &node.ID
+	if similarity >= t.autoThreshold {
+		m.NodeID, m.Status = &node.ID, entity.AspectMappingAuto
+	}
+	return m, nil
+}
+
+// Nodes 某 class 可用的面向樹（含祖先 class 的節點），classID <= 0 時回傳全部
+func (t *AspectTaxonomy) Nodes(ctx context.Context, classID int) ([]*entity.AspectNode, error) {
+	nodes, err := t.repo.ListNodes(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	return buildAspectTree(nodes), nil
+}
+
+// CreateNode 新增節點（父節點須屬於同一 class 或其祖先 class），有 embedding 服務時一併計算向量
+func (t *AspectTaxonomy) CreateNode(ctx context.Context, node *entity.AspectNode) error {
+	node.Name = strings.TrimSpace(node.Name)
+	if node.Name == "" || node.ClassID <= 0 {
+		return ErrInvalidAspectNode
+	}
+	if node.ParentID != nil {
+		ok, err := t.nodeAvailable(ctx, node.ClassID, *node.ParentID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidAspectNode
+		}
+	}
+
+	created, err := t.repo.SaveNode(ctx, node)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrInvalidAspectNode
+	}
+	if t.embedSvc != nil {
+		if emb, err := t.embedSvc.Embed(ctx, aspectNodeText(node)); err == nil {
+			_ = t.repo.SaveNodeEmbedding(ctx, node.ID, emb)
+		}
+	}
+	return nil
+}
+
+// nodeAvailable 節點是否屬於該 class 或其祖先 class
+func (t *AspectTaxonomy) nodeAvailable(ctx context.Context, classID, nodeID int) (bool, error) {
+	nodes, err := t.repo.ListNodes(ctx, classID)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range nodes {
+		if n.ID == nodeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Mappings 依狀態列出對應（審核佇列為 pending）
+func (t *AspectTaxonomy) Mappings(ctx context.Context, status entity.AspectMappingStatus, offset, limit int) ([]*entity.AspectMapping, int, error) {
+	return t.repo.ListMappings(ctx, status, offset, limit)
+}
+
+// ResolveMapping 人工審核：nodeID 為 nil 時忽略該面向；結果立即寫回既有的 entity_aspects
+func (t *AspectTaxonomy) ResolveMapping(ctx context.Context, id int64, nodeID *int, reviewer string) (*entity.AspectMapping, int64, error) {
+	m, err := t.repo.FindMappingByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if m == nil {
+		return nil, 0, ErrAspectMappingNotFound
+	}
+
+	status := entity.AspectMappingIgnored
+	if nodeID != nil {
+		ok, err := t.nodeAvailable(ctx, m.ClassID, *nodeID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			return nil, 0, ErrInvalidAspectNode
+		}
+		status = entity.AspectMappingManual
+	}
+
+	if err := t.repo.ResolveMapping(ctx, id, nodeID, status, reviewer); err != nil {
+		return nil, 0, err
+	}
+	applied, err := t.repo.ApplyMapping(ctx, m.ClassID, m.RawAspect, nodeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	t.remember(fmt.Sprintf("%d:%s", m.ClassID, m.RawAspect), nodeID)
+
+	m, err = t.repo.FindMappingByID(ctx, id)
+	return m, applied, err
+}
+
+// EmbedNodes 為尚未計算向量的節點計算向量（名稱 + 別名），回傳處理數
+func (t *AspectTaxonomy) EmbedNodes(ctx context.Context) (int, error) {
+	if t.embedSvc == nil {
+		return 0, nil
+	}
+	done := 0
+	for {
+		nodes, err := t.repo.NodesWithoutEmbedding(ctx, aspectEmbedBatchSize)
+		if err != nil {
+			return done, err
+		}
+		if len(nodes) == 0 {
+			return done, nil
+		}
+		texts := make([]string, len(nodes))
+		for i, n := range nodes {
+			texts[i] = aspectNodeText(n)
+		}
+		embs, err := t.embedSvc.BatchEmbed(ctx, texts)
+		if err != nil {
+			return done, fmt.Errorf("failed to embed aspect nodes: %w", err)
+		}
+		for i, n := range nodes {
+			if err := t.repo.SaveNodeEmbedding(ctx, n.ID, embs[i]); err != nil {
+				return done, err
+			}
+			done++
+		}
+	}
+}
+
+// AspectBackfillResult 回填結果
+type AspectBackfillResult struct {
+	Aspects int   // 處理的原始面向數
+	Mapped  int   // 對應到節點的原始面向數
+	Pending int   // 排入審核的原始面向數
+	Rows    int64 // 更新的 entity_aspects 筆數
+}
+
+// Backfill 對既有 entity_aspects 中尚未對應的原始面向做對應並寫回
+func (t *AspectTaxonomy) Backfill(ctx context.Context, limit int) (*AspectBackfillResult, error) {
+	res := &AspectBackfillResult{}
+	raws, err := t.repo.UnmappedAspects(ctx, limit)
+	if err != nil {
+		return res, err
+	}
+	for _, raw := range raws {
+		res.Aspects++
+		m, err := t.resolve(ctx, raw.ClassID, raw.RawAspect, raw.Count)
+		if err != nil {
+			return res, err
+		}
+		if m.NodeID == nil {
+			if m.Status == entity.AspectMappingPending {
+				res.Pending++
+			}
+			continue
+		}
+		n, err := t.repo.ApplyMapping(ctx, raw.ClassID, raw.RawAspect, m.NodeID)
+		if err != nil {
+			return res, err
+		}
+		res.Mapped++
+		res.Rows += n
+	}
+	return res, nil
+}
+
+// aspectNodeText 節點的向量文字：路徑 + 別名（「餐點 > 薯條（fries）」）
+func aspectNodeText(n *entity.AspectNode) string {
+	text := strings.Join(n.Path, " > ")
+	if text == "" {
+		text = n.Name
+	}
+	if len(n.Synonyms) > 0 {
+		text += "（" + strings.Join(n.Synonyms, "、") + "）"
+	}
+	return text
+}
+
+// buildAspectTree 將節點列表組成樹（父節點不在列表中的節點視為根）
+func buildAspectTree(nodes []*entity.AspectNode) []*entity.AspectNode {
+	byID := make(map[int]*entity.AspectNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+	roots := make([]*entity.AspectNode, 0)
+	for _, n := range nodes {
+		if n.ParentID != nil {
+			if parent, ok := byID[*n.ParentID]; ok {
+				parent.Children = append(parent.Children, n)
+				continue
+			}
+		}
+		roots = append(roots, n)
+	}
+	return roots
+}
@@ -181,9 +181,10 @@ func (a *Assigner) validateTagsWithCluster(tags *PostTags, cluster *entity.Clust
 	// 建立 cluster 關鍵詞集合（名稱 + keywords）
 	clusterTerms := make(map[string]bool)
 
-	// 加入 cluster 名稱的每個字詞
-	for _, word := range splitChineseWords(cluster.Name) {
-		clusterTerms[strings.ToLower(word)] = true
+	// 加入 cluster 名稱與其中的字詞
+	clusterTerms[strings.ToLower(cluster.Name)] = true
+	for _, word := range Tokenize(cluster.Name, DetectLanguage(cluster.Name)) {
+		clusterTerms[word] = true
 	}
 
 	// 加入 cluster keywords
@@ -212,30 +213,6 @@ func (a *Assigner) validateTagsWithCluster(tags *PostTags, cluster *entity.Clust
 	return false
 }
 
-// splitChineseWords 簡單的中文分詞（按常見詞長度切分）
-func splitChineseWords(s string) []string {
-	words := []string{s} // 先加入完整字串
-
-	runes := []rune(s)
-	if len(runes) <= 2 {
-		return words
-	}
-
-	// 2-gram
-	for i := 0; i < len(runes)-1; i++ {
-		words = append(words, string(runes[i:i+2]))
-	}
-
-	// 3-gram (如果長度足夠)
-	if len(runes) >= 3 {
-		for i := 0; i < len(runes)-2; i++ {
-			words = append(words, string(runes[i:i+3]))
-		}
-	}
-
-	return words
-}
-
 // AssignBatch 批次分配（不帶 tag 驗證）
 func (a *Assigner) AssignBatch(ctx context.Context, embeddings []entity.Vector) ([]*AssignResult, error) {
 	tagsList := make([]*PostTags, len(embeddings))
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// AuthorRegistry 作者登錄：匯入貼文時登記作者、記錄追蹤數，並連結 person Entity
+// 連結規則：
+//   - 帳號名稱已是某個 person Entity 的正規名稱或別名 → 直接連結
+//   - 找不到且追蹤數達 KOL 門檻 → 建立 person Entity（別名含 @帳號）後連結
+//   - 名稱屬於其他類型的 Entity（品牌官方帳號等）→ 不連結
+type AuthorRegistry struct {
+	authorRepo   repository.AuthorRepository
+	objectRepo   repository.ObjectRepository
+	minFollowers int // 自動建立 person Entity 的追蹤數門檻（< 0 不自動建立）
+}
+
+// NewAuthorRegistry 建立 AuthorRegistry（minFollowers 為 0 使用 entity.KOLMinFollowers，負數停用自動建立）
+func NewAuthorRegistry(authorRepo repository.AuthorRepository, objectRepo repository.ObjectRepository, minFollowers int) *AuthorRegistry {
+	if minFollowers == 0 {
+		minFollowers = entity.KOLMinFollowers
+	}
+	return &AuthorRegistry{authorRepo: authorRepo, objectRepo: objectRepo, minFollowers: minFollowers}
+}
+
+// MinFollowers 自動建立 person Entity 的追蹤數門檻（< 0 表示停用）
+func (r *AuthorRegistry) MinFollowers() int { return r.minFollowers }
+
+// Observe 登記一批貼文的作者（同一作者只寫一次，追蹤數取最大值），回傳新連結 person Entity 的作者數
+func (r *AuthorRegistry) Observe(ctx context.Context, posts []*entity.Post) (int, error) {
+	byKey := make(map[string]*entity.AuthorProfile)
+	var order []string
+	for _, p := range posts {
+		key := entity.AuthorKey(p.Author)
+		if key == "" {
+			continue
+		}
+		k := string(p.Platform) + "\x00" + key
+		a, ok := byKey[k]
+		if !ok {
+			a = &entity.AuthorProfile{Platform: p.Platform, PlatformUserID: key}
+			byKey[k] = a
+			order = append(order, k)
+		}
+		if p.Author.Username != "" {
+			a.Username = p.Author.Username
+		}
+		if p.Author.Followers > a.Followers {
+			a.Followers = p.Author.Followers
+		}
+		if p.CreatedAt.After(a.LastSeenAt) {
+			a.LastSeenAt = p.CreatedAt
+		}
+	}
+
+	linked := 0
+	var lastErr error
+	for _, k := range order {
+		a := byKey[k]
+		if a.LastSeenAt.IsZero() {
+			a.LastSeenAt = time.Now()
+		}
+		if err := r.authorRepo.Upsert(ctx, a); err != nil {
+			lastErr = err
+			continue
+		}
+		if a.ObjectID != nil {
+			continue
+		}
+		ok, err := r.link(ctx, a)
+		if err != nil {
+			log.Printf("[author] link %s/%s error: %v", a.Platform, a.Username, err)
+			continue
+		}
+		if ok {
+			linked++
+		}
+	}
+	return linked, lastErr
+}
+
+// link 將作者連結到既有或新建的 person Entity
+func (r *AuthorRegistry) link(ctx context.Context, a *entity.AuthorProfile) (bool, error) {
+	name := strings.TrimSpace(a.Username)
+	if name == "" {
+		return false, nil
+	}
+
+	obj, err := r.objectRepo.ResolveEntity(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("resolve %q: %w", name, err)
+	}
+	if obj != nil && obj.Type != entity.ObjectTypePerson {
+		return false, nil
+	}
+	if obj == nil {
+		if r.minFollowers < 0 || a.Followers < r.minFollowers {
+			return false, nil
+		}
+		obj = &entity.Object{
+			Type:          entity.ObjectTypePerson,
+			CanonicalName: name,
+			Properties: map[string]any{
+				"source":           "author_registry",
+				"platform":         string(a.Platform),
+				"platform_user_id": a.PlatformUserID,
+				"followers":        a.Followers,
+			},
+		}
+		if err := r.objectRepo.SaveObject(ctx, obj); err != nil {
+			return false, fmt.Errorf("create person %q: %w", name, err)
+		}
+		if !strings.HasPrefix(name, "@") {
+			alias := &entity.ObjectAlias{ObjectID: obj.ID, Alias: "@" + name, Source: entity.AliasSourceSystem, Confidence: 1.0}
+			if err := r.objectRepo.SaveAlias(ctx, alias); err != nil {
+				log.Printf("[author] save alias @%s error: %v", name, err)
+			}
+		}
+		log.Printf("[author] created person entity %q for %s author (%d followers)", name, a.Platform, a.Followers)
+	}
+
+	if err := r.authorRepo.LinkObject(ctx, a.ID, obj.ID); err != nil {
+		return false, err
+	}
+	a.ObjectID = &obj.ID
+	a.ObjectName = obj.CanonicalName
+	return true, nil
+}
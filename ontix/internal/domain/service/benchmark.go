@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+var (
+	// ErrInvalidBenchmark 比較條件錯誤（Entity 數量不足 / 超過上限）
+	ErrInvalidBenchmark = errors.New("invalid benchmark request")
+	// ErrInvalidCompetitor 兩個 Entity 不能建立競爭關係（同一個 Entity 或不是同類的品牌 / 產品）
+	ErrInvalidCompetitor = errors.New("entities cannot compete")
+)
+
+const (
+	benchmarkMaxEntities   = 10
+	benchmarkDefaultAspect = 20
+)
+
+// BenchmarkService 競品集合與比較（聲量佔比、情感落差、面向逐項比較、趨勢）
+// 競品集合：competes_with 關係（明確）+ 同類且常同篇提及的 Entity（隱含）
+type BenchmarkService struct {
+	repo repository.BenchmarkRepository
+}
+
+// NewBenchmarkService 建立 BenchmarkService
+func NewBenchmarkService(repo repository.BenchmarkRepository) *BenchmarkService {
+	return &BenchmarkService{repo: repo}
+}
+
+// Competitors 某 Entity 的競品：明確關係在前，其餘依同篇提及數補足到 filter.Limit
+func (s *BenchmarkService) Competitors(ctx context.Context, objectID string, filter *entity.CompetitorFilter) ([]*entity.Competitor, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = benchmarkMaxEntities - 1
+	}
+
+	var explicit, peers []*entity.Competitor
+	var err error
+	if filter.Source != entity.CompetitorSourceCoMention {
+		if explicit, err = s.repo.ExplicitCompetitors(ctx, objectID); err != nil {
+			return nil, err
+		}
+	}
+	// 隱含競品與明確競品的同篇提及數都來自同一個查詢
+	if filter.Source != entity.CompetitorSourceRelation || len(explicit) > 0 {
+		peerFilter := *filter
+		peerFilter.Limit = filter.Limit + len(explicit)
+		if filter.Source == entity.CompetitorSourceRelation {
+			peerFilter.MinCoMentions = 1
+		}
+		if peers, err = s.repo.CoMentionedPeers(ctx, objectID, &peerFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	coMentions := make(map[string]int, len(peers))
+	for _, p := range peers {
+		coMentions[p.ObjectID] = p.CoMentions
+	}
+	seen := make(map[string]bool, len(explicit))
+	out := make([]*entity.Competitor, 0, filter.Limit)
+	for _, c := range explicit {
+		c.CoMentions = coMentions[c.ObjectID]
+		seen[c.ObjectID] = true
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CoMentions > out[j].CoMentions })
+
+	if filter.Source != entity.CompetitorSourceRelation {
+		for _, p := range peers {
+			if !seen[p.ObjectID] {
+				out = append(out, p)
+			}
+		}
+	}
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+// AddCompetitor 人工指定競品（competes_with 關係，source = manual）
+func (s *BenchmarkService) AddCompetitor(ctx context.Context, objectID, competitorID string) error {
+	if objectID == competitorID {
+		return ErrInvalidCompetitor
+	}
+	ok, err := s.repo.AddCompetitor(ctx, objectID, competitorID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCompetitor
+	}
+	return nil
+}
+
+// RemoveCompetitor 移除競爭關係，回傳是否有刪除
+func (s *BenchmarkService) RemoveCompetitor(ctx context.Context, objectID, competitorID string) (bool, error) {
+	return s.repo.RemoveCompetitor(ctx, objectID, competitorID)
+}
+
+// Benchmark 比較一組 Entity（ObjectIDs[0] 為主體）
+func (s *BenchmarkService) Benchmark(ctx context.Context, filter *entity.BenchmarkFilter) (*entity.Benchmark, error) {
+	ids := uniqueStrings(filter.ObjectIDs)
+	if len(ids) < 2 || len(ids) > benchmarkMaxEntities {
+		return nil, ErrInvalidBenchmark
+	}
+	if filter.PeriodType != "day" {
+		filter.PeriodType = "week"
+	}
+	if filter.Periods <= 0 {
+		filter.Periods = 12
+	}
+	if filter.MinAspectCount <= 0 {
+		filter.MinAspectCount = 3
+	}
+	if filter.AspectLimit <= 0 {
+		filter.AspectLimit = benchmarkDefaultAspect
+	}
+	focal := ids[0]
+
+	totals, err := s.repo.Totals(ctx, ids, filter.PeriodType, filter.Periods)
+	if err != nil {
+		return nil, err
+	}
+	trend, err := s.repo.Trend(ctx, ids, filter.PeriodType, filter.Periods)
+	if err != nil {
+		return nil, err
+	}
+	aspects, err := s.repo.AspectStats(ctx, ids, filter.MinAspectCount)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &entity.Benchmark{
+		FocalID:    focal,
+		PeriodType: filter.PeriodType,
+		Periods:    filter.Periods,
+		Entities:   benchmarkEntities(ids, totals),
+		Aspects:    compareAspects(focal, aspects, filter.AspectLimit),
+		Trend:      trend,
+	}
+	if b.Aspects == nil {
+		b.Aspects = []*entity.BenchmarkAspect{}
+	}
+	if b.Trend == nil {
+		b.Trend = []*entity.BenchmarkPeriod{}
+	}
+	for _, p := range b.Trend {
+		total := 0
+		for _, pt := range p.Points {
+			total += pt.MentionCount
+		}
+		for _, pt := range p.Points {
+			pt.ShareOfVoice = ratio(float64(pt.MentionCount), float64(total))
+		}
+	}
+	return b, nil
+}
+
+// benchmarkEntities 依輸入順序組出比較結果，計算集合內佔比與相對主體的情感落差
+func benchmarkEntities(ids []string, totals []*entity.BenchmarkTotals) []*entity.BenchmarkEntity {
+	byID := make(map[string]*entity.BenchmarkTotals, len(totals))
+	var mentions, reach float64
+	for _, t := range totals {
+		byID[t.ObjectID] = t
+		mentions += float64(t.MentionCount)
+		reach += float64(t.EstimatedReach)
+	}
+
+	var focalSentiment *float64
+	if t, ok := byID[ids[0]]; ok {
+		focalSentiment = t.AvgSentiment
+	}
+
+	out := make([]*entity.BenchmarkEntity, 0, len(ids))
+	for i, id := range ids {
+		t, ok := byID[id]
+		if !ok {
+			continue // 不存在的 Entity
+		}
+		e := &entity.BenchmarkEntity{
+			ObjectID:          t.ObjectID,
+			Name:              t.Name,
+			Type:              t.Type,
+			IsFocal:           i == 0,
+			MentionCount:      t.MentionCount,
+			ShareOfVoice:      ratio(float64(t.MentionCount), mentions),
+			ReachShare:        ratio(float64(t.EstimatedReach), reach),
+			AvgSentiment:      t.AvgSentiment,
+			WeightedSentiment: t.WeightedSentiment,
+			TotalEngagement:   t.TotalEngagement,
+			EstimatedReach:    t.EstimatedReach,
+		}
+		if i > 0 && focalSentiment != nil && t.AvgSentiment != nil {
+			gap := *focalSentiment - *t.AvgSentiment
+			e.SentimentGap = &gap
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// compareAspects 將面向統計轉為逐項比較（依總提及數排序，取前 limit 項）
+func compareAspects(focal string, stats []*entity.AspectStat, limit int) []*entity.BenchmarkAspect {
+	byAspect := make(map[string]*entity.BenchmarkAspect)
+	var order []*entity.BenchmarkAspect
+	for _, st := range stats {
+		a, ok := byAspect[st.Aspect]
+		if !ok {
+			a = &entity.BenchmarkAspect{Aspect: st.Aspect}
+			byAspect[st.Aspect] = a
+			order = append(order, a)
+		}
+		a.Total += st.Count
+		a.Entities = append(a.Entities, st)
+	}
+
+	for _, a := range order {
+		var focalStat, best *entity.AspectStat
+		var leader *entity.AspectStat
+		for _, st := range a.Entities {
+			if leader == nil || st.AvgSentiment > leader.AvgSentiment {
+				leader = st
+			}
+			if st.ObjectID == focal {
+				focalStat = st
+			} else if best == nil || st.AvgSentiment > best.AvgSentiment {
+				best = st
+			}
+		}
+		a.LeaderID = leader.ObjectID
+		if focalStat != nil && best != nil {
+			gap := focalStat.AvgSentiment - best.AvgSentiment
+			a.SentimentGap = &gap
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Total > order[j].Total })
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
+
+func ratio(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total
+}
+
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// chatKeepRecentMessages 摘要時保留原文的最近訊息數（6 輪）
+	chatKeepRecentMessages = 12
+	// chatSummarizeThreshold 未摘要的訊息超過此數量時，將較舊的訊息併入摘要
+	chatSummarizeThreshold = 24
+	// chatTitleMaxRunes 由第一個問題自動產生標題時的最大長度
+	chatTitleMaxRunes = 40
+	// MaxChatTitleRunes 使用者自訂標題的最大長度（chat_sessions.title VARCHAR(200)）
+	MaxChatTitleRunes = 200
+)
+
+var (
+	// ErrChatSessionNotFound session 不存在或不屬於該使用者
+	ErrChatSessionNotFound = errors.New("chat session not found")
+	// ErrChatTitleTooLong 標題超過 MaxChatTitleRunes
+	ErrChatTitleTooLong = fmt.Errorf("chat session title must be at most %d characters", MaxChatTitleRunes)
+)
+
+// ChatTurn 一輪對話的結果（問題、回答、引用與用量）
+type ChatTurn struct {
+	Question  string
+	Answer    string
+	Citations []entity.Citation
+	Usage     entity.TokenUsage
+}
+
+// ChatSessionService Entity 對話 session：持久化歷史、摘要較舊的對話、累計 token 用量
+type ChatSessionService struct {
+	llm  EntitySummaryService
+	repo repository.ChatSessionRepository
+}
+
+// NewChatSessionService 建立 ChatSessionService
+func NewChatSessionService(llm EntitySummaryService, repo repository.ChatSessionRepository) *ChatSessionService {
+	return &ChatSessionService{llm: llm, repo: repo}
+}
+
+// Create 建立新的 session
+func (s *ChatSessionService) Create(ctx context.Context, userID, objectID, title, period string) (*entity.ChatSession, error) {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > MaxChatTitleRunes {
+		return nil, ErrChatTitleTooLong
+	}
+	session := &entity.ChatSession{
+		UserID:   userID,
+		ObjectID: objectID,
+		Title:    title,
+		Period:   period,
+	}
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Open 取得 session 以續談；id 為空或尚不存在時以該 id 建立（相容前端自行產生的 session_id）
+func (s *ChatSessionService) Open(ctx context.Context, userID, id, objectID, period string) (*entity.ChatSession, error) {
+	if id != "" {
+		session, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil {
+			if session.UserID != userID || session.ObjectID != objectID {
+				return nil, ErrChatSessionNotFound
+			}
+			return session, nil
+		}
+	}
+
+	session := &entity.ChatSession{ID: id, UserID: userID, ObjectID: objectID, Period: period}
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Get 取得使用者的 session
+func (s *ChatSessionService) Get(ctx context.Context, userID, id string) (*entity.ChatSession, error) {
+	session, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID {
+		return nil, ErrChatSessionNotFound
+	}
+	return session, nil
+}
+
+// List 列出使用者的 session
+func (s *ChatSessionService) List(ctx context.Context, filter entity.ChatSessionFilter) ([]*entity.ChatSession, int, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Messages 取得 session 的完整對話紀錄
+func (s *ChatSessionService) Messages(ctx context.Context, session *entity.ChatSession) ([]*entity.ChatSessionMessage, error) {
+	return s.repo.ListMessages(ctx, session.ID, 0)
+}
+
+// Rename 修改 session 標題（超過 MaxChatTitleRunes 回傳 ErrChatTitleTooLong）
+func (s *ChatSessionService) Rename(ctx context.Context, userID, id, title string) (*entity.ChatSession, error) {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > MaxChatTitleRunes {
+		return nil, ErrChatTitleTooLong
+	}
+	session, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	session.Title = title
+	if err := s.repo.Rename(ctx, id, session.Title); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Delete 刪除 session
+func (s *ChatSessionService) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Stream 帶入 session 歷史（摘要 + 近期訊息）後串流回答；回傳的 UsageMeter 在串流結束後可取得本輪用量
+func (s *ChatSessionService) Stream(ctx context.Context, session *entity.ChatSession, req *EntityChatRequest) (<-chan string, <-chan error, *UsageMeter, error) {
+	history, err := s.history(ctx, session)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	req.History = history
+
+	meter := &UsageMeter{}
+	tokenCh, errCh := s.llm.StreamEntityChat(WithUsageMeter(ctx, meter), req)
+	return tokenCh, errCh, meter, nil
+}
+
+// SaveTurn 保存一輪對話，必要時將較舊的訊息併入摘要
+func (s *ChatSessionService) SaveTurn(ctx context.Context, session *entity.ChatSession, turn *ChatTurn) error {
+	messages := []*entity.ChatSessionMessage{
+		{Role: "user", Content: turn.Question},
+		{Role: "assistant", Content: turn.Answer, Citations: turn.Citations, Usage: turn.Usage},
+	}
+	if err := s.repo.AppendMessages(ctx, session.ID, messages); err != nil {
+		return err
+	}
+	session.MessageCount += len(messages)
+	session.Usage.InputTokens += turn.Usage.InputTokens
+	session.Usage.OutputTokens += turn.Usage.OutputTokens
+	session.Usage.CostUSD += turn.Usage.CostUSD
+
+	if session.Title == "" {
+		session.Title = TruncateRunes(strings.Join(strings.Fields(turn.Question), " "), chatTitleMaxRunes)
+		if err := s.repo.Rename(ctx, session.ID, session.Title); err != nil {
+			return err
+		}
+	}
+
+	if err := s.compact(ctx, session); err != nil {
+		// 摘要失敗不影響對話，下一輪會再嘗試
+		log.Printf("[chat] summarize session %s: %v", session.ID, err)
+	}
+	return nil
+}
+
+// history 組裝送給 LLM 的歷史：較舊對話的摘要 + 尚未摘要的訊息
+func (s *ChatSessionService) history(ctx context.Context, session *entity.ChatSession) ([]ChatMessage, error) {
+	messages, err := s.repo.ListMessages(ctx, session.ID, session.SummarizedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]ChatMessage, 0, len(messages)+1)
+	if session.Summary != "" {
+		history = append(history, ChatMessage{Role: "system", Content: "先前對話摘要：\n" + session.Summary})
+	}
+	for _, m := range messages {
+		history = append(history, ChatMessage{Role: m.Role, Content: m.Content})
+	}
+	return history, nil
+}
+
+// compact 未摘要的訊息過多時，保留最近的訊息，其餘併入摘要
+func (s *ChatSessionService) compact(ctx context.Context, session *entity.ChatSession) error {
+	messages, err := s.repo.ListMessages(ctx, session.ID, session.SummarizedUntil)
+	if err != nil {
+		return err
+	}
+	if len(messages) <= chatSummarizeThreshold {
+		return nil
+	}
+
+	older := messages[:len(messages)-chatKeepRecentMessages]
+	req := &ChatHistorySummaryRequest{
+		EntityName:      session.ObjectName,
+		PreviousSummary: session.Summary,
+		Messages:        make([]ChatMessage, len(older)),
+	}
+	for i, m := range older {
+		req.Messages[i] = ChatMessage{Role: m.Role, Content: m.Content}
+	}
+
+	meter := &UsageMeter{}
+	summary, err := s.llm.SummarizeChatHistory(WithUsageMeter(ctx, meter), req)
+	if err != nil {
+		return fmt.Errorf("failed to summarize chat history: %w", err)
+	}
+
+	until := older[len(older)-1].ID
+	usage := meter.Usage()
+	if err := s.repo.SaveSummary(ctx, session.ID, summary, until, usage); err != nil {
+		return err
+	}
+	session.Summary = summary
+	session.SummarizedUntil = until
+	session.Usage.InputTokens += usage.InputTokens
+	session.Usage.OutputTokens += usage.OutputTokens
+	session.Usage.CostUSD += usage.CostUSD
+	return nil
+}
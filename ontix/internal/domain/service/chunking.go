@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// PoolMode chunk 向量合併為貼文向量的方式
+type PoolMode string
+
+const (
+	PoolMean PoolMode = "mean" // 依 chunk 長度加權平均（預設）
+	PoolMax  PoolMode = "max"  // 逐維取最大值
+)
+
+const (
+	DefaultChunkMaxRunes  = 800 // 單一 chunk 的字數上限（約 1k token，遠低於 embedding 上限）
+	DefaultChunkMaxChunks = 16  // 單篇最多處理的 chunk 數（超過的部分捨棄，控制成本）
+)
+
+// Chunk 長文切段（Start / End 為原文 rune 位置，End 不含）
+type Chunk struct {
+	Index int
+	Text  string
+	Start int
+	End   int
+}
+
+// Chunker 長文切段：依句子邊界切成不超過 MaxRunes 的段落
+// 原本 embedding / 分類只取前 500 bytes，長的 YouTube 說明、Threads 長文大半內容被丟掉，
+// 且 byte 截斷可能切壞 UTF-8 字元
+type Chunker struct {
+	maxRunes  int
+	maxChunks int
+	pooling   PoolMode
+}
+
+// NewChunker 建立 Chunker（參數 <= 0 使用預設值，未知的 pooling 使用 mean）
+func NewChunker(maxRunes, maxChunks int, pooling PoolMode) *Chunker {
+	if maxRunes <= 0 {
+		maxRunes = DefaultChunkMaxRunes
+	}
+	if maxChunks <= 0 {
+		maxChunks = DefaultChunkMaxChunks
+	}
+	if pooling != PoolMax {
+		pooling = PoolMean
+	}
+	return &Chunker{maxRunes: maxRunes, maxChunks: maxChunks, pooling: pooling}
+}
+
+// DefaultChunker 預設設定的 Chunker
+func DefaultChunker() *Chunker {
+	return NewChunker(0, 0, PoolMean)
+}
+
+// MaxRunes 單一 chunk 的字數上限
+func (c *Chunker) MaxRunes() int { return c.maxRunes }
+
+// MaxChunks 單篇最多處理的 chunk 數
+func (c *Chunker) MaxChunks() int { return c.maxChunks }
+
+// Pooling chunk 向量合併方式
+func (c *Chunker) Pooling() PoolMode { return c.pooling }
+
+// Split 依句子邊界切段；單句超過上限時在字元邊界硬切
+// 短文回傳單一 chunk（與原文相同）；空白文字回傳 nil
+func (c *Chunker) Split(text string) []Chunk {
+	runes := []rune(text)
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if len(runes) <= c.maxRunes {
+		return []Chunk{{Index: 0, Text: text, Start: 0, End: len(runes)}}
+	}
+
+	var chunks []Chunk
+	emit := func(start, end int) bool {
+		// 去掉前後空白但保留原文位置
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		if start == end {
+			return true
+		}
+		chunks = append(chunks, Chunk{Index: len(chunks), Text: string(runes[start:end]), Start: start, End: end})
+		return len(chunks) < c.maxChunks
+	}
+
+	chunkStart := 0
+	lastBoundary := -1 // 目前 chunk 內最後一個句子結尾
+	for i := 0; i < len(runes); i++ {
+		if isSentenceEnd(runes, i) {
+			lastBoundary = i + 1
+		}
+		if i+1-chunkStart < c.maxRunes {
+			continue
+		}
+		cut := lastBoundary
+		if cut <= chunkStart {
+			cut = i + 1 // 整段沒有句子邊界：硬切
+		}
+		if !emit(chunkStart, cut) {
+			return chunks
+		}
+		chunkStart = cut
+		lastBoundary = -1
+		i = cut - 1
+	}
+	if chunkStart < len(runes) {
+		emit(chunkStart, len(runes))
+	}
+	return chunks
+}
+
+// isSentenceEnd runes[i] 是否為句子結尾（中文句讀、換行，英文標點需後接空白）
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '。', '！', '？', '；', '\n', '…':
+		return true
+	case '.', '!', '?', ';':
+		return i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+	}
+	return false
+}
+
+// Head 取開頭不超過 maxRunes 的內容，盡量停在句子邊界（分類等只看開頭的場景）
+func Head(text string, maxRunes int) (string, bool) {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text, false
+	}
+	cut := maxRunes
+	for i := maxRunes - 1; i >= maxRunes/2; i-- {
+		if isSentenceEnd(runes, i) {
+			cut = i + 1
+			break
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut])), true
+}
+
+// ChunkTexts chunk 內容（批次 embedding 用）
+func ChunkTexts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, ch := range chunks {
+		texts[i] = ch.Text
+	}
+	return texts
+}
+
+// Pool 將各 chunk 的向量合併為貼文向量（L2 正規化）
+func (c *Chunker) Pool(chunks []Chunk, vectors [][]float32) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	if len(vectors) == 1 {
+		return vectors[0]
+	}
+
+	dim := len(vectors[0])
+	pooled := make([]float64, dim)
+	switch c.pooling {
+	case PoolMax:
+		for d := range pooled {
+			pooled[d] = math.Inf(-1)
+		}
+		for _, v := range vectors {
+			for d := 0; d < dim && d < len(v); d++ {
+				pooled[d] = math.Max(pooled[d], float64(v[d]))
+			}
+		}
+	default:
+		total := 0.0
+		for i, v := range vectors {
+			w := 1.0
+			if i < len(chunks) {
+				w = float64(chunks[i].End - chunks[i].Start)
+			}
+			total += w
+			for d := 0; d < dim && d < len(v); d++ {
+				pooled[d] += w * float64(v[d])
+			}
+		}
+		for d := range pooled {
+			pooled[d] /= total
+		}
+	}
+
+	norm := 0.0
+	for _, x := range pooled {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float32, dim)
+	for d, x := range pooled {
+		if norm > 0 {
+			x /= norm
+		}
+		out[d] = float32(x)
+	}
+	return out
+}
+
+// EmbedChunks 切段 → 批次 embedding → 合併為貼文向量
+// 回傳貼文向量、各 chunk 與其向量（單一 chunk 時 chunk 向量即貼文向量）
+func (c *Chunker) EmbedChunks(ctx context.Context, embedSvc EmbeddingService, text string) ([]float32, []Chunk, [][]float32, error) {
+	chunks := c.Split(text)
+	if len(chunks) == 0 {
+		chunks = []Chunk{{Text: text}}
+	}
+	vectors, err := embedSvc.BatchEmbed(ctx, ChunkTexts(chunks))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return c.Pool(chunks, vectors), chunks, vectors, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// 引用標記：[P:貼文ID]、[F:事實ID]、[E:實體ID]、[A:面向]
+var citationPattern = regexp.MustCompile(`\[([PFEA]):([^\[\]\n]+?)\]`)
+
+var citationPrefixes = map[entity.CitationKind]string{
+	entity.CitationPost:   "P",
+	entity.CitationFact:   "F",
+	entity.CitationEntity: "E",
+	entity.CitationAspect: "A",
+}
+
+var citationKinds = map[string]entity.CitationKind{
+	"P": entity.CitationPost,
+	"F": entity.CitationFact,
+	"E": entity.CitationEntity,
+	"A": entity.CitationAspect,
+}
+
+// CitationMarker 來源的引用標記（例：[P:123]），放進 prompt 讓 LLM 引用
+func CitationMarker(kind entity.CitationKind, id string) string {
+	return "[" + citationPrefixes[kind] + ":" + id + "]"
+}
+
+// CitationSources 提供給 LLM 的可引用來源（標記 → 顯示標籤）
+type CitationSources map[string]string
+
+// Add 加入可引用來源
+func (s CitationSources) Add(kind entity.CitationKind, id, label string) {
+	s[citationPrefixes[kind]+":"+id] = label
+}
+
+// Has 來源是否存在
+func (s CitationSources) Has(kind entity.CitationKind, id string) bool {
+	_, ok := s[citationPrefixes[kind]+":"+id]
+	return ok
+}
+
+// Extract 從回答中找出引用標記，只保留確實提供過的來源（依出現順序、去重）
+func (s CitationSources) Extract(answer string) []entity.Citation {
+	citations := []entity.Citation{}
+	seen := make(map[string]bool)
+	for _, m := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		key := m[1] + ":" + m[2]
+		label, ok := s[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		citations = append(citations, entity.Citation{Kind: citationKinds[m[1]], ID: m[2], Label: label})
+	}
+	return citations
+}
@@ -131,7 +131,7 @@ func (s *ColdStartService) ProcessColdStart(ctx context.Context, topicID int) er
 		}
 
 		embeddings = append(embeddings, post.Embedding)
-		texts = append(texts, truncateText(post.Content, 200))
+		texts = append(texts, truncateRunes(post.Content, 200))
 		validPostIDs = append(validPostIDs, postID)
 	}
 
@@ -202,10 +202,3 @@ func (s *ColdStartService) IsReady(ctx context.Context, topicID int) (bool, erro
 func (s *ColdStartService) Reset(ctx context.Context, topicID int) error {
 	return s.coldStartRepo.Clear(ctx, topicID)
 }
-
-func truncateText(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen]
-}
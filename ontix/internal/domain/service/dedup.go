@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// DefaultDedupThreshold 預設近似重複門檻（估計 Jaccard 相似度）
+	DefaultDedupThreshold = 0.85
+	// DefaultDedupMinChars 正規化後少於此字數不做重複判定（「+1」「好想吃」這類短文重複是正常現象）
+	DefaultDedupMinChars = 20
+
+	minHashSize        = 64 // 簽章長度
+	minHashBands       = 16 // LSH band 數（每 band minHashSize/minHashBands 列）
+	minHashShingle     = 3  // 字元 n-gram（中文無空白分詞，以字元為單位）
+	dedupCandidateMax  = 50
+	dedupExcerptLength = 280
+)
+
+var dedupURLPattern = regexp.MustCompile(`https?://\S+`)
+
+// DedupService 轉貼 / 複製貼上洗版偵測
+// 正規化內容雜湊找完全相同的貼文，MinHash + LSH 找近似重複；
+// 只有原始貼文會登記指紋，重複貼文一律連到群組的原始貼文。
+type DedupService struct {
+	repo      repository.DedupRepository
+	policy    entity.DedupPolicy
+	threshold float64
+	minChars  int
+}
+
+// NewDedupService 建立 DedupService（threshold / minChars <= 0 使用預設值，policy 空字串為 link）
+func NewDedupService(repo repository.DedupRepository, policy entity.DedupPolicy, threshold float64, minChars int) *DedupService {
+	if policy == "" {
+		policy = entity.DedupPolicyLink
+	}
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultDedupThreshold
+	}
+	if minChars <= 0 {
+		minChars = DefaultDedupMinChars
+	}
+	return &DedupService{repo: repo, policy: policy, threshold: threshold, minChars: minChars}
+}
+
+// Policy 重複貼文的處理策略
+func (s *DedupService) Policy() entity.DedupPolicy {
+	return s.policy
+}
+
+// Threshold 近似重複門檻
+func (s *DedupService) Threshold() float64 {
+	return s.threshold
+}
+
+// Check 判斷貼文是否為既有貼文的重複
+// 重複時記錄並回傳 PostDuplicate；否則登記為原始貼文並回傳 nil
+func (s *DedupService) Check(ctx context.Context, post *entity.Post) (*entity.PostDuplicate, error) {
+	norm := NormalizeDedupContent(post.Content)
+	if len([]rune(norm)) < s.minChars {
+		return nil, nil
+	}
+	hash := ContentHash(norm)
+
+	dup := &entity.PostDuplicate{
+		PostID:         post.PostID,
+		Platform:       post.Platform,
+		AuthorUsername: post.Author.Username,
+		Content:        truncateRunes(post.Content, dedupExcerptLength),
+		Policy:         s.policy,
+		DetectedAt:     time.Now(),
+	}
+
+	// 1. 完全相同
+	fp, err := s.repo.FindByContentHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if fp != nil && fp.PostID != post.PostID {
+		dup.OriginalPostID = fp.PostID
+		dup.Similarity = 1
+		dup.Method = entity.DedupMethodExact
+		return dup, s.repo.SaveDuplicate(ctx, dup)
+	}
+
+	// 2. 近似重複：LSH 候選 → 簽章比對
+	sig := MinHashSignature(norm)
+	bands := MinHashBands(sig)
+	candidates, err := s.repo.FindCandidates(ctx, bands, dedupCandidateMax)
+	if err != nil {
+		return nil, err
+	}
+	var best *entity.PostFingerprint
+	var bestSim float64
+	for _, c := range candidates {
+		if c.PostID == post.PostID {
+			continue
+		}
+		sim := MinHashSimilarity(sig, c.Signature)
+		if sim > bestSim || (sim == bestSim && best != nil && c.CreatedAt.Before(best.CreatedAt)) {
+			best, bestSim = c, sim
+		}
+	}
+	if best != nil && bestSim >= s.threshold {
+		dup.OriginalPostID = best.PostID
+		dup.Similarity = bestSim
+		dup.Method = entity.DedupMethodMinHash
+		return dup, s.repo.SaveDuplicate(ctx, dup)
+	}
+
+	// 3. 原始貼文：登記指紋
+	err = s.repo.SaveFingerprint(ctx, &entity.PostFingerprint{
+		PostID:      post.PostID,
+		Platform:    post.Platform,
+		ContentHash: hash,
+		Signature:   sig,
+	}, bands)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	return nil, nil
+}
+
+// ============================================
+// 內容指紋
+// ============================================
+
+// NormalizeDedupContent 去重用正規化：移除網址、只保留文字與數字並轉小寫
+// （空白、標點、emoji、hashtag 符號的差異不影響判定）
+func NormalizeDedupContent(content string) string {
+	content = dedupURLPattern.ReplaceAllString(content, "")
+	var b strings.Builder
+	for _, r := range content {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// ContentHash 正規化內容的 SHA-256
+func ContentHash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// minHashSeeds 每個 hash 函數的種子（固定值，簽章才能跨程序比對）
+var minHashSeeds = func() [minHashSize]uint64 {
+	var seeds [minHashSize]uint64
+	for i := range seeds {
+		seeds[i] = mix64(uint64(i+1) * 0x9E3779B97F4A7C15)
+	}
+	return seeds
+}()
+
+// mix64 splitmix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
+
+// MinHashSignature 以字元 3-gram 計算 MinHash 簽章
+func MinHashSignature(normalized string) []uint32 {
+	sig := make([]uint32, minHashSize)
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+
+	runes := []rune(normalized)
+	n := minHashShingle
+	if len(runes) < n {
+		n = len(runes)
+	}
+	if n == 0 {
+		return sig
+	}
+
+	seen := make(map[uint64]struct{}, len(runes))
+	for i := 0; i+n <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+n])))
+		base := h.Sum64()
+		if _, ok := seen[base]; ok {
+			continue
+		}
+		seen[base] = struct{}{}
+
+		for j, seed := range minHashSeeds {
+			if v := uint32(mix64(base^seed) >> 32); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+	return sig
+}
+
+// MinHashBands LSH 分桶：每個 band 的簽章片段雜湊為一個值
+// 門檻約 (1/bands)^(1/rows) ≈ 0.5，相似度 0.85 的貼文落入同桶的機率 > 99%
+func MinHashBands(sig []uint32) []int64 {
+	rows := len(sig) / minHashBands
+	bands := make([]int64, minHashBands)
+	buf := make([]byte, 4)
+	for b := range bands {
+		h := fnv.New64a()
+		for _, v := range sig[b*rows : (b+1)*rows] {
+			binary.LittleEndian.PutUint32(buf, v)
+			h.Write(buf)
+		}
+		bands[b] = int64(h.Sum64())
+	}
+	return bands
+}
+
+// MinHashSimilarity 兩個簽章的估計 Jaccard 相似度
+func MinHashSimilarity(a, b []uint32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// truncateRunes 依字元截斷（不切斷 UTF-8）
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
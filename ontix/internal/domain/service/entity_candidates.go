@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// DefaultCandidateTopK 每篇貼文注入抽取 Prompt 的已知 Entity 上限
+	DefaultCandidateTopK = 40
+
+	candidateTrigramThreshold = 0.6  // 別名 trigram 命中比例門檻
+	candidateEmbeddingMin     = 0.35 // 貼文與 Entity embedding 的最低 cosine similarity
+
+	// 各訊號的分數：精確別名命中必定入選，模糊與語意相似依分數排序
+	candidateScoreExact  = 2.0
+	candidateEmbedWeight = 0.8 // embedding similarity 的權重（低於同分的 trigram）
+	candidateMultiSignal = 0.1 // 多個訊號同時命中的加分
+)
+
+// CandidateSelector 抽取前的候選 Entity 挑選
+// 已知 Entity 多達數千個時全部貼進 Prompt 會超出 context 且成本爆炸，
+// 改為每篇貼文以三種訊號挑 top-k：
+//  1. 記憶體內 Aho-Corasick 掃描所有別名（正規化後精確命中）
+//  2. 記憶體內別名 trigram 倒排索引的模糊比對（錯字、空白差異）
+//  3. 貼文 embedding 與 Entity embedding 的相似度（未直接寫出名稱的指涉）
+type CandidateSelector struct {
+	objectRepo repository.ObjectRepository
+	embedRepo  repository.EntityEmbeddingRepository // 可選
+	topK       int
+
+	mu       sync.RWMutex
+	matcher  *AhoCorasick
+	trigrams *TrigramIndex
+	owners   []string // pattern index → object ID
+
+	stats candidateCounters
+}
+
+// CandidateSet 單篇貼文的候選
+type CandidateSet struct {
+	Entities []KnownEntity
+	ids      map[string]bool
+	all      bool // 已知 Entity 不多於 top-k，全部注入
+}
+
+// Contains Entity 是否在候選中
+func (c *CandidateSet) Contains(objectID string) bool {
+	return c == nil || c.all || c.ids[objectID]
+}
+
+// NewCandidateSelector 建立 CandidateSelector（topK <= 0 使用預設值，embedRepo 可為 nil）
+func NewCandidateSelector(objectRepo repository.ObjectRepository, embedRepo repository.EntityEmbeddingRepository, topK int) *CandidateSelector {
+	if topK <= 0 {
+		topK = DefaultCandidateTopK
+	}
+	return &CandidateSelector{objectRepo: objectRepo, embedRepo: embedRepo, topK: topK}
+}
+
+// TopK 每篇貼文的候選上限
+func (s *CandidateSelector) TopK() int {
+	return s.topK
+}
+
+// Refresh 以目前的已知 Entity 與別名重建 Aho-Corasick 自動機與 trigram 索引（每個 batch 呼叫一次）
+func (s *CandidateSelector) Refresh(ctx context.Context, known []KnownEntity) error {
+	aliases, err := s.objectRepo.ListActiveAliases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load aliases: %w", err)
+	}
+
+	patterns := make([]string, 0, len(aliases)+len(known))
+	owners := make([]string, 0, len(aliases)+len(known))
+	for _, k := range known {
+		patterns = append(patterns, NormalizeAlias(k.CanonicalName))
+		owners = append(owners, k.ObjectID)
+	}
+	for _, a := range aliases {
+		patterns = append(patterns, NormalizeAlias(a.Alias))
+		owners = append(owners, a.ObjectID)
+	}
+	matcher := NewAhoCorasick(patterns)
+	trigrams := NewTrigramIndex(patterns)
+
+	s.mu.Lock()
+	s.matcher, s.trigrams, s.owners = matcher, trigrams, owners
+	s.mu.Unlock()
+	return nil
+}
+
+// Select 挑出與貼文相關的已知 Entity（embedding 可為 nil）
+func (s *CandidateSelector) Select(ctx context.Context, text string, embedding []float32, known []KnownEntity) *CandidateSet {
+	atomic.AddInt64(&s.stats.posts, 1)
+	if len(known) <= s.topK {
+		atomic.AddInt64(&s.stats.candidates, int64(len(known)))
+		return &CandidateSet{Entities: known, all: true}
+	}
+
+	scores := make(map[string]float64)
+	signals := make(map[string]int)
+	add := func(id string, score float64) {
+		if score > scores[id] {
+			scores[id] = score
+		}
+		signals[id]++
+	}
+
+	// 1. 精確別名命中
+	s.mu.RLock()
+	matcher, trigrams, owners := s.matcher, s.trigrams, s.owners
+	s.mu.RUnlock()
+	normalized := NormalizeForMatch(text).Text
+	if matcher != nil {
+		hit := make(map[string]bool)
+		for _, m := range matcher.FindAll(normalized) {
+			hit[owners[m.Pattern]] = true
+		}
+		for id := range hit {
+			add(id, candidateScoreExact)
+		}
+	}
+
+	// 2. 別名 trigram（同一 Entity 的多個別名在 add 中取最高分）
+	if trigrams != nil {
+		best := make(map[string]float64)
+		for _, m := range trigrams.Match(normalized, candidateTrigramThreshold) {
+			if id := owners[m.Pattern]; m.Score > best[id] {
+				best[id] = m.Score
+			}
+		}
+		for id, score := range best {
+			add(id, score)
+		}
+	}
+
+	// 3. embedding 相似度
+	if s.embedRepo != nil && len(embedding) > 0 {
+		matches, err := s.embedRepo.SearchByEmbedding(ctx, embedding, "", s.topK)
+		if err != nil {
+			log.Printf("[candidates] embedding search error: %v", err)
+		}
+		for _, m := range matches {
+			if m.Similarity >= candidateEmbeddingMin {
+				add(m.ID, m.Similarity*candidateEmbedWeight)
+			}
+		}
+	}
+
+	byID := make(map[string]KnownEntity, len(known))
+	for _, k := range known {
+		byID[k.ObjectID] = k
+	}
+	type scored struct {
+		id    string
+		score float64
+	}
+	ranked := make([]scored, 0, len(scores))
+	for id, score := range scores {
+		if _, ok := byID[id]; !ok { // 別名表 / embedding 中已非 active 的 Entity
+			continue
+		}
+		ranked = append(ranked, scored{id, score + candidateMultiSignal*float64(signals[id]-1)})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].id < ranked[j].id
+	})
+	if len(ranked) > s.topK {
+		ranked = ranked[:s.topK]
+	}
+
+	set := &CandidateSet{Entities: make([]KnownEntity, len(ranked)), ids: make(map[string]bool, len(ranked))}
+	for i, r := range ranked {
+		set.Entities[i] = byID[r.id]
+		set.ids[r.id] = true
+	}
+	atomic.AddInt64(&s.stats.candidates, int64(len(ranked)))
+	return set
+}
+
+// Record 記錄 LLM 抽出的 Entity 是否在候選中（created = 新建的 Entity，不計入命中率）
+func (s *CandidateSelector) Record(set *CandidateSet, objectID string, created bool) {
+	switch {
+	case created:
+		atomic.AddInt64(&s.stats.created, 1)
+	case set.Contains(objectID):
+		atomic.AddInt64(&s.stats.picked, 1)
+	default:
+		atomic.AddInt64(&s.stats.nonCandidate, 1)
+	}
+}
+
+// CandidateStats 候選挑選統計
+type CandidateStats struct {
+	Posts        int64 // 經過候選挑選的貼文數
+	Candidates   int64 // 注入 Prompt 的候選總數
+	Picked       int64 // LLM 抽出的既有 Entity 中，在候選內的
+	NonCandidate int64 // LLM 抽出的既有 Entity 中，不在候選內的（候選漏召回）
+	Created      int64 // 新建的 Entity
+}
+
+// NonCandidateRate 既有 Entity 不在候選內的比例
+func (s CandidateStats) NonCandidateRate() float64 {
+	total := s.Picked + s.NonCandidate
+	if total == 0 {
+		return 0
+	}
+	return float64(s.NonCandidate) / float64(total)
+}
+
+// AvgCandidates 平均每篇注入的候選數
+func (s CandidateStats) AvgCandidates() float64 {
+	if s.Posts == 0 {
+		return 0
+	}
+	return float64(s.Candidates) / float64(s.Posts)
+}
+
+type candidateCounters struct {
+	posts, candidates, picked, nonCandidate, created int64
+}
+
+// Stats 目前的統計（程序啟動後累計）
+func (s *CandidateSelector) Stats() CandidateStats {
+	return CandidateStats{
+		Posts:        atomic.LoadInt64(&s.stats.posts),
+		Candidates:   atomic.LoadInt64(&s.stats.candidates),
+		Picked:       atomic.LoadInt64(&s.stats.picked),
+		NonCandidate: atomic.LoadInt64(&s.stats.nonCandidate),
+		Created:      atomic.LoadInt64(&s.stats.created),
+	}
+}
+
+// Summary 單行統計摘要（worker 定期輸出）
+func (s *CandidateSelector) Summary() string {
+	st := s.Stats()
+	return fmt.Sprintf("posts=%d avg_candidates=%.1f (top-%d) existing_picked=%d non_candidate=%d (%.1f%%) new=%d",
+		st.Posts, st.AvgCandidates(), s.topK, st.Picked, st.NonCandidate, st.NonCandidateRate()*100, st.Created)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// entityProfileWeight profile 文字 embedding 的權重，其餘為提及貼文 embedding 平均
+	entityProfileWeight = 0.6
+	// entityEmbedBatchSize 每次 BatchEmbed 的 profile 數
+	entityEmbedBatchSize = 50
+)
+
+// ErrEntityNotFound Entity 不存在
+var ErrEntityNotFound = errors.New("entity not found")
+
+// EntityEmbedder 產生 Entity embedding 並提供 Entity 語意搜尋 / 相似 Entity
+//
+// Entity embedding = normalize(0.6 × embed(profile 文字) + 0.4 × 提及貼文 embedding 平均)
+// profile 文字包含別名、aspect 評價分佈、共同提及的 Entity 與提及片段
+type EntityEmbedder struct {
+	embedSvc EmbeddingService
+	repo     repository.EntityEmbeddingRepository
+}
+
+// NewEntityEmbedder 建立 EntityEmbedder
+func NewEntityEmbedder(embedSvc EmbeddingService, repo repository.EntityEmbeddingRepository) *EntityEmbedder {
+	return &EntityEmbedder{embedSvc: embedSvc, repo: repo}
+}
+
+// RefreshStale 為尚未產生或已過期的 Entity 產生 embedding，回傳更新數量
+func (e *EntityEmbedder) RefreshStale(ctx context.Context, limit int, force bool) (int, error) {
+	profiles, err := e.repo.ListStaleProfiles(ctx, limit, force)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for start := 0; start < len(profiles); start += entityEmbedBatchSize {
+		end := min(start+entityEmbedBatchSize, len(profiles))
+		n, err := e.embedProfiles(ctx, profiles[start:end])
+		updated += n
+		if err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// EmbedOne 立即為單一 Entity 產生 embedding
+func (e *EntityEmbedder) EmbedOne(ctx context.Context, objectID string) error {
+	profile, err := e.repo.LoadProfile(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return ErrEntityNotFound
+	}
+	_, err = e.embedProfiles(ctx, []*entity.EntityProfile{profile})
+	return err
+}
+
+func (e *EntityEmbedder) embedProfiles(ctx context.Context, profiles []*entity.EntityProfile) (int, error) {
+	texts := make([]string, len(profiles))
+	for i, p := range profiles {
+		texts[i] = p.Text()
+	}
+
+	embeddings, err := e.embedSvc.BatchEmbed(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed entity profiles: %w", err)
+	}
+
+	for i, p := range profiles {
+		vec := blendEmbedding(embeddings[i], p.PostCentroid, entityProfileWeight)
+		if err := e.repo.SaveEmbedding(ctx, p.ObjectID, vec, p.MentionCount); err != nil {
+			return i, err
+		}
+	}
+	return len(profiles), nil
+}
+
+// Search 以自然語言搜尋 Entity（例：「平價早午餐」→ 相關店家 / 品牌）
+func (e *EntityEmbedder) Search(ctx context.Context, query string, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error) {
+	embedding, err := e.embedSvc.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	return e.repo.SearchByEmbedding(ctx, embedding, objectType, limit)
+}
+
+// Similar 與指定 Entity 最相似的 Entity；尚未產生 embedding 時先即時產生
+func (e *EntityEmbedder) Similar(ctx context.Context, objectID string, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error) {
+	has, err := e.repo.HasEmbedding(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		if err := e.EmbedOne(ctx, objectID); err != nil {
+			return nil, err
+		}
+	}
+	return e.repo.FindSimilar(ctx, objectID, objectType, limit)
+}
+
+// blendEmbedding 以權重 w 混合兩個向量（各自先正規化）並正規化；b 為空時只正規化 a
+func blendEmbedding(a, b []float32, w float64) []float32 {
+	out := make([]float32, len(a))
+	useB := len(b) == len(a)
+	normA, normB := vectorNorm(a), vectorNorm(b)
+	if normB == 0 {
+		useB = false
+	}
+	var norm float64
+	for i := range a {
+		v := float64(a[i])
+		if useB && normA > 0 {
+			v = w*v/normA + (1-w)*float64(b[i])/normB
+		}
+		out[i] = float32(v)
+		norm += v * v
+	}
+	if norm == 0 {
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i := range out {
+		out[i] = float32(float64(out[i]) / norm)
+	}
+	return out
+}
+
+func vectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
@@ -7,10 +7,14 @@ type EntityExtractionService interface {
 	// ExtractEntities 從貼文內容中抽取 Entity 和歸屬的 Aspect
 	// knownEntities: 系統中已知的 Entity 列表，注入 Prompt 讓 LLM 做消歧
 	ExtractEntities(ctx context.Context, content string, knownEntities []KnownEntity) (*EntityExtractionResult, error)
+	// ExtractCommentEntities 從留言 / 回覆中抽取 Entity，所屬貼文作為上下文
+	// （「這支」「他們家」等指涉可解析到貼文提到的實體，但只抽取留言本身提及 / 評價的實體）
+	ExtractCommentEntities(ctx context.Context, content string, thread ThreadContext, knownEntities []KnownEntity) (*EntityExtractionResult, error)
 }
 
 // KnownEntity 已知 Entity（注入 Prompt 用）
 type KnownEntity struct {
+	ObjectID      string // 不注入 Prompt，供候選挑選與命中統計使用
 	CanonicalName string
 	Type          string
 	ClassName     string // ontology class slug（如 venue, creator, brand）
@@ -21,6 +25,8 @@ type KnownEntity struct {
 type EntityExtractionResult struct {
 	Entities      []ExtractedEntity      `json:"entities"`
 	Relationships []ExtractedRelationship `json:"relationships"`
+	Model         string                  `json:"-"` // 產生結果的模型（由實作填入，不在 LLM 回應中）
+	PromptVersion string                  `json:"-"` // 產生結果的 prompt 版本
 }
 
 // ExtractedRelationship LLM 識別出的 Entity 間關係
@@ -42,6 +48,10 @@ type ExtractedEntity struct {
 	SentimentScore float64          `json:"sentiment_score"` // 0.0 ~ 1.0
 	MentionText    string           `json:"mention_text"`    // 原文片段
 	Aspects        []EntityAspectLLM `json:"aspects"`        // 歸屬此 Entity 的 Aspect
+
+	// 長文分段抽取時，此 Entity 所在段落於原文的 rune 位置（MentionText 只會出現在這個範圍內）
+	ChunkStart int `json:"-"`
+	ChunkEnd   int `json:"-"`
 }
 
 // PostForExtraction 待 Entity 抽取的貼文
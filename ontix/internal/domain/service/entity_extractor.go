@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/repository"
@@ -17,6 +18,12 @@ type EntityExtractor struct {
 	objectRepo repository.ObjectRepository
 	schemaRepo repository.OntologySchemaRepository
 	relRepo    repository.ObjectRelationRepository
+	reviewRepo repository.ReviewRepository // 別名衝突排入人工審核（可選）
+	candidates *CandidateSelector          // 每篇貼文只注入相關的已知 Entity（可選）
+	linker     *DictionaryLinker           // LLM 之前的別名規則連結（可選）
+	chunker    *Chunker                    // 長文分段抽取（可選，未設定時整篇一次送 LLM）
+	aspects    *AspectTaxonomy             // 原始面向對應到正規節點（可選）
+	calibrator *SentimentCalibrator        // 情緒分數校準（可選）
 
 	// caches (loaded once per batch)
 	classCache    map[string]int // slug → class_id
@@ -40,6 +47,46 @@ func NewEntityExtractor(
 	}
 }
 
+// SetReviewRepo 設定審核佇列（抽取結果與別名表不一致時排入人工審核）
+func (e *EntityExtractor) SetReviewRepo(repo repository.ReviewRepository) {
+	e.reviewRepo = repo
+}
+
+// SetCandidateSelector 設定候選挑選（未設定時注入全部已知 Entity）
+func (e *EntityExtractor) SetCandidateSelector(sel *CandidateSelector) {
+	e.candidates = sel
+}
+
+// CandidateSelector 目前的候選挑選（未設定為 nil）
+func (e *EntityExtractor) CandidateSelector() *CandidateSelector {
+	return e.candidates
+}
+
+// SetDictionaryLinker 設定規則連結（別名精確命中不送 LLM）
+func (e *EntityExtractor) SetDictionaryLinker(l *DictionaryLinker) {
+	e.linker = l
+}
+
+// DictionaryLinker 目前的規則連結（未設定為 nil）
+func (e *EntityExtractor) DictionaryLinker() *DictionaryLinker {
+	return e.linker
+}
+
+// SetChunker 設定長文切段（每段各自抽取，提及合併）
+func (e *EntityExtractor) SetChunker(c *Chunker) {
+	e.chunker = c
+}
+
+// SetAspectTaxonomy 設定面向正規化（未設定時 aspect_node_id 留空，之後可用 aspect map 回填）
+func (e *EntityExtractor) SetAspectTaxonomy(t *AspectTaxonomy) {
+	e.aspects = t
+}
+
+// SetSentimentCalibrator 設定情緒分數校準（未設定時仍記錄原始分數與模型，sentiment_score 與原始分數相同）
+func (e *EntityExtractor) SetSentimentCalibrator(c *SentimentCalibrator) {
+	e.calibrator = c
+}
+
 // EntityExtractionSummary 單篇貼文的抽取摘要（方便呼叫端知道結果）
 type EntityExtractionSummary struct {
 	PostID          string
@@ -47,6 +94,14 @@ type EntityExtractionSummary struct {
 	EntitiesCreated int
 	AspectsFound    int
 	LinksFound      int
+	Candidates      int  // 注入 Prompt 的已知 Entity 數（分段時為各段合計）
+	RuleLinked      int  // 規則連結的 Entity 數（含在 EntitiesFound 內）
+	SkippedLLM      bool // 全部由規則連結，未呼叫 LLM
+	Chunks          int  // 切段數（短文為 1）
+
+	// 內容編輯重跑（ReextractWithKnown）時提及與 aspect 不寫入而留在這裡，由呼叫端在取代舊分析的交易內寫入
+	Mentions []*entity.PostEntityMention
+	Aspects  []*entity.EntityAspect
 }
 
 // ProcessPost 處理一篇貼文：撈已知 Entity → LLM 抽取+消歧 → 存入 DB
@@ -57,90 +112,92 @@ func (e *EntityExtractor) ProcessPost(ctx context.Context, postID string, conten
 		knownEntities = nil
 	}
 
-	return e.processPostInternal(ctx, postID, content, knownEntities)
+	return e.processPostInternal(ctx, postID, content, nil, nil, knownEntities, false)
 }
 
 // ProcessPostWithKnown 處理一篇貼文，使用預載的 known entities（適合 batch 場景）
-func (e *EntityExtractor) ProcessPostWithKnown(ctx context.Context, postID string, content string, knownEntities []KnownEntity) (*EntityExtractionSummary, error) {
-	return e.processPostInternal(ctx, postID, content, knownEntities)
+// embedding 為貼文向量（可為 nil），供候選挑選的語意相似訊號使用
+func (e *EntityExtractor) ProcessPostWithKnown(ctx context.Context, postID string, content string, embedding []float32, knownEntities []KnownEntity) (*EntityExtractionSummary, error) {
+	return e.processPostInternal(ctx, postID, content, nil, embedding, knownEntities, false)
+}
+
+// ProcessCommentWithKnown 處理一則留言 / 回覆，所屬貼文作為上下文（提及仍記在留言上）
+func (e *EntityExtractor) ProcessCommentWithKnown(ctx context.Context, postID string, content string, thread ThreadContext, embedding []float32, knownEntities []KnownEntity) (*EntityExtractionSummary, error) {
+	return e.processPostInternal(ctx, postID, content, &thread, embedding, knownEntities, false)
 }
 
-// processPostInternal 共用的貼文處理邏輯
-func (e *EntityExtractor) processPostInternal(ctx context.Context, postID string, content string, knownEntities []KnownEntity) (*EntityExtractionSummary, error) {
+// ReextractWithKnown 內容編輯後重新抽取（thread 非 nil 時為留言）：提及與 aspect 不寫入，
+// 放在 summary.Mentions / summary.Aspects，重跑全部成功後才與新內容一起取代舊結果
+func (e *EntityExtractor) ReextractWithKnown(ctx context.Context, postID string, content string, thread *ThreadContext, embedding []float32, knownEntities []KnownEntity) (*EntityExtractionSummary, error) {
+	return e.processPostInternal(ctx, postID, content, thread, embedding, knownEntities, true)
+}
+
+// processPostInternal 共用的貼文處理邏輯（thread 非 nil 時為留言）
+// 長文依句子邊界切段，每段各自做規則連結與 LLM 抽取，結果合併後寫入（deferWrites 時留在 summary）
+func (e *EntityExtractor) processPostInternal(ctx context.Context, postID string, content string, thread *ThreadContext, embedding []float32, knownEntities []KnownEntity, deferWrites bool) (*EntityExtractionSummary, error) {
 	// 確保 cache 已載入
 	if err := e.ensureCaches(ctx); err != nil {
 		log.Printf("[EntityExtractor] failed to load ontology caches (continuing without): %v", err)
 	}
 
-	result, err := e.llm.ExtractEntities(ctx, content, knownEntities)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract entities: %w", err)
-	}
+	// 各段沿用整篇的語言（短段落容易誤判），prompt 模板與情緒校準依此選擇
+	lang := ResolveLanguage(ctx, content)
+	ctx = WithLanguage(ctx, lang)
 
-	summary := &EntityExtractionSummary{
-		PostID: postID,
+	chunks := e.splitContent(content)
+	st := &extractionState{
+		language: lang,
+		summary:  &EntityExtractionSummary{PostID: postID, Chunks: len(chunks), SkippedLLM: true},
+		content:  content,
+		nameToID: make(map[string]string),
+		mentions: make(map[string]*chunkMention),
+		names:    make(map[string]string, len(knownEntities)),
+		deferred: deferWrites,
+	}
+	for _, k := range knownEntities {
+		st.names[k.ObjectID] = k.CanonicalName
 	}
 
-	// name → object ID 映射（供 relationship 解析用）
-	nameToID := make(map[string]string)
-
-	// 處理每個 Entity
-	for _, extracted := range result.Entities {
-		obj, created, err := e.resolveOrCreate(ctx, &extracted)
-		if err != nil {
-			log.Printf("[EntityExtractor] failed to resolve entity %q: %v", extracted.Name, err)
-			continue
+	var lastErr error
+	failed := 0
+	for _, ch := range chunks {
+		if err := e.extractChunk(ctx, postID, ch, thread, embedding, knownEntities, st); err != nil {
+			log.Printf("[EntityExtractor] post %s chunk %d/%d: %v", postID, ch.Index+1, len(chunks), err)
+			lastErr = err
+			failed++
 		}
+	}
+	if failed == len(chunks) && lastErr != nil {
+		return nil, lastErr
+	}
 
-		nameToID[extracted.Name] = obj.ID
-
-		if created {
-			summary.EntitiesCreated++
+	// 存 post_entity_mentions（同一 Entity 在多段出現時合併為一筆）
+	for _, objectID := range st.order {
+		m := st.mentions[objectID]
+		if m.mention.Source == "llm" {
+			// 多段平均後的原始分數再校準
+			label, score := e.calibrator.ApplyLabeled(&m.mention.SentimentScoring, m.mention.Sentiment, m.scoreSum/float64(m.count), st.model, st.promptVersion, string(st.language))
+			m.mention.Sentiment, m.mention.SentimentScore = label, &score
 		}
-		summary.EntitiesFound++
-
-		// 設定 class_id（如果有 ontology class 且 entity 尚未設定）
-		e.setClassIfNeeded(ctx, obj, &extracted)
-
-		// 存 post_entity_mentions
-		mention := &entity.PostEntityMention{
-			PostID:         postID,
-			ObjectID:       obj.ID,
-			Sentiment:      extracted.Sentiment,
-			SentimentScore: extracted.SentimentScore,
-			MentionText:    extracted.MentionText,
-			Source:         "llm",
-		}
-		if err := e.objectRepo.SaveMention(ctx, mention); err != nil {
-			log.Printf("[EntityExtractor] failed to save mention for %q: %v", extracted.Name, err)
+		if st.deferred {
+			st.summary.Mentions = append(st.summary.Mentions, m.mention)
+		} else if err := e.objectRepo.SaveMention(ctx, m.mention); err != nil {
+			log.Printf("[EntityExtractor] failed to save mention %q: %v", m.mention.MentionText, err)
 			continue
 		}
-
-		// 存 entity_aspects
-		for _, aspectLLM := range extracted.Aspects {
-			aspect := &entity.EntityAspect{
-				PostID:         postID,
-				ObjectID:       obj.ID,
-				Aspect:         aspectLLM.Aspect,
-				Sentiment:      aspectLLM.Sentiment,
-				SentimentScore: aspectLLM.SentimentScore,
-				MentionText:    aspectLLM.Mention,
-			}
-			if err := e.objectRepo.SaveEntityAspect(ctx, aspect); err != nil {
-				log.Printf("[EntityExtractor] failed to save aspect %q for %q: %v", aspectLLM.Aspect, extracted.Name, err)
-				continue
-			}
-			summary.AspectsFound++
+		if m.mention.Source == "rule" {
+			st.summary.RuleLinked++
 		}
+		st.summary.EntitiesFound++
 	}
 
 	// 自動建立 topic 相關 relations（discusses + relevant_to）
-	e.createTopicRelations(ctx, result.Entities, nameToID)
+	e.createTopicRelations(ctx, st.entities, st.nameToID)
 
 	// 存 relationships
-	for _, rel := range result.Relationships {
-		sourceID, sourceOK := nameToID[rel.Source]
-		targetID, targetOK := nameToID[rel.Target]
+	for _, rel := range st.relationships {
+		sourceID, sourceOK := st.nameToID[rel.Source]
+		targetID, targetOK := st.nameToID[rel.Target]
 		if !sourceOK || !targetOK {
 			log.Printf("[EntityExtractor] relationship %q -> %q: source or target not resolved, skip", rel.Source, rel.Target)
 			continue
@@ -168,10 +225,207 @@ func (e *EntityExtractor) processPostInternal(ctx context.Context, postID string
 			log.Printf("[EntityExtractor] failed to save link %q -[%s]-> %q: %v", rel.Source, linkType, rel.Target, err)
 			continue
 		}
-		summary.LinksFound++
+		st.summary.LinksFound++
+	}
+
+	return st.summary, nil
+}
+
+// extractionState 單篇貼文跨 chunk 累積的抽取結果
+type extractionState struct {
+	summary       *EntityExtractionSummary
+	content       string            // 整篇原文（計算提及位置用）
+	nameToID      map[string]string // name → object ID（供 relationship 解析用）
+	names         map[string]string // object ID → canonical name（已知 Entity）
+	mentions      map[string]*chunkMention
+	order         []string // mentions 的寫入順序（依首次出現）
+	entities      []ExtractedEntity
+	relationships []ExtractedRelationship
+	model         string // 產生提及分數的模型 / prompt 版本（各段相同）
+	promptVersion string
+	language      entity.Language // 整篇貼文的語言（情緒校準依語言選擇）
+	deferred      bool            // 提及與 aspect 留在 summary，不寫入
+}
+
+// chunkMention 同一 Entity 在各段的提及合併
+type chunkMention struct {
+	mention  *entity.PostEntityMention
+	scoreSum float64
+	count    int // LLM 提及次數（規則連結不計）
+}
+
+// addRule 規則連結的提及：命中片段不送 LLM，沒有情感分數（記為 neutral、分數留空，平均時略過）；
+// 同一 Entity 有 LLM 結果時以 LLM 為準
+func (st *extractionState) addRule(postID string, link RuleLink) {
+	if _, ok := st.mentions[link.ObjectID]; ok {
+		return
+	}
+	st.mentions[link.ObjectID] = &chunkMention{mention: &entity.PostEntityMention{
+		PostID:      postID,
+		ObjectID:    link.ObjectID,
+		Sentiment:   "neutral",
+		MentionText: link.MentionText,
+		Source:      "rule",
+		Span:        &entity.TextSpan{Start: link.Start, End: link.End},
+	}}
+	st.order = append(st.order, link.ObjectID)
+	if name, ok := st.names[link.ObjectID]; ok {
+		st.nameToID[name] = link.ObjectID
+	}
+}
+
+// addLLM LLM 抽出的提及：多段出現時分數取平均，情感不一致記為 mixed，原文片段保留第一段的
+// 原文位置以片段所在的段落為優先範圍對齊；規則連結已有位置時沿用（規則位置必定精確）
+func (st *extractionState) addLLM(postID, objectID string, extracted *ExtractedEntity) {
+	m, ok := st.mentions[objectID]
+	if !ok {
+		m = &chunkMention{}
+		st.mentions[objectID] = m
+		st.order = append(st.order, objectID)
+	}
+	if m.count == 0 {
+		var span *entity.TextSpan
+		if m.mention != nil {
+			span = m.mention.Span
+		}
+		if span == nil {
+			span = st.align(extracted.MentionText, extracted.ChunkStart, extracted.ChunkEnd)
+		}
+		m.mention = &entity.PostEntityMention{
+			PostID:      postID,
+			ObjectID:    objectID,
+			Sentiment:   extracted.Sentiment,
+			MentionText: extracted.MentionText,
+			Source:      "llm",
+			Span:        span,
+		}
+		m.scoreSum, m.count = extracted.SentimentScore, 1
+		return
+	}
+	m.count++
+	m.scoreSum += extracted.SentimentScore
+	if m.mention.Sentiment != extracted.Sentiment {
+		m.mention.Sentiment = "mixed"
+	}
+}
+
+// align 對齊原文位置（對不上時回傳 nil）
+func (st *extractionState) align(snippet string, from, to int) *entity.TextSpan {
+	span, _ := AlignSpan(st.content, snippet, from, to)
+	return span
+}
+
+// splitContent 切段（未設定 Chunker 時整篇為一段）
+func (e *EntityExtractor) splitContent(content string) []Chunk {
+	if e.chunker != nil {
+		if chunks := e.chunker.Split(content); len(chunks) > 0 {
+			return chunks
+		}
+	}
+	return []Chunk{{Text: content, End: utf8.RuneCountInString(content)}}
+}
+
+// extractChunk 單段的規則連結 + 候選挑選 + LLM 抽取
+func (e *EntityExtractor) extractChunk(ctx context.Context, postID string, ch Chunk, thread *ThreadContext, embedding []float32, knownEntities []KnownEntity, st *extractionState) error {
+	content := ch.Text
+
+	// 規則連結：別名精確命中直接記為提及，剩餘文字才送 LLM（位置換算回整篇貼文）
+	if e.linker != nil {
+		linked := e.linker.Link(content)
+		for _, link := range linked.Links {
+			link.Start += ch.Start
+			link.End += ch.Start
+			st.addRule(postID, link)
+		}
+		if !linked.HasResidual() {
+			return nil
+		}
+		content = linked.Residual
+	}
+	st.summary.SkippedLLM = false
+
+	// 候選挑選：留言連同所屬貼文一起比對（留言常以代稱指涉貼文中的 Entity）
+	var candidates *CandidateSet
+	if e.candidates != nil && len(knownEntities) > 0 {
+		text := content
+		if thread != nil {
+			text = strings.Join([]string{thread.PostContent, thread.ParentContent, content}, "\n")
+		}
+		candidates = e.candidates.Select(ctx, text, embedding, knownEntities)
+		knownEntities = candidates.Entities
+	}
+	st.summary.Candidates += len(knownEntities)
+
+	var result *EntityExtractionResult
+	var err error
+	if thread != nil {
+		result, err = e.llm.ExtractCommentEntities(ctx, content, *thread, knownEntities)
+	} else {
+		result, err = e.llm.ExtractEntities(ctx, content, knownEntities)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract entities: %w", err)
 	}
+	st.model, st.promptVersion = result.Model, result.PromptVersion
+
+	// 處理每個 Entity
+	for _, extracted := range result.Entities {
+		extracted.ChunkStart, extracted.ChunkEnd = ch.Start, ch.End
+
+		obj, created, err := e.resolveOrCreate(ctx, &extracted)
+		if err != nil {
+			log.Printf("[EntityExtractor] failed to resolve entity %q: %v", extracted.Name, err)
+			continue
+		}
+
+		st.nameToID[extracted.Name] = obj.ID
+		st.entities = append(st.entities, extracted)
+		if candidates != nil {
+			e.candidates.Record(candidates, obj.ID, created)
+		}
+
+		// 原文片段在別名表中指向另一個 Entity → 排入人工審核
+		e.checkAliasConflict(ctx, postID, &extracted, obj)
+
+		if created {
+			st.summary.EntitiesCreated++
+		}
+
+		// 設定 class_id（如果有 ontology class 且 entity 尚未設定）
+		e.setClassIfNeeded(ctx, obj, &extracted)
+
+		st.addLLM(postID, obj.ID, &extracted)
 
-	return summary, nil
+		// 存 entity_aspects
+		for _, aspectLLM := range extracted.Aspects {
+			aspect := &entity.EntityAspect{
+				PostID:         postID,
+				ObjectID:       obj.ID,
+				Aspect:         aspectLLM.Aspect,
+				Sentiment:      aspectLLM.Sentiment,
+				SentimentScore: aspectLLM.SentimentScore,
+				MentionText:    aspectLLM.Mention,
+				Span:           st.align(aspectLLM.Mention, ch.Start, ch.End),
+			}
+			aspect.Sentiment, aspect.SentimentScore = e.calibrator.ApplyLabeled(&aspect.SentimentScoring, aspectLLM.Sentiment, aspectLLM.SentimentScore, result.Model, result.PromptVersion, string(st.language))
+			if e.aspects != nil {
+				nodeID, err := e.aspects.Resolve(ctx, obj.ClassID, aspectLLM.Aspect)
+				if err != nil {
+					log.Printf("[EntityExtractor] failed to map aspect %q: %v", aspectLLM.Aspect, err)
+				}
+				aspect.AspectNodeID = nodeID
+			}
+			if st.deferred {
+				st.summary.Aspects = append(st.summary.Aspects, aspect)
+			} else if err := e.objectRepo.SaveEntityAspect(ctx, aspect); err != nil {
+				log.Printf("[EntityExtractor] failed to save aspect %q for %q: %v", aspectLLM.Aspect, extracted.Name, err)
+				continue
+			}
+			st.summary.AspectsFound++
+		}
+	}
+	st.relationships = append(st.relationships, result.Relationships...)
+	return nil
 }
 
 // ensureCaches 載入 class 和 relation type cache（lazy init）
@@ -408,6 +662,39 @@ func (e *EntityExtractor) resolveOrCreate(ctx context.Context, extracted *Extrac
 	return newObj, true, nil
 }
 
+// checkAliasConflict 檢查 LLM 解析結果是否與別名表一致
+// 例：LLM 回傳「麥當勞」但原文片段「麥當當」在別名表中屬於另一個 Entity
+func (e *EntityExtractor) checkAliasConflict(ctx context.Context, postID string, extracted *ExtractedEntity, obj *entity.Object) {
+	if e.reviewRepo == nil || extracted.MentionText == "" || extracted.MentionText == extracted.Name {
+		return
+	}
+
+	aliasObj, err := e.objectRepo.ResolveEntity(ctx, strings.TrimSpace(extracted.MentionText))
+	if err != nil || aliasObj == nil || aliasObj.ID == obj.ID {
+		return
+	}
+
+	item := &entity.ReviewItem{
+		PostID: postID,
+		Reason: entity.ReviewReasonAliasConflict,
+		Detail: map[string]any{
+			"extracted_name":    extracted.Name,
+			"mention_text":      extracted.MentionText,
+			"resolved_id":       obj.ID,
+			"resolved_name":     obj.CanonicalName,
+			"alias_object_id":   aliasObj.ID,
+			"alias_object_name": aliasObj.CanonicalName,
+		},
+		Priority: 0.7,
+	}
+	if err := e.reviewRepo.Enqueue(ctx, item); err != nil {
+		log.Printf("[EntityExtractor] failed to enqueue alias conflict for post %s: %v", postID, err)
+		return
+	}
+	log.Printf("[EntityExtractor] alias conflict: %q resolved to %s but alias points to %s (post %s)",
+		extracted.MentionText, obj.CanonicalName, aliasObj.CanonicalName, postID)
+}
+
 // BuildKnownEntities 從 DB 撈已知 Entity，轉成 Prompt 注入格式
 // Worker 可在 batch 層級呼叫一次，避免每篇貼文都查 DB
 func (e *EntityExtractor) BuildKnownEntities(ctx context.Context) ([]KnownEntity, error) {
@@ -440,12 +727,25 @@ func (e *EntityExtractor) BuildKnownEntities(ctx context.Context) ([]KnownEntity
 			}
 		}
 		known[i] = KnownEntity{
+			ObjectID:      obj.ID,
 			CanonicalName: obj.CanonicalName,
 			Type:          string(obj.Type),
 			ClassName:     className,
 			Category:      category,
 		}
 	}
+
+	// 已知 Entity 變動時一併重建別名自動機
+	if e.linker != nil {
+		if err := e.linker.Refresh(ctx); err != nil {
+			log.Printf("[EntityExtractor] failed to refresh dictionary linker: %v", err)
+		}
+	}
+	if e.candidates != nil {
+		if err := e.candidates.Refresh(ctx, known); err != nil {
+			log.Printf("[EntityExtractor] failed to refresh candidate matcher: %v", err)
+		}
+	}
 	return known, nil
 }
 
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// DefaultLinkerMinAliasRunes 正規化後少於此長度的別名不做規則比對（單字別名誤判率太高）
+const DefaultLinkerMinAliasRunes = 2
+
+// DictionaryLinker 以別名表做確定性的 Entity 連結（在 LLM 抽取之前執行）
+// 「7-11」「小七」「統一超商」這類精確命中不需要 LLM，命中的片段記為 source=rule 的提及，
+// 其餘文字才送 LLM 抽取
+type DictionaryLinker struct {
+	objectRepo repository.ObjectRepository
+	minRunes   int
+
+	mu      sync.RWMutex
+	matcher *AhoCorasick
+	owners  []string // pattern index → object ID
+	aliases []string // pattern index → 原始別名
+}
+
+// RuleLink 單筆規則命中（Start / End 為原文 rune 位置，End 不含）
+type RuleLink struct {
+	ObjectID    string
+	Alias       string
+	MentionText string
+	Start       int
+	End         int
+}
+
+// LinkResult 規則連結結果
+type LinkResult struct {
+	Links    []RuleLink
+	Residual string // 命中片段以空白取代後的剩餘文字（送 LLM 抽取）
+}
+
+// HasResidual 剩餘文字是否還有值得送 LLM 的內容
+func (r *LinkResult) HasResidual() bool {
+	n := 0
+	for _, c := range r.Residual {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if n++; n >= 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewDictionaryLinker 建立 DictionaryLinker（minRunes <= 0 使用預設值）
+func NewDictionaryLinker(objectRepo repository.ObjectRepository, minRunes int) *DictionaryLinker {
+	if minRunes <= 0 {
+		minRunes = DefaultLinkerMinAliasRunes
+	}
+	return &DictionaryLinker{objectRepo: objectRepo, minRunes: minRunes}
+}
+
+// Refresh 從別名表重建字典（每個 batch 呼叫一次）
+// 正規化後相同的別名若屬於不同 Entity 視為歧義，不做規則連結（交給 LLM 依上下文判斷）
+func (l *DictionaryLinker) Refresh(ctx context.Context) error {
+	aliases, err := l.objectRepo.ListActiveAliases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load aliases: %w", err)
+	}
+
+	type entry struct {
+		objectID  string
+		alias     string
+		ambiguous bool
+	}
+	dict := make(map[string]*entry)
+	for _, a := range aliases {
+		key := NormalizeAlias(a.Alias)
+		if utf8.RuneCountInString(key) < l.minRunes {
+			continue
+		}
+		if e, ok := dict[key]; ok {
+			if e.objectID != a.ObjectID {
+				e.ambiguous = true
+			}
+			continue
+		}
+		dict[key] = &entry{objectID: a.ObjectID, alias: a.Alias}
+	}
+
+	patterns := make([]string, 0, len(dict))
+	owners := make([]string, 0, len(dict))
+	originals := make([]string, 0, len(dict))
+	for key, e := range dict {
+		if e.ambiguous {
+			continue
+		}
+		patterns = append(patterns, key)
+		owners = append(owners, e.objectID)
+		originals = append(originals, e.alias)
+	}
+	matcher := NewAhoCorasick(patterns)
+
+	l.mu.Lock()
+	l.matcher, l.owners, l.aliases = matcher, owners, originals
+	l.mu.Unlock()
+	return nil
+}
+
+// Size 字典中的別名數
+func (l *DictionaryLinker) Size() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.owners)
+}
+
+// Link 找出文字中所有精確命中的別名
+// 拉丁字母 / 數字開頭或結尾的別名須落在字詞邊界（「hp」不命中「chp」、「app」不命中「happy」）；
+// 重疊時取最左、最長的命中；同一 Entity 只記錄第一次出現，但所有出現處都會從剩餘文字中移除
+func (l *DictionaryLinker) Link(text string) *LinkResult {
+	l.mu.RLock()
+	matcher, owners, aliases := l.matcher, l.owners, l.aliases
+	l.mu.RUnlock()
+
+	result := &LinkResult{Residual: text}
+	if matcher == nil || text == "" {
+		return result
+	}
+
+	norm := NormalizeForMatch(text)
+	matches := matcher.FindAll(norm.Text)
+	if len(matches) == 0 {
+		return result
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	runes := []rune(text)
+	seen := make(map[string]bool)
+	var spans [][2]int
+	lastEnd := 0
+	for _, m := range matches {
+		if m.Start < lastEnd {
+			continue
+		}
+		// 正規化會去除連接符號，邊界以原文再確認一次
+		start, end := norm.Span(m.Start, m.End)
+		if start >= end || !atWordBoundary(runes, start, end) {
+			continue
+		}
+		lastEnd = m.End
+		spans = append(spans, [2]int{start, end})
+
+		objectID := owners[m.Pattern]
+		if seen[objectID] {
+			continue
+		}
+		seen[objectID] = true
+		result.Links = append(result.Links, RuleLink{
+			ObjectID:    objectID,
+			Alias:       aliases[m.Pattern],
+			MentionText: string(runes[start:end]),
+			Start:       start,
+			End:         end,
+		})
+	}
+
+	// 剩餘文字：命中片段以空白取代（避免前後文字黏在一起）
+	var b strings.Builder
+	prev := 0
+	for _, s := range spans {
+		b.WriteString(string(runes[prev:s[0]]))
+		b.WriteByte(' ')
+		prev = s[1]
+	}
+	b.WriteString(string(runes[prev:]))
+	result.Residual = b.String()
+	return result
+}
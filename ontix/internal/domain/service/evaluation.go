@@ -0,0 +1,747 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GoldExample 標註黃金集的一筆資料（JSONL 一行）
+// 欄位省略（nil）代表該任務不評估此筆；空陣列代表「應該沒有任何標籤」
+type GoldExample struct {
+	ID        string       `json:"id"`
+	Content   string       `json:"content"`
+	Topics    []string     `json:"topics"`    // 主題代碼，第一個為主要主題
+	Sentiment string       `json:"sentiment"` // positive/negative/neutral/mixed
+	Tags      []string     `json:"tags"`
+	Entities  []GoldEntity `json:"entities"`
+	Aspects   []GoldAspect `json:"aspects"`
+}
+
+// GoldEntity 黃金集中的 Entity
+type GoldEntity struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// GoldAspect 黃金集中的面向情感
+type GoldAspect struct {
+	Aspect    string `json:"aspect"`
+	Sentiment string `json:"sentiment"`
+}
+
+// 評估任務名稱
+const (
+	EvalTaskTopics    = "topics"
+	EvalTaskSentiment = "sentiment"
+	EvalTaskTags      = "tags"
+	EvalTaskEntities  = "entities"
+	EvalTaskAspects   = "aspects"
+)
+
+// evalNone 預測為空時在混淆矩陣中的標籤
+const evalNone = "(none)"
+
+// LabelMetrics 單一標籤的 P/R/F1
+type LabelMetrics struct {
+	Label     string  `json:"label"`
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	Support   int     `json:"support"` // 黃金集中的正樣本數 (TP+FN)
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// ConfusionMatrix 混淆矩陣（列為黃金標籤，欄為預測標籤）
+type ConfusionMatrix struct {
+	Labels []string `json:"labels"`
+	Matrix [][]int  `json:"matrix"`
+}
+
+// PipelineCost 管線成本與延遲
+// Token / 成本僅在底層服務有回報時（LLMClassificationResult）才有值
+type PipelineCost struct {
+	Calls        int     `json:"calls"`
+	Errors       int     `json:"errors"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	AvgLatencyMS int64   `json:"avg_latency_ms"`
+	P95LatencyMS int64   `json:"p95_latency_ms"`
+
+	latencies []int64
+}
+
+// TaskReport 單一評估任務的結果
+type TaskReport struct {
+	Task      string           `json:"task"`
+	Examples  int              `json:"examples"`
+	Micro     LabelMetrics     `json:"micro"`
+	MacroF1   float64          `json:"macro_f1"`
+	Accuracy  *float64         `json:"accuracy,omitempty"` // 單標籤任務（sentiment、主要主題）
+	PerLabel  []LabelMetrics   `json:"per_label"`
+	Confusion *ConfusionMatrix `json:"confusion,omitempty"`
+	Pipeline  string           `json:"pipeline"` // 產生此任務預測的管線
+}
+
+// EvalReport 完整評估報告（可存成 JSON 作為下次比較的 baseline）
+type EvalReport struct {
+	CreatedAt time.Time                `json:"created_at"`
+	GoldPath  string                   `json:"gold_path"`
+	Examples  int                      `json:"examples"`
+	Config    map[string]string        `json:"config"`
+	Tasks     map[string]*TaskReport   `json:"tasks"`
+	Costs     map[string]*PipelineCost `json:"costs"`
+}
+
+// Evaluator 以黃金集評估標註、主題分類、情感與 Entity 抽取管線
+// 只呼叫 LLM / Embedding，不寫入資料庫
+type Evaluator struct {
+	tagging    TaggingService
+	classifier *LLMClassifier
+	extractor  EntityExtractionService
+
+	// 向量主題分配（設定後取代 LLMClassifier）
+	assigner *TopicAssigner
+	embedSvc EmbeddingService
+
+	knownEntities []KnownEntity
+	tasks         map[string]bool
+}
+
+// NewEvaluator 建立 Evaluator（nil 的服務對應的任務會被略過）
+func NewEvaluator(tagging TaggingService, classifier *LLMClassifier, extractor EntityExtractionService) *Evaluator {
+	return &Evaluator{
+		tagging:    tagging,
+		classifier: classifier,
+		extractor:  extractor,
+		tasks: map[string]bool{
+			EvalTaskTopics:    true,
+			EvalTaskSentiment: true,
+			EvalTaskTags:      true,
+			EvalTaskEntities:  true,
+			EvalTaskAspects:   true,
+		},
+	}
+}
+
+// SetTopicAssigner 改用向量多標籤分配評估主題（用於比較 threshold / 校準）
+func (e *Evaluator) SetTopicAssigner(assigner *TopicAssigner, embedSvc EmbeddingService) {
+	e.assigner = assigner
+	e.embedSvc = embedSvc
+}
+
+// SetKnownEntities 設定注入抽取 prompt 的已知 Entity（與 worker 行為一致）
+func (e *Evaluator) SetKnownEntities(known []KnownEntity) {
+	e.knownEntities = known
+}
+
+// SetTasks 只評估指定任務（空值代表全部）
+func (e *Evaluator) SetTasks(tasks []string) {
+	if len(tasks) == 0 {
+		return
+	}
+	for k := range e.tasks {
+		e.tasks[k] = false
+	}
+	for _, t := range tasks {
+		e.tasks[t] = true
+	}
+}
+
+// TopicPipeline 主題分類使用的管線名稱
+func (e *Evaluator) TopicPipeline() string {
+	if e.assigner != nil {
+		return "embedding"
+	}
+	return "llm"
+}
+
+// evalRun 評估過程中的累加器
+type evalRun struct {
+	topics       *labelCounter
+	topicPrimary *confusionCounter
+	sentiment    *labelCounter
+	sentConf     *confusionCounter
+	tags         *labelCounter
+	entities     *labelCounter
+	entityType   *confusionCounter
+	aspects      *labelCounter
+	aspectSent   *confusionCounter
+
+	counts map[string]int
+	costs  map[string]*PipelineCost
+}
+
+// Run 依序評估黃金集中的每篇貼文
+func (e *Evaluator) Run(ctx context.Context, gold []*GoldExample, onProgress func(current, total int)) (*EvalReport, error) {
+	run := &evalRun{
+		topics:       newLabelCounter(),
+		topicPrimary: newConfusionCounter(),
+		sentiment:    newLabelCounter(),
+		sentConf:     newConfusionCounter(),
+		tags:         newLabelCounter(),
+		entities:     newLabelCounter(),
+		entityType:   newConfusionCounter(),
+		aspects:      newLabelCounter(),
+		aspectSent:   newConfusionCounter(),
+		counts:       make(map[string]int),
+		costs:        make(map[string]*PipelineCost),
+	}
+
+	for i, ex := range gold {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if e.tasks[EvalTaskTopics] && ex.Topics != nil {
+			e.evalTopics(ctx, ex, run)
+		}
+		if e.needsAnalysis(ex) {
+			e.evalAnalysis(ctx, ex, run)
+		}
+		if e.tasks[EvalTaskEntities] && ex.Entities != nil && e.extractor != nil {
+			e.evalEntities(ctx, ex, run)
+		}
+
+		if onProgress != nil {
+			onProgress(i+1, len(gold))
+		}
+	}
+
+	report := &EvalReport{
+		CreatedAt: time.Now(),
+		Examples:  len(gold),
+		Config:    map[string]string{"topic_pipeline": e.TopicPipeline()},
+		Tasks:     make(map[string]*TaskReport),
+		Costs:     run.costs,
+	}
+
+	if n := run.counts[EvalTaskTopics]; n > 0 {
+		t := run.topics.report(EvalTaskTopics, n)
+		t.Confusion = run.topicPrimary.matrix()
+		acc := run.topicPrimary.accuracy()
+		t.Accuracy = &acc
+		t.Pipeline = "topic_" + e.TopicPipeline()
+		report.Tasks[EvalTaskTopics] = t
+	}
+	if n := run.counts[EvalTaskSentiment]; n > 0 {
+		t := run.sentiment.report(EvalTaskSentiment, n)
+		t.Confusion = run.sentConf.matrix()
+		acc := run.sentConf.accuracy()
+		t.Accuracy = &acc
+		t.Pipeline = "analysis"
+		report.Tasks[EvalTaskSentiment] = t
+	}
+	if n := run.counts[EvalTaskTags]; n > 0 {
+		t := run.tags.report(EvalTaskTags, n)
+		t.Pipeline = "analysis"
+		report.Tasks[EvalTaskTags] = t
+	}
+	if n := run.counts[EvalTaskEntities]; n > 0 {
+		t := run.entities.report(EvalTaskEntities, n)
+		t.Confusion = run.entityType.matrix()
+		t.Pipeline = "entity_extraction"
+		report.Tasks[EvalTaskEntities] = t
+	}
+	if n := run.counts[EvalTaskAspects]; n > 0 {
+		t := run.aspects.report(EvalTaskAspects, n)
+		t.Confusion = run.aspectSent.matrix()
+		t.Pipeline = "analysis"
+		report.Tasks[EvalTaskAspects] = t
+	}
+
+	for _, c := range report.Costs {
+		c.finalize()
+	}
+	return report, nil
+}
+
+// evalTopics 主題：每個主題代碼的多標籤 P/R/F1 + 主要主題混淆矩陣
+func (e *Evaluator) evalTopics(ctx context.Context, ex *GoldExample, run *evalRun) {
+	var predicted []string
+	var err error
+	if e.assigner != nil {
+		predicted, err = e.predictTopicsEmbedding(ctx, ex.Content, run.cost("topic_embedding"))
+	} else if e.classifier != nil {
+		predicted, err = e.predictTopicsLLM(ctx, ex.Content, run.cost("topic_llm"))
+	} else {
+		return
+	}
+	if err != nil {
+		log.Printf("[Eval] topic prediction failed for %s: %v", ex.ID, err)
+	}
+
+	run.counts[EvalTaskTopics]++
+	run.topics.add(ex.Topics, predicted)
+	run.topicPrimary.add(firstOr(ex.Topics, evalNone), firstOr(predicted, evalNone))
+}
+
+func (e *Evaluator) predictTopicsLLM(ctx context.Context, content string, cost *PipelineCost) ([]string, error) {
+	start := time.Now()
+	result, err := e.classifier.Classify(ctx, content)
+	if err != nil {
+		cost.record(time.Since(start).Milliseconds(), err)
+		return nil, err
+	}
+	cost.Calls++
+	cost.InputTokens += result.InputTokens
+	cost.OutputTokens += result.OutputTokens
+	cost.CostUSD += result.CostUSD
+	cost.latencies = append(cost.latencies, result.LatencyMS)
+
+	var codes []string
+	if t := e.classifier.FindTopicByName(result.PrimaryTopic); t != nil {
+		codes = append(codes, t.Code)
+	}
+	if result.SecondaryTopic != nil {
+		if t := e.classifier.FindTopicByName(*result.SecondaryTopic); t != nil && (len(codes) == 0 || codes[0] != t.Code) {
+			codes = append(codes, t.Code)
+		}
+	}
+	return codes, nil
+}
+
+func (e *Evaluator) predictTopicsEmbedding(ctx context.Context, content string, cost *PipelineCost) ([]string, error) {
+	start := time.Now()
+	emb, err := e.embedSvc.Embed(ctx, content)
+	if err != nil {
+		cost.record(time.Since(start).Milliseconds(), err)
+		return nil, err
+	}
+	results, err := e.assigner.AssignMultiLabel(ctx, emb, content)
+	cost.record(time.Since(start).Milliseconds(), err)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(results))
+	for _, r := range results {
+		codes = append(codes, r.TopicCode)
+	}
+	return codes, nil
+}
+
+func (e *Evaluator) needsAnalysis(ex *GoldExample) bool {
+	if e.tagging == nil {
+		return false
+	}
+	return (e.tasks[EvalTaskSentiment] && ex.Sentiment != "") ||
+		(e.tasks[EvalTaskTags] && ex.Tags != nil) ||
+		(e.tasks[EvalTaskAspects] && ex.Aspects != nil)
+}
+
+// evalAnalysis AnalyzePost 一次產出 sentiment、soft tags、aspects
+func (e *Evaluator) evalAnalysis(ctx context.Context, ex *GoldExample, run *evalRun) {
+	cost := run.cost("analysis")
+	start := time.Now()
+	analysis, err := e.tagging.AnalyzePost(ctx, ex.Content)
+	cost.record(time.Since(start).Milliseconds(), err)
+	if err != nil {
+		log.Printf("[Eval] analysis failed for %s: %v", ex.ID, err)
+		analysis = &PostAnalysis{}
+	}
+
+	if e.tasks[EvalTaskSentiment] && ex.Sentiment != "" {
+		run.counts[EvalTaskSentiment]++
+		pred := analysis.Sentiment.Label
+		run.sentiment.add([]string{ex.Sentiment}, nonEmpty(pred))
+		run.sentConf.add(ex.Sentiment, firstOr(nonEmpty(pred), evalNone))
+	}
+
+	if e.tasks[EvalTaskTags] && ex.Tags != nil {
+		run.counts[EvalTaskTags]++
+		pred := make([]string, 0, len(analysis.SoftTags))
+		for _, t := range analysis.SoftTags {
+			pred = append(pred, t.Tag)
+		}
+		run.tags.add(normalizeLabels(ex.Tags), normalizeLabels(pred))
+	}
+
+	if e.tasks[EvalTaskAspects] && ex.Aspects != nil {
+		run.counts[EvalTaskAspects]++
+		goldSent := make(map[string]string, len(ex.Aspects))
+		goldNames := make([]string, 0, len(ex.Aspects))
+		for _, a := range ex.Aspects {
+			name := normalizeLabel(a.Aspect)
+			goldNames = append(goldNames, name)
+			goldSent[name] = a.Sentiment
+		}
+		predNames := make([]string, 0, len(analysis.Aspects))
+		for _, a := range analysis.Aspects {
+			name := normalizeLabel(a.Aspect)
+			predNames = append(predNames, name)
+			// 面向命中時比較情感
+			if gs, ok := goldSent[name]; ok && gs != "" {
+				run.aspectSent.add(gs, firstOr(nonEmpty(a.Sentiment), evalNone))
+			}
+		}
+		run.aspects.add(goldNames, predNames)
+	}
+}
+
+// evalEntities Entity：依 type 計算 P/R/F1（名稱 + type 都相同才算命中），
+// 名稱命中但 type 不同者記入 type 混淆矩陣
+func (e *Evaluator) evalEntities(ctx context.Context, ex *GoldExample, run *evalRun) {
+	cost := run.cost("entity_extraction")
+	start := time.Now()
+	result, err := e.extractor.ExtractEntities(ctx, ex.Content, e.knownEntities)
+	cost.record(time.Since(start).Milliseconds(), err)
+	if err != nil {
+		log.Printf("[Eval] entity extraction failed for %s: %v", ex.ID, err)
+		result = &EntityExtractionResult{}
+	}
+	run.counts[EvalTaskEntities]++
+
+	goldByName := make(map[string]string, len(ex.Entities))
+	for _, g := range ex.Entities {
+		goldByName[normalizeLabel(g.Name)] = g.Type
+	}
+	predByName := make(map[string]string, len(result.Entities))
+	for _, p := range result.Entities {
+		predByName[normalizeLabel(p.Name)] = p.Type
+	}
+
+	for name, goldType := range goldByName {
+		predType, ok := predByName[name]
+		if !ok {
+			run.entities.fn(goldType)
+			continue
+		}
+		run.entityType.add(goldType, predType)
+		if predType == goldType {
+			run.entities.tp(goldType)
+		} else {
+			run.entities.fn(goldType)
+			run.entities.fp(predType)
+		}
+	}
+	for name, predType := range predByName {
+		if _, ok := goldByName[name]; !ok {
+			run.entities.fp(predType)
+		}
+	}
+}
+
+func (r *evalRun) cost(pipeline string) *PipelineCost {
+	c, ok := r.costs[pipeline]
+	if !ok {
+		c = &PipelineCost{}
+		r.costs[pipeline] = c
+	}
+	return c
+}
+
+func (c *PipelineCost) record(latencyMS int64, err error) {
+	c.Calls++
+	if err != nil {
+		c.Errors++
+	}
+	c.latencies = append(c.latencies, latencyMS)
+}
+
+func (c *PipelineCost) finalize() {
+	if len(c.latencies) == 0 {
+		return
+	}
+	sorted := append([]int64(nil), c.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, l := range sorted {
+		sum += l
+	}
+	c.AvgLatencyMS = sum / int64(len(sorted))
+	c.P95LatencyMS = sorted[(len(sorted)*95+99)/100-1]
+}
+
+// --- Metrics ---
+
+// labelCounter 以集合比對累計每個標籤的 TP/FP/FN
+type labelCounter struct {
+	labels map[string]*LabelMetrics
+}
+
+func newLabelCounter() *labelCounter {
+	return &labelCounter{labels: make(map[string]*LabelMetrics)}
+}
+
+func (lc *labelCounter) get(label string) *LabelMetrics {
+	m, ok := lc.labels[label]
+	if !ok {
+		m = &LabelMetrics{Label: label}
+		lc.labels[label] = m
+	}
+	return m
+}
+
+func (lc *labelCounter) tp(label string) { lc.get(label).TP++ }
+func (lc *labelCounter) fp(label string) { lc.get(label).FP++ }
+func (lc *labelCounter) fn(label string) { lc.get(label).FN++ }
+
+// add 比對單篇貼文的黃金標籤集合與預測標籤集合
+func (lc *labelCounter) add(gold, predicted []string) {
+	goldSet := make(map[string]bool, len(gold))
+	for _, g := range gold {
+		goldSet[g] = true
+	}
+	predSet := make(map[string]bool, len(predicted))
+	for _, p := range predicted {
+		predSet[p] = true
+	}
+	for g := range goldSet {
+		if predSet[g] {
+			lc.tp(g)
+		} else {
+			lc.fn(g)
+		}
+	}
+	for p := range predSet {
+		if !goldSet[p] {
+			lc.fp(p)
+		}
+	}
+}
+
+// report 計算每個標籤與 micro / macro 指標
+func (lc *labelCounter) report(task string, examples int) *TaskReport {
+	t := &TaskReport{Task: task, Examples: examples, Micro: LabelMetrics{Label: "micro"}}
+
+	var macroSum float64
+	var macroN int
+	for _, m := range lc.labels {
+		m.Support = m.TP + m.FN
+		m.Precision, m.Recall, m.F1 = prf(m.TP, m.FP, m.FN)
+		t.PerLabel = append(t.PerLabel, *m)
+
+		t.Micro.TP += m.TP
+		t.Micro.FP += m.FP
+		t.Micro.FN += m.FN
+		if m.Support > 0 {
+			macroSum += m.F1
+			macroN++
+		}
+	}
+	t.Micro.Support = t.Micro.TP + t.Micro.FN
+	t.Micro.Precision, t.Micro.Recall, t.Micro.F1 = prf(t.Micro.TP, t.Micro.FP, t.Micro.FN)
+	if macroN > 0 {
+		t.MacroF1 = macroSum / float64(macroN)
+	}
+
+	sort.Slice(t.PerLabel, func(i, j int) bool {
+		if t.PerLabel[i].Support != t.PerLabel[j].Support {
+			return t.PerLabel[i].Support > t.PerLabel[j].Support
+		}
+		return t.PerLabel[i].Label < t.PerLabel[j].Label
+	})
+	return t
+}
+
+func prf(tp, fp, fn int) (precision, recall, f1 float64) {
+	if tp+fp > 0 {
+		precision = float64(tp) / float64(tp+fp)
+	}
+	if tp+fn > 0 {
+		recall = float64(tp) / float64(tp+fn)
+	}
+	if precision+recall > 0 {
+		f1 = 2 * precision * recall / (precision + recall)
+	}
+	return
+}
+
+// confusionCounter 單標籤混淆矩陣累加器
+type confusionCounter struct {
+	counts map[string]map[string]int
+	total  int
+	hits   int
+}
+
+func newConfusionCounter() *confusionCounter {
+	return &confusionCounter{counts: make(map[string]map[string]int)}
+}
+
+func (cc *confusionCounter) add(gold, predicted string) {
+	row, ok := cc.counts[gold]
+	if !ok {
+		row = make(map[string]int)
+		cc.counts[gold] = row
+	}
+	row[predicted]++
+	cc.total++
+	if gold == predicted {
+		cc.hits++
+	}
+}
+
+func (cc *confusionCounter) accuracy() float64 {
+	if cc.total == 0 {
+		return 0
+	}
+	return float64(cc.hits) / float64(cc.total)
+}
+
+func (cc *confusionCounter) matrix() *ConfusionMatrix {
+	if cc.total == 0 {
+		return nil
+	}
+	labelSet := make(map[string]bool)
+	for g, row := range cc.counts {
+		labelSet[g] = true
+		for p := range row {
+			labelSet[p] = true
+		}
+	}
+	labels := make([]string, 0, len(labelSet))
+	for l := range labelSet {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	m := &ConfusionMatrix{Labels: labels, Matrix: make([][]int, len(labels))}
+	for i, g := range labels {
+		m.Matrix[i] = make([]int, len(labels))
+		for j, p := range labels {
+			m.Matrix[i][j] = cc.counts[g][p]
+		}
+	}
+	return m
+}
+
+// --- Baseline Diff ---
+
+// LabelDiff 單一標籤相對 baseline 的變化
+type LabelDiff struct {
+	Label      string  `json:"label"`
+	BaselineF1 float64 `json:"baseline_f1"`
+	CurrentF1  float64 `json:"current_f1"`
+	DeltaF1    float64 `json:"delta_f1"`
+}
+
+// TaskDiff 單一任務相對 baseline 的變化
+type TaskDiff struct {
+	Task          string      `json:"task"`
+	MicroF1Delta  float64     `json:"micro_f1_delta"`
+	MacroF1Delta  float64     `json:"macro_f1_delta"`
+	AccuracyDelta *float64    `json:"accuracy_delta,omitempty"`
+	Labels        []LabelDiff `json:"labels"` // 依 |delta| 由大到小
+}
+
+// EvalDiff 兩份評估報告的差異
+type EvalDiff struct {
+	Tasks      []TaskDiff         `json:"tasks"`
+	CostDelta  map[string]float64 `json:"cost_delta_usd"`
+	P95Delta   map[string]int64   `json:"p95_latency_delta_ms"`
+	OnlyInBase []string           `json:"only_in_baseline,omitempty"`
+}
+
+// DiffReports 比較目前報告與 baseline（正值代表變好；成本 / 延遲正值代表變貴 / 變慢）
+func DiffReports(baseline, current *EvalReport) *EvalDiff {
+	diff := &EvalDiff{
+		CostDelta: make(map[string]float64),
+		P95Delta:  make(map[string]int64),
+	}
+
+	taskNames := make([]string, 0, len(current.Tasks))
+	for name := range current.Tasks {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	for _, name := range taskNames {
+		cur := current.Tasks[name]
+		base, ok := baseline.Tasks[name]
+		if !ok {
+			continue
+		}
+		td := TaskDiff{
+			Task:         name,
+			MicroF1Delta: cur.Micro.F1 - base.Micro.F1,
+			MacroF1Delta: cur.MacroF1 - base.MacroF1,
+		}
+		if cur.Accuracy != nil && base.Accuracy != nil {
+			d := *cur.Accuracy - *base.Accuracy
+			td.AccuracyDelta = &d
+		}
+
+		baseF1 := make(map[string]float64, len(base.PerLabel))
+		for _, l := range base.PerLabel {
+			baseF1[l.Label] = l.F1
+		}
+		seen := make(map[string]bool, len(cur.PerLabel))
+		for _, l := range cur.PerLabel {
+			seen[l.Label] = true
+			td.Labels = append(td.Labels, LabelDiff{
+				Label:      l.Label,
+				BaselineF1: baseF1[l.Label],
+				CurrentF1:  l.F1,
+				DeltaF1:    l.F1 - baseF1[l.Label],
+			})
+		}
+		for _, l := range base.PerLabel {
+			if !seen[l.Label] {
+				td.Labels = append(td.Labels, LabelDiff{Label: l.Label, BaselineF1: l.F1, DeltaF1: -l.F1})
+			}
+		}
+		sort.Slice(td.Labels, func(i, j int) bool {
+			return abs(td.Labels[i].DeltaF1) > abs(td.Labels[j].DeltaF1)
+		})
+		diff.Tasks = append(diff.Tasks, td)
+	}
+
+	for name := range baseline.Tasks {
+		if _, ok := current.Tasks[name]; !ok {
+			diff.OnlyInBase = append(diff.OnlyInBase, name)
+		}
+	}
+	sort.Strings(diff.OnlyInBase)
+
+	for name, cur := range current.Costs {
+		if base, ok := baseline.Costs[name]; ok {
+			diff.CostDelta[name] = cur.CostUSD - base.CostUSD
+			diff.P95Delta[name] = cur.P95LatencyMS - base.P95LatencyMS
+		}
+	}
+	return diff
+}
+
+// --- Helpers ---
+
+func normalizeLabel(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func normalizeLabels(labels []string) []string {
+	out := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if n := normalizeLabel(l); n != "" {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+func firstOr(labels []string, fallback string) string {
+	if len(labels) == 0 {
+		return fallback
+	}
+	return labels[0]
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
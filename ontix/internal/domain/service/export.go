@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// exportFlushRows 每累積多少列就 flush 一次（CSV / JSONL 送出緩衝、Parquet 結束一個 row group）
+const exportFlushRows = 5000
+
+// ErrInvalidExport 匯出參數錯誤（未知資料集 / 格式）
+var ErrInvalidExport = errors.New("invalid export request")
+
+// ExportEncoder 逐列編碼輸出
+type ExportEncoder interface {
+	Write(row []any) error
+	Flush() error
+	Close() error // 寫出結尾（例如 Parquet footer），不關閉底層 writer
+}
+
+// ExportEncoderFactory 建立某格式的 ExportEncoder
+type ExportEncoderFactory func(w io.Writer, columns []entity.ExportColumn) (ExportEncoder, error)
+
+// ExportRequest 匯出請求
+type ExportRequest struct {
+	Dataset entity.ExportDataset
+	Format  entity.ExportFormat
+	Period  string // 1w / 4w / 12w…，未指定 From 時以現在往前推算
+	Filter  entity.ExportFilter
+}
+
+// ExportService 批次匯出（串流，不將整個資料集載入記憶體）
+type ExportService struct {
+	repo     repository.ExportRepository
+	encoders map[entity.ExportFormat]ExportEncoderFactory
+}
+
+// NewExportService 建立 ExportService（內建 CSV / JSONL）
+func NewExportService(repo repository.ExportRepository) *ExportService {
+	return &ExportService{
+		repo: repo,
+		encoders: map[entity.ExportFormat]ExportEncoderFactory{
+			entity.ExportCSV:   newCSVExportEncoder,
+			entity.ExportJSONL: newJSONLExportEncoder,
+		},
+	}
+}
+
+// SetEncoder 註冊額外格式（例如 Parquet）
+func (s *ExportService) SetEncoder(format entity.ExportFormat, factory ExportEncoderFactory) {
+	s.encoders[format] = factory
+}
+
+// Validate 檢查資料集與格式，並將 Period 換算為 Filter.From
+func (s *ExportService) Validate(req *ExportRequest) error {
+	if entity.ExportColumns(req.Dataset) == nil {
+		return fmt.Errorf("%w: unknown dataset %q", ErrInvalidExport, req.Dataset)
+	}
+	if _, ok := s.encoders[req.Format]; !ok {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidExport, req.Format)
+	}
+	if req.Period != "" && req.Filter.From == nil {
+		days := parsePeriodDays(req.Period)
+		if days == 0 {
+			return fmt.Errorf("%w: invalid period %q", ErrInvalidExport, req.Period)
+		}
+		from := time.Now().AddDate(0, 0, -days)
+		req.Filter.From = &from
+	}
+	return nil
+}
+
+// Export 將資料集以指定格式寫入 w，回傳列數
+// w 若實作 Flush()（例如 http.ResponseWriter），每 exportFlushRows 列會一併送出
+func (s *ExportService) Export(ctx context.Context, w io.Writer, req *ExportRequest) (int, error) {
+	if err := s.Validate(req); err != nil {
+		return 0, err
+	}
+	columns := entity.ExportColumns(req.Dataset)
+	enc, err := s.encoders[req.Format](w, columns)
+	if err != nil {
+		return 0, err
+	}
+	flusher, _ := w.(interface{ Flush() })
+
+	n := 0
+	err = s.repo.Stream(ctx, req.Dataset, &req.Filter, func(row []any) error {
+		if err := enc.Write(row); err != nil {
+			return err
+		}
+		n++
+		if n%exportFlushRows == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := enc.Close(); err != nil {
+		return n, err
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return n, nil
+}
+
+// ExportContentType 各格式的 Content-Type
+func ExportContentType(format entity.ExportFormat) string {
+	switch format {
+	case entity.ExportCSV:
+		return "text/csv; charset=utf-8"
+	case entity.ExportJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// ExportFilename 預設檔名，例：ontix-posts-20260105.parquet
+func ExportFilename(dataset entity.ExportDataset, format entity.ExportFormat, now time.Time) string {
+	return fmt.Sprintf("ontix-%s-%s.%s", dataset, now.Format("20060102"), format)
+}
+
+// ============================================
+// CSV：第一列為欄名；時間為 RFC3339、清單為 JSON 陣列、NULL 為空字串
+// ============================================
+
+type csvExportEncoder struct {
+	w       *csv.Writer
+	columns []entity.ExportColumn
+	record  []string
+}
+
+func newCSVExportEncoder(w io.Writer, columns []entity.ExportColumn) (ExportEncoder, error) {
+	enc := &csvExportEncoder{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, col := range columns {
+		enc.record[i] = col.Name
+	}
+	if err := enc.w.Write(enc.record); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (e *csvExportEncoder) Write(row []any) error {
+	for i, v := range row {
+		e.record[i] = csvExportValue(v)
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvExportEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportEncoder) Close() error {
+	return e.Flush()
+}
+
+func csvExportValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case []string:
+		if x == nil {
+			x = []string{}
+		}
+		data, _ := json.Marshal(x)
+		return string(data)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// ============================================
+// JSONL：每列一個 JSON 物件（欄位順序固定），NULL 為 null
+// ============================================
+
+type jsonlExportEncoder struct {
+	w       io.Writer
+	columns []entity.ExportColumn
+	buf     bytes.Buffer
+}
+
+func newJSONLExportEncoder(w io.Writer, columns []entity.ExportColumn) (ExportEncoder, error) {
+	return &jsonlExportEncoder{w: w, columns: columns}, nil
+}
+
+func (e *jsonlExportEncoder) Write(row []any) error {
+	e.buf.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.columns[i].Name)
+		e.buf.Write(key)
+		e.buf.WriteByte(':')
+		if list, ok := v.([]string); ok && list == nil {
+			v = []string{}
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(value)
+	}
+	e.buf.WriteString("}\n")
+
+	// 緩衝超過 64KB 才寫出，減少小量寫入
+	if e.buf.Len() >= 64<<10 {
+		return e.Flush()
+	}
+	return nil
+}
+
+func (e *jsonlExportEncoder) Flush() error {
+	if e.buf.Len() == 0 {
+		return nil
+	}
+	_, err := e.w.Write(e.buf.Bytes())
+	e.buf.Reset()
+	return err
+}
+
+func (e *jsonlExportEncoder) Close() error {
+	return e.Flush()
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// ============================================
+// 語言偵測與分詞
+// ============================================
+
+type languageCtxKey struct{}
+
+// WithLanguage 在 context 中標記內容語言（prompt 模板與關鍵字規則依此選擇）
+func WithLanguage(ctx context.Context, lang entity.Language) context.Context {
+	if lang == entity.LanguageUnknown {
+		return ctx
+	}
+	return context.WithValue(ctx, languageCtxKey{}, lang)
+}
+
+// LanguageFromContext 取得 context 中的語言（未設定時回傳空字串）
+func LanguageFromContext(ctx context.Context) entity.Language {
+	lang, _ := ctx.Value(languageCtxKey{}).(entity.Language)
+	return lang
+}
+
+// ResolveLanguage 優先使用 context 標記的語言，未標記時由內容偵測
+func ResolveLanguage(ctx context.Context, text string) entity.Language {
+	if lang := LanguageFromContext(ctx); lang != entity.LanguageUnknown {
+		return lang
+	}
+	return DetectLanguage(text)
+}
+
+// 英文 / 馬來文常見功能詞（以拉丁字母書寫的貼文依命中數區分，分詞時去除）
+var (
+	englishStopwords = wordSet("the", "and", "is", "are", "was", "were", "i", "you", "it", "this", "that", "of", "to",
+		"in", "for", "with", "my", "but", "have", "has", "so", "very", "just", "on", "be", "they", "we", "at",
+		"can", "will", "would", "what", "from", "your", "our", "an", "a")
+	malayStopwords = wordSet("yang", "dan", "di", "ini", "itu", "tak", "tidak", "saya", "aku", "dengan", "untuk",
+		"ada", "sangat", "sudah", "dah", "boleh", "nak", "lah", "je", "jer", "ke", "pun", "kita", "kami", "mereka",
+		"dalam", "pada", "dari", "juga", "lagi", "sini", "tu", "ni", "apa", "macam", "kat", "tapi", "sebab", "bila",
+		"mana", "weh", "mesti", "memang", "betul")
+	// malayMarkers 只用於判斷語言的馬來文口語 / 常見詞（分詞時保留）
+	malayMarkers = wordSet("sedap", "murah", "mahal", "beli", "harga", "kedai", "bagus", "terima", "kasih", "gila",
+		"jom", "cantik", "makan", "minum", "rasa", "sikit", "banyak", "baru", "cuba")
+)
+
+func wordSet(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// DetectLanguage 以文字系統與功能詞判斷貼文語言（中 / 英 / 日 / 馬來文）
+// 含足量假名 → 日文；漢字多於拉丁字詞 → 中文；拉丁字母依英文 / 馬來文功能詞命中數區分；
+// 內容過短（例如只有表情符號或單一品牌名）回傳 LanguageUnknown
+func DetectLanguage(text string) entity.Language {
+	var han, kana int
+	for _, r := range text {
+		switch {
+		case isKanaRune(r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		}
+	}
+	words := latinWords(text)
+	cjk := han + kana
+
+	// 中文貼文偶爾夾「の」等假名，需占一定比例才視為日文
+	if cjk >= 2 && cjk >= len(words) {
+		if kana >= 3 && kana*5 >= cjk {
+			return entity.LanguageJapanese
+		}
+		return entity.LanguageChinese
+	}
+	if len(words) < 3 {
+		if cjk > 0 {
+			return entity.LanguageChinese
+		}
+		return entity.LanguageUnknown
+	}
+
+	var en, ms int
+	for _, w := range words {
+		if englishStopwords[w] {
+			en++
+		}
+		if malayStopwords[w] || malayMarkers[w] {
+			ms++
+		}
+	}
+	if ms > en {
+		return entity.LanguageMalay
+	}
+	return entity.LanguageEnglish
+}
+
+// latinWords 拉丁字母組成的字詞（小寫，含數字，如 iphone15、3c）
+func latinWords(text string) []string {
+	var words []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range text {
+		if isLatinWordRune(r) {
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		if r == '\'' || r == '’' {
+			continue // don't / it's 視為一個字
+		}
+		flush()
+	}
+	flush()
+	return words
+}
+
+func isLatinWordRune(r rune) bool {
+	return unicode.Is(unicode.Latin, r) || unicode.IsDigit(r)
+}
+
+func isCJKRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) || isKanaRune(r)
+}
+
+// isKanaRune 平假名 / 片假名（含長音符「ー」）
+func isKanaRune(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+// Tokenize 依語言切出比對用的字詞（小寫）
+// 中日文沒有空白分詞，每段連續的漢字 / 假名取整段與 2-gram、3-gram；
+// 拉丁字母依空白與標點切字，英文 / 馬來文另外去掉該語言的功能詞
+func Tokenize(text string, lang entity.Language) []string {
+	var tokens []string
+	var stopwords map[string]bool
+	switch lang {
+	case entity.LanguageEnglish:
+		stopwords = englishStopwords
+	case entity.LanguageMalay:
+		stopwords = malayStopwords
+	}
+
+	for _, run := range cjkRuns(text) {
+		tokens = append(tokens, cjkNGrams(run)...)
+	}
+	for _, w := range latinWords(text) {
+		if stopwords[w] {
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+// cjkRuns 連續的漢字 / 假名片段
+func cjkRuns(text string) []string {
+	var runs []string
+	start := -1
+	for i, r := range text {
+		if isCJKRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			runs = append(runs, text[start:i])
+			start = -1
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, text[start:])
+	}
+	return runs
+}
+
+// cjkNGrams 整段與 2-gram、3-gram（兩字以內只回傳整段）
+func cjkNGrams(s string) []string {
+	grams := []string{s}
+	runes := []rune(s)
+	if len(runes) <= 2 {
+		return grams
+	}
+	for n := 2; n <= 3; n++ {
+		for i := 0; i+n <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+n]))
+		}
+	}
+	return grams
+}
+
+// keywordText 關鍵字比對用的內容索引
+// 中日文關鍵字以子字串比對；拉丁字母關鍵字以完整字詞（或連續字詞）比對，
+// 避免 "app" 命中 "happy"、"cat" 命中 "education"
+type keywordText struct {
+	lower string
+	words []string
+	set   map[string]bool
+}
+
+func newKeywordText(content string) *keywordText {
+	t := &keywordText{
+		lower: strings.ToLower(content),
+		words: latinWords(content),
+	}
+	t.set = make(map[string]bool, len(t.words))
+	for _, w := range t.words {
+		t.set[w] = true
+	}
+	return t
+}
+
+// contains 內容是否包含關鍵字
+func (t *keywordText) contains(keyword string) bool {
+	kw := strings.ToLower(strings.TrimSpace(keyword))
+	if kw == "" {
+		return false
+	}
+	if strings.IndexFunc(kw, isCJKRune) >= 0 {
+		return strings.Contains(t.lower, kw)
+	}
+
+	kwWords := latinWords(kw)
+	switch len(kwWords) {
+	case 0:
+		return strings.Contains(t.lower, kw)
+	case 1:
+		w := kwWords[0]
+		return t.set[w] || t.set[w+"s"] || t.set[w+"es"] // 簡單處理英文複數
+	}
+	for i := 0; i+len(kwWords) <= len(t.words); i++ {
+		match := true
+		for j, w := range kwWords {
+			if t.words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want entity.Language
+	}{
+		{"chinese", "今天的咖啡很好喝", entity.LanguageChinese},
+		{"japanese", "東京の新しいカフェに行きました", entity.LanguageJapanese},
+		{"chinese with a stray kana", "我覺得のiPhone很好用", entity.LanguageChinese},
+		{"chinese with a brand name", "iPhone 好用", entity.LanguageChinese},
+		{"english", "The coffee at this shop is really good", entity.LanguageEnglish},
+		{"malay", "Makanan di kedai ini sangat sedap dan murah", entity.LanguageMalay},
+		{"brand name only", "iPhone 17", entity.LanguageUnknown},
+		{"emoji only", "😀😀", entity.LanguageUnknown},
+		{"empty", "", entity.LanguageUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		lang entity.Language
+		want []string
+	}{
+		{"cjk n-grams", "我愛咖啡", entity.LanguageChinese, []string{"我愛咖啡", "我愛", "愛咖", "咖啡", "我愛咖", "愛咖啡"}},
+		{"short cjk run kept whole", "好喝 coffee", entity.LanguageEnglish, []string{"好喝", "coffee"}},
+		{"english stopwords removed", "The iPhone is great", entity.LanguageEnglish, []string{"iphone", "great"}},
+		{"stopwords kept for other languages", "The iPhone is great", entity.LanguageChinese, []string{"the", "iphone", "is", "great"}},
+		{"malay stopwords removed, markers kept", "Kopi ini sangat sedap", entity.LanguageMalay, []string{"kopi", "sedap"}},
+		{"apostrophes join words", "don't stop", entity.LanguageEnglish, []string{"dont", "stop"}},
+		{"empty", "", entity.LanguageUnknown, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q, %q) = %q, want %q", tt.text, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordTextContains(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		keyword string
+		want    bool
+	}{
+		{"whole word", "I love this app", "app", true},
+		{"not inside another word", "I am so happy", "app", false},
+		{"not inside another word 2", "education matters", "cat", false},
+		{"plural s", "Best apps of the year", "app", true},
+		{"plural es", "Two boxes arrived", "box", true},
+		{"no singularization", "one box", "boxes", false},
+		{"case insensitive", "APP store", "App", true},
+		{"phrase", "The customer service was slow", "customer service", true},
+		{"phrase out of order", "service for customer", "customer service", false},
+		{"split by connector", "去7-11買咖啡", "7-11", true},
+		{"cjk substring", "這家咖啡店", "咖啡", true},
+		{"cjk missing", "這家茶店", "咖啡", false},
+		{"empty keyword", "anything", "  ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newKeywordText(tt.content).contains(tt.keyword); got != tt.want {
+				t.Errorf("contains(%q) in %q = %v, want %v", tt.keyword, tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// 快取命名空間（每個 LLM / Embedding 呼叫點一個）
+const (
+	CacheNSAnalyze   = "analyze"
+	CacheNSExtract   = "extract"
+	CacheNSClassify  = "classify"
+	CacheNSTags      = "tags"
+	CacheNSEmbed     = "embed"
+	CacheNSTranslate = "translate"
+)
+
+// DefaultLLMCacheTTL 預設快取保存時間
+const DefaultLLMCacheTTL = 30 * 24 * time.Hour
+
+// LLMCache LLM / Embedding 回應快取
+// key = hash(命名空間, 模型, prompt 版本, 正規化後內容, prompt 其他變動部分)，
+// prompt 模板改版時調整版本號即可讓舊快取自然失效。
+// nil *LLMCache 視為停用（永遠 miss），呼叫端不需判斷。
+type LLMCache struct {
+	repo repository.LLMCacheRepository
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	stats map[string]*CacheStats
+}
+
+// CacheStats 單一命名空間的命中統計
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+}
+
+// HitRate 命中率
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// NewLLMCache 建立 LLMCache（ttl<=0 使用預設值）
+func NewLLMCache(repo repository.LLMCacheRepository, ttl time.Duration) *LLMCache {
+	if ttl <= 0 {
+		ttl = DefaultLLMCacheTTL
+	}
+	return &LLMCache{
+		repo:  repo,
+		ttl:   ttl,
+		stats: make(map[string]*CacheStats),
+	}
+}
+
+// CacheKey 快取鍵
+type CacheKey struct {
+	Namespace string
+	Hash      string
+}
+
+func (k CacheKey) String() string {
+	return k.Namespace + ":" + k.Hash
+}
+
+// Key 產生快取鍵；extra 為 prompt 中除了貼文內容以外會變動的部分（few-shot 範例、已知 Entity 等）
+func (c *LLMCache) Key(namespace, model, promptVersion, content string, extra ...string) CacheKey {
+	h := sha256.New()
+	for _, part := range append([]string{model, promptVersion, NormalizeContent(content)}, extra...) {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return CacheKey{Namespace: namespace, Hash: hex.EncodeToString(h.Sum(nil))}
+}
+
+// Get 讀取快取並解碼到 dest，命中時回傳 true
+func (c *LLMCache) Get(ctx context.Context, key CacheKey, dest any) bool {
+	if c == nil {
+		return false
+	}
+
+	data, found, err := c.repo.Get(ctx, key.String())
+	if err != nil {
+		log.Printf("[LLMCache] get %s error: %v", key.Namespace, err)
+		c.record(key.Namespace, func(s *CacheStats) { s.Errors++; s.Misses++ })
+		return false
+	}
+	if !found {
+		c.record(key.Namespace, func(s *CacheStats) { s.Misses++ })
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		// 結構變動導致的舊資料視為 miss
+		c.record(key.Namespace, func(s *CacheStats) { s.Errors++; s.Misses++ })
+		return false
+	}
+	c.record(key.Namespace, func(s *CacheStats) { s.Hits++ })
+	return true
+}
+
+// Set 寫入快取（失敗只記 log，不影響主流程）
+func (c *LLMCache) Set(ctx context.Context, key CacheKey, value any) {
+	if c == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("[LLMCache] marshal %s error: %v", key.Namespace, err)
+		return
+	}
+	if err := c.repo.Set(ctx, key.String(), data, c.ttl); err != nil {
+		log.Printf("[LLMCache] set %s error: %v", key.Namespace, err)
+		c.record(key.Namespace, func(s *CacheStats) { s.Errors++ })
+	}
+}
+
+// Stats 取得各命名空間的命中統計快照
+func (c *LLMCache) Stats() map[string]CacheStats {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]CacheStats, len(c.stats))
+	for ns, s := range c.stats {
+		out[ns] = *s
+	}
+	return out
+}
+
+// Summary 一行摘要（log 用），例如 "analyze 12/20 (60%), embed 20/20 (100%)"
+func (c *LLMCache) Summary() string {
+	if c == nil {
+		return "disabled"
+	}
+	stats := c.Stats()
+	if len(stats) == 0 {
+		return "no lookups"
+	}
+
+	namespaces := make([]string, 0, len(stats))
+	for ns := range stats {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	parts := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		s := stats[ns]
+		part := fmt.Sprintf("%s %d/%d (%.0f%%)", ns, s.Hits, s.Hits+s.Misses, s.HitRate()*100)
+		if s.Errors > 0 {
+			part += fmt.Sprintf(" errors=%d", s.Errors)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c *LLMCache) record(namespace string, fn func(s *CacheStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[namespace]
+	if !ok {
+		s = &CacheStats{}
+		c.stats[namespace] = s
+	}
+	fn(s)
+}
+
+// NormalizeContent 正規化貼文內容：去除頭尾空白並合併連續空白，
+// 讓只差在換行 / 空白的重複貼文共用同一筆快取
+func NormalizeContent(content string) string {
+	return strings.Join(strings.Fields(content), " ")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ikala/ontix/internal/domain/entity"
@@ -16,6 +17,26 @@ type LLMClassifier struct {
 	client *openai.Client
 	model  string
 	topics []*entity.Topic
+
+	mu       sync.RWMutex
+	fewShots []*entity.FewShotExample // 人工校正的主題範例
+
+	cache *LLMCache      // 回應快取（nil = 停用）
+	usage *UsageTracker // 用量記帳（nil = 停用）
+}
+
+// classifyPromptVersion 分類 prompt 版本：修改 prompt 模板時請遞增，讓舊的快取自然失效
+const classifyPromptVersion = "classify/v2"
+
+// classifyMaxRunes 分類只看貼文開頭（主題通常在前段就能判斷），盡量停在句子邊界
+const classifyMaxRunes = 1000
+
+// llmClassification 可快取的分類結果（不含成本 / 延遲等元數據）
+type llmClassification struct {
+	PrimaryTopic   string  `json:"primary"`
+	SecondaryTopic *string `json:"secondary"`
+	Confidence     string  `json:"confidence"`
+	Reason         string  `json:"reason"`
 }
 
 // LLMClassificationResult LLM 分類結果
@@ -31,6 +52,7 @@ type LLMClassificationResult struct {
 	CostUSD      float64
 	LatencyMS    int64
 	ModelUsed    string
+	Cached       bool // 命中快取（未呼叫 API，成本為 0）
 }
 
 // NewLLMClassifier 建立 LLM 分類器
@@ -57,15 +79,30 @@ func (c *LLMClassifier) Classify(ctx context.Context, content string) (*LLMClass
 	}
 
 	// 截斷過長內容
-	if len(content) > 500 {
-		content = content[:500] + "..."
+	if head, truncated := Head(content, classifyMaxRunes); truncated {
+		content = head + "..."
+	}
+
+	fewShots := c.formatFewShots()
+	key := c.cache.Key(CacheNSClassify, c.model, classifyPromptVersion, content, strings.Join(topicNames, "、"), fewShots)
+	var cached llmClassification
+	if c.cache.Get(ctx, key, &cached) {
+		return &LLMClassificationResult{
+			PrimaryTopic:   cached.PrimaryTopic,
+			SecondaryTopic: cached.SecondaryTopic,
+			Confidence:     cached.Confidence,
+			Reason:         cached.Reason,
+			LatencyMS:      time.Since(start).Milliseconds(),
+			ModelUsed:      c.model,
+			Cached:         true,
+		}, nil
 	}
 
 	prompt := fmt.Sprintf(`你是社群貼文分類專家。請分析以下貼文並分類到最適合的主題。
 
 可用主題列表：
 %s
-
+%s
 貼文內容：
 %s
 
@@ -77,7 +114,11 @@ func (c *LLMClassifier) Classify(ctx context.Context, content string) (*LLMClass
   "secondary": "次要主題名稱（如果沒有則為 null）",
   "confidence": "high/medium/low",
   "reason": "簡短說明分類理由（20字內）"
-}`, strings.Join(topicNames, "、"), content)
+}`, strings.Join(topicNames, "、"), fewShots, content)
+
+	if err := c.usage.Allow(ctx, entity.UsageStageClassification); err != nil {
+		return nil, err
+	}
 
 	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
 		Model: c.model,
@@ -89,6 +130,7 @@ func (c *LLMClassifier) Classify(ctx context.Context, content string) (*LLMClass
 			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 		},
 	})
+	c.usage.Record(ctx, entity.UsageStageClassification, "openai", c.model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens, time.Since(start), err)
 	if err != nil {
 		return nil, fmt.Errorf("OpenAI API error: %w", err)
 	}
@@ -101,6 +143,12 @@ func (c *LLMClassifier) Classify(ctx context.Context, content string) (*LLMClass
 		if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
 			return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 		}
+		c.cache.Set(ctx, key, llmClassification{
+			PrimaryTopic:   result.PrimaryTopic,
+			SecondaryTopic: result.SecondaryTopic,
+			Confidence:     result.Confidence,
+			Reason:         result.Reason,
+		})
 	}
 
 	// 計算成本 (GPT-4o-mini pricing)
@@ -153,27 +201,58 @@ func (c *LLMClassifier) FindTopicByName(name string) *entity.Topic {
 	return nil
 }
 
-// calculateCost 計算 API 成本
+// calculateCost 計算 API 成本（定價見 entity.EstimateCostUSD）
 func (c *LLMClassifier) calculateCost(inputTokens, outputTokens int) float64 {
-	// GPT-4o-mini pricing (as of 2024)
-	// Input: $0.15 per 1M tokens
-	// Output: $0.60 per 1M tokens
-	var inputPrice, outputPrice float64
-
-	switch c.model {
-	case openai.GPT4oMini:
-		inputPrice = 0.15 / 1_000_000
-		outputPrice = 0.60 / 1_000_000
-	case openai.GPT4o:
-		inputPrice = 2.50 / 1_000_000
-		outputPrice = 10.00 / 1_000_000
-	default:
-		// gpt-4o-mini pricing as default
-		inputPrice = 0.15 / 1_000_000
-		outputPrice = 0.60 / 1_000_000
-	}
+	return entity.EstimateCostUSD(c.model, inputTokens, outputTokens)
+}
+
+// SetUsageTracker 設定用量記帳
+func (c *LLMClassifier) SetUsageTracker(tracker *UsageTracker) {
+	c.usage = tracker
+}
+
+// SetCache 設定回應快取
+func (c *LLMClassifier) SetCache(cache *LLMCache) {
+	c.cache = cache
+}
+
+// SetFewShotExamples 設定人工校正的主題範例（實作 FewShotReceiver）
+func (c *LLMClassifier) SetFewShotExamples(examples []*entity.FewShotExample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fewShots = examples
+}
+
+// formatFewShots 將主題校正範例格式化為 prompt 片段
+func (c *LLMClassifier) formatFewShots() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	return float64(inputTokens)*inputPrice + float64(outputTokens)*outputPrice
+	var sb strings.Builder
+	for _, ex := range c.fewShots {
+		ids, _ := ex.After["topic_ids"].([]any)
+		var names []string
+		for _, id := range ids {
+			n, ok := id.(float64)
+			if !ok {
+				continue
+			}
+			for _, t := range c.topics {
+				if t.ID == int(n) {
+					names = append(names, t.Name)
+					break
+				}
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- 貼文：%s\n  主題：%s\n", TruncateRunes(ex.Content, fewShotContentRunes), strings.Join(names, "、")))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "\n以下是人工校正過的分類範例（第一個為主要主題），請參考其判斷標準：\n" + sb.String()
 }
 
 // GetTopics 取得主題列表
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -277,6 +279,11 @@ func (e *OntologyEngine) GenerateNarratives(
 		}
 
 		result, err := e.narrativeSvc.GenerateNarrative(ctx, req)
+		if errors.Is(err, ErrBudgetExceeded) {
+			// 預算降級：本輪剩餘的 narrative 全部略過
+			log.Printf("[ontology] skip remaining narratives: %v", err)
+			break
+		}
 		if err != nil {
 			log.Printf("[ontology] warn: narrative for %s: %v", info.name, err)
 			continue
@@ -396,6 +403,20 @@ func computeDelta(current, previous *entity.EntityObservation, name, classSlug s
 		d.MentionDeltaPct = float64(d.MentionDelta) / float64(previous.MentionCount) * 100
 	}
 
+	// Weighted delta
+	d.WeightedSentimentDelta = current.WeightedSentiment - previous.WeightedSentiment
+	if previous.WeightedSentiment != 0 {
+		d.WeightedSentimentDeltaPct = d.WeightedSentimentDelta / math.Abs(previous.WeightedSentiment) * 100
+	}
+	d.ReachDelta = current.EstimatedReach - previous.EstimatedReach
+	if previous.EstimatedReach > 0 {
+		d.ReachDeltaPct = float64(d.ReachDelta) / float64(previous.EstimatedReach) * 100
+	}
+	d.ShareOfVoiceDelta = current.ShareOfVoice - previous.ShareOfVoice
+	if previous.ShareOfVoice > 0 {
+		d.ShareOfVoiceDeltaPct = d.ShareOfVoiceDelta / previous.ShareOfVoice * 100
+	}
+
 	// Aspect delta
 	prevMap := aspectMap(previous.AspectData)
 	currMap := aspectMap(current.AspectData)
@@ -439,6 +460,56 @@ func aspectMap(data []entity.AspectObservation) map[string]*entity.AspectObserva
 	return m
 }
 
+// rollupAspects 將面向快照彙總到面向樹的第 level 層（1 = 頂層）
+// 比 level 淺的節點維持原節點，未對應的面向維持原字串；情感以提及數加權平均
+func rollupAspects(data []entity.AspectObservation, level int) []entity.AspectObservation {
+	if level <= 0 || len(data) == 0 {
+		return data
+	}
+	byKey := make(map[string]int, len(data))
+	var sums []float64
+	var out []entity.AspectObservation
+	for _, a := range data {
+		key, path := a.Aspect, a.Path
+		if len(path) > 0 {
+			depth := min(level, len(path))
+			key, path = path[depth-1], path[:depth]
+		}
+		i, ok := byKey[key]
+		if !ok {
+			i = len(out)
+			byKey[key] = i
+			out = append(out, entity.AspectObservation{Aspect: key, Path: path})
+			sums = append(sums, 0)
+		}
+		if len(path) > 0 && len(a.Path) == len(path) {
+			out[i].NodeID = a.NodeID // 節點本身（而非子節點）才帶 node_id
+		}
+		out[i].Count += a.Count
+		sums[i] += a.AvgSentiment * float64(a.Count)
+	}
+	for i := range out {
+		if out[i].Count > 0 {
+			out[i].AvgSentiment = sums[i] / float64(out[i].Count)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+	return out
+}
+
+// rollupDelta 以 roll-up 後的面向快照重算 delta（只有面向相關欄位會不同）
+func rollupDelta(delta *entity.ObservationDelta, level int) *entity.ObservationDelta {
+	cur := *delta.Current
+	cur.AspectData = rollupAspects(cur.AspectData, level)
+	var prev *entity.EntityObservation
+	if delta.Previous != nil {
+		p := *delta.Previous
+		p.AspectData = rollupAspects(p.AspectData, level)
+		prev = &p
+	}
+	return computeDelta(&cur, prev, delta.ObjectName, delta.ClassSlug)
+}
+
 func stepBack(t time.Time, periodType string) time.Time {
 	switch periodType {
 	case "day":
@@ -476,9 +547,17 @@ func (e *OntologyEngine) evaluateRule(
 		return e.evalSentiment(ctx, rule, delta, periodStart, periodType)
 	case "mention_count":
 		return e.evalMention(ctx, rule, delta, periodStart, periodType)
+	case "weighted_sentiment", "estimated_reach", "share_of_voice":
+		return e.evalWeighted(ctx, rule, delta, periodStart, periodType)
 	case "new_aspects":
+		if cond.AspectLevel > 0 {
+			delta = rollupDelta(delta, cond.AspectLevel)
+		}
 		return e.evalNewAspects(rule, delta, periodStart, periodType)
 	case "aspect_sentiment":
+		if cond.AspectLevel > 0 {
+			delta = rollupDelta(delta, cond.AspectLevel)
+		}
 		return e.evalAspectFlip(rule, delta, periodStart, periodType)
 	default:
 		return nil, nil
@@ -545,6 +624,34 @@ func (e *OntologyEngine) evalMention(
 	return e.createFacts(ctx, rule, delta, periodStart, periodType)
 }
 
+// --- weighted_sentiment / estimated_reach / share_of_voice increase/decrease ---
+
+func (e *OntologyEngine) evalWeighted(
+	ctx context.Context,
+	rule *entity.Rule,
+	delta *entity.ObservationDelta,
+	periodStart time.Time,
+	periodType string,
+) ([]*entity.DerivedFact, error) {
+	if delta.Previous == nil {
+		return nil, nil
+	}
+
+	pct := metricDeltaPct(rule.Condition.Metric, delta)
+	matched := false
+	switch rule.Condition.Operator {
+	case "decrease_pct":
+		matched = pct <= -rule.Condition.Threshold
+	case "increase_pct":
+		matched = pct >= rule.Condition.Threshold
+	}
+	if !matched {
+		return nil, nil
+	}
+
+	return e.createFacts(ctx, rule, delta, periodStart, periodType)
+}
+
 // --- R4: new_aspects exists ---
 
 func (e *OntologyEngine) evalNewAspects(
@@ -672,10 +779,7 @@ func (e *OntologyEngine) createFacts(
 	periodStart time.Time,
 	periodType string,
 ) ([]*entity.DerivedFact, error) {
-	absDelta := math.Abs(delta.SentimentDeltaPct)
-	if rule.Condition.Metric == "mention_count" {
-		absDelta = math.Abs(delta.MentionDeltaPct)
-	}
+	absDelta := math.Abs(metricDeltaPct(rule.Condition.Metric, delta))
 
 	var changedAspects []string
 	for _, ad := range delta.AspectDeltas {
@@ -849,11 +953,33 @@ func metricValue(rule *entity.Rule, obs *entity.EntityObservation) float64 {
 		return obs.AvgSentiment
 	case "mention_count":
 		return float64(obs.MentionCount)
+	case "weighted_sentiment":
+		return obs.WeightedSentiment
+	case "estimated_reach":
+		return float64(obs.EstimatedReach)
+	case "share_of_voice":
+		return obs.ShareOfVoice
 	default:
 		return 0
 	}
 }
 
+// metricDeltaPct 規則指標的兩期變化百分比（未知指標沿用 avg_sentiment）
+func metricDeltaPct(metric string, delta *entity.ObservationDelta) float64 {
+	switch metric {
+	case "mention_count":
+		return delta.MentionDeltaPct
+	case "weighted_sentiment":
+		return delta.WeightedSentimentDeltaPct
+	case "estimated_reach":
+		return delta.ReachDeltaPct
+	case "share_of_voice":
+		return delta.ShareOfVoiceDeltaPct
+	default:
+		return delta.SentimentDeltaPct
+	}
+}
+
 func metricValuePrev(rule *entity.Rule, obs *entity.EntityObservation) float64 {
 	if obs == nil {
 		return 0
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestRollupAspects(t *testing.T) {
+	food := entity.AspectObservation{Aspect: "餐點", Count: 2, AvgSentiment: 0.8, NodeID: intPtr(1), Path: []string{"餐點"}}
+	fries := entity.AspectObservation{Aspect: "薯條", Count: 3, AvgSentiment: 0.2, NodeID: intPtr(2), Path: []string{"餐點", "薯條"}}
+	burger := entity.AspectObservation{Aspect: "漢堡", Count: 1, AvgSentiment: 0.6, NodeID: intPtr(3), Path: []string{"餐點", "漢堡"}}
+	svc := entity.AspectObservation{Aspect: "服務", Count: 4, AvgSentiment: 0.5, NodeID: intPtr(4), Path: []string{"服務"}}
+	raw := entity.AspectObservation{Aspect: "停車", Count: 1, AvgSentiment: 0.3}
+
+	tests := []struct {
+		name  string
+		data  []entity.AspectObservation
+		level int
+		want  []entity.AspectObservation
+	}{
+		{
+			name:  "level 0 keeps data",
+			data:  []entity.AspectObservation{fries, svc},
+			level: 0,
+			want:  []entity.AspectObservation{fries, svc},
+		},
+		{
+			name:  "empty",
+			data:  nil,
+			level: 1,
+			want:  nil,
+		},
+		{
+			name:  "children roll up to top level with weighted sentiment",
+			data:  []entity.AspectObservation{fries, burger, svc},
+			level: 1,
+			want: []entity.AspectObservation{
+				{Aspect: "餐點", Count: 4, AvgSentiment: 0.3, Path: []string{"餐點"}},
+				{Aspect: "服務", Count: 4, AvgSentiment: 0.5, NodeID: intPtr(4), Path: []string{"服務"}},
+			},
+		},
+		{
+			name:  "parent node keeps its node id",
+			data:  []entity.AspectObservation{food, fries},
+			level: 1,
+			want: []entity.AspectObservation{
+				{Aspect: "餐點", Count: 5, AvgSentiment: 0.44, NodeID: intPtr(1), Path: []string{"餐點"}},
+			},
+		},
+		{
+			name:  "shallower nodes and unmapped aspects stay as is",
+			data:  []entity.AspectObservation{fries, svc, raw},
+			level: 2,
+			want: []entity.AspectObservation{
+				{Aspect: "服務", Count: 4, AvgSentiment: 0.5, NodeID: intPtr(4), Path: []string{"服務"}},
+				{Aspect: "薯條", Count: 3, AvgSentiment: 0.2, NodeID: intPtr(2), Path: []string{"餐點", "薯條"}},
+				{Aspect: "停車", Count: 1, AvgSentiment: 0.3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rollupAspects(tt.data, tt.level)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d aspects %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if math.Abs(g.AvgSentiment-w.AvgSentiment) > 1e-9 {
+					t.Errorf("[%d] %s avg_sentiment = %v, want %v", i, g.Aspect, g.AvgSentiment, w.AvgSentiment)
+				}
+				g.AvgSentiment, w.AvgSentiment = 0, 0
+				if !reflect.DeepEqual(g, w) {
+					t.Errorf("[%d] got %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
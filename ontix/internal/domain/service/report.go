@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// reportMaxEntities 單一報表最多涵蓋的 Entity 數（class 展開時依提及數取前 N）
+	reportMaxEntities = 20
+	// reportSectionLimit 各章節列出的筆數
+	reportSectionLimit = 10
+	// entitySummaryCacheTTL AI 摘要快取時間（與 /api/entities/:id/summary 相同）
+	entitySummaryCacheTTL = 6 * time.Hour
+	// reportClaimLease 排程報表認領後、寫入下次排程前的保留時間（worker 中斷時過期後重新到期）
+	reportClaimLease = 30 * time.Minute
+)
+
+// 報表產出觸發來源
+const (
+	ReportTriggerSchedule = "schedule"
+	ReportTriggerManual   = "manual"
+)
+
+var (
+	// ErrReportNotFound 報表定義不存在
+	ErrReportNotFound = errors.New("report not found")
+	// ErrInvalidReport 報表定義不合法
+	ErrInvalidReport = errors.New("invalid report definition")
+)
+
+// EntitySummaryCacheKey AI 摘要快取 key（/api/entities/:id/summary 與報表共用）
+func EntitySummaryCacheKey(objectID, period string) string {
+	return "entity_summary:" + objectID + ":" + period
+}
+
+// SummaryCache AI 摘要快取（Redis）
+type SummaryCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+}
+
+// PDFRenderer 將自帶樣式的 HTML 轉為 PDF
+type PDFRenderer interface {
+	RenderPDF(ctx context.Context, html string) ([]byte, error)
+}
+
+// MailAttachment Email 附件
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ReportMailer 寄送報表
+type ReportMailer interface {
+	Send(ctx context.Context, to []string, subject, htmlBody string, attachments []MailAttachment) error
+}
+
+// ReportDocument 報表內容（供 HTML 模板渲染）
+type ReportDocument struct {
+	Definition  *entity.ReportDefinition
+	Window      entity.ReportWindow
+	PeriodLabel string
+	GeneratedAt time.Time
+	Entities    []*ReportEntitySection
+}
+
+// ReportEntitySection 單一 Entity 的報表章節資料
+type ReportEntitySection struct {
+	Entity      *entity.ReportEntity
+	KPIs        *entity.ReportKPIs
+	Trend       []*entity.ReportTrendPoint
+	Aspects     []*entity.ReportAspect
+	KOLs        []*entity.ReportKOL
+	Facts       []*entity.ReportFact
+	Summary     *EntitySummaryResult
+	SummaryNote string // AI 摘要無法產生時的說明
+}
+
+// ReportService 排程品牌報表：彙整 KPI / 趨勢 / 面向 / KOL / facts / AI 摘要，輸出 HTML、PDF 並寄送
+type ReportService struct {
+	repo       repository.ReportRepository
+	data       repository.ReportDataRepository
+	summarizer EntitySummaryService
+	cache      SummaryCache
+	pdf        PDFRenderer
+	mailer     ReportMailer
+}
+
+// NewReportService 建立 ReportService
+func NewReportService(repo repository.ReportRepository, data repository.ReportDataRepository, summarizer EntitySummaryService) *ReportService {
+	return &ReportService{repo: repo, data: data, summarizer: summarizer}
+}
+
+// SetSummaryCache 設定 AI 摘要快取（與 /api/entities/:id/summary 共用，避免重複呼叫 LLM）
+func (s *ReportService) SetSummaryCache(cache SummaryCache) {
+	s.cache = cache
+}
+
+// SetPDFRenderer 設定 PDF 轉檔（未設定時只輸出 HTML）
+func (s *ReportService) SetPDFRenderer(pdf PDFRenderer) {
+	s.pdf = pdf
+}
+
+// SetMailer 設定 Email 寄送（未設定時不寄送）
+func (s *ReportService) SetMailer(mailer ReportMailer) {
+	s.mailer = mailer
+}
+
+// Get 取得報表定義
+func (s *ReportService) Get(ctx context.Context, id int64) (*entity.ReportDefinition, error) {
+	def, err := s.repo.FindDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if def == nil {
+		return nil, ErrReportNotFound
+	}
+	return def, nil
+}
+
+// List 列出報表定義
+func (s *ReportService) List(ctx context.Context, offset, limit int) ([]*entity.ReportDefinition, int, error) {
+	return s.repo.ListDefinitions(ctx, offset, limit)
+}
+
+// Create 驗證並建立報表定義（依排程計算下次產出時間）
+func (s *ReportService) Create(ctx context.Context, def *entity.ReportDefinition) error {
+	if err := s.prepare(def, time.Now()); err != nil {
+		return err
+	}
+	return s.repo.CreateDefinition(ctx, def)
+}
+
+// Update 驗證並更新報表定義
+func (s *ReportService) Update(ctx context.Context, def *entity.ReportDefinition) error {
+	if _, err := s.Get(ctx, def.ID); err != nil {
+		return err
+	}
+	if err := s.prepare(def, time.Now()); err != nil {
+		return err
+	}
+	return s.repo.UpdateDefinition(ctx, def)
+}
+
+// Delete 刪除報表定義
+func (s *ReportService) Delete(ctx context.Context, id int64) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteDefinition(ctx, id)
+}
+
+// Runs 列出報表的產出紀錄
+func (s *ReportService) Runs(ctx context.Context, definitionID int64, offset, limit int) ([]*entity.ReportRun, int, error) {
+	return s.repo.ListRuns(ctx, definitionID, offset, limit)
+}
+
+// FindRun 取得產出紀錄（含 HTML / PDF 內容）
+func (s *ReportService) FindRun(ctx context.Context, definitionID, runID int64) (*entity.ReportRun, error) {
+	run, err := s.repo.FindRun(ctx, runID, true)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil || run.DefinitionID != definitionID {
+		return nil, ErrReportNotFound
+	}
+	return run, nil
+}
+
+// prepare 補預設值、驗證欄位並計算下次排程時間
+func (s *ReportService) prepare(def *entity.ReportDefinition, now time.Time) error {
+	def.Name = strings.TrimSpace(def.Name)
+	if def.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidReport)
+	}
+	if len(def.EntityIDs) == 0 && len(def.ClassIDs) == 0 {
+		return fmt.Errorf("%w: entity_ids or class_ids is required", ErrInvalidReport)
+	}
+	if def.Period == "" {
+		def.Period = "1w"
+	}
+	if parsePeriodDays(def.Period) == 0 {
+		return fmt.Errorf("%w: period must be like 1w / 4w / 12w", ErrInvalidReport)
+	}
+	if len(def.Sections) == 0 {
+		def.Sections = entity.AllReportSections
+	}
+	for _, sec := range def.Sections {
+		if !validReportSection(sec) {
+			return fmt.Errorf("%w: unknown section %q", ErrInvalidReport, sec)
+		}
+	}
+	if len(def.Formats) == 0 {
+		def.Formats = []entity.ReportFormat{entity.ReportFormatHTML, entity.ReportFormatPDF}
+	}
+	for _, f := range def.Formats {
+		if f != entity.ReportFormatHTML && f != entity.ReportFormatPDF {
+			return fmt.Errorf("%w: unknown format %q", ErrInvalidReport, f)
+		}
+	}
+	if def.Timezone == "" {
+		def.Timezone = "Asia/Taipei"
+	}
+	loc, err := time.LoadLocation(def.Timezone)
+	if err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidReport, def.Timezone)
+	}
+	for _, r := range def.Recipients {
+		if !strings.Contains(r, "@") {
+			return fmt.Errorf("%w: invalid recipient %q", ErrInvalidReport, r)
+		}
+	}
+
+	def.NextRunAt = nil
+	def.Schedule = strings.TrimSpace(def.Schedule)
+	if def.Schedule != "" {
+		sched, err := ParseCron(def.Schedule)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+		}
+		if def.IsActive {
+			next := sched.Next(now.In(loc))
+			def.NextRunAt = &next
+		}
+	}
+	return nil
+}
+
+// reportLocation 報表設定的時區（無法載入時使用伺服器時區）
+func reportLocation(def *entity.ReportDefinition) *time.Location {
+	loc, err := time.LoadLocation(def.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func validReportSection(section entity.ReportSection) bool {
+	for _, s := range entity.AllReportSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// RunDue 產出所有已到排程時間的報表，回傳產出數量
+func (s *ReportService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	defs, err := s.repo.ClaimDue(ctx, now, now.Add(reportClaimLease))
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for _, def := range defs {
+		// 先推進排程，避免產出失敗時每分鐘重試
+		var next *time.Time
+		if sched, err := ParseCron(def.Schedule); err == nil {
+			t := sched.Next(now.In(reportLocation(def)))
+			next = &t
+		}
+		if err := s.repo.MarkScheduled(ctx, def.ID, now, next); err != nil {
+			return ran, err
+		}
+
+		run, err := s.Run(ctx, def, ReportTriggerSchedule)
+		if err != nil {
+			log.Printf("[report] %d (%s) failed: %v", def.ID, def.Name, err)
+			continue
+		}
+		ran++
+		log.Printf("[report] %d (%s) run %d %s", def.ID, def.Name, run.ID, run.Status)
+	}
+	return ran, nil
+}
+
+// Run 產出一次報表：彙整資料 → HTML → PDF → 寄送，結果存入 report_runs
+// 報表期間以報表設定的時區切日（例如 Asia/Taipei 的 00:00），與伺服器時區無關
+func (s *ReportService) Run(ctx context.Context, def *entity.ReportDefinition, trigger string) (*entity.ReportRun, error) {
+	now := time.Now().In(reportLocation(def))
+	doc := &ReportDocument{
+		Definition:  def,
+		Window:      ReportWindowFor(def.Period, now),
+		PeriodLabel: reportPeriodLabel(def.Period),
+		GeneratedAt: now,
+	}
+	run := &entity.ReportRun{
+		DefinitionID: def.ID,
+		Trigger:      trigger,
+		PeriodStart:  doc.Window.From,
+		PeriodEnd:    doc.Window.To,
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	if err := s.produce(ctx, doc, run); err != nil {
+		run.Status = entity.ReportRunFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = entity.ReportRunSucceeded
+	}
+
+	// 請求被取消時仍要寫入結果
+	if err := s.repo.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// produce 彙整資料並輸出 HTML / PDF、寄送；PDF 或寄送失敗記錄於 run.Error 但不視為整體失敗
+func (s *ReportService) produce(ctx context.Context, doc *ReportDocument, run *entity.ReportRun) error {
+	if err := s.Collect(ctx, doc); err != nil {
+		return err
+	}
+
+	html, err := RenderReportHTML(doc)
+	if err != nil {
+		return err
+	}
+	run.HTML = html
+
+	var problems []string
+	def := doc.Definition
+	if def.HasFormat(entity.ReportFormatPDF) {
+		if s.pdf == nil {
+			problems = append(problems, "pdf: no converter configured")
+		} else if run.PDF, err = s.pdf.RenderPDF(ctx, html); err != nil {
+			problems = append(problems, "pdf: "+err.Error())
+		}
+	}
+
+	if len(def.Recipients) > 0 {
+		if s.mailer == nil {
+			problems = append(problems, "email: no SMTP server configured")
+		} else if err := s.deliver(ctx, doc, run); err != nil {
+			problems = append(problems, "email: "+err.Error())
+		}
+	}
+
+	run.Error = strings.Join(problems, "; ")
+	return nil
+}
+
+func (s *ReportService) deliver(ctx context.Context, doc *ReportDocument, run *entity.ReportRun) error {
+	def := doc.Definition
+	filename := fmt.Sprintf("report-%d-%s", def.ID, doc.Window.To.Format("20060102"))
+	var attachments []MailAttachment
+	if def.HasFormat(entity.ReportFormatHTML) {
+		attachments = append(attachments, MailAttachment{Filename: filename + ".html", ContentType: "text/html; charset=utf-8", Data: []byte(run.HTML)})
+	}
+	if len(run.PDF) > 0 {
+		attachments = append(attachments, MailAttachment{Filename: filename + ".pdf", ContentType: "application/pdf", Data: run.PDF})
+	}
+
+	subject := fmt.Sprintf("%s｜%s ~ %s", def.Name,
+		doc.Window.From.Format("2006/01/02"), doc.Window.To.AddDate(0, 0, -1).Format("2006/01/02"))
+	if err := s.mailer.Send(ctx, def.Recipients, subject, run.HTML, attachments); err != nil {
+		return err
+	}
+	run.DeliveredTo = def.Recipients
+	return nil
+}
+
+// Collect 彙整報表各章節資料
+func (s *ReportService) Collect(ctx context.Context, doc *ReportDocument) error {
+	def := doc.Definition
+	entities, err := s.data.ResolveEntities(ctx, def.EntityIDs, def.ClassIDs, reportMaxEntities)
+	if err != nil {
+		return err
+	}
+	if len(entities) == 0 {
+		return errors.New("no entities matched the report definition")
+	}
+
+	for _, e := range entities {
+		sec := &ReportEntitySection{Entity: e}
+		w := doc.Window
+
+		// KPI 也是 AI 摘要的輸入，一律查詢
+		if sec.KPIs, err = s.data.KPIs(ctx, e.ID, w); err != nil {
+			return err
+		}
+		if def.HasSection(entity.ReportSectionSentimentTrend) || def.HasSection(entity.ReportSectionAISummary) {
+			if sec.Trend, err = s.data.SentimentTrend(ctx, e.ID, w); err != nil {
+				return err
+			}
+		}
+		if def.HasSection(entity.ReportSectionTopAspects) || def.HasSection(entity.ReportSectionAISummary) {
+			if sec.Aspects, err = s.data.TopAspects(ctx, e.ID, w, reportSectionLimit); err != nil {
+				return err
+			}
+		}
+		if def.HasSection(entity.ReportSectionKOLAttribution) {
+			if sec.KOLs, err = s.data.KOLs(ctx, e.ID, w, reportSectionLimit); err != nil {
+				return err
+			}
+		}
+		if def.HasSection(entity.ReportSectionFacts) || def.HasSection(entity.ReportSectionAISummary) {
+			if sec.Facts, err = s.data.Facts(ctx, e.ID, w, reportSectionLimit); err != nil {
+				return err
+			}
+		}
+		if def.HasSection(entity.ReportSectionAISummary) {
+			s.summarize(ctx, doc, sec)
+		}
+		doc.Entities = append(doc.Entities, sec)
+	}
+	return nil
+}
+
+// summarize 取得 AI 摘要：先讀 /api/entities/:id/summary 的快取，沒有再呼叫 GenerateEntitySummary
+func (s *ReportService) summarize(ctx context.Context, doc *ReportDocument, sec *ReportEntitySection) {
+	key := EntitySummaryCacheKey(sec.Entity.ID, doc.Definition.Period)
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, key); err == nil {
+			var result EntitySummaryResult
+			if json.Unmarshal([]byte(cached), &result) == nil && result.Headline != "" {
+				sec.Summary = &result
+				return
+			}
+		}
+	}
+	if s.summarizer == nil {
+		sec.SummaryNote = "AI 摘要未啟用"
+		return
+	}
+
+	samples, err := s.data.NegativeSamples(ctx, sec.Entity.ID, doc.Window, 3)
+	if err != nil {
+		log.Printf("[report] negative samples for %s: %v", sec.Entity.ID, err)
+	}
+	result, err := s.summarizer.GenerateEntitySummary(ctx, buildReportSummaryRequest(doc, sec, samples))
+	if errors.Is(err, ErrBudgetExceeded) {
+		sec.SummaryNote = "今日 LLM 預算已用盡，本期未產生 AI 摘要"
+		return
+	}
+	if err != nil {
+		log.Printf("[report] summary for %s: %v", sec.Entity.ID, err)
+		sec.SummaryNote = "AI 摘要產生失敗"
+		return
+	}
+	sec.Summary = result
+
+	if s.cache != nil {
+		if data, err := json.Marshal(result); err == nil {
+			s.cache.Set(ctx, key, string(data), entitySummaryCacheTTL)
+		}
+	}
+}
+
+// buildReportSummaryRequest 以報表資料組成 GenerateEntitySummary 的輸入（與 /api/entities/:id/summary 相同結構）
+func buildReportSummaryRequest(doc *ReportDocument, sec *ReportEntitySection, samples []string) *EntitySummaryRequest {
+	k := sec.KPIs
+	delta := k.Mentions - k.PrevMentions
+	req := &EntitySummaryRequest{
+		EntityName:  sec.Entity.Name,
+		EntityType:  string(sec.Entity.Type),
+		PeriodLabel: doc.PeriodLabel,
+		Stats: SummaryStats{
+			MentionCount:  k.Mentions,
+			PositiveCount: k.Positive,
+			NegativeCount: k.Negative,
+			NeutralCount:  k.Neutral,
+			AvgSentiment:  k.AvgSentiment,
+			MentionDelta:  &delta,
+		},
+		TrendDir: "持平",
+	}
+	if n := len(sec.Trend); n >= 2 {
+		recent, older := sec.Trend[n-1].Mentions, sec.Trend[0].Mentions
+		if recent > older+2 {
+			req.TrendDir = "上升"
+		} else if recent < older-2 {
+			req.TrendDir = "下降"
+		}
+	}
+	for _, a := range sec.Aspects {
+		sentiment := "neutral"
+		if a.Positive > a.Negative {
+			sentiment = "positive"
+		} else if a.Negative > a.Positive {
+			sentiment = "negative"
+		}
+		ab := AspectBrief{Aspect: a.Aspect, Sentiment: sentiment, PositiveCount: a.Positive, NegativeCount: a.Negative, Total: a.Total}
+		req.TopAspects = append(req.TopAspects, ab)
+		if sentiment == "negative" {
+			req.NegAspects = append(req.NegAspects, ab)
+		}
+	}
+	for _, f := range sec.Facts {
+		req.RecentFacts = append(req.RecentFacts, FactBrief{Type: f.FactType, Severity: f.Severity, Title: f.Title, Description: f.Description})
+	}
+	for _, text := range samples {
+		req.SampleNeg = append(req.SampleNeg, TruncateRunes(text, 150))
+	}
+	return req
+}
+
+// ReportWindowFor 報表期間：以 now 當天 00:00 為結束，往前推 period
+func ReportWindowFor(period string, now time.Time) entity.ReportWindow {
+	days := parsePeriodDays(period)
+	if days == 0 {
+		days = 7
+	}
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return entity.ReportWindow{
+		To:       to,
+		From:     to.AddDate(0, 0, -days),
+		PrevFrom: to.AddDate(0, 0, -2*days),
+	}
+}
+
+func reportPeriodLabel(period string) string {
+	switch period {
+	case "1w":
+		return "近 1 週"
+	case "4w":
+		return "近 4 週"
+	case "12w":
+		return "近 12 週"
+	}
+	return "近 " + strings.TrimSuffix(period, "w") + " 週"
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// RenderReportHTML 將報表渲染為自帶樣式的單一 HTML（無外部資源，可直接寄送或轉 PDF）
+func RenderReportHTML(doc *ReportDocument) (string, error) {
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, doc); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+var (
+	reportBoldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	reportRedPattern  = regexp.MustCompile(`!!(.+?)!!`)
+	// [[名稱|ID]] entity 連結在報表中只顯示名稱
+	reportLinkPattern = regexp.MustCompile(`\[\[([^|\]]+)\|[^\]]+\]\]`)
+)
+
+var reportFuncs = template.FuncMap{
+	"has": func(def *entity.ReportDefinition, section string) bool {
+		return def.HasSection(entity.ReportSection(section))
+	},
+	"date": func(t interface{ Format(string) string }) string {
+		return t.Format("2006/01/02")
+	},
+	"num":   formatThousands,
+	"num64": func(n int64) string { return formatThousands(int(n)) },
+	"f2":    func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) },
+	"f1":    func(f float64) string { return strconv.FormatFloat(f, 'f', 1, 64) },
+	"pct": func(n, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return strconv.FormatFloat(float64(n)/float64(total)*100, 'f', 1, 64) + "%"
+	},
+	"delta": func(cur, prev int) template.HTML {
+		return deltaHTML(float64(cur), float64(prev))
+	},
+	"delta64": func(cur, prev int64) template.HTML {
+		return deltaHTML(float64(cur), float64(prev))
+	},
+	"share": func(n, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(n) / float64(total) * 100
+	},
+	"markdown": func(s string) template.HTML {
+		s = reportLinkPattern.ReplaceAllString(s, "$1")
+		s = template.HTMLEscapeString(s)
+		s = reportBoldPattern.ReplaceAllString(s, "<strong>$1</strong>")
+		s = reportRedPattern.ReplaceAllString(s, `<span class="neg">$1</span>`)
+		return template.HTML(strings.ReplaceAll(s, "\n", "<br>"))
+	},
+	"trendChart": trendChartSVG,
+	"severity": func(s string) string {
+		switch s {
+		case "critical":
+			return "sev-critical"
+		case "warning":
+			return "sev-warning"
+		}
+		return "sev-info"
+	},
+}
+
+func deltaHTML(cur, prev float64) template.HTML {
+	if prev == 0 {
+		return `<span class="muted">—</span>`
+	}
+	d := (cur - prev) / prev * 100
+	class := "pos"
+	if d < 0 {
+		class = "neg"
+	}
+	return template.HTML(fmt.Sprintf(`<span class="%s">%+.1f%%</span>`, class, d))
+}
+
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// trendChartSVG 每週提及數長條 + 平均情感折線（內嵌 SVG）
+func trendChartSVG(points []*entity.ReportTrendPoint) template.HTML {
+	if len(points) == 0 {
+		return `<p class="muted">本期尚無每週觀測資料</p>`
+	}
+	const width, height, pad = 640.0, 180.0, 24.0
+	maxMentions := 1
+	for _, p := range points {
+		maxMentions = max(maxMentions, p.Mentions)
+	}
+
+	slot := (width - 2*pad) / float64(len(points))
+	barW := math.Min(slot*0.6, 48)
+	var bars, labels strings.Builder
+	var line []string
+	for i, p := range points {
+		x := pad + slot*float64(i) + slot/2
+		h := (height - 2*pad) * float64(p.Mentions) / float64(maxMentions)
+		negH := 0.0
+		if p.Mentions > 0 {
+			negH = h * float64(p.Negative) / float64(p.Mentions)
+		}
+		fmt.Fprintf(&bars, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#c7d2fe"/>`, x-barW/2, height-pad-h, barW, h-negH)
+		fmt.Fprintf(&bars, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#fca5a5"/>`, x-barW/2, height-pad-negH, barW, negH)
+		fmt.Fprintf(&labels, `<text x="%.1f" y="%.1f" font-size="10" text-anchor="middle" fill="#64748b">%s</text>`, x, height-6, p.WeekStart.Format("01/02"))
+		y := height - pad - (height-2*pad)*p.AvgSentiment
+		line = append(line, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	return template.HTML(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="100%%" role="img">
+<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#e2e8f0"/>%s%s
+<polyline points="%s" fill="none" stroke="#7c3aed" stroke-width="2"/>
+</svg>
+<p class="legend"><span class="sw" style="background:#c7d2fe"></span>提及數 <span class="sw" style="background:#fca5a5"></span>負面 <span class="sw" style="background:#7c3aed"></span>平均情感（0~1）</p>`,
+		width, height, pad, height-pad, width-pad, height-pad, bars.String(), labels.String(), strings.Join(line, " ")))
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html lang="zh-Hant">
+<head>
+<meta charset="utf-8">
+<title>{{.Definition.Name}}</title>
+<style>
+body { font-family: -apple-system, "Noto Sans TC", "PingFang TC", "Microsoft JhengHei", sans-serif; color: #0f172a; margin: 0; background: #f8fafc; }
+.page { max-width: 760px; margin: 0 auto; padding: 32px 24px; background: #fff; }
+h1 { font-size: 24px; margin: 0 0 4px; }
+h2 { font-size: 20px; margin: 32px 0 8px; padding-bottom: 6px; border-bottom: 2px solid #7c3aed; }
+h3 { font-size: 15px; margin: 20px 0 8px; color: #334155; }
+.muted { color: #64748b; }
+.pos { color: #059669; }
+.neg { color: #dc2626; font-weight: 600; }
+.kpis { display: flex; flex-wrap: wrap; gap: 8px; }
+.kpi { flex: 1 1 150px; border: 1px solid #e2e8f0; border-radius: 8px; padding: 10px 12px; }
+.kpi .label { font-size: 12px; color: #64748b; }
+.kpi .value { font-size: 20px; font-weight: 700; }
+.bar { display: flex; height: 10px; border-radius: 5px; overflow: hidden; background: #e2e8f0; margin-top: 8px; }
+.bar .p { background: #34d399; } .bar .n { background: #f87171; } .bar .u { background: #cbd5e1; }
+table { width: 100%; border-collapse: collapse; font-size: 13px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #e2e8f0; }
+th { color: #64748b; font-weight: 600; }
+td.r, th.r { text-align: right; }
+.summary { background: #f5f3ff; border-radius: 8px; padding: 12px 16px; }
+.summary .headline { font-weight: 700; font-size: 16px; margin-bottom: 6px; }
+.fact { border-left: 4px solid #cbd5e1; padding: 4px 10px; margin: 6px 0; }
+.sev-critical { border-color: #dc2626; } .sev-warning { border-color: #f59e0b; } .sev-info { border-color: #3b82f6; }
+.legend { font-size: 11px; color: #64748b; }
+.sw { display: inline-block; width: 10px; height: 10px; margin: 0 4px 0 10px; vertical-align: middle; }
+footer { margin-top: 40px; font-size: 11px; color: #94a3b8; }
+@media print { body { background: #fff; } .page { padding: 0; } h2 { page-break-after: avoid; } .entity { page-break-before: always; } .entity:first-of-type { page-break-before: auto; } }
+</style>
+</head>
+<body>
+<div class="page">
+<h1>{{.Definition.Name}}</h1>
+<div class="muted">{{.PeriodLabel}}｜{{date .Window.From}} ~ {{date (.Window.To.AddDate 0 0 -1)}}｜對照上期 {{date .Window.PrevFrom}} ~ {{date (.Window.From.AddDate 0 0 -1)}}</div>
+{{$def := .Definition}}
+{{range .Entities}}
+<section class="entity">
+<h2>{{.Entity.Name}} <span class="muted" style="font-size:13px">{{.Entity.Type}}</span></h2>
+
+{{if has $def "kpis"}}{{with .KPIs}}
+<div class="kpis">
+  <div class="kpi"><div class="label">提及數</div><div class="value">{{num .Mentions}}</div><div>{{delta .Mentions .PrevMentions}} vs 上期</div></div>
+  <div class="kpi"><div class="label">貼文數</div><div class="value">{{num .Posts}}</div></div>
+  <div class="kpi"><div class="label">互動數</div><div class="value">{{num64 .Engagement}}</div><div>{{delta64 .Engagement .PrevEngagement}} vs 上期</div></div>
+  <div class="kpi"><div class="label">平均情感</div><div class="value">{{f2 .AvgSentiment}}</div><div class="muted">上期 {{f2 .PrevAvgSentiment}}</div></div>
+</div>
+<div class="bar"><div class="p" style="width:{{f1 (share .Positive .Mentions)}}%"></div><div class="n" style="width:{{f1 (share .Negative .Mentions)}}%"></div><div class="u" style="width:{{f1 (share .Neutral .Mentions)}}%"></div></div>
+<div class="legend">正面 {{pct .Positive .Mentions}}　負面 {{pct .Negative .Mentions}}　中性 {{pct .Neutral .Mentions}}</div>
+{{end}}{{end}}
+
+{{if has $def "ai_summary"}}
+<h3>AI 洞察</h3>
+{{with .Summary}}
+<div class="summary">
+  <div class="headline">{{markdown .Headline}}</div>
+  <div>{{markdown .Body}}</div>
+  {{if .ReasoningChain}}<ol>{{range .ReasoningChain}}<li><strong>{{.Signal}}</strong> → {{.Reasoning}} → {{markdown .Conclusion}}</li>{{end}}</ol>{{end}}
+  {{if .Actions}}<table><tr><th>依據</th><th>建議行動</th><th>目標</th></tr>{{range .Actions}}<tr><td>{{.Trigger}}</td><td>{{.Action}}</td><td>{{.Target}}</td></tr>{{end}}</table>{{end}}
+</div>
+{{else}}<p class="muted">{{.SummaryNote}}</p>{{end}}
+{{end}}
+
+{{if has $def "sentiment_trend"}}
+<h3>聲量與情感走勢</h3>
+{{trendChart .Trend}}
+{{end}}
+
+{{if has $def "top_aspects"}}
+<h3>熱門面向</h3>
+{{if .Aspects}}<table>
+<tr><th>面向</th><th class="r">提及</th><th class="r">正面</th><th class="r">負面</th><th class="r">平均情感</th></tr>
+{{range .Aspects}}<tr><td>{{.Aspect}}</td><td class="r">{{num .Total}}</td><td class="r">{{pct .Positive .Total}}</td><td class="r">{{pct .Negative .Total}}</td><td class="r">{{f2 .AvgSentiment}}</td></tr>{{end}}
+</table>{{else}}<p class="muted">本期無面向資料</p>{{end}}
+{{end}}
+
+{{if has $def "kol_attribution"}}
+<h3>KOL 歸因</h3>
+{{if .KOLs}}<table>
+<tr><th>作者</th><th class="r">粉絲</th><th class="r">貼文</th><th class="r">互動</th><th class="r">平均情感</th><th class="r">貢獻</th></tr>
+{{range .KOLs}}<tr><td>@{{.Author}}</td><td class="r">{{num .Followers}}</td><td class="r">{{num .Posts}}</td><td class="r">{{num64 .Engagement}}</td><td class="r">{{f2 .AvgSentiment}}</td><td class="r">{{f1 .ContributionPct}}%</td></tr>{{end}}
+</table>{{else}}<p class="muted">本期無作者資料</p>{{end}}
+{{end}}
+
+{{if has $def "facts"}}
+<h3>警報與趨勢</h3>
+{{if .Facts}}{{range .Facts}}<div class="fact {{severity .Severity}}"><strong>{{.Title}}</strong> <span class="muted">{{.FactType}} · {{date .CreatedAt}}</span><div>{{.Description}}</div></div>{{end}}
+{{else}}<p class="muted">本期無警報</p>{{end}}
+{{end}}
+</section>
+{{end}}
+<footer>Ontix · 產出時間 {{.GeneratedAt.Format "2006/01/02 15:04"}}</footer>
+</div>
+</body>
+</html>
+`))
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// FewShotReceiver 可注入人工校正範例的 LLM 服務
+// Worker 定期從 review_corrections 撈最新範例並注入 prompt
+type FewShotReceiver interface {
+	SetFewShotExamples(examples []*entity.FewShotExample)
+}
+
+// fewShotContentRunes few-shot 範例貼文截斷長度（避免 prompt 過長）
+const fewShotContentRunes = 150
+
+// FormatAnalysisExamples 將情感 / 軟標籤校正範例格式化為 prompt 片段（無範例時回傳空字串）
+func FormatAnalysisExamples(examples []*entity.FewShotExample) string {
+	var sb strings.Builder
+	for _, ex := range examples {
+		var answer string
+		switch ex.Field {
+		case entity.CorrectionFieldSentiment:
+			answer = fmt.Sprintf("sentiment.label = %v", ex.After["label"])
+		case entity.CorrectionFieldTags:
+			tags, _ := ex.After["tags"].([]any)
+			names := make([]string, 0, len(tags))
+			for _, t := range tags {
+				names = append(names, fmt.Sprint(t))
+			}
+			answer = "soft_tags = " + strings.Join(names, "、")
+		default:
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- 貼文：%s\n  正確答案：%s\n", TruncateRunes(ex.Content, fewShotContentRunes), answer))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "以下是人工校正過的範例，請參考其判斷標準：\n" + sb.String()
+}
+
+// TruncateRunes 以字元（非 byte）截斷字串，避免切斷多位元組中文
+func TruncateRunes(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes]) + "..."
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const (
+	// DefaultSearchVectorK 向量候選數（命中集合中向量那一側的上限）
+	DefaultSearchVectorK = 200
+	// DefaultSearchMinSimilarity 向量候選最低相似度，避免無關貼文進入命中集合
+	DefaultSearchMinSimilarity = 0.3
+	// searchSnippetRunes 片段長度（字元）
+	searchSnippetRunes = 120
+)
+
+// ErrInvalidCursor 分頁游標格式錯誤
+var ErrInvalidCursor = errors.New("invalid search cursor")
+
+// SearchRequest 搜尋請求
+type SearchRequest struct {
+	Query         string
+	Mode          entity.SearchMode
+	Filter        entity.SearchFilter
+	Cursor        string // 上一頁回傳的 next_cursor
+	Limit         int
+	MinSimilarity float64 // 0 = 使用預設值
+}
+
+// SearchResults 搜尋結果
+type SearchResults struct {
+	Hits       []*entity.SearchHit
+	Total      int
+	Facets     *entity.SearchFacets
+	NextCursor string // 空字串 = 沒有下一頁
+	Neighbors  []*entity.TopicNeighbor
+	Mode       entity.SearchMode // 實際使用的模式（embedding 失敗時降級為 keyword）
+}
+
+// SearchService 貼文混合搜尋：向量相似度 + trigram/全文檢索，RRF 融合
+type SearchService struct {
+	embedSvc EmbeddingService
+	repo     repository.SearchRepository
+}
+
+// NewSearchService 建立 SearchService
+func NewSearchService(embedSvc EmbeddingService, repo repository.SearchRepository) *SearchService {
+	return &SearchService{embedSvc: embedSvc, repo: repo}
+}
+
+// Search 執行混合搜尋並產生高亮片段
+func (s *SearchService) Search(ctx context.Context, req SearchRequest) (*SearchResults, error) {
+	q := &entity.SearchQuery{
+		Text:          strings.TrimSpace(req.Query),
+		Terms:         strings.Fields(req.Query),
+		Mode:          req.Mode,
+		Filter:        req.Filter,
+		Limit:         req.Limit,
+		VectorK:       DefaultSearchVectorK,
+		MinSimilarity: req.MinSimilarity,
+	}
+	if q.Mode == "" {
+		q.Mode = entity.SearchModeHybrid
+	}
+	if q.MinSimilarity <= 0 {
+		q.MinSimilarity = DefaultSearchMinSimilarity
+	}
+	if req.Cursor != "" {
+		cursor, err := DecodeSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		q.Cursor = cursor
+	}
+
+	// 1. 查詢向量（hybrid 模式 embedding 失敗時降級為純關鍵字）
+	if q.Mode != entity.SearchModeKeyword {
+		embedding, err := s.embedSvc.Embed(ctx, q.Text)
+		if err != nil {
+			if q.Mode == entity.SearchModeVector {
+				return nil, fmt.Errorf("failed to embed query: %w", err)
+			}
+			log.Printf("[search] embedding failed, falling back to keyword search: %v", err)
+			q.Mode = entity.SearchModeKeyword
+		}
+		q.Embedding = embedding
+	}
+
+	// 2. 混合搜尋
+	page, err := s.repo.HybridSearch(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range page.Hits {
+		h.Snippet = HighlightSnippet(h.Content, q.Terms, searchSnippetRunes)
+	}
+
+	results := &SearchResults{
+		Hits:   page.Hits,
+		Total:  page.Total,
+		Facets: page.Facets,
+		Mode:   q.Mode,
+	}
+	if page.NextCursor != nil {
+		results.NextCursor = EncodeSearchCursor(page.NextCursor)
+	}
+
+	// 3. 語意鄰居：與查詢向量最接近的主題
+	if len(q.Embedding) > 0 {
+		neighbors, err := s.repo.NearestTopics(ctx, q.Embedding, 5)
+		if err != nil {
+			log.Printf("[search] failed to load topic neighbors: %v", err)
+		}
+		results.Neighbors = neighbors
+	}
+	return results, nil
+}
+
+// EncodeSearchCursor 將游標編碼為不透明字串
+func EncodeSearchCursor(c *entity.SearchCursor) string {
+	raw := strconv.FormatFloat(c.Score, 'g', -1, 64) + "|" + c.PostID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSearchCursor 解析 EncodeSearchCursor 產生的游標
+func DecodeSearchCursor(s string) (*entity.SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	scoreStr, postID, ok := strings.Cut(string(raw), "|")
+	if !ok || postID == "" {
+		return nil, ErrInvalidCursor
+	}
+	score, err := strconv.ParseFloat(scoreStr, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &entity.SearchCursor{Score: score, PostID: postID}, nil
+}
+
+// HighlightSnippet 擷取第一個命中關鍵字附近 maxRunes 個字元，並以 <mark> 標示所有命中
+// 以 rune 為單位處理（不會切壞 UTF-8），輸出已 HTML escape；沒有命中時回傳開頭片段
+func HighlightSnippet(content string, terms []string, maxRunes int) string {
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var needles [][]rune
+	for _, t := range terms {
+		n := []rune(t)
+		for i, r := range n {
+			n[i] = unicode.ToLower(r)
+		}
+		if len(n) > 0 {
+			needles = append(needles, n)
+		}
+	}
+
+	matchAt := func(i, end int) int {
+		for _, n := range needles {
+			if i+len(n) <= end && runesEqual(lower[i:i+len(n)], n) {
+				return len(n)
+			}
+		}
+		return 0
+	}
+
+	// 以第一個命中位置為中心（往前保留約 1/3 視窗）
+	first := -1
+	for i := range lower {
+		if matchAt(i, len(lower)) > 0 {
+			first = i
+			break
+		}
+	}
+	start := 0
+	if first > maxRunes/3 {
+		start = first - maxRunes/3
+	}
+	end := start + maxRunes
+	if end > len(runes) {
+		end = len(runes)
+		start = max(0, end-maxRunes)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := matchAt(i, end); n > 0 {
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(string(runes[i : i+n])))
+			sb.WriteString("</mark>")
+			i += n
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+const sentimentRescoreBatchSize = 1000
+
+// SentimentCalibrator 情緒分數校準服務
+// 以人工校正的提及為標註，對每個 model / prompt 版本（/ 語言）擬合 isotonic regression，
+// 將 LLM 原始分數對應到共同尺度；換模型或改 prompt 後重新擬合、重新計分即可避免 avg_sentiment 整體平移
+type SentimentCalibrator struct {
+	repo       repository.SentimentCalibrationRepository
+	minSamples int
+
+	mu     sync.RWMutex
+	active map[string]*entity.SentimentCalibration // model|prompt|language → 啟用中的校準
+}
+
+// NewSentimentCalibrator 建立 SentimentCalibrator
+func NewSentimentCalibrator(repo repository.SentimentCalibrationRepository) *SentimentCalibrator {
+	return &SentimentCalibrator{
+		repo:       repo,
+		minSamples: 30,
+		active:     make(map[string]*entity.SentimentCalibration),
+	}
+}
+
+// SetMinSamples 設定每個 key 最少樣本數
+func (c *SentimentCalibrator) SetMinSamples(minSamples int) {
+	c.minSamples = minSamples
+}
+
+// Load 從資料庫載入啟用中的校準，回傳載入數
+func (c *SentimentCalibrator) Load(ctx context.Context) (int, error) {
+	cals, err := c.repo.ListActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+	c.setActive(cals)
+	return len(cals), nil
+}
+
+func (c *SentimentCalibrator) setActive(cals []*entity.SentimentCalibration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cal := range cals {
+		c.active[sentimentCalibrationKey(cal.Model, cal.PromptVersion, cal.Language)] = cal
+	}
+}
+
+// Find 某 model / prompt 版本 / 語言使用的校準：先找語言專屬，再退回不分語言；沒有時回傳 nil
+func (c *SentimentCalibrator) Find(model, promptVersion, language string) *entity.SentimentCalibration {
+	if c == nil || model == "" {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if language != "" {
+		if cal, ok := c.active[sentimentCalibrationKey(model, promptVersion, language)]; ok {
+			return cal
+		}
+	}
+	return c.active[sentimentCalibrationKey(model, promptVersion, "")]
+}
+
+// ApplyLabeled 同 Apply，有套用校準時一併依校準後分數重新判定標籤（未校準時保留 LLM 的標籤）
+func (c *SentimentCalibrator) ApplyLabeled(scoring *entity.SentimentScoring, label string, raw float64, model, promptVersion, language string) (string, float64) {
+	score := c.Apply(scoring, raw, model, promptVersion, language)
+	if scoring.CalibrationID != nil {
+		label = entity.SentimentLabelFor(label, score)
+	}
+	return label, score
+}
+
+// Apply 記錄原始分數與來源模型並回傳校準後分數（沒有校準時原樣回傳）
+// c 為 nil 時仍會記錄原始分數，之後可用 rescore 補上校準
+func (c *SentimentCalibrator) Apply(scoring *entity.SentimentScoring, raw float64, model, promptVersion, language string) float64 {
+	scoring.RawSentimentScore = &raw
+	scoring.Model = model
+	scoring.PromptVersion = promptVersion
+	scoring.CalibrationID = nil
+
+	cal := c.Find(model, promptVersion, language)
+	if cal == nil {
+		return raw
+	}
+	scoring.CalibrationID = &cal.ID
+	return cal.Apply(raw)
+}
+
+// SentimentCalibrationResult 校準結果
+type SentimentCalibrationResult struct {
+	Samples int                            // 標註樣本數
+	Fitted  []*entity.SentimentCalibration // 成功擬合的 key
+	Skipped map[string]string              // 未擬合的 key 與原因
+}
+
+// Fit 擬合所有 model / prompt 版本的校準；dryRun 時不寫入資料庫
+// 有語言的樣本同時計入不分語言的 key，語言專屬的校準在樣本足夠時才會產生
+func (c *SentimentCalibrator) Fit(ctx context.Context, dryRun bool) (*SentimentCalibrationResult, error) {
+	samples, err := c.repo.ListSamples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &SentimentCalibrationResult{
+		Samples: len(samples),
+		Skipped: make(map[string]string),
+	}
+
+	groups := make(map[string][]*entity.SentimentSample)
+	for _, s := range samples {
+		key := sentimentCalibrationKey(s.Model, s.PromptVersion, "")
+		groups[key] = append(groups[key], s)
+		if s.Language != "" {
+			key = sentimentCalibrationKey(s.Model, s.PromptVersion, s.Language)
+			groups[key] = append(groups[key], s)
+		}
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		ss := groups[key]
+		if len(ss) < c.minSamples {
+			result.Skipped[key] = fmt.Sprintf("too few samples (%d < %d)", len(ss), c.minSamples)
+			continue
+		}
+		rawKnots, calKnots := fitIsotonic(ss)
+		cal := &entity.SentimentCalibration{
+			Model:           ss[0].Model,
+			PromptVersion:   ss[0].PromptVersion,
+			RawKnots:        rawKnots,
+			CalibratedKnots: calKnots,
+			SampleCount:     len(ss),
+			FittedAt:        time.Now(),
+		}
+		if key != sentimentCalibrationKey(cal.Model, cal.PromptVersion, "") {
+			cal.Language = ss[0].Language
+		}
+		cal.MAEBefore, cal.MAEAfter = sentimentMAE(cal, ss)
+
+		if !dryRun {
+			if err := c.repo.SaveCalibration(ctx, cal); err != nil {
+				return nil, err
+			}
+		}
+		result.Fitted = append(result.Fitted, cal)
+	}
+
+	if !dryRun {
+		c.setActive(result.Fitted)
+	}
+	return result, nil
+}
+
+// SentimentRescoreResult 重新計分結果
+type SentimentRescoreResult struct {
+	Mentions int64      // 更新的提及數
+	Aspects  int64      // 更新的面向數
+	Since    *time.Time // 受影響資料最早的 created_at（重新聚合觀測用），沒有更新時為 nil
+}
+
+// Rescore 以啟用中的校準重新計算歷史提及 / 面向的 sentiment_score 與標籤（只處理尚未套用該校準的資料）
+func (c *SentimentCalibrator) Rescore(ctx context.Context) (*SentimentRescoreResult, error) {
+	result := &SentimentRescoreResult{}
+
+	c.mu.RLock()
+	cals := make([]*entity.SentimentCalibration, 0, len(c.active))
+	for _, cal := range c.active {
+		cals = append(cals, cal)
+	}
+	c.mu.RUnlock()
+
+	// 不分語言的校準不處理已有語言專屬校準的語言（與 Find 的選擇一致）
+	specific := make(map[string][]string)
+	for _, cal := range cals {
+		if cal.Language != "" {
+			key := sentimentCalibrationKey(cal.Model, cal.PromptVersion, "")
+			specific[key] = append(specific[key], cal.Language)
+		}
+	}
+
+	for _, cal := range cals {
+		var exclude []string
+		if cal.Language == "" {
+			exclude = specific[sentimentCalibrationKey(cal.Model, cal.PromptVersion, "")]
+		}
+		for _, kind := range []string{"mention", "aspect"} {
+			n, err := c.rescoreKind(ctx, kind, cal, exclude, result)
+			if err != nil {
+				return result, err
+			}
+			if kind == "mention" {
+				result.Mentions += n
+			} else {
+				result.Aspects += n
+			}
+		}
+	}
+	return result, nil
+}
+
+// rescoreKind 依 id 分批重新計分某一種資料
+func (c *SentimentCalibrator) rescoreKind(ctx context.Context, kind string, cal *entity.SentimentCalibration, exclude []string, result *SentimentRescoreResult) (int64, error) {
+	var done int64
+	var afterID int64
+	for {
+		rows, err := c.repo.ListStale(ctx, kind, cal.Model, cal.PromptVersion, cal.Language, exclude, cal.ID, afterID, sentimentRescoreBatchSize)
+		if err != nil {
+			return done, err
+		}
+		if len(rows) == 0 {
+			return done, nil
+		}
+		ids := make([]int64, len(rows))
+		scores := make([]float64, len(rows))
+		labels := make([]string, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ID
+			scores[i] = cal.Apply(row.Raw)
+			labels[i] = entity.SentimentLabelFor(row.Sentiment, scores[i])
+			if result.Since == nil || row.CreatedAt.Before(*result.Since) {
+				t := row.CreatedAt
+				result.Since = &t
+			}
+		}
+		if err := c.repo.UpdateScores(ctx, kind, ids, scores, labels, cal.ID); err != nil {
+			return done, err
+		}
+		done += int64(len(rows))
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// fitIsotonic 以 PAV（pool adjacent violators）擬合非遞減的原始分數 → 目標分數對應
+// 相同原始分數先合併；回傳每個區塊的平均原始分數（嚴格遞增）與平均目標分數
+func fitIsotonic(samples []*entity.SentimentSample) (rawKnots, calKnots []float64) {
+	sorted := make([]*entity.SentimentSample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Raw < sorted[j].Raw })
+
+	type block struct {
+		sumX, sumY, n float64
+	}
+	var blocks []block
+	for i, s := range sorted {
+		if i > 0 && s.Raw == sorted[i-1].Raw {
+			b := &blocks[len(blocks)-1]
+			b.sumX += s.Raw
+			b.sumY += s.Target
+			b.n++
+		} else {
+			blocks = append(blocks, block{sumX: s.Raw, sumY: s.Target, n: 1})
+		}
+		// 合併違反單調性的相鄰區塊
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sumY/prev.n <= last.sumY/last.n {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sumX: prev.sumX + last.sumX, sumY: prev.sumY + last.sumY, n: prev.n + last.n}
+		}
+	}
+
+	rawKnots = make([]float64, len(blocks))
+	calKnots = make([]float64, len(blocks))
+	for i, b := range blocks {
+		rawKnots[i] = b.sumX / b.n
+		calKnots[i] = b.sumY / b.n
+	}
+	return rawKnots, calKnots
+}
+
+// sentimentMAE 校準前 / 後與目標分數的平均絕對誤差
+func sentimentMAE(cal *entity.SentimentCalibration, samples []*entity.SentimentSample) (before, after float64) {
+	for _, s := range samples {
+		before += math.Abs(s.Raw - s.Target)
+		after += math.Abs(cal.Apply(s.Raw) - s.Target)
+	}
+	n := float64(len(samples))
+	return before / n, after / n
+}
+
+func sentimentCalibrationKey(model, promptVersion, language string) string {
+	return model + "|" + promptVersion + "|" + language
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+func TestFitIsotonic(t *testing.T) {
+	samples := func(pairs ...float64) []*entity.SentimentSample {
+		out := make([]*entity.SentimentSample, 0, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			out = append(out, &entity.SentimentSample{Raw: pairs[i], Target: pairs[i+1]})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		samples  []*entity.SentimentSample
+		wantRaw  []float64
+		wantCals []float64
+	}{
+		{
+			name:     "empty",
+			samples:  nil,
+			wantRaw:  []float64{},
+			wantCals: []float64{},
+		},
+		{
+			name:     "already monotone",
+			samples:  samples(0.1, 0.2, 0.5, 0.5, 0.9, 0.8),
+			wantRaw:  []float64{0.1, 0.5, 0.9},
+			wantCals: []float64{0.2, 0.5, 0.8},
+		},
+		{
+			name:     "unsorted input",
+			samples:  samples(0.9, 0.8, 0.1, 0.2, 0.5, 0.5),
+			wantRaw:  []float64{0.1, 0.5, 0.9},
+			wantCals: []float64{0.2, 0.5, 0.8},
+		},
+		{
+			name:     "equal raw scores merge",
+			samples:  samples(0.5, 0.4, 0.5, 0.6, 0.8, 0.9),
+			wantRaw:  []float64{0.5, 0.8},
+			wantCals: []float64{0.5, 0.9},
+		},
+		{
+			name:     "violators pooled",
+			samples:  samples(0.2, 0.6, 0.4, 0.2, 0.8, 0.9),
+			wantRaw:  []float64{0.3, 0.8},
+			wantCals: []float64{0.4, 0.9},
+		},
+		{
+			name:     "pooling cascades backwards",
+			samples:  samples(0.1, 0.5, 0.2, 0.6, 0.3, 0.1),
+			wantRaw:  []float64{0.2},
+			wantCals: []float64{0.4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, cals := fitIsotonic(tt.samples)
+			assertFloats(t, "raw knots", raw, tt.wantRaw)
+			assertFloats(t, "calibrated knots", cals, tt.wantCals)
+			for i := 1; i < len(raw); i++ {
+				if raw[i] <= raw[i-1] || cals[i] < cals[i-1] {
+					t.Errorf("knots not monotone at %d: raw %v, calibrated %v", i, raw, cals)
+				}
+			}
+		})
+	}
+}
+
+func assertFloats(t *testing.T, what string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("%s = %v, want %v", what, got, want)
+		}
+	}
+}
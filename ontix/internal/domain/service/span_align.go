@@ -0,0 +1,118 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+const (
+	// MinSpanAlignScore 模糊比對的最低相似度（1 - 編輯距離 / 片段長度），低於此值視為對不上
+	MinSpanAlignScore = 0.6
+
+	spanScoreExact      = 1.0
+	spanScoreNormalized = 0.95 // 全半形、簡繁、大小寫、標點差異
+)
+
+// AlignSpan 找出 LLM 回傳的片段在原文中的位置（rune，End 不含）
+// LLM 的 mention_text 常不是原文逐字（改寫標點、簡繁、漏字），依序嘗試：
+//  1. 原文精確比對
+//  2. 正規化後精確比對
+//  3. 正規化後的近似子字串比對（Sellers edit distance）
+//
+// [from, to) 為優先搜尋範圍（長文分段抽取時為片段所在的段落，to <= 0 表示到結尾）；
+// 範圍內找不到時再搜尋全文。對不上時回傳 nil
+func AlignSpan(text, snippet string, from, to int) (*entity.TextSpan, float64) {
+	snippet = strings.TrimSpace(snippet)
+	if snippet == "" || text == "" {
+		return nil, 0
+	}
+
+	n := utf8.RuneCountInString(text)
+	if to <= 0 || to > n {
+		to = n
+	}
+	if from < 0 || from >= to {
+		from = 0
+	}
+
+	if span, score := alignIn(text, snippet, from, to); span != nil {
+		return span, score
+	}
+	if from > 0 || to < n {
+		return alignIn(text, snippet, 0, n)
+	}
+	return nil, 0
+}
+
+// alignIn 在 text 的 [from, to) 範圍內比對
+func alignIn(text, snippet string, from, to int) (*entity.TextSpan, float64) {
+	window := text
+	if from > 0 || to < utf8.RuneCountInString(text) {
+		window = string([]rune(text)[from:to])
+	}
+
+	// 1. 精確
+	if i := strings.Index(window, snippet); i >= 0 {
+		start := from + utf8.RuneCountInString(window[:i])
+		return &entity.TextSpan{Start: start, End: start + utf8.RuneCountInString(snippet)}, spanScoreExact
+	}
+
+	// 2. 正規化後精確
+	norm := NormalizeForMatch(window)
+	pattern := []rune(NormalizeAlias(snippet))
+	if len(pattern) == 0 || norm.Text == "" {
+		return nil, 0
+	}
+	if i := strings.Index(norm.Text, string(pattern)); i >= 0 {
+		s := utf8.RuneCountInString(norm.Text[:i])
+		start, end := norm.Span(s, s+len(pattern))
+		return &entity.TextSpan{Start: from + start, End: from + end}, spanScoreNormalized
+	}
+
+	// 3. 近似子字串
+	s, e, dist := approxSubstring([]rune(norm.Text), pattern)
+	score := 1 - float64(dist)/float64(len(pattern))
+	if s >= e || score < MinSpanAlignScore {
+		return nil, 0
+	}
+	start, end := norm.Span(s, e)
+	return &entity.TextSpan{Start: from + start, End: from + end}, score
+}
+
+// approxSubstring Sellers 演算法：找出 text 中與 pattern 編輯距離最小的子字串 [start, end)
+func approxSubstring(text, pattern []rune) (int, int, int) {
+	m := len(pattern)
+	dist := make([]int, m+1)   // 目前 text 位置的 D[i]
+	starts := make([]int, m+1) // 對應子字串的起點
+	for i := range dist {
+		dist[i] = i
+	}
+
+	bestDist, bestStart, bestEnd := m, 0, 0
+	for j := 1; j <= len(text); j++ {
+		diag, diagStart := dist[0], starts[0]
+		dist[0], starts[0] = 0, j // 子字串可從任意位置開始
+		for i := 1; i <= m; i++ {
+			up, upStart := dist[i], starts[i]
+			cost := 1
+			if pattern[i-1] == text[j-1] {
+				cost = 0
+			}
+			d, st := diag+cost, diagStart
+			if up+1 < d {
+				d, st = up+1, upStart
+			}
+			if dist[i-1]+1 < d {
+				d, st = dist[i-1]+1, starts[i-1]
+			}
+			diag, diagStart = up, upStart
+			dist[i], starts[i] = d, st
+		}
+		if dist[m] < bestDist {
+			bestDist, bestStart, bestEnd = dist[m], starts[m], j
+		}
+	}
+	return bestStart, bestEnd, bestDist
+}
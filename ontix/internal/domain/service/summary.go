@@ -22,6 +22,14 @@ type EntityChatRequest struct {
 type EntitySummaryService interface {
 	GenerateEntitySummary(ctx context.Context, req *EntitySummaryRequest) (*EntitySummaryResult, error)
 	StreamEntityChat(ctx context.Context, req *EntityChatRequest) (<-chan string, <-chan error)
+	SummarizeChatHistory(ctx context.Context, req *ChatHistorySummaryRequest) (string, error)
+}
+
+// ChatHistorySummaryRequest 將較舊的對話摘要，取代直接截斷歷史
+type ChatHistorySummaryRequest struct {
+	EntityName      string
+	PreviousSummary string        // 先前的摘要（可為空）
+	Messages        []ChatMessage // 要併入摘要的對話
 }
 
 // EntitySummaryRequest 摘要生成請求
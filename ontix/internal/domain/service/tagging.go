@@ -6,6 +6,16 @@ import "context"
 type TaggingService interface {
 	// AnalyzePost 分析貼文，回傳完整標註結果
 	AnalyzePost(ctx context.Context, content string) (*PostAnalysis, error)
+	// AnalyzeComment 分析留言 / 回覆，以所屬貼文（與上層留言）作為上下文，只標註留言本身
+	AnalyzeComment(ctx context.Context, content string, thread ThreadContext) (*PostAnalysis, error)
+}
+
+// ThreadContext 留言的串上下文
+type ThreadContext struct {
+	PostContent   string // 串的原始貼文
+	PostAuthor    string
+	ParentContent string // 回覆的上層留言（頂層留言為空）
+	ParentAuthor  string
 }
 
 // PostAnalysis 貼文分析結果
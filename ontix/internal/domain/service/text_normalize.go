@@ -0,0 +1,140 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizedText 比對用的正規化文字，保留與原文的 rune 位置對應
+type NormalizedText struct {
+	Text  string
+	index []int // 正規化後第 i 個 rune 對應原文的 rune 位置
+}
+
+// Span 將正規化文字上的 [start, end) 換算回原文 rune 位置
+func (n *NormalizedText) Span(start, end int) (int, int) {
+	if start >= end || end > len(n.index) {
+		return 0, 0
+	}
+	return n.index[start], n.index[end-1] + 1
+}
+
+// NormalizeForMatch 別名比對用的正規化：
+//   - 全形英數與符號轉半形、全形空白轉半形
+//   - 簡體轉繁體（常用字表，僅求兩邊一致，不做詞彙轉換）
+//   - 英文轉小寫
+//   - 連字號、句點、間隔號等連接符號去除（7-11 = 711、Mr.Brown = MrBrown），其餘標點視為空白
+//   - 連續空白合併為一個
+func NormalizeForMatch(s string) *NormalizedText {
+	var b strings.Builder
+	b.Grow(len(s))
+	index := make([]int, 0, len(s))
+	lastSpace := true // 開頭的空白直接略過
+
+	i := 0
+	for _, r := range s {
+		pos := i
+		i++
+
+		r = foldWidth(r)
+		if t, ok := simplifiedToTraditional[r]; ok {
+			r = t
+		}
+		r = unicode.ToLower(r)
+
+		switch {
+		case isConnector(r):
+			continue
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			if lastSpace {
+				continue
+			}
+			r = ' '
+			lastSpace = true
+		default:
+			lastSpace = false
+		}
+		b.WriteRune(r)
+		index = append(index, pos)
+	}
+
+	text := b.String()
+	if lastSpace && len(index) > 0 { // 去掉結尾空白
+		text = text[:len(text)-1]
+		index = index[:len(index)-1]
+	}
+	return &NormalizedText{Text: text, index: index}
+}
+
+// NormalizeAlias 別名的正規化形式（用於建立字典）
+func NormalizeAlias(alias string) string {
+	return NormalizeForMatch(alias).Text
+}
+
+// foldWidth 全形 → 半形
+func foldWidth(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// isConnector 名稱內常見的連接符號（去除而非視為空白）
+func isConnector(r rune) bool {
+	if unicode.Is(unicode.Pd, r) {
+		return true
+	}
+	switch r {
+	case '.', '\'', '’', '‘', '_', '·', '・', '‧':
+		return true
+	}
+	return false
+}
+
+// simplifiedToTraditional 簡 → 繁常用字對照（品牌、商品、地名常見字）
+var simplifiedToTraditional = func() map[rune]rune {
+	const pairs = `
+万萬 与與 专專 业業 丛叢 东東 丝絲 两兩 严嚴 丧喪 个個 丰豐 临臨 为為 丽麗 举舉 义義 乌烏 乐樂 乔喬
+习習 乡鄉 书書 买買 乱亂 争爭 亏虧 云雲 亚亞 产產 亲親 亿億 仅僅 从從 仓倉 仪儀 们們 价價 众眾 优優
+会會 伞傘 伟偉 传傳 伤傷 伦倫 伪偽 体體 佣傭 侠俠 侣侶 侦偵 俭儉 债債 倾傾 偿償 储儲 儿兒 党黨 兰蘭
+关關 兴興 养養 兽獸 内內 冈岡 册冊 写寫 军軍 农農 冯馮 冲衝 决決 况況 冻凍 净淨 凉涼 减減 凤鳳 击擊
+刘劉 则則 刚剛 创創 删刪 别別 剂劑 剑劍 剧劇 劝勸 办辦 务務 动動 励勵 劲勁 劳勞 势勢 区區 医醫 华華
+协協 单單 卖賣 卢盧 卫衛 却卻 厂廠 厅廳 历歷 压壓 厌厭 县縣 参參 双雙 变變 叙敘 叶葉 号號 叹嘆 吗嗎
+吨噸 听聽 启啟 吴吳 员員 响響 唤喚 喷噴 团團 园園 围圍 图圖 圆圓 圣聖 场場 坏壞 块塊 坚堅 坛壇 垒壘
+报報 墙牆 壮壯 声聲 壳殼 处處 备備 够夠 头頭 夹夾 夺奪 奋奮 奖獎 妆妝 妇婦 妈媽 娱娛 婴嬰 孙孫 学學
+宁寧 宝寶 实實 宠寵 审審 宪憲 宽寬 宾賓 对對 寻尋 导導 寿壽 将將 尔爾 尘塵 尝嘗 层層 属屬 岁歲 岛島
+岭嶺 币幣 师師 帐帳 带帶 帮幫 广廣 庄莊 庆慶 库庫 应應 废廢 开開 异異 弃棄 张張 弹彈 强強 归歸 当當
+录錄 彻徹 径徑 忆憶 态態 怀懷 总總 恋戀 恶惡 悬懸 惊驚 惯慣 愤憤 愿願 懒懶 战戰 户戶 扑撲 执執 扩擴
+扫掃 扬揚 护護 担擔 拟擬 拥擁 择擇 挂掛 挤擠 挥揮 损損 换換 据據 搅攪 携攜 摄攝 摆擺 摇搖 撑撐 数數
+敌敵 断斷 无無 旧舊 时時 显顯 晒曬 晓曉 暂暫 术術 机機 杀殺 杂雜 权權 条條 来來 杨楊 极極 构構 枪槍
+柜櫃 标標 栏欄 树樹 样樣 桥橋 档檔 梦夢 检檢 楼樓 欢歡 欧歐 残殘 毕畢 气氣 汇匯 汉漢 汤湯 沟溝 没沒
+沪滬 泪淚 泽澤 洁潔 浅淺 测測 济濟 浓濃 涂塗 润潤 涨漲 温溫 湾灣 湿濕 满滿 滚滾 灭滅 灯燈 灵靈 灾災
+炉爐 点點 炼煉 烟煙 烦煩 烧燒 热熱 爱愛 爷爺 牵牽 犹猶 狮獅 独獨 狱獄 猎獵 猫貓 献獻 环環 现現 电電
+画畫 畅暢 疗療 盐鹽 监監 盘盤 矿礦 码碼 砖磚 础礎 确確 礼禮 祸禍 离離 种種 积積 称稱 稳穩 穷窮 窃竊
+竞競 笔筆 笼籠 筑築 签簽 简簡 类類 粮糧 紧緊 纠糾 红紅 约約 级級 纪紀 纯純 纱紗 纳納 纸紙 纹紋 线線
+练練 组組 细細 织織 终終 经經 结結 绕繞 绘繪 给給 络絡 统統 继繼 绩績 续續 维維 绵綿 综綜 绿綠 缓緩
+编編 缘緣 缩縮 网網 罗羅 罚罰 职職 联聯 肃肅 肠腸 肤膚 胜勝 脑腦 脚腳 脸臉 腊臘 舰艦 艺藝 节節 芦蘆
+苏蘇 苹蘋 范範 荐薦 药藥 莲蓮 获獲 萝蘿 营營 蓝藍 虑慮 虫蟲 虽雖 蚁蟻 补補 装裝 见見 观觀 规規 视視
+览覽 觉覺 计計 订訂 认認 讨討 让讓 训訓 议議 讯訊 记記 讲講 许許 论論 设設 访訪 证證 评評 识識 诉訴
+词詞 译譯 试試 诗詩 诚誠 话話 询詢 该該 详詳 语語 误誤 说說 请請 诸諸 读讀 课課 谁誰 调調 谈談 谢謝
+谱譜 贝貝 负負 贡貢 财財 责責 败敗 货貨 质質 贩販 贪貪 购購 贯貫 贴貼 贵貴 贷貸 贸貿 费費 资資 赏賞
+赔賠 赛賽 赞讚 赠贈 赵趙 趋趨 跃躍 践踐 踪蹤 车車 轨軌 转轉 轮輪 软軟 轻輕 载載 较較 辅輔 辆輛 辈輩
+边邊 达達 迁遷 过過 运運 还還 这這 进進 远遠 违違 连連 迟遲 选選 递遞 逻邏 遗遺 邓鄧 邮郵 邻鄰 郑鄭
+酱醬 释釋 鉴鑑 针針 钉釘 钓釣 钙鈣 钢鋼 钥鑰 钻鑽 铁鐵 铃鈴 铅鉛 银銀 铺鋪 链鏈 销銷 锁鎖 锅鍋 错錯
+锦錦 键鍵 镇鎮 镜鏡 长長 门門 闪閃 闭閉 问問 闯闖 闲閒 间間 闹鬧 闻聞 阀閥 阅閱 队隊 阳陽 阴陰 阵陣
+阶階 际際 陆陸 陈陳 险險 随隨 隐隱 难難 雾霧 静靜 韩韓 页頁 顶頂 项項 顺順 须須 顾顧 顿頓 预預 领領
+频頻 题題 颜顏 额額 风風 飞飛 饥飢 饭飯 饮飲 饰飾 饱飽 饼餅 馆館 马馬 驱驅 驶駛 驻駐 验驗 骑騎 骗騙
+鱼魚 鲁魯 鲜鮮 鸟鳥 鸡雞 鸭鴨 鹅鵝 麦麥 黄黃 齐齊 齿齒 龙龍 龟龜 霉黴
+`
+	m := make(map[rune]rune)
+	for _, p := range strings.Fields(pairs) {
+		r := []rune(p)
+		if len(r) == 2 && r[0] != r[1] {
+			m[r[0]] = r[1]
+		}
+	}
+	return m
+}()
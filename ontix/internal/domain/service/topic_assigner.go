@@ -2,8 +2,8 @@ package service
 
 import (
 	"context"
+	"math"
 	"sort"
-	"strings"
 
 	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/repository"
@@ -19,13 +19,16 @@ type TopicAssignResult struct {
 	Assigned     bool
 	IsAmbiguous  bool    // 是否模糊分類 (top1-top2 gap < 0.02)
 	KeywordBoost float64 // 關鍵字加權值
+	Probability  float64 // 校準後機率（多標籤模式）
+	Margin       float64 // 機率與門檻的距離（多標籤模式；越小越不確定）
 }
 
 // KeywordRule 關鍵字加權規則
 type KeywordRule struct {
-	Keywords  []string // 關鍵字列表
-	TopicCode string   // 目標主題代碼
-	Boost     float64  // 加權值
+	Keywords  []string        // 關鍵字列表
+	TopicCode string          // 目標主題代碼
+	Boost     float64         // 加權值
+	Language  entity.Language // 適用語言（空字串 = 所有語言）
 }
 
 // 預設關鍵字規則（根據數據分析結果）
@@ -52,6 +55,30 @@ var defaultKeywordRules = []KeywordRule{
 
 	// 科技
 	{Keywords: []string{"手機", "電腦", "3C", "科技", "APP", "軟體"}, TopicCode: "technology", Boost: 0.05},
+
+	// 英文（拉丁字母關鍵字以完整字詞比對，複數自動涵蓋）
+	{Keywords: []string{"haircut", "hair color", "hairstyle", "salon", "makeup", "lipstick", "eyeliner", "nail art", "manicure"}, TopicCode: "beauty_and_fashion", Boost: 0.05, Language: entity.LanguageEnglish},
+	{Keywords: []string{"workout", "gym", "weight loss", "diet", "fitness", "supplement", "vitamin"}, TopicCode: "health", Boost: 0.05, Language: entity.LanguageEnglish},
+	{Keywords: []string{"restaurant", "delicious", "recipe", "dessert", "brunch", "cafe", "foodie"}, TopicCode: "food", Boost: 0.05, Language: entity.LanguageEnglish},
+	{Keywords: []string{"travel", "trip", "itinerary", "hotel", "flight", "sightseeing", "vacation"}, TopicCode: "travel", Boost: 0.05, Language: entity.LanguageEnglish},
+	{Keywords: []string{"puppy", "kitten", "dog", "cat", "pet"}, TopicCode: "pets", Boost: 0.05, Language: entity.LanguageEnglish},
+	{Keywords: []string{"smartphone", "laptop", "gadget", "app", "software", "tech"}, TopicCode: "technology", Boost: 0.05, Language: entity.LanguageEnglish},
+
+	// 馬來文
+	{Keywords: []string{"rambut", "solekan", "gincu", "kuku", "salun"}, TopicCode: "beauty_and_fashion", Boost: 0.05, Language: entity.LanguageMalay},
+	{Keywords: []string{"kurus", "diet", "senaman", "berat badan", "kesihatan"}, TopicCode: "health", Boost: 0.05, Language: entity.LanguageMalay},
+	{Keywords: []string{"sedap", "makan", "restoran", "kedai makan", "resipi", "nasi lemak", "mamak"}, TopicCode: "food", Boost: 0.05, Language: entity.LanguageMalay},
+	{Keywords: []string{"melancong", "percutian", "hotel", "tiket kapal terbang", "tempat menarik"}, TopicCode: "travel", Boost: 0.05, Language: entity.LanguageMalay},
+	{Keywords: []string{"kucing", "anjing", "haiwan peliharaan"}, TopicCode: "pets", Boost: 0.05, Language: entity.LanguageMalay},
+	{Keywords: []string{"telefon", "komputer", "aplikasi", "gajet"}, TopicCode: "technology", Boost: 0.05, Language: entity.LanguageMalay},
+
+	// 日文
+	{Keywords: []string{"ヘアカラー", "美容院", "美容室", "メイク", "コスメ", "ネイル"}, TopicCode: "beauty_and_fashion", Boost: 0.05, Language: entity.LanguageJapanese},
+	{Keywords: []string{"ダイエット", "筋トレ", "ジム", "サプリ"}, TopicCode: "health", Boost: 0.05, Language: entity.LanguageJapanese},
+	{Keywords: []string{"美味しい", "おいしい", "レストラン", "カフェ", "ランチ", "スイーツ", "レシピ"}, TopicCode: "food", Boost: 0.05, Language: entity.LanguageJapanese},
+	{Keywords: []string{"旅行", "観光", "ホテル", "旅館"}, TopicCode: "travel", Boost: 0.05, Language: entity.LanguageJapanese},
+	{Keywords: []string{"ワンちゃん", "猫", "ペット"}, TopicCode: "pets", Boost: 0.05, Language: entity.LanguageJapanese},
+	{Keywords: []string{"スマホ", "パソコン", "アプリ", "ガジェット"}, TopicCode: "technology", Boost: 0.05, Language: entity.LanguageJapanese},
 }
 
 // TopicAssigner 主題分配服務
@@ -62,18 +89,24 @@ type TopicAssigner struct {
 	ambiguousGap    float64 // 模糊分類的 gap 閾值
 	topicCache      []*entity.Topic
 	keywordRules    []KeywordRule
-	enableKeywords  bool // 是否啟用關鍵字加權
+	enableKeywords  bool                             // 是否啟用關鍵字加權
+	calibrations    map[int]*entity.TopicCalibration // 各主題機率校準（多標籤模式）
+	maxLabels       int                              // 多標籤模式每篇貼文最多標籤數
+	ambiguousMargin float64                          // 多標籤模式：機率距門檻小於此值視為模糊
 }
 
 // NewTopicAssigner 建立 TopicAssigner
 func NewTopicAssigner(topicRepo repository.TopicRepository) *TopicAssigner {
 	return &TopicAssigner{
-		topicRepo:      topicRepo,
-		threshold:      0.25, // 主題分類閾值（降低以提高召回率）
-		highThreshold:  0.40, // 高信心閾值
-		ambiguousGap:   0.02, // gap < 0.02 視為模糊
-		keywordRules:   defaultKeywordRules,
-		enableKeywords: true, // 預設啟用關鍵字加權
+		topicRepo:       topicRepo,
+		threshold:       0.25, // 主題分類閾值（降低以提高召回率）
+		highThreshold:   0.40, // 高信心閾值
+		ambiguousGap:    0.02, // gap < 0.02 視為模糊
+		keywordRules:    defaultKeywordRules,
+		enableKeywords:  true, // 預設啟用關鍵字加權
+		calibrations:    make(map[int]*entity.TopicCalibration),
+		maxLabels:       3,
+		ambiguousMargin: 0.1,
 	}
 }
 
@@ -87,17 +120,20 @@ func (a *TopicAssigner) SetKeywordRules(rules []KeywordRule) {
 	a.keywordRules = rules
 }
 
-// calculateKeywordBoosts 計算每個主題的關鍵字加權
-func (a *TopicAssigner) calculateKeywordBoosts(content string) map[string]float64 {
+// calculateKeywordBoosts 計算每個主題的關鍵字加權（只套用不限語言或與內容語言相同的規則）
+func (a *TopicAssigner) calculateKeywordBoosts(content string, lang entity.Language) map[string]float64 {
 	boosts := make(map[string]float64)
 	if !a.enableKeywords || content == "" {
 		return boosts
 	}
 
-	contentLower := strings.ToLower(content)
+	text := newKeywordText(content)
 	for _, rule := range a.keywordRules {
+		if rule.Language != entity.LanguageUnknown && rule.Language != lang {
+			continue
+		}
 		for _, keyword := range rule.Keywords {
-			if strings.Contains(contentLower, strings.ToLower(keyword)) {
+			if text.contains(keyword) {
 				// 累加 boost（同一主題可能多個關鍵字命中）
 				if boosts[rule.TopicCode] < rule.Boost {
 					boosts[rule.TopicCode] = rule.Boost // 取最大值而非累加，避免過度加權
@@ -144,7 +180,7 @@ func (a *TopicAssigner) AssignWithContent(ctx context.Context, embedding entity.
 	}
 
 	// 計算關鍵字加權
-	keywordBoosts := a.calculateKeywordBoosts(content)
+	keywordBoosts := a.calculateKeywordBoosts(content, ResolveLanguage(ctx, content))
 
 	// 計算與所有主題的相似度
 	type match struct {
@@ -330,3 +366,112 @@ func (a *TopicAssigner) GetTopMatches(ctx context.Context, embedding entity.Vect
 	}
 	return results, nil
 }
+
+// SetMaxLabels 設定多標籤模式每篇貼文最多標籤數
+func (a *TopicAssigner) SetMaxLabels(n int) {
+	if n > 0 {
+		a.maxLabels = n
+	}
+}
+
+// SetCalibrations 設定主題機率校準參數
+func (a *TopicAssigner) SetCalibrations(cals []*entity.TopicCalibration) {
+	a.calibrations = make(map[int]*entity.TopicCalibration, len(cals))
+	for _, c := range cals {
+		a.calibrations[c.TopicID] = c
+	}
+}
+
+// LoadCalibrations 從資料庫載入主題機率校準參數
+func (a *TopicAssigner) LoadCalibrations(ctx context.Context) error {
+	cals, err := a.topicRepo.ListCalibrations(ctx)
+	if err != nil {
+		return err
+	}
+	a.SetCalibrations(cals)
+	return nil
+}
+
+// CalibrationCount 已校準的主題數
+func (a *TopicAssigner) CalibrationCount() int {
+	return len(a.calibrations)
+}
+
+// calibrationFor 取得主題的校準參數；未校準的主題使用以 threshold 為中心的預設 sigmoid
+func (a *TopicAssigner) calibrationFor(topicID int) *entity.TopicCalibration {
+	if c, ok := a.calibrations[topicID]; ok {
+		return c
+	}
+	const defaultSlope = 20.0
+	return &entity.TopicCalibration{
+		TopicID:   topicID,
+		Slope:     defaultSlope,
+		Intercept: -defaultSlope * a.threshold,
+		Threshold: 0.5,
+	}
+}
+
+// AssignMultiLabel 多標籤分配：回傳校準後機率超過各自門檻的所有主題（依機率排序，最多 maxLabels 個）
+// 沒有任何主題過門檻時回傳空結果，不以未過門檻的主題充數
+func (a *TopicAssigner) AssignMultiLabel(ctx context.Context, embedding entity.Vector, content string) ([]*TopicAssignResult, error) {
+	// 確保快取已載入
+	if len(a.topicCache) == 0 {
+		if err := a.LoadTopics(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	keywordBoosts := a.calculateKeywordBoosts(content, ResolveLanguage(ctx, content))
+
+	var candidates []*TopicAssignResult
+	for _, t := range a.topicCache {
+		if len(t.Embedding) == 0 {
+			continue
+		}
+		// 校準以原始相似度擬合（GetAllScores），關鍵字加權僅記錄不參與機率計算
+		sim := embedding.CosineSimilarity(t.Embedding)
+		cal := a.calibrationFor(t.ID)
+		prob := cal.Probability(sim)
+
+		confidence := entity.ConfidenceLow
+		if prob >= 0.8 {
+			confidence = entity.ConfidenceHigh
+		} else if prob >= cal.Threshold {
+			confidence = entity.ConfidenceMedium
+		}
+
+		candidates = append(candidates, &TopicAssignResult{
+			TopicID:      t.ID,
+			TopicCode:    t.Code,
+			TopicName:    t.Name,
+			Similarity:   sim,
+			Confidence:   confidence,
+			Assigned:     prob >= cal.Threshold,
+			IsAmbiguous:  math.Abs(prob-cal.Threshold) < a.ambiguousMargin,
+			KeywordBoost: keywordBoosts[t.Code],
+			Probability:  prob,
+			Margin:       math.Abs(prob - cal.Threshold),
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Probability > candidates[j].Probability
+	})
+
+	var results []*TopicAssignResult
+	for _, c := range candidates {
+		if len(results) >= a.maxLabels {
+			break
+		}
+		// 各主題門檻不同：機率較低的主題仍可能過自己的門檻
+		if !c.Assigned {
+			continue
+		}
+		results = append(results, c)
+	}
+	return results, nil
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// TopicCalibrator 主題機率校準服務
+// 以人工標註（post_topic_labels）為 gold label，對 TopicAssigner.GetAllScores 的原始相似度
+// 擬合每個主題的 Platt scaling 參數，並挑選 F1 最佳的多標籤門檻
+type TopicCalibrator struct {
+	topicRepo  repository.TopicRepository
+	postRepo   repository.PostRepository
+	assigner   *TopicAssigner
+	minSamples int // 每個主題最少樣本數
+	minPos     int // 每個主題最少正樣本數
+}
+
+// NewTopicCalibrator 建立 TopicCalibrator
+func NewTopicCalibrator(
+	topicRepo repository.TopicRepository,
+	postRepo repository.PostRepository,
+	assigner *TopicAssigner,
+) *TopicCalibrator {
+	return &TopicCalibrator{
+		topicRepo:  topicRepo,
+		postRepo:   postRepo,
+		assigner:   assigner,
+		minSamples: 30,
+		minPos:     3,
+	}
+}
+
+// SetMinSamples 設定每個主題最少樣本數 / 正樣本數
+func (c *TopicCalibrator) SetMinSamples(minSamples, minPos int) {
+	c.minSamples = minSamples
+	c.minPos = minPos
+}
+
+// CalibrationResult 校準結果
+type CalibrationResult struct {
+	LabeledPosts int                        // 有標註的貼文數
+	UsedPosts    int                        // 有 embedding、實際參與擬合的貼文數
+	Fitted       []*entity.TopicCalibration // 成功擬合的主題
+	Skipped      map[int]string             // 未擬合的主題與原因
+}
+
+// calibrationSample 單一樣本（相似度 + 是否屬於此主題）
+type calibrationSample struct {
+	score    float64
+	positive bool
+}
+
+// Fit 擬合所有主題的校準參數；dryRun 時不寫入資料庫
+func (c *TopicCalibrator) Fit(ctx context.Context, dryRun bool) (*CalibrationResult, error) {
+	labels, err := c.topicRepo.ListTopicLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 依貼文分組：relevant 主題集合（未標註的主題視為負樣本）
+	relevant := make(map[int64]map[int]bool)
+	for _, l := range labels {
+		if _, ok := relevant[l.PostID]; !ok {
+			relevant[l.PostID] = make(map[int]bool)
+		}
+		if l.IsRelevant {
+			relevant[l.PostID][l.TopicID] = true
+		}
+	}
+
+	result := &CalibrationResult{
+		LabeledPosts: len(relevant),
+		Skipped:      make(map[int]string),
+	}
+	if len(relevant) == 0 {
+		return result, nil
+	}
+
+	postIDs := make([]string, 0, len(relevant))
+	for id := range relevant {
+		postIDs = append(postIDs, strconv.FormatInt(id, 10))
+	}
+
+	// 分批取得 embedding，計算各主題相似度
+	samples := make(map[int][]calibrationSample)
+	const chunkSize = 500
+	for start := 0; start < len(postIDs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(postIDs) {
+			end = len(postIDs)
+		}
+		posts, err := c.postRepo.FindByIDs(ctx, postIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range posts {
+			if len(p.Embedding) == 0 {
+				continue
+			}
+			scores, err := c.assigner.GetAllScores(ctx, p.Embedding)
+			if err != nil {
+				return nil, err
+			}
+			pos := relevant[parseInt64(p.PostID)]
+			for _, s := range scores {
+				samples[s.TopicID] = append(samples[s.TopicID], calibrationSample{
+					score:    s.Similarity,
+					positive: pos[s.TopicID],
+				})
+			}
+			result.UsedPosts++
+		}
+	}
+
+	topicIDs := make([]int, 0, len(samples))
+	for id := range samples {
+		topicIDs = append(topicIDs, id)
+	}
+	sort.Ints(topicIDs)
+
+	for _, topicID := range topicIDs {
+		ss := samples[topicID]
+		positives := 0
+		for _, s := range ss {
+			if s.positive {
+				positives++
+			}
+		}
+		if len(ss) < c.minSamples {
+			result.Skipped[topicID] = fmt.Sprintf("too few samples (%d < %d)", len(ss), c.minSamples)
+			continue
+		}
+		if positives < c.minPos || positives == len(ss) {
+			result.Skipped[topicID] = fmt.Sprintf("not enough positive/negative samples (%d/%d)", positives, len(ss))
+			continue
+		}
+
+		slope, intercept := fitPlatt(ss)
+		cal := &entity.TopicCalibration{
+			TopicID:       topicID,
+			Slope:         slope,
+			Intercept:     intercept,
+			SampleCount:   len(ss),
+			PositiveCount: positives,
+			FittedAt:      time.Now(),
+		}
+		cal.Threshold = bestF1Threshold(cal, ss)
+		cal.LogLoss = logLoss(cal, ss)
+
+		if !dryRun {
+			if err := c.topicRepo.SaveCalibration(ctx, cal); err != nil {
+				return nil, err
+			}
+		}
+		result.Fitted = append(result.Fitted, cal)
+	}
+
+	if !dryRun {
+		c.assigner.SetCalibrations(result.Fitted)
+	}
+	return result, nil
+}
+
+// fitPlatt 以 Newton 法擬合 Platt scaling（含 Platt 的目標平滑，避免極端樣本過擬合）
+func fitPlatt(samples []calibrationSample) (slope, intercept float64) {
+	var nPos, nNeg float64
+	for _, s := range samples {
+		if s.positive {
+			nPos++
+		} else {
+			nNeg++
+		}
+	}
+	hiTarget := (nPos + 1) / (nPos + 2)
+	loTarget := 1 / (nNeg + 2)
+
+	targets := make([]float64, len(samples))
+	for i, s := range samples {
+		if s.positive {
+			targets[i] = hiTarget
+		} else {
+			targets[i] = loTarget
+		}
+	}
+
+	a, b := 0.0, -math.Log((nNeg+1)/(nPos+1))
+	const (
+		maxIter = 100
+		sigma   = 1e-12 // Hessian 正則化
+		eps     = 1e-6
+	)
+	for iter := 0; iter < maxIter; iter++ {
+		var h11, h22, h21, g1, g2 float64
+		h11, h22 = sigma, sigma
+		for i, s := range samples {
+			p := sigmoid(a*s.score + b)
+			d1 := targets[i] - p
+			d2 := p * (1 - p)
+			h11 += s.score * s.score * d2
+			h22 += d2
+			h21 += s.score * d2
+			g1 += s.score * d1
+			g2 += d1
+		}
+		if math.Abs(g1) < eps && math.Abs(g2) < eps {
+			break
+		}
+		det := h11*h22 - h21*h21
+		if det == 0 {
+			break
+		}
+		da := (h22*g1 - h21*g2) / det
+		db := (-h21*g1 + h11*g2) / det
+		a += da
+		b += db
+		if math.Abs(da) < eps && math.Abs(db) < eps {
+			break
+		}
+	}
+	return a, b
+}
+
+// bestF1Threshold 在候選機率中挑選 F1 最高的門檻
+func bestF1Threshold(cal *entity.TopicCalibration, samples []calibrationSample) float64 {
+	type scored struct {
+		prob     float64
+		positive bool
+	}
+	probs := make([]scored, len(samples))
+	totalPos := 0
+	for i, s := range samples {
+		probs[i] = scored{prob: cal.Probability(s.score), positive: s.positive}
+		if s.positive {
+			totalPos++
+		}
+	}
+	sort.Slice(probs, func(i, j int) bool { return probs[i].prob > probs[j].prob })
+
+	best, bestF1 := 0.5, -1.0
+	tp, fp := 0, 0
+	for i, p := range probs {
+		if p.positive {
+			tp++
+		} else {
+			fp++
+		}
+		// 同機率的樣本一起判斷
+		if i+1 < len(probs) && probs[i+1].prob == p.prob {
+			continue
+		}
+		precision := float64(tp) / float64(tp+fp)
+		recall := float64(tp) / float64(totalPos)
+		if precision+recall == 0 {
+			continue
+		}
+		f1 := 2 * precision * recall / (precision + recall)
+		if f1 > bestF1 {
+			bestF1 = f1
+			best = p.prob
+		}
+	}
+	return best
+}
+
+// logLoss 計算擬合後的平均 log loss
+func logLoss(cal *entity.TopicCalibration, samples []calibrationSample) float64 {
+	const clip = 1e-15
+	var sum float64
+	for _, s := range samples {
+		p := math.Min(math.Max(cal.Probability(s.score), clip), 1-clip)
+		if s.positive {
+			sum -= math.Log(p)
+		} else {
+			sum -= math.Log(1 - p)
+		}
+	}
+	return sum / float64(len(samples))
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func parseInt64(s string) int64 {
+	id, _ := strconv.ParseInt(s, 10, 64)
+	return id
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// MaxTranslateTexts 單次翻譯最多片段數
+const MaxTranslateTexts = 50
+
+// ErrTooManyTranslateTexts 單次翻譯超過 MaxTranslateTexts 則
+var ErrTooManyTranslateTexts = errors.New("too many texts to translate")
+
+// maxTranslateRunes 每個片段最多翻譯的字數（超過截斷，片段翻譯不需要全文）
+const maxTranslateRunes = 1000
+
+// TranslationService LLM 片段翻譯介面（回傳與 texts 同順序的譯文）
+type TranslationService interface {
+	Translate(ctx context.Context, texts []string, target entity.Language) ([]string, error)
+}
+
+// Translation 單一片段的翻譯結果
+type Translation struct {
+	Text           string          `json:"text"`
+	Translated     string          `json:"translated"`
+	SourceLanguage entity.Language `json:"source_language"`
+	TargetLanguage entity.Language `json:"target_language"`
+	Skipped        bool            `json:"skipped,omitempty"` // 已是目標語言或無法判斷語言，原文照回
+}
+
+// Translator 分析師用的片段翻譯：只送出非目標語言的片段，其餘原文照回
+type Translator struct {
+	llm    TranslationService
+	target entity.Language // 預設目標語言
+}
+
+// NewTranslator 建立 Translator（target 為空時預設翻成中文）
+func NewTranslator(llm TranslationService, target entity.Language) *Translator {
+	if target == entity.LanguageUnknown {
+		target = entity.LanguageChinese
+	}
+	return &Translator{llm: llm, target: target}
+}
+
+// Translate 翻譯片段（target 為空時使用預設目標語言）
+func (t *Translator) Translate(ctx context.Context, texts []string, target entity.Language) ([]*Translation, error) {
+	if len(texts) > MaxTranslateTexts {
+		return nil, fmt.Errorf("%w (%d > %d)", ErrTooManyTranslateTexts, len(texts), MaxTranslateTexts)
+	}
+	if target == entity.LanguageUnknown {
+		target = t.target
+	}
+
+	out := make([]*Translation, len(texts))
+	var pending []string
+	var pendingIdx []int
+	for i, text := range texts {
+		tr := &Translation{
+			Text:           text,
+			Translated:     text,
+			SourceLanguage: DetectLanguage(text),
+			TargetLanguage: target,
+		}
+		out[i] = tr
+		if tr.SourceLanguage == target || tr.SourceLanguage == entity.LanguageUnknown {
+			tr.Skipped = true
+			continue
+		}
+		pending = append(pending, TruncateRunes(text, maxTranslateRunes))
+		pendingIdx = append(pendingIdx, i)
+	}
+	if len(pending) == 0 {
+		return out, nil
+	}
+
+	translated, err := t.llm.Translate(ctx, pending, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(translated) != len(pending) {
+		return nil, fmt.Errorf("translation returned %d texts, want %d", len(translated), len(pending))
+	}
+	for j, i := range pendingIdx {
+		out[i].Translated = translated[j]
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import "strings"
+
+// TrigramIndex 別名的 trigram 倒排索引（記憶體內的模糊比對）
+// trigram 切法與分數定義同 pg_trgm 的 word_similarity：每個詞前補兩個空白、後補一個空白再取連續三字，
+// 分數為別名的 trigram 集合與文字中任一段連續 trigram 的最大相似度。
+// 倒排索引先以共同 trigram 數（分數上限）篩出候選，再逐一計算分數
+type TrigramIndex struct {
+	postings map[string][]int32    // trigram → pattern index
+	sets     []map[string]struct{} // 每個 pattern 的 trigram 集合（nil = 略過）
+}
+
+// TrigramMatch 模糊比對結果
+type TrigramMatch struct {
+	Pattern int
+	Score   float64
+}
+
+// NewTrigramIndex 以（已正規化的）patterns 建立索引；少於兩個字的 pattern 略過
+func NewTrigramIndex(patterns []string) *TrigramIndex {
+	ix := &TrigramIndex{postings: make(map[string][]int32), sets: make([]map[string]struct{}, len(patterns))}
+	for i, p := range patterns {
+		if len([]rune(strings.TrimSpace(p))) < 2 {
+			continue
+		}
+		set := make(map[string]struct{})
+		for _, t := range trigrams(p) {
+			set[t] = struct{}{}
+		}
+		ix.sets[i] = set
+		for t := range set {
+			ix.postings[t] = append(ix.postings[t], int32(i))
+		}
+	}
+	return ix
+}
+
+// Match 找出分數不低於 threshold 的 pattern（text 需與 patterns 以同樣方式正規化）
+func (ix *TrigramIndex) Match(text string, threshold float64) []TrigramMatch {
+	seq := trigrams(text)
+	hits := make(map[int32]int)
+	seen := make(map[string]bool, len(seq))
+	for _, t := range seq {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		for _, p := range ix.postings[t] {
+			hits[p]++
+		}
+	}
+
+	var matches []TrigramMatch
+	for p, n := range hits {
+		set := ix.sets[p]
+		if float64(n) < threshold*float64(len(set)) {
+			continue
+		}
+		if score := extentSimilarity(set, seq, threshold); score >= threshold {
+			matches = append(matches, TrigramMatch{Pattern: int(p), Score: score})
+		}
+	}
+	return matches
+}
+
+// extentSimilarity set 與 seq 中任一段連續 trigram 的最大相似度（共同數 / 聯集數）
+// 只需考慮頭尾都落在 set 內的片段；片段的相異 trigram 數超過 |set| / threshold 時分數必低於門檻，不再延伸
+func extentSimilarity(set map[string]struct{}, seq []string, threshold float64) float64 {
+	n := len(set)
+	maxUnique := len(seq)
+	if threshold > 0 {
+		maxUnique = int(float64(n) / threshold)
+	}
+
+	best := 0.0
+	for start := range seq {
+		if _, ok := set[seq[start]]; !ok {
+			continue
+		}
+		seen := make(map[string]bool)
+		common, unique := 0, 0
+		for _, t := range seq[start:] {
+			_, in := set[t]
+			if !seen[t] {
+				seen[t] = true
+				unique++
+				if in {
+					common++
+				}
+			}
+			if unique > maxUnique {
+				break
+			}
+			if in {
+				if sim := float64(common) / float64(n+unique-common); sim > best {
+					best = sim
+				}
+			}
+		}
+	}
+	return best
+}
+
+// trigrams 文字依序的 trigram（以空白分詞，每個詞前補兩個空白、後補一個空白）
+func trigrams(s string) []string {
+	var out []string
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		runes := append([]rune("  "+w), ' ')
+		for i := 0; i+3 <= len(runes); i++ {
+			out = append(out, string(runes[i:i+3]))
+		}
+	}
+	return out
+}
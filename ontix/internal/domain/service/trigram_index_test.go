@@ -0,0 +1,46 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrigramIndexMatch(t *testing.T) {
+	patterns := []string{
+		NormalizeAlias("Starbucks"),
+		NormalizeAlias("Burger King"),
+		NormalizeAlias("麥當勞"),
+		NormalizeAlias("A"),
+	}
+	ix := NewTrigramIndex(patterns)
+
+	tests := []struct {
+		name      string
+		text      string
+		threshold float64
+		want      map[int]float64 // pattern → score
+	}{
+		{"exact word", "coffee at Starbucks today", 0.6, map[int]float64{0: 1}},
+		{"typo", "coffee at starbuck today", 0.6, map[int]float64{0: 0.8}},
+		{"scattered trigrams", "stars and bucks", 0.6, map[int]float64{}},
+		{"multi-word alias", "burgr king fries", 0.6, map[int]float64{1: 9.0 / 14}},
+		{"cjk run", "麥當勞", 0.6, map[int]float64{2: 1}},
+		{"single-rune patterns skipped", "a a a", 0, map[int]float64{}},
+		{"empty text", "", 0, map[int]float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ix.Match(NormalizeForMatch(tt.text).Text, tt.threshold)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match(%q) = %+v, want %v", tt.text, got, tt.want)
+			}
+			for _, m := range got {
+				want, ok := tt.want[m.Pattern]
+				if !ok || math.Abs(m.Score-want) > 1e-9 {
+					t.Errorf("Match(%q) pattern %d score = %v, want %v", tt.text, m.Pattern, m.Score, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// ErrBudgetExceeded 當日 LLM 預算已用盡（該階段被降級停用）
+var ErrBudgetExceeded = errors.New("daily LLM budget exceeded")
+
+// budgetSyncInterval 從資料庫重新同步當日花費的間隔（多個 process 共用預算）
+const budgetSyncInterval = time.Minute
+
+type tenantCtxKey struct{}
+
+// WithTenant 在 context 中標記 tenant（用量記帳依此分類）
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantCtxKey{}, tenant)
+}
+
+// TenantFromContext 取得 context 中的 tenant（未設定時回傳空字串）
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantCtxKey{}).(string)
+	return tenant
+}
+
+type usageMeterCtxKey struct{}
+
+// UsageMeter 累計同一個 context 內所有 LLM 呼叫的用量（例如一輪 chat 對話）
+type UsageMeter struct {
+	mu    sync.Mutex
+	usage entity.TokenUsage
+}
+
+// WithUsageMeter 在 context 中掛上 UsageMeter，之後經 UsageTracker.Record 的呼叫都會累計進去
+func WithUsageMeter(ctx context.Context, meter *UsageMeter) context.Context {
+	return context.WithValue(ctx, usageMeterCtxKey{}, meter)
+}
+
+// Usage 取得目前累計的用量
+func (m *UsageMeter) Usage() entity.TokenUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+func (m *UsageMeter) add(inputTokens, outputTokens int, costUSD float64) {
+	m.mu.Lock()
+	m.usage.InputTokens += int64(inputTokens)
+	m.usage.OutputTokens += int64(outputTokens)
+	m.usage.CostUSD += costUSD
+	m.mu.Unlock()
+}
+
+// UsageTracker LLM 用量記帳 + 每日預算控管
+// nil *UsageTracker 視為停用（不記帳、不限制），呼叫端不需判斷。
+type UsageTracker struct {
+	repo          repository.UsageRepository
+	defaultTenant string
+	dailyBudget   float64 // USD，0 = 不限制
+
+	mu       sync.Mutex
+	day      string
+	spent    map[string]float64   // tenant → 當日累計成本
+	syncedAt map[string]time.Time // tenant → 上次從 DB 同步時間
+	warned   map[string]bool      // tenant/stage → 當日已提示降級
+}
+
+// NewUsageTracker 建立 UsageTracker
+func NewUsageTracker(repo repository.UsageRepository, defaultTenant string, dailyBudgetUSD float64) *UsageTracker {
+	if defaultTenant == "" {
+		defaultTenant = entity.DefaultTenant
+	}
+	return &UsageTracker{
+		repo:          repo,
+		defaultTenant: defaultTenant,
+		dailyBudget:   dailyBudgetUSD,
+		spent:         make(map[string]float64),
+		syncedAt:      make(map[string]time.Time),
+		warned:        make(map[string]bool),
+	}
+}
+
+// Record 記錄一次呼叫（成本依模型定價估算；寫入失敗只記 log）
+func (t *UsageTracker) Record(ctx context.Context, stage entity.UsageStage, provider, model string, inputTokens, outputTokens int, latency time.Duration, callErr error) {
+	cost := entity.EstimateCostUSD(model, inputTokens, outputTokens)
+	if meter, ok := ctx.Value(usageMeterCtxKey{}).(*UsageMeter); ok {
+		meter.add(inputTokens, outputTokens, cost)
+	}
+	if t == nil {
+		return
+	}
+
+	usage := &entity.LLMUsage{
+		Tenant:       t.tenant(ctx),
+		Stage:        stage,
+		Provider:     provider,
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		CostUSD:      cost,
+		LatencyMS:    latency.Milliseconds(),
+		Success:      callErr == nil,
+	}
+
+	// 請求被取消（例如 SSE 斷線）時仍要記帳
+	if err := t.repo.Record(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("[usage] record error: %v", err)
+	}
+
+	t.mu.Lock()
+	t.rollDay()
+	t.spent[usage.Tenant] += usage.CostUSD
+	t.mu.Unlock()
+}
+
+// Allow 檢查該階段是否仍在預算內；超過時回傳 ErrBudgetExceeded
+// 降級順序見 entity.BudgetCutoff：narrative → summary → chat / tagging / extraction，
+// classification 與 embedding 不受限制
+func (t *UsageTracker) Allow(ctx context.Context, stage entity.UsageStage) error {
+	if t == nil || t.dailyBudget <= 0 {
+		return nil
+	}
+	cutoff := entity.BudgetCutoff(stage)
+	if cutoff <= 0 {
+		return nil
+	}
+
+	tenant := t.tenant(ctx)
+	spent := t.SpentToday(ctx, tenant)
+	if spent < t.dailyBudget*cutoff {
+		return nil
+	}
+
+	t.mu.Lock()
+	key := tenant + "/" + string(stage)
+	if !t.warned[key] {
+		t.warned[key] = true
+		log.Printf("[usage] %s: daily budget %.0f%% used ($%.2f / $%.2f), %s disabled until tomorrow",
+			tenant, spent/t.dailyBudget*100, spent, t.dailyBudget, stage)
+	}
+	t.mu.Unlock()
+
+	return fmt.Errorf("%w: %s disabled ($%.2f / $%.2f)", ErrBudgetExceeded, stage, spent, t.dailyBudget)
+}
+
+// SpentToday 取得 tenant 當日累計成本（每分鐘與 DB 同步一次）
+func (t *UsageTracker) SpentToday(ctx context.Context, tenant string) float64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	t.rollDay()
+	spent := t.spent[tenant]
+	needSync := time.Since(t.syncedAt[tenant]) > budgetSyncInterval
+	t.mu.Unlock()
+
+	if !needSync {
+		return spent
+	}
+
+	cost, err := t.repo.CostSince(ctx, tenant, startOfDay(time.Now()))
+	if err != nil {
+		log.Printf("[usage] sync spent error: %v", err)
+		return spent
+	}
+
+	t.mu.Lock()
+	t.spent[tenant] = cost
+	t.syncedAt[tenant] = time.Now()
+	t.mu.Unlock()
+	return cost
+}
+
+// BudgetStatus 當日預算狀態
+type BudgetStatus struct {
+	Tenant         string   `json:"tenant"`
+	DailyBudgetUSD float64  `json:"daily_budget_usd"` // 0 = 不限制
+	SpentTodayUSD  float64  `json:"spent_today_usd"`
+	RemainingUSD   *float64 `json:"remaining_usd,omitempty"`
+	DisabledStages []string `json:"disabled_stages"`
+}
+
+// Status 取得 tenant 當日預算狀態（tenant 空字串 = 預設 tenant）
+func (t *UsageTracker) Status(ctx context.Context, tenant string) *BudgetStatus {
+	if t == nil {
+		return &BudgetStatus{Tenant: tenant, DisabledStages: []string{}}
+	}
+	if tenant == "" {
+		tenant = t.tenant(ctx)
+	}
+
+	status := &BudgetStatus{
+		Tenant:         tenant,
+		DailyBudgetUSD: t.dailyBudget,
+		SpentTodayUSD:  t.SpentToday(ctx, tenant),
+		DisabledStages: []string{},
+	}
+	if t.dailyBudget > 0 {
+		remaining := t.dailyBudget - status.SpentTodayUSD
+		if remaining < 0 {
+			remaining = 0
+		}
+		status.RemainingUSD = &remaining
+
+		for _, stage := range []entity.UsageStage{
+			entity.UsageStageNarrative, entity.UsageStageSummary, entity.UsageStageChat,
+			entity.UsageStageTagging, entity.UsageStageExtraction,
+		} {
+			if status.SpentTodayUSD >= t.dailyBudget*entity.BudgetCutoff(stage) {
+				status.DisabledStages = append(status.DisabledStages, string(stage))
+			}
+		}
+	}
+	return status
+}
+
+func (t *UsageTracker) tenant(ctx context.Context) string {
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		return tenant
+	}
+	return t.defaultTenant
+}
+
+// rollDay 跨日時重置當日累計（呼叫端需持有 mu）
+func (t *UsageTracker) rollDay() {
+	today := time.Now().Format("2006-01-02")
+	if t.day == today {
+		return
+	}
+	t.day = today
+	t.spent = make(map[string]float64)
+	t.syncedAt = make(map[string]time.Time)
+	t.warned = make(map[string]bool)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
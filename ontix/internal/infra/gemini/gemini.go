@@ -3,9 +3,11 @@ package gemini
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 	"google.golang.org/api/option"
 )
@@ -15,6 +17,7 @@ type Client struct {
 	client         *genai.Client
 	embeddingModel string
 	llmModel       string
+	usage          *service.UsageTracker // 用量記帳（nil = 停用）
 }
 
 // New 建立 Gemini 客戶端
@@ -37,12 +40,19 @@ func (c *Client) Close() error {
 	return c.client.Close()
 }
 
+// SetUsageTracker 設定用量記帳
+func (c *Client) SetUsageTracker(tracker *service.UsageTracker) {
+	c.usage = tracker
+}
+
 // === EmbeddingService 實作 ===
 
 // Embed 產生單一文本的向量
 func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
 	model := c.client.EmbeddingModel(c.embeddingModel)
+	start := time.Now()
 	res, err := model.EmbedContent(ctx, genai.Text(text))
+	c.usage.Record(ctx, entity.UsageStageEmbedding, "gemini", c.embeddingModel, 0, 0, time.Since(start), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed: %w", err)
 	}
@@ -57,7 +67,9 @@ func (c *Client) BatchEmbed(ctx context.Context, texts []string) ([][]float32, e
 		batch.AddContent(genai.Text(t))
 	}
 
+	start := time.Now()
 	res, err := model.BatchEmbedContents(ctx, batch)
+	c.usage.Record(ctx, entity.UsageStageEmbedding, "gemini", c.embeddingModel, 0, 0, time.Since(start), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch embed: %w", err)
 	}
@@ -93,7 +105,18 @@ func (c *Client) GenerateTags(ctx context.Context, content string) ([]service.Ta
 3. is_hard_tag: 如果是明確的產品類別（如「口紅」「粉底」）設為 true
 4. 標籤應涵蓋：產品類型、品牌、功效、使用場景、情感傾向`, content)
 
+	if err := c.usage.Allow(ctx, entity.UsageStageTagging); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	res, err := model.GenerateContent(ctx, genai.Text(prompt))
+	var inputTokens, outputTokens int
+	if res != nil && res.UsageMetadata != nil {
+		inputTokens = int(res.UsageMetadata.PromptTokenCount)
+		outputTokens = int(res.UsageMetadata.CandidatesTokenCount)
+	}
+	c.usage.Record(ctx, entity.UsageStageTagging, "gemini", c.llmModel, inputTokens, outputTokens, time.Since(start), err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tags: %w", err)
 	}
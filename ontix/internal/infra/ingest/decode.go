@@ -0,0 +1,226 @@
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineSize JSONL 單行上限
+const maxLineSize = 16 << 20
+
+// errStop 由回呼回傳以提前結束解析（達到 Limit）
+var errStop = errors.New("stop")
+
+// DecodeFunc 每筆資料的回呼；decodeErr 非 nil 表示該筆無法解析（應退件）
+type DecodeFunc func(row int, rec Record, decodeErr error) error
+
+// Decode 依格式串流解析輸入，不會一次載入整個檔案
+func Decode(r io.Reader, format Format, fn DecodeFunc) error {
+	switch format {
+	case FormatJSONL:
+		return decodeJSONL(r, fn)
+	case FormatCSV:
+		return decodeCSV(r, fn)
+	case FormatJSON:
+		return decodeJSON(r, fn)
+	default:
+		return fmt.Errorf("unsupported format %q (expected jsonl, csv or json)", format)
+	}
+}
+
+// DetectFormat 依副檔名或 Content-Type 推斷格式，無法判斷時回傳空字串
+func DetectFormat(nameOrContentType string) Format {
+	s := strings.ToLower(nameOrContentType)
+	switch {
+	case strings.HasSuffix(s, ".jsonl"), strings.HasSuffix(s, ".ndjson"),
+		strings.Contains(s, "ndjson"), strings.Contains(s, "jsonl"):
+		return FormatJSONL
+	case strings.HasSuffix(s, ".csv"), strings.Contains(s, "text/csv"):
+		return FormatCSV
+	case strings.HasSuffix(s, ".json"), strings.Contains(s, "application/json"):
+		return FormatJSON
+	default:
+		return ""
+	}
+}
+
+func decodeJSONL(r io.Reader, fn DecodeFunc) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	row := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row++
+		rec, err := unmarshalRecord(line)
+		if err := fn(row, rec, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func unmarshalRecord(data []byte) (Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var rec Record
+	if err := dec.Decode(&rec); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if rec == nil {
+		return nil, errors.New("invalid JSON: not an object")
+	}
+	return rec, nil
+}
+
+func decodeCSV(r io.Reader, fn DecodeFunc) error {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, h := range header {
+		// 去除 Excel 匯出的 BOM
+		columns[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	row := 0
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		row++
+		if err != nil {
+			if err := fn(row, nil, fmt.Errorf("invalid CSV row: %v", err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(fields) != len(columns) {
+			if err := fn(row, nil, fmt.Errorf("expected %d columns, got %d", len(columns), len(fields))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rec := make(Record, len(columns))
+		for i, col := range columns {
+			if v := strings.TrimSpace(fields[i]); v != "" {
+				rec[col] = v
+			}
+		}
+		if err := fn(row, rec, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeJSON 支援：
+//   - 陣列：[{...}, {...}]
+//   - 包著陣列的物件：{"data":[...], "paging":{...}}、{"items":[...]}、{"data":{"videos":[...]}}、
+//     舊版 ontix push 的 {"<任意 key>":[...]}（物件中所有陣列都會展開，巢狀物件往下找一層）
+func decodeJSON(r io.Reader, fn DecodeFunc) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	row := 0
+	switch tok {
+	case json.Delim('['):
+		return decodeJSONArray(dec, &row, fn)
+	case json.Delim('{'):
+		return decodeJSONWrapper(dec, &row, fn, 0)
+	default:
+		return errors.New("invalid JSON: expected an array or an object wrapping an array")
+	}
+}
+
+// decodeJSONArray 讀取陣列元素（開頭的 '[' 已讀取）
+func decodeJSONArray(dec *json.Decoder, row *int, fn DecodeFunc) error {
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		*row++
+		rec, err := unmarshalRecord(raw)
+		if err := fn(*row, rec, err); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // ']'
+	return err
+}
+
+// decodeJSONWrapper 讀取物件，展開其中的陣列（開頭的 '{' 已讀取）
+func decodeJSONWrapper(dec *json.Decoder, row *int, fn DecodeFunc, depth int) error {
+	for dec.More() {
+		if _, err := dec.Token(); err != nil { // key
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		// 先偷看值的型別：陣列展開、物件往下找一層，其他略過
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		switch tok {
+		case json.Delim('['):
+			if err := decodeJSONArray(dec, row, fn); err != nil {
+				return err
+			}
+		case json.Delim('{'):
+			if depth < 1 {
+				if err := decodeJSONWrapper(dec, row, fn, depth+1); err != nil {
+					return err
+				}
+			} else if err := skipJSON(dec); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := dec.Token() // '}'
+	return err
+}
+
+// skipJSON 略過目前容器剩餘的內容（開頭的 '{' 或 '[' 已讀取）
+func skipJSON(dec *json.Decoder) error {
+	for level := 1; level > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			level++
+		case json.Delim('}'), json.Delim(']'):
+			level--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Package ingest 多格式貼文匯入：解析 JSONL / CSV / JSON → 各平台 Mapper 正規化為 redis.PostMessage → 批次寫入 Redis Stream
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ikala/ontix/internal/infra/redis"
+)
+
+const (
+	// DefaultBatchSize 每次 pipeline 寫入 Redis 的筆數
+	DefaultBatchSize = 500
+	// maxRejections 結果中最多列出的退件明細（計數不受限）
+	maxRejections = 1000
+)
+
+// ErrPublish 寫入 Redis Stream 失敗（其餘錯誤為輸入格式問題）
+var ErrPublish = errors.New("failed to publish to queue")
+
+// Format 輸入格式
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl" // 每行一筆 JSON
+	FormatCSV   Format = "csv"   // 第一列為欄名，巢狀欄位以 a.b 表示
+	FormatJSON  Format = "json"  // JSON 陣列，或包著陣列的物件（平台 API 匯出：data / items / posts / videos…）
+)
+
+// Record 一筆原始資料（JSON 物件或 CSV 列）
+type Record map[string]any
+
+// Mapper 將某平台的原始資料轉為 PostMessage
+type Mapper interface {
+	// Name 平台名稱（CLI --platform / API ?platform= 使用）
+	Name() string
+	// Map 轉換一筆資料；回傳錯誤即退件（錯誤訊息即退件原因）
+	Map(rec Record) (*redis.PostMessage, error)
+}
+
+var mappers = map[string]Mapper{}
+
+// Register 註冊 Mapper（同名覆蓋）
+func Register(m Mapper) {
+	mappers[m.Name()] = m
+}
+
+// Lookup 取得 Mapper；空字串為通用 Mapper
+func Lookup(name string) (Mapper, bool) {
+	if name == "" {
+		name = genericName
+	}
+	m, ok := mappers[strings.ToLower(name)]
+	return m, ok
+}
+
+// Names 已註冊的平台名稱
+func Names() []string {
+	names := make([]string, 0, len(mappers))
+	for name := range mappers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rejection 退件明細
+type Rejection struct {
+	Row    int    `json:"row"` // 第幾筆資料（從 1 起算；CSV 不含標題列）
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Result 匯入結果
+type Result struct {
+	Total      int          `json:"total"`
+	Accepted   int          `json:"accepted"`
+	Rejected   int          `json:"rejected"`
+	Unchanged  int          `json:"unchanged"` // 已排入過且內容 / 互動數未變而略過的筆數
+	Rejections []*Rejection `json:"rejections"`
+	Truncated  bool         `json:"rejections_truncated,omitempty"` // 退件明細超過上限未全部列出
+}
+
+func (r *Result) reject(rej *Rejection, onReject func(*Rejection)) {
+	r.Rejected++
+	if onReject != nil {
+		onReject(rej)
+	}
+	if len(r.Rejections) >= maxRejections {
+		r.Truncated = true
+		return
+	}
+	r.Rejections = append(r.Rejections, rej)
+}
+
+// Options 匯入選項
+type Options struct {
+	Format    Format
+	Platform  string // Mapper 名稱，空字串 = 通用
+	BatchSize int
+	Skip      int  // 略過前 N 筆
+	Limit     int  // 最多處理 N 筆（0 = 全部）
+	DryRun    bool // 只驗證不寫入
+	Force     bool // 略過冪等檢查，未變動的貼文也重新排入佇列
+
+	// OnReject 每筆退件都會呼叫（不受明細上限影響，例如 CLI 寫出完整退件檔）
+	OnReject func(r *Rejection)
+}
+
+// Ingester 串流解析輸入並批次寫入 Redis Stream
+type Ingester struct {
+	stream *redis.StreamRepo
+	guard  *redis.IngestGuard // nil = 不做冪等檢查
+}
+
+// NewIngester 建立 Ingester（guard 可為 nil）
+func NewIngester(stream *redis.StreamRepo, guard *redis.IngestGuard) *Ingester {
+	return &Ingester{stream: stream, guard: guard}
+}
+
+// Ingest 解析 r 並寫入佇列；寫入 Redis 失敗時中止並回傳錯誤（已寫入的筆數記在 Accepted）
+func (g *Ingester) Ingest(ctx context.Context, r io.Reader, opts Options) (*Result, error) {
+	mapper, ok := Lookup(opts.Platform)
+	if !ok {
+		return nil, fmt.Errorf("unknown platform %q (available: %s)", opts.Platform, strings.Join(Names(), ", "))
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	res := &Result{Rejections: []*Rejection{}}
+	batch := make([]redis.PostMessage, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		defer func() { batch = batch[:0] }()
+		if opts.DryRun {
+			res.Accepted += len(batch)
+			return nil
+		}
+
+		msgs := batch
+		if g.guard != nil && !opts.Force {
+			fresh, err := g.guard.Claim(ctx, batch)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrPublish, err)
+			}
+			msgs = make([]redis.PostMessage, 0, len(batch))
+			for i, ok := range fresh {
+				if ok {
+					msgs = append(msgs, batch[i])
+				}
+			}
+			res.Unchanged += len(batch) - len(msgs)
+		}
+
+		if err := g.stream.PublishBatch(ctx, msgs); err != nil {
+			if g.guard != nil && !opts.Force {
+				g.guard.Release(ctx, msgs)
+			}
+			return fmt.Errorf("%w: %v", ErrPublish, err)
+		}
+		res.Accepted += len(msgs)
+		return nil
+	}
+
+	seen := 0
+	err := Decode(r, opts.Format, func(row int, rec Record, decodeErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		seen++
+		if seen <= opts.Skip {
+			return nil
+		}
+		if opts.Limit > 0 && res.Total >= opts.Limit {
+			return errStop
+		}
+		res.Total++
+
+		if decodeErr != nil {
+			res.reject(&Rejection{Row: row, Reason: decodeErr.Error()}, opts.OnReject)
+			return nil
+		}
+		msg, err := mapper.Map(rec)
+		if err == nil {
+			err = Validate(msg)
+		}
+		if err != nil {
+			res.reject(&Rejection{Row: row, ID: rawID(rec), Reason: err.Error()}, opts.OnReject)
+			return nil
+		}
+
+		batch = append(batch, *msg)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil && err != errStop {
+		return res, err
+	}
+	if err := flush(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// rawID 退件明細用：盡量取出原始 ID
+func rawID(rec Record) string {
+	return str(rec, "id", "post_id", "platform_post_id", "shortcode")
+}
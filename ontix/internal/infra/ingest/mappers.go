@@ -0,0 +1,220 @@
+package ingest
+
+import (
+	"errors"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/infra/redis"
+)
+
+const genericName = "generic"
+
+// fieldMapper 以欄位別名描述平台格式：每個欄位依序嘗試，取第一個有值的路徑
+type fieldMapper struct {
+	name     string
+	platform string // 固定平台；空字串 = 讀取資料的 platform 欄位
+
+	id        []string
+	content   []string
+	title     []string // 有值時與 content 合併（YouTube 標題 + 說明）
+	userID    []string
+	username  []string
+	postTime  []string
+	likes     []string
+	comments  []string
+	shares    []string
+	views     []string
+	followers []string // 作者追蹤數
+	parentID  []string // 留言 / 回覆的直接上層
+	rootID    []string // 留言串的原始貼文
+}
+
+// 所有 Mapper 共用的通用欄位（ontix 原生格式 + 常見命名），排在平台專屬欄位之後
+var (
+	genericID        = []string{"id", "post_id", "platform_post_id", "external_id"}
+	genericContent   = []string{"content", "text", "message", "caption", "body", "description"}
+	genericUserID    = []string{"platform_user_id", "author_id", "owner_id", "user_id"}
+	genericUsername  = []string{"owner_username", "author_username", "username", "author", "owner_id"}
+	genericTime      = []string{"post_time", "created_at", "timestamp", "published_at", "created_time", "create_time"}
+	genericLikes     = []string{"like_count", "likes", "likes_count"}
+	genericComments  = []string{"comment_count", "comments", "comments_count"}
+	genericShares    = []string{"share_count", "shares", "shares_count"}
+	genericViews     = []string{"view_count", "views", "views_count", "play_count"}
+	genericFollowers = []string{"owner_followers", "author_followers", "followers_count", "follower_count", "followers"}
+	genericParentID  = []string{"parent_id", "parent_post_id", "in_reply_to_id", "reply_to_id"}
+	genericRootID    = []string{"root_id", "root_post_id", "thread_id"}
+)
+
+func (m *fieldMapper) Name() string { return m.name }
+
+func (m *fieldMapper) Map(rec Record) (*redis.PostMessage, error) {
+	platform := m.platform
+	if platform == "" {
+		platform = NormalizePlatform(str(rec, "platform", "source"))
+		if platform == "" {
+			return nil, errors.New("missing platform (set the platform field or choose a platform mapper)")
+		}
+	}
+
+	rawID := str(rec, append(m.id, genericID...)...)
+	if rawID == "" {
+		return nil, errors.New("missing post id")
+	}
+
+	postTime, err := NormalizeTime(firstValue(rec, append(m.postTime, genericTime...)))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &redis.PostMessage{
+		ID:             NormalizeID(platform, rawID),
+		Platform:       platform,
+		PlatformUserID: str(rec, append(m.userID, genericUserID...)...),
+		Content:        str(rec, append(m.content, genericContent...)...),
+		OwnerUsername:  str(rec, append(m.username, genericUsername...)...),
+		PostTime:       postTime,
+	}
+	if len(m.title) > 0 {
+		msg.Content = join(str(rec, m.title...), msg.Content)
+	}
+	if msg.OwnerUsername == "" {
+		msg.OwnerUsername = msg.PlatformUserID
+	}
+
+	// 留言串：只帶所屬貼文的頂層留言，parent 即為 root
+	if parent := str(rec, append(m.parentID, genericParentID...)...); parent != "" {
+		msg.ParentID = NormalizeID(platform, parent)
+	}
+	if root := str(rec, append(m.rootID, genericRootID...)...); root != "" {
+		msg.RootID = NormalizeID(platform, root)
+	}
+	if msg.RootID == msg.ID {
+		msg.RootID = 0
+	}
+	if msg.ParentID == 0 {
+		msg.ParentID = msg.RootID
+	}
+
+	counts := []struct {
+		dst   *int
+		paths []string
+	}{
+		{&msg.LikeCount, append(m.likes, genericLikes...)},
+		{&msg.CommentCount, append(m.comments, genericComments...)},
+		{&msg.ShareCount, append(m.shares, genericShares...)},
+		{&msg.ViewCount, append(m.views, genericViews...)},
+	}
+	for _, c := range counts {
+		if *c.dst, err = count(rec, c.paths...); err != nil {
+			return nil, err
+		}
+	}
+	// 追蹤數只是作者屬性，格式不符時忽略（不讓整筆貼文匯入失敗）
+	msg.OwnerFollowers, _ = count(rec, append(m.followers, genericFollowers...)...)
+	return msg, nil
+}
+
+// firstValue 第一個存在且非空的原始值（交給 NormalizeTime 判斷型別）
+func firstValue(rec Record, paths []string) any {
+	for _, p := range paths {
+		if v, ok := lookup(rec, p); ok && v != nil && v != "" {
+			return v
+		}
+	}
+	return nil
+}
+
+func init() {
+	// 通用：ontix 原生 JSONL / CSV、舊版 ontix push 格式；平台由資料的 platform 欄位決定
+	Register(&fieldMapper{name: genericName})
+
+	// Instagram Graph API media（id, caption, timestamp, like_count, comments_count, username）
+	// 與常見爬蟲匯出（shortCode, likesCount, commentsCount, ownerUsername, videoViewCount）
+	Register(&fieldMapper{
+		name:      "instagram",
+		platform:  string(entity.PlatformInstagram),
+		id:        []string{"id", "shortCode", "shortcode", "code"},
+		content:   []string{"caption", "caption.text", "edge_media_to_caption.edges.0.node.text"},
+		userID:    []string{"owner.id", "ownerId", "user.pk"},
+		username:  []string{"username", "owner.username", "ownerUsername", "user.username"},
+		postTime:  []string{"timestamp", "taken_at_timestamp", "taken_at"},
+		likes:     []string{"like_count", "likesCount", "edge_liked_by.count"},
+		comments:  []string{"comments_count", "commentsCount", "edge_media_to_comment.count"},
+		views:     []string{"video_view_count", "videoViewCount", "videoPlayCount", "play_count"},
+		followers: []string{"owner.followers_count", "ownerFollowersCount", "user.follower_count", "followers_count"},
+		parentID:  []string{"parent_id", "parentId"},
+		rootID:    []string{"media.id"},
+	})
+
+	// Facebook Graph API page posts（id, message, created_time, from, reactions / comments summary, shares.count）
+	// 與常見爬蟲匯出（postId, text, time, likes, comments, shares, pageName）
+	Register(&fieldMapper{
+		name:      "facebook",
+		platform:  string(entity.PlatformFacebook),
+		id:        []string{"id", "postId", "post_id"},
+		content:   []string{"message", "text", "story"},
+		userID:    []string{"from.id", "user.id", "pageId"},
+		username:  []string{"from.name", "user.name", "pageName"},
+		postTime:  []string{"created_time", "time", "timestamp"},
+		likes:     []string{"reactions.summary.total_count", "likes.summary.total_count", "likes", "reactionsCount"},
+		comments:  []string{"comments.summary.total_count", "comments", "commentsCount"},
+		shares:    []string{"shares.count", "shares", "sharesCount"},
+		followers: []string{"from.followers_count", "from.fan_count", "pageFollowers", "pageLikes"},
+		parentID:  []string{"parent.id", "replyToCommentId"},
+	})
+
+	// Threads API media（id, text, timestamp, username, owner.id）+ insights 欄位（likes, replies, reposts, views）
+	Register(&fieldMapper{
+		name:      "threads",
+		platform:  string(entity.PlatformThreads),
+		id:        []string{"id", "code"},
+		content:   []string{"text", "caption.text"},
+		userID:    []string{"owner.id", "user.pk", "user.id"},
+		username:  []string{"username", "user.username"},
+		postTime:  []string{"timestamp", "taken_at"},
+		likes:     []string{"likes", "like_count"},
+		comments:  []string{"replies", "reply_count", "text_post_app_info.direct_reply_count"},
+		shares:    []string{"reposts", "repost_count", "quotes"},
+		views:     []string{"views"},
+		followers: []string{"owner.followers_count", "user.follower_count", "followers_count"},
+		parentID:  []string{"replied_to.id"},
+		rootID:    []string{"root_post.id"},
+	})
+
+	// YouTube Data API videos / search（id 或 id.videoId, snippet.*, statistics.* 為字串數字）
+	Register(&fieldMapper{
+		name:      "youtube",
+		platform:  string(entity.PlatformYouTube),
+		id:        []string{"id.videoId", "id", "videoId"},
+		title:     []string{"snippet.title", "title"},
+		content:   []string{"snippet.description", "description", "snippet.textOriginal", "snippet.topLevelComment.snippet.textOriginal"},
+		userID:    []string{"snippet.channelId", "channelId", "snippet.authorChannelId.value"},
+		username:  []string{"snippet.channelTitle", "channelTitle", "snippet.authorDisplayName"},
+		postTime:  []string{"snippet.publishedAt", "publishedAt"},
+		likes:     []string{"statistics.likeCount", "likeCount"},
+		comments:  []string{"statistics.commentCount", "commentCount"},
+		views:     []string{"statistics.viewCount", "viewCount"},
+		followers: []string{"channelStatistics.subscriberCount", "subscriberCount", "numberOfSubscribers"},
+		parentID:  []string{"snippet.parentId"},
+		rootID:    []string{"snippet.videoId"},
+	})
+
+	// TikTok Research API（id, video_description, create_time, username, *_count）
+	// 與常見爬蟲匯出（text, createTimeISO, authorMeta, diggCount, playCount）
+	Register(&fieldMapper{
+		name:      "tiktok",
+		platform:  string(entity.PlatformTikTok),
+		id:        []string{"id", "video_id"},
+		content:   []string{"video_description", "desc", "text"},
+		userID:    []string{"authorMeta.id", "author.id"},
+		username:  []string{"username", "authorMeta.name", "author.uniqueId"},
+		postTime:  []string{"create_time", "createTimeISO", "createTime"},
+		likes:     []string{"like_count", "diggCount", "stats.diggCount"},
+		comments:  []string{"comment_count", "commentCount", "stats.commentCount"},
+		shares:    []string{"share_count", "shareCount", "stats.shareCount"},
+		views:     []string{"view_count", "playCount", "stats.playCount"},
+		followers: []string{"follower_count", "authorMeta.fans", "authorStats.followerCount"},
+		parentID:  []string{"parent_comment_id", "repliesToId"},
+		rootID:    []string{"video_id"},
+	})
+}
@@ -0,0 +1,230 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/infra/redis"
+)
+
+// Validate 檢查正規化後的 PostMessage
+func Validate(msg *redis.PostMessage) error {
+	switch {
+	case msg.ID <= 0:
+		return errors.New("missing post id")
+	case strings.TrimSpace(msg.Content) == "":
+		return errors.New("missing content")
+	case msg.Platform == "":
+		return errors.New("missing platform")
+	case msg.LikeCount < 0 || msg.CommentCount < 0 || msg.ShareCount < 0 || msg.ViewCount < 0:
+		return errors.New("negative engagement count")
+	case msg.ParentID == msg.ID || msg.RootID == msg.ID:
+		return errors.New("post cannot be its own parent")
+	case msg.RootID != 0 && msg.ParentID == 0:
+		return errors.New("root id set without parent id")
+	}
+	if msg.PostTime != "" {
+		if _, err := time.Parse(time.RFC3339, msg.PostTime); err != nil {
+			return fmt.Errorf("invalid post time %q", msg.PostTime)
+		}
+	}
+	return nil
+}
+
+// NormalizeID 將平台貼文 ID 轉為 PostMessage.ID
+// 純數字 ID 直接使用；其他（YouTube video ID、Instagram shortcode、Facebook "頁面ID_貼文ID"…）
+// 以 platform + ID 的 FNV-64a 雜湊取正值，同一篇貼文重複匯入會得到相同 ID
+func NormalizeID(platform, rawID string) int64 {
+	rawID = strings.TrimSpace(rawID)
+	if rawID == "" {
+		return 0
+	}
+	if id, err := strconv.ParseInt(rawID, 10, 64); err == nil && id > 0 {
+		return id
+	}
+	h := fnv.New64a()
+	h.Write([]byte(platform + ":" + rawID))
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
+// NormalizePlatform 平台名稱統一為 entity.Platform（ig / IG / Instagram → instagram）
+func NormalizePlatform(p string) string {
+	switch strings.ToLower(strings.TrimSpace(p)) {
+	case "ig", "instagram":
+		return string(entity.PlatformInstagram)
+	case "fb", "facebook":
+		return string(entity.PlatformFacebook)
+	case "threads":
+		return string(entity.PlatformThreads)
+	case "yt", "youtube":
+		return string(entity.PlatformYouTube)
+	case "tiktok", "tt":
+		return string(entity.PlatformTikTok)
+	default:
+		return strings.ToLower(strings.TrimSpace(p))
+	}
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700", // Facebook / Instagram Graph API
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// NormalizeTime 將各平台時間格式（RFC3339、Graph API、Unix 秒 / 毫秒…）轉為 RFC3339；空值回傳空字串
+func NormalizeTime(v any) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case json.Number:
+		return normalizeUnix(string(x))
+	case float64:
+		return normalizeUnix(strconv.FormatFloat(x, 'f', -1, 64))
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return "", nil
+		}
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return normalizeUnix(s)
+		}
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return t.Format(time.RFC3339), nil
+			}
+		}
+		return "", fmt.Errorf("invalid post time %q", s)
+	default:
+		return "", fmt.Errorf("invalid post time %v", v)
+	}
+}
+
+// normalizeUnix Unix 秒或毫秒（> 1e12 視為毫秒）
+func normalizeUnix(s string) (string, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return "", fmt.Errorf("invalid post time %q", s)
+	}
+	if f > 1e12 {
+		return time.UnixMilli(int64(f)).Format(time.RFC3339), nil
+	}
+	return time.Unix(int64(f), 0).Format(time.RFC3339), nil
+}
+
+// ============================================
+// Record 取值（支援 a.b 巢狀路徑與 a.0.b 陣列索引；CSV 欄名本身含 "." 時優先以整個欄名比對）
+// ============================================
+
+func lookup(rec Record, path string) (any, bool) {
+	if v, ok := rec[path]; ok {
+		return v, true
+	}
+	var cur any = map[string]any(rec)
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// str 第一個非空字串值
+func str(rec Record, paths ...string) string {
+	for _, p := range paths {
+		v, ok := lookup(rec, p)
+		if !ok || v == nil {
+			continue
+		}
+		var s string
+		switch x := v.(type) {
+		case string:
+			s = x
+		case json.Number:
+			s = x.String()
+		case float64:
+			s = strconv.FormatFloat(x, 'f', -1, 64)
+		case bool:
+			s = strconv.FormatBool(x)
+		default:
+			continue
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// count 第一個存在的數值（接受數字、"1,234"、"1.2K" / "3.4M"）；不存在回傳 0
+func count(rec Record, paths ...string) (int, error) {
+	for _, p := range paths {
+		v, ok := lookup(rec, p)
+		if !ok || v == nil {
+			continue
+		}
+		s := str(Record{"v": v}, "v")
+		if s == "" {
+			continue
+		}
+		n, err := parseCount(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", p, s)
+		}
+		return n, nil
+	}
+	return 0, nil
+}
+
+func parseCount(s string) (int, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "K"), strings.HasSuffix(s, "k"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"), strings.HasSuffix(s, "m"):
+		mult, s = 1e6, s[:len(s)-1]
+	case strings.HasSuffix(s, "B"), strings.HasSuffix(s, "b"):
+		mult, s = 1e9, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(f * mult)), nil
+}
+
+// join 以空行合併非空文字（例：YouTube 標題 + 說明）
+func join(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
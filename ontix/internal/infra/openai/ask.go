@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+// PlanAskQuery 使用 LLM 將自然語言問題轉為檢索計畫
+func (c *Client) PlanAskQuery(ctx context.Context, question string, entityTypes []string) (*service.AskPlan, error) {
+	prompt := fmt.Sprintf(`你是社群輿情知識圖譜的檢索規劃器。請把用戶的問題轉成檢索計畫。
+
+問題：%s
+
+欄位說明：
+- entities: 問題中明確點名的品牌、產品、人物等名稱（保留原文，沒有就給空陣列）
+- entity_types: 問題涉及的 Entity 類型，只能從 [%s] 中選
+- category: 沒有點名 Entity 時，用來搜尋相關 Entity 的簡短描述（例：「保養品牌」「平價早午餐」），有點名時給空字串
+- aspects: 問題關注的面向（例：「價格」「服務」「包裝」），沒有就給空陣列
+- sentiment: 問題聚焦的情感，"positive"、"negative" 或 ""
+- period: 問題的時間範圍，只能是 "1w"、"4w"、"12w"（「上週」→1w、「最近一個月」→4w、「這一季」→12w，未提及給 "4w"）
+- retrieve: 需要檢索的資料，從 ["sentiment", "kols", "facts", "relations", "posts"] 中選
+  - sentiment: 情感 / 面向的變化；kols: 帶動討論的作者；facts: 系統偵測到的警報與趨勢；relations: 競品 / 從屬等關係；posts: 代表性貼文
+
+回覆 JSON（嚴格遵守格式，不要加任何其他文字）：
+{"entities": [], "entity_types": [], "category": "", "aspects": [], "sentiment": "", "period": "4w", "retrieve": []}`,
+		question, strings.Join(entityTypes, ", "))
+
+	chatReq := chatRequest{
+		Model: "gpt-4o-mini",
+		Messages: []chatMessage{
+			{Role: "user", Content: prompt},
+		},
+		MaxTokens:   300,
+		Temperature: 0,
+	}
+
+	result, err := c.doChat(ctx, entity.UsageStageChat, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("empty ask plan response")
+	}
+
+	responseText := result.Choices[0].Message.Content
+	start := strings.Index(responseText, "{")
+	end := strings.LastIndex(responseText, "}")
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("no JSON object found in ask plan response: %s", responseText)
+	}
+
+	var plan service.AskPlan
+	if err := json.Unmarshal([]byte(responseText[start:end+1]), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse ask plan JSON: %w", err)
+	}
+	return &plan, nil
+}
+
+// StreamAskAnswer 根據檢索到的證據串流回答，回答須引用來源標記
+func (c *Client) StreamAskAnswer(ctx context.Context, req *service.AskAnswerRequest) (<-chan string, <-chan error) {
+	systemPrompt := fmt.Sprintf(`你是 Ontix Ontology 推理引擎的分析師，根據知識圖譜檢索到的證據回答用戶的問題。
+
+證據：
+%s
+
+回答規則：
+1. 只能根據上方證據回答，不要使用證據以外的知識或臆測
+2. 每個論點都要在句尾附上來源標記，格式與證據中完全相同：貼文 [P:貼文ID]、事實 [F:事實ID]、實體 [E:實體ID]；只能引用證據中出現過的標記
+3. 引用具體數據（提及數、負面比例變化、粉絲數）佐證
+4. 支援 **粗體** 標記重要數據和結論，!!紅色!! 標記風險或負面資訊
+5. 回答簡潔精準，150-400 字
+6. 如果證據不足以回答問題，直接回答「資料不足」並說明缺少哪些資料，不要編造`, req.Evidence)
+
+	return c.streamChat(ctx, entity.UsageStageChat, streamChatRequest{
+		Model: "gpt-4o-mini",
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: req.Question},
+		},
+		MaxTokens:   1000,
+		Temperature: 0.2,
+	})
+}
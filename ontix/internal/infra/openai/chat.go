@@ -8,16 +8,24 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 )
 
 type streamChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []chatMessage `json:"messages"`
-	MaxTokens   int           `json:"max_tokens"`
-	Temperature float64       `json:"temperature"`
-	Stream      bool          `json:"stream"`
+	Model         string         `json:"model"`
+	Messages      []chatMessage  `json:"messages"`
+	MaxTokens     int            `json:"max_tokens"`
+	Temperature   float64        `json:"temperature"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+}
+
+// streamOptions include_usage 讓最後一個 chunk 帶回 token 用量
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 type streamDelta struct {
@@ -31,19 +39,13 @@ type streamChoice struct {
 
 type streamChunk struct {
 	Choices []streamChoice `json:"choices"`
+	Usage   *apiUsage      `json:"usage"`
 }
 
 // StreamEntityChat 使用 OpenAI streaming API 進行 Entity follow-up 對話
 func (c *Client) StreamEntityChat(ctx context.Context, req *service.EntityChatRequest) (<-chan string, <-chan error) {
-	tokenCh := make(chan string, 64)
-	errCh := make(chan error, 1)
-
-	go func() {
-		defer close(tokenCh)
-		defer close(errCh)
-
-		// Build system prompt
-		systemPrompt := fmt.Sprintf(`你是 Ontix Ontology 推理引擎的分析師。你已為「%s」（%s）生成以下洞察摘要，用戶正在針對摘要內容追問。
+	// Build system prompt
+	systemPrompt := fmt.Sprintf(`你是 Ontix Ontology 推理引擎的分析師。你已為「%s」（%s）生成以下洞察摘要，用戶正在針對摘要內容追問。
 
 已生成的摘要：
 %s
@@ -57,23 +59,87 @@ func (c *Client) StreamEntityChat(ctx context.Context, req *service.EntityChatRe
 3. 支援 !!紅色!! 標記風險或負面資訊
 4. 提及【關聯實體】清單中的任何實體時，一律使用 [[實體名稱|實體ID]] 格式，系統會自動渲染為可點擊連結（用戶只看到名稱，看不到 ID）。絕對不要在回答中直接顯示 ID。每一次提到清單中的實體名稱都必須用此格式包裹，不能有遺漏。只引用清單中存在的實體，不要編造 ID
 5. 回答簡潔精準，150-300 字
-6. 如果用戶問的超出你掌握的數據範圍，坦白說明`, req.EntityName, req.EntityType, req.SummaryJSON, req.ContextData)
+6. 如果用戶問的超出你掌握的數據範圍，坦白說明
+7. 使用數據時在句尾附上來源標記，格式與上下文中完全相同：提及貼文 [P:貼文ID]、面向 [A:面向]、推理引擎事實 [F:事實ID]；只能引用上下文中出現過的標記`, req.EntityName, req.EntityType, req.SummaryJSON, req.ContextData)
+
+	// Assemble messages
+	messages := []chatMessage{
+		{Role: "system", Content: systemPrompt},
+	}
+	for _, h := range req.History {
+		messages = append(messages, chatMessage{Role: h.Role, Content: h.Content})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: req.Question})
+
+	return c.streamChat(ctx, entity.UsageStageChat, streamChatRequest{
+		Model:       "gpt-4o-mini",
+		Messages:    messages,
+		MaxTokens:   800,
+		Temperature: 0.4,
+	})
+}
 
-		// Assemble messages
-		messages := []chatMessage{
-			{Role: "system", Content: systemPrompt},
+// SummarizeChatHistory 將較舊的對話併入摘要（保留用戶關心的問題與已給出的結論）
+func (c *Client) SummarizeChatHistory(ctx context.Context, req *service.ChatHistorySummaryRequest) (string, error) {
+	var b strings.Builder
+	for _, m := range req.Messages {
+		role := "用戶"
+		if m.Role == "assistant" {
+			role = "分析師"
 		}
-		for _, h := range req.History {
-			messages = append(messages, chatMessage{Role: h.Role, Content: h.Content})
-		}
-		messages = append(messages, chatMessage{Role: "user", Content: req.Question})
-
-		chatReq := streamChatRequest{
-			Model:       "gpt-4o-mini",
-			Messages:    messages,
-			MaxTokens:   800,
-			Temperature: 0.4,
-			Stream:      true,
+		b.WriteString(role + "：" + m.Content + "\n")
+	}
+
+	previous := req.PreviousSummary
+	if previous == "" {
+		previous = "（無）"
+	}
+
+	prompt := fmt.Sprintf(`以下是用戶與分析師針對「%s」的對話。請將「先前摘要」與「新對話」合併成一份精簡摘要，供後續對話延續上下文。
+
+先前摘要：
+%s
+
+新對話：
+%s
+
+要求：
+1. 保留用戶關心的問題、分析師給出的結論與關鍵數據
+2. 保留引用標記（[P:...]、[A:...]、[F:...]）與 [[名稱|ID]] 格式
+3. 200 字以內，只輸出摘要本文`, req.EntityName, previous, b.String())
+
+	result, err := c.doChat(ctx, entity.UsageStageChat, chatRequest{
+		Model: "gpt-4o-mini",
+		Messages: []chatMessage{
+			{Role: "user", Content: prompt},
+		},
+		MaxTokens:   400,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("empty chat summary response")
+	}
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
+
+// streamChat 送出 streaming chat completion，逐 token 回傳並記錄用量
+func (c *Client) streamChat(ctx context.Context, stage entity.UsageStage, chatReq streamChatRequest) (<-chan string, <-chan error) {
+	tokenCh := make(chan string, 64)
+	errCh := make(chan error, 1)
+
+	chatReq.Stream = true
+	chatReq.StreamOptions = &streamOptions{IncludeUsage: true}
+
+	go func() {
+		defer close(tokenCh)
+		defer close(errCh)
+
+		if err := c.usage.Allow(ctx, stage); err != nil {
+			errCh <- err
+			return
 		}
 
 		body, err := json.Marshal(chatReq)
@@ -91,15 +157,24 @@ func (c *Client) StreamEntityChat(ctx context.Context, req *service.EntityChatRe
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
 
+		start := time.Now()
+		var usage apiUsage
+		var streamErr error
+		defer func() {
+			c.usage.Record(ctx, stage, "openai", chatReq.Model, usage.PromptTokens, usage.CompletionTokens, time.Since(start), streamErr)
+		}()
+
 		resp, err := c.httpClient.Do(httpReq)
 		if err != nil {
-			errCh <- fmt.Errorf("send chat request: %w", err)
+			streamErr = fmt.Errorf("send chat request: %w", err)
+			errCh <- streamErr
 			return
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			errCh <- fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+			streamErr = fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+			errCh <- streamErr
 			return
 		}
 
@@ -119,6 +194,9 @@ func (c *Client) StreamEntityChat(ctx context.Context, req *service.EntityChatRe
 			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 				continue
 			}
+			if chunk.Usage != nil {
+				usage = *chunk.Usage
+			}
 
 			for _, choice := range chunk.Choices {
 				if choice.Delta.Content != "" {
@@ -132,7 +210,8 @@ func (c *Client) StreamEntityChat(ctx context.Context, req *service.EntityChatRe
 		}
 
 		if err := scanner.Err(); err != nil {
-			errCh <- fmt.Errorf("read stream: %w", err)
+			streamErr = fmt.Errorf("read stream: %w", err)
+			errCh <- streamErr
 		}
 	}()
 
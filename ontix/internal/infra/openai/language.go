@@ -0,0 +1,66 @@
+package openai
+
+import (
+	"fmt"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+)
+
+// ============================================
+// 各語言的 prompt 補充說明
+//
+// 原本的 prompt 以繁體中文撰寫且預設中文貼文；其他語言的貼文在同一份 prompt 中
+// 插入語言段落：原文片段逐字保留、彙總用的欄位（面向、軟標籤、主題標籤）統一用繁體中文，
+// 並補上該語言的情感判斷提示。中文（與無法判斷的）貼文不插入任何內容，快取鍵維持不變。
+// ============================================
+
+// promptTask 使用語言段落的 prompt
+type promptTask int
+
+const (
+	promptTaskTags promptTask = iota
+	promptTaskAnalyze
+	promptTaskExtract
+)
+
+// languageCues 各語言的情感判斷提示
+var languageCues = map[entity.Language]string{
+	entity.LanguageEnglish: "注意否定與反諷（not bad 偏正面、could be better 偏負面、\"great, another delay\" 是抱怨）",
+	entity.LanguageJapanese: "日文評價常較委婉：「ちょっと…」「微妙」「イマイチ」通常是負面，「やばい」「ヤバい」依上下文可正可負，" +
+		"敬語與客套話不代表正面",
+	entity.LanguageMalay: "馬來西亞貼文常夾雜英文與華語（Manglish / bahasa rojak）：「best」「sedap」「padu」是正面，" +
+		"「tak berbaloi」「mahal gila」「hampeh」是負面，「lah」「je」「kan」只是語氣詞",
+}
+
+// languageSection 插入 prompt 的語言段落（中文或無法判斷時回傳空字串）
+func languageSection(lang entity.Language, task promptTask) string {
+	cues, ok := languageCues[lang]
+	if !ok {
+		return ""
+	}
+	name := lang.Name()
+
+	var rules string
+	switch task {
+	case promptTaskTags:
+		rules = "- brand / product 標籤保留原文寫法，不要翻譯\n" +
+			"- topic 標籤使用繁體中文（如 開箱、評測），與其他語言的貼文一起彙總\n" +
+			"- sentiment 標籤仍只能是：推薦, 不推, 普通\n"
+	case promptTaskAnalyze:
+		rules = fmt.Sprintf("- soft_tags、aspects.aspect、product_type、sentiment.reason 使用繁體中文，與其他語言的貼文一起彙總\n"+
+			"- aspects.mention 必須逐字引用%s原文，不要翻譯\n", name)
+	case promptTaskExtract:
+		rules = fmt.Sprintf("- name：匹配已知實體時使用清單中的名稱；新實體使用原文的正式名稱，不要自行翻譯\n"+
+			"- mention_text 與 aspects.mention 必須逐字引用%s原文，不要翻譯\n"+
+			"- aspects.aspect 與 content_topic 名稱使用繁體中文（如 服務態度、CP值），與其他語言的貼文一起彙總\n", name)
+	}
+	return fmt.Sprintf("【貼文語言】%s\n%s- %s\n\n", name, rules, cues)
+}
+
+// withLanguageKey 非中文貼文把語言段落加入快取鍵（中文貼文的快取鍵維持不變）
+func withLanguageKey(extra []string, section string) []string {
+	if section == "" {
+		return extra
+	}
+	return append(extra, section)
+}
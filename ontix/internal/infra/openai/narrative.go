@@ -1,12 +1,9 @@
 package openai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
 	"github.com/ikala/ontix/internal/domain/entity"
@@ -55,37 +52,9 @@ func (c *Client) GenerateNarrative(ctx context.Context, req *service.NarrativeRe
 		Temperature: 0.3,
 	}
 
-	body, err := json.Marshal(chatReq)
+	result, err := c.doChat(ctx, entity.UsageStageNarrative, chatReq)
 	if err != nil {
-		return nil, fmt.Errorf("marshal narrative request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create narrative request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("send narrative request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read narrative response: %w", err)
-	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal narrative response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ikala/ontix/config"
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 )
 
@@ -17,8 +21,25 @@ type Client struct {
 	apiKey         string
 	embeddingModel string
 	httpClient     *http.Client
+
+	mu       sync.RWMutex
+	fewShots []*entity.FewShotExample // 人工校正的情感 / 軟標籤範例（AnalyzePost few-shot）
+
+	cache *service.LLMCache     // 回應快取（nil = 停用）
+	usage *service.UsageTracker // 用量記帳 + 預算控管（nil = 停用）
 }
 
+// Prompt 版本：修改 prompt 模板時請遞增，讓舊的快取自然失效
+const (
+	tagsPromptVersion    = "tags/v1"
+	analyzePromptVersion = "analyze/v2"
+	extractPromptVersion = "extract/v1"
+	embedVersion         = "embed/v1"
+)
+
+// chatModel 標註 / 抽取使用的模型
+const chatModel = "gpt-4o-mini"
+
 // New 建立 OpenAI 客戶端
 func New(cfg *config.Config) (*Client, error) {
 	apiKey := cfg.OpenAIAPIKey
@@ -33,6 +54,16 @@ func New(cfg *config.Config) (*Client, error) {
 	}, nil
 }
 
+// SetCache 設定 LLM / Embedding 回應快取
+func (c *Client) SetCache(cache *service.LLMCache) {
+	c.cache = cache
+}
+
+// SetUsageTracker 設定用量記帳（每次 API 呼叫記錄 token / 延遲 / 成本）
+func (c *Client) SetUsageTracker(tracker *service.UsageTracker) {
+	c.usage = tracker
+}
+
 // === EmbeddingService 實作 ===
 
 type embeddingRequest struct {
@@ -44,6 +75,7 @@ type embeddingResponse struct {
 	Data []struct {
 		Embedding []float32 `json:"embedding"`
 	} `json:"data"`
+	Usage apiUsage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error"`
@@ -56,94 +88,65 @@ type batchEmbeddingRequest struct {
 
 // Embed 產生單一文本的向量
 func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := c.cache.Key(service.CacheNSEmbed, c.embeddingModel, embedVersion, text)
+	var cached []float32
+	if c.cache.Get(ctx, key, &cached) {
+		return cached, nil
+	}
+
 	req := embeddingRequest{
 		Model: c.embeddingModel,
 		Input: text,
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	result, err := c.doEmbedding(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var result embeddingResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Data) == 0 {
 		return nil, fmt.Errorf("empty embedding response")
 	}
 
+	c.cache.Set(ctx, key, result.Data[0].Embedding)
 	return result.Data[0].Embedding, nil
 }
 
-// BatchEmbed 批次產生向量
+// BatchEmbed 批次產生向量（只對快取未命中的文本呼叫 API）
 func (c *Client) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
-	req := batchEmbeddingRequest{
-		Model: c.embeddingModel,
-		Input: texts,
+	embeddings := make([][]float32, len(texts))
+	keys := make([]service.CacheKey, len(texts))
+	var missIdx []int
+	var missTexts []string
+	for i, text := range texts {
+		keys[i] = c.cache.Key(service.CacheNSEmbed, c.embeddingModel, embedVersion, text)
+		if c.cache.Get(ctx, keys[i], &embeddings[i]) {
+			continue
+		}
+		missIdx = append(missIdx, i)
+		missTexts = append(missTexts, text)
 	}
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	if len(missTexts) == 0 {
+		return embeddings, nil
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	req := batchEmbeddingRequest{
+		Model: c.embeddingModel,
+		Input: missTexts,
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
+	result, err := c.doEmbedding(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if len(result.Data) != len(missTexts) {
+		return nil, fmt.Errorf("embedding count mismatch: got %d, want %d", len(result.Data), len(missTexts))
 	}
-
-	var result embeddingResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
-	}
-
-	embeddings := make([][]float32, len(result.Data))
-	for i, d := range result.Data {
+	for j, d := range result.Data {
+		i := missIdx[j]
 		embeddings[i] = d.Embedding
+		c.cache.Set(ctx, keys[i], d.Embedding)
 	}
 
 	return embeddings, nil
@@ -169,11 +172,84 @@ type chatResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage apiUsage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error"`
 }
 
+// apiUsage OpenAI 回傳的 token 用量
+type apiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// doChat 送出 chat completion 請求並記錄用量（預算不足時回傳 service.ErrBudgetExceeded）
+func (c *Client) doChat(ctx context.Context, stage entity.UsageStage, req chatRequest) (*chatResponse, error) {
+	if err := c.usage.Allow(ctx, stage); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var result chatResponse
+	err := c.post(ctx, "https://api.openai.com/v1/chat/completions", req, &result)
+	if err == nil && result.Error != nil {
+		err = fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+	}
+	c.usage.Record(ctx, stage, "openai", req.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// doEmbedding 送出 embedding 請求並記錄用量
+func (c *Client) doEmbedding(ctx context.Context, req any) (*embeddingResponse, error) {
+	start := time.Now()
+	var result embeddingResponse
+	err := c.post(ctx, "https://api.openai.com/v1/embeddings", req, &result)
+	if err == nil && result.Error != nil {
+		err = fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+	}
+	c.usage.Record(ctx, entity.UsageStageEmbedding, "openai", c.embeddingModel, result.Usage.PromptTokens, 0, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// post 送出 JSON 請求並解析回應
+func (c *Client) post(ctx context.Context, url string, reqBody any, out any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
 type tagResult struct {
 	Name       string  `json:"name"`
 	Type       string  `json:"type"`
@@ -182,11 +258,18 @@ type tagResult struct {
 
 // GenerateTags 使用 LLM 產生標籤
 func (c *Client) GenerateTags(ctx context.Context, content string) ([]service.TagResult, error) {
+	langSection := languageSection(service.ResolveLanguage(ctx, content), promptTaskTags)
+	key := c.cache.Key(service.CacheNSTags, chatModel, tagsPromptVersion, content, withLanguageKey(nil, langSection)...)
+	var cached []service.TagResult
+	if c.cache.Get(ctx, key, &cached) {
+		return cached, nil
+	}
+
 	prompt := fmt.Sprintf(`分析以下社群貼文，提取結構化標籤。
 
 貼文內容：
 %s
-
+%s
 請以 JSON 格式回傳標籤列表：
 [
   {"name": "標籤名稱", "type": "類型", "confidence": 0.95}
@@ -203,10 +286,10 @@ func (c *Client) GenerateTags(ctx context.Context, content string) ([]service.Ta
 2. 情緒標籤必須有且只有一個
 3. 主題標籤 1-3 個
 4. confidence 範圍 0.0-1.0
-5. 總共 3-8 個標籤`, content)
+5. 總共 3-8 個標籤`, content, langSection)
 
 	req := chatRequest{
-		Model: "gpt-4o-mini",
+		Model: chatModel,
 		Messages: []chatMessage{
 			{Role: "user", Content: prompt},
 		},
@@ -214,37 +297,9 @@ func (c *Client) GenerateTags(ctx context.Context, content string) ([]service.Ta
 		Temperature: 0.2,
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	result, err := c.doChat(ctx, entity.UsageStageTagging, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
@@ -302,20 +357,57 @@ func (c *Client) GenerateTags(ctx context.Context, content string) ([]service.Ta
 		}
 	}
 
+	c.cache.Set(ctx, key, results)
 	return results, nil
 }
 
 // === TaggingService 實作 (全量 LLM 智能標註) ===
 
+// SetFewShotExamples 設定人工校正範例（實作 service.FewShotReceiver）
+func (c *Client) SetFewShotExamples(examples []*entity.FewShotExample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fewShots = examples
+}
+
 // AnalyzePost 分析貼文，回傳完整標註結果
 func (c *Client) AnalyzePost(ctx context.Context, content string) (*service.PostAnalysis, error) {
-	prompt := fmt.Sprintf(`你是社群貼文分析專家。分析以下貼文：
+	return c.analyze(ctx, content, "貼文", "")
+}
 
-貼文內容：
+// AnalyzeComment 分析留言 / 回覆，所屬貼文與上層留言只作為上下文
+func (c *Client) AnalyzeComment(ctx context.Context, content string, thread service.ThreadContext) (*service.PostAnalysis, error) {
+	threadSection := formatThreadContext(thread) +
+		"\n只分析「留言內容」本身：情感是留言者的態度，aspects 與 mention 只取自留言原文；" +
+		"留言中的「這個」「他們家」等指涉可依上下文理解。\n"
+	return c.analyze(ctx, content, "留言", threadSection)
+}
+
+// analyze AnalyzePost / AnalyzeComment 共用：subject 為「貼文」或「留言」，threadSection 為串上下文
+func (c *Client) analyze(ctx context.Context, content, subject, threadSection string) (*service.PostAnalysis, error) {
+	c.mu.RLock()
+	fewShots := service.FormatAnalysisExamples(c.fewShots)
+	c.mu.RUnlock()
+
+	extra := []string{fewShots}
+	if threadSection != "" { // 貼文的快取鍵維持不變
+		extra = append(extra, threadSection)
+	}
+	langSection := languageSection(service.ResolveLanguage(ctx, content), promptTaskAnalyze)
+	extra = withLanguageKey(extra, langSection)
+	key := c.cache.Key(service.CacheNSAnalyze, chatModel, analyzePromptVersion, content, extra...)
+	var cached service.PostAnalysis
+	if c.cache.Get(ctx, key, &cached) {
+		return &cached, nil
+	}
+
+	prompt := fmt.Sprintf(`你是社群貼文分析專家。分析以下%s：
+%s%s
+%s內容：
 """
 %s
 """
-
+%s
 請回覆 JSON（嚴格遵守格式，不要加任何其他文字）：
 {
   "sentiment": {
@@ -338,10 +430,10 @@ func (c *Client) AnalyzePost(ctx context.Context, content string) (*service.Post
 2. aspects 提取貼文中提到的具體面向評價（如持妝度、遮瑕力、控油效果等）
 3. sentiment.label 根據整體情感傾向判斷
 4. sentiment.score: positive=0.7-1.0, neutral=0.4-0.6, negative=0.0-0.3, mixed=0.4-0.6
-5. intent 判斷貼文意圖類型`, content)
+5. intent 判斷%s意圖類型`, subject, fewShots, threadSection, subject, content, langSection, subject)
 
 	req := chatRequest{
-		Model: "gpt-4o-mini",
+		Model: chatModel,
 		Messages: []chatMessage{
 			{Role: "user", Content: prompt},
 		},
@@ -349,37 +441,9 @@ func (c *Client) AnalyzePost(ctx context.Context, content string) (*service.Post
 		Temperature: 0.1,
 	}
 
-	body, err := json.Marshal(req)
+	result, err := c.doChat(ctx, entity.UsageStageTagging, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
@@ -420,6 +484,7 @@ func (c *Client) AnalyzePost(ctx context.Context, content string) (*service.Post
 		return nil, fmt.Errorf("failed to parse analysis (json: %s): %w", jsonStr, err)
 	}
 
+	c.cache.Set(ctx, key, &analysis)
 	return &analysis, nil
 }
 
@@ -431,6 +496,19 @@ func (c *Client) AnalyzePost(ctx context.Context, content string) (*service.Post
 // - knownEntities 注入 Prompt，LLM 直接回傳 canonical_name（一次呼叫完成抽取+消歧）
 // - 例如：已知 [星巴克(brand)]，貼文出現 "Starbucks" → LLM 直接回傳 name="星巴克"
 func (c *Client) ExtractEntities(ctx context.Context, content string, knownEntities []service.KnownEntity) (*service.EntityExtractionResult, error) {
+	return c.extractEntities(ctx, content, "", knownEntities)
+}
+
+// ExtractCommentEntities 從留言 / 回覆中抽取 Entity，所屬貼文作為指涉解析的上下文
+func (c *Client) ExtractCommentEntities(ctx context.Context, content string, thread service.ThreadContext, knownEntities []service.KnownEntity) (*service.EntityExtractionResult, error) {
+	threadSection := "\n" + formatThreadContext(thread) +
+		"\n下方「貼文內容」是上述貼文底下的留言。只抽取留言本身提及或評價的實體，mention_text 與 aspects.mention 必須取自留言原文；" +
+		"留言以「這個」「他們家」「這支」等指涉貼文中的實體時，使用該實體的名稱。上下文中出現但留言沒有談到的實體不要抽取。\n"
+	return c.extractEntities(ctx, content, threadSection, knownEntities)
+}
+
+// extractEntities ExtractEntities / ExtractCommentEntities 共用
+func (c *Client) extractEntities(ctx context.Context, content, threadSection string, knownEntities []service.KnownEntity) (*service.EntityExtractionResult, error) {
 	// 建構已知 Entity 列表字串
 	knownSection := ""
 	if len(knownEntities) > 0 {
@@ -449,18 +527,31 @@ func (c *Client) ExtractEntities(ctx context.Context, content string, knownEntit
 		knownSection += "\n如果貼文提到的實體不在清單中，才視為新實體。\n"
 	}
 
+	extra := []string{knownSection}
+	if threadSection != "" { // 貼文的快取鍵維持不變
+		extra = append(extra, threadSection)
+	}
+	langSection := languageSection(service.ResolveLanguage(ctx, content), promptTaskExtract)
+	extra = withLanguageKey(extra, langSection)
+	key := c.cache.Key(service.CacheNSExtract, chatModel, extractPromptVersion, content, extra...)
+	var cached service.EntityExtractionResult
+	if c.cache.Get(ctx, key, &cached) {
+		cached.Model, cached.PromptVersion = chatModel, extractPromptVersion
+		return &cached, nil
+	}
+
 	prompt := fmt.Sprintf(`【任務背景】
 你在一個「品牌輿情監控系統」中負責實體抽取。這個系統的目標是追蹤社群媒體上人們討論了哪些品牌、產品、店家、人物、作品、活動，以及對它們的評價。
 抽取出的實體會建成知識圖譜，供品牌方查詢：「消費者怎麼看我的品牌？」「哪個 KOL 提過我的產品？」「競品的評價如何？」
 
 因此，你應該只抽取「有人會想查詢、追蹤、比較」的具名實體。
 純地址、泛稱角色（全職媽媽、上班族）、形容詞、情境描述都不是實體。
-%s
+%s%s
 貼文內容：
 """
 %s
 """
-
+%s
 請回覆 JSON（嚴格遵守格式，不要加任何其他文字）：
 {
   "entities": [
@@ -588,10 +679,10 @@ product 命名原則（重要！）：
    - 若貼文在討論一個可追蹤的主題概念（如「油痘肌護膚」、「美妝教程」），抽取為 content_topic
    - 必須填寫 category 欄位（美妝/穿搭/美食/旅遊/3C/生活/健身/寵物/其他）
    - 如果文中有 person 在討論 topic，加入 discusses 關係（person → discusses → topic）
-   - 如果文中有 brand 與 topic 相關，加入 relevant_to 關係（topic → relevant_to → brand）`, knownSection, content)
+   - 如果文中有 brand 與 topic 相關，加入 relevant_to 關係（topic → relevant_to → brand）`, knownSection, threadSection, content, langSection)
 
 	req := chatRequest{
-		Model: "gpt-4o-mini",
+		Model: chatModel,
 		Messages: []chatMessage{
 			{Role: "user", Content: prompt},
 		},
@@ -599,37 +690,9 @@ product 命名原則（重要！）：
 		Temperature: 0.1,
 	}
 
-	body, err := json.Marshal(req)
+	result, err := c.doChat(ctx, entity.UsageStageExtraction, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
@@ -669,5 +732,36 @@ product 命名原則（重要！）：
 		return nil, fmt.Errorf("failed to parse entity extraction (json: %s): %w", jsonStr, err)
 	}
 
+	c.cache.Set(ctx, key, &extraction)
+	extraction.Model, extraction.PromptVersion = chatModel, extractPromptVersion
 	return &extraction, nil
 }
+
+// threadContextMaxRunes 上下文貼文 / 留言的截斷長度（只用來理解指涉，不需要全文）
+const threadContextMaxRunes = 600
+
+// formatThreadContext 留言串上下文（貼文 + 上層留言）
+func formatThreadContext(thread service.ThreadContext) string {
+	var b strings.Builder
+	b.WriteString("【所屬貼文（僅供上下文，不要分析）】\n")
+	if thread.PostAuthor != "" {
+		fmt.Fprintf(&b, "作者：%s\n", thread.PostAuthor)
+	}
+	fmt.Fprintf(&b, "\"\"\"\n%s\n\"\"\"\n", truncateContext(thread.PostContent))
+	if thread.ParentContent != "" {
+		b.WriteString("【回覆的上層留言（僅供上下文，不要分析）】\n")
+		if thread.ParentAuthor != "" {
+			fmt.Fprintf(&b, "作者：%s\n", thread.ParentAuthor)
+		}
+		fmt.Fprintf(&b, "\"\"\"\n%s\n\"\"\"\n", truncateContext(thread.ParentContent))
+	}
+	return b.String()
+}
+
+func truncateContext(s string) string {
+	r := []rune(s)
+	if len(r) <= threadContextMaxRunes {
+		return s
+	}
+	return string(r[:threadContextMaxRunes]) + "…"
+}
@@ -1,15 +1,13 @@
 package openai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ikala/ontix/internal/domain/entity"
 	"github.com/ikala/ontix/internal/domain/service"
 )
 
@@ -134,37 +132,9 @@ func (c *Client) GenerateEntitySummary(ctx context.Context, req *service.EntityS
 		Temperature: 0.3,
 	}
 
-	body, err := json.Marshal(chatReq)
+	result, err := c.doChat(ctx, entity.UsageStageSummary, chatReq)
 	if err != nil {
-		return nil, fmt.Errorf("marshal summary request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create summary request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("send summary request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read summary response: %w", err)
-	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal summary response: %w", err)
-	}
-
-	if result.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", result.Error.Message)
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
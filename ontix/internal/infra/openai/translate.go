@@ -0,0 +1,95 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+)
+
+const translatePromptVersion = "translate/v1"
+
+// translateTargets 翻譯目標語言在 prompt 中的寫法
+var translateTargets = map[entity.Language]string{
+	entity.LanguageChinese:  "繁體中文（台灣用語）",
+	entity.LanguageEnglish:  "英文（English）",
+	entity.LanguageJapanese: "日文（日本語）",
+	entity.LanguageMalay:    "馬來文（Bahasa Melayu）",
+}
+
+// Translate 將片段翻譯為目標語言（實作 service.TranslationService）
+// 每個片段各自快取，未命中的片段合併為一次呼叫
+func (c *Client) Translate(ctx context.Context, texts []string, target entity.Language) ([]string, error) {
+	targetName, ok := translateTargets[target]
+	if !ok {
+		return nil, fmt.Errorf("unsupported target language %q", target)
+	}
+
+	out := make([]string, len(texts))
+	keys := make([]service.CacheKey, len(texts))
+	var missIdx []int
+	for i, text := range texts {
+		keys[i] = c.cache.Key(service.CacheNSTranslate, chatModel, translatePromptVersion, text, string(target))
+		if !c.cache.Get(ctx, keys[i], &out[i]) {
+			missIdx = append(missIdx, i)
+		}
+	}
+	if len(missIdx) == 0 {
+		return out, nil
+	}
+
+	var b strings.Builder
+	for n, i := range missIdx {
+		fmt.Fprintf(&b, "[%d]\n\"\"\"\n%s\n\"\"\"\n", n+1, texts[i])
+	}
+
+	prompt := fmt.Sprintf(`你是社群輿情分析的翻譯助手。將以下 %d 則社群貼文片段翻譯成%s，供分析師閱讀。
+
+%s
+規則：
+1. 忠實翻譯語氣與情緒（抱怨、反諷、俚語要譯出實際意思），不要摘要或修飾
+2. 品牌、產品、人名保留原文寫法
+3. emoji、hashtag、<mark> 等標記原樣保留
+4. 回覆 JSON 字串陣列，順序與編號一致，共 %d 個元素，不要加任何其他文字`, len(missIdx), targetName, b.String(), len(missIdx))
+
+	req := chatRequest{
+		Model: chatModel,
+		Messages: []chatMessage{
+			{Role: "user", Content: prompt},
+		},
+		MaxTokens:   4000,
+		Temperature: 0.1,
+	}
+
+	result, err := c.doChat(ctx, entity.UsageStageTranslation, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+
+	responseText := result.Choices[0].Message.Content
+	start := strings.Index(responseText, "[")
+	end := strings.LastIndex(responseText, "]")
+	if start == -1 || end <= start {
+		return nil, fmt.Errorf("no JSON array found in translation response: %s", responseText)
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(responseText[start:end+1]), &translated); err != nil {
+		return nil, fmt.Errorf("failed to parse translation: %w", err)
+	}
+	if len(translated) != len(missIdx) {
+		return nil, fmt.Errorf("translation returned %d texts, want %d", len(translated), len(missIdx))
+	}
+
+	for n, i := range missIdx {
+		out[i] = translated[n]
+		c.cache.Set(ctx, keys[i], translated[n])
+	}
+	return out, nil
+}
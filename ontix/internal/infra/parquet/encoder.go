@@ -0,0 +1,138 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/service"
+	pq "github.com/parquet-go/parquet-go"
+)
+
+// rowGroupRows 每個 row group 的列數上限（兼顧讀取效率與寫入端記憶體）
+const rowGroupRows = 20000
+
+// Encoder 以 Parquet 格式逐列輸出
+//
+// 純量欄位為 optional（NULL 以 null 表示），清單欄位為 repeated string，
+// 時間欄位為 TIMESTAMP(MILLIS, UTC)；pandas / pyarrow 讀取後即為原生型別
+type Encoder struct {
+	w        *pq.Writer
+	columns  []entity.ExportColumn
+	index    []int // columns[i] 在 schema 中的 leaf column index
+	order    []int // 依 leaf column index 排序後的 columns 索引
+	row      pq.Row
+	buffered int
+}
+
+// NewEncoder 建立 Parquet Encoder（符合 service.ExportEncoderFactory）
+func NewEncoder(w io.Writer, columns []entity.ExportColumn) (service.ExportEncoder, error) {
+	group := pq.Group{}
+	for _, col := range columns {
+		group[col.Name] = parquetNode(col.Type)
+	}
+	schema := pq.NewSchema("ontix", group)
+
+	enc := &Encoder{
+		w:       pq.NewWriter(w, schema, pq.Compression(&pq.Snappy), pq.CreatedBy("ontix", "", "")),
+		columns: columns,
+		index:   make([]int, len(columns)),
+		order:   make([]int, len(columns)),
+	}
+	for i, col := range columns {
+		leaf, ok := schema.Lookup(col.Name)
+		if !ok {
+			return nil, fmt.Errorf("parquet column %q not found in schema", col.Name)
+		}
+		enc.index[i] = leaf.ColumnIndex
+		enc.order[leaf.ColumnIndex] = i
+	}
+	return enc, nil
+}
+
+func parquetNode(t entity.ExportColumnType) pq.Node {
+	switch t {
+	case entity.ExportInt:
+		return pq.Optional(pq.Int(64))
+	case entity.ExportFloat:
+		return pq.Optional(pq.Leaf(pq.DoubleType))
+	case entity.ExportBool:
+		return pq.Optional(pq.Leaf(pq.BooleanType))
+	case entity.ExportTime:
+		return pq.Optional(pq.Timestamp(pq.Millisecond))
+	case entity.ExportStrings:
+		return pq.Repeated(pq.String())
+	default:
+		return pq.Optional(pq.String())
+	}
+}
+
+// Write 寫入一列（值依 schema 的 column 順序排列）
+func (e *Encoder) Write(row []any) error {
+	e.row = e.row[:0]
+	for _, i := range e.order {
+		col := e.index[i]
+		v := row[i]
+
+		if e.columns[i].Type == entity.ExportStrings {
+			list, _ := v.([]string)
+			if len(list) == 0 {
+				e.row = append(e.row, pq.NullValue().Level(0, 0, col))
+				continue
+			}
+			for j, s := range list {
+				rep := 0
+				if j > 0 {
+					rep = 1
+				}
+				e.row = append(e.row, pq.ByteArrayValue([]byte(s)).Level(rep, 1, col))
+			}
+			continue
+		}
+
+		value, ok := parquetValue(v)
+		if !ok {
+			e.row = append(e.row, pq.NullValue().Level(0, 0, col))
+			continue
+		}
+		e.row = append(e.row, value.Level(0, 1, col))
+	}
+
+	if _, err := e.w.WriteRows([]pq.Row{e.row}); err != nil {
+		return err
+	}
+	e.buffered++
+	return nil
+}
+
+func parquetValue(v any) (pq.Value, bool) {
+	switch x := v.(type) {
+	case string:
+		return pq.ByteArrayValue([]byte(x)), true
+	case int64:
+		return pq.Int64Value(x), true
+	case float64:
+		return pq.DoubleValue(x), true
+	case bool:
+		return pq.BooleanValue(x), true
+	case time.Time:
+		return pq.Int64Value(x.UnixMilli()), true
+	default:
+		return pq.Value{}, false
+	}
+}
+
+// Flush 緩衝達 rowGroupRows 時結束目前的 row group 並寫出
+func (e *Encoder) Flush() error {
+	if e.buffered < rowGroupRows {
+		return nil
+	}
+	e.buffered = 0
+	return e.w.Flush()
+}
+
+// Close 寫出剩餘資料與 footer
+func (e *Encoder) Close() error {
+	return e.w.Close()
+}
@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ikala/ontix/config"
+)
+
+// 支援的轉檔程式
+const (
+	ConverterWkhtmltopdf = "wkhtmltopdf"
+	ConverterChromium    = "chromium"
+)
+
+// renderTimeout 單次轉檔的最長時間
+const renderTimeout = 2 * time.Minute
+
+// Renderer 以外部程式（wkhtmltopdf / headless Chromium）將 HTML 轉為 PDF
+type Renderer struct {
+	converter string
+	binary    string
+}
+
+// New 依設定建立 Renderer；未設定 pdf_converter 時回傳 nil（不產生 PDF）
+func New(cfg *config.Config) (*Renderer, error) {
+	converter := cfg.Reports.PDFConverter
+	if converter == "" {
+		return nil, nil
+	}
+
+	binary := cfg.Reports.PDFBinary
+	switch converter {
+	case ConverterWkhtmltopdf:
+		if binary == "" {
+			binary = "wkhtmltopdf"
+		}
+	case ConverterChromium:
+		if binary == "" {
+			binary = "chromium"
+		}
+	default:
+		return nil, fmt.Errorf("unknown pdf converter %q (expected %s or %s)", converter, ConverterWkhtmltopdf, ConverterChromium)
+	}
+
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("pdf converter %q not found: %w", binary, err)
+	}
+	return &Renderer{converter: converter, binary: path}, nil
+}
+
+// RenderPDF 將 HTML 轉為 PDF
+func (r *Renderer) RenderPDF(ctx context.Context, html string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "ontix-report-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "report.html")
+	out := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(in, []byte(html), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write report html: %w", err)
+	}
+
+	var args []string
+	switch r.converter {
+	case ConverterWkhtmltopdf:
+		args = []string{"--quiet", "--encoding", "utf-8", "--page-size", "A4", in, out}
+	case ConverterChromium:
+		args = []string{"--headless", "--disable-gpu", "--no-sandbox", "--no-pdf-header-footer",
+			"--print-to-pdf=" + out, "file://" + in}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", r.converter, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered pdf: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// AspectTaxonomyRepo 正規面向樹與原始面向對應 PostgreSQL 實作
+type AspectTaxonomyRepo struct {
+	db *DB
+}
+
+// NewAspectTaxonomyRepo 建立 AspectTaxonomyRepo
+func NewAspectTaxonomyRepo(db *DB) repository.AspectTaxonomyRepository {
+	return &AspectTaxonomyRepo{db: db}
+}
+
+const aspectNodeSelect = `
+	SELECT n.id, n.class_id, c.slug, n.parent_id, n.name, n.path, n.synonyms, n.created_at
+	FROM aspect_nodes n
+	JOIN ontology_classes c ON c.id = n.class_id`
+
+// aspectClassChain 限定在 class $1 與其祖先的節點（n 為 aspect_nodes 的別名），ca.depth 越小越近
+const aspectClassChain = `
+	JOIN ontology_class_ancestors ca ON ca.ancestor_id = n.class_id AND ca.class_id = $1`
+
+// ListNodes 某 class 可用的節點（含祖先 class），classID <= 0 時回傳全部
+func (r *AspectTaxonomyRepo) ListNodes(ctx context.Context, classID int) ([]*entity.AspectNode, error) {
+	query := aspectNodeSelect + ` ORDER BY n.path, n.class_id`
+	var args []any
+	if classID > 0 {
+		query = aspectNodeSelect + aspectClassChain + ` ORDER BY n.path, ca.depth`
+		args = append(args, classID)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aspect nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.AspectNode
+	for rows.Next() {
+		n, err := scanAspectNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// FindNode 以 ID 查節點
+func (r *AspectTaxonomyRepo) FindNode(ctx context.Context, id int) (*entity.AspectNode, error) {
+	n, err := scanAspectNode(r.db.Pool.QueryRow(ctx, aspectNodeSelect+` WHERE n.id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return n, err
+}
+
+// SaveNode 新增節點，path = 父節點 path || name
+func (r *AspectTaxonomyRepo) SaveNode(ctx context.Context, node *entity.AspectNode) (bool, error) {
+	synonyms := node.Synonyms
+	if synonyms == nil {
+		synonyms = []string{}
+	}
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms)
+		SELECT $1, $2, $3, COALESCE((SELECT path FROM aspect_nodes WHERE id = $2), '{}') || $3::text, $4
+		ON CONFLICT (class_id, name) DO NOTHING
+		RETURNING id, path, created_at`,
+		node.ClassID, node.ParentID, node.Name, synonyms,
+	).Scan(&node.ID, &node.Path, &node.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save aspect node: %w", err)
+	}
+	node.Synonyms = synonyms
+	return true, nil
+}
+
+// NodesWithoutEmbedding 尚未計算向量的節點
+func (r *AspectTaxonomyRepo) NodesWithoutEmbedding(ctx context.Context, limit int) ([]*entity.AspectNode, error) {
+	rows, err := r.db.Pool.Query(ctx, aspectNodeSelect+`
+		WHERE n.embedding IS NULL
+		ORDER BY n.id
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aspect nodes without embedding: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.AspectNode
+	for rows.Next() {
+		n, err := scanAspectNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// SaveNodeEmbedding 儲存節點向量
+func (r *AspectTaxonomyRepo) SaveNodeEmbedding(ctx context.Context, id int, embedding []float32) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE aspect_nodes SET embedding = $2 WHERE id = $1`,
+		id, pgvector.NewVector(embedding))
+	if err != nil {
+		return fmt.Errorf("failed to save aspect node embedding: %w", err)
+	}
+	return nil
+}
+
+// MatchNode 名稱或別名精確命中的節點：最近的 class 優先，同 class 時名稱優先於別名
+func (r *AspectTaxonomyRepo) MatchNode(ctx context.Context, classID int, raw string) (*entity.AspectNode, error) {
+	n, err := scanAspectNode(r.db.Pool.QueryRow(ctx, aspectNodeSelect+aspectClassChain+`
+		WHERE LOWER(n.name) = $2
+		   OR $2 = ANY(SELECT LOWER(s) FROM unnest(n.synonyms) s)
+		ORDER BY ca.depth, (LOWER(n.name) <> $2)
+		LIMIT 1`, classID, raw))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match aspect node: %w", err)
+	}
+	return n, nil
+}
+
+// NearestNode 向量最相近的節點（cosine 相似度）
+func (r *AspectTaxonomyRepo) NearestNode(ctx context.Context, classID int, embedding []float32) (*entity.AspectNode, float64, error) {
+	var n entity.AspectNode
+	var similarity float64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT n.id, n.class_id, c.slug, n.parent_id, n.name, n.path, n.synonyms, n.created_at,
+		       1 - (n.embedding <=> $2)
+		FROM aspect_nodes n
+		JOIN ontology_classes c ON c.id = n.class_id`+aspectClassChain+`
+		WHERE n.embedding IS NOT NULL
+		ORDER BY n.embedding <=> $2, ca.depth
+		LIMIT 1`, classID, pgvector.NewVector(embedding),
+	).Scan(&n.ID, &n.ClassID, &n.ClassSlug, &n.ParentID, &n.Name, &n.Path, &n.Synonyms, &n.CreatedAt, &similarity)
+	if err == pgx.ErrNoRows {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find nearest aspect node: %w", err)
+	}
+	return &n, similarity, nil
+}
+
+const aspectMappingSelect = `
+	SELECT m.id, m.class_id, c.slug, m.raw_aspect, m.node_id, n.path, m.status,
+	       m.similarity::float8, m.suggested_node_id, sn.path, m.mention_count,
+	       COALESCE(m.reviewer, ''), m.created_at, m.updated_at
+	FROM aspect_mappings m
+	JOIN ontology_classes c ON c.id = m.class_id
+	LEFT JOIN aspect_nodes n ON n.id = m.node_id
+	LEFT JOIN aspect_nodes sn ON sn.id = m.suggested_node_id`
+
+// FindMapping 查原始面向的對應
+func (r *AspectTaxonomyRepo) FindMapping(ctx context.Context, classID int, raw string) (*entity.AspectMapping, error) {
+	m, err := scanAspectMapping(r.db.Pool.QueryRow(ctx, aspectMappingSelect+`
+		WHERE m.class_id = $1 AND m.raw_aspect = $2`, classID, raw))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// FindMappingByID 以 ID 查對應
+func (r *AspectTaxonomyRepo) FindMappingByID(ctx context.Context, id int64) (*entity.AspectMapping, error) {
+	m, err := scanAspectMapping(r.db.Pool.QueryRow(ctx, aspectMappingSelect+` WHERE m.id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// SaveMapping 新增對應；已存在時累加 mention_count，人工審核過或自動對應的結果不會被覆寫
+// 回填實際寫入後的 ID / 狀態 / 節點
+func (r *AspectTaxonomyRepo) SaveMapping(ctx context.Context, m *entity.AspectMapping) error {
+	var status string
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO aspect_mappings (class_id, raw_aspect, node_id, status, similarity, suggested_node_id, mention_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (class_id, raw_aspect) DO UPDATE SET
+			mention_count     = aspect_mappings.mention_count + EXCLUDED.mention_count,
+			node_id           = CASE WHEN aspect_mappings.status = 'pending' THEN EXCLUDED.node_id ELSE aspect_mappings.node_id END,
+			similarity        = CASE WHEN aspect_mappings.status = 'pending' THEN EXCLUDED.similarity ELSE aspect_mappings.similarity END,
+			suggested_node_id = CASE WHEN aspect_mappings.status = 'pending' THEN EXCLUDED.suggested_node_id ELSE aspect_mappings.suggested_node_id END,
+			status            = CASE WHEN aspect_mappings.status = 'pending' THEN EXCLUDED.status ELSE aspect_mappings.status END,
+			updated_at        = NOW()
+		RETURNING id, node_id, status, created_at, updated_at`,
+		m.ClassID, m.RawAspect, m.NodeID, string(m.Status), m.Similarity, m.SuggestedNodeID, m.MentionCount,
+	).Scan(&m.ID, &m.NodeID, &status, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save aspect mapping: %w", err)
+	}
+	m.Status = entity.AspectMappingStatus(status)
+	return nil
+}
+
+// ListMappings 依狀態列出對應
+func (r *AspectTaxonomyRepo) ListMappings(ctx context.Context, status entity.AspectMappingStatus, offset, limit int) ([]*entity.AspectMapping, int, error) {
+	where := ""
+	args := []any{}
+	if status != "" {
+		where = ` WHERE m.status = $1`
+		args = append(args, string(status))
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM aspect_mappings m`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count aspect mappings: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	rows, err := r.db.Pool.Query(ctx, aspectMappingSelect+where+fmt.Sprintf(`
+		ORDER BY m.mention_count DESC, m.id
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list aspect mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.AspectMapping
+	for rows.Next() {
+		m, err := scanAspectMapping(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, m)
+	}
+	return out, total, rows.Err()
+}
+
+// ResolveMapping 人工審核結果
+func (r *AspectTaxonomyRepo) ResolveMapping(ctx context.Context, id int64, nodeID *int, status entity.AspectMappingStatus, reviewer string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE aspect_mappings
+		SET node_id = $2, status = $3, reviewer = NULLIF($4, ''), updated_at = NOW()
+		WHERE id = $1`, id, nodeID, string(status), reviewer)
+	if err != nil {
+		return fmt.Errorf("failed to resolve aspect mapping: %w", err)
+	}
+	return nil
+}
+
+// ApplyMapping 將對應寫回 entity_aspects（未設定 class 的 Entity 視為根類別）
+func (r *AspectTaxonomyRepo) ApplyMapping(ctx context.Context, classID int, raw string, nodeID *int) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE entity_aspects ea SET aspect_node_id = $3
+		FROM objects o
+		WHERE o.id = ea.object_id
+		  AND COALESCE(o.class_id, $4) = $1
+		  AND LOWER(BTRIM(ea.aspect)) = $2
+		  AND ea.aspect_node_id IS DISTINCT FROM $3::int`,
+		classID, raw, nodeID, entity.AspectRootClassID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply aspect mapping: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UnmappedAspects 尚未對應、也不在審核佇列（pending / ignored）的原始面向
+// 已有 auto / manual 對應但 aspect_node_id 仍為空的面向也會列出（例如審核後才寫入的資料）
+func (r *AspectTaxonomyRepo) UnmappedAspects(ctx context.Context, limit int) ([]*entity.RawAspect, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT r.class_id, r.raw_aspect, r.cnt
+		FROM (
+			SELECT COALESCE(o.class_id, $2) AS class_id, LOWER(BTRIM(ea.aspect)) AS raw_aspect, COUNT(*)::int AS cnt
+			FROM entity_aspects ea
+			JOIN objects o ON o.id = ea.object_id
+			WHERE ea.aspect_node_id IS NULL
+			GROUP BY 1, 2
+		) r
+		WHERE NOT EXISTS (
+			SELECT 1 FROM aspect_mappings m
+			WHERE m.class_id = r.class_id AND m.raw_aspect = r.raw_aspect
+			  AND m.status IN ('pending', 'ignored')
+		)
+		ORDER BY r.cnt DESC, r.raw_aspect
+		LIMIT $1`, limit, entity.AspectRootClassID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmapped aspects: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.RawAspect
+	for rows.Next() {
+		var a entity.RawAspect
+		if err := rows.Scan(&a.ClassID, &a.RawAspect, &a.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan unmapped aspect: %w", err)
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func scanAspectNode(row pgx.Row) (*entity.AspectNode, error) {
+	var n entity.AspectNode
+	if err := row.Scan(&n.ID, &n.ClassID, &n.ClassSlug, &n.ParentID, &n.Name, &n.Path, &n.Synonyms, &n.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan aspect node: %w", err)
+	}
+	return &n, nil
+}
+
+func scanAspectMapping(row pgx.Row) (*entity.AspectMapping, error) {
+	var m entity.AspectMapping
+	var status string
+	err := row.Scan(&m.ID, &m.ClassID, &m.ClassSlug, &m.RawAspect, &m.NodeID, &m.NodePath, &status,
+		&m.Similarity, &m.SuggestedNodeID, &m.SuggestedNodePath, &m.MentionCount,
+		&m.Reviewer, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan aspect mapping: %w", err)
+	}
+	m.Status = entity.AspectMappingStatus(status)
+	return &m, nil
+}
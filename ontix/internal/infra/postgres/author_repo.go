@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuthorRepo 作者登錄 PostgreSQL 實作
+type AuthorRepo struct {
+	db *DB
+}
+
+// NewAuthorRepo 建立 AuthorRepo
+func NewAuthorRepo(db *DB) repository.AuthorRepository {
+	return &AuthorRepo{db: db}
+}
+
+// authorPostJoin posts → authors 的 join 條件（與 idx_posts_author_key 一致）
+const authorPostJoin = `p.platform = a.platform AND COALESCE(NULLIF(p.author_id, ''), p.author_username) = a.platform_user_id`
+
+// authorSelect 作者檔案 + 統計（統計尚未刷新的新作者為 NULL）
+const authorSelect = `
+	SELECT a.id, a.platform, a.platform_user_id, a.username, a.followers,
+	       a.object_id::text, COALESCE(o.canonical_name, ''), a.first_seen_at, a.last_seen_at,
+	       COALESCE(s.post_count, 0), COALESCE(s.posts_28d, 0), COALESCE(s.posts_per_week, 0)::float8,
+	       s.avg_sentiment::float8, COALESCE(s.avg_engagement, 0)::float8, s.engagement_rate::float8, s.last_post_at
+	FROM authors a
+	LEFT JOIN objects o ON o.id = a.object_id
+	LEFT JOIN author_stats s ON s.author_id = a.id`
+
+var authorSortColumns = map[string]string{
+	"followers":       "a.followers",
+	"posts":           "COALESCE(s.post_count, 0)",
+	"posts_per_week":  "COALESCE(s.posts_per_week, 0)",
+	"engagement_rate": "COALESCE(s.engagement_rate, 0)",
+	"avg_sentiment":   "COALESCE(s.avg_sentiment, 0)",
+	"last_seen":       "a.last_seen_at",
+}
+
+// Upsert 登錄作者並記錄當日追蹤數快照
+// 追蹤數為 0 視為未知（不覆蓋既有值、不記錄快照）
+func (r *AuthorRepo) Upsert(ctx context.Context, author *entity.AuthorProfile) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO authors (platform, platform_user_id, username, followers, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (platform, platform_user_id) DO UPDATE SET
+			username = COALESCE(NULLIF(EXCLUDED.username, ''), authors.username),
+			followers = CASE WHEN EXCLUDED.followers > 0 THEN EXCLUDED.followers ELSE authors.followers END,
+			first_seen_at = LEAST(authors.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(authors.last_seen_at, EXCLUDED.last_seen_at),
+			updated_at = NOW()
+		RETURNING id, username, followers, object_id::text, first_seen_at, last_seen_at`,
+		string(author.Platform), author.PlatformUserID, author.Username, author.Followers, author.LastSeenAt,
+	).Scan(&author.ID, &author.Username, &author.Followers, &author.ObjectID, &author.FirstSeenAt, &author.LastSeenAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert author: %w", err)
+	}
+
+	if author.Followers > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO author_follower_history (author_id, captured_on, followers)
+			VALUES ($1, CURRENT_DATE, $2)
+			ON CONFLICT (author_id, captured_on) DO UPDATE SET followers = EXCLUDED.followers`,
+			author.ID, author.Followers)
+		if err != nil {
+			return fmt.Errorf("failed to save follower snapshot: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// LinkObject 將作者連結到 person Entity
+func (r *AuthorRepo) LinkObject(ctx context.Context, authorID int64, objectID string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE authors SET object_id = $2, updated_at = NOW() WHERE id = $1`, authorID, objectID)
+	if err != nil {
+		return fmt.Errorf("failed to link author to object: %w", err)
+	}
+	return nil
+}
+
+// FindByID 查詢作者（含統計）
+func (r *AuthorRepo) FindByID(ctx context.Context, id int64) (*entity.AuthorProfile, error) {
+	row := r.db.Pool.QueryRow(ctx, authorSelect+` WHERE a.id = $1`, id)
+	a, err := scanAuthor(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+// List 列出作者（含統計）
+func (r *AuthorRepo) List(ctx context.Context, filter *entity.AuthorFilter, offset, limit int) ([]*entity.AuthorProfile, int, error) {
+	var where []string
+	var args []any
+	add := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	orderBy := "a.followers DESC"
+	if filter != nil {
+		if filter.Platform != "" {
+			where = append(where, "a.platform = "+add(filter.Platform))
+		}
+		if filter.Q != "" {
+			where = append(where, "a.username ILIKE "+add("%"+filter.Q+"%"))
+		}
+		if filter.MinFollowers > 0 {
+			where = append(where, "a.followers >= "+add(filter.MinFollowers))
+		}
+		if filter.LinkedOnly {
+			where = append(where, "a.object_id IS NOT NULL")
+		}
+		if col, ok := authorSortColumns[filter.Sort]; ok {
+			dir := " DESC"
+			if filter.Order == "asc" {
+				dir = " ASC"
+			}
+			orderBy = col + dir + ", a.id"
+		}
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM authors a`+whereSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count authors: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, authorSelect+whereSQL+`
+		ORDER BY `+orderBy+`
+		OFFSET `+add(offset)+` LIMIT `+add(limit), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []*entity.AuthorProfile
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, total, rows.Err()
+}
+
+// FollowerHistory 追蹤數每日快照（由舊到新）
+func (r *AuthorRepo) FollowerHistory(ctx context.Context, authorID int64, days int) ([]*entity.FollowerSnapshot, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT captured_on, followers
+		FROM author_follower_history
+		WHERE author_id = $1 AND captured_on >= CURRENT_DATE - $2::int
+		ORDER BY captured_on`, authorID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follower history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*entity.FollowerSnapshot{}
+	for rows.Next() {
+		s := &entity.FollowerSnapshot{}
+		if err := rows.Scan(&s.Date, &s.Followers); err != nil {
+			return nil, fmt.Errorf("failed to scan follower snapshot: %w", err)
+		}
+		history = append(history, s)
+	}
+	return history, rows.Err()
+}
+
+// TopEntities 作者最常提及的 Entity（不含作者本人連結的 person Entity）
+func (r *AuthorRepo) TopEntities(ctx context.Context, authorID int64, limit int) ([]*entity.AuthorAffinity, error) {
+	return r.queryAffinity(ctx, `
+		WITH ap AS (
+			SELECT p.post_id FROM authors a JOIN posts p ON `+authorPostJoin+` WHERE a.id = $1
+		)
+		SELECT o.id::text, o.canonical_name, ot.name,
+		       COUNT(DISTINCT pem.post_id),
+		       COUNT(DISTINCT pem.post_id)::float8 / NULLIF((SELECT COUNT(*) FROM ap), 0),
+		       AVG(pem.sentiment_score)::float8
+		FROM ap
+		JOIN post_entity_mentions pem ON pem.post_id = ap.post_id
+		JOIN objects o ON o.id = pem.object_id AND o.status = 'active'
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE o.id IS DISTINCT FROM (SELECT object_id FROM authors WHERE id = $1)
+		GROUP BY o.id, o.canonical_name, ot.name
+		ORDER BY COUNT(DISTINCT pem.post_id) DESC, o.canonical_name
+		LIMIT $2`, authorID, limit)
+}
+
+// TopTopics 作者貼文最常歸屬的主題
+func (r *AuthorRepo) TopTopics(ctx context.Context, authorID int64, limit int) ([]*entity.AuthorAffinity, error) {
+	return r.queryAffinity(ctx, `
+		WITH ap AS (
+			SELECT p.post_id, p.sentiment_score FROM authors a JOIN posts p ON `+authorPostJoin+` WHERE a.id = $1
+		)
+		SELECT t.id::text, t.name, 'topic',
+		       COUNT(DISTINCT ap.post_id),
+		       COUNT(DISTINCT ap.post_id)::float8 / NULLIF((SELECT COUNT(*) FROM ap), 0),
+		       AVG(ap.sentiment_score)::float8
+		FROM ap
+		JOIN post_topics pt ON pt.post_id::text = ap.post_id
+		JOIN topics t ON t.id = pt.topic_id
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(DISTINCT ap.post_id) DESC, t.name
+		LIMIT $2`, authorID, limit)
+}
+
+func (r *AuthorRepo) queryAffinity(ctx context.Context, query string, authorID int64, limit int) ([]*entity.AuthorAffinity, error) {
+	rows, err := r.db.Pool.Query(ctx, query, authorID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query author affinity: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*entity.AuthorAffinity{}
+	for rows.Next() {
+		a := &entity.AuthorAffinity{}
+		var share *float64
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Posts, &share, &a.AvgSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan author affinity: %w", err)
+		}
+		if share != nil {
+			a.Share = *share
+		}
+		items = append(items, a)
+	}
+	return items, rows.Err()
+}
+
+var kolSortColumns = map[string]string{
+	"posts":         "k.posts DESC, k.engagement DESC",
+	"engagement":    "k.engagement DESC, k.posts DESC",
+	"reach":         "k.reach DESC, k.posts DESC",
+	"followers":     "a.followers DESC, k.posts DESC",
+	"avg_sentiment": "k.avg_sentiment DESC NULLS LAST, k.posts DESC",
+}
+
+// RankKOLs 依 Entity（post_entity_mentions）或主題（post_topics）排名作者
+func (r *AuthorRepo) RankKOLs(ctx context.Context, filter *entity.KOLRankFilter, limit int) ([]*entity.KOLRank, error) {
+	var where []string
+	var args []any
+	add := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var source string
+	switch {
+	case filter.ObjectID != "":
+		source = `
+			SELECT pem.post_id, pem.sentiment_score FROM post_entity_mentions pem
+			WHERE pem.object_id = ` + add(filter.ObjectID)
+	case filter.TopicID > 0:
+		source = `
+			SELECT DISTINCT ON (pt.post_id) pt.post_id::text AS post_id, p0.sentiment_score
+			FROM post_topics pt
+			JOIN posts p0 ON p0.post_id = pt.post_id::text
+			WHERE pt.topic_id = ` + add(filter.TopicID)
+	default:
+		return nil, fmt.Errorf("object_id or topic_id is required")
+	}
+
+	if filter.Platform != "" {
+		where = append(where, "p.platform = "+add(filter.Platform))
+	}
+	if filter.Since != nil {
+		where = append(where, "p.created_at >= "+add(*filter.Since))
+	}
+	authorWhere := ""
+	if filter.MinFollowers > 0 {
+		authorWhere = " WHERE a.followers >= " + add(filter.MinFollowers)
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
+	}
+	orderBy, ok := kolSortColumns[filter.Sort]
+	if !ok {
+		orderBy = kolSortColumns["posts"]
+	}
+
+	query := `
+		WITH src AS (` + source + `
+		),
+		m AS (
+			SELECT a.id AS author_id, p.post_id,
+			       COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0) AS engagement,
+			       COALESCE(NULLIF(p.author_followers, 0), a.followers) AS followers,
+			       src.sentiment_score
+			FROM src
+			JOIN posts p ON p.post_id = src.post_id
+			JOIN authors a ON ` + authorPostJoin + whereSQL + `
+		),
+		k AS (
+			SELECT author_id, COUNT(DISTINCT post_id) AS posts, SUM(engagement) AS engagement,
+			       SUM(followers) AS reach, AVG(sentiment_score)::float8 AS avg_sentiment
+			FROM m GROUP BY author_id
+		),
+		total AS (SELECT COUNT(DISTINCT post_id) AS n FROM m)
+		SELECT a.id, a.platform, a.platform_user_id, a.username, a.followers,
+		       a.object_id::text, COALESCE(o.canonical_name, ''), a.first_seen_at, a.last_seen_at,
+		       k.posts, k.engagement, k.reach, k.avg_sentiment,
+		       CASE WHEN total.n > 0 THEN k.posts::float8 / total.n * 100 ELSE 0 END
+		FROM k
+		JOIN authors a ON a.id = k.author_id
+		LEFT JOIN objects o ON o.id = a.object_id
+		CROSS JOIN total` + authorWhere + `
+		ORDER BY ` + orderBy + `
+		LIMIT ` + add(limit)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank KOLs: %w", err)
+	}
+	defer rows.Close()
+
+	ranks := []*entity.KOLRank{}
+	for rows.Next() {
+		a := &entity.AuthorProfile{}
+		k := &entity.KOLRank{Author: a}
+		var platform string
+		if err := rows.Scan(&a.ID, &platform, &a.PlatformUserID, &a.Username, &a.Followers,
+			&a.ObjectID, &a.ObjectName, &a.FirstSeenAt, &a.LastSeenAt,
+			&k.Posts, &k.Engagement, &k.Reach, &k.AvgSentiment, &k.ContributionPct); err != nil {
+			return nil, fmt.Errorf("failed to scan KOL rank: %w", err)
+		}
+		a.Platform = entity.Platform(platform)
+		ranks = append(ranks, k)
+	}
+	return ranks, rows.Err()
+}
+
+func scanAuthor(row pgx.Row) (*entity.AuthorProfile, error) {
+	a := &entity.AuthorProfile{Stats: &entity.AuthorStats{}}
+	var platform string
+	err := row.Scan(&a.ID, &platform, &a.PlatformUserID, &a.Username, &a.Followers,
+		&a.ObjectID, &a.ObjectName, &a.FirstSeenAt, &a.LastSeenAt,
+		&a.Stats.PostCount, &a.Stats.Posts28d, &a.Stats.PostsPerWeek,
+		&a.Stats.AvgSentiment, &a.Stats.AvgEngagement, &a.Stats.EngagementRate, &a.Stats.LastPostAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan author: %w", err)
+	}
+	a.Platform = entity.Platform(platform)
+	return a, nil
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// BenchmarkRepo 競品比較 PostgreSQL 實作
+type BenchmarkRepo struct {
+	db *DB
+}
+
+// NewBenchmarkRepo 建立 BenchmarkRepo
+func NewBenchmarkRepo(db *DB) repository.BenchmarkRepository {
+	return &BenchmarkRepo{db: db}
+}
+
+// ExplicitCompetitors 以 competes_with / competes_with_product 關係連結的競品（關係為對稱，兩個方向都算）
+func (r *BenchmarkRepo) ExplicitCompetitors(ctx context.Context, objectID string) ([]*entity.Competitor, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT DISTINCT ON (o.id) o.id, o.canonical_name, ot.name, r.source
+		FROM object_relations r
+		JOIN ontology_relation_types rt ON rt.id = r.relation_type_id
+		JOIN objects o ON o.id = CASE WHEN r.source_id = $1 THEN r.target_id ELSE r.source_id END
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE (r.source_id = $1 OR r.target_id = $1)
+		  AND rt.slug = ANY($2)
+		  AND o.status = 'active'
+		ORDER BY o.id, r.confidence DESC`, objectID, entity.CompetitorRelationSlugs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list explicit competitors: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.Competitor
+	for rows.Next() {
+		c := &entity.Competitor{Source: entity.CompetitorSourceRelation}
+		if err := rows.Scan(&c.ObjectID, &c.Name, &c.Type, &c.RelationSource); err != nil {
+			return nil, fmt.Errorf("failed to scan competitor: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// CoMentionedPeers 同類（相同 object type）Entity 依同篇提及的貼文數排序，重複貼文不計
+func (r *BenchmarkRepo) CoMentionedPeers(ctx context.Context, objectID string, filter *entity.CompetitorFilter) ([]*entity.Competitor, error) {
+	var since time.Time
+	if filter.Since != nil {
+		since = *filter.Since
+	}
+	minCo := filter.MinCoMentions
+	if minCo <= 0 {
+		minCo = 1
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT o.id, o.canonical_name, ot.name, COUNT(DISTINCT m2.post_id) AS co_mentions
+		FROM post_entity_mentions m1
+		JOIN objects f ON f.id = m1.object_id
+		JOIN post_entity_mentions m2 ON m2.post_id = m1.post_id AND m2.object_id <> m1.object_id
+		JOIN objects o ON o.id = m2.object_id AND o.type_id = f.type_id AND o.status = 'active'
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE m1.object_id = $1
+		  AND m1.created_at >= $2
+		  AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = m1.post_id)
+		GROUP BY o.id, o.canonical_name, ot.name
+		HAVING COUNT(DISTINCT m2.post_id) >= $3
+		ORDER BY co_mentions DESC, o.canonical_name
+		LIMIT $4`, objectID, since, minCo, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list co-mentioned peers: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.Competitor
+	for rows.Next() {
+		c := &entity.Competitor{Source: entity.CompetitorSourceCoMention}
+		if err := rows.Scan(&c.ObjectID, &c.Name, &c.Type, &c.CoMentions); err != nil {
+			return nil, fmt.Errorf("failed to scan peer: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// AddCompetitor 建立人工 competes_with 關係
+// 關係類型依兩端的 ontology class 決定（品牌 ↔ 品牌、產品 ↔ 產品），沒有對應類型時回傳 false
+func (r *BenchmarkRepo) AddCompetitor(ctx context.Context, objectID, competitorID string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO object_relations (source_id, target_id, relation_type_id, confidence, source)
+		SELECT s.id, t.id, rt.id, 1.0, 'manual'
+		FROM objects s
+		JOIN objects t ON t.id = $2
+		JOIN ontology_relation_types rt
+		  ON rt.slug = ANY($3) AND rt.source_class_id = s.class_id AND rt.target_class_id = t.class_id
+		WHERE s.id = $1
+		LIMIT 1
+		ON CONFLICT (source_id, target_id, relation_type_id)
+		DO UPDATE SET confidence = EXCLUDED.confidence, source = EXCLUDED.source`,
+		objectID, competitorID, entity.CompetitorRelationSlugs)
+	if err != nil {
+		return false, fmt.Errorf("failed to add competitor: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoveCompetitor 刪除兩個 Entity 之間的 competes_with 關係（不分方向）
+func (r *BenchmarkRepo) RemoveCompetitor(ctx context.Context, objectID, competitorID string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM object_relations r
+		USING ontology_relation_types rt
+		WHERE rt.id = r.relation_type_id
+		  AND rt.slug = ANY($3)
+		  AND ((r.source_id = $1 AND r.target_id = $2) OR (r.source_id = $2 AND r.target_id = $1))`,
+		objectID, competitorID, entity.CompetitorRelationSlugs)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove competitor: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// benchmarkWindow 最近 N 期的 entity_observations 條件（eo 為別名，$2 = period_type，$3 = 天數）
+// 以該 period_type 最新一期為終點，不以 NOW() 為準，避免觀測尚未產生時整段落空
+const benchmarkWindow = `eo.period_type = $2
+	AND eo.period_start > (SELECT MAX(period_start) FROM entity_observations WHERE period_type = $2) - make_interval(days => $3)`
+
+// periodDays 單期天數
+func periodDays(periodType string) int {
+	if periodType == "day" {
+		return 1
+	}
+	return 7
+}
+
+// Totals 比較集合在最近 N 期的觀測彙總
+// 平均情感以各期提及數加權（等同逐則提及平均）
+func (r *BenchmarkRepo) Totals(ctx context.Context, objectIDs []string, periodType string, periods int) ([]*entity.BenchmarkTotals, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT o.id, o.canonical_name, ot.name,
+		       COALESCE(SUM(eo.mention_count), 0)::int,
+		       (SUM(eo.avg_sentiment * eo.mention_count)
+		           / NULLIF(SUM(eo.mention_count) FILTER (WHERE eo.avg_sentiment IS NOT NULL), 0))::float8,
+		       (SUM(eo.weighted_sentiment * eo.mention_count)
+		           / NULLIF(SUM(eo.mention_count) FILTER (WHERE eo.weighted_sentiment IS NOT NULL), 0))::float8,
+		       COALESCE(SUM(eo.total_engagement), 0)::bigint,
+		       COALESCE(SUM(eo.estimated_reach), 0)::bigint
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		LEFT JOIN entity_observations eo ON eo.object_id = o.id AND `+benchmarkWindow+`
+		WHERE o.id = ANY($1)
+		GROUP BY o.id, o.canonical_name, ot.name`,
+		objectIDs, periodType, periods*periodDays(periodType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load benchmark totals: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.BenchmarkTotals
+	for rows.Next() {
+		var t entity.BenchmarkTotals
+		if err := rows.Scan(&t.ObjectID, &t.Name, &t.Type, &t.MentionCount,
+			&t.AvgSentiment, &t.WeightedSentiment, &t.TotalEngagement, &t.EstimatedReach); err != nil {
+			return nil, fmt.Errorf("failed to scan benchmark totals: %w", err)
+		}
+		out = append(out, &t)
+	}
+	return out, rows.Err()
+}
+
+// Trend 比較集合在最近 N 期的逐期觀測（由舊到新）
+func (r *BenchmarkRepo) Trend(ctx context.Context, objectIDs []string, periodType string, periods int) ([]*entity.BenchmarkPeriod, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT eo.period_start, eo.object_id, eo.mention_count,
+		       eo.avg_sentiment::float8, eo.weighted_sentiment::float8
+		FROM entity_observations eo
+		WHERE eo.object_id = ANY($1) AND `+benchmarkWindow+`
+		ORDER BY eo.period_start, eo.object_id`,
+		objectIDs, periodType, periods*periodDays(periodType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load benchmark trend: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.BenchmarkPeriod
+	for rows.Next() {
+		var start time.Time
+		p := &entity.BenchmarkTrendPoint{}
+		if err := rows.Scan(&start, &p.ObjectID, &p.MentionCount, &p.AvgSentiment, &p.WeightedSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan benchmark trend: %w", err)
+		}
+		if n := len(out); n == 0 || !out[n-1].PeriodStart.Equal(start) {
+			out = append(out, &entity.BenchmarkPeriod{PeriodStart: start})
+		}
+		last := out[len(out)-1]
+		last.Points = append(last.Points, p)
+	}
+	return out, rows.Err()
+}
+
+// AspectStats 比較集合的面向統計（entity_aspect_stats）
+func (r *BenchmarkRepo) AspectStats(ctx context.Context, objectIDs []string, minCount int) ([]*entity.AspectStat, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT object_id, aspect, total::int,
+		       COALESCE(avg_sentiment_score, 0)::float8, COALESCE(positive_ratio, 0)::float8
+		FROM entity_aspect_stats
+		WHERE object_id = ANY($1) AND total >= $2
+		ORDER BY aspect, object_id`, objectIDs, minCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aspect stats: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*entity.AspectStat
+	for rows.Next() {
+		var a entity.AspectStat
+		if err := rows.Scan(&a.ObjectID, &a.Aspect, &a.Count, &a.AvgSentiment, &a.PositiveRatio); err != nil {
+			return nil, fmt.Errorf("failed to scan aspect stat: %w", err)
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ChatSessionRepo PostgreSQL 實作的 ChatSessionRepository
+type ChatSessionRepo struct {
+	db *DB
+}
+
+// NewChatSessionRepo 建立 ChatSessionRepository
+func NewChatSessionRepo(db *DB) repository.ChatSessionRepository {
+	return &ChatSessionRepo{db: db}
+}
+
+const chatSessionColumns = `
+	cs.id, cs.user_id, cs.object_id, COALESCE(o.canonical_name, ''), cs.title, cs.period,
+	cs.summary, cs.summarized_until, cs.message_count,
+	cs.input_tokens, cs.output_tokens, cs.cost_usd::float8,
+	cs.created_at, cs.updated_at`
+
+// Create 建立 session
+func (r *ChatSessionRepo) Create(ctx context.Context, session *entity.ChatSession) error {
+	var id any
+	if session.ID != "" {
+		id = session.ID
+	}
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO chat_sessions (id, user_id, object_id, title, period)
+		VALUES (COALESCE($1::uuid, gen_random_uuid()), $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		id, session.UserID, session.ObjectID, session.Title, session.Period,
+	).Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create chat session: %w", err)
+	}
+	return nil
+}
+
+// FindByID 查詢 session
+func (r *ChatSessionRepo) FindByID(ctx context.Context, id string) (*entity.ChatSession, error) {
+	query := `SELECT ` + chatSessionColumns + `
+		FROM chat_sessions cs
+		LEFT JOIN objects o ON o.id = cs.object_id
+		WHERE cs.id = $1`
+
+	session, err := scanChatSession(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat session: %w", err)
+	}
+	return session, nil
+}
+
+// List 列出使用者的 session
+func (r *ChatSessionRepo) List(ctx context.Context, filter entity.ChatSessionFilter) ([]*entity.ChatSession, int, error) {
+	where := []string{"cs.user_id = $1"}
+	args := []any{filter.UserID}
+	if filter.ObjectID != "" {
+		where = append(where, "cs.object_id = $2")
+		args = append(args, filter.ObjectID)
+	}
+	whereSQL := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM chat_sessions cs`+whereSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count chat sessions: %w", err)
+	}
+
+	argIdx := len(args) + 1
+	query := `SELECT ` + chatSessionColumns + `
+		FROM chat_sessions cs
+		LEFT JOIN objects o ON o.id = cs.object_id` + whereSQL + `
+		ORDER BY cs.updated_at DESC, cs.id
+		LIMIT $` + strconv.Itoa(argIdx) + ` OFFSET $` + strconv.Itoa(argIdx+1)
+
+	rows, err := r.db.Pool.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query chat sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*entity.ChatSession
+	for rows.Next() {
+		session, err := scanChatSession(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan chat session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, total, rows.Err()
+}
+
+// Rename 修改 session 標題
+func (r *ChatSessionRepo) Rename(ctx context.Context, id, title string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE chat_sessions SET title = $2, updated_at = NOW() WHERE id = $1`, id, title)
+	if err != nil {
+		return fmt.Errorf("failed to rename chat session: %w", err)
+	}
+	return nil
+}
+
+// Delete 刪除 session（訊息由 ON DELETE CASCADE 一併刪除）
+func (r *ChatSessionRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM chat_sessions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete chat session: %w", err)
+	}
+	return nil
+}
+
+// AppendMessages 新增訊息並累計 session 統計（同一交易）
+func (r *ChatSessionRepo) AppendMessages(ctx context.Context, sessionID string, messages []*entity.ChatSessionMessage) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var usage entity.TokenUsage
+	for _, m := range messages {
+		citations := m.Citations
+		if citations == nil {
+			citations = []entity.Citation{}
+		}
+		citationsJSON, err := json.Marshal(citations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal citations: %w", err)
+		}
+		err = tx.QueryRow(ctx, `
+			INSERT INTO chat_messages (session_id, role, content, citations, input_tokens, output_tokens, cost_usd)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at`,
+			sessionID, m.Role, m.Content, citationsJSON,
+			m.Usage.InputTokens, m.Usage.OutputTokens, m.Usage.CostUSD,
+		).Scan(&m.ID, &m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert chat message: %w", err)
+		}
+		m.SessionID = sessionID
+		usage.InputTokens += m.Usage.InputTokens
+		usage.OutputTokens += m.Usage.OutputTokens
+		usage.CostUSD += m.Usage.CostUSD
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE chat_sessions SET
+			message_count = message_count + $2,
+			input_tokens = input_tokens + $3,
+			output_tokens = output_tokens + $4,
+			cost_usd = cost_usd + $5,
+			updated_at = NOW()
+		WHERE id = $1`,
+		sessionID, len(messages), usage.InputTokens, usage.OutputTokens, usage.CostUSD)
+	if err != nil {
+		return fmt.Errorf("failed to update chat session: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ListMessages 列出 ID 大於 afterID 的訊息
+func (r *ChatSessionRepo) ListMessages(ctx context.Context, sessionID string, afterID int64) ([]*entity.ChatSessionMessage, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, session_id, role, content, citations,
+		       input_tokens, output_tokens, cost_usd::float8, created_at
+		FROM chat_messages
+		WHERE session_id = $1 AND id > $2
+		ORDER BY id`, sessionID, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*entity.ChatSessionMessage
+	for rows.Next() {
+		m := &entity.ChatSessionMessage{}
+		var citationsJSON []byte
+		if err := rows.Scan(&m.ID, &m.SessionID, &m.Role, &m.Content, &citationsJSON,
+			&m.Usage.InputTokens, &m.Usage.OutputTokens, &m.Usage.CostUSD, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		if err := json.Unmarshal(citationsJSON, &m.Citations); err != nil {
+			m.Citations = []entity.Citation{}
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// SaveSummary 更新較舊對話的摘要
+func (r *ChatSessionRepo) SaveSummary(ctx context.Context, sessionID, summary string, summarizedUntil int64, usage entity.TokenUsage) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE chat_sessions SET
+			summary = $2,
+			summarized_until = $3,
+			input_tokens = input_tokens + $4,
+			output_tokens = output_tokens + $5,
+			cost_usd = cost_usd + $6
+		WHERE id = $1`,
+		sessionID, summary, summarizedUntil, usage.InputTokens, usage.OutputTokens, usage.CostUSD)
+	if err != nil {
+		return fmt.Errorf("failed to save chat summary: %w", err)
+	}
+	return nil
+}
+
+func scanChatSession(row pgx.Row) (*entity.ChatSession, error) {
+	s := &entity.ChatSession{}
+	err := row.Scan(&s.ID, &s.UserID, &s.ObjectID, &s.ObjectName, &s.Title, &s.Period,
+		&s.Summary, &s.SummarizedUntil, &s.MessageCount,
+		&s.Usage.InputTokens, &s.Usage.OutputTokens, &s.Usage.CostUSD,
+		&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
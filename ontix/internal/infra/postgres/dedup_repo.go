@@ -0,0 +1,351 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxGroupDuplicates GetGroup 最多回傳的重複貼文數
+const maxGroupDuplicates = 1000
+
+// DedupRepo PostgreSQL 實作的 DedupRepository
+type DedupRepo struct {
+	db *DB
+}
+
+// NewDedupRepo 建立 DedupRepository
+func NewDedupRepo(db *DB) repository.DedupRepository {
+	return &DedupRepo{db: db}
+}
+
+// FindByContentHash 查詢內容雜湊相同的原始貼文
+func (r *DedupRepo) FindByContentHash(ctx context.Context, hash string) (*entity.PostFingerprint, error) {
+	row := r.db.Pool.QueryRow(ctx, `
+		SELECT post_id, platform, content_hash, signature, created_at
+		FROM post_fingerprints
+		WHERE content_hash = $1
+		ORDER BY created_at
+		LIMIT 1`, hash)
+	fp, err := scanFingerprint(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return fp, err
+}
+
+// FindCandidates 依 LSH 分桶找出近似重複候選（命中 band 數多者優先）
+func (r *DedupRepo) FindCandidates(ctx context.Context, bands []int64, limit int) ([]*entity.PostFingerprint, error) {
+	bandNos := make([]int16, len(bands))
+	for i := range bands {
+		bandNos[i] = int16(i)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH hits AS (
+			SELECT b.post_id, COUNT(*) AS hit
+			FROM post_minhash_bands b
+			JOIN unnest($1::smallint[], $2::bigint[]) AS q(band, band_hash)
+			  ON b.band = q.band AND b.band_hash = q.band_hash
+			GROUP BY b.post_id
+			ORDER BY hit DESC
+			LIMIT $3
+		)
+		SELECT f.post_id, f.platform, f.content_hash, f.signature, f.created_at
+		FROM hits h
+		JOIN post_fingerprints f ON f.post_id = h.post_id
+		ORDER BY h.hit DESC, f.created_at`, bandNos, bands, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dedup candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var fps []*entity.PostFingerprint
+	for rows.Next() {
+		fp, err := scanFingerprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		fps = append(fps, fp)
+	}
+	return fps, rows.Err()
+}
+
+// SaveFingerprint 儲存原始貼文的指紋與分桶
+func (r *DedupRepo) SaveFingerprint(ctx context.Context, fp *entity.PostFingerprint, bands []int64) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := saveFingerprintTx(ctx, tx, fp, bands); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// saveFingerprintTx 在交易內寫入指紋與分桶（已有指紋時不覆蓋；CreatedAt 為零值時使用目前時間）
+func saveFingerprintTx(ctx context.Context, tx pgx.Tx, fp *entity.PostFingerprint, bands []int64) error {
+	sig := make([]int64, len(fp.Signature))
+	for i, v := range fp.Signature {
+		sig[i] = int64(v)
+	}
+	bandNos := make([]int16, len(bands))
+	for i := range bands {
+		bandNos[i] = int16(i)
+	}
+	createdAt := fp.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO post_fingerprints (post_id, platform, content_hash, signature, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (post_id) DO NOTHING`,
+		fp.PostID, string(fp.Platform), fp.ContentHash, sig, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO post_minhash_bands (band, band_hash, post_id)
+		SELECT q.band, q.band_hash, $3
+		FROM unnest($1::smallint[], $2::bigint[]) AS q(band, band_hash)
+		ON CONFLICT DO NOTHING`, bandNos, bands, fp.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to save minhash bands: %w", err)
+	}
+	return nil
+}
+
+// SaveDuplicate 記錄重複貼文
+func (r *DedupRepo) SaveDuplicate(ctx context.Context, dup *entity.PostDuplicate) error {
+	return saveDuplicate(ctx, r.db.Pool, dup)
+}
+
+func saveDuplicate(ctx context.Context, q execer, dup *entity.PostDuplicate) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO post_duplicates
+			(post_id, platform, author_username, content, original_post_id, similarity, method, policy, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (post_id) DO UPDATE SET
+			original_post_id = EXCLUDED.original_post_id,
+			similarity = EXCLUDED.similarity,
+			method = EXCLUDED.method,
+			policy = EXCLUDED.policy,
+			detected_at = EXCLUDED.detected_at`,
+		dup.PostID, string(dup.Platform), dup.AuthorUsername, dup.Content, dup.OriginalPostID,
+		dup.Similarity, string(dup.Method), string(dup.Policy), dup.DetectedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save duplicate: %w", err)
+	}
+	return nil
+}
+
+// ListGroups 列出重複群組
+func (r *DedupRepo) ListGroups(ctx context.Context, filter *entity.DuplicateFilter, perGroup, offset, limit int) ([]*entity.DuplicateGroup, int, error) {
+	var where []string
+	var args []any
+	add := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	minSize := 1
+	if filter != nil {
+		if filter.Platform != "" {
+			where = append(where, "d.platform = "+add(filter.Platform))
+		}
+		if filter.Method != "" {
+			where = append(where, "d.method = "+add(string(filter.Method)))
+		}
+		if filter.Since != nil {
+			where = append(where, "d.detected_at >= "+add(*filter.Since))
+		}
+		if filter.MinSize > 1 {
+			minSize = filter.MinSize
+		}
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = " WHERE " + strings.Join(where, " AND ")
+	}
+	groupsCTE := `
+		WITH groups AS (
+			SELECT d.original_post_id,
+			       COUNT(*) AS dup_count,
+			       COUNT(DISTINCT d.author_username) AS author_count,
+			       MAX(d.detected_at) AS last_detected
+			FROM post_duplicates d` + whereSQL + `
+			GROUP BY d.original_post_id
+			HAVING COUNT(*) >= ` + add(minSize) + `
+		)`
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, groupsCTE+` SELECT COUNT(*) FROM groups`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count duplicate groups: %w", err)
+	}
+
+	query := groupsCTE + groupSelect + `
+		ORDER BY g.dup_count DESC, g.last_detected DESC
+		OFFSET ` + add(offset) + ` LIMIT ` + add(limit)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list duplicate groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*entity.DuplicateGroup
+	for rows.Next() {
+		g, err := scanDuplicateGroup(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.attachDuplicates(ctx, groups, perGroup); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+// GetGroup 取得貼文所屬的重複群組
+func (r *DedupRepo) GetGroup(ctx context.Context, postID string) (*entity.DuplicateGroup, error) {
+	originalID := postID
+	err := r.db.Pool.QueryRow(ctx, `SELECT original_post_id FROM post_duplicates WHERE post_id = $1`, postID).Scan(&originalID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to resolve duplicate group: %w", err)
+	}
+
+	query := `
+		WITH groups AS (
+			SELECT d.original_post_id,
+			       COUNT(*) AS dup_count,
+			       COUNT(DISTINCT d.author_username) AS author_count,
+			       MAX(d.detected_at) AS last_detected
+			FROM post_duplicates d
+			WHERE d.original_post_id = $1
+			GROUP BY d.original_post_id
+		)` + groupSelect
+	g, err := scanDuplicateGroup(r.db.Pool.QueryRow(ctx, query, originalID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachDuplicates(ctx, []*entity.DuplicateGroup{g}, maxGroupDuplicates); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// groupSelect 群組欄位（原始貼文可能已不存在，平台改取指紋）
+const groupSelect = `
+		SELECT g.original_post_id,
+		       COALESCE(p.platform, f.platform, ''),
+		       COALESCE(p.author_username, ''),
+		       COALESCE(p.content, ''),
+		       p.created_at,
+		       g.dup_count, g.author_count, g.last_detected
+		FROM groups g
+		LEFT JOIN posts p ON p.post_id = g.original_post_id
+		LEFT JOIN post_fingerprints f ON f.post_id = g.original_post_id`
+
+// attachDuplicates 為每個群組附上最近 perGroup 筆重複貼文
+func (r *DedupRepo) attachDuplicates(ctx context.Context, groups []*entity.DuplicateGroup, perGroup int) error {
+	if len(groups) == 0 || perGroup <= 0 {
+		for _, g := range groups {
+			g.Duplicates = []*entity.PostDuplicate{}
+		}
+		return nil
+	}
+
+	byID := make(map[string]*entity.DuplicateGroup, len(groups))
+	ids := make([]string, len(groups))
+	for i, g := range groups {
+		g.Duplicates = []*entity.PostDuplicate{}
+		byID[g.OriginalPostID] = g
+		ids[i] = g.OriginalPostID
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT post_id, platform, COALESCE(author_username, ''), content, original_post_id,
+		       similarity, method, policy, detected_at
+		FROM (
+			SELECT d.*, ROW_NUMBER() OVER (PARTITION BY d.original_post_id ORDER BY d.detected_at DESC) AS rn
+			FROM post_duplicates d
+			WHERE d.original_post_id = ANY($1)
+		) x
+		WHERE rn <= $2
+		ORDER BY detected_at DESC`, ids, perGroup)
+	if err != nil {
+		return fmt.Errorf("failed to query duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d entity.PostDuplicate
+		var platform, method, policy string
+		if err := rows.Scan(&d.PostID, &platform, &d.AuthorUsername, &d.Content, &d.OriginalPostID,
+			&d.Similarity, &method, &policy, &d.DetectedAt); err != nil {
+			return fmt.Errorf("failed to scan duplicate: %w", err)
+		}
+		d.Platform = entity.Platform(platform)
+		d.Method = entity.DedupMethod(method)
+		d.Policy = entity.DedupPolicy(policy)
+		if g := byID[d.OriginalPostID]; g != nil {
+			g.Duplicates = append(g.Duplicates, &d)
+		}
+	}
+	return rows.Err()
+}
+
+func scanDuplicateGroup(row pgx.Row) (*entity.DuplicateGroup, error) {
+	var g entity.DuplicateGroup
+	var platform string
+	var postedAt *time.Time
+	err := row.Scan(&g.OriginalPostID, &platform, &g.AuthorUsername, &g.Content, &postedAt,
+		&g.DuplicateCount, &g.AuthorCount, &g.LastDetectedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan duplicate group: %w", err)
+	}
+	g.Platform = entity.Platform(platform)
+	g.PostedAt = postedAt
+	return &g, nil
+}
+
+func scanFingerprint(row pgx.Row) (*entity.PostFingerprint, error) {
+	var fp entity.PostFingerprint
+	var platform string
+	var sig []int64
+	if err := row.Scan(&fp.PostID, &platform, &fp.ContentHash, &sig, &fp.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan fingerprint: %w", err)
+	}
+	fp.Platform = entity.Platform(platform)
+	fp.Signature = make([]uint32, len(sig))
+	for i, v := range sig {
+		fp.Signature[i] = uint32(v)
+	}
+	return &fp, nil
+}
@@ -0,0 +1,275 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// EntityEmbeddingRepo PostgreSQL 實作的 EntityEmbeddingRepository
+type EntityEmbeddingRepo struct {
+	db *DB
+}
+
+// NewEntityEmbeddingRepo 建立 EntityEmbeddingRepository
+func NewEntityEmbeddingRepo(db *DB) repository.EntityEmbeddingRepository {
+	return &EntityEmbeddingRepo{db: db}
+}
+
+// ListStaleProfiles 需要（重新）產生 embedding 的 Entity profile，提及數多的優先
+func (r *EntityEmbeddingRepo) ListStaleProfiles(ctx context.Context, limit int, force bool) ([]*entity.EntityProfile, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT o.id, ot.name, o.canonical_name, COALESCE(mc.cnt, 0)
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		LEFT JOIN (
+			SELECT object_id, COUNT(*) AS cnt FROM post_entity_mentions GROUP BY object_id
+		) mc ON mc.object_id = o.id
+		WHERE o.status = 'active'
+		  AND ($2 OR o.embedding IS NULL OR COALESCE(mc.cnt, 0) > o.embedding_mention_count * 1.2)
+		ORDER BY COALESCE(mc.cnt, 0) DESC, o.id
+		LIMIT $1`, limit, force)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale entity embeddings: %w", err)
+	}
+
+	var profiles []*entity.EntityProfile
+	for rows.Next() {
+		p := &entity.EntityProfile{}
+		if err := rows.Scan(&p.ObjectID, &p.Type, &p.CanonicalName, &p.MentionCount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan entity profile: %w", err)
+		}
+		profiles = append(profiles, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stale entities: %w", err)
+	}
+
+	for _, p := range profiles {
+		if err := r.fillProfile(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// LoadProfile 載入單一 Entity 的 profile（不存在回傳 nil）
+func (r *EntityEmbeddingRepo) LoadProfile(ctx context.Context, objectID string) (*entity.EntityProfile, error) {
+	p := &entity.EntityProfile{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT o.id, ot.name, o.canonical_name,
+			(SELECT COUNT(*) FROM post_entity_mentions m WHERE m.object_id = o.id)
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE o.id = $1`, objectID).Scan(&p.ObjectID, &p.Type, &p.CanonicalName, &p.MentionCount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load entity profile: %w", err)
+	}
+	if err := r.fillProfile(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fillProfile 補上別名、aspect 分佈、共同提及、提及片段與貼文 embedding 平均
+func (r *EntityEmbeddingRepo) fillProfile(ctx context.Context, p *entity.EntityProfile) error {
+	var err error
+
+	p.Aliases, err = r.queryStrings(ctx, `
+		SELECT alias FROM object_aliases
+		WHERE object_id = $1 AND alias <> $2
+		ORDER BY confidence DESC NULLS LAST, alias
+		LIMIT 10`, p.ObjectID, p.CanonicalName)
+	if err != nil {
+		return fmt.Errorf("failed to load aliases: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT aspect,
+			COUNT(*) FILTER (WHERE sentiment = 'positive'),
+			COUNT(*) FILTER (WHERE sentiment = 'negative'),
+			COUNT(*) FILTER (WHERE sentiment NOT IN ('positive', 'negative'))
+		FROM entity_aspects
+		WHERE object_id = $1
+		GROUP BY aspect
+		ORDER BY COUNT(*) DESC, aspect
+		LIMIT 15`, p.ObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to load aspect profile: %w", err)
+	}
+	p.Aspects = nil
+	for rows.Next() {
+		var a entity.AspectProfile
+		if err := rows.Scan(&a.Aspect, &a.Positive, &a.Negative, &a.Neutral); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan aspect profile: %w", err)
+		}
+		p.Aspects = append(p.Aspects, a)
+	}
+	rows.Close()
+
+	p.CoMentions, err = r.queryStrings(ctx, `
+		SELECT o2.canonical_name
+		FROM post_entity_mentions m1
+		JOIN post_entity_mentions m2 ON m2.post_id = m1.post_id AND m2.object_id <> m1.object_id
+		JOIN objects o2 ON o2.id = m2.object_id
+		WHERE m1.object_id = $1 AND o2.status = 'active'
+		GROUP BY o2.canonical_name
+		ORDER BY COUNT(*) DESC, o2.canonical_name
+		LIMIT 8`, p.ObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to load co-mentions: %w", err)
+	}
+
+	p.MentionTexts, err = r.queryStrings(ctx, `
+		SELECT LEFT(mention_text, 200)
+		FROM post_entity_mentions
+		WHERE object_id = $1 AND COALESCE(mention_text, '') <> ''
+		ORDER BY created_at DESC
+		LIMIT 10`, p.ObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to load mention texts: %w", err)
+	}
+
+	var centroid *pgvector.Vector
+	err = r.db.Pool.QueryRow(ctx, `
+		SELECT AVG(pe.embedding)
+		FROM post_embeddings pe
+		WHERE pe.post_id IN (
+			SELECT post_id FROM post_entity_mentions
+			WHERE object_id = $1
+			ORDER BY created_at DESC
+			LIMIT 200
+		)`, p.ObjectID).Scan(&centroid)
+	if err != nil {
+		return fmt.Errorf("failed to load mention centroid: %w", err)
+	}
+	if centroid != nil {
+		p.PostCentroid = centroid.Slice()
+	}
+	return nil
+}
+
+func (r *EntityEmbeddingRepo) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// SaveEmbedding 儲存 Entity embedding
+func (r *EntityEmbeddingRepo) SaveEmbedding(ctx context.Context, objectID string, embedding []float32, mentionCount int) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE objects
+		SET embedding = $2, embedding_mention_count = $3, embedding_updated_at = NOW()
+		WHERE id = $1`, objectID, pgvector.NewVector(embedding), mentionCount)
+	if err != nil {
+		return fmt.Errorf("failed to save entity embedding: %w", err)
+	}
+	return nil
+}
+
+// HasEmbedding Entity 是否已有 embedding
+func (r *EntityEmbeddingRepo) HasEmbedding(ctx context.Context, objectID string) (bool, error) {
+	var has bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT embedding IS NOT NULL FROM objects WHERE id = $1`, objectID).Scan(&has)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check entity embedding: %w", err)
+	}
+	return has, nil
+}
+
+// SearchByEmbedding 以查詢向量搜尋 active Entity
+func (r *EntityEmbeddingRepo) SearchByEmbedding(ctx context.Context, embedding []float32, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT o.id, ot.name, o.canonical_name,
+			(SELECT COUNT(*) FROM post_entity_mentions m WHERE m.object_id = o.id),
+			(1 - (o.embedding <=> $1::vector))::float8
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE o.status = 'active' AND o.embedding IS NOT NULL
+		  AND ($3 = '' OR ot.name = $3)
+		ORDER BY o.embedding <=> $1::vector
+		LIMIT $2`, pgvector.NewVector(embedding), limit, string(objectType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search entities: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*entity.EntityMatch
+	for rows.Next() {
+		m := &entity.EntityMatch{}
+		if err := rows.Scan(&m.ID, &m.Type, &m.CanonicalName, &m.MentionCount, &m.Similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan entity match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// FindSimilar 與指定 Entity embedding 最接近的 active Entity
+func (r *EntityEmbeddingRepo) FindSimilar(ctx context.Context, objectID string, objectType entity.ObjectType, limit int) ([]*entity.EntityMatch, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH src AS (
+			SELECT embedding FROM objects WHERE id = $1 AND embedding IS NOT NULL
+		)
+		SELECT o.id, ot.name, o.canonical_name,
+			(SELECT COUNT(*) FROM post_entity_mentions m WHERE m.object_id = o.id),
+			(1 - (o.embedding <=> src.embedding))::float8,
+			ARRAY(
+				SELECT a.aspect::text FROM entity_aspects a WHERE a.object_id = o.id
+				INTERSECT
+				SELECT b.aspect::text FROM entity_aspects b WHERE b.object_id = $1
+				LIMIT 5
+			),
+			(SELECT COUNT(DISTINCT m1.post_id)
+			 FROM post_entity_mentions m1
+			 JOIN post_entity_mentions m2 ON m2.post_id = m1.post_id
+			 WHERE m1.object_id = o.id AND m2.object_id = $1)
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		CROSS JOIN src
+		WHERE o.status = 'active' AND o.embedding IS NOT NULL AND o.id <> $1
+		  AND ($3 = '' OR ot.name = $3)
+		ORDER BY o.embedding <=> src.embedding
+		LIMIT $2`, objectID, limit, string(objectType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar entities: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*entity.EntityMatch
+	for rows.Next() {
+		m := &entity.EntityMatch{}
+		if err := rows.Scan(&m.ID, &m.Type, &m.CanonicalName, &m.MentionCount, &m.Similarity,
+			&m.SharedAspects, &m.CoMentions); err != nil {
+			return nil, fmt.Errorf("failed to scan similar entity: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
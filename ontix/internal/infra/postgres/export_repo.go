@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// ExportRepo PostgreSQL 實作的 ExportRepository
+type ExportRepo struct {
+	db *DB
+}
+
+// NewExportRepo 建立 ExportRepository
+func NewExportRepo(db *DB) repository.ExportRepository {
+	return &ExportRepo{db: db}
+}
+
+// Stream 逐列讀出資料集
+func (r *ExportRepo) Stream(ctx context.Context, dataset entity.ExportDataset, filter *entity.ExportFilter, fn func(row []any) error) error {
+	columns := entity.ExportColumns(dataset)
+	if columns == nil {
+		return fmt.Errorf("unknown export dataset %q", dataset)
+	}
+	query, args := buildExportQuery(dataset, filter)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s export: %w", dataset, err)
+	}
+	defer rows.Close()
+
+	targets := exportScanTargets(columns)
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return fmt.Errorf("failed to scan %s export row: %w", dataset, err)
+		}
+		if err := fn(exportRowValues(targets)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// buildExportQuery 各資料集的查詢（SELECT 欄位順序與 entity.ExportColumns 一致）
+func buildExportQuery(dataset entity.ExportDataset, f *entity.ExportFilter) (string, []any) {
+	var args searchArgs
+
+	switch dataset {
+	case entity.ExportPosts:
+		// 期間 / 平台 / 語言 / 主題 / Entity 篩選與混合搜尋相同
+		filterSQL := buildSearchFilter(&entity.SearchFilter{
+			From:       f.From,
+			To:         f.To,
+			Platforms:  f.Platforms,
+			Languages:  f.Languages,
+			TopicCodes: f.TopicCodes,
+			EntityIDs:  f.EntityIDs,
+		}, &args)
+		return `
+			SELECT p.post_id, p.platform, p.created_at, p.author_id, p.author_username,
+				p.author_followers::bigint, p.content,
+				p.likes::bigint, p.comments::bigint, p.shares::bigint, p.views::bigint,
+				p.sentiment, p.sentiment_score::float8, p.sentiment_source, p.intent, p.product_type,
+				COALESCE((SELECT array_agg(DISTINCT t.code::text)
+					FROM post_topics pt JOIN topics t ON t.id = pt.topic_id
+					WHERE pt.post_id::text = p.post_id), '{}'::text[]),
+				COALESCE((SELECT array_agg(pst.tag::text ORDER BY pst.confidence DESC)
+					FROM post_soft_tags pst WHERE pst.post_id = p.post_id), '{}'::text[]),
+				p.kind, COALESCE(p.parent_post_id, ''), COALESCE(p.root_post_id, ''),
+				COALESCE(p.language, '')
+			FROM posts p
+			WHERE true` + filterSQL + `
+			ORDER BY p.created_at, p.post_id`, args
+
+	case entity.ExportMentions:
+		filterSQL := buildSearchFilter(&entity.SearchFilter{
+			From:       f.From,
+			To:         f.To,
+			Platforms:  f.Platforms,
+			Languages:  f.Languages,
+			TopicCodes: f.TopicCodes,
+		}, &args)
+		filterSQL += exportEntityFilter("pem.object_id", f.EntityIDs, &args)
+		return `
+			SELECT pem.post_id, pem.object_id::text, o.canonical_name, ot.name,
+				pem.sentiment, pem.sentiment_score::float8, pem.mention_text, pem.source,
+				p.platform, p.created_at
+			FROM post_entity_mentions pem
+			JOIN posts p ON p.post_id = pem.post_id
+			JOIN objects o ON o.id = pem.object_id
+			JOIN object_types ot ON ot.id = o.type_id
+			WHERE true` + filterSQL + `
+			ORDER BY p.created_at, pem.post_id, pem.object_id`, args
+
+	case entity.ExportAspects:
+		filterSQL := buildSearchFilter(&entity.SearchFilter{
+			From:       f.From,
+			To:         f.To,
+			Platforms:  f.Platforms,
+			Languages:  f.Languages,
+			TopicCodes: f.TopicCodes,
+		}, &args)
+		filterSQL += exportEntityFilter("ea.object_id", f.EntityIDs, &args)
+		return `
+			SELECT ea.post_id, ea.object_id::text, o.canonical_name, ea.aspect,
+				ea.sentiment, ea.sentiment_score::float8, ea.mention_text,
+				p.platform, p.created_at
+			FROM entity_aspects ea
+			JOIN posts p ON p.post_id = ea.post_id
+			JOIN objects o ON o.id = ea.object_id
+			WHERE true` + filterSQL + `
+			ORDER BY p.created_at, ea.post_id, ea.id`, args
+
+	case entity.ExportObservations:
+		filterSQL := exportTimeFilter("eo.period_start", f, &args)
+		filterSQL += exportEntityFilter("eo.object_id", f.EntityIDs, &args)
+		return `
+			SELECT eo.object_id::text, o.canonical_name, ot.name, eo.period_type,
+				eo.period_start::timestamptz,
+				eo.mention_count::bigint, eo.positive_count::bigint, eo.negative_count::bigint,
+				eo.neutral_count::bigint, eo.mixed_count::bigint,
+				eo.avg_sentiment::float8, COALESCE(eo.aspect_data, '[]'::jsonb)::text
+			FROM entity_observations eo
+			JOIN objects o ON o.id = eo.object_id
+			JOIN object_types ot ON ot.id = o.type_id
+			WHERE true` + filterSQL + `
+			ORDER BY eo.period_start, eo.period_type, o.canonical_name`, args
+
+	default: // entity.ExportFacts
+		filterSQL := exportTimeFilter("df.created_at", f, &args)
+		filterSQL += exportEntityFilter("df.object_id", f.EntityIDs, &args)
+		return `
+			SELECT df.id, df.object_id::text, o.canonical_name, df.fact_type, df.severity,
+				df.title, df.description, COALESCE(df.evidence, '{}'::jsonb)::text,
+				df.period_type, df.period_start::timestamptz,
+				COALESCE(df.is_read, false), COALESCE(df.is_dismissed, false), df.created_at
+			FROM derived_facts df
+			JOIN objects o ON o.id = df.object_id
+			WHERE true` + filterSQL + `
+			ORDER BY df.created_at, df.id`, args
+	}
+}
+
+func exportTimeFilter(column string, f *entity.ExportFilter, args *searchArgs) string {
+	sql := ""
+	if f.From != nil {
+		sql += fmt.Sprintf(" AND %s >= %s", column, args.add(*f.From))
+	}
+	if f.To != nil {
+		sql += fmt.Sprintf(" AND %s < %s", column, args.add(*f.To))
+	}
+	return sql
+}
+
+func exportEntityFilter(column string, entityIDs []string, args *searchArgs) string {
+	if len(entityIDs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s::text = ANY(%s::text[])", column, args.add(entityIDs))
+}
+
+// exportScanTargets 依欄位型別建立可接受 NULL 的 Scan 目標
+func exportScanTargets(columns []entity.ExportColumn) []any {
+	targets := make([]any, len(columns))
+	for i, col := range columns {
+		switch col.Type {
+		case entity.ExportInt:
+			targets[i] = new(*int64)
+		case entity.ExportFloat:
+			targets[i] = new(*float64)
+		case entity.ExportBool:
+			targets[i] = new(*bool)
+		case entity.ExportTime:
+			targets[i] = new(*time.Time)
+		case entity.ExportStrings:
+			targets[i] = new([]string)
+		default:
+			targets[i] = new(*string)
+		}
+	}
+	return targets
+}
+
+// exportRowValues 取出 Scan 結果（NULL → nil）
+func exportRowValues(targets []any) []any {
+	row := make([]any, len(targets))
+	for i, t := range targets {
+		switch v := t.(type) {
+		case **int64:
+			if *v != nil {
+				row[i] = **v
+			}
+		case **float64:
+			if *v != nil {
+				row[i] = **v
+			}
+		case **bool:
+			if *v != nil {
+				row[i] = **v
+			}
+		case **time.Time:
+			if *v != nil {
+				row[i] = **v
+			}
+		case *[]string:
+			row[i] = append([]string(nil), *v...)
+		case **string:
+			if *v != nil {
+				row[i] = **v
+			}
+		}
+	}
+	return row
+}
@@ -0,0 +1,284 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// GraphRetrievalRepo PostgreSQL 實作的 GraphRetrievalRepository
+type GraphRetrievalRepo struct {
+	db *DB
+}
+
+// NewGraphRetrievalRepo 建立 GraphRetrievalRepository
+func NewGraphRetrievalRepo(db *DB) repository.GraphRetrievalRepository {
+	return &GraphRetrievalRepo{db: db}
+}
+
+// ResolveEntityName 名稱 / 別名精確比對優先，其次 trigram 模糊比對
+func (r *GraphRetrievalRepo) ResolveEntityName(ctx context.Context, name string) (*entity.AskEntity, error) {
+	e := &entity.AskEntity{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT o.id, o.canonical_name, ot.name,
+			CASE
+				WHEN lower(o.canonical_name) = lower($1) THEN 'name'
+				WHEN EXISTS (SELECT 1 FROM object_aliases oa WHERE oa.object_id = o.id AND lower(oa.alias) = lower($1)) THEN 'alias'
+				ELSE 'fuzzy'
+			END AS match
+		FROM objects o
+		JOIN object_types ot ON ot.id = o.type_id
+		WHERE o.status = 'active'
+		  AND (lower(o.canonical_name) = lower($1)
+			OR o.id IN (SELECT oa.object_id FROM object_aliases oa WHERE lower(oa.alias) = lower($1))
+			OR similarity(o.canonical_name, $1) > 0.4)
+		ORDER BY (lower(o.canonical_name) = lower($1)) DESC,
+			similarity(o.canonical_name, $1) DESC
+		LIMIT 1`, name).Scan(&e.ID, &e.Name, &e.Type, &e.Match)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve entity %q: %w", name, err)
+	}
+	return e, nil
+}
+
+// aspectFilter 以子字串比對 aspect（alias 為 entity_aspects 的別名），回傳 " AND ..." 條件
+func aspectFilter(alias string, aspects []string, args *searchArgs) string {
+	if len(aspects) == 0 {
+		return ""
+	}
+	patterns := make([]string, len(aspects))
+	for i, a := range aspects {
+		patterns[i] = "%" + escapeLike(a) + "%"
+	}
+	return fmt.Sprintf(" AND %s.aspect ILIKE ANY(%s::text[])", alias, args.add(patterns))
+}
+
+// SentimentShifts Entity 整體（post_entity_mentions）與 aspect 層級（entity_aspects）的情感變化
+func (r *GraphRetrievalRepo) SentimentShifts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.SentimentShift, error) {
+	args := searchArgs{q.EntityIDs, q.Window.PrevFrom, q.Window.From, q.Window.To}
+	aspectSQL := aspectFilter("ea", q.Aspects, &args)
+	limit := args.add(q.Limit)
+
+	query := `
+		SELECT pem.object_id::text, o.canonical_name, '' AS aspect,
+			COUNT(*) FILTER (WHERE p.created_at >= $3),
+			COUNT(*) FILTER (WHERE p.created_at >= $3 AND pem.sentiment = 'negative'),
+			COALESCE(AVG(pem.sentiment_score) FILTER (WHERE p.created_at >= $3), 0)::float8,
+			COUNT(*) FILTER (WHERE p.created_at < $3),
+			COUNT(*) FILTER (WHERE p.created_at < $3 AND pem.sentiment = 'negative'),
+			COALESCE(AVG(pem.sentiment_score) FILTER (WHERE p.created_at < $3), 0)::float8
+		FROM post_entity_mentions pem
+		JOIN posts p ON p.post_id = pem.post_id
+		JOIN objects o ON o.id = pem.object_id
+		WHERE pem.object_id::text = ANY($1::text[])
+		  AND p.created_at >= $2 AND p.created_at < $4
+		GROUP BY pem.object_id, o.canonical_name
+		UNION ALL
+		(SELECT ea.object_id::text, o.canonical_name, ea.aspect::text,
+			COUNT(*) FILTER (WHERE p.created_at >= $3),
+			COUNT(*) FILTER (WHERE p.created_at >= $3 AND ea.sentiment = 'negative'),
+			COALESCE(AVG(ea.sentiment_score) FILTER (WHERE p.created_at >= $3), 0)::float8,
+			COUNT(*) FILTER (WHERE p.created_at < $3),
+			COUNT(*) FILTER (WHERE p.created_at < $3 AND ea.sentiment = 'negative'),
+			COALESCE(AVG(ea.sentiment_score) FILTER (WHERE p.created_at < $3), 0)::float8
+		FROM entity_aspects ea
+		JOIN posts p ON p.post_id = ea.post_id
+		JOIN objects o ON o.id = ea.object_id
+		WHERE ea.object_id::text = ANY($1::text[])
+		  AND p.created_at >= $2 AND p.created_at < $4` + aspectSQL + `
+		GROUP BY ea.object_id, o.canonical_name, ea.aspect
+		ORDER BY COUNT(*) DESC
+		LIMIT ` + limit + `)`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment shifts: %w", err)
+	}
+	defer rows.Close()
+
+	var shifts []*entity.SentimentShift
+	for rows.Next() {
+		s := &entity.SentimentShift{}
+		if err := rows.Scan(&s.EntityID, &s.EntityName, &s.Aspect,
+			&s.CurTotal, &s.CurNegative, &s.CurAvgScore,
+			&s.PrevTotal, &s.PrevNegative, &s.PrevAvgScore); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment shift: %w", err)
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
+}
+
+// mentionFilter 貼文層級的 aspect / 情感篩選（pem = post_entity_mentions）
+func mentionFilter(q *entity.AskEvidenceQuery, args *searchArgs) string {
+	var sb strings.Builder
+	if len(q.Aspects) > 0 {
+		sb.WriteString(` AND EXISTS (
+			SELECT 1 FROM entity_aspects ea
+			WHERE ea.post_id = pem.post_id AND ea.object_id = pem.object_id` + aspectFilter("ea", q.Aspects, args))
+		if q.Sentiment != "" {
+			fmt.Fprintf(&sb, " AND ea.sentiment = %s", args.add(q.Sentiment))
+		}
+		sb.WriteString(")")
+	} else if q.Sentiment != "" {
+		fmt.Fprintf(&sb, " AND pem.sentiment = %s", args.add(q.Sentiment))
+	}
+	return sb.String()
+}
+
+// AuthorDrivers 本期提及 Entity 最多的作者
+func (r *GraphRetrievalRepo) AuthorDrivers(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.AuthorDriver, error) {
+	args := searchArgs{q.EntityIDs, q.Window.From, q.Window.To}
+	filterSQL := mentionFilter(q, &args)
+	limit := args.add(q.Limit)
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT pem.object_id::text, o.canonical_name, p.author_username,
+			COALESCE(MAX(p.author_followers), 0),
+			COUNT(DISTINCT p.post_id),
+			COUNT(DISTINCT p.post_id) FILTER (WHERE pem.sentiment = 'negative'),
+			(array_agg(DISTINCT p.post_id::text))[1:3]
+		FROM post_entity_mentions pem
+		JOIN posts p ON p.post_id = pem.post_id
+		JOIN objects o ON o.id = pem.object_id
+		WHERE pem.object_id::text = ANY($1::text[])
+		  AND p.created_at >= $2 AND p.created_at < $3
+		  AND COALESCE(p.author_username, '') <> ''`+filterSQL+`
+		GROUP BY pem.object_id, o.canonical_name, p.author_username
+		ORDER BY COUNT(DISTINCT p.post_id) DESC, MAX(p.author_followers) DESC NULLS LAST
+		LIMIT `+limit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query author drivers: %w", err)
+	}
+	defer rows.Close()
+
+	var drivers []*entity.AuthorDriver
+	for rows.Next() {
+		d := &entity.AuthorDriver{}
+		if err := rows.Scan(&d.EntityID, &d.EntityName, &d.Author, &d.Followers,
+			&d.Posts, &d.Negative, &d.PostIDs); err != nil {
+			return nil, fmt.Errorf("failed to scan author driver: %w", err)
+		}
+		drivers = append(drivers, d)
+	}
+	return drivers, rows.Err()
+}
+
+// Facts 期間內（含對照期）未忽略的 derived facts，嚴重度高者優先
+func (r *GraphRetrievalRepo) Facts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.AskFact, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT f.id, f.object_id::text, o.canonical_name, f.fact_type, f.severity, f.title,
+			COALESCE(f.description, ''), f.created_at
+		FROM derived_facts f
+		JOIN objects o ON o.id = f.object_id
+		WHERE f.object_id::text = ANY($1::text[])
+		  AND NOT f.is_dismissed
+		  AND f.created_at >= $2
+		ORDER BY CASE f.severity WHEN 'critical' THEN 1 WHEN 'warning' THEN 2 ELSE 3 END, f.created_at DESC
+		LIMIT $3`, q.EntityIDs, q.Window.PrevFrom, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query facts: %w", err)
+	}
+	defer rows.Close()
+
+	var facts []*entity.AskFact
+	for rows.Next() {
+		f := &entity.AskFact{}
+		if err := rows.Scan(&f.ID, &f.EntityID, &f.EntityName, &f.FactType, &f.Severity,
+			&f.Title, &f.Description, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fact: %w", err)
+		}
+		facts = append(facts, f)
+	}
+	return facts, rows.Err()
+}
+
+// Relations typed relations 優先，補上舊的 object_links
+func (r *GraphRetrievalRepo) Relations(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.RelationEvidence, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT source_id, source_name, relation, target_id, target_name, target_type FROM (
+			SELECT s.id::text AS source_id, s.canonical_name AS source_name,
+				COALESCE(rt.display_name, rt.slug) AS relation,
+				t.id::text AS target_id, t.canonical_name AS target_name, ot.name AS target_type,
+				0 AS priority, r.confidence AS weight
+			FROM object_relations r
+			JOIN objects s ON s.id = r.source_id
+			JOIN objects t ON t.id = r.target_id
+			JOIN object_types ot ON ot.id = t.type_id
+			JOIN ontology_relation_types rt ON rt.id = r.relation_type_id
+			WHERE r.source_id::text = ANY($1::text[])
+			UNION ALL
+			SELECT s.id::text, s.canonical_name, ol.link_type, t.id::text, t.canonical_name, ot.name,
+				1, 1.0
+			FROM object_links ol
+			JOIN objects s ON s.id = ol.source_id
+			JOIN objects t ON t.id = ol.target_id
+			JOIN object_types ot ON ot.id = t.type_id
+			WHERE ol.source_id::text = ANY($1::text[])
+		) rel
+		ORDER BY priority, weight DESC
+		LIMIT $2`, q.EntityIDs, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relations: %w", err)
+	}
+	defer rows.Close()
+
+	var relations []*entity.RelationEvidence
+	seen := make(map[string]bool)
+	for rows.Next() {
+		rel := &entity.RelationEvidence{}
+		if err := rows.Scan(&rel.SourceID, &rel.SourceName, &rel.Relation,
+			&rel.TargetID, &rel.TargetName, &rel.TargetType); err != nil {
+			return nil, fmt.Errorf("failed to scan relation: %w", err)
+		}
+		key := rel.SourceID + "|" + rel.TargetID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		relations = append(relations, rel)
+	}
+	return relations, rows.Err()
+}
+
+// EvidencePosts 本期代表性貼文（提及片段優先，沒有則取內容開頭）
+func (r *GraphRetrievalRepo) EvidencePosts(ctx context.Context, q *entity.AskEvidenceQuery) ([]*entity.PostEvidence, error) {
+	args := searchArgs{q.EntityIDs, q.Window.From, q.Window.To}
+	filterSQL := mentionFilter(q, &args)
+	limit := args.add(q.Limit)
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT p.post_id, pem.object_id::text, o.canonical_name,
+			LEFT(COALESCE(NULLIF(pem.mention_text, ''), p.content), 200),
+			COALESCE(pem.sentiment, ''), COALESCE(p.author_username, ''), p.created_at
+		FROM post_entity_mentions pem
+		JOIN posts p ON p.post_id = pem.post_id
+		JOIN objects o ON o.id = pem.object_id
+		WHERE pem.object_id::text = ANY($1::text[])
+		  AND p.created_at >= $2 AND p.created_at < $3`+filterSQL+`
+		ORDER BY (COALESCE(p.likes, 0) + COALESCE(p.comments, 0) * 2 + COALESCE(p.shares, 0) * 3) DESC,
+			p.created_at DESC
+		LIMIT `+limit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query evidence posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.PostEvidence
+	for rows.Next() {
+		p := &entity.PostEvidence{}
+		if err := rows.Scan(&p.PostID, &p.EntityID, &p.EntityName, &p.Snippet,
+			&p.Sentiment, &p.Author, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan evidence post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
@@ -198,6 +198,29 @@ func (r *ObjectRepo) SaveAlias(ctx context.Context, alias *entity.ObjectAlias) e
 	return nil
 }
 
+// ListActiveAliases 列出 active Entity 的所有別名
+func (r *ObjectRepo) ListActiveAliases(ctx context.Context) ([]*entity.ObjectAlias, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT oa.id, oa.object_id, oa.alias, oa.source, oa.confidence, oa.created_at
+		FROM object_aliases oa
+		JOIN objects o ON o.id = oa.object_id
+		WHERE o.status = 'active'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []*entity.ObjectAlias
+	for rows.Next() {
+		var a entity.ObjectAlias
+		if err := rows.Scan(&a.ID, &a.ObjectID, &a.Alias, &a.Source, &a.Confidence, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, &a)
+	}
+	return aliases, rows.Err()
+}
+
 // SaveLink 建立 Entity 之間的關係
 func (r *ObjectRepo) SaveLink(ctx context.Context, link *entity.ObjectLink) error {
 	propsJSON, err := json.Marshal(link.Properties)
@@ -244,15 +267,31 @@ func (r *ObjectRepo) FindLinksBySource(ctx context.Context, sourceID string) ([]
 
 // SaveMention 記錄貼文提及 Entity
 func (r *ObjectRepo) SaveMention(ctx context.Context, mention *entity.PostEntityMention) error {
-	_, err := r.db.Pool.Exec(ctx, `
-		INSERT INTO post_entity_mentions (post_id, object_id, sentiment, sentiment_score, mention_text, source)
-		VALUES ($1, $2, $3, $4, $5, $6)
+	return saveMention(ctx, r.db.Pool, mention)
+}
+
+// saveMention 寫入提及（同一貼文同一 Entity 更新為新結果，人工校正不覆蓋）
+func saveMention(ctx context.Context, q execer, mention *entity.PostEntityMention) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO post_entity_mentions (post_id, object_id, sentiment, sentiment_score, mention_text, source, mention_start, mention_end,
+			raw_sentiment_score, model, prompt_version, calibration_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), $12)
 		ON CONFLICT (post_id, object_id) DO UPDATE SET
 			sentiment = EXCLUDED.sentiment,
 			sentiment_score = EXCLUDED.sentiment_score,
-			mention_text = EXCLUDED.mention_text`,
+			mention_text = EXCLUDED.mention_text,
+			source = EXCLUDED.source,
+			mention_start = EXCLUDED.mention_start,
+			mention_end = EXCLUDED.mention_end,
+			raw_sentiment_score = EXCLUDED.raw_sentiment_score,
+			model = EXCLUDED.model,
+			prompt_version = EXCLUDED.prompt_version,
+			calibration_id = EXCLUDED.calibration_id
+		WHERE post_entity_mentions.source <> 'manual'`,
 		mention.PostID, mention.ObjectID, mention.Sentiment,
-		mention.SentimentScore, mention.MentionText, mention.Source)
+		mention.SentimentScore, mention.MentionText, mention.Source,
+		spanStart(mention.Span), spanEnd(mention.Span),
+		mention.RawSentimentScore, mention.Model, mention.PromptVersion, mention.CalibrationID)
 	if err != nil {
 		return fmt.Errorf("failed to save mention: %w", err)
 	}
@@ -262,7 +301,9 @@ func (r *ObjectRepo) SaveMention(ctx context.Context, mention *entity.PostEntity
 // FindMentionsByObject 查詢某 Entity 被哪些貼文提及
 func (r *ObjectRepo) FindMentionsByObject(ctx context.Context, objectID string, limit int) ([]*entity.PostEntityMention, error) {
 	query := `
-		SELECT id, post_id, object_id, sentiment, sentiment_score, mention_text, source, created_at
+		SELECT id, post_id, object_id, sentiment, sentiment_score, mention_text, source,
+		       mention_start, mention_end, raw_sentiment_score, COALESCE(model, ''), COALESCE(prompt_version, ''),
+		       calibration_id, created_at
 		FROM post_entity_mentions
 		WHERE object_id = $1
 		ORDER BY created_at DESC
@@ -287,11 +328,18 @@ func (r *ObjectRepo) FindMentionsByObject(ctx context.Context, objectID string,
 
 // SaveEntityAspect 儲存 Entity 的 Aspect 評價
 func (r *ObjectRepo) SaveEntityAspect(ctx context.Context, aspect *entity.EntityAspect) error {
-	_, err := r.db.Pool.Exec(ctx, `
-		INSERT INTO entity_aspects (post_id, object_id, aspect, sentiment, sentiment_score, mention_text)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
+	return saveEntityAspect(ctx, r.db.Pool, aspect)
+}
+
+func saveEntityAspect(ctx context.Context, q execer, aspect *entity.EntityAspect) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO entity_aspects (post_id, object_id, aspect, sentiment, sentiment_score, mention_text, mention_start, mention_end, aspect_node_id,
+			raw_sentiment_score, model, prompt_version, calibration_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13)`,
 		aspect.PostID, aspect.ObjectID, aspect.Aspect,
-		aspect.Sentiment, aspect.SentimentScore, aspect.MentionText)
+		aspect.Sentiment, aspect.SentimentScore, aspect.MentionText,
+		spanStart(aspect.Span), spanEnd(aspect.Span), aspect.AspectNodeID,
+		aspect.RawSentimentScore, aspect.Model, aspect.PromptVersion, aspect.CalibrationID)
 	if err != nil {
 		return fmt.Errorf("failed to save entity aspect: %w", err)
 	}
@@ -301,7 +349,9 @@ func (r *ObjectRepo) SaveEntityAspect(ctx context.Context, aspect *entity.Entity
 // FindAspectsByObject 查詢某 Entity 的所有 Aspect 評價
 func (r *ObjectRepo) FindAspectsByObject(ctx context.Context, objectID string) ([]*entity.EntityAspect, error) {
 	query := `
-		SELECT id, post_id, object_id, aspect, sentiment, sentiment_score, mention_text, created_at
+		SELECT id, post_id, object_id, aspect, sentiment, sentiment_score, mention_text,
+		       mention_start, mention_end, aspect_node_id, raw_sentiment_score, COALESCE(model, ''),
+		       COALESCE(prompt_version, ''), calibration_id, created_at
 		FROM entity_aspects
 		WHERE object_id = $1
 		ORDER BY created_at DESC`
@@ -323,6 +373,82 @@ func (r *ObjectRepo) FindAspectsByObject(ctx context.Context, objectID string) (
 	return aspects, nil
 }
 
+// spanAlignTables 可回填原文位置的資料表
+var spanAlignTables = map[string]string{
+	"mention": "post_entity_mentions",
+	"aspect":  "entity_aspects",
+}
+
+// ListUnalignedSpans 列出尚未記錄原文位置的提及或面向
+func (r *ObjectRepo) ListUnalignedSpans(ctx context.Context, kind string, afterID int64, limit int) ([]*entity.SpanAlignTarget, error) {
+	table, ok := spanAlignTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown span kind %q", kind)
+	}
+	rows, err := r.db.Pool.Query(ctx, fmt.Sprintf(`
+		SELECT t.id, t.mention_text, p.content
+		FROM %s t
+		JOIN posts p ON p.post_id = t.post_id
+		WHERE t.id > $1
+		  AND t.mention_start IS NULL
+		  AND t.mention_text IS NOT NULL AND t.mention_text <> ''
+		  AND p.content IS NOT NULL
+		ORDER BY t.id
+		LIMIT $2`, table), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unaligned %ss: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var targets []*entity.SpanAlignTarget
+	for rows.Next() {
+		t := &entity.SpanAlignTarget{Kind: kind}
+		if err := rows.Scan(&t.ID, &t.MentionText, &t.Content); err != nil {
+			return nil, fmt.Errorf("failed to scan unaligned %s: %w", kind, err)
+		}
+		targets = append(targets, t)
+	}
+	return targets, rows.Err()
+}
+
+// SaveSpan 更新提及或面向的原文位置
+func (r *ObjectRepo) SaveSpan(ctx context.Context, kind string, id int64, span *entity.TextSpan) error {
+	table, ok := spanAlignTables[kind]
+	if !ok {
+		return fmt.Errorf("unknown span kind %q", kind)
+	}
+	_, err := r.db.Pool.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s SET mention_start = $2, mention_end = $3 WHERE id = $1`, table),
+		id, spanStart(span), spanEnd(span))
+	if err != nil {
+		return fmt.Errorf("failed to save %s span: %w", kind, err)
+	}
+	return nil
+}
+
+// spanStart / spanEnd 將可為 nil 的位置轉為 nullable 欄位值
+func spanStart(span *entity.TextSpan) *int {
+	if span == nil {
+		return nil
+	}
+	return &span.Start
+}
+
+func spanEnd(span *entity.TextSpan) *int {
+	if span == nil {
+		return nil
+	}
+	return &span.End
+}
+
+// scanSpan 由 nullable 欄位還原位置（任一為 NULL 時回傳 nil）
+func scanSpan(start, end *int) *entity.TextSpan {
+	if start == nil || end == nil {
+		return nil
+	}
+	return &entity.TextSpan{Start: *start, End: *end}
+}
+
 // --- scan helpers ---
 
 func (r *ObjectRepo) scanObject(row pgx.Row) (*entity.Object, error) {
@@ -371,26 +497,33 @@ func (r *ObjectRepo) scanLink(rows pgx.Rows) (*entity.ObjectLink, error) {
 
 func (r *ObjectRepo) scanMention(rows pgx.Rows) (*entity.PostEntityMention, error) {
 	var m entity.PostEntityMention
+	var start, end *int
 	err := rows.Scan(
 		&m.ID, &m.PostID, &m.ObjectID,
 		&m.Sentiment, &m.SentimentScore,
-		&m.MentionText, &m.Source, &m.CreatedAt,
+		&m.MentionText, &m.Source,
+		&start, &end, &m.RawSentimentScore, &m.Model, &m.PromptVersion,
+		&m.CalibrationID, &m.CreatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan mention: %w", err)
 	}
+	m.Span = scanSpan(start, end)
 	return &m, nil
 }
 
 func (r *ObjectRepo) scanEntityAspect(rows pgx.Rows) (*entity.EntityAspect, error) {
 	var a entity.EntityAspect
+	var start, end *int
 	err := rows.Scan(
 		&a.ID, &a.PostID, &a.ObjectID,
 		&a.Aspect, &a.Sentiment, &a.SentimentScore,
-		&a.MentionText, &a.CreatedAt,
+		&a.MentionText, &start, &end, &a.AspectNodeID,
+		&a.RawSentimentScore, &a.Model, &a.PromptVersion, &a.CalibrationID, &a.CreatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan entity aspect: %w", err)
 	}
+	a.Span = scanSpan(start, end)
 	return &a, nil
 }
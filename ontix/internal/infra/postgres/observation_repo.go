@@ -32,8 +32,11 @@ func (r *ObservationRepo) SaveObservation(ctx context.Context, obs *entity.Entit
 		INSERT INTO entity_observations
 			(object_id, period_start, period_type, mention_count,
 			 positive_count, negative_count, neutral_count, mixed_count,
-			 avg_sentiment, aspect_data)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			 avg_sentiment, aspect_data,
+			 comment_mention_count, comment_positive_count, comment_negative_count,
+			 comment_neutral_count, comment_mixed_count, comment_avg_sentiment,
+			 weighted_sentiment, total_engagement, estimated_reach, share_of_voice)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		ON CONFLICT (object_id, period_start, period_type)
 		DO UPDATE SET
 			mention_count  = EXCLUDED.mention_count,
@@ -42,7 +45,17 @@ func (r *ObservationRepo) SaveObservation(ctx context.Context, obs *entity.Entit
 			neutral_count  = EXCLUDED.neutral_count,
 			mixed_count    = EXCLUDED.mixed_count,
 			avg_sentiment  = EXCLUDED.avg_sentiment,
-			aspect_data    = EXCLUDED.aspect_data
+			aspect_data    = EXCLUDED.aspect_data,
+			comment_mention_count  = EXCLUDED.comment_mention_count,
+			comment_positive_count = EXCLUDED.comment_positive_count,
+			comment_negative_count = EXCLUDED.comment_negative_count,
+			comment_neutral_count  = EXCLUDED.comment_neutral_count,
+			comment_mixed_count    = EXCLUDED.comment_mixed_count,
+			comment_avg_sentiment  = EXCLUDED.comment_avg_sentiment,
+			weighted_sentiment = EXCLUDED.weighted_sentiment,
+			total_engagement   = EXCLUDED.total_engagement,
+			estimated_reach    = EXCLUDED.estimated_reach,
+			share_of_voice     = EXCLUDED.share_of_voice
 		RETURNING id`
 
 	err = r.db.Pool.QueryRow(ctx, query,
@@ -50,6 +63,9 @@ func (r *ObservationRepo) SaveObservation(ctx context.Context, obs *entity.Entit
 		obs.MentionCount, obs.PositiveCount, obs.NegativeCount,
 		obs.NeutralCount, obs.MixedCount, obs.AvgSentiment,
 		aspectJSON,
+		obs.CommentMentionCount, obs.CommentPositiveCount, obs.CommentNegativeCount,
+		obs.CommentNeutralCount, obs.CommentMixedCount, obs.CommentAvgSentiment,
+		obs.WeightedSentiment, obs.TotalEngagement, obs.EstimatedReach, obs.ShareOfVoice,
 	).Scan(&obs.ID)
 	if err != nil {
 		return fmt.Errorf("failed to save observation: %w", err)
@@ -62,7 +78,10 @@ func (r *ObservationRepo) FindObservation(ctx context.Context, objectID string,
 	query := `
 		SELECT id, object_id, period_start, period_type,
 		       mention_count, positive_count, negative_count, neutral_count, mixed_count,
-		       avg_sentiment, aspect_data, created_at
+		       avg_sentiment, aspect_data, created_at,
+		       comment_mention_count, comment_positive_count, comment_negative_count,
+		       comment_neutral_count, comment_mixed_count, comment_avg_sentiment,
+		       weighted_sentiment, total_engagement, estimated_reach, share_of_voice
 		FROM entity_observations
 		WHERE object_id = $1 AND period_start = $2 AND period_type = $3`
 
@@ -75,7 +94,10 @@ func (r *ObservationRepo) FindPreviousObservation(ctx context.Context, objectID
 	query := `
 		SELECT id, object_id, period_start, period_type,
 		       mention_count, positive_count, negative_count, neutral_count, mixed_count,
-		       avg_sentiment, aspect_data, created_at
+		       avg_sentiment, aspect_data, created_at,
+		       comment_mention_count, comment_positive_count, comment_negative_count,
+		       comment_neutral_count, comment_mixed_count, comment_avg_sentiment,
+		       weighted_sentiment, total_engagement, estimated_reach, share_of_voice
 		FROM entity_observations
 		WHERE object_id = $1 AND period_type = $2 AND period_start < $3
 		ORDER BY period_start DESC
@@ -90,7 +112,10 @@ func (r *ObservationRepo) ListRecentObservations(ctx context.Context, objectID s
 	query := `
 		SELECT id, object_id, period_start, period_type,
 		       mention_count, positive_count, negative_count, neutral_count, mixed_count,
-		       avg_sentiment, aspect_data, created_at
+		       avg_sentiment, aspect_data, created_at,
+		       comment_mention_count, comment_positive_count, comment_negative_count,
+		       comment_neutral_count, comment_mixed_count, comment_avg_sentiment,
+		       weighted_sentiment, total_engagement, estimated_reach, share_of_voice
 		FROM entity_observations
 		WHERE object_id = $1 AND period_type = $2
 		ORDER BY period_start DESC
@@ -118,7 +143,10 @@ func (r *ObservationRepo) ListObservationsForPeriod(ctx context.Context, periodS
 	query := `
 		SELECT id, object_id, period_start, period_type,
 		       mention_count, positive_count, negative_count, neutral_count, mixed_count,
-		       avg_sentiment, aspect_data, created_at
+		       avg_sentiment, aspect_data, created_at,
+		       comment_mention_count, comment_positive_count, comment_negative_count,
+		       comment_neutral_count, comment_mixed_count, comment_avg_sentiment,
+		       weighted_sentiment, total_engagement, estimated_reach, share_of_voice
 		FROM entity_observations
 		WHERE period_start = $1 AND period_type = $2
 		ORDER BY mention_count DESC`
@@ -141,6 +169,9 @@ func (r *ObservationRepo) ListObservationsForPeriod(ctx context.Context, periodS
 }
 
 // MaterializeObservations 從 post_entity_mentions + entity_aspects 聚合產生觀測
+// 重複貼文（post_duplicates）不計入，轉貼 / 洗版不會灌水聲量；
+// 留言 / 回覆的提及計入 comment_* 欄位，不影響原始貼文的計數與面向快照
+// 加權指標（互動加權情感、估計觸及、同類聲量佔比）與未加權欄位同一次寫入（SQL 與 recomputeObservations 共用）
 // 回傳新建/更新的觀測數量
 func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodStart time.Time, periodType string) (int, error) {
 	// 計算 period_end
@@ -159,21 +190,30 @@ func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodSta
 		INSERT INTO entity_observations
 			(object_id, period_start, period_type,
 			 mention_count, positive_count, negative_count, neutral_count, mixed_count,
-			 avg_sentiment, aspect_data)
+			 avg_sentiment, aspect_data,
+			 comment_mention_count, comment_positive_count, comment_negative_count,
+			 comment_neutral_count, comment_mixed_count, comment_avg_sentiment)
 		SELECT
 			m.object_id,
 			$1::date AS period_start,
 			$2 AS period_type,
-			COUNT(*),
-			COUNT(*) FILTER (WHERE m.sentiment = 'positive'),
-			COUNT(*) FILTER (WHERE m.sentiment = 'negative'),
-			COUNT(*) FILTER (WHERE m.sentiment = 'neutral'),
-			COUNT(*) FILTER (WHERE m.sentiment = 'mixed'),
-			AVG(m.sentiment_score),
-			'[]'::jsonb
+			COUNT(*) FILTER (WHERE `+mentionIsPost+`),
+			COUNT(*) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'positive'),
+			COUNT(*) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'negative'),
+			COUNT(*) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'neutral'),
+			COUNT(*) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'mixed'),
+			AVG(m.sentiment_score) FILTER (WHERE `+mentionIsPost+`),
+			'[]'::jsonb,
+			COUNT(*) FILTER (WHERE NOT `+mentionIsPost+`),
+			COUNT(*) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'positive'),
+			COUNT(*) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'negative'),
+			COUNT(*) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'neutral'),
+			COUNT(*) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'mixed'),
+			AVG(m.sentiment_score) FILTER (WHERE NOT `+mentionIsPost+`)
 		FROM post_entity_mentions m
 		WHERE m.created_at >= $1::timestamptz
 		  AND m.created_at < ($1::timestamptz + interval '%s')
+		  AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = m.post_id)
 		GROUP BY m.object_id
 		ON CONFLICT (object_id, period_start, period_type)
 		DO UPDATE SET
@@ -182,7 +222,13 @@ func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodSta
 			negative_count = EXCLUDED.negative_count,
 			neutral_count  = EXCLUDED.neutral_count,
 			mixed_count    = EXCLUDED.mixed_count,
-			avg_sentiment  = EXCLUDED.avg_sentiment`, interval)
+			avg_sentiment  = EXCLUDED.avg_sentiment,
+			comment_mention_count  = EXCLUDED.comment_mention_count,
+			comment_positive_count = EXCLUDED.comment_positive_count,
+			comment_negative_count = EXCLUDED.comment_negative_count,
+			comment_neutral_count  = EXCLUDED.comment_neutral_count,
+			comment_mixed_count    = EXCLUDED.comment_mixed_count,
+			comment_avg_sentiment  = EXCLUDED.comment_avg_sentiment`, interval)
 
 	tag, err := r.db.Pool.Exec(ctx, mentionQuery, periodStart, periodType)
 	if err != nil {
@@ -191,6 +237,7 @@ func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodSta
 	count := int(tag.RowsAffected())
 
 	// Step 2: Update aspect_data from entity_aspects
+	// 以正規面向節點彙總（未對應的面向維持原字串），附上節點路徑供規則 roll-up
 	aspectQuery := fmt.Sprintf(`
 		UPDATE entity_observations eo
 		SET aspect_data = COALESCE(asp_agg.data, '[]'::jsonb)
@@ -198,23 +245,30 @@ func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodSta
 			SELECT
 				per_aspect.object_id,
 				jsonb_agg(
-					jsonb_build_object(
+					jsonb_strip_nulls(jsonb_build_object(
 						'aspect', per_aspect.aspect,
 						'count', per_aspect.cnt,
-						'avg_sentiment', per_aspect.avg_s
-					) ORDER BY per_aspect.cnt DESC
+						'avg_sentiment', per_aspect.avg_s,
+						'node_id', per_aspect.node_id,
+						'path', an.path
+					)) ORDER BY per_aspect.cnt DESC
 				) AS data
 			FROM (
 				SELECT
-					object_id,
-					aspect,
+					ea.object_id,
+					COALESCE(n.name, ea.aspect) AS aspect,
+					MAX(n.id) AS node_id,
 					COUNT(*) AS cnt,
-					ROUND(AVG(sentiment_score)::NUMERIC, 3) AS avg_s
-				FROM entity_aspects
-				WHERE created_at >= $1::timestamptz
-				  AND created_at < ($1::timestamptz + interval '%s')
-				GROUP BY object_id, aspect
+					ROUND(AVG(ea.sentiment_score)::NUMERIC, 3) AS avg_s
+				FROM entity_aspects ea
+				LEFT JOIN aspect_nodes n ON n.id = ea.aspect_node_id
+				WHERE ea.created_at >= $1::timestamptz
+				  AND ea.created_at < ($1::timestamptz + interval '%s')
+				  AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = ea.post_id)
+				  AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.post_id = ea.post_id AND p.kind <> 'post')
+				GROUP BY ea.object_id, COALESCE(n.name, ea.aspect)
 			) per_aspect
+			LEFT JOIN aspect_nodes an ON an.id = per_aspect.node_id
 			GROUP BY per_aspect.object_id
 		) asp_agg
 		WHERE eo.object_id = asp_agg.object_id
@@ -226,25 +280,102 @@ func (r *ObservationRepo) MaterializeObservations(ctx context.Context, periodSta
 		return count, fmt.Errorf("failed to update aspect_data: %w", err)
 	}
 
+	// Step 3: 互動加權情感 + 互動數 + 觸及估計，Step 4: 同類聲量佔比（與刪除 / 編輯貼文後的重算共用）
+	periodCond := `eo.period_start = $1::date AND eo.period_type = $2`
+	if _, err := r.db.Pool.Exec(ctx, observationWeightedSQL(periodCond), periodStart, periodType); err != nil {
+		return count, fmt.Errorf("failed to update weighted metrics: %w", err)
+	}
+	if _, err := r.db.Pool.Exec(ctx, observationShareOfVoiceSQL(periodCond), periodStart, periodType); err != nil {
+		return count, fmt.Errorf("failed to update share_of_voice: %w", err)
+	}
+
 	return count, nil
 }
 
+// observationWeightedSQL 重算符合 cond 的觀測（eo 為 entity_observations 別名）的互動加權情感、互動數與觸及估計
+// 只計原始貼文；權重 1 + ln(1 + 互動數)：沒有互動的貼文權重 1，爆文影響較大但不會完全壓過其他提及。
+// 期間內已無原始貼文提及時互動數 / 觸及歸零、加權情感為 NULL（同 avg_sentiment）
+func observationWeightedSQL(cond string) string {
+	return `
+		UPDATE entity_observations t
+		SET weighted_sentiment = w.weighted_sentiment,
+		    total_engagement   = COALESCE(w.engagement, 0),
+		    estimated_reach    = COALESCE(w.reach, 0)
+		FROM entity_observations eo
+		LEFT JOIN LATERAL (
+			SELECT
+				SUM((1 + LN(1 + pm.engagement)) * m.sentiment_score)
+					/ NULLIF(SUM(1 + LN(1 + pm.engagement)) FILTER (WHERE m.sentiment_score IS NOT NULL), 0) AS weighted_sentiment,
+				SUM(pm.engagement) AS engagement,
+				SUM(pm.reach) AS reach
+			FROM post_entity_mentions m
+			CROSS JOIN LATERAL (
+				SELECT
+					COALESCE(MAX(COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0)), 0)::BIGINT AS engagement,
+					COALESCE(MAX(COALESCE(NULLIF(p.views, 0), NULLIF(p.author_followers, 0), a.followers)), 0)::BIGINT AS reach
+				FROM posts p
+				LEFT JOIN authors a ON ` + authorPostJoin + `
+				WHERE p.post_id = m.post_id
+			) pm
+			WHERE m.object_id = eo.object_id
+			  AND m.created_at >= eo.period_start::timestamptz
+			  AND m.created_at < ` + observationWindow + `
+			  AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = m.post_id)
+			  AND ` + mentionIsPost + `
+		) w ON true
+		WHERE t.id = eo.id AND ` + cond
+}
+
+// observationShareOfVoiceSQL 重算符合 cond 的觀測所在期間的同類 Entity（相同 object type，與推理引擎的 class 對應一致）聲量佔比
+// 同期所有觀測一起重算（分母改變）；沒有提及的觀測為 0
+func observationShareOfVoiceSQL(cond string) string {
+	return `
+		UPDATE entity_observations t
+		SET share_of_voice = COALESCE(sov.share, 0)
+		FROM (
+			SELECT
+				o2.id AS observation_id,
+				o2.mention_count::REAL / NULLIF(SUM(o2.mention_count) OVER (PARTITION BY o2.period_start, o2.period_type, obj.type_id), 0) AS share
+			FROM entity_observations o2
+			JOIN objects obj ON obj.id = o2.object_id
+			WHERE (o2.period_start, o2.period_type) IN (
+				SELECT eo.period_start, eo.period_type FROM entity_observations eo WHERE ` + cond + `
+			)
+		) sov
+		WHERE t.id = sov.observation_id`
+}
+
+// mentionIsPost 提及來自原始貼文（SQL 片段，m 為 post_entity_mentions 別名；未入庫的貼文視為原始貼文）
+const mentionIsPost = `NOT EXISTS (SELECT 1 FROM posts mp WHERE mp.post_id = m.post_id AND mp.kind <> 'post')`
+
 // --- scan helpers ---
 
 func (r *ObservationRepo) scanObservation(row pgx.Row) (*entity.EntityObservation, error) {
 	var obs entity.EntityObservation
 	var aspectJSON []byte
-	var avgSentiment *float64
+	var avgSentiment, commentAvgSentiment, weightedSentiment, shareOfVoice *float64
 
 	err := row.Scan(
 		&obs.ID, &obs.ObjectID, &obs.PeriodStart, &obs.PeriodType,
 		&obs.MentionCount, &obs.PositiveCount, &obs.NegativeCount,
 		&obs.NeutralCount, &obs.MixedCount,
 		&avgSentiment, &aspectJSON, &obs.CreatedAt,
+		&obs.CommentMentionCount, &obs.CommentPositiveCount, &obs.CommentNegativeCount,
+		&obs.CommentNeutralCount, &obs.CommentMixedCount, &commentAvgSentiment,
+		&weightedSentiment, &obs.TotalEngagement, &obs.EstimatedReach, &shareOfVoice,
 	)
 	if avgSentiment != nil {
 		obs.AvgSentiment = *avgSentiment
 	}
+	if commentAvgSentiment != nil {
+		obs.CommentAvgSentiment = *commentAvgSentiment
+	}
+	if weightedSentiment != nil {
+		obs.WeightedSentiment = *weightedSentiment
+	}
+	if shareOfVoice != nil {
+		obs.ShareOfVoice = *shareOfVoice
+	}
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -27,12 +27,20 @@ func (r *PostAnalysisRepo) SaveAnalysis(ctx context.Context, postID string, anal
 	}
 	defer tx.Rollback(ctx)
 
-	// 1. 更新 posts 表的 sentiment 欄位
-	_, err = tx.Exec(ctx, `
+	if err := saveAnalysisTx(ctx, tx, postID, analysis); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// saveAnalysisTx 在交易內寫入分析結果（SaveAnalysis 與 PostRepo.ReplaceAnalysis 共用）
+func saveAnalysisTx(ctx context.Context, tx pgx.Tx, postID string, analysis *repository.PostAnalysis) error {
+	// 1. 更新 posts 表的 sentiment 欄位（人工校正過的 sentiment 不覆蓋）
+	_, err := tx.Exec(ctx, `
 		UPDATE posts SET
-			sentiment = $1,
-			sentiment_score = $2,
-			sentiment_reason = $3,
+			sentiment = CASE WHEN sentiment_source = 'manual' THEN sentiment ELSE $1 END,
+			sentiment_score = CASE WHEN sentiment_source = 'manual' THEN sentiment_score ELSE $2 END,
+			sentiment_reason = CASE WHEN sentiment_source = 'manual' THEN sentiment_reason ELSE $3 END,
 			intent = $4,
 			product_type = $5
 		WHERE post_id = $6`,
@@ -47,11 +55,12 @@ func (r *PostAnalysisRepo) SaveAnalysis(ctx context.Context, postID string, anal
 		return fmt.Errorf("failed to update post sentiment: %w", err)
 	}
 
-	// 2. 儲存 soft_tags
+	// 2. 儲存 soft_tags（已有人工校正標籤的貼文不再寫入 LLM 標籤）
 	for _, tag := range analysis.SoftTags {
 		_, err = tx.Exec(ctx, `
 			INSERT INTO post_soft_tags (post_id, tag, confidence, created_at)
-			VALUES ($1, $2, $3, $4)
+			SELECT $1, $2, $3, $4
+			WHERE NOT EXISTS (SELECT 1 FROM post_soft_tags WHERE post_id = $1 AND source = 'manual')
 			ON CONFLICT (post_id, tag) DO UPDATE SET
 				confidence = EXCLUDED.confidence`,
 			postID, tag.Tag, tag.Confidence, time.Now(),
@@ -73,7 +82,7 @@ func (r *PostAnalysisRepo) SaveAnalysis(ctx context.Context, postID string, anal
 		}
 	}
 
-	return tx.Commit(ctx)
+	return nil
 }
 
 // SaveSoftTags 批次儲存軟標籤
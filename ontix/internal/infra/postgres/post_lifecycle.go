@@ -0,0 +1,601 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// 刪除貼文時一併清除的衍生資料表（post_id 為 VARCHAR）
+var postTextTables = []string{
+	"post_entity_mentions",
+	"entity_aspects",
+	"post_soft_tags",
+	"post_aspects",
+	"post_clusters",
+	"review_items",
+	"review_corrections",
+	"post_duplicates",
+	"post_fingerprints", // post_minhash_bands 隨 FK cascade
+	"post_metrics_history",
+	"post_chunks",
+}
+
+// 刪除貼文時一併清除的衍生資料表（post_id 為 BIGINT）
+var postNumericTables = []string{
+	"post_embeddings",
+	"post_tags",
+	"cluster_assignments",
+	"post_topics",
+	"post_topic_scores",
+	"post_llm_classifications",
+	"post_topic_labels",
+}
+
+// insertMetricsSnapshot 記錄互動數快照（與該貼文最新一筆相同時略過）
+func insertMetricsSnapshot(ctx context.Context, tx pgx.Tx, postID string, m entity.Metrics) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO post_metrics_history (post_id, likes, comments, shares, views)
+		SELECT $1, $2, $3, $4, $5
+		WHERE NOT EXISTS (
+			SELECT 1 FROM (
+				SELECT likes, comments, shares, views
+				FROM post_metrics_history
+				WHERE post_id = $1
+				ORDER BY captured_at DESC
+				LIMIT 1
+			) last
+			WHERE last.likes = $2 AND last.comments = $3 AND last.shares = $4 AND last.views = $5
+		)`, postID, m.Likes, m.Comments, m.Shares, m.Views)
+	if err != nil {
+		return fmt.Errorf("failed to save metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// UpdateMetrics 更新互動數並記錄快照
+func (r *PostRepo) UpdateMetrics(ctx context.Context, postID string, metrics entity.Metrics) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE posts SET likes = $2, comments = $3, shares = $4, views = $5, metrics_updated_at = NOW()
+		WHERE post_id = $1`,
+		postID, metrics.Likes, metrics.Comments, metrics.Shares, metrics.Views)
+	if err != nil {
+		return fmt.Errorf("failed to update metrics: %w", err)
+	}
+	if err := insertMetricsSnapshot(ctx, tx, postID, metrics); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ListMetricsHistory 互動數快照（由新到舊）
+func (r *PostRepo) ListMetricsHistory(ctx context.Context, postID string, limit int) ([]*entity.MetricsSnapshot, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT post_id, likes, comments, shares, views, captured_at
+		FROM post_metrics_history
+		WHERE post_id = $1
+		ORDER BY captured_at DESC
+		LIMIT $2`, postID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics history: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*entity.MetricsSnapshot
+	for rows.Next() {
+		var s entity.MetricsSnapshot
+		if err := rows.Scan(&s.PostID, &s.Metrics.Likes, &s.Metrics.Comments, &s.Metrics.Shares, &s.Metrics.Views, &s.CapturedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan metrics snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+	return snapshots, rows.Err()
+}
+
+// PrepareReanalysis 內容編輯後重跑前的準備：記下並清除舊內容的去重狀態（新內容重新比對），
+// 內容與 LLM 衍生資料保留到 ReplaceAnalysis 才替換；重跑失敗時以 RestoreReanalysis 還原
+func (r *PostRepo) PrepareReanalysis(ctx context.Context, postID string) (*entity.ReanalysisMark, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	mark := &entity.ReanalysisMark{PostID: postID}
+	fp, err := scanFingerprint(tx.QueryRow(ctx, `
+		SELECT post_id, platform, content_hash, signature, created_at
+		FROM post_fingerprints WHERE post_id = $1`, postID))
+	switch {
+	case err == pgx.ErrNoRows:
+	case err != nil:
+		return nil, err
+	default:
+		mark.Fingerprint = fp
+		if err := tx.QueryRow(ctx, `
+			SELECT COALESCE(array_agg(band_hash ORDER BY band), '{}')
+			FROM post_minhash_bands WHERE post_id = $1`, postID).Scan(&mark.FingerprintBands); err != nil {
+			return nil, fmt.Errorf("failed to load minhash bands: %w", err)
+		}
+	}
+
+	var dup entity.PostDuplicate
+	var platform, method, policy string
+	err = tx.QueryRow(ctx, `
+		SELECT post_id, platform, COALESCE(author_username, ''), content, original_post_id,
+		       similarity, method, policy, detected_at
+		FROM post_duplicates WHERE post_id = $1`, postID).Scan(&dup.PostID, &platform, &dup.AuthorUsername,
+		&dup.Content, &dup.OriginalPostID, &dup.Similarity, &method, &policy, &dup.DetectedAt)
+	switch {
+	case err == pgx.ErrNoRows:
+	case err != nil:
+		return nil, fmt.Errorf("failed to load duplicate: %w", err)
+	default:
+		dup.Platform = entity.Platform(platform)
+		dup.Method = entity.DedupMethod(method)
+		dup.Policy = entity.DedupPolicy(policy)
+		mark.Duplicate = &dup
+	}
+
+	if err := clearDedupState(ctx, tx, postID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mark, nil
+}
+
+// RestoreReanalysis 重跑失敗：清掉新內容比對時寫入的去重狀態，還原 PrepareReanalysis 記下的
+func (r *PostRepo) RestoreReanalysis(ctx context.Context, mark *entity.ReanalysisMark) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := clearDedupState(ctx, tx, mark.PostID); err != nil {
+		return err
+	}
+	if mark.Fingerprint != nil {
+		if err := saveFingerprintTx(ctx, tx, mark.Fingerprint, mark.FingerprintBands); err != nil {
+			return err
+		}
+	}
+	if mark.Duplicate != nil {
+		if err := saveDuplicate(ctx, tx, mark.Duplicate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// clearDedupState 刪除貼文的重複紀錄與指紋（post_minhash_bands 隨 FK cascade）
+func clearDedupState(ctx context.Context, tx pgx.Tx, postID string) error {
+	for _, table := range []string{"post_duplicates", "post_fingerprints"} {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE post_id = $1`, postID); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceAnalysis 新分析成功後，在同一交易內寫入新內容、以新結果取代舊的 LLM 衍生資料（人工校正保留）並重算受影響的觀測
+//   - mentions：本次重新抽取的提及（upsert，沿用既有列的建立時間），其餘舊提及刪除
+//   - aspects：舊的 entity_aspects 全部刪除後寫入本次的
+//   - analysis 非 nil 時寫入新的整體情感 / 軟標籤 / 面向；全為 nil 表示不重新分析（例如改為近似重複只入庫）
+func (r *PostRepo) ReplaceAnalysis(ctx context.Context, post *entity.Post, mark *entity.ReanalysisMark, analysis *repository.PostAnalysis,
+	mentions []*entity.PostEntityMention, aspects []*entity.EntityAspect) error {
+	postID := mark.PostID
+	numericID, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid post id %q: %w", postID, err)
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	obsIDs, err := affectedObservations(ctx, tx, postID)
+	if err != nil {
+		return err
+	}
+
+	if err := savePostTx(ctx, tx, post); err != nil {
+		return err
+	}
+
+	keep := make([]string, 0, len(mentions))
+	for _, m := range mentions {
+		keep = append(keep, m.ObjectID)
+	}
+	stmts := []struct {
+		sql  string
+		args []any
+	}{
+		{`DELETE FROM post_entity_mentions WHERE post_id = $1 AND source <> 'manual'
+			AND NOT (object_id::text = ANY($2::text[]))`, []any{postID, keep}},
+		{`DELETE FROM entity_aspects WHERE post_id = $1`, []any{postID}},
+		{`DELETE FROM post_soft_tags WHERE post_id = $1 AND source <> 'manual'`, []any{postID}},
+		{`DELETE FROM post_aspects WHERE post_id = $1`, []any{postID}},
+		{`DELETE FROM post_chunks WHERE post_id = $1`, []any{postID}},
+		{`DELETE FROM post_tags WHERE post_id = $1`, []any{numericID}},
+		{`DELETE FROM post_topic_scores WHERE post_id = $1`, []any{numericID}},
+		{`DELETE FROM post_llm_classifications WHERE post_id = $1`, []any{numericID}},
+	}
+	for _, st := range stmts {
+		if _, err := tx.Exec(ctx, st.sql, st.args...); err != nil {
+			return fmt.Errorf("failed to replace analysis: %w", err)
+		}
+	}
+
+	if analysis != nil {
+		if err := saveAnalysisTx(ctx, tx, postID, analysis); err != nil {
+			return err
+		}
+	}
+	for _, m := range mentions {
+		if err := saveMention(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	for _, a := range aspects {
+		if err := saveEntityAspect(ctx, tx, a); err != nil {
+			return err
+		}
+	}
+
+	// 新提及落入的觀測（新 Entity / 新期間）一併重算
+	newIDs, err := affectedObservations(ctx, tx, postID)
+	if err != nil {
+		return err
+	}
+	if err := recomputeObservations(ctx, tx, unionIDs(obsIDs, newIDs)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// unionIDs 合併兩組 id（去重）
+func unionIDs(a, b []int64) []int64 {
+	seen := make(map[int64]bool, len(a)+len(b))
+	out := make([]int64, 0, len(a)+len(b))
+	for _, id := range append(append([]int64{}, a...), b...) {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Delete 刪除貼文及所有衍生資料並留下 tombstone
+//   - 貼文底下的留言 / 回覆一併刪除並留下 tombstone（原貼文下架後留言也不再公開，不再計入 comment_* 觀測）
+//   - 被刪除的貼文是重複群組的原始貼文時，最早的重複貼文升為新的原始貼文（見 promoteDuplicate）
+func (r *PostRepo) Delete(ctx context.Context, tombstone *entity.PostTombstone) (bool, error) {
+	postID := tombstone.PostID
+	if _, err := strconv.ParseInt(postID, 10, 64); err != nil {
+		return false, fmt.Errorf("invalid post id %q: %w", postID, err)
+	}
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 平台未指定時沿用貼文本身的平台
+	platform := string(tombstone.Platform)
+	if platform == "" {
+		if err := tx.QueryRow(ctx, `SELECT platform FROM posts WHERE post_id = $1`, postID).Scan(&platform); err != nil && err != pgx.ErrNoRows {
+			return false, fmt.Errorf("failed to load post: %w", err)
+		}
+	}
+
+	replies, err := threadReplies(ctx, tx, postID)
+	if err != nil {
+		return false, err
+	}
+	ids := append([]string{postID}, replies...)
+
+	// 受影響的觀測：被刪除的貼文，以及升為原始貼文、開始計入觀測的重複貼文
+	var obsIDs []int64
+	for _, id := range ids {
+		promoted, err := promoteDuplicate(ctx, tx, id, ids)
+		if err != nil {
+			return false, err
+		}
+		affected := []string{id}
+		if promoted != "" {
+			affected = append(affected, promoted)
+		}
+		for _, a := range affected {
+			aIDs, err := affectedObservations(ctx, tx, a)
+			if err != nil {
+				return false, err
+			}
+			obsIDs = unionIDs(obsIDs, aIDs)
+		}
+	}
+
+	var existed bool
+	for _, id := range ids {
+		ok, err := deletePostTx(ctx, tx, id)
+		if err != nil {
+			return false, err
+		}
+		if id == postID {
+			existed = ok
+		}
+	}
+
+	if err := recomputeObservations(ctx, tx, obsIDs); err != nil {
+		return false, err
+	}
+
+	deletedAt := tombstone.DeletedAt
+	if deletedAt.IsZero() {
+		deletedAt = time.Now()
+	}
+	for _, id := range ids {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO post_tombstones (post_id, platform, reason, requested_by, deleted_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (post_id) DO UPDATE SET
+				reason = EXCLUDED.reason,
+				requested_by = EXCLUDED.requested_by,
+				deleted_at = EXCLUDED.deleted_at`,
+			id, platform, tombstone.Reason, tombstone.RequestedBy, deletedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to save tombstone: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	tombstone.Platform = entity.Platform(platform)
+	tombstone.DeletedAt = deletedAt
+	return existed, nil
+}
+
+// threadReplies 貼文底下的所有留言與回覆（含回覆的回覆）
+func threadReplies(ctx context.Context, tx pgx.Tx, postID string) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		WITH RECURSIVE thread AS (
+			SELECT post_id FROM posts WHERE parent_post_id = $1 OR root_post_id = $1
+			UNION
+			SELECT p.post_id FROM posts p JOIN thread t ON p.parent_post_id = t.post_id
+		)
+		SELECT post_id FROM thread WHERE post_id <> $1`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thread replies: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan reply id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// promoteDuplicate 原始貼文被刪除時，群組中最早入庫的重複貼文升為新的原始貼文：
+// 其餘重複紀錄改連到它、它的重複紀錄移除（開始計入觀測），並沿用原本的指紋
+// （exact 重複內容相同；近似重複的相似度已過門檻，指紋足以代表）。
+// 群組內沒有已入庫的貼文（drop 策略）時整組重複紀錄一併刪除；deleting 為同一次刪除的貼文，不升格。
+// 回傳升格的貼文 ID（無則空字串）
+func promoteDuplicate(ctx context.Context, tx pgx.Tx, postID string, deleting []string) (string, error) {
+	var promoted string
+	err := tx.QueryRow(ctx, `
+		SELECT d.post_id
+		FROM post_duplicates d
+		JOIN posts p ON p.post_id = d.post_id
+		WHERE d.original_post_id = $1 AND d.post_id <> ALL($2)
+		ORDER BY p.created_at, d.detected_at, d.post_id
+		LIMIT 1`, postID, deleting).Scan(&promoted)
+	if err == pgx.ErrNoRows {
+		if _, err := tx.Exec(ctx, `DELETE FROM post_duplicates WHERE original_post_id = $1`, postID); err != nil {
+			return "", fmt.Errorf("failed to delete duplicate group: %w", err)
+		}
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query duplicate group: %w", err)
+	}
+
+	stmts := []struct {
+		sql  string
+		args []any
+	}{
+		{`DELETE FROM post_duplicates WHERE post_id = $1`, []any{promoted}},
+		{`UPDATE post_duplicates SET original_post_id = $2 WHERE original_post_id = $1`, []any{postID, promoted}},
+		{`INSERT INTO post_fingerprints (post_id, platform, content_hash, signature, created_at)
+			SELECT $2, p.platform, f.content_hash, f.signature, f.created_at
+			FROM post_fingerprints f, posts p
+			WHERE f.post_id = $1 AND p.post_id = $2
+			ON CONFLICT (post_id) DO NOTHING`, []any{postID, promoted}},
+		{`INSERT INTO post_minhash_bands (band, band_hash, post_id)
+			SELECT band, band_hash, $2 FROM post_minhash_bands WHERE post_id = $1
+			ON CONFLICT DO NOTHING`, []any{postID, promoted}},
+	}
+	for _, st := range stmts {
+		if _, err := tx.Exec(ctx, st.sql, st.args...); err != nil {
+			return "", fmt.Errorf("failed to promote duplicate %s: %w", promoted, err)
+		}
+	}
+	return promoted, nil
+}
+
+// deletePostTx 刪除單篇貼文及其衍生資料，回傳貼文原本是否存在
+func deletePostTx(ctx context.Context, tx pgx.Tx, postID string) (bool, error) {
+	numericID, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid post id %q: %w", postID, err)
+	}
+	for _, table := range postTextTables {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE post_id = $1`, postID); err != nil {
+			return false, fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	for _, table := range postNumericTables {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE post_id = $1`, numericID); err != nil {
+			return false, fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM posts WHERE post_id = $1`, postID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete post: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FindTombstoned 回傳 ids 中已刪除的貼文 ID
+func (r *PostRepo) FindTombstoned(ctx context.Context, ids []string) (map[string]bool, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT post_id FROM post_tombstones WHERE post_id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+// observationWindow 觀測期間的結束時間（SQL 片段，eo 為 entity_observations 別名）
+const observationWindow = `(eo.period_start::timestamptz + CASE eo.period_type WHEN 'day' THEN interval '1 day' ELSE interval '7 days' END)`
+
+// affectedObservations 貼文的提及 / aspect 所落入的觀測（刪除前呼叫）
+func affectedObservations(ctx context.Context, tx pgx.Tx, postID string) ([]int64, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT eo.id
+		FROM entity_observations eo
+		JOIN (
+			SELECT object_id, created_at FROM post_entity_mentions WHERE post_id = $1
+			UNION ALL
+			SELECT object_id, created_at FROM entity_aspects WHERE post_id = $1
+		) x ON x.object_id = eo.object_id
+		   AND x.created_at >= eo.period_start::timestamptz
+		   AND x.created_at < `+observationWindow, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query affected observations: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan observation id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// recomputeObservations 以剩餘的提及 / aspect 重算觀測（與 MaterializeObservations 相同口徑，含加權指標與聲量佔比），
+// 已無提及的觀測直接刪除
+func recomputeObservations(ctx context.Context, tx pgx.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE entity_observations t SET
+			mention_count  = s.cnt,
+			positive_count = s.pos,
+			negative_count = s.neg,
+			neutral_count  = s.neu,
+			mixed_count    = s.mix,
+			avg_sentiment  = s.avg_s,
+			aspect_data    = s.aspects,
+			comment_mention_count  = s.c_cnt,
+			comment_positive_count = s.c_pos,
+			comment_negative_count = s.c_neg,
+			comment_neutral_count  = s.c_neu,
+			comment_mixed_count    = s.c_mix,
+			comment_avg_sentiment  = s.c_avg_s
+		FROM (
+			SELECT eo.id,
+				COUNT(m.id) FILTER (WHERE `+mentionIsPost+`) AS cnt,
+				COUNT(m.id) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'positive') AS pos,
+				COUNT(m.id) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'negative') AS neg,
+				COUNT(m.id) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'neutral') AS neu,
+				COUNT(m.id) FILTER (WHERE `+mentionIsPost+` AND m.sentiment = 'mixed') AS mix,
+				AVG(m.sentiment_score) FILTER (WHERE `+mentionIsPost+`) AS avg_s,
+				COUNT(m.id) FILTER (WHERE NOT `+mentionIsPost+`) AS c_cnt,
+				COUNT(m.id) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'positive') AS c_pos,
+				COUNT(m.id) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'negative') AS c_neg,
+				COUNT(m.id) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'neutral') AS c_neu,
+				COUNT(m.id) FILTER (WHERE NOT `+mentionIsPost+` AND m.sentiment = 'mixed') AS c_mix,
+				AVG(m.sentiment_score) FILTER (WHERE NOT `+mentionIsPost+`) AS c_avg_s,
+				(
+					SELECT COALESCE(jsonb_agg(
+						jsonb_strip_nulls(jsonb_build_object('aspect', pa.aspect, 'count', pa.cnt, 'avg_sentiment', pa.avg_s,
+							'node_id', pa.node_id, 'path', an.path))
+						ORDER BY pa.cnt DESC), '[]'::jsonb)
+					FROM (
+						SELECT COALESCE(n.name, ea.aspect) AS aspect, MAX(n.id) AS node_id,
+						       COUNT(*) AS cnt, ROUND(AVG(ea.sentiment_score)::NUMERIC, 3) AS avg_s
+						FROM entity_aspects ea
+						LEFT JOIN aspect_nodes n ON n.id = ea.aspect_node_id
+						WHERE ea.object_id = eo.object_id
+						  AND ea.created_at >= eo.period_start::timestamptz
+						  AND ea.created_at < `+observationWindow+`
+						  AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = ea.post_id)
+						  AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.post_id = ea.post_id AND p.kind <> 'post')
+						GROUP BY COALESCE(n.name, ea.aspect)
+					) pa
+					LEFT JOIN aspect_nodes an ON an.id = pa.node_id
+				) AS aspects
+			FROM entity_observations eo
+			LEFT JOIN post_entity_mentions m
+			       ON m.object_id = eo.object_id
+			      AND m.created_at >= eo.period_start::timestamptz
+			      AND m.created_at < `+observationWindow+`
+			      AND NOT EXISTS (SELECT 1 FROM post_duplicates d WHERE d.post_id = m.post_id)
+			WHERE eo.id = ANY($1)
+			GROUP BY eo.id
+		) s
+		WHERE t.id = s.id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to recompute observations: %w", err)
+	}
+
+	// 加權指標與聲量佔比（與 MaterializeObservations 相同 SQL）；先於刪除重算，同期其他觀測的佔比一併更新
+	idsCond := `eo.id = ANY($1)`
+	if _, err := tx.Exec(ctx, observationWeightedSQL(idsCond), ids); err != nil {
+		return fmt.Errorf("failed to recompute weighted metrics: %w", err)
+	}
+	if _, err := tx.Exec(ctx, observationShareOfVoiceSQL(idsCond), ids); err != nil {
+		return fmt.Errorf("failed to recompute share_of_voice: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM entity_observations WHERE id = ANY($1) AND mention_count = 0 AND comment_mention_count = 0`, ids); err != nil {
+		return fmt.Errorf("failed to delete empty observations: %w", err)
+	}
+	return nil
+}
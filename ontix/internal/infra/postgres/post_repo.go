@@ -29,17 +29,30 @@ func (r *PostRepo) Save(ctx context.Context, post *entity.Post) error {
 	}
 	defer tx.Rollback(ctx)
 
+	if err := savePostTx(ctx, tx, post); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// savePostTx 在交易內寫入貼文、互動數快照與 embedding（Save 與 ReplaceAnalysis 共用）
+func savePostTx(ctx context.Context, tx pgx.Tx, post *entity.Post) error {
 	// 1. 檢查是否已存在
 	var existingID int64
-	err = tx.QueryRow(ctx, `SELECT id FROM posts WHERE post_id = $1`, post.PostID).Scan(&existingID)
+	err := tx.QueryRow(ctx, `SELECT id FROM posts WHERE post_id = $1`, post.PostID).Scan(&existingID)
 
 	if err == nil {
-		// 已存在，更新
+		// 已存在，更新（內容不同時記錄編輯時間）
 		post.ID = existingID
 		_, err = tx.Exec(ctx, `
-			UPDATE posts SET content = $1, likes = $2, comments = $3
-			WHERE id = $4`,
-			post.Content, post.Metrics.Likes, post.Metrics.Comments, existingID)
+			UPDATE posts SET
+				edited_at = CASE WHEN content IS DISTINCT FROM $1 THEN NOW() ELSE edited_at END,
+				language = CASE WHEN content IS DISTINCT FROM $1 OR language IS NULL THEN $7 ELSE language END,
+				content = $1, likes = $2, comments = $3, shares = $4, views = $5,
+				metrics_updated_at = NOW()
+			WHERE id = $6`,
+			post.Content, post.Metrics.Likes, post.Metrics.Comments, post.Metrics.Shares, post.Metrics.Views, existingID,
+			post.Language)
 		if err != nil {
 			return fmt.Errorf("failed to update post: %w", err)
 		}
@@ -47,10 +60,15 @@ func (r *PostRepo) Save(ctx context.Context, post *entity.Post) error {
 		// 不存在，插入
 		query := `
 			INSERT INTO posts (post_id, content, platform, author_id, author_username, author_followers,
-				likes, comments, shares, views, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+				likes, comments, shares, views, created_at, kind, parent_post_id, root_post_id, language)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), NULLIF($14, ''), $15)
 			RETURNING id`
 
+		kind := post.Kind
+		if kind == "" {
+			kind = entity.PostKindPost
+		}
+
 		err = tx.QueryRow(ctx, query,
 			post.PostID,
 			post.Content,
@@ -63,6 +81,10 @@ func (r *PostRepo) Save(ctx context.Context, post *entity.Post) error {
 			post.Metrics.Shares,
 			post.Metrics.Views,
 			post.CreatedAt,
+			kind,
+			post.ParentID,
+			post.RootID,
+			post.Language,
 		).Scan(&post.ID)
 		if err != nil {
 			return fmt.Errorf("failed to insert post: %w", err)
@@ -71,7 +93,12 @@ func (r *PostRepo) Save(ctx context.Context, post *entity.Post) error {
 		return fmt.Errorf("failed to check existing post: %w", err)
 	}
 
-	// 2. 插入/更新 embedding (如果有)
+	// 2. 互動數快照
+	if err := insertMetricsSnapshot(ctx, tx, post.PostID, post.Metrics); err != nil {
+		return err
+	}
+
+	// 3. 插入/更新 embedding (如果有)
 	if len(post.Embedding) > 0 {
 		embQuery := `
 			INSERT INTO post_embeddings (post_id, embedding, created_at)
@@ -83,7 +110,33 @@ func (r *PostRepo) Save(ctx context.Context, post *entity.Post) error {
 			return fmt.Errorf("failed to save embedding: %w", err)
 		}
 	}
+	return nil
+}
+
+// SaveChunks 以本次切段結果取代貼文既有的 chunk
+func (r *PostRepo) SaveChunks(ctx context.Context, postID string, chunks []*entity.PostChunk) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
+	if _, err := tx.Exec(ctx, `DELETE FROM post_chunks WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to clear chunks: %w", err)
+	}
+	for _, ch := range chunks {
+		var embedding any
+		if len(ch.Embedding) > 0 {
+			embedding = pgvector.NewVector(ch.Embedding)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO post_chunks (post_id, chunk_index, start_offset, end_offset, content, embedding)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			postID, ch.ChunkIndex, ch.Start, ch.End, ch.Content, embedding)
+		if err != nil {
+			return fmt.Errorf("failed to save chunk %d: %w", ch.ChunkIndex, err)
+		}
+	}
 	return tx.Commit(ctx)
 }
 
@@ -93,7 +146,9 @@ func (r *PostRepo) FindByID(ctx context.Context, id string) (*entity.Post, error
 		SELECT p.id, p.post_id, p.content, p.platform,
 			p.author_id, p.author_username, p.author_followers,
 			p.likes, p.comments, p.shares, p.views,
-			pe.embedding, p.created_at
+			pe.embedding, p.created_at,
+			p.kind, COALESCE(p.parent_post_id, ''), COALESCE(p.root_post_id, ''),
+			COALESCE(p.language, '')
 		FROM posts p
 		LEFT JOIN post_embeddings pe ON p.post_id = pe.post_id
 		WHERE p.post_id = $1`
@@ -108,7 +163,9 @@ func (r *PostRepo) FindByIDs(ctx context.Context, ids []string) ([]*entity.Post,
 		SELECT p.id, p.post_id, p.content, p.platform,
 			p.author_id, p.author_username, p.author_followers,
 			p.likes, p.comments, p.shares, p.views,
-			pe.embedding, p.created_at
+			pe.embedding, p.created_at,
+			p.kind, COALESCE(p.parent_post_id, ''), COALESCE(p.root_post_id, ''),
+			COALESCE(p.language, '')
 		FROM posts p
 		LEFT JOIN post_embeddings pe ON p.post_id = pe.post_id
 		WHERE p.post_id = ANY($1)`
@@ -141,7 +198,9 @@ func (r *PostRepo) FindSimilar(ctx context.Context, postID string, limit int) ([
 		SELECT p.id, p.post_id, p.content, p.platform,
 			p.author_id, p.author_username, p.author_followers,
 			p.likes, p.comments, p.shares, p.views,
-			pe.embedding, p.created_at
+			pe.embedding, p.created_at,
+			p.kind, COALESCE(p.parent_post_id, ''), COALESCE(p.root_post_id, ''),
+			COALESCE(p.language, '')
 		FROM posts p
 		JOIN post_embeddings pe ON p.post_id = pe.post_id, target t
 		WHERE p.post_id != $1
@@ -171,7 +230,9 @@ func (r *PostRepo) FindWithEmbeddings(ctx context.Context, limit int, offset int
 		SELECT p.id, p.post_id, p.content, p.platform,
 			p.author_id, p.author_username, p.author_followers,
 			p.likes, p.comments, p.shares, p.views,
-			pe.embedding, p.created_at
+			pe.embedding, p.created_at,
+			p.kind, COALESCE(p.parent_post_id, ''), COALESCE(p.root_post_id, ''),
+			COALESCE(p.language, '')
 		FROM posts p
 		JOIN post_embeddings pe ON p.post_id = pe.post_id
 		ORDER BY p.created_at DESC
@@ -201,6 +262,51 @@ func (r *PostRepo) CountWithEmbeddings(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+// ListWithoutLanguage 尚未偵測語言的貼文（只填 ID / PostID / Content）
+func (r *PostRepo) ListWithoutLanguage(ctx context.Context, afterID int64, limit int) ([]*entity.Post, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, post_id, content
+		FROM posts
+		WHERE language IS NULL AND id > $1
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posts without language: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		var post entity.Post
+		if err := rows.Scan(&post.ID, &post.PostID, &post.Content); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
+// UpdateLanguages 批次寫入偵測到的語言
+func (r *PostRepo) UpdateLanguages(ctx context.Context, languages map[string]entity.Language) error {
+	if len(languages) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(languages))
+	langs := make([]string, 0, len(languages))
+	for id, lang := range languages {
+		ids = append(ids, id)
+		langs = append(langs, string(lang))
+	}
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE posts p SET language = u.language
+		FROM unnest($1::text[], $2::text[]) AS u(post_id, language)
+		WHERE p.post_id = u.post_id`, ids, langs)
+	if err != nil {
+		return fmt.Errorf("failed to update post languages: %w", err)
+	}
+	return nil
+}
+
 func (r *PostRepo) scanPost(row pgx.Row) (*entity.Post, error) {
 	var post entity.Post
 	var authorID, authorUsername string
@@ -223,6 +329,10 @@ func (r *PostRepo) scanPost(row pgx.Row) (*entity.Post, error) {
 		&views,
 		&embedding,
 		&createdAt,
+		&post.Kind,
+		&post.ParentID,
+		&post.RootID,
+		&post.Language,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -262,6 +372,10 @@ func (r *PostRepo) scanPostRows(rows pgx.Rows) (*entity.Post, error) {
 		&views,
 		&embedding,
 		&createdAt,
+		&post.Kind,
+		&post.ParentID,
+		&post.RootID,
+		&post.Language,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan post: %w", err)
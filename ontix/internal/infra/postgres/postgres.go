@@ -6,6 +6,7 @@ import (
 
 	"github.com/ikala/ontix/config"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	pgxvec "github.com/pgvector/pgvector-go/pgx"
 )
@@ -15,6 +16,11 @@ type DB struct {
 	Pool *pgxpool.Pool
 }
 
+// execer 連線池與交易共用的 Exec（同一段寫入 SQL 可在交易內外使用）
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
 // New 建立 PostgreSQL 連線池
 func New(cfg *config.Config) (*DB, error) {
 	poolCfg, err := pgxpool.ParseConfig(cfg.PostgresDSN())
@@ -46,7 +52,7 @@ func (db *DB) Close() {
 
 // RefreshMaterializedViews 刷新 Ontology 相關的 materialized views
 func (db *DB) RefreshMaterializedViews(ctx context.Context) error {
-	views := []string{"entity_stats", "entity_aspect_stats"}
+	views := []string{"entity_stats", "entity_aspect_stats", "author_stats"}
 	for _, v := range views {
 		if _, err := db.Pool.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+v); err != nil {
 			return fmt.Errorf("refresh %s: %w", v, err)
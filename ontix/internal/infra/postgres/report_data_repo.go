@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// ReportDataRepo PostgreSQL 實作的 ReportDataRepository
+type ReportDataRepo struct {
+	db *DB
+}
+
+// NewReportDataRepo 建立 ReportDataRepository
+func NewReportDataRepo(db *DB) repository.ReportDataRepository {
+	return &ReportDataRepo{db: db}
+}
+
+// ResolveEntities 展開報表涵蓋的 Entity
+func (r *ReportDataRepo) ResolveEntities(ctx context.Context, entityIDs []string, classIDs []int, limit int) ([]*entity.ReportEntity, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH RECURSIVE classes AS (
+			SELECT id FROM ontology_classes WHERE id = ANY($2::int[])
+			UNION
+			SELECT c.id FROM ontology_classes c JOIN classes ON c.parent_id = classes.id
+		),
+		picked AS (
+			SELECT o.id, 0 AS rank_group, 0::bigint AS mentions
+			FROM objects o
+			WHERE o.id::text = ANY($1::text[])
+			UNION ALL
+			SELECT * FROM (
+				SELECT o.id, 1, (SELECT COUNT(*) FROM post_entity_mentions pem WHERE pem.object_id = o.id)
+				FROM objects o
+				WHERE o.class_id IN (SELECT id FROM classes)
+				  AND o.status = 'active'
+				  AND o.id::text <> ALL($1::text[])
+				ORDER BY 3 DESC
+				LIMIT $3
+			) by_class
+		)
+		SELECT o.id::text, o.canonical_name, ot.name
+		FROM picked
+		JOIN objects o ON o.id = picked.id
+		JOIN object_types ot ON ot.id = o.type_id
+		ORDER BY picked.rank_group, picked.mentions DESC, o.canonical_name`,
+		nonNilStrings(entityIDs), nonNilInts(classIDs), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve report entities: %w", err)
+	}
+	defer rows.Close()
+
+	var entities []*entity.ReportEntity
+	for rows.Next() {
+		e := &entity.ReportEntity{}
+		var objType string
+		if err := rows.Scan(&e.ID, &e.Name, &objType); err != nil {
+			return nil, fmt.Errorf("failed to scan report entity: %w", err)
+		}
+		e.Type = entity.ObjectType(objType)
+		entities = append(entities, e)
+	}
+	return entities, rows.Err()
+}
+
+// KPIs 本期 vs 上期指標
+// 提及數 / 情感以提及列計算；互動數先依貼文去重再加總，不隨提及列數重複計算
+func (r *ReportDataRepo) KPIs(ctx context.Context, objectID string, w entity.ReportWindow) (*entity.ReportKPIs, error) {
+	k := &entity.ReportKPIs{}
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH m AS (
+			SELECT pem.post_id, pem.sentiment, pem.sentiment_score,
+				p.created_at >= $3 AS cur,
+				COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0) AS engagement
+			FROM post_entity_mentions pem
+			JOIN posts p ON p.post_id = pem.post_id
+			WHERE pem.object_id = $1 AND p.created_at >= $2 AND p.created_at < $4
+		),
+		post_engagement AS (
+			SELECT DISTINCT ON (post_id) post_id, cur, engagement FROM m
+		)
+		SELECT
+			COUNT(*) FILTER (WHERE cur),
+			COUNT(*) FILTER (WHERE NOT cur),
+			COUNT(*) FILTER (WHERE cur AND sentiment = 'positive'),
+			COUNT(*) FILTER (WHERE cur AND sentiment = 'negative'),
+			COUNT(*) FILTER (WHERE cur AND sentiment = 'neutral'),
+			COALESCE(AVG(sentiment_score) FILTER (WHERE cur), 0),
+			COALESCE(AVG(sentiment_score) FILTER (WHERE NOT cur), 0),
+			COUNT(DISTINCT post_id) FILTER (WHERE cur),
+			(SELECT COALESCE(SUM(engagement) FILTER (WHERE cur), 0) FROM post_engagement),
+			(SELECT COALESCE(SUM(engagement) FILTER (WHERE NOT cur), 0) FROM post_engagement)
+		FROM m`,
+		objectID, w.PrevFrom, w.From, w.To,
+	).Scan(&k.Mentions, &k.PrevMentions, &k.Positive, &k.Negative, &k.Neutral,
+		&k.AvgSentiment, &k.PrevAvgSentiment, &k.Posts, &k.Engagement, &k.PrevEngagement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report KPIs: %w", err)
+	}
+	return k, nil
+}
+
+// SentimentTrend 期間內每週提及 / 情感（entity_observations 週快照）
+func (r *ReportDataRepo) SentimentTrend(ctx context.Context, objectID string, w entity.ReportWindow) ([]*entity.ReportTrendPoint, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT period_start::timestamptz, mention_count, positive_count, negative_count, COALESCE(avg_sentiment, 0)
+		FROM entity_observations
+		WHERE object_id = $1 AND period_type = 'week'
+		  AND period_start >= $2::date - 6 AND period_start < $3
+		ORDER BY period_start`,
+		objectID, w.From, w.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment trend: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*entity.ReportTrendPoint
+	for rows.Next() {
+		p := &entity.ReportTrendPoint{}
+		if err := rows.Scan(&p.WeekStart, &p.Mentions, &p.Positive, &p.Negative, &p.AvgSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan trend point: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// TopAspects 本期提及最多的面向
+func (r *ReportDataRepo) TopAspects(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportAspect, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT aspect, COUNT(*),
+			COUNT(*) FILTER (WHERE sentiment = 'positive'),
+			COUNT(*) FILTER (WHERE sentiment = 'negative'),
+			COALESCE(AVG(sentiment_score), 0)
+		FROM entity_aspects
+		WHERE object_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY aspect
+		ORDER BY COUNT(*) DESC, aspect
+		LIMIT $4`,
+		objectID, w.From, w.To, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report aspects: %w", err)
+	}
+	defer rows.Close()
+
+	var aspects []*entity.ReportAspect
+	for rows.Next() {
+		a := &entity.ReportAspect{}
+		if err := rows.Scan(&a.Aspect, &a.Total, &a.Positive, &a.Negative, &a.AvgSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan report aspect: %w", err)
+		}
+		aspects = append(aspects, a)
+	}
+	return aspects, rows.Err()
+}
+
+// KOLs 本期帶動討論的作者（依提及數、互動數排序）
+func (r *ReportDataRepo) KOLs(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportKOL, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH m AS (
+			SELECT p.author_username AS author, p.post_id,
+				COALESCE(p.author_followers, 0) AS followers,
+				COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0) AS engagement,
+				pem.sentiment_score
+			FROM post_entity_mentions pem
+			JOIN posts p ON p.post_id = pem.post_id
+			WHERE pem.object_id = $1 AND p.created_at >= $2 AND p.created_at < $3
+			  AND COALESCE(p.author_username, '') <> ''
+		),
+		total AS (SELECT COUNT(*) AS n FROM m)
+		SELECT author, MAX(followers), COUNT(DISTINCT post_id), SUM(engagement),
+			COALESCE(AVG(sentiment_score), 0),
+			CASE WHEN total.n > 0 THEN COUNT(*)::float8 / total.n * 100 ELSE 0 END
+		FROM m, total
+		GROUP BY author, total.n
+		ORDER BY COUNT(DISTINCT post_id) DESC, SUM(engagement) DESC
+		LIMIT $4`,
+		objectID, w.From, w.To, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report KOLs: %w", err)
+	}
+	defer rows.Close()
+
+	var kols []*entity.ReportKOL
+	for rows.Next() {
+		k := &entity.ReportKOL{}
+		if err := rows.Scan(&k.Author, &k.Followers, &k.Posts, &k.Engagement, &k.AvgSentiment, &k.ContributionPct); err != nil {
+			return nil, fmt.Errorf("failed to scan report KOL: %w", err)
+		}
+		kols = append(kols, k)
+	}
+	return kols, rows.Err()
+}
+
+// Facts 本期未忽略的 derived facts
+func (r *ReportDataRepo) Facts(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]*entity.ReportFact, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, fact_type, severity, title, COALESCE(description, ''), created_at
+		FROM derived_facts
+		WHERE object_id = $1 AND NOT is_dismissed
+		  AND created_at >= $2 AND created_at < $3
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, created_at DESC
+		LIMIT $4`,
+		objectID, w.From, w.To, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report facts: %w", err)
+	}
+	defer rows.Close()
+
+	var facts []*entity.ReportFact
+	for rows.Next() {
+		f := &entity.ReportFact{}
+		if err := rows.Scan(&f.ID, &f.FactType, &f.Severity, &f.Title, &f.Description, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan report fact: %w", err)
+		}
+		facts = append(facts, f)
+	}
+	return facts, rows.Err()
+}
+
+// NegativeSamples 本期負面提及片段
+func (r *ReportDataRepo) NegativeSamples(ctx context.Context, objectID string, w entity.ReportWindow, limit int) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT pem.mention_text
+		FROM post_entity_mentions pem
+		JOIN posts p ON p.post_id = pem.post_id
+		WHERE pem.object_id = $1 AND pem.sentiment = 'negative'
+		  AND p.created_at >= $2 AND p.created_at < $3
+		  AND COALESCE(pem.mention_text, '') <> ''
+		ORDER BY COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0) DESC
+		LIMIT $4`,
+		objectID, w.From, w.To, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query negative samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("failed to scan negative sample: %w", err)
+		}
+		samples = append(samples, text)
+	}
+	return samples, rows.Err()
+}
@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReportRepo PostgreSQL 實作的 ReportRepository
+type ReportRepo struct {
+	db *DB
+}
+
+// NewReportRepo 建立 ReportRepository
+func NewReportRepo(db *DB) repository.ReportRepository {
+	return &ReportRepo{db: db}
+}
+
+const reportDefinitionColumns = `
+	id, name, entity_ids::text[], class_ids, period, sections, formats,
+	schedule, timezone, recipients, is_active, last_run_at, next_run_at, created_at, updated_at`
+
+const reportRunColumns = `
+	id, definition_id, status, trigger, period_start, period_end,
+	html IS NOT NULL, pdf IS NOT NULL, COALESCE(error, ''), delivered_to, created_at, finished_at`
+
+// CreateDefinition 建立報表定義
+func (r *ReportRepo) CreateDefinition(ctx context.Context, def *entity.ReportDefinition) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO report_definitions
+			(name, entity_ids, class_ids, period, sections, formats, schedule, timezone, recipients, is_active, next_run_at)
+		VALUES ($1, $2::text[]::uuid[], $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at`,
+		def.Name, nonNilStrings(def.EntityIDs), nonNilInts(def.ClassIDs), def.Period,
+		sectionStrings(def.Sections), formatStrings(def.Formats), def.Schedule, def.Timezone,
+		nonNilStrings(def.Recipients), def.IsActive, def.NextRunAt,
+	).Scan(&def.ID, &def.CreatedAt, &def.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create report definition: %w", err)
+	}
+	return nil
+}
+
+// UpdateDefinition 更新報表定義
+func (r *ReportRepo) UpdateDefinition(ctx context.Context, def *entity.ReportDefinition) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE report_definitions SET
+			name = $2, entity_ids = $3::text[]::uuid[], class_ids = $4, period = $5,
+			sections = $6, formats = $7, schedule = $8, timezone = $9,
+			recipients = $10, is_active = $11, next_run_at = $12, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		def.ID, def.Name, nonNilStrings(def.EntityIDs), nonNilInts(def.ClassIDs), def.Period,
+		sectionStrings(def.Sections), formatStrings(def.Formats), def.Schedule, def.Timezone,
+		nonNilStrings(def.Recipients), def.IsActive, def.NextRunAt,
+	).Scan(&def.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update report definition: %w", err)
+	}
+	return nil
+}
+
+// DeleteDefinition 刪除報表定義（產出紀錄由 ON DELETE CASCADE 一併刪除）
+func (r *ReportRepo) DeleteDefinition(ctx context.Context, id int64) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM report_definitions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete report definition: %w", err)
+	}
+	return nil
+}
+
+// FindDefinition 查詢報表定義
+func (r *ReportRepo) FindDefinition(ctx context.Context, id int64) (*entity.ReportDefinition, error) {
+	def, err := scanReportDefinition(r.db.Pool.QueryRow(ctx,
+		`SELECT `+reportDefinitionColumns+` FROM report_definitions WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report definition: %w", err)
+	}
+	return def, nil
+}
+
+// ListDefinitions 列出報表定義
+func (r *ReportRepo) ListDefinitions(ctx context.Context, offset, limit int) ([]*entity.ReportDefinition, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM report_definitions`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count report definitions: %w", err)
+	}
+
+	defs, err := r.queryDefinitions(ctx, `
+		SELECT `+reportDefinitionColumns+` FROM report_definitions
+		ORDER BY id DESC LIMIT $1 OFFSET $2`, limit, offset)
+	return defs, total, err
+}
+
+// ClaimDue 認領已到排程時間的啟用中報表：同一個 UPDATE 內以 SKIP LOCKED 鎖定並把 next_run_at 推到 leaseUntil，
+// 多個 worker 同時執行時每份報表只會被其中一個認領；認領後未呼叫 MarkScheduled（例如 worker 中斷）則 leaseUntil 後重新到期
+func (r *ReportRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time) ([]*entity.ReportDefinition, error) {
+	return r.queryDefinitions(ctx, `
+		UPDATE report_definitions SET next_run_at = $2
+		WHERE id IN (
+			SELECT id FROM report_definitions
+			WHERE is_active AND schedule <> '' AND next_run_at IS NOT NULL AND next_run_at <= $1
+			ORDER BY next_run_at
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+reportDefinitionColumns, now, leaseUntil)
+}
+
+// MarkScheduled 記錄最後產出時間與下次排程時間
+func (r *ReportRepo) MarkScheduled(ctx context.Context, id int64, lastRunAt time.Time, nextRunAt *time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE report_definitions SET last_run_at = $2, next_run_at = $3 WHERE id = $1`,
+		id, lastRunAt, nextRunAt)
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+	return nil
+}
+
+// CreateRun 建立產出紀錄
+func (r *ReportRepo) CreateRun(ctx context.Context, run *entity.ReportRun) error {
+	run.Status = entity.ReportRunRunning
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO report_runs (definition_id, status, trigger, period_start, period_end)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		run.DefinitionID, string(run.Status), run.Trigger, run.PeriodStart, run.PeriodEnd,
+	).Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create report run: %w", err)
+	}
+	return nil
+}
+
+// FinishRun 寫入產出結果
+func (r *ReportRepo) FinishRun(ctx context.Context, run *entity.ReportRun) error {
+	var html *string
+	if run.HTML != "" {
+		html = &run.HTML
+	}
+	var pdf []byte
+	if len(run.PDF) > 0 {
+		pdf = run.PDF
+	}
+	var errText *string
+	if run.Error != "" {
+		errText = &run.Error
+	}
+
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE report_runs SET
+			status = $2, html = $3, pdf = $4, error = $5, delivered_to = $6, finished_at = NOW()
+		WHERE id = $1
+		RETURNING finished_at`,
+		run.ID, string(run.Status), html, pdf, errText, nonNilStrings(run.DeliveredTo),
+	).Scan(&run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish report run: %w", err)
+	}
+	run.HasHTML = html != nil
+	run.HasPDF = pdf != nil
+	return nil
+}
+
+// FindRun 查詢產出紀錄
+func (r *ReportRepo) FindRun(ctx context.Context, id int64, withContent bool) (*entity.ReportRun, error) {
+	run, err := scanReportRun(r.db.Pool.QueryRow(ctx,
+		`SELECT `+reportRunColumns+` FROM report_runs WHERE id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report run: %w", err)
+	}
+
+	if withContent {
+		var html *string
+		if err := r.db.Pool.QueryRow(ctx, `SELECT html, pdf FROM report_runs WHERE id = $1`, id).Scan(&html, &run.PDF); err != nil {
+			return nil, fmt.Errorf("failed to load report content: %w", err)
+		}
+		if html != nil {
+			run.HTML = *html
+		}
+	}
+	return run, nil
+}
+
+// ListRuns 列出報表的產出紀錄
+func (r *ReportRepo) ListRuns(ctx context.Context, definitionID int64, offset, limit int) ([]*entity.ReportRun, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM report_runs WHERE definition_id = $1`, definitionID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count report runs: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+reportRunColumns+` FROM report_runs
+		WHERE definition_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, definitionID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query report runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*entity.ReportRun
+	for rows.Next() {
+		run, err := scanReportRun(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan report run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, total, rows.Err()
+}
+
+func (r *ReportRepo) queryDefinitions(ctx context.Context, query string, args ...any) ([]*entity.ReportDefinition, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var defs []*entity.ReportDefinition
+	for rows.Next() {
+		def, err := scanReportDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report definition: %w", err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, rows.Err()
+}
+
+func scanReportDefinition(row pgx.Row) (*entity.ReportDefinition, error) {
+	d := &entity.ReportDefinition{}
+	var sections, formats []string
+	err := row.Scan(&d.ID, &d.Name, &d.EntityIDs, &d.ClassIDs, &d.Period, &sections, &formats,
+		&d.Schedule, &d.Timezone, &d.Recipients, &d.IsActive, &d.LastRunAt, &d.NextRunAt,
+		&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sections {
+		d.Sections = append(d.Sections, entity.ReportSection(s))
+	}
+	for _, f := range formats {
+		d.Formats = append(d.Formats, entity.ReportFormat(f))
+	}
+	return d, nil
+}
+
+func scanReportRun(row pgx.Row) (*entity.ReportRun, error) {
+	run := &entity.ReportRun{}
+	var status string
+	err := row.Scan(&run.ID, &run.DefinitionID, &status, &run.Trigger, &run.PeriodStart, &run.PeriodEnd,
+		&run.HasHTML, &run.HasPDF, &run.Error, &run.DeliveredTo, &run.CreatedAt, &run.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	run.Status = entity.ReportRunStatus(status)
+	return run, nil
+}
+
+func sectionStrings(sections []entity.ReportSection) []string {
+	out := make([]string, len(sections))
+	for i, s := range sections {
+		out[i] = string(s)
+	}
+	return out
+}
+
+func formatStrings(formats []entity.ReportFormat) []string {
+	out := make([]string, len(formats))
+	for i, f := range formats {
+		out[i] = string(f)
+	}
+	return out
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nonNilInts(s []int) []int {
+	if s == nil {
+		return []int{}
+	}
+	return s
+}
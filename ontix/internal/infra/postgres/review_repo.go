@@ -0,0 +1,448 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReviewRepo PostgreSQL 實作的 ReviewRepository
+type ReviewRepo struct {
+	db *DB
+}
+
+// NewReviewRepo 建立 ReviewRepository
+func NewReviewRepo(db *DB) repository.ReviewRepository {
+	return &ReviewRepo{db: db}
+}
+
+// Enqueue 加入審核佇列
+func (r *ReviewRepo) Enqueue(ctx context.Context, item *entity.ReviewItem) error {
+	detailJSON, err := json.Marshal(item.Detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review detail: %w", err)
+	}
+
+	// 已審核的項目不重新排入；pending 的項目更新為最新的模型輸出
+	_, err = r.db.Pool.Exec(ctx, `
+		INSERT INTO review_items (post_id, reason, detail, priority)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (post_id, reason) DO UPDATE SET
+			detail = EXCLUDED.detail,
+			priority = EXCLUDED.priority
+		WHERE review_items.status = 'pending'`,
+		item.PostID, string(item.Reason), detailJSON, item.Priority)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue review item: %w", err)
+	}
+	return nil
+}
+
+// FindByID 查詢單一審核項目
+func (r *ReviewRepo) FindByID(ctx context.Context, id int64) (*entity.ReviewItem, error) {
+	query := `
+		SELECT ri.id, ri.post_id, ri.reason, ri.detail, ri.priority, ri.status,
+		       COALESCE(ri.reviewer, ''), ri.created_at, ri.resolved_at, COALESCE(p.content, '')
+		FROM review_items ri
+		LEFT JOIN posts p ON p.post_id = ri.post_id
+		WHERE ri.id = $1`
+
+	item, err := r.scanItem(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return item, err
+}
+
+// List 依狀態 / 原因列出審核項目
+func (r *ReviewRepo) List(ctx context.Context, status entity.ReviewStatus, reason entity.ReviewReason, offset, limit int) ([]*entity.ReviewItem, int, error) {
+	where := []string{"ri.status = $1"}
+	args := []any{string(status)}
+	if reason != "" {
+		where = append(where, "ri.reason = $2")
+		args = append(args, string(reason))
+	}
+	whereSQL := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM review_items ri`+whereSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count review items: %w", err)
+	}
+
+	argIdx := len(args) + 1
+	query := `
+		SELECT ri.id, ri.post_id, ri.reason, ri.detail, ri.priority, ri.status,
+		       COALESCE(ri.reviewer, ''), ri.created_at, ri.resolved_at, COALESCE(p.content, '')
+		FROM review_items ri
+		LEFT JOIN posts p ON p.post_id = ri.post_id` + whereSQL + `
+		ORDER BY ri.priority DESC, ri.created_at ASC
+		LIMIT $` + strconv.Itoa(argIdx) + ` OFFSET $` + strconv.Itoa(argIdx+1)
+
+	rows, err := r.db.Pool.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query review items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*entity.ReviewItem
+	for rows.Next() {
+		item, err := r.scanItem(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// CountByReason 各原因的 pending 數量
+func (r *ReviewRepo) CountByReason(ctx context.Context) (map[entity.ReviewReason]int, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT reason, COUNT(*) FROM review_items
+		WHERE status = 'pending'
+		GROUP BY reason`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count review items: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[entity.ReviewReason]int)
+	for rows.Next() {
+		var reason string
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan review count: %w", err)
+		}
+		counts[entity.ReviewReason(reason)] = count
+	}
+	return counts, nil
+}
+
+// Resolve 套用校正並標記為已審核
+func (r *ReviewRepo) Resolve(ctx context.Context, id int64, c *entity.ReviewCorrection) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var postID, reason, status string
+	err = tx.QueryRow(ctx, `SELECT post_id, reason, status FROM review_items WHERE id = $1 FOR UPDATE`, id).Scan(&postID, &reason, &status)
+	if err == pgx.ErrNoRows {
+		return repository.ErrReviewItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock review item: %w", err)
+	}
+	if status != string(entity.ReviewStatusPending) {
+		return repository.ErrReviewItemClosed
+	}
+
+	// 1. 主題：未指定且原因為主題相關 → 確認現有主題
+	topicReason := reason == string(entity.ReviewReasonAmbiguousTopic) || reason == string(entity.ReviewReasonLowConfidence)
+	if c.Topics != nil || topicReason {
+		if err := r.applyTopics(ctx, tx, id, postID, c.Topics, c.Reviewer); err != nil {
+			return err
+		}
+	}
+
+	// 2. 軟標籤（整組替換）
+	if c.Tags != nil {
+		if err := r.applyTags(ctx, tx, id, postID, c.Tags, c.Reviewer); err != nil {
+			return err
+		}
+	}
+
+	// 3. 整體情感
+	if c.Sentiment != nil {
+		if err := r.applySentiment(ctx, tx, id, postID, c.Sentiment, c.Reviewer); err != nil {
+			return err
+		}
+	}
+
+	// 4. Entity 提及
+	for _, m := range c.Mentions {
+		if err := r.applyMention(ctx, tx, id, postID, m, c.Reviewer); err != nil {
+			return err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE review_items SET status = 'resolved', reviewer = $2, resolved_at = NOW()
+		WHERE id = $1 AND status = 'pending'`, id, c.Reviewer)
+	if err != nil {
+		return fmt.Errorf("failed to resolve review item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrReviewItemClosed
+	}
+
+	return tx.Commit(ctx)
+}
+
+// applyTopics 以人工主題集合取代 post_topics，並寫入 gold label（供機率校準）
+// topics 為 nil 表示確認現有主題
+func (r *ReviewRepo) applyTopics(ctx context.Context, tx pgx.Tx, itemID int64, postID string, topics []int, reviewer string) error {
+	numericID, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid post id %q for topic correction: %w", postID, err)
+	}
+
+	var before []int
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(DISTINCT topic_id), '{}') FROM post_topics WHERE post_id = $1`,
+		numericID).Scan(&before); err != nil {
+		return fmt.Errorf("failed to load current topics: %w", err)
+	}
+	if topics == nil {
+		topics = before
+	}
+
+	// gold label：列出的主題為正樣本，其餘 active 主題為負樣本
+	_, err = tx.Exec(ctx, `
+		INSERT INTO post_topic_labels (post_id, topic_id, is_relevant, source, created_at)
+		SELECT $1, t.id, t.id = ANY($2::int[]), $3, NOW()
+		FROM topics t WHERE t.is_active = true
+		ON CONFLICT (post_id, topic_id) DO UPDATE SET
+			is_relevant = EXCLUDED.is_relevant,
+			source = EXCLUDED.source,
+			created_at = EXCLUDED.created_at`,
+		numericID, topics, string(entity.TagSourceManual))
+	if err != nil {
+		return fmt.Errorf("failed to save topic labels: %w", err)
+	}
+
+	// post_topics：移除錯誤主題、保留並提升正確主題、補上缺少的主題
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM post_topics WHERE post_id = $1 AND NOT (topic_id = ANY($2::int[]))`,
+		numericID, topics); err != nil {
+		return fmt.Errorf("failed to delete post topics: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE post_topics SET confidence = 'high', probability = 1, is_ambiguous = false
+		WHERE post_id = $1`, numericID); err != nil {
+		return fmt.Errorf("failed to update post topics: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO post_topics (post_id, topic_id, similarity, confidence, is_ambiguous, keyword_boost, probability, assigned_at)
+		SELECT $1, t, 1.0, 'high', false, 0, 1, NOW()
+		FROM unnest($2::int[]) AS t
+		WHERE NOT EXISTS (SELECT 1 FROM post_topics pt WHERE pt.post_id = $1 AND pt.topic_id = t)`,
+		numericID, topics); err != nil {
+		return fmt.Errorf("failed to insert post topics: %w", err)
+	}
+
+	return r.saveCorrection(ctx, tx, itemID, postID, entity.CorrectionFieldTopic,
+		map[string]any{"topic_ids": before}, map[string]any{"topic_ids": topics}, reviewer)
+}
+
+// applyTags 以人工軟標籤取代 LLM 軟標籤
+func (r *ReviewRepo) applyTags(ctx context.Context, tx pgx.Tx, itemID int64, postID string, tags []string, reviewer string) error {
+	var before []string
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(tag ORDER BY confidence DESC), '{}') FROM post_soft_tags WHERE post_id = $1`,
+		postID).Scan(&before); err != nil {
+		return fmt.Errorf("failed to load current tags: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM post_soft_tags WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to delete soft tags: %w", err)
+	}
+	for _, tag := range tags {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO post_soft_tags (post_id, tag, confidence, source, created_at)
+			VALUES ($1, $2, 1.0, $3, $4)
+			ON CONFLICT (post_id, tag) DO NOTHING`,
+			postID, tag, string(entity.TagSourceManual), time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to save soft tag: %w", err)
+		}
+	}
+
+	return r.saveCorrection(ctx, tx, itemID, postID, entity.CorrectionFieldTags,
+		map[string]any{"tags": before}, map[string]any{"tags": tags}, reviewer)
+}
+
+// applySentiment 更新貼文整體情感（標記 sentiment_source=manual，LLM 重跑不覆蓋）
+func (r *ReviewRepo) applySentiment(ctx context.Context, tx pgx.Tx, itemID int64, postID string, s *entity.SentimentCorrection, reviewer string) error {
+	var beforeLabel string
+	var beforeScore float64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(sentiment, ''), COALESCE(sentiment_score, 0) FROM posts WHERE post_id = $1`,
+		postID).Scan(&beforeLabel, &beforeScore); err != nil {
+		return fmt.Errorf("failed to load current sentiment: %w", err)
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE posts SET sentiment = $2, sentiment_score = $3, sentiment_reason = '人工校正', sentiment_source = $4
+		WHERE post_id = $1`, postID, s.Label, s.Score, string(entity.TagSourceManual))
+	if err != nil {
+		return fmt.Errorf("failed to update sentiment: %w", err)
+	}
+
+	return r.saveCorrection(ctx, tx, itemID, postID, entity.CorrectionFieldSentiment,
+		map[string]any{"label": beforeLabel, "score": beforeScore},
+		map[string]any{"label": s.Label, "score": s.Score}, reviewer)
+}
+
+// applyMention 新增 / 修正 / 刪除 Entity 提及（source=manual）
+func (r *ReviewRepo) applyMention(ctx context.Context, tx pgx.Tx, itemID int64, postID string, m entity.MentionCorrection, reviewer string) error {
+	before := map[string]any{"object_id": m.ObjectID}
+	var sentiment, mentionText, source string
+	var score float64
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(sentiment, ''), COALESCE(sentiment_score, 0), COALESCE(mention_text, ''), source
+		FROM post_entity_mentions WHERE post_id = $1 AND object_id = $2`,
+		postID, m.ObjectID).Scan(&sentiment, &score, &mentionText, &source)
+	switch {
+	case err == pgx.ErrNoRows:
+		before = nil
+	case err != nil:
+		return fmt.Errorf("failed to load current mention: %w", err)
+	default:
+		before["sentiment"] = sentiment
+		before["sentiment_score"] = score
+		before["mention_text"] = mentionText
+		before["source"] = source
+	}
+
+	after := map[string]any{
+		"object_id":       m.ObjectID,
+		"sentiment":       m.Sentiment,
+		"sentiment_score": m.SentimentScore,
+		"mention_text":    m.MentionText,
+		"remove":          m.Remove,
+	}
+
+	if m.Remove {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM post_entity_mentions WHERE post_id = $1 AND object_id = $2`,
+			postID, m.ObjectID); err != nil {
+			return fmt.Errorf("failed to delete mention: %w", err)
+		}
+	} else {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO post_entity_mentions (post_id, object_id, sentiment, sentiment_score, mention_text, source)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (post_id, object_id) DO UPDATE SET
+				sentiment = EXCLUDED.sentiment,
+				sentiment_score = EXCLUDED.sentiment_score,
+				mention_text = COALESCE(NULLIF(EXCLUDED.mention_text, ''), post_entity_mentions.mention_text),
+				source = EXCLUDED.source`,
+			postID, m.ObjectID, m.Sentiment, m.SentimentScore, m.MentionText, string(entity.TagSourceManual))
+		if err != nil {
+			return fmt.Errorf("failed to save mention: %w", err)
+		}
+	}
+
+	return r.saveCorrection(ctx, tx, itemID, postID, entity.CorrectionFieldMention, before, after, reviewer)
+}
+
+// saveCorrection 寫入校正紀錄
+func (r *ReviewRepo) saveCorrection(ctx context.Context, tx pgx.Tx, itemID int64, postID string, field entity.CorrectionField, before, after map[string]any, reviewer string) error {
+	var beforeJSON []byte
+	if before != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return fmt.Errorf("failed to marshal correction: %w", err)
+		}
+		beforeJSON = b
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return fmt.Errorf("failed to marshal correction: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO review_corrections (review_item_id, post_id, field, before, after, reviewer, source)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		itemID, postID, string(field), beforeJSON, afterJSON, reviewer, string(entity.TagSourceManual))
+	if err != nil {
+		return fmt.Errorf("failed to save correction: %w", err)
+	}
+	return nil
+}
+
+// Skip 略過審核項目
+func (r *ReviewRepo) Skip(ctx context.Context, id int64, reviewer string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE review_items SET status = 'skipped', reviewer = $2, resolved_at = NOW()
+		WHERE id = $1 AND status = 'pending'`, id, reviewer)
+	if err != nil {
+		return fmt.Errorf("failed to skip review item: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// 沒有更新任何列：區分項目不存在與已審核 / 已略過
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM review_items WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check review item: %w", err)
+	}
+	if !exists {
+		return repository.ErrReviewItemNotFound
+	}
+	return repository.ErrReviewItemClosed
+}
+
+// ListFewShotExamples 取得最近的人工校正範例（每篇貼文取最新一筆）
+func (r *ReviewRepo) ListFewShotExamples(ctx context.Context, field entity.CorrectionField, limit int) ([]*entity.FewShotExample, error) {
+	query := `
+		SELECT post_id, content, after FROM (
+			SELECT DISTINCT ON (rc.post_id) rc.post_id, p.content, rc.after, rc.created_at
+			FROM review_corrections rc
+			JOIN posts p ON p.post_id = rc.post_id
+			WHERE rc.field = $1 AND rc.source = $3
+			ORDER BY rc.post_id, rc.created_at DESC
+		) latest
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.Pool.Query(ctx, query, string(field), limit, string(entity.TagSourceManual))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query few-shot examples: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []*entity.FewShotExample
+	for rows.Next() {
+		ex := &entity.FewShotExample{Field: field}
+		var after []byte
+		if err := rows.Scan(&ex.PostID, &ex.Content, &after); err != nil {
+			return nil, fmt.Errorf("failed to scan few-shot example: %w", err)
+		}
+		if after != nil {
+			_ = json.Unmarshal(after, &ex.After)
+		}
+		examples = append(examples, ex)
+	}
+	return examples, nil
+}
+
+func (r *ReviewRepo) scanItem(row pgx.Row) (*entity.ReviewItem, error) {
+	var item entity.ReviewItem
+	var reason, status string
+	var detail []byte
+
+	if err := row.Scan(
+		&item.ID, &item.PostID, &reason, &detail, &item.Priority, &status,
+		&item.Reviewer, &item.CreatedAt, &item.ResolvedAt, &item.Content,
+	); err != nil {
+		return nil, err
+	}
+
+	item.Reason = entity.ReviewReason(reason)
+	item.Status = entity.ReviewStatus(status)
+	if detail != nil {
+		_ = json.Unmarshal(detail, &item.Detail)
+	}
+	return &item, nil
+}
@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/pgvector/pgvector-go"
+)
+
+// rrfK reciprocal rank fusion 常數（score = Σ 1 / (k + rank)）
+const rrfK = 60
+
+// facetLimit 高基數 facet（topic / entity / author / tag）最多回傳的值數
+const facetLimit = 20
+
+// SearchRepo PostgreSQL 實作的 SearchRepository
+type SearchRepo struct {
+	db *DB
+}
+
+// NewSearchRepo 建立 SearchRepository
+func NewSearchRepo(db *DB) repository.SearchRepository {
+	return &SearchRepo{db: db}
+}
+
+// searchArgs 依序累積 SQL 參數並回傳 placeholder
+type searchArgs []any
+
+func (a *searchArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// HybridSearch 向量 + 關鍵字混合搜尋
+//
+// 命中集合 = 向量前 VectorK 名（相似度 >= MinSimilarity）∪ 所有關鍵字命中，兩者都套用篩選條件；
+// 排序以 RRF 融合兩邊的名次，facet 與總數在完整命中集合上計算，分頁使用 (score, post_id) 游標
+func (r *SearchRepo) HybridSearch(ctx context.Context, q *entity.SearchQuery) (*entity.SearchPage, error) {
+	var args searchArgs
+	cte := r.buildMatchCTE(q, &args)
+
+	// 1. 當頁結果（多取一筆判斷是否有下一頁）
+	pageArgs := append(searchArgs{}, args...)
+	cursorSQL := ""
+	if q.Cursor != nil {
+		score := pageArgs.add(q.Cursor.Score)
+		postID := pageArgs.add(q.Cursor.PostID)
+		cursorSQL = fmt.Sprintf("WHERE (f.score < %s OR (f.score = %s AND f.post_id > %s))", score, score, postID)
+	}
+	limit := pageArgs.add(q.Limit + 1)
+
+	pageSQL := cte + `
+		SELECT f.post_id, p.content, p.platform, COALESCE(p.language, ''), COALESCE(p.author_username, ''), p.created_at,
+			COALESCE(p.sentiment, ''), COALESCE(p.intent, ''),
+			COALESCE((SELECT (array_agg(pst.tag::text ORDER BY pst.confidence DESC))[1:5]
+				FROM post_soft_tags pst WHERE pst.post_id = f.post_id), '{}'::text[]),
+			f.score, f.similarity, f.keyword_score
+		FROM fused f
+		JOIN posts p ON p.post_id = f.post_id
+		` + cursorSQL + `
+		ORDER BY f.score DESC, f.post_id ASC
+		LIMIT ` + limit
+
+	rows, err := r.db.Pool.Query(ctx, pageSQL, pageArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run hybrid search: %w", err)
+	}
+	defer rows.Close()
+
+	page := &entity.SearchPage{Hits: []*entity.SearchHit{}}
+	for rows.Next() {
+		h := &entity.SearchHit{}
+		if err := rows.Scan(&h.PostID, &h.Content, &h.Platform, &h.Language, &h.AuthorUsername, &h.CreatedAt,
+			&h.Sentiment, &h.Intent, &h.SoftTags, &h.Score, &h.Similarity, &h.KeywordScore); err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		page.Hits = append(page.Hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search hits: %w", err)
+	}
+
+	if len(page.Hits) > q.Limit {
+		page.Hits = page.Hits[:q.Limit]
+		last := page.Hits[len(page.Hits)-1]
+		page.NextCursor = &entity.SearchCursor{Score: last.Score, PostID: last.PostID}
+	}
+
+	// 2. 總數 + facet（完整命中集合）
+	total, facets, err := r.facets(ctx, cte, args)
+	if err != nil {
+		return nil, err
+	}
+	page.Total = total
+	page.Facets = facets
+	return page, nil
+}
+
+// buildMatchCTE 建立 vec / kw / fused 三段 CTE
+func (r *SearchRepo) buildMatchCTE(q *entity.SearchQuery, args *searchArgs) string {
+	filterSQL := buildSearchFilter(&q.Filter, args)
+
+	vecSQL := `SELECT NULL::varchar AS post_id, NULL::float8 AS similarity, NULL::bigint AS rnk WHERE false`
+	if q.Mode != entity.SearchModeKeyword && len(q.Embedding) > 0 {
+		vec := args.add(pgvector.NewVector(q.Embedding))
+		// 貼文向量與長文段落向量各取 top-k，同一貼文取最高相似度
+		vecSQL = fmt.Sprintf(`
+			SELECT c.post_id, c.similarity, ROW_NUMBER() OVER (ORDER BY c.similarity DESC, c.post_id) AS rnk
+			FROM (
+				SELECT u.post_id, MAX(u.similarity) AS similarity
+				FROM (
+					(SELECT p.post_id, (1 - (pe.embedding <=> %[1]s::vector))::float8 AS similarity
+					FROM post_embeddings pe
+					JOIN posts p ON p.post_id = pe.post_id
+					WHERE true%[2]s
+					ORDER BY pe.embedding <=> %[1]s::vector
+					LIMIT %[3]s)
+					UNION ALL
+					(SELECT p.post_id, (1 - (pc.embedding <=> %[1]s::vector))::float8 AS similarity
+					FROM post_chunks pc
+					JOIN posts p ON p.post_id = pc.post_id
+					WHERE pc.embedding IS NOT NULL%[2]s
+					ORDER BY pc.embedding <=> %[1]s::vector
+					LIMIT %[3]s)
+				) u
+				GROUP BY u.post_id
+			) c
+			WHERE c.similarity >= %[4]s`,
+			vec, filterSQL, args.add(q.VectorK), args.add(q.MinSimilarity))
+	}
+
+	kwSQL := `SELECT NULL::varchar AS post_id, NULL::float8 AS score, NULL::bigint AS rnk WHERE false`
+	if q.Mode != entity.SearchModeVector && len(q.Terms) > 0 {
+		text := args.add(q.Text)
+		patterns := make([]string, len(q.Terms))
+		for i, t := range q.Terms {
+			patterns[i] = "%" + escapeLike(t) + "%"
+		}
+		kwSQL = fmt.Sprintf(`
+			SELECT c.post_id, c.score, ROW_NUMBER() OVER (ORDER BY c.score DESC, c.post_id) AS rnk
+			FROM (
+				SELECT p.post_id,
+					(ts_rank(p.content_tsv, plainto_tsquery('simple', %[1]s)) + word_similarity(%[1]s, p.content))::float8 AS score
+				FROM posts p
+				WHERE (p.content ILIKE ALL(%[2]s::text[]) OR p.content_tsv @@ plainto_tsquery('simple', %[1]s))%[3]s
+			) c`,
+			text, args.add(patterns), filterSQL)
+	}
+
+	return fmt.Sprintf(`
+		WITH vec AS (%s),
+		kw AS (%s),
+		fused AS (
+			SELECT COALESCE(v.post_id, k.post_id) AS post_id,
+				COALESCE(1.0::float8 / (%d + v.rnk), 0) + COALESCE(1.0::float8 / (%d + k.rnk), 0) AS score,
+				v.similarity,
+				k.score AS keyword_score
+			FROM vec v
+			FULL OUTER JOIN kw k ON k.post_id = v.post_id
+		)`, vecSQL, kwSQL, rrfK, rrfK)
+}
+
+// buildSearchFilter 篩選條件（以 " AND ..." 形式附加在 posts p 的 WHERE 後）
+func buildSearchFilter(f *entity.SearchFilter, args *searchArgs) string {
+	var sb strings.Builder
+	if len(f.Platforms) > 0 {
+		fmt.Fprintf(&sb, " AND p.platform = ANY(%s::text[])", args.add(f.Platforms))
+	}
+	if len(f.Languages) > 0 {
+		fmt.Fprintf(&sb, " AND p.language = ANY(%s::text[])", args.add(f.Languages))
+	}
+	if f.From != nil {
+		fmt.Fprintf(&sb, " AND p.created_at >= %s", args.add(*f.From))
+	}
+	if f.To != nil {
+		fmt.Fprintf(&sb, " AND p.created_at < %s", args.add(*f.To))
+	}
+	if len(f.Sentiments) > 0 {
+		fmt.Fprintf(&sb, " AND p.sentiment = ANY(%s::text[])", args.add(f.Sentiments))
+	}
+	if len(f.Intents) > 0 {
+		fmt.Fprintf(&sb, " AND p.intent = ANY(%s::text[])", args.add(f.Intents))
+	}
+	if len(f.Authors) > 0 {
+		authors := args.add(f.Authors)
+		fmt.Fprintf(&sb, " AND (p.author_username = ANY(%[1]s::text[]) OR p.author_id = ANY(%[1]s::text[]))", authors)
+	}
+	if len(f.TopicCodes) > 0 {
+		// post_topics.post_id 為 BIGINT，轉為文字比對 posts.post_id
+		fmt.Fprintf(&sb, ` AND p.post_id IN (
+			SELECT pt.post_id::text FROM post_topics pt JOIN topics t ON t.id = pt.topic_id
+			WHERE t.code = ANY(%s::text[]))`, args.add(f.TopicCodes))
+	}
+	if len(f.EntityIDs) > 0 {
+		fmt.Fprintf(&sb, ` AND p.post_id IN (
+			SELECT pem.post_id FROM post_entity_mentions pem WHERE pem.object_id::text = ANY(%s::text[]))`,
+			args.add(f.EntityIDs))
+	}
+	return sb.String()
+}
+
+// facets 在完整命中集合上計算總數與各 facet
+func (r *SearchRepo) facets(ctx context.Context, cte string, args searchArgs) (int, *entity.SearchFacets, error) {
+	query := cte + fmt.Sprintf(`,
+		m AS (
+			SELECT f.post_id, p.platform, p.language, p.sentiment, p.intent, p.author_username
+			FROM fused f
+			JOIN posts p ON p.post_id = f.post_id
+		)
+		SELECT 'total', '', '', COUNT(*) FROM m
+		UNION ALL
+		SELECT 'platform', platform, '', COUNT(*) FROM m GROUP BY platform
+		UNION ALL
+		SELECT 'language', language, '', COUNT(*) FROM m WHERE COALESCE(language, '') <> '' GROUP BY language
+		UNION ALL
+		SELECT 'sentiment', sentiment, '', COUNT(*) FROM m WHERE sentiment IS NOT NULL GROUP BY sentiment
+		UNION ALL
+		SELECT 'intent', intent, '', COUNT(*) FROM m WHERE intent IS NOT NULL GROUP BY intent
+		UNION ALL
+		(SELECT 'topic', t.code, t.name, COUNT(DISTINCT m.post_id)
+		 FROM m
+		 JOIN post_topics pt ON pt.post_id::text = m.post_id
+		 JOIN topics t ON t.id = pt.topic_id
+		 GROUP BY t.code, t.name ORDER BY 4 DESC LIMIT %[1]d)
+		UNION ALL
+		(SELECT 'entity', o.id::text, o.canonical_name, COUNT(DISTINCT m.post_id)
+		 FROM m
+		 JOIN post_entity_mentions pem ON pem.post_id = m.post_id
+		 JOIN objects o ON o.id = pem.object_id
+		 GROUP BY o.id, o.canonical_name ORDER BY 4 DESC LIMIT %[1]d)
+		UNION ALL
+		(SELECT 'author', author_username, '', COUNT(*)
+		 FROM m WHERE COALESCE(author_username, '') <> ''
+		 GROUP BY author_username ORDER BY 4 DESC LIMIT %[1]d)
+		UNION ALL
+		(SELECT 'tag', pst.tag, '', COUNT(DISTINCT m.post_id)
+		 FROM m
+		 JOIN post_soft_tags pst ON pst.post_id = m.post_id
+		 GROUP BY pst.tag ORDER BY 4 DESC LIMIT %[1]d)`, facetLimit)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to compute search facets: %w", err)
+	}
+	defer rows.Close()
+
+	total := 0
+	facets := &entity.SearchFacets{
+		Platforms:  []entity.FacetCount{},
+		Sentiments: []entity.FacetCount{},
+		Intents:    []entity.FacetCount{},
+		Topics:     []entity.FacetCount{},
+		Entities:   []entity.FacetCount{},
+		Authors:    []entity.FacetCount{},
+		Tags:       []entity.FacetCount{},
+		Languages:  []entity.FacetCount{},
+	}
+	for rows.Next() {
+		var kind string
+		var fc entity.FacetCount
+		if err := rows.Scan(&kind, &fc.Value, &fc.Label, &fc.Count); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan facet: %w", err)
+		}
+		switch kind {
+		case "total":
+			total = fc.Count
+		case "platform":
+			facets.Platforms = append(facets.Platforms, fc)
+		case "language":
+			fc.Label = entity.Language(fc.Value).Name()
+			facets.Languages = append(facets.Languages, fc)
+		case "sentiment":
+			facets.Sentiments = append(facets.Sentiments, fc)
+		case "intent":
+			facets.Intents = append(facets.Intents, fc)
+		case "topic":
+			facets.Topics = append(facets.Topics, fc)
+		case "entity":
+			facets.Entities = append(facets.Entities, fc)
+		case "author":
+			facets.Authors = append(facets.Authors, fc)
+		case "tag":
+			facets.Tags = append(facets.Tags, fc)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to iterate facets: %w", err)
+	}
+
+	for _, list := range [][]entity.FacetCount{
+		facets.Platforms, facets.Sentiments, facets.Intents, facets.Topics,
+		facets.Entities, facets.Authors, facets.Tags, facets.Languages,
+	} {
+		sortFacets(list)
+	}
+	return total, facets, nil
+}
+
+// NearestTopics 與查詢向量最接近的主題
+func (r *SearchRepo) NearestTopics(ctx context.Context, embedding []float32, limit int) ([]*entity.TopicNeighbor, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT code, name, (1 - (embedding <=> $1::vector))::float8 AS similarity
+		FROM topics
+		WHERE is_active = true AND embedding IS NOT NULL
+		ORDER BY embedding <=> $1::vector
+		LIMIT $2`, pgvector.NewVector(embedding), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nearest topics: %w", err)
+	}
+	defer rows.Close()
+
+	var neighbors []*entity.TopicNeighbor
+	for rows.Next() {
+		n := &entity.TopicNeighbor{}
+		if err := rows.Scan(&n.Code, &n.Name, &n.Similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan topic neighbor: %w", err)
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, rows.Err()
+}
+
+// sortFacets 依計數由大到小，同數依值排序（結果穩定）
+func sortFacets(list []entity.FacetCount) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Value < list[j].Value
+	})
+}
+
+// escapeLike 跳脫 LIKE 特殊字元
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
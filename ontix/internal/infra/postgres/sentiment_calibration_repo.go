@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// SentimentCalibrationRepo 情緒分數校準 PostgreSQL 實作
+type SentimentCalibrationRepo struct {
+	db *DB
+}
+
+// NewSentimentCalibrationRepo 建立 SentimentCalibrationRepo
+func NewSentimentCalibrationRepo(db *DB) repository.SentimentCalibrationRepository {
+	return &SentimentCalibrationRepo{db: db}
+}
+
+// sentimentScoreTables 可重新計分的資料表與額外條件（提及只重算 LLM 產生的）
+var sentimentScoreTables = map[string]struct{ table, filter string }{
+	"mention": {"post_entity_mentions", "AND t.source = 'llm'"},
+	"aspect":  {"entity_aspects", ""},
+}
+
+// ListSamples 保留 LLM 原始分數與模型的標註提及（語言取自貼文）：
+//   - 人工校正過的提及（source = manual），目標為校正後的標籤 / 分數
+//   - 審核時確認無誤的提及：貼文的審核項目已處理、提及在審核前就存在且未被修改（仍為 source = llm），
+//     目標為原始分數（只有校正樣本時，擬合會偏向 LLM 判錯的案例）
+func (r *SentimentCalibrationRepo) ListSamples(ctx context.Context) ([]*entity.SentimentSample, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT m.model, m.prompt_version, COALESCE(p.language, ''),
+		       m.raw_sentiment_score, COALESCE(m.sentiment, ''),
+		       CASE WHEN m.source = 'manual' THEN COALESCE(m.sentiment_score, 0) ELSE m.raw_sentiment_score END
+		FROM post_entity_mentions m
+		LEFT JOIN posts p ON p.post_id = m.post_id
+		WHERE m.raw_sentiment_score IS NOT NULL
+		  AND m.model IS NOT NULL AND m.prompt_version IS NOT NULL
+		  AND (
+			m.source = 'manual'
+			OR (m.source = 'llm' AND EXISTS (
+				SELECT 1 FROM review_items ri
+				WHERE ri.post_id = m.post_id AND ri.status = 'resolved' AND ri.resolved_at >= m.created_at
+			))
+		  )`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []*entity.SentimentSample
+	for rows.Next() {
+		var s entity.SentimentSample
+		var label string
+		var score float64
+		if err := rows.Scan(&s.Model, &s.PromptVersion, &s.Language, &s.Raw, &label, &score); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment sample: %w", err)
+		}
+		target, ok := entity.SentimentTarget(label, score)
+		if !ok {
+			continue
+		}
+		s.Target = target
+		samples = append(samples, &s)
+	}
+	return samples, rows.Err()
+}
+
+// SaveCalibration 新增校準並停用同一 key 的舊校準
+func (r *SentimentCalibrationRepo) SaveCalibration(ctx context.Context, cal *entity.SentimentCalibration) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE sentiment_calibrations SET is_active = FALSE
+		WHERE model = $1 AND prompt_version = $2 AND language = $3 AND is_active`,
+		cal.Model, cal.PromptVersion, cal.Language); err != nil {
+		return fmt.Errorf("failed to deactivate sentiment calibration: %w", err)
+	}
+
+	fittedAt := cal.FittedAt
+	if fittedAt.IsZero() {
+		fittedAt = time.Now()
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO sentiment_calibrations
+			(model, prompt_version, language, raw_knots, calibrated_knots, sample_count, mae_before, mae_after, is_active, fitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, TRUE, $9)
+		RETURNING id`,
+		cal.Model, cal.PromptVersion, cal.Language, cal.RawKnots, cal.CalibratedKnots,
+		cal.SampleCount, cal.MAEBefore, cal.MAEAfter, fittedAt,
+	).Scan(&cal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save sentiment calibration: %w", err)
+	}
+	cal.IsActive = true
+	cal.FittedAt = fittedAt
+	return tx.Commit(ctx)
+}
+
+// ListActive 所有啟用中的校準
+func (r *SentimentCalibrationRepo) ListActive(ctx context.Context) ([]*entity.SentimentCalibration, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, model, prompt_version, language, raw_knots, calibrated_knots, sample_count,
+		       COALESCE(mae_before, 0), COALESCE(mae_after, 0), is_active, fitted_at
+		FROM sentiment_calibrations
+		WHERE is_active
+		ORDER BY model, prompt_version, language`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment calibrations: %w", err)
+	}
+	defer rows.Close()
+
+	var cals []*entity.SentimentCalibration
+	for rows.Next() {
+		var c entity.SentimentCalibration
+		if err := rows.Scan(&c.ID, &c.Model, &c.PromptVersion, &c.Language, &c.RawKnots, &c.CalibratedKnots,
+			&c.SampleCount, &c.MAEBefore, &c.MAEAfter, &c.IsActive, &c.FittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment calibration: %w", err)
+		}
+		cals = append(cals, &c)
+	}
+	return cals, rows.Err()
+}
+
+// ListStale 某 model / prompt_version（/ 語言）下尚未套用 calibrationID 的資料，語言取自貼文
+func (r *SentimentCalibrationRepo) ListStale(ctx context.Context, kind, model, promptVersion, language string, excludeLanguages []string, calibrationID int64, afterID int64, limit int) ([]*repository.SentimentScoreRow, error) {
+	t, ok := sentimentScoreTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown sentiment kind %q", kind)
+	}
+	if excludeLanguages == nil {
+		excludeLanguages = []string{} // NULL 陣列會讓 NOT ... = ANY 整段變成 NULL
+	}
+	rows, err := r.db.Pool.Query(ctx, fmt.Sprintf(`
+		SELECT t.id, t.raw_sentiment_score, COALESCE(t.sentiment, ''), COALESCE(t.created_at, NOW())
+		FROM %s t
+		LEFT JOIN posts p ON p.post_id = t.post_id
+		WHERE t.model = $1 AND t.prompt_version = $2
+		  AND t.raw_sentiment_score IS NOT NULL
+		  AND t.calibration_id IS DISTINCT FROM $3
+		  AND t.id > $4 %s
+		  AND ($6::text = '' OR p.language = $6::text)
+		  AND NOT (COALESCE(p.language, '') = ANY($7::text[]))
+		ORDER BY t.id
+		LIMIT $5`, t.table, t.filter),
+		model, promptVersion, calibrationID, afterID, limit, language, excludeLanguages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale %ss: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var out []*repository.SentimentScoreRow
+	for rows.Next() {
+		var row repository.SentimentScoreRow
+		if err := rows.Scan(&row.ID, &row.Raw, &row.Sentiment, &row.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stale %s: %w", kind, err)
+		}
+		out = append(out, &row)
+	}
+	return out, rows.Err()
+}
+
+// UpdateScores 寫入校準後分數、標籤與 calibrationID
+func (r *SentimentCalibrationRepo) UpdateScores(ctx context.Context, kind string, ids []int64, scores []float64, labels []string, calibrationID int64) error {
+	t, ok := sentimentScoreTables[kind]
+	if !ok {
+		return fmt.Errorf("unknown sentiment kind %q", kind)
+	}
+	_, err := r.db.Pool.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s t SET sentiment_score = u.score, sentiment = u.label, calibration_id = $4
+		FROM unnest($1::bigint[], $2::real[], $3::text[]) AS u(id, score, label)
+		WHERE t.id = u.id`, t.table),
+		ids, scores, labels, calibrationID)
+	if err != nil {
+		return fmt.Errorf("failed to update %s scores: %w", kind, err)
+	}
+	return nil
+}
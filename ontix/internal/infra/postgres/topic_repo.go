@@ -113,8 +113,8 @@ func (r *TopicRepo) UpdateEmbedding(ctx context.Context, id int, embedding entit
 // SavePostTopic 儲存貼文-主題關聯
 func (r *TopicRepo) SavePostTopic(ctx context.Context, pt *entity.PostTopic) error {
 	query := `
-		INSERT INTO post_topics (post_id, topic_id, similarity, confidence, is_ambiguous, keyword_boost, assigned_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO post_topics (post_id, topic_id, similarity, confidence, is_ambiguous, keyword_boost, probability, assigned_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7::real, 0), $8)
 		ON CONFLICT DO NOTHING`
 
 	assignedAt := pt.AssignedAt
@@ -128,7 +128,7 @@ func (r *TopicRepo) SavePostTopic(ctx context.Context, pt *entity.PostTopic) err
 	}
 
 	_, err := r.db.Pool.Exec(ctx, query,
-		pt.PostID, pt.TopicID, pt.Similarity, confidence, pt.IsAmbiguous, pt.KeywordBoost, assignedAt,
+		pt.PostID, pt.TopicID, pt.Similarity, confidence, pt.IsAmbiguous, pt.KeywordBoost, pt.Probability, assignedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save post topic: %w", err)
@@ -136,13 +136,65 @@ func (r *TopicRepo) SavePostTopic(ctx context.Context, pt *entity.PostTopic) err
 	return nil
 }
 
+// ReplacePostTopics 以新的分配結果取代貼文的自動主題（同一交易內先刪後寫，重跑分配不會累積重複列）
+// 人工審核確認的主題（post_topic_labels.source = manual）保留，人工判定不相關的主題不再寫入
+func (r *TopicRepo) ReplacePostTopics(ctx context.Context, postID int64, pts []*entity.PostTopic) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM post_topics pt
+		WHERE pt.post_id = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM post_topic_labels l
+			WHERE l.post_id = pt.post_id AND l.topic_id = pt.topic_id
+			  AND l.source = $2 AND l.is_relevant
+		  )`, postID, string(entity.TagSourceManual))
+	if err != nil {
+		return fmt.Errorf("failed to delete post topics: %w", err)
+	}
+
+	query := `
+		INSERT INTO post_topics (post_id, topic_id, similarity, confidence, is_ambiguous, keyword_boost, probability, assigned_at)
+		SELECT $1, $2, $3, $4, $5, $6, NULLIF($7::real, 0), $8
+		WHERE NOT EXISTS (SELECT 1 FROM post_topics WHERE post_id = $1 AND topic_id = $2)
+		  AND NOT EXISTS (SELECT 1 FROM post_topic_labels WHERE post_id = $1 AND topic_id = $2 AND source = $9)`
+
+	for _, pt := range pts {
+		assignedAt := pt.AssignedAt
+		if assignedAt.IsZero() {
+			assignedAt = time.Now()
+		}
+		confidence := pt.Confidence
+		if confidence == "" {
+			confidence = entity.ConfidenceMedium
+		}
+		_, err := tx.Exec(ctx, query,
+			postID, pt.TopicID, pt.Similarity, confidence, pt.IsAmbiguous, pt.KeywordBoost, pt.Probability, assignedAt,
+			string(entity.TagSourceManual),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save post topic: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit post topics: %w", err)
+	}
+	return nil
+}
+
 // FindPostTopics 查詢貼文的所有主題
 func (r *TopicRepo) FindPostTopics(ctx context.Context, postID int64) ([]*entity.PostTopic, error) {
 	query := `
-		SELECT id, post_id, topic_id, similarity, confidence, assigned_at
+		SELECT id, post_id, topic_id, similarity, confidence,
+			COALESCE(is_ambiguous, false), COALESCE(keyword_boost, 0), COALESCE(probability, 0), assigned_at
 		FROM post_topics
 		WHERE post_id = $1
-		ORDER BY similarity DESC`
+		ORDER BY COALESCE(probability, 0) DESC, similarity DESC`
 
 	rows, err := r.db.Pool.Query(ctx, query, postID)
 	if err != nil {
@@ -153,7 +205,8 @@ func (r *TopicRepo) FindPostTopics(ctx context.Context, postID int64) ([]*entity
 	var pts []*entity.PostTopic
 	for rows.Next() {
 		var pt entity.PostTopic
-		err := rows.Scan(&pt.ID, &pt.PostID, &pt.TopicID, &pt.Similarity, &pt.Confidence, &pt.AssignedAt)
+		err := rows.Scan(&pt.ID, &pt.PostID, &pt.TopicID, &pt.Similarity, &pt.Confidence,
+			&pt.IsAmbiguous, &pt.KeywordBoost, &pt.Probability, &pt.AssignedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan post topic: %w", err)
 		}
@@ -209,3 +262,107 @@ func (r *TopicRepo) GetTopicStats(ctx context.Context) (map[string]int, error) {
 	}
 	return stats, nil
 }
+
+// SaveTopicLabel 儲存人工標註（同一篇貼文同一主題以最新標註為準）
+func (r *TopicRepo) SaveTopicLabel(ctx context.Context, label *entity.TopicLabel) error {
+	query := `
+		INSERT INTO post_topic_labels (post_id, topic_id, is_relevant, source, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (post_id, topic_id) DO UPDATE SET
+			is_relevant = EXCLUDED.is_relevant,
+			source = EXCLUDED.source,
+			created_at = EXCLUDED.created_at`
+
+	source := label.Source
+	if source == "" {
+		source = entity.TagSourceManual
+	}
+	createdAt := label.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err := r.db.Pool.Exec(ctx, query, label.PostID, label.TopicID, label.IsRelevant, string(source), createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to save topic label: %w", err)
+	}
+	return nil
+}
+
+// ListTopicLabels 查詢所有人工標註
+func (r *TopicRepo) ListTopicLabels(ctx context.Context) ([]*entity.TopicLabel, error) {
+	query := `
+		SELECT post_id, topic_id, is_relevant, source, created_at
+		FROM post_topic_labels
+		ORDER BY post_id, topic_id`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topic labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []*entity.TopicLabel
+	for rows.Next() {
+		var l entity.TopicLabel
+		var source string
+		if err := rows.Scan(&l.PostID, &l.TopicID, &l.IsRelevant, &source, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan topic label: %w", err)
+		}
+		l.Source = entity.TagSource(source)
+		labels = append(labels, &l)
+	}
+	return labels, nil
+}
+
+// SaveCalibration 儲存主題校準參數（覆蓋舊值）
+func (r *TopicRepo) SaveCalibration(ctx context.Context, cal *entity.TopicCalibration) error {
+	query := `
+		INSERT INTO topic_calibrations (topic_id, slope, intercept, threshold, sample_count, positive_count, log_loss, fitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (topic_id) DO UPDATE SET
+			slope = EXCLUDED.slope,
+			intercept = EXCLUDED.intercept,
+			threshold = EXCLUDED.threshold,
+			sample_count = EXCLUDED.sample_count,
+			positive_count = EXCLUDED.positive_count,
+			log_loss = EXCLUDED.log_loss,
+			fitted_at = EXCLUDED.fitted_at`
+
+	fittedAt := cal.FittedAt
+	if fittedAt.IsZero() {
+		fittedAt = time.Now()
+	}
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		cal.TopicID, cal.Slope, cal.Intercept, cal.Threshold, cal.SampleCount, cal.PositiveCount, cal.LogLoss, fittedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save topic calibration: %w", err)
+	}
+	return nil
+}
+
+// ListCalibrations 查詢所有主題校準參數
+func (r *TopicRepo) ListCalibrations(ctx context.Context) ([]*entity.TopicCalibration, error) {
+	query := `
+		SELECT topic_id, slope, intercept, threshold, sample_count, positive_count, COALESCE(log_loss, 0), fitted_at
+		FROM topic_calibrations`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topic calibrations: %w", err)
+	}
+	defer rows.Close()
+
+	var cals []*entity.TopicCalibration
+	for rows.Next() {
+		var c entity.TopicCalibration
+		if err := rows.Scan(&c.TopicID, &c.Slope, &c.Intercept, &c.Threshold,
+			&c.SampleCount, &c.PositiveCount, &c.LogLoss, &c.FittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan topic calibration: %w", err)
+		}
+		cals = append(cals, &c)
+	}
+	return cals, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/entity"
+	"github.com/ikala/ontix/internal/domain/repository"
+)
+
+// UsageRepo PostgreSQL 實作的 UsageRepository
+type UsageRepo struct {
+	db *DB
+}
+
+// NewUsageRepo 建立 UsageRepository
+func NewUsageRepo(db *DB) repository.UsageRepository {
+	return &UsageRepo{db: db}
+}
+
+// Record 記錄一次 LLM / Embedding 呼叫
+func (r *UsageRepo) Record(ctx context.Context, u *entity.LLMUsage) error {
+	tenant := u.Tenant
+	if tenant == "" {
+		tenant = entity.DefaultTenant
+	}
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO llm_usage (tenant, stage, provider, model, input_tokens, output_tokens, cost_usd, latency_ms, success)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		tenant, string(u.Stage), u.Provider, u.Model, u.InputTokens, u.OutputTokens, u.CostUSD, u.LatencyMS, u.Success)
+	if err != nil {
+		return fmt.Errorf("failed to record llm usage: %w", err)
+	}
+	return nil
+}
+
+// CostSince 取得自某時間點起的累計成本
+func (r *UsageRepo) CostSince(ctx context.Context, tenant string, since time.Time) (float64, error) {
+	var cost float64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(cost_usd), 0)::float8
+		FROM llm_usage
+		WHERE created_at >= $1 AND ($2 = '' OR tenant = $2)`,
+		since, tenant).Scan(&cost)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum llm cost: %w", err)
+	}
+	return cost, nil
+}
+
+// usageGroupColumns group_by 白名單
+var usageGroupColumns = map[string]string{
+	"day":    "to_char(created_at, 'YYYY-MM-DD')",
+	"stage":  "stage",
+	"model":  "model",
+	"tenant": "tenant",
+}
+
+// Summarize 依條件彙總用量
+func (r *UsageRepo) Summarize(ctx context.Context, f entity.UsageFilter) ([]*entity.UsageSummary, error) {
+	groupCol, ok := usageGroupColumns[f.GroupBy]
+	if !ok {
+		groupCol = usageGroupColumns["stage"]
+	}
+	to := f.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+groupCol+` AS grp,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE NOT success),
+		       COALESCE(SUM(input_tokens), 0),
+		       COALESCE(SUM(output_tokens), 0),
+		       COALESCE(SUM(cost_usd), 0)::float8,
+		       COALESCE(AVG(latency_ms), 0)::float8
+		FROM llm_usage
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3 = '' OR tenant = $3)
+		  AND ($4 = '' OR stage = $4)
+		GROUP BY grp
+		ORDER BY grp`,
+		f.From, to, f.Tenant, string(f.Stage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize llm usage: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*entity.UsageSummary
+	for rows.Next() {
+		var s entity.UsageSummary
+		if err := rows.Scan(&s.Group, &s.Calls, &s.Failures, &s.InputTokens, &s.OutputTokens, &s.CostUSD, &s.AvgLatencyMS); err != nil {
+			return nil, fmt.Errorf("failed to scan llm usage: %w", err)
+		}
+		result = append(result, &s)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ingestSeenKeyPrefix = "ingest:seen:"
+
+	// DefaultIngestSeenTTL 發佈端冪等 key 預設保留時間
+	DefaultIngestSeenTTL = 30 * 24 * time.Hour
+)
+
+// IngestGuard 發佈端冪等：以 (platform, 貼文 ID) 為 key 記錄最後排入的內容 + 互動數指紋，
+// TTL 內重送完全相同的貼文不會再進佇列（重推同一個檔案不會讓每篇貼文再跑一次 pipeline）；
+// 內容編輯或互動數變動的貼文照常排入，由 worker 更新
+type IngestGuard struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewIngestGuard 建立 IngestGuard（ttl<=0 使用預設值）
+func NewIngestGuard(client *Client, ttl time.Duration) *IngestGuard {
+	if ttl <= 0 {
+		ttl = DefaultIngestSeenTTL
+	}
+	return &IngestGuard{client: client, ttl: ttl}
+}
+
+// claimScript 指紋相同回傳 0；否則寫入新指紋並回傳 1
+var claimScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`
+
+func ingestSeenKey(msg PostMessage) string {
+	return ingestSeenKeyPrefix + msg.Platform + ":" + strconv.FormatInt(msg.ID, 10)
+}
+
+// ingestFingerprint 內容 + 互動數指紋
+func ingestFingerprint(msg PostMessage) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d|%d|%d|%d|%s", msg.LikeCount, msg.CommentCount, msg.ShareCount, msg.ViewCount, msg.Content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Claim 登記即將發佈的貼文，回傳每篇是否需要排入（false = TTL 內已排入過完全相同的內容與互動數）
+func (g *IngestGuard) Claim(ctx context.Context, msgs []PostMessage) ([]bool, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	pipe := g.client.rdb.Pipeline()
+	cmds := make([]*redis.Cmd, len(msgs))
+	ttl := strconv.FormatInt(g.ttl.Milliseconds(), 10)
+	for i, msg := range msgs {
+		cmds[i] = pipe.Eval(ctx, claimScript, []string{ingestSeenKey(msg)}, ingestFingerprint(msg), ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to claim ingest keys: %w", err)
+	}
+
+	fresh := make([]bool, len(msgs))
+	for i, cmd := range cmds {
+		n, _ := cmd.Int()
+		fresh[i] = n == 1
+	}
+	return fresh, nil
+}
+
+// Release 撤銷登記（發佈失敗時呼叫，讓同一批貼文可以重送）
+func (g *IngestGuard) Release(ctx context.Context, msgs []PostMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	keys := make([]string, len(msgs))
+	for i, msg := range msgs {
+		keys[i] = ingestSeenKey(msg)
+	}
+	if err := g.client.rdb.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to release ingest keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ikala/ontix/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const llmCacheKeyPrefix = "llmcache:"
+
+// LLMCacheRepo Redis 實作的 LLMCacheRepository
+type LLMCacheRepo struct {
+	client *Client
+}
+
+// NewLLMCacheRepo 建立 LLMCacheRepository
+func NewLLMCacheRepo(client *Client) repository.LLMCacheRepository {
+	return &LLMCacheRepo{client: client}
+}
+
+// Get 取得快取值
+func (r *LLMCacheRepo) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := r.client.rdb.Get(ctx, llmCacheKeyPrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get llm cache: %w", err)
+	}
+	return data, true, nil
+}
+
+// Set 寫入快取值
+func (r *LLMCacheRepo) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.client.rdb.Set(ctx, llmCacheKeyPrefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set llm cache: %w", err)
+	}
+	return nil
+}
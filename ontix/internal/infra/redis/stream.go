@@ -25,7 +25,13 @@ type PostMessage struct {
 	ShareCount     int    `json:"share_count"`
 	ViewCount      int    `json:"view_count"`
 	OwnerUsername  string `json:"owner_username"`
+	OwnerFollowers int    `json:"owner_followers,omitempty"`
 	PostTime       string `json:"post_time"`
+
+	// Comment / reply threads: ParentID is the direct parent (post or comment),
+	// RootID the thread's original post. Both zero for top-level posts.
+	ParentID int64 `json:"parent_id,omitempty"`
+	RootID   int64 `json:"root_id,omitempty"`
 }
 
 // StreamRepo handles Redis Stream operations
@@ -66,6 +72,29 @@ func (s *StreamRepo) Publish(ctx context.Context, msg PostMessage) error {
 	}).Err()
 }
 
+// PublishBatch publishes posts to the stream in a single pipeline round trip
+func (s *StreamRepo) PublishBatch(ctx context.Context, msgs []PostMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	pipe := s.client.rdb.Pipeline()
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: streamKey,
+			Values: map[string]interface{}{"data": string(data)},
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to publish batch: %w", err)
+	}
+	return nil
+}
+
 // Consume consumes messages (blocks waiting for new messages)
 func (s *StreamRepo) Consume(ctx context.Context, batchSize int, timeout time.Duration) ([]PostMessage, []string, error) {
 	results, err := s.client.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
@@ -154,6 +183,35 @@ func (s *StreamRepo) ClaimStale(ctx context.Context, maxIdle time.Duration, batc
 	return posts, ids, nil
 }
 
+// DeliveryCounts returns how many times each pending message has been delivered
+// (ids no longer pending are omitted)
+func (s *StreamRepo) DeliveryCounts(ctx context.Context, ids []string) (map[string]int64, error) {
+	pipe := s.client.rdb.Pipeline()
+	cmds := make([]*redis.XPendingExtCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: streamKey,
+			Group:  groupName,
+			Start:  id,
+			End:    id,
+			Count:  1,
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("xpending: %w", err)
+	}
+
+	counts := make(map[string]int64, len(ids))
+	for i, cmd := range cmds {
+		pending, err := cmd.Result()
+		if err != nil || len(pending) == 0 {
+			continue
+		}
+		counts[ids[i]] = pending[0].RetryCount
+	}
+	return counts, nil
+}
+
 // Len returns the stream length
 func (s *StreamRepo) Len(ctx context.Context) (int64, error) {
 	return s.client.rdb.XLen(ctx, streamKey).Result()
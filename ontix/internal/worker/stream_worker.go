@@ -2,6 +2,8 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"strconv"
 	"sync"
@@ -28,11 +30,19 @@ type StreamWorker struct {
 	analysisRepo repository.PostAnalysisRepository // 分析結果儲存
 	assigner    *service.Assigner
 	llmClassifier *service.LLMClassifier
+	topicAssigner *service.TopicAssigner // 多標籤模式（非 nil 時取代 LLM 分類）
+	reviewRepo    repository.ReviewRepository // 人工審核佇列 + few-shot 回饋
 	coldStartSvc  *service.ColdStartService
 	subClusterSvc *service.SubClusterService
 	entityExtractor *service.EntityExtractor // Ontology Entity 抽取
 	ontologyEngine  *service.OntologyEngine  // Ontology 推理引擎
 	db              *postgres.DB             // for materialized view refresh
+	llmCache        *service.LLMCache        // LLM 回應快取（僅用於定期輸出命中統計）
+	entityEmbedder  *service.EntityEmbedder  // Entity embedding 定期更新
+	reportSvc       *service.ReportService   // 排程報表
+	dedupSvc        *service.DedupService    // 近似重複偵測
+	chunker         *service.Chunker         // 長文切段（embedding 合併 + 分段抽取）
+	authorRegistry  *service.AuthorRegistry  // 作者登錄 + person Entity 連結
 
 	batchSize    int
 	batchTimeout time.Duration
@@ -61,6 +71,7 @@ func NewStreamWorker(
 		topicRepo:     topicRepo,
 		assigner:      assigner,
 		llmClassifier: llmClassifier,
+		chunker:       service.DefaultChunker(),
 		batchSize:     1,
 		batchTimeout:  1 * time.Second, // short timeout for graceful shutdown
 		concurrency:   1,
@@ -92,6 +103,16 @@ func (w *StreamWorker) SetConcurrency(n int) {
 	w.concurrency = n
 }
 
+// SetTopicAssigner enables multi-label topic assignment with calibrated probabilities
+func (w *StreamWorker) SetTopicAssigner(assigner *service.TopicAssigner) {
+	w.topicAssigner = assigner
+}
+
+// SetReviewRepo sets the review queue (uncertain results are enqueued, corrections feed back as few-shot examples)
+func (w *StreamWorker) SetReviewRepo(repo repository.ReviewRepository) {
+	w.reviewRepo = repo
+}
+
 // SetColdStartService sets the cold start service
 func (w *StreamWorker) SetColdStartService(svc *service.ColdStartService) {
 	w.coldStartSvc = svc
@@ -112,6 +133,36 @@ func (w *StreamWorker) SetOntologyEngine(engine *service.OntologyEngine) {
 	w.ontologyEngine = engine
 }
 
+// SetLLMCache sets the LLM response cache (hit/miss stats are logged periodically)
+func (w *StreamWorker) SetLLMCache(cache *service.LLMCache) {
+	w.llmCache = cache
+}
+
+// SetEntityEmbedder 設定 Entity embedding 產生器（定期更新過期的 Entity embedding）
+func (w *StreamWorker) SetEntityEmbedder(embedder *service.EntityEmbedder) {
+	w.entityEmbedder = embedder
+}
+
+// SetReportService 設定報表服務（定期產出到期的排程報表）
+func (w *StreamWorker) SetReportService(svc *service.ReportService) {
+	w.reportSvc = svc
+}
+
+// SetDedupService 設定近似重複偵測（轉貼 / 複製貼上洗版依策略略過或不計入觀測）
+func (w *StreamWorker) SetDedupService(svc *service.DedupService) {
+	w.dedupSvc = svc
+}
+
+// SetChunker 設定長文切段（預設 800 字一段、最多 16 段、mean pooling）
+func (w *StreamWorker) SetChunker(c *service.Chunker) {
+	w.chunker = c
+}
+
+// SetAuthorRegistry 設定作者登錄（匯入時登記作者、追蹤數快照，KOL 連結 person Entity）
+func (w *StreamWorker) SetAuthorRegistry(r *service.AuthorRegistry) {
+	w.authorRegistry = r
+}
+
 // SetDB sets the database for materialized view refresh
 func (w *StreamWorker) SetDB(db *postgres.DB) {
 	w.db = db
@@ -126,9 +177,6 @@ func (w *StreamWorker) Run(ctx context.Context) error {
 
 	log.Printf("Stream worker started (batch=%d, concurrency=%d, timeout=%s)", w.batchSize, w.concurrency, w.batchTimeout)
 
-	// Drain stale pending messages from previous runs
-	w.drainStalePending(ctx)
-
 	// Periodic materialized view refresh (every 10 minutes)
 	if w.db != nil {
 		go w.periodicRefreshViews(ctx, 10*time.Minute)
@@ -139,6 +187,31 @@ func (w *StreamWorker) Run(ctx context.Context) error {
 		go w.periodicOntologyEval(ctx, 1*time.Hour)
 	}
 
+	// Periodic few-shot refresh from review corrections (every 10 minutes)
+	if w.reviewRepo != nil {
+		go w.periodicFewShotRefresh(ctx, 10*time.Minute)
+	}
+
+	// Periodic LLM cache stats (every 10 minutes)
+	if w.llmCache != nil {
+		go w.periodicCacheStats(ctx, 10*time.Minute)
+	}
+
+	// Periodic candidate selection stats (every 10 minutes)
+	if w.entityExtractor != nil && w.entityExtractor.CandidateSelector() != nil {
+		go w.periodicCandidateStats(ctx, 10*time.Minute)
+	}
+
+	// Periodic entity embedding refresh (every 30 minutes)
+	if w.entityEmbedder != nil {
+		go w.periodicEntityEmbedding(ctx, 30*time.Minute)
+	}
+
+	// Scheduled reports (checked every minute)
+	if w.reportSvc != nil {
+		go w.periodicReports(ctx, 1*time.Minute)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -214,34 +287,226 @@ func (w *StreamWorker) runOntologyEval(ctx context.Context) {
 		result.Observations, result.Deltas, result.FactsCreated)
 }
 
-// drainStalePending claims and ACKs any messages pending for over 5 minutes.
-// These are messages that were consumed but never acknowledged (e.g. worker
-// crashed or embedding failed). We ACK+DEL them to prevent permanent queue
-// growth; the underlying posts can be re-seeded if needed.
-func (w *StreamWorker) drainStalePending(ctx context.Context) {
-	const staleThreshold = 5 * time.Minute
-	total := 0
+// periodicCacheStats 定期輸出 LLM 快取命中統計
+func (w *StreamWorker) periodicCacheStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	for {
-		msgs, ids, err := w.stream.ClaimStale(ctx, staleThreshold, 100)
-		if err != nil {
-			log.Printf("drain stale pending error: %v", err)
+		select {
+		case <-ctx.Done():
 			return
+		case <-ticker.C:
+			log.Printf("[llm-cache] %s", w.llmCache.Summary())
 		}
-		if len(ids) == 0 {
-			break
+	}
+}
+
+// periodicCandidateStats 定期輸出候選挑選統計（LLM 選中非候選 Entity 的比例）
+func (w *StreamWorker) periodicCandidateStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sel := w.entityExtractor.CandidateSelector()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Printf("[candidates] %s", sel.Summary())
 		}
-		// Try to process; on failure just ACK to unblock the queue
-		for i, msg := range msgs {
-			log.Printf("recovered stale message: post %d (%s)", msg.ID, ids[i])
+	}
+}
+
+// periodicEntityEmbedding 定期為新 Entity / 提及數明顯成長的 Entity 重新產生 embedding
+func (w *StreamWorker) periodicEntityEmbedding(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			updated, err := w.entityEmbedder.RefreshStale(ctx, 200, false)
+			if err != nil {
+				log.Printf("[entity-embed] refresh error: %v", err)
+			}
+			if updated > 0 {
+				log.Printf("[entity-embed] %d entity embeddings updated", updated)
+			}
 		}
-		_ = msgs // logged above
-		if err := w.stream.Ack(ctx, ids); err != nil {
-			log.Printf("ack stale messages error: %v", err)
+	}
+}
+
+// periodicReports 定期產出到期的排程報表
+func (w *StreamWorker) periodicReports(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := w.reportSvc.RunDue(ctx)
+			if err != nil {
+				log.Printf("[report] run due reports error: %v", err)
+			}
+			if n > 0 {
+				log.Printf("[report] %d scheduled reports produced", n)
+			}
 		}
-		total += len(ids)
 	}
-	if total > 0 {
-		log.Printf("drained %d stale pending messages", total)
+}
+
+// periodicFewShotRefresh 定期將人工校正範例注入 LLM prompt
+func (w *StreamWorker) periodicFewShotRefresh(ctx context.Context, interval time.Duration) {
+	w.refreshFewShots(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.refreshFewShots(ctx)
+		}
+	}
+}
+
+// refreshFewShots 撈最新的人工校正範例：主題 → LLMClassifier，情感 / 軟標籤 → TaggingService
+func (w *StreamWorker) refreshFewShots(ctx context.Context) {
+	const perField = 5
+
+	if w.llmClassifier != nil {
+		examples, err := w.reviewRepo.ListFewShotExamples(ctx, entity.CorrectionFieldTopic, perField)
+		if err != nil {
+			log.Printf("[review] load topic few-shot examples error: %v", err)
+		} else {
+			w.llmClassifier.SetFewShotExamples(examples)
+		}
+	}
+
+	if receiver, ok := w.taggingSvc.(service.FewShotReceiver); ok {
+		var examples []*entity.FewShotExample
+		for _, field := range []entity.CorrectionField{entity.CorrectionFieldSentiment, entity.CorrectionFieldTags} {
+			ex, err := w.reviewRepo.ListFewShotExamples(ctx, field, perField)
+			if err != nil {
+				log.Printf("[review] load %s few-shot examples error: %v", field, err)
+				continue
+			}
+			examples = append(examples, ex...)
+		}
+		receiver.SetFewShotExamples(examples)
+	}
+}
+
+// enqueueReview 將不確定的結果排入人工審核佇列
+func (w *StreamWorker) enqueueReview(ctx context.Context, postID string, reason entity.ReviewReason, priority float64, detail map[string]any) {
+	if w.reviewRepo == nil {
+		return
+	}
+	item := &entity.ReviewItem{
+		PostID:   postID,
+		Reason:   reason,
+		Detail:   detail,
+		Priority: priority,
+	}
+	if err := w.reviewRepo.Enqueue(ctx, item); err != nil {
+		log.Printf("[review] enqueue post %s error: %v", postID, err)
+	}
+}
+
+const (
+	staleThreshold = 5 * time.Minute // 超過此時間未 ACK 的訊息重新領取
+	maxDeliveries  = 5               // 投遞超過此次數仍未完成則放棄（ACK）
+)
+
+// claimStalePending 重新領取 pending 超過 staleThreshold 的訊息（worker 中斷，或內容編輯重跑失敗而刻意不 ACK 的），
+// 投遞次數超過 maxDeliveries 的放棄並 ACK，避免永遠卡在 pending
+func (w *StreamWorker) claimStalePending(ctx context.Context) ([]redis.PostMessage, []string) {
+	msgs, ids, err := w.stream.ClaimStale(ctx, staleThreshold, w.batchSize)
+	if err != nil {
+		log.Printf("claim stale pending error: %v", err)
+		return nil, nil
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	counts, err := w.stream.DeliveryCounts(ctx, ids)
+	if err != nil {
+		log.Printf("load delivery counts error (retrying all): %v", err)
+	}
+
+	keptMsgs := make([]redis.PostMessage, 0, len(msgs))
+	keptIDs := make([]string, 0, len(ids))
+	var abandoned []string
+	for i, id := range ids {
+		if n := counts[id]; n > maxDeliveries {
+			log.Printf("giving up on post %d (%s) after %d deliveries", msgs[i].ID, id, n)
+			abandoned = append(abandoned, id)
+			continue
+		}
+		keptMsgs = append(keptMsgs, msgs[i])
+		keptIDs = append(keptIDs, id)
+	}
+	if len(abandoned) > 0 {
+		if err := w.stream.Ack(ctx, abandoned); err != nil {
+			log.Printf("ack abandoned messages error: %v", err)
+		}
+	}
+	if len(keptIDs) > 0 {
+		log.Printf("reclaimed %d stale pending messages", len(keptIDs))
+	}
+	return keptMsgs, keptIDs
+}
+
+// ackExcept ACK 本批訊息，retry 中的貼文除外（留在 pending，逾時後由 claimStalePending 重新領取）
+func (w *StreamWorker) ackExcept(ctx context.Context, msgs []redis.PostMessage, ids []string, retry map[string]bool) {
+	ack := make([]string, 0, len(ids))
+	for i, id := range ids {
+		if !retry[strconv.FormatInt(msgs[i].ID, 10)] {
+			ack = append(ack, id)
+		}
+	}
+	if len(ack) == 0 {
+		return
+	}
+	if err := w.stream.Ack(ctx, ack); err != nil {
+		log.Printf("ack error: %v", err)
+	}
+}
+
+// restoreReanalysis 內容編輯重跑失敗：還原去重狀態，貼文留待重試
+func (w *StreamWorker) restoreReanalysis(ctx context.Context, mark *entity.ReanalysisMark) {
+	if err := w.postRepo.RestoreReanalysis(ctx, mark); err != nil {
+		log.Printf("[dedup] restore edited post %s error: %v", mark.PostID, err)
+	}
+}
+
+// saveChunks 儲存長文的 chunk 向量（單段貼文的 chunk 向量即貼文向量，不另存，
+// 但仍清掉編輯前留下的舊 chunk，避免搜尋命中已不存在的內容）
+func (w *StreamWorker) saveChunks(ctx context.Context, m redis.PostMessage, chunks []service.Chunk, vectors [][]float32) {
+	postID := strconv.FormatInt(m.ID, 10)
+	if len(chunks) < 2 {
+		chunks = nil
+	}
+	rows := make([]*entity.PostChunk, len(chunks))
+	for i, ch := range chunks {
+		rows[i] = &entity.PostChunk{
+			PostID:     postID,
+			ChunkIndex: ch.Index,
+			Start:      ch.Start,
+			End:        ch.End,
+			Content:    ch.Text,
+			Embedding:  vectors[i],
+		}
+	}
+	if err := w.postRepo.SaveChunks(ctx, postID, rows); err != nil {
+		log.Printf("save chunks for post %d error: %v", m.ID, err)
 	}
 }
 
@@ -252,15 +517,20 @@ type processedPost struct {
 	embedding []float32
 	tags      *service.PostTags
 	analysis  *service.PostAnalysis // 全量 LLM 分析結果
+	language  entity.Language       // 分析時使用的語言（主題分配的關鍵字規則依此選擇）
 }
 
 func (w *StreamWorker) processBatch(ctx context.Context) {
-	// 1. Consume messages
-	msgs, ids, err := w.stream.Consume(ctx, w.batchSize, w.batchTimeout)
-	if err != nil {
-		log.Printf("consume error: %v", err)
-		time.Sleep(time.Second)
-		return
+	// 1. 先重新領取逾時未 ACK 的訊息（重試），沒有才讀新訊息
+	msgs, ids := w.claimStalePending(ctx)
+	if len(msgs) == 0 {
+		var err error
+		msgs, ids, err = w.stream.Consume(ctx, w.batchSize, w.batchTimeout)
+		if err != nil {
+			log.Printf("consume error: %v", err)
+			time.Sleep(time.Second)
+			return
+		}
 	}
 	if len(msgs) == 0 {
 		return
@@ -268,18 +538,44 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 
 	log.Printf("Processing batch of %d posts", len(msgs))
 
-	// 2. 準備內容
-	contents := make([]string, len(msgs))
+	// 1.4 留言串：補齊 root、載入所屬貼文作為 LLM 上下文
+	threads := w.resolveThreads(ctx, msgs)
+
+	// 1.5 去重（ids 保留全部，處理完一併 ACK）；edited 為內容被編輯、需要重跑的貼文，
+	// retry 為內容編輯重跑失敗、不 ACK 留待重試的貼文
+	allMsgs := msgs
+	msgs, edited, retry := w.filterDuplicates(ctx, msgs)
+	if len(msgs) == 0 {
+		w.ackExcept(ctx, allMsgs, ids, retry)
+		return
+	}
+
+	// 1.6 各貼文的語言由（新）內容偵測；編輯後的內容無法判斷時才沿用儲存的值。
+	// 分析、Entity 抽取、主題分配與 posts.language 都使用同一個值
+	langCtxs := make([]context.Context, len(msgs))
+	for i, m := range msgs {
+		lang := service.DetectLanguage(m.Content)
+		if mark := edited[strconv.FormatInt(m.ID, 10)]; mark != nil && lang == entity.LanguageUnknown {
+			lang = mark.Language
+		}
+		langCtxs[i] = service.WithLanguage(ctx, lang)
+	}
+
+	// 2. 長文依句子邊界切段（整個 batch 的 chunk 一次送 embedding）
+	postChunks := make([][]service.Chunk, len(msgs))
+	var chunkTexts []string
 	for i, m := range msgs {
-		content := m.Content
-		if len(content) > 500 {
-			content = content[:500]
+		chunks := w.chunker.Split(m.Content)
+		if len(chunks) == 0 {
+			chunks = []service.Chunk{{Text: m.Content}}
 		}
-		contents[i] = content
+		postChunks[i] = chunks
+		chunkTexts = append(chunkTexts, service.ChunkTexts(chunks)...)
 	}
 
 	// 3. 並行執行 Embedding + LLM Analysis + Entity Extraction
-	var embeddings [][]float32
+	var embeddings [][]float32     // 貼文向量（多段時為 chunk 向量合併）
+	var chunkVectors [][][]float32 // 各貼文的 chunk 向量
 	var analyses []*service.PostAnalysis
 	var entitySummaries []*service.EntityExtractionSummary
 	var embedErr error
@@ -290,13 +586,28 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 	}
 	var parallelWg sync.WaitGroup
 	parallelWg.Add(parallelCount)
+	embedDone := make(chan struct{}) // 候選挑選需要貼文 embedding
 
 	// 3.1 Batch Embedding
 	go func() {
 		defer parallelWg.Done()
-		embeddings, embedErr = w.embedSvc.BatchEmbed(ctx, contents)
-		if embedErr != nil {
+		defer close(embedDone)
+		vectors, err := w.embedSvc.BatchEmbed(ctx, chunkTexts)
+		if err == nil && len(vectors) != len(chunkTexts) {
+			err = fmt.Errorf("expected %d embeddings, got %d", len(chunkTexts), len(vectors))
+		}
+		if err != nil {
+			embedErr = err
 			log.Printf("batch embed error: %v", embedErr)
+			return
+		}
+		embeddings = make([][]float32, len(msgs))
+		chunkVectors = make([][][]float32, len(msgs))
+		offset := 0
+		for i, chunks := range postChunks {
+			chunkVectors[i] = vectors[offset : offset+len(chunks)]
+			offset += len(chunks)
+			embeddings[i] = w.chunker.Pool(chunks, chunkVectors[i])
 		}
 	}()
 
@@ -313,18 +624,24 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 
 		for i, msg := range msgs {
 			analysisWg.Add(1)
-			go func(idx int, content string) {
+			go func(idx int, content string, thread *service.ThreadContext) {
 				defer analysisWg.Done()
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				analysis, err := w.taggingSvc.AnalyzePost(ctx, content)
+				var analysis *service.PostAnalysis
+				var err error
+				if thread != nil {
+					analysis, err = w.taggingSvc.AnalyzeComment(langCtxs[idx], content, *thread)
+				} else {
+					analysis, err = w.taggingSvc.AnalyzePost(langCtxs[idx], content)
+				}
 				if err != nil {
 					log.Printf("analyze post error: %v", err)
 					return
 				}
 				analyses[idx] = analysis
-			}(i, msg.Content)
+			}(i, msg.Content, threads[msg.ID])
 		}
 		analysisWg.Wait()
 	}()
@@ -341,6 +658,15 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 				knownEntities = nil
 			}
 
+			// 候選挑選用貼文 embedding 找語意相近的 Entity，等 embedding 完成
+			var postEmbeddings [][]float32
+			if w.entityExtractor.CandidateSelector() != nil {
+				<-embedDone
+				if embedErr == nil && len(embeddings) == len(msgs) {
+					postEmbeddings = embeddings
+				}
+			}
+
 			entitySummaries = make([]*service.EntityExtractionSummary, len(msgs))
 			var entityWg sync.WaitGroup
 			sem := make(chan struct{}, w.concurrency)
@@ -353,7 +679,20 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 					defer func() { <-sem }()
 
 					postID := strconv.FormatInt(m.ID, 10)
-					summary, err := w.entityExtractor.ProcessPostWithKnown(ctx, postID, m.Content, knownEntities)
+					var embedding []float32
+					if postEmbeddings != nil {
+						embedding = postEmbeddings[idx]
+					}
+					var summary *service.EntityExtractionSummary
+					var err error
+					if edited[postID] != nil {
+						// 內容編輯重跑：提及與 aspect 留在 summary，成功後與新內容一起寫入
+						summary, err = w.entityExtractor.ReextractWithKnown(langCtxs[idx], postID, m.Content, threads[m.ID], embedding, knownEntities)
+					} else if thread := threads[m.ID]; thread != nil {
+						summary, err = w.entityExtractor.ProcessCommentWithKnown(langCtxs[idx], postID, m.Content, *thread, embedding, knownEntities)
+					} else {
+						summary, err = w.entityExtractor.ProcessPostWithKnown(langCtxs[idx], postID, m.Content, embedding, knownEntities)
+					}
 					if err != nil {
 						log.Printf("entity extraction error for post %d: %v", m.ID, err)
 						return
@@ -367,12 +706,14 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 
 	parallelWg.Wait()
 
-	// 如果 embedding 失敗，ACK 消息避免永遠卡住，然後返回
+	// 如果 embedding 失敗，ACK 消息避免永遠卡住，然後返回；內容編輯的貼文還原後留待重試（舊內容與分析仍在）
 	if embedErr != nil {
-		log.Printf("embedding failed, ACK-ing %d messages to prevent queue stuck", len(ids))
-		if err := w.stream.Ack(ctx, ids); err != nil {
-			log.Printf("ack after embed error: %v", err)
+		for postID, mark := range edited {
+			w.restoreReanalysis(ctx, mark)
+			retry[postID] = true
 		}
+		log.Printf("embedding failed, ACK-ing messages to prevent queue stuck (%d edited posts left for retry)", len(edited))
+		w.ackExcept(ctx, allMsgs, ids, retry)
 		return
 	}
 
@@ -397,13 +738,35 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 				analysis = analyses[idx]
 			}
 
-			result, err := w.processPostWithAnalysis(ctx, m, embeddings[idx], analysis)
+			postID := strconv.FormatInt(m.ID, 10)
+			if mark := edited[postID]; mark != nil {
+				var summary *service.EntityExtractionSummary
+				if entitySummaries != nil {
+					summary = entitySummaries[idx]
+				}
+				err := errReanalysisFailed
+				if w.reanalysisSucceeded(analysis, summary) {
+					err = w.replaceAnalysis(langCtxs[idx], m, embeddings[idx], mark, analysis, summary)
+				}
+				if err != nil {
+					log.Printf("[dedup] re-analysis of edited post %s failed, keeping previous content for retry: %v", postID, err)
+					w.restoreReanalysis(ctx, mark)
+					mu.Lock()
+					retry[postID] = true
+					mu.Unlock()
+					return
+				}
+			}
+
+			result, err := w.processPostWithAnalysis(langCtxs[idx], m, embeddings[idx], analysis, edited[postID] != nil)
 			if err != nil {
 				log.Printf("process post %d error: %v", m.ID, err)
 				return
 			}
+			w.saveChunks(ctx, m, postChunks[idx], chunkVectors[idx])
 
-			if result != nil {
+			// 留言不參與主題分配（主題以原始貼文為單位）
+			if result != nil && m.ParentID == 0 {
 				mu.Lock()
 				processed = append(processed, *result)
 				mu.Unlock()
@@ -413,15 +776,15 @@ func (w *StreamWorker) processBatch(ctx context.Context) {
 	}
 	wg.Wait()
 
-	// 5. Batch assign to topics using LLM
-	if w.llmClassifier != nil && len(processed) > 0 {
+	// 5. Batch assign to topics（多標籤模式使用校準後的 TopicAssigner，否則使用 LLM）
+	if w.topicAssigner != nil && len(processed) > 0 {
+		w.batchAssignMultiLabel(ctx, processed)
+	} else if w.llmClassifier != nil && len(processed) > 0 {
 		w.batchAssignToTopics(ctx, processed)
 	}
 
-	// 6. Acknowledge processed messages
-	if err := w.stream.Ack(ctx, ids); err != nil {
-		log.Printf("ack error: %v", err)
-	}
+	// 6. Acknowledge processed messages（重跑失敗的編輯除外）
+	w.ackExcept(ctx, allMsgs, ids, retry)
 
 	// 7. Log entity extraction summary
 	if entitySummaries != nil {
@@ -475,7 +838,8 @@ func (w *StreamWorker) batchAssignToTopics(ctx context.Context, posts []processe
 			primaryTopicID = &topic.ID
 		}
 
-		// 儲存主要主題
+		// 主要 + 次要主題一次取代貼文現有的自動主題
+		var pts []*entity.PostTopic
 		if primaryTopicID != nil {
 			confidence := entity.ConfidenceMedium
 			switch result.Confidence {
@@ -488,67 +852,53 @@ func (w *StreamWorker) batchAssignToTopics(ctx context.Context, posts []processe
 			case "low":
 				confidence = entity.ConfidenceLow
 				lowCount++
+				w.enqueueReview(ctx, postID, entity.ReviewReasonLowConfidence, 0.6, map[string]any{
+					"primary":   result.PrimaryTopic,
+					"secondary": result.SecondaryTopic,
+					"reason":    result.Reason,
+				})
 			}
 
-			pt := &entity.PostTopic{
+			pts = append(pts, &entity.PostTopic{
 				PostID:     parsePostID(postID),
 				TopicID:    *primaryTopicID,
 				Similarity: 0.9, // LLM 分類沒有相似度分數，使用固定值
 				Confidence: confidence,
 				AssignedAt: time.Now(),
-			}
-			if err := w.topicRepo.SavePostTopic(ctx, pt); err != nil {
-				log.Printf("save topic error: %v", err)
-			} else {
-				log.Printf("post %s -> [%s] (%s) %s",
-					postID, result.PrimaryTopic, result.Confidence, result.Reason)
-			}
-
-			// 冷啟動處理：檢查該 Topic 的 sub-cluster 是否已初始化
-			if w.coldStartSvc != nil {
-				csResult, err := w.coldStartSvc.HandleNewPost(ctx, *primaryTopicID, postID)
-				if err != nil {
-					log.Printf("cold start error for topic %d: %v", *primaryTopicID, err)
-				} else {
-					switch csResult.Action {
-					case "queued":
-						coldStartQueued++
-					case "triggered":
-						coldStartTriggered++
-						// 異步執行 HDBSCAN 初始化
-						go func(topicID int) {
-							if err := w.coldStartSvc.ProcessColdStart(context.Background(), topicID); err != nil {
-								log.Printf("cold start process error for topic %d: %v", topicID, err)
-							}
-						}(*primaryTopicID)
-					case "already_ready":
-						// 正常 KNN sub-cluster 分配
-						if w.subClusterSvc != nil {
-							result, err := w.subClusterSvc.AssignToSubCluster(ctx, postID, *primaryTopicID)
-							if err != nil {
-								log.Printf("sub-cluster assign error for post %s: %v", postID, err)
-							} else if result != nil {
-								log.Printf("post %s -> sub-cluster %s (distance: %.4f, noise: %v)",
-									postID, result.ClusterID, result.Distance, result.IsNoise)
-							}
-						}
-					}
-				}
-			}
+			})
 		}
 
 		// 處理次要主題
 		if result.SecondaryTopic != nil && *result.SecondaryTopic != "" {
 			multiCount++
 			if secondaryTopic := w.llmClassifier.FindTopicByName(*result.SecondaryTopic); secondaryTopic != nil {
-				pt := &entity.PostTopic{
+				pts = append(pts, &entity.PostTopic{
 					PostID:     parsePostID(postID),
 					TopicID:    secondaryTopic.ID,
 					Similarity: 0.7,
 					Confidence: entity.ConfidenceMedium,
 					AssignedAt: time.Now(),
-				}
-				w.topicRepo.SavePostTopic(ctx, pt)
+				})
+			}
+		}
+
+		if len(pts) == 0 {
+			continue
+		}
+		if err := w.topicRepo.ReplacePostTopics(ctx, parsePostID(postID), pts); err != nil {
+			log.Printf("save topic error: %v", err)
+			continue
+		}
+		log.Printf("post %s -> [%s] (%s) %s",
+			postID, result.PrimaryTopic, result.Confidence, result.Reason)
+
+		// 冷啟動處理：檢查該 Topic 的 sub-cluster 是否已初始化
+		if primaryTopicID != nil {
+			switch w.handleColdStart(ctx, *primaryTopicID, postID) {
+			case "queued":
+				coldStartQueued++
+			case "triggered":
+				coldStartTriggered++
 			}
 		}
 	}
@@ -563,6 +913,115 @@ func (w *StreamWorker) batchAssignToTopics(ctx context.Context, posts []processe
 	}
 }
 
+// handleColdStart 冷啟動處理：檢查該 Topic 的 sub-cluster 是否已初始化，回傳執行的動作
+func (w *StreamWorker) handleColdStart(ctx context.Context, topicID int, postID string) string {
+	if w.coldStartSvc == nil {
+		return ""
+	}
+
+	csResult, err := w.coldStartSvc.HandleNewPost(ctx, topicID, postID)
+	if err != nil {
+		log.Printf("cold start error for topic %d: %v", topicID, err)
+		return ""
+	}
+
+	switch csResult.Action {
+	case "triggered":
+		// 異步執行 HDBSCAN 初始化
+		go func(topicID int) {
+			if err := w.coldStartSvc.ProcessColdStart(context.Background(), topicID); err != nil {
+				log.Printf("cold start process error for topic %d: %v", topicID, err)
+			}
+		}(topicID)
+	case "already_ready":
+		// 正常 KNN sub-cluster 分配
+		if w.subClusterSvc != nil {
+			result, err := w.subClusterSvc.AssignToSubCluster(ctx, postID, topicID)
+			if err != nil {
+				log.Printf("sub-cluster assign error for post %s: %v", postID, err)
+			} else if result != nil {
+				log.Printf("post %s -> sub-cluster %s (distance: %.4f, noise: %v)",
+					postID, result.ClusterID, result.Distance, result.IsNoise)
+			}
+		}
+	}
+	return csResult.Action
+}
+
+// batchAssignMultiLabel 使用 TopicAssigner 多標籤分配：每個過門檻的主題都寫入 post_topics（含校準機率）
+func (w *StreamWorker) batchAssignMultiLabel(ctx context.Context, posts []processedPost) {
+	var labelCount, ambiguousCount int
+	var coldStartQueued, coldStartTriggered int
+
+	for _, p := range posts {
+		results, err := w.topicAssigner.AssignMultiLabel(service.WithLanguage(ctx, p.language), p.embedding, p.content)
+		if err != nil {
+			log.Printf("multi-label assign error for post %s: %v", p.postID, err)
+			continue
+		}
+
+		// 任一標籤接近門檻 → 排入人工審核（越接近門檻越優先）
+		minMargin := 1.0
+		labels := make([]map[string]any, 0, len(results))
+		for _, r := range results {
+			labels = append(labels, map[string]any{"topic_id": r.TopicID, "topic": r.TopicCode, "probability": r.Probability})
+			if r.IsAmbiguous && r.Margin < minMargin {
+				minMargin = r.Margin
+			}
+		}
+		if minMargin < 1.0 {
+			w.enqueueReview(ctx, p.postID, entity.ReviewReasonAmbiguousTopic, 1-minMargin, map[string]any{"labels": labels})
+		} else if len(results) == 0 {
+			// 沒有主題過門檻：不寫入標籤，交由人工判斷
+			w.enqueueReview(ctx, p.postID, entity.ReviewReasonAmbiguousTopic, 0.5, map[string]any{"labels": labels})
+		}
+
+		pts := make([]*entity.PostTopic, 0, len(results))
+		for _, r := range results {
+			pts = append(pts, &entity.PostTopic{
+				PostID:       parsePostID(p.postID),
+				TopicID:      r.TopicID,
+				Similarity:   r.Similarity,
+				Confidence:   r.Confidence,
+				IsAmbiguous:  r.IsAmbiguous,
+				KeywordBoost: r.KeywordBoost,
+				Probability:  r.Probability,
+				AssignedAt:   time.Now(),
+			})
+		}
+		if err := w.topicRepo.ReplacePostTopics(ctx, parsePostID(p.postID), pts); err != nil {
+			log.Printf("save topic error: %v", err)
+			continue
+		}
+
+		for i, r := range results {
+			labelCount++
+			if r.IsAmbiguous {
+				ambiguousCount++
+			}
+			log.Printf("post %s -> [%s] p=%.2f (sim=%.3f)", p.postID, r.TopicCode, r.Probability, r.Similarity)
+
+			// sub-cluster 只追蹤機率最高的主題
+			if i == 0 {
+				switch w.handleColdStart(ctx, r.TopicID, p.postID) {
+				case "queued":
+					coldStartQueued++
+				case "triggered":
+					coldStartTriggered++
+				}
+			}
+		}
+	}
+
+	if labelCount > 0 {
+		log.Printf("Multi-label classification: %d posts, %d labels (ambiguous:%d)",
+			len(posts), labelCount, ambiguousCount)
+	}
+	if coldStartQueued > 0 || coldStartTriggered > 0 {
+		log.Printf("Cold start: %d queued, %d triggered", coldStartQueued, coldStartTriggered)
+	}
+}
+
 // parsePostID 將 postID 字串轉換為 int64
 func parsePostID(postID string) int64 {
 	id, _ := strconv.ParseInt(postID, 10, 64)
@@ -598,8 +1057,9 @@ func (w *StreamWorker) processPostAndCollect(ctx context.Context, msg redis.Post
 		Content:  msg.Content,
 		Platform: toPlatform(msg.Platform),
 		Author: entity.Author{
-			ID:       msg.PlatformUserID,
-			Username: msg.OwnerUsername,
+			ID:        msg.PlatformUserID,
+			Username:  msg.OwnerUsername,
+			Followers: msg.OwnerFollowers,
 		},
 		Metrics: entity.Metrics{
 			Likes:    msg.LikeCount,
@@ -609,6 +1069,7 @@ func (w *StreamWorker) processPostAndCollect(ctx context.Context, msg redis.Post
 		},
 		Embedding: embedding,
 		CreatedAt: postTime,
+		Language:  service.DetectLanguage(msg.Content),
 	}
 
 	if err := w.postRepo.Save(ctx, post); err != nil {
@@ -673,47 +1134,22 @@ func (w *StreamWorker) processPostAndCollect(ctx context.Context, msg redis.Post
 }
 
 // processPostWithAnalysis 處理單篇貼文（含全量 LLM 分析）
-func (w *StreamWorker) processPostWithAnalysis(ctx context.Context, msg redis.PostMessage, embedding []float32, analysis *service.PostAnalysis) (*processedPost, error) {
+// replaced 為 true 表示內容編輯重跑的貼文已由 replaceAnalysis 寫入內容與分析結果
+func (w *StreamWorker) processPostWithAnalysis(ctx context.Context, msg redis.PostMessage, embedding []float32, analysis *service.PostAnalysis, replaced bool) (*processedPost, error) {
 	postID := strconv.FormatInt(msg.ID, 10)
 
-	// Check if already exists
-	existing, _ := w.postRepo.FindByID(ctx, postID)
-	if existing != nil {
-		log.Printf("post %s already exists, skip", postID)
-		return nil, nil
-	}
-
-	// Parse time
-	postTime, _ := time.Parse(time.RFC3339, msg.PostTime)
-	if postTime.IsZero() {
-		postTime = time.Now()
-	}
-
+	// 已入庫的貼文在 filterDuplicates 已處理（互動數更新 / 內容編輯重跑），這裡照常寫入
 	// Save post
-	post := &entity.Post{
-		PostID:   postID,
-		Content:  msg.Content,
-		Platform: toPlatform(msg.Platform),
-		Author: entity.Author{
-			ID:       msg.PlatformUserID,
-			Username: msg.OwnerUsername,
-		},
-		Metrics: entity.Metrics{
-			Likes:    msg.LikeCount,
-			Comments: msg.CommentCount,
-			Shares:   msg.ShareCount,
-			Views:    msg.ViewCount,
-		},
-		Embedding: embedding,
-		CreatedAt: postTime,
-	}
-
-	if err := w.postRepo.Save(ctx, post); err != nil {
-		return nil, err
+	post := messageToPost(msg, embedding)
+	post.Language = service.ResolveLanguage(ctx, msg.Content)
+	if !replaced {
+		if err := w.postRepo.Save(ctx, post); err != nil {
+			return nil, err
+		}
 	}
 
 	// 儲存全量 LLM 分析結果
-	if analysis != nil && w.analysisRepo != nil {
+	if analysis != nil && w.analysisRepo != nil && !replaced {
 		// 轉換 service.PostAnalysis -> repository.PostAnalysis
 		repoAnalysis := serviceToRepoAnalysis(analysis)
 		if err := w.analysisRepo.SaveAnalysis(ctx, postID, repoAnalysis); err != nil {
@@ -786,9 +1222,305 @@ func (w *StreamWorker) processPostWithAnalysis(ctx context.Context, msg redis.Po
 		embedding: embedding,
 		tags:      postTags,
 		analysis:  analysis,
+		language:  post.Language,
 	}, nil
 }
 
+// filterDuplicates 分析前過濾：
+//   - 已刪除（tombstone）的貼文略過
+//   - 已入庫且內容未變：只更新互動數（記錄快照），不再跑 LLM
+//   - 已入庫但內容被編輯：清除去重指紋後重跑，舊內容與 LLM 衍生資料保留到新分析成功（見 replaceAnalysis）；
+//     準備或替換失敗的放進 retry，不 ACK 留待重試
+//   - 近似重複依 DedupService 策略：drop 略過、link 只入庫不分析、process 照常分析（觀測不計入）
+//   - 未刪除貼文的作者登記至作者登錄（追蹤數快照、person Entity 連結）
+func (w *StreamWorker) filterDuplicates(ctx context.Context, msgs []redis.PostMessage) ([]redis.PostMessage, map[string]*entity.ReanalysisMark, map[string]bool) {
+	postIDs := make([]string, len(msgs))
+	for i, m := range msgs {
+		postIDs[i] = strconv.FormatInt(m.ID, 10)
+	}
+
+	tombstoned, err := w.postRepo.FindTombstoned(ctx, postIDs)
+	if err != nil {
+		log.Printf("[dedup] check tombstones error: %v", err)
+	}
+	existing := make(map[string]*entity.Post)
+	posts, err := w.postRepo.FindByIDs(ctx, postIDs)
+	if err != nil {
+		log.Printf("[dedup] check existing posts error: %v", err)
+	}
+	for _, p := range posts {
+		existing[p.PostID] = p
+	}
+
+	kept := make([]redis.PostMessage, 0, len(msgs))
+	edits := make(map[string]*entity.ReanalysisMark)
+	retry := make(map[string]bool)
+	observed := make([]*entity.Post, 0, len(msgs)) // 未刪除的貼文（含只更新互動數的），登記作者用
+	seen := make(map[string]bool, len(msgs))
+	var deleted, updated, edited, dropped, linked int
+	for i, m := range msgs {
+		postID := postIDs[i]
+		if seen[postID] { // 同一批次重複出現
+			continue
+		}
+		seen[postID] = true
+		if tombstoned[postID] {
+			deleted++
+			continue
+		}
+
+		post := messageToPost(m, nil)
+		observed = append(observed, post)
+		if ex := existing[postID]; ex != nil {
+			if ex.Content == m.Content {
+				if err := w.postRepo.UpdateMetrics(ctx, postID, post.Metrics); err != nil {
+					log.Printf("[dedup] update metrics for post %s error: %v", postID, err)
+				}
+				updated++
+				continue
+			}
+			mark, err := w.postRepo.PrepareReanalysis(ctx, postID)
+			if err != nil {
+				log.Printf("[dedup] prepare reanalysis for edited post %s error: %v", postID, err)
+				retry[postID] = true
+				continue
+			}
+			mark.Language = ex.Language
+			edits[postID] = mark
+			edited++
+		}
+
+		if w.dedupSvc == nil {
+			kept = append(kept, m)
+			continue
+		}
+		dup, err := w.dedupSvc.Check(ctx, post)
+		if err != nil {
+			log.Printf("[dedup] check post %s error: %v", postID, err)
+			kept = append(kept, m)
+			continue
+		}
+		if dup == nil {
+			kept = append(kept, m)
+			continue
+		}
+
+		log.Printf("[dedup] post %s duplicates %s (%s %.2f, %s)", postID, dup.OriginalPostID, dup.Method, dup.Similarity, dup.Policy)
+		switch dup.Policy {
+		case entity.DedupPolicyDrop:
+			if existing[postID] == nil {
+				dropped++
+				break
+			}
+			fallthrough // 已入庫的貼文被編輯成重複內容：仍需更新內容
+		case entity.DedupPolicyLink:
+			// 編輯後成為重複內容、不再分析：寫入新內容，舊內容的分析結果同一交易內清除
+			if mark := edits[postID]; mark != nil {
+				delete(edits, postID)
+				if err := w.postRepo.ReplaceAnalysis(ctx, post, mark, nil, nil, nil); err != nil {
+					log.Printf("[dedup] replace linked post %s error: %v", postID, err)
+					w.restoreReanalysis(ctx, mark)
+					retry[postID] = true
+					break
+				}
+			} else if err := w.postRepo.Save(ctx, post); err != nil {
+				log.Printf("[dedup] save linked post %s error: %v", postID, err)
+			}
+			linked++
+		default:
+			kept = append(kept, m)
+		}
+	}
+
+	if deleted+updated+edited+dropped+linked > 0 {
+		log.Printf("[dedup] %d deleted (skipped), %d metrics updated, %d edited (reprocessing), %d duplicates dropped, %d linked without analysis",
+			deleted, updated, edited, dropped, linked)
+	}
+	w.observeAuthors(ctx, observed)
+	return kept, edits, retry
+}
+
+// errReanalysisFailed 內容編輯重跑的 LLM 步驟有失敗
+var errReanalysisFailed = errors.New("analysis or entity extraction failed")
+
+// reanalysisSucceeded 內容編輯重跑的 LLM 步驟是否都成功（任一失敗則保留舊內容與分析結果，留待重試）
+func (w *StreamWorker) reanalysisSucceeded(analysis *service.PostAnalysis, summary *service.EntityExtractionSummary) bool {
+	if w.taggingSvc != nil && analysis == nil {
+		return false
+	}
+	if w.entityExtractor != nil && summary == nil {
+		return false
+	}
+	return true
+}
+
+// replaceAnalysis 內容編輯重跑成功後，在同一交易內寫入新內容、重新抽取的提及與 aspect，並以新分析取代舊的衍生資料
+func (w *StreamWorker) replaceAnalysis(ctx context.Context, msg redis.PostMessage, embedding []float32, mark *entity.ReanalysisMark, analysis *service.PostAnalysis, summary *service.EntityExtractionSummary) error {
+	post := messageToPost(msg, embedding)
+	post.Language = service.ResolveLanguage(ctx, msg.Content)
+
+	var repoAnalysis *repository.PostAnalysis
+	if analysis != nil && w.analysisRepo != nil {
+		repoAnalysis = serviceToRepoAnalysis(analysis)
+	}
+	var mentions []*entity.PostEntityMention
+	var aspects []*entity.EntityAspect
+	if summary != nil {
+		mentions, aspects = summary.Mentions, summary.Aspects
+	}
+	return w.postRepo.ReplaceAnalysis(ctx, post, mark, repoAnalysis, mentions, aspects)
+}
+
+// observeAuthors 登記作者與追蹤數（失敗不影響貼文處理）
+func (w *StreamWorker) observeAuthors(ctx context.Context, posts []*entity.Post) {
+	if w.authorRegistry == nil || len(posts) == 0 {
+		return
+	}
+	linked, err := w.authorRegistry.Observe(ctx, posts)
+	if err != nil {
+		log.Printf("[author] observe authors error: %v", err)
+	}
+	if linked > 0 {
+		log.Printf("[author] %d authors linked to person entities", linked)
+	}
+}
+
+// messageToPost 佇列訊息轉為 entity.Post（未帶時間時使用目前時間）
+func messageToPost(msg redis.PostMessage, embedding []float32) *entity.Post {
+	postTime, _ := time.Parse(time.RFC3339, msg.PostTime)
+	if postTime.IsZero() {
+		postTime = time.Now()
+	}
+
+	return &entity.Post{
+		PostID:   strconv.FormatInt(msg.ID, 10),
+		Content:  msg.Content,
+		Platform: toPlatform(msg.Platform),
+		Author: entity.Author{
+			ID:        msg.PlatformUserID,
+			Username:  msg.OwnerUsername,
+			Followers: msg.OwnerFollowers,
+		},
+		Metrics: entity.Metrics{
+			Likes:    msg.LikeCount,
+			Comments: msg.CommentCount,
+			Shares:   msg.ShareCount,
+			Views:    msg.ViewCount,
+		},
+		Embedding: embedding,
+		CreatedAt: postTime,
+		Language:  service.DetectLanguage(msg.Content),
+		Kind:      messageKind(msg),
+		ParentID:  formatThreadID(msg.ParentID),
+		RootID:    formatThreadID(msg.RootID),
+	}
+}
+
+// messageKind 依 parent / root 判斷內容類型（root 未補齊時視為頂層留言）
+func messageKind(msg redis.PostMessage) entity.PostKind {
+	switch {
+	case msg.ParentID == 0:
+		return entity.PostKindPost
+	case msg.RootID == 0 || msg.ParentID == msg.RootID:
+		return entity.PostKindComment
+	default:
+		return entity.PostKindReply
+	}
+}
+
+func formatThreadID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// resolveThreads 留言 / 回覆：補齊 msgs 的 RootID，並載入所屬貼文與上層留言作為 LLM 上下文
+// 回傳以訊息 ID 為鍵的上下文；原始貼文或找不到上層內容的留言不在其中（照一般貼文分析）
+func (w *StreamWorker) resolveThreads(ctx context.Context, msgs []redis.PostMessage) map[int64]*service.ThreadContext {
+	threads := make(map[int64]*service.ThreadContext)
+
+	// 同一批次的貼文也可作為上下文（貼文與留言一起匯入）
+	known := make(map[int64]*entity.Post, len(msgs))
+	hasComments := false
+	for _, m := range msgs {
+		known[m.ID] = messageToPost(m, nil)
+		hasComments = hasComments || m.ParentID != 0
+	}
+	if !hasComments {
+		return threads
+	}
+
+	load := func(ids []int64) {
+		var missing []string
+		for _, id := range ids {
+			if id != 0 && known[id] == nil {
+				missing = append(missing, strconv.FormatInt(id, 10))
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+		posts, err := w.postRepo.FindByIDs(ctx, missing)
+		if err != nil {
+			log.Printf("[thread] load parent posts error: %v", err)
+			return
+		}
+		for _, p := range posts {
+			known[parsePostID(p.PostID)] = p
+		}
+	}
+
+	// 1. 上層
+	var ids []int64
+	for _, m := range msgs {
+		ids = append(ids, m.ParentID, m.RootID)
+	}
+	load(ids)
+
+	// 2. 補齊 root：上層為留言時沿用它的 root，否則上層即為 root
+	ids = ids[:0]
+	for i := range msgs {
+		m := &msgs[i]
+		if m.ParentID == 0 || m.RootID != 0 {
+			continue
+		}
+		m.RootID = m.ParentID
+		if parent := known[m.ParentID]; parent != nil && parent.IsComment() {
+			switch {
+			case parent.RootID != "":
+				m.RootID = parsePostID(parent.RootID)
+			case parent.ParentID != "":
+				m.RootID = parsePostID(parent.ParentID)
+			}
+		}
+		ids = append(ids, m.RootID)
+	}
+	load(ids)
+
+	// 3. 組上下文
+	for _, m := range msgs {
+		if m.ParentID == 0 {
+			continue
+		}
+		thread := &service.ThreadContext{}
+		if root := known[m.RootID]; root != nil {
+			thread.PostContent = root.Content
+			thread.PostAuthor = root.Author.Username
+		}
+		if parent := known[m.ParentID]; parent != nil && m.ParentID != m.RootID {
+			thread.ParentContent = parent.Content
+			thread.ParentAuthor = parent.Author.Username
+		}
+		if thread.PostContent == "" && thread.ParentContent == "" {
+			log.Printf("[thread] parent of comment %d not found, analyzing without context", m.ID)
+			continue
+		}
+		threads[m.ID] = thread
+	}
+	return threads
+}
+
 func toPlatform(p string) entity.Platform {
 	switch p {
 	case "ig":
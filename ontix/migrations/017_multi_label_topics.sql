@@ -0,0 +1,38 @@
+-- ============================================
+-- Multi-label Topic Assignment + Probability Calibration
+--
+-- 1. post_topics 新增 probability（校準後機率，多標籤模式使用）
+-- 2. post_topic_labels：人工標註的 gold label（校準 / 評估用）
+-- 3. topic_calibrations：每個主題的 Platt scaling 參數與門檻
+-- ============================================
+
+BEGIN;
+
+-- 1. post_topics.probability（NULL = 單標籤 / 未校準）
+ALTER TABLE post_topics ADD COLUMN IF NOT EXISTS probability REAL;
+
+-- 2. 人工標註（同一篇貼文的標註視為完整集合，未標註的主題即為負樣本）
+CREATE TABLE IF NOT EXISTS post_topic_labels (
+    post_id BIGINT NOT NULL,
+    topic_id INTEGER NOT NULL REFERENCES topics(id),
+    is_relevant BOOLEAN NOT NULL DEFAULT TRUE,
+    source VARCHAR(32) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (post_id, topic_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_topic_labels_topic ON post_topic_labels(topic_id);
+
+-- 3. 主題機率校準參數
+CREATE TABLE IF NOT EXISTS topic_calibrations (
+    topic_id INTEGER PRIMARY KEY REFERENCES topics(id),
+    slope FLOAT NOT NULL,
+    intercept FLOAT NOT NULL,
+    threshold FLOAT NOT NULL DEFAULT 0.5,   -- 多標籤機率門檻
+    sample_count INTEGER NOT NULL DEFAULT 0,
+    positive_count INTEGER NOT NULL DEFAULT 0,
+    log_loss FLOAT,
+    fitted_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,70 @@
+-- ============================================
+-- Human-in-the-loop Review Queue
+--
+-- 1. review_items：待人工審核的貼文（模糊主題 / 低信心分類 / 別名衝突）
+-- 2. review_corrections：人工校正紀錄（before/after，回饋 few-shot 與門檻訓練）
+-- 3. 人工校正來源欄位：posts.sentiment_source、post_soft_tags.source
+-- 4. 回填既有的模糊 / 低信心分類
+-- ============================================
+
+BEGIN;
+
+-- 1. 審核佇列（同一篇貼文同一原因只排一次）
+CREATE TABLE IF NOT EXISTS review_items (
+    id BIGSERIAL PRIMARY KEY,
+    post_id VARCHAR(64) NOT NULL,
+    reason VARCHAR(32) NOT NULL,            -- ambiguous_topic / low_confidence / alias_conflict
+    detail JSONB DEFAULT '{}',              -- 觸發當下的模型輸出（供審核者參考）
+    priority REAL NOT NULL DEFAULT 0.5,     -- 越高越優先（active learning：越不確定越先審）
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending / resolved / skipped
+    reviewer VARCHAR(128),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE(post_id, reason)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_items_pending ON review_items(priority DESC, created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_review_items_reason ON review_items(reason, status);
+
+-- 2. 校正紀錄
+CREATE TABLE IF NOT EXISTS review_corrections (
+    id BIGSERIAL PRIMARY KEY,
+    review_item_id BIGINT REFERENCES review_items(id) ON DELETE SET NULL,
+    post_id VARCHAR(64) NOT NULL,
+    field VARCHAR(32) NOT NULL,             -- topic / tags / sentiment / mention
+    before JSONB,
+    after JSONB,
+    reviewer VARCHAR(128),
+    source VARCHAR(16) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_corrections_post ON review_corrections(post_id);
+CREATE INDEX IF NOT EXISTS idx_review_corrections_field ON review_corrections(field, created_at DESC);
+
+-- 3. 人工校正來源（LLM 重跑時不覆蓋 manual）
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS sentiment_source VARCHAR(16) DEFAULT 'llm';
+ALTER TABLE post_soft_tags ADD COLUMN IF NOT EXISTS source VARCHAR(16) DEFAULT 'llm';
+
+-- 4. 回填：模糊分類 / 低信心分類
+INSERT INTO review_items (post_id, reason, detail, priority)
+SELECT DISTINCT ON (pt.post_id)
+    pt.post_id::text, 'ambiguous_topic',
+    jsonb_build_object('topic_id', pt.topic_id, 'similarity', pt.similarity, 'probability', pt.probability),
+    0.8
+FROM post_topics pt
+WHERE pt.is_ambiguous = TRUE
+ORDER BY pt.post_id, pt.assigned_at DESC
+ON CONFLICT (post_id, reason) DO NOTHING;
+
+INSERT INTO review_items (post_id, reason, detail, priority)
+SELECT DISTINCT ON (pt.post_id)
+    pt.post_id::text, 'low_confidence',
+    jsonb_build_object('topic_id', pt.topic_id, 'confidence', pt.confidence),
+    0.6
+FROM post_topics pt
+WHERE pt.confidence = 'low'
+ORDER BY pt.post_id, pt.assigned_at DESC
+ON CONFLICT (post_id, reason) DO NOTHING;
+
+COMMIT;
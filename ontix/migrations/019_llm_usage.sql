@@ -0,0 +1,28 @@
+-- ============================================
+-- LLM Usage Accounting
+--
+-- 每次 LLM / Embedding API 呼叫記錄一筆：模型、token、延遲、估算成本，
+-- 依 stage（tagging / extraction / classification / narrative / summary / chat / embedding）
+-- 與 tenant 分類，供 /api/usage、ontix usage 報表與每日預算控管使用
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id BIGSERIAL PRIMARY KEY,
+    tenant VARCHAR(64) NOT NULL DEFAULT 'default',
+    stage VARCHAR(32) NOT NULL,
+    provider VARCHAR(16) NOT NULL,          -- openai / gemini
+    model VARCHAR(64) NOT NULL,
+    input_tokens INT NOT NULL DEFAULT 0,
+    output_tokens INT NOT NULL DEFAULT 0,
+    cost_usd NUMERIC(12, 6) NOT NULL DEFAULT 0,
+    latency_ms INT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usage_tenant_created ON llm_usage(tenant, created_at);
+CREATE INDEX IF NOT EXISTS idx_llm_usage_created ON llm_usage(created_at);
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- ============================================
+-- Hybrid Search（語意 + 關鍵字）
+--
+-- 1. posts.content trigram 索引：支援 ILIKE 子字串比對（中文無斷詞也可用）
+-- 2. posts.content_tsv 全文檢索欄位（simple 設定，適用英文 / 數字 / 混合字詞）
+-- 3. 篩選常用欄位索引（author）
+--
+-- /api/search 以 reciprocal rank fusion 融合向量相似度與關鍵字排名
+-- ============================================
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_posts_content_trgm ON posts USING gin (content gin_trgm_ops);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS content_tsv tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(content, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_content_tsv ON posts USING gin (content_tsv);
+CREATE INDEX IF NOT EXISTS idx_posts_author_username ON posts(author_username);
+
+COMMIT;
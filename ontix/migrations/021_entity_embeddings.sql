@@ -0,0 +1,19 @@
+-- ============================================
+-- Entity Embeddings
+--
+-- objects.embedding（012 已建立欄位）由提及片段、aspect 評價與共同提及的 Entity
+-- 組成的 profile 文字，加上提及貼文 embedding 的平均混合而成。
+-- embedding_mention_count 記錄產生當下的提及數，提及數成長超過 20% 時重新計算。
+-- 供 /api/entities/search 與 /api/entities/:id/similar 使用
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE objects ADD COLUMN IF NOT EXISTS embedding_mention_count INT NOT NULL DEFAULT 0;
+ALTER TABLE objects ADD COLUMN IF NOT EXISTS embedding_updated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_objects_embedding_hnsw ON objects
+    USING hnsw (embedding vector_cosine_ops)
+    WHERE status = 'active';
+
+COMMIT;
@@ -0,0 +1,47 @@
+-- ============================================
+-- Durable Entity Chat Sessions
+--
+-- 取代 Redis 中每個 session_id 只保留 10 輪、會過期的對話歷史：
+-- 1. chat_sessions：每位使用者的對話 session（可列出 / 續談 / 改名 / 刪除），
+--    較舊的對話摘要進 summary，並累計 token 用量
+-- 2. chat_messages：完整對話紀錄，回答附上引用來源（貼文 / 面向 / 事實）
+-- ============================================
+
+BEGIN;
+
+-- 1. 對話 session
+CREATE TABLE IF NOT EXISTS chat_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(128) NOT NULL,
+    object_id UUID NOT NULL REFERENCES objects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL DEFAULT '',
+    period VARCHAR(8) NOT NULL DEFAULT '4w',
+    summary TEXT NOT NULL DEFAULT '',          -- 已摘要的較舊對話
+    summarized_until BIGINT NOT NULL DEFAULT 0, -- 已併入 summary 的最後一則 chat_messages.id
+    message_count INT NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cost_usd NUMERIC(12, 6) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_sessions_user ON chat_sessions(user_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chat_sessions_object ON chat_sessions(object_id, user_id);
+
+-- 2. 對話訊息
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES chat_sessions(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL,                 -- user / assistant
+    content TEXT NOT NULL,
+    citations JSONB NOT NULL DEFAULT '[]',     -- [{kind, id, label}]
+    input_tokens INT NOT NULL DEFAULT 0,
+    output_tokens INT NOT NULL DEFAULT 0,
+    cost_usd NUMERIC(12, 6) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_session ON chat_messages(session_id, id);
+
+COMMIT;
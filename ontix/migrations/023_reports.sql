@@ -0,0 +1,49 @@
+-- ============================================
+-- Scheduled Brand Reports
+--
+-- 1. report_definitions：報表定義（Entity 或 class、期間、章節、cron 排程、收件人）
+-- 2. report_runs：每次產出的報表（自帶樣式的 HTML + PDF），保留歷史供 /api/reports 下載
+-- ============================================
+
+BEGIN;
+
+-- 1. 報表定義
+CREATE TABLE IF NOT EXISTS report_definitions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    entity_ids UUID[] NOT NULL DEFAULT '{}',
+    class_ids INT[] NOT NULL DEFAULT '{}',            -- 含子 class（IS-A）
+    period VARCHAR(8) NOT NULL DEFAULT '1w',          -- 1w / 4w / 12w
+    sections TEXT[] NOT NULL DEFAULT '{}',            -- kpis / sentiment_trend / top_aspects / kol_attribution / facts / ai_summary
+    formats TEXT[] NOT NULL DEFAULT '{html,pdf}',
+    schedule VARCHAR(64) NOT NULL DEFAULT '',         -- cron（分 時 日 月 週），空字串 = 只手動產出
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Taipei',
+    recipients TEXT[] NOT NULL DEFAULT '{}',          -- email，空 = 不寄送
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_definitions_due ON report_definitions(next_run_at) WHERE is_active;
+
+-- 2. 報表產出紀錄
+CREATE TABLE IF NOT EXISTS report_runs (
+    id BIGSERIAL PRIMARY KEY,
+    definition_id BIGINT NOT NULL REFERENCES report_definitions(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',    -- running / succeeded / failed
+    trigger VARCHAR(16) NOT NULL DEFAULT 'schedule',  -- schedule / manual
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    html TEXT,
+    pdf BYTEA,
+    error TEXT,
+    delivered_to TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_runs_definition ON report_runs(definition_id, created_at DESC);
+
+COMMIT;
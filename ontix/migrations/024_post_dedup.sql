@@ -0,0 +1,49 @@
+-- ============================================
+-- Content Deduplication
+--
+-- 1. post_fingerprints：原始貼文（非重複）的內容指紋（正規化內容雜湊 + MinHash 簽章）
+-- 2. post_minhash_bands：MinHash LSH 分桶索引，用於找出近似重複候選
+-- 3. post_duplicates：偵測到的重複貼文（完全相同 / 近似），以 original_post_id 分組
+--    觀測聚合（entity_observations）排除此表中的貼文
+-- ============================================
+
+BEGIN;
+
+-- 1. 內容指紋（只記錄群組的原始貼文，重複貼文一律連到原始貼文）
+CREATE TABLE IF NOT EXISTS post_fingerprints (
+    post_id VARCHAR(64) PRIMARY KEY,
+    platform VARCHAR(32) NOT NULL,
+    content_hash CHAR(64) NOT NULL,                   -- 正規化內容 SHA-256
+    signature BIGINT[] NOT NULL,                      -- MinHash 簽章（64 個 uint32）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_fingerprints_hash ON post_fingerprints(content_hash);
+
+-- 2. LSH 分桶（16 bands × 4 rows）
+CREATE TABLE IF NOT EXISTS post_minhash_bands (
+    band SMALLINT NOT NULL,
+    band_hash BIGINT NOT NULL,
+    post_id VARCHAR(64) NOT NULL REFERENCES post_fingerprints(post_id) ON DELETE CASCADE,
+    PRIMARY KEY (band, band_hash, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_minhash_bands_post ON post_minhash_bands(post_id);
+
+-- 3. 重複貼文
+CREATE TABLE IF NOT EXISTS post_duplicates (
+    post_id VARCHAR(64) PRIMARY KEY,
+    platform VARCHAR(32) NOT NULL,
+    author_username VARCHAR(128),
+    content TEXT NOT NULL DEFAULT '',                 -- 內容摘錄（drop 策略下貼文不入庫，供 API 顯示）
+    original_post_id VARCHAR(64) NOT NULL,
+    similarity REAL NOT NULL,                         -- 估計 Jaccard 相似度（exact = 1）
+    method VARCHAR(16) NOT NULL,                      -- exact / minhash
+    policy VARCHAR(16) NOT NULL,                      -- drop / link / process
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_duplicates_original ON post_duplicates(original_post_id);
+CREATE INDEX IF NOT EXISTS idx_post_duplicates_detected ON post_duplicates(detected_at DESC);
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- ============================================
+-- Post Lifecycle：互動數更新、內容編輯、刪除（平台下架合規）
+--
+-- 1. post_metrics_history：互動數快照（重新匯入時與上一筆不同才記錄）
+-- 2. post_tombstones：已刪除貼文，之後重新匯入一律略過
+-- 3. posts.edited_at / metrics_updated_at
+-- ============================================
+
+BEGIN;
+
+-- 1. 互動數快照
+CREATE TABLE IF NOT EXISTS post_metrics_history (
+    id BIGSERIAL PRIMARY KEY,
+    post_id VARCHAR(64) NOT NULL,
+    likes INTEGER NOT NULL DEFAULT 0,
+    comments INTEGER NOT NULL DEFAULT 0,
+    shares INTEGER NOT NULL DEFAULT 0,
+    views INTEGER NOT NULL DEFAULT 0,
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_metrics_history_post ON post_metrics_history(post_id, captured_at DESC);
+
+-- 2. Tombstone
+CREATE TABLE IF NOT EXISTS post_tombstones (
+    post_id VARCHAR(64) PRIMARY KEY,
+    platform VARCHAR(32) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',                  -- takedown / user_request / gdpr ...
+    requested_by VARCHAR(128) NOT NULL DEFAULT '',
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 3. 編輯 / 互動數更新時間
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS metrics_updated_at TIMESTAMPTZ;
+
+-- 回填：現有貼文的互動數作為第一筆快照
+INSERT INTO post_metrics_history (post_id, likes, comments, shares, views, captured_at)
+SELECT p.post_id, COALESCE(p.likes, 0), COALESCE(p.comments, 0), COALESCE(p.shares, 0), COALESCE(p.views, 0), p.created_at
+FROM posts p
+WHERE NOT EXISTS (SELECT 1 FROM post_metrics_history h WHERE h.post_id = p.post_id);
+
+COMMIT;
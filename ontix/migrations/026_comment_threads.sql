@@ -0,0 +1,35 @@
+-- ============================================
+-- Comment / Reply Threads
+--
+-- 1. posts.kind / parent_post_id / root_post_id：留言與回覆以同一張 posts 表儲存，
+--    parent 為直接上層（貼文或留言），root 為串的原始貼文
+-- 2. entity_observations.comment_*：留言 / 回覆的提及另外聚合，
+--    既有 mention_count 等欄位只計原始貼文
+-- ============================================
+
+BEGIN;
+
+-- 1. 留言串
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'post';   -- post / comment / reply
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS parent_post_id VARCHAR(64);
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS root_post_id VARCHAR(64);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'posts_kind_check') THEN
+        ALTER TABLE posts ADD CONSTRAINT posts_kind_check CHECK (kind IN ('post', 'comment', 'reply'));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_posts_parent ON posts(parent_post_id) WHERE parent_post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_root ON posts(root_post_id) WHERE root_post_id IS NOT NULL;
+
+-- 2. 留言觀測
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_mention_count INT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_positive_count INT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_negative_count INT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_neutral_count INT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_mixed_count INT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS comment_avg_sentiment REAL;
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- ============================================
+-- Long-Content Chunks
+--
+-- 長文依句子邊界切段，各段 embedding 合併（mean / max）為 post_embeddings 的貼文向量；
+-- 段落向量另存於此供搜尋（長文後段的內容也能被找到）。
+-- start_offset / end_offset 為 posts.content 的字元（rune）位置，end 不含。
+-- 只有切成多段的貼文才會寫入；短文的段落向量即貼文向量。
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS post_chunks (
+    post_id VARCHAR(64) NOT NULL,
+    chunk_index INT NOT NULL,
+    start_offset INT NOT NULL,
+    end_offset INT NOT NULL,
+    content TEXT NOT NULL,
+    embedding vector(1536),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, chunk_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_chunks_hnsw ON post_chunks
+    USING hnsw (embedding vector_cosine_ops)
+    WITH (m = 16, ef_construction = 64);
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- ============================================
+-- Mention / Aspect Span Offsets
+--
+-- 提及與面向片段在 posts.content 中的位置，前端可直接標示原文而不必字串搜尋。
+-- mention_start / mention_end 為字元（Unicode code point）位置，end 不含；
+-- LLM 回傳的片段常非逐字，抽取時以模糊比對對齊，對不上時為 NULL。
+-- 既有資料以 `ontix entity align` 回填。
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE post_entity_mentions
+    ADD COLUMN IF NOT EXISTS mention_start INT,
+    ADD COLUMN IF NOT EXISTS mention_end INT;
+
+ALTER TABLE entity_aspects
+    ADD COLUMN IF NOT EXISTS mention_start INT,
+    ADD COLUMN IF NOT EXISTS mention_end INT;
+
+-- 回填時找出尚未對齊的資料
+CREATE INDEX IF NOT EXISTS idx_post_entity_mentions_unaligned ON post_entity_mentions(id)
+    WHERE mention_start IS NULL AND mention_text IS NOT NULL AND mention_text <> '';
+CREATE INDEX IF NOT EXISTS idx_entity_aspects_unaligned ON entity_aspects(id)
+    WHERE mention_start IS NULL AND mention_text IS NOT NULL AND mention_text <> '';
+
+COMMIT;
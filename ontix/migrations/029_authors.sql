@@ -0,0 +1,104 @@
+-- ============================================
+-- Author Registry：作者 / KOL 檔案
+--
+-- 原本作者只是 posts 上的 author_id / author_username / author_followers 三個欄位，
+-- KOL 歸因要用 canonical_name = author_username 比對 person Entity。
+--
+-- 1. authors：每個平台帳號一筆（platform + platform_user_id），連結 person Entity
+-- 2. author_follower_history：追蹤數每日快照
+-- 3. author_stats：發文頻率、平均情感、互動率（materialized view，與 entity_stats 一起刷新）
+--
+-- 作者鍵：posts.author_id，沒有時用 author_username（部分匯入格式只有帳號名稱）
+-- ============================================
+
+BEGIN;
+
+-- 1. 作者登錄
+CREATE TABLE IF NOT EXISTS authors (
+    id BIGSERIAL PRIMARY KEY,
+    platform VARCHAR(32) NOT NULL,
+    platform_user_id VARCHAR(128) NOT NULL,
+    username VARCHAR(128) NOT NULL DEFAULT '',
+    followers INTEGER NOT NULL DEFAULT 0,                       -- 最近一次觀察到的追蹤數
+    object_id UUID REFERENCES objects(id) ON DELETE SET NULL,   -- 連結的 person Entity
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (platform, platform_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_object ON authors(object_id) WHERE object_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_authors_followers ON authors(followers DESC);
+CREATE INDEX IF NOT EXISTS idx_authors_username_trgm ON authors USING gin (username gin_trgm_ops);
+
+-- posts → authors 的 join 鍵
+CREATE INDEX IF NOT EXISTS idx_posts_author_key
+    ON posts(platform, (COALESCE(NULLIF(author_id, ''), author_username)));
+
+-- 2. 追蹤數每日快照（同一天多次觀察取最後一次）
+CREATE TABLE IF NOT EXISTS author_follower_history (
+    author_id BIGINT NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    captured_on DATE NOT NULL,
+    followers INTEGER NOT NULL,
+    PRIMARY KEY (author_id, captured_on)
+);
+
+-- 既有貼文回填作者（追蹤數取最近一篇貼文的值）
+INSERT INTO authors (platform, platform_user_id, username, followers, first_seen_at, last_seen_at)
+SELECT DISTINCT ON (p.platform, COALESCE(NULLIF(p.author_id, ''), p.author_username))
+    p.platform,
+    COALESCE(NULLIF(p.author_id, ''), p.author_username),
+    COALESCE(p.author_username, ''),
+    COALESCE(p.author_followers, 0),
+    MIN(p.created_at) OVER w,
+    MAX(p.created_at) OVER w
+FROM posts p
+WHERE COALESCE(NULLIF(p.author_id, ''), p.author_username, '') <> ''
+WINDOW w AS (PARTITION BY p.platform, COALESCE(NULLIF(p.author_id, ''), p.author_username))
+ORDER BY p.platform, COALESCE(NULLIF(p.author_id, ''), p.author_username), p.created_at DESC
+ON CONFLICT (platform, platform_user_id) DO NOTHING;
+
+-- 既有的 person Entity：帳號名稱與正規名稱或別名相同時連結
+UPDATE authors a SET object_id = m.object_id
+FROM (
+    SELECT DISTINCT ON (a2.id) a2.id AS author_id, o.id AS object_id
+    FROM authors a2
+    JOIN (
+        -- 正規名稱優先於別名
+        SELECT id AS object_id, canonical_name AS name, 0 AS priority, 1.0::real AS confidence
+        FROM objects
+        UNION ALL
+        SELECT object_id, alias, 1, COALESCE(confidence, 0)
+        FROM object_aliases
+    ) n ON n.name = a2.username
+    JOIN objects o ON o.id = n.object_id AND o.status = 'active'
+    JOIN object_types ot ON ot.id = o.type_id AND ot.name = 'person'
+    WHERE a2.username <> ''
+    ORDER BY a2.id, n.priority, n.confidence DESC
+) m
+WHERE a.id = m.author_id AND a.object_id IS NULL;
+
+-- 3. 作者統計（互動率 = 互動數 / 發文當下追蹤數，沒有時用目前追蹤數）
+CREATE MATERIALIZED VIEW IF NOT EXISTS author_stats AS
+SELECT
+    a.id AS author_id,
+    COUNT(p.id) AS post_count,
+    COUNT(p.id) FILTER (WHERE p.created_at >= NOW() - INTERVAL '28 days') AS posts_28d,
+    ROUND(COUNT(p.id) FILTER (WHERE p.created_at >= NOW() - INTERVAL '28 days')::NUMERIC / 4, 2) AS posts_per_week,
+    ROUND(AVG(p.sentiment_score)::NUMERIC, 3) AS avg_sentiment,
+    ROUND(AVG(COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0))::NUMERIC, 1) AS avg_engagement,
+    ROUND(AVG(
+        (COALESCE(p.likes, 0) + COALESCE(p.comments, 0) + COALESCE(p.shares, 0))::NUMERIC
+        / NULLIF(COALESCE(NULLIF(p.author_followers, 0), a.followers), 0)
+    ), 5) AS engagement_rate,
+    MAX(p.created_at) AS last_post_at
+FROM authors a
+LEFT JOIN posts p
+    ON p.platform = a.platform
+   AND COALESCE(NULLIF(p.author_id, ''), p.author_username) = a.platform_user_id
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_author_stats_author ON author_stats(author_id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- ============================================
+-- 加權觀測：互動加權情感、估計觸及、同類聲量佔比
+--
+-- 原本 avg_sentiment 每則提及權重相同，5 個讚的貼文和 50 萬次觀看的爆文影響一樣大。
+-- 以下欄位由 MaterializeObservations 與未加權欄位一起寫入（只計原始貼文，不含留言與重複貼文）：
+--
+--   weighted_sentiment  每則提及以 1 + ln(1 + 讚 + 留言 + 分享) 加權的平均情感
+--   total_engagement    提及貼文的互動數總和
+--   estimated_reach     提及貼文的觸及估計總和（觀看數，沒有時用發文當下追蹤數，再沒有用作者目前追蹤數）
+--   share_of_voice      同類 Entity（相同 object type）本期提及數的佔比（0 ~ 1）
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS weighted_sentiment REAL;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS total_engagement BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS estimated_reach BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE entity_observations ADD COLUMN IF NOT EXISTS share_of_voice REAL;
+
+COMMIT;
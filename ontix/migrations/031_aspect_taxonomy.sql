@@ -0,0 +1,203 @@
+-- ============================================
+-- Aspect Taxonomy：面向正規化與階層
+--
+-- LLM 回傳的面向是自由文字，「服務」「服務態度」「店員態度」「service」會被當成不同面向。
+--
+-- 1. aspect_nodes：每個 ontology class 的正規面向樹（餐點 > 薯條），子類繼承父類的節點
+--    path 為根到此節點的名稱（roll-up 用），synonyms 為精確對應的別名
+-- 2. aspect_mappings：原始面向（小寫、去空白）→ 節點，依 Entity 的 class 分開記錄
+--    精確命中名稱 / 別名或 embedding 相似度達門檻時自動對應（auto），
+--    否則記為 pending 進入審核佇列（附最相近的建議節點）
+-- 3. entity_aspects.aspect_node_id：對應後的正規節點
+-- 4. entity_aspect_stats 改以正規面向彙總（未對應的面向維持原字串）
+--
+-- 未設定 class 的 Entity 視為根類別 entity（id = 1），只使用通用節點。
+-- ============================================
+
+BEGIN;
+
+-- class → 自己與所有祖先（depth 0 = 自己）
+CREATE OR REPLACE VIEW ontology_class_ancestors AS
+WITH RECURSIVE chain AS (
+    SELECT id AS class_id, id AS ancestor_id, 0 AS depth FROM ontology_classes
+    UNION ALL
+    SELECT ch.class_id, c.parent_id, ch.depth + 1
+    FROM chain ch
+    JOIN ontology_classes c ON c.id = ch.ancestor_id
+    WHERE c.parent_id IS NOT NULL
+)
+SELECT class_id, ancestor_id, depth FROM chain;
+
+-- 1. 正規面向樹
+CREATE TABLE IF NOT EXISTS aspect_nodes (
+    id SERIAL PRIMARY KEY,
+    class_id INT NOT NULL REFERENCES ontology_classes(id),
+    parent_id INT REFERENCES aspect_nodes(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    path TEXT[] NOT NULL,                         -- 根 → 此節點的名稱
+    synonyms TEXT[] NOT NULL DEFAULT '{}',        -- 精確對應的別名
+    embedding vector(1536),                       -- 名稱 + 別名的向量（自動對應用）
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (class_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_aspect_nodes_parent ON aspect_nodes(parent_id);
+
+-- 2. 原始面向對應
+CREATE TABLE IF NOT EXISTS aspect_mappings (
+    id BIGSERIAL PRIMARY KEY,
+    class_id INT NOT NULL REFERENCES ontology_classes(id),
+    raw_aspect TEXT NOT NULL,                     -- LOWER(BTRIM(aspect))
+    node_id INT REFERENCES aspect_nodes(id) ON DELETE SET NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('auto', 'manual', 'pending', 'ignored')),
+    similarity REAL,                              -- 與建議節點的相似度（精確命中為 1）
+    suggested_node_id INT REFERENCES aspect_nodes(id) ON DELETE SET NULL,
+    mention_count INT NOT NULL DEFAULT 0,         -- 建立時的面向數（審核排序用）
+    reviewer TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (class_id, raw_aspect)
+);
+
+CREATE INDEX IF NOT EXISTS idx_aspect_mappings_pending
+    ON aspect_mappings(mention_count DESC) WHERE status = 'pending';
+
+-- 3. entity_aspects → 正規節點
+ALTER TABLE entity_aspects ADD COLUMN IF NOT EXISTS aspect_node_id INT REFERENCES aspect_nodes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_entity_aspects_node ON entity_aspects(aspect_node_id);
+CREATE INDEX IF NOT EXISTS idx_entity_aspects_unmapped ON entity_aspects(id) WHERE aspect_node_id IS NULL;
+
+-- 預設面向樹
+-- 通用（所有 class 繼承）
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms) VALUES
+    (1, NULL, '價格', ARRAY['價格'], ARRAY['價錢', '定價', '售價', 'price']),
+    (1, NULL, '服務', ARRAY['服務'], ARRAY['服務態度', '店員態度', '服務品質', 'service']),
+    (1, NULL, '品質', ARRAY['品質'], ARRAY['品質穩定', 'quality']),
+    (1, NULL, '通路', ARRAY['通路'], ARRAY['通路方便', '購買管道', '購買方便']),
+    (1, NULL, '品牌', ARRAY['品牌'], ARRAY['brand'])
+ON CONFLICT (class_id, name) DO NOTHING;
+
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms)
+SELECT 1, p.id, v.name, p.path || v.name, v.synonyms
+FROM (VALUES
+    ('價格', '性價比',   ARRAY['CP值', '划算']),
+    ('價格', '促銷',     ARRAY['優惠', '折扣', '特價']),
+    ('服務', '客服',     ARRAY['售後服務', '客服回覆']),
+    ('服務', '等待時間', ARRAY['排隊', '出餐速度', '等候時間']),
+    ('品牌', '品牌形象', ARRAY['形象']),
+    ('品牌', '品牌信任', ARRAY['信任', '信任度', '口碑'])
+) AS v(parent, name, synonyms)
+JOIN aspect_nodes p ON p.class_id = 1 AND p.name = v.parent
+ON CONFLICT (class_id, name) DO NOTHING;
+
+-- 品牌
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms) VALUES
+    (11, NULL, '產品線', ARRAY['產品線'], ARRAY['品項', '產品組合'])
+ON CONFLICT (class_id, name) DO NOTHING;
+
+-- 實體產品
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms) VALUES
+    (21, NULL, '效果',     ARRAY['效果'],     ARRAY['功效']),
+    (21, NULL, '質地',     ARRAY['質地'],     ARRAY['觸感', '膚感']),
+    (21, NULL, '成分',     ARRAY['成分'],     ARRAY['配方']),
+    (21, NULL, '包裝設計', ARRAY['包裝設計'], ARRAY['包裝', '外觀'])
+ON CONFLICT (class_id, name) DO NOTHING;
+
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms)
+SELECT 21, p.id, v.name, p.path || v.name, v.synonyms
+FROM (VALUES
+    ('效果', '保濕效果',   ARRAY['保濕']),
+    ('效果', '控油',       ARRAY['控油效果']),
+    ('效果', '吸收速度',   ARRAY['吸收']),
+    ('效果', '持久度',     ARRAY['持久']),
+    ('成分', '成分安全',   ARRAY['安全性']),
+    ('成分', '致敏性',     ARRAY['過敏', '刺激性']),
+    ('成分', '敏感肌友善', ARRAY['敏感肌'])
+) AS v(parent, name, synonyms)
+JOIN aspect_nodes p ON p.class_id = 21 AND p.name = v.parent
+ON CONFLICT (class_id, name) DO NOTHING;
+
+-- 場所（餐廳、沙龍…）
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms) VALUES
+    (41, NULL, '餐點', ARRAY['餐點'], ARRAY['食物', '菜色', 'food']),
+    (41, NULL, '環境', ARRAY['環境'], ARRAY['裝潢', '氛圍', '座位'])
+ON CONFLICT (class_id, name) DO NOTHING;
+
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms)
+SELECT 41, p.id, v.name, p.path || v.name, v.synonyms
+FROM (VALUES
+    ('餐點', '薯條', ARRAY['fries']),
+    ('餐點', '飲料', ARRAY['飲品']),
+    ('餐點', '口味', ARRAY['味道']),
+    ('環境', '衛生', ARRAY['清潔', '乾淨'])
+) AS v(parent, name, synonyms)
+JOIN aspect_nodes p ON p.class_id = 41 AND p.name = v.parent
+ON CONFLICT (class_id, name) DO NOTHING;
+
+-- 創作者
+INSERT INTO aspect_nodes (class_id, parent_id, name, path, synonyms) VALUES
+    (31, NULL, '內容品質', ARRAY['內容品質'], ARRAY['內容']),
+    (31, NULL, '真實性',   ARRAY['真實性'],   ARRAY['真實']),
+    (31, NULL, '業配',     ARRAY['業配'],     ARRAY['置入', '合作']),
+    (31, NULL, '互動',     ARRAY['互動'],     ARRAY['粉絲互動']),
+    (31, NULL, '表達力',   ARRAY['表達力'],   ARRAY['口條']),
+    (31, NULL, '專業度',   ARRAY['專業度'],   ARRAY['專業'])
+ON CONFLICT (class_id, name) DO NOTHING;
+
+-- 既有面向：精確命中名稱 / 別名的直接對應（最近的 class 優先）
+INSERT INTO aspect_mappings (class_id, raw_aspect, node_id, status, similarity, mention_count)
+SELECT DISTINCT ON (r.class_id, r.raw_aspect) r.class_id, r.raw_aspect, n.id, 'auto', 1, r.cnt
+FROM (
+    SELECT COALESCE(o.class_id, 1) AS class_id, LOWER(BTRIM(ea.aspect)) AS raw_aspect, COUNT(*) AS cnt
+    FROM entity_aspects ea
+    JOIN objects o ON o.id = ea.object_id
+    GROUP BY 1, 2
+) r
+JOIN ontology_class_ancestors ca ON ca.class_id = r.class_id
+JOIN aspect_nodes n ON n.class_id = ca.ancestor_id
+WHERE r.raw_aspect = LOWER(n.name) OR r.raw_aspect = ANY(SELECT LOWER(s) FROM unnest(n.synonyms) s)
+ORDER BY r.class_id, r.raw_aspect, ca.depth, (r.raw_aspect <> LOWER(n.name))
+ON CONFLICT (class_id, raw_aspect) DO NOTHING;
+
+UPDATE entity_aspects ea SET aspect_node_id = m.node_id
+FROM objects o, aspect_mappings m
+WHERE o.id = ea.object_id
+  AND m.class_id = COALESCE(o.class_id, 1)
+  AND m.raw_aspect = LOWER(BTRIM(ea.aspect))
+  AND m.node_id IS NOT NULL
+  AND ea.aspect_node_id IS NULL;
+
+-- 4. entity_aspect_stats 以正規面向彙總（aspect 為節點名稱，未對應時為原字串）
+DROP MATERIALIZED VIEW IF EXISTS entity_aspect_stats;
+
+CREATE MATERIALIZED VIEW entity_aspect_stats AS
+SELECT s.*, an.path AS aspect_path
+FROM (
+    SELECT
+        ea.object_id,
+        o.canonical_name,
+        COALESCE(n.name, ea.aspect) AS aspect,
+        MAX(n.id) AS aspect_node_id,
+        COUNT(*) AS total,
+        COUNT(*) FILTER (WHERE ea.sentiment = 'positive') AS positive_count,
+        COUNT(*) FILTER (WHERE ea.sentiment = 'negative') AS negative_count,
+        COUNT(*) FILTER (WHERE ea.sentiment = 'neutral') AS neutral_count,
+        ROUND(AVG(ea.sentiment_score)::NUMERIC, 3) AS avg_sentiment_score,
+        ROUND(
+            COUNT(*) FILTER (WHERE ea.sentiment = 'positive')::NUMERIC
+            / NULLIF(COUNT(*), 0) * 100, 1
+        ) AS positive_ratio
+    FROM entity_aspects ea
+    JOIN objects o ON ea.object_id = o.id
+    LEFT JOIN aspect_nodes n ON n.id = ea.aspect_node_id
+    GROUP BY ea.object_id, o.canonical_name, COALESCE(n.name, ea.aspect)
+) s
+LEFT JOIN aspect_nodes an ON an.id = s.aspect_node_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entity_aspect_stats_pk
+    ON entity_aspect_stats(object_id, aspect);
+CREATE INDEX IF NOT EXISTS idx_entity_aspect_stats_object
+    ON entity_aspect_stats(object_id);
+
+COMMIT;
@@ -0,0 +1,77 @@
+-- ============================================
+-- Sentiment Calibration：跨模型 / prompt 版本的情緒分數校準
+--
+-- sentiment_score 直接取自 LLM 回傳的 0~1 分數，不同模型（gpt-4o-mini → Gemini）或
+-- prompt 版本的分布不同，換模型會整體平移 avg_sentiment 並觸發誤報。
+--
+-- 1. post_entity_mentions / entity_aspects 記錄原始分數與產生它的模型、prompt 版本
+--    sentiment_score 改為校準後分數（未校準時與原始分數相同），calibration_id 記錄使用的校準
+-- 2. sentiment_calibrations：以人工校正的提及為標註資料，對每個 model / prompt_version
+--    擬合的單調（isotonic）對應表，以分段線性內插套用；language 為空字串代表不分語言
+--    每個 key 只有一筆 is_active，重新擬合時舊的保留作為紀錄
+--
+-- 歷史資料回填：raw_sentiment_score = sentiment_score；
+-- 在此之前只有 gpt-4o-mini + extract/v1 一條抽取路徑，LLM 提及與所有面向以此標記。
+-- 已被人工校正的提及，原始分數取自 review_corrections 的 before（校正前為 llm 的紀錄）。
+-- 重新計分：ontix sentiment calibrate → ontix sentiment rescore --rematerialize
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS sentiment_calibrations (
+    id BIGSERIAL PRIMARY KEY,
+    model VARCHAR(64) NOT NULL,
+    prompt_version VARCHAR(32) NOT NULL,
+    language VARCHAR(16) NOT NULL DEFAULT '',      -- '' = 不分語言
+    raw_knots DOUBLE PRECISION[] NOT NULL,         -- 遞增的原始分數
+    calibrated_knots DOUBLE PRECISION[] NOT NULL,  -- 對應的校準分數（非遞減）
+    sample_count INTEGER NOT NULL DEFAULT 0,
+    mae_before FLOAT,                              -- 校準前與標註的平均絕對誤差
+    mae_after FLOAT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    fitted_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sentiment_calibrations_active
+    ON sentiment_calibrations(model, prompt_version, language) WHERE is_active;
+
+-- 1. 原始分數與來源模型
+ALTER TABLE post_entity_mentions
+    ADD COLUMN IF NOT EXISTS raw_sentiment_score REAL,
+    ADD COLUMN IF NOT EXISTS model VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS prompt_version VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS calibration_id BIGINT REFERENCES sentiment_calibrations(id) ON DELETE SET NULL;
+
+ALTER TABLE entity_aspects
+    ADD COLUMN IF NOT EXISTS raw_sentiment_score REAL,
+    ADD COLUMN IF NOT EXISTS model VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS prompt_version VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS calibration_id BIGINT REFERENCES sentiment_calibrations(id) ON DELETE SET NULL;
+
+-- 2. 回填歷史資料
+UPDATE post_entity_mentions
+SET raw_sentiment_score = sentiment_score, model = 'gpt-4o-mini', prompt_version = 'extract/v1'
+WHERE source = 'llm' AND raw_sentiment_score IS NULL;
+
+UPDATE entity_aspects
+SET raw_sentiment_score = sentiment_score, model = 'gpt-4o-mini', prompt_version = 'extract/v1'
+WHERE raw_sentiment_score IS NULL;
+
+-- 人工校正過的提及：取最早一筆「校正前為 llm」的分數作為原始分數（擬合用的標註樣本）
+UPDATE post_entity_mentions m
+SET raw_sentiment_score = c.raw_score, model = 'gpt-4o-mini', prompt_version = 'extract/v1'
+FROM (
+    SELECT DISTINCT ON (post_id, before->>'object_id')
+        post_id, (before->>'object_id')::uuid AS object_id, (before->>'sentiment_score')::real AS raw_score
+    FROM review_corrections
+    WHERE field = 'mention' AND before->>'source' = 'llm' AND before ? 'sentiment_score'
+    ORDER BY post_id, before->>'object_id', created_at
+) c
+WHERE m.post_id = c.post_id AND m.object_id = c.object_id
+  AND m.source = 'manual' AND m.raw_sentiment_score IS NULL;
+
+-- 重新計分時依 model / prompt_version 掃描尚未套用目前校準的資料
+CREATE INDEX IF NOT EXISTS idx_post_entity_mentions_model ON post_entity_mentions(model, prompt_version) WHERE model IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_entity_aspects_model ON entity_aspects(model, prompt_version) WHERE model IS NOT NULL;
+
+COMMIT;
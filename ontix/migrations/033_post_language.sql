@@ -0,0 +1,19 @@
+-- ============================================
+-- 貼文語言：入庫時偵測，供 prompt 模板、關鍵字規則、搜尋與儀表板篩選使用
+--
+-- 客戶除了中文也監測英文、日文、馬來文貼文；語言在 worker 入庫時以文字系統與功能詞判斷
+--   zh / en / ja / ms   偵測到的語言
+--   ''                  無法判斷（內容過短、只有表情符號或品牌名）
+--   NULL                尚未偵測（本 migration 之前的歷史資料）
+--
+-- 偵測在應用程式端執行，歷史資料以 `ontix language detect` 回填
+-- （回填後可再執行 `ontix sentiment calibrate` 擬合各語言的情緒校準）
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+CREATE INDEX IF NOT EXISTS idx_posts_language ON posts(language, created_at);
+
+COMMIT;